@@ -144,6 +144,24 @@ func (api *PrivateMinerAPI) SetFourtwentycoinbase(fourtwentycoinbase common.Addr
 	return true
 }
 
+// SetCoinbaseList sets the coinbases the miner rotates mined blocks among, each
+// one receiving interval consecutive blocks. If interval is nil, the coinbases
+// are rotated round-robin on every block. An empty list disables the rotation,
+// mining to the fourtwentycoinbase again.
+func (api *PrivateMinerAPI) SetCoinbaseList(coinbases []common.Address, interval *uint64) error {
+	for _, coinbase := range coinbases {
+		if coinbase == (common.Address{}) {
+			return errors.New("zero address in coinbase list")
+		}
+	}
+	var blocks uint64 = 1
+	if interval != nil {
+		blocks = *interval
+	}
+	api.e.SetCoinbaseList(coinbases, blocks)
+	return nil
+}
+
 // SetRecommitInterval updates the interval for miner sealing work recommitting.
 func (api *PrivateMinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
@@ -154,6 +172,79 @@ func (api *PrivateMinerAPI) GetHashrate() uint64 {
 	return api.e.miner.HashRate()
 }
 
+// PrivateTxPoolAPI provides private RPC methods to tune the transaction pool
+// of a running node.
+type PrivateTxPoolAPI struct {
+	e *Fourtwentycoin
+}
+
+// NewPrivateTxPoolAPI creates a new RPC service which controls the transaction
+// pool of this node.
+func NewPrivateTxPoolAPI(e *Fourtwentycoin) *PrivateTxPoolAPI {
+	return &PrivateTxPoolAPI{e: e}
+}
+
+// TxPoolLimits are the runtime adjustable settings of the transaction pool. The
+// lifetime is a duration string, e.g. "3h". Fields omitted in a call to
+// SetConfig keep their current value.
+type TxPoolLimits struct {
+	PriceBump    *uint64 `json:"priceBump,omitempty"`
+	AccountSlots *uint64 `json:"accountSlots,omitempty"`
+	GlobalSlots  *uint64 `json:"globalSlots,omitempty"`
+	AccountQueue *uint64 `json:"accountQueue,omitempty"`
+	GlobalQueue  *uint64 `json:"globalQueue,omitempty"`
+	Lifetime     *string `json:"lifetime,omitempty"`
+}
+
+// newTxPoolLimits returns the runtime adjustable settings of a pool configuration.
+func newTxPoolLimits(config core.TxPoolConfig) TxPoolLimits {
+	lifetime := config.Lifetime.String()
+	return TxPoolLimits{
+		PriceBump:    &config.PriceBump,
+		AccountSlots: &config.AccountSlots,
+		GlobalSlots:  &config.GlobalSlots,
+		AccountQueue: &config.AccountQueue,
+		GlobalQueue:  &config.GlobalQueue,
+		Lifetime:     &lifetime,
+	}
+}
+
+// Config returns the current runtime adjustable settings of the transaction pool.
+func (api *PrivateTxPoolAPI) Config() TxPoolLimits {
+	return newTxPoolLimits(api.e.txPool.Config())
+}
+
+// SetConfig updates the replacement price bump, the slot and queue limits and the
+// queue lifetime of the transaction pool without a restart. Transactions above
+// lowered limits are evicted. The applied settings are returned, with invalid
+// values replaced by the defaults.
+func (api *PrivateTxPoolAPI) SetConfig(limits TxPoolLimits) (TxPoolLimits, error) {
+	config := api.e.txPool.Config()
+	if limits.PriceBump != nil {
+		config.PriceBump = *limits.PriceBump
+	}
+	if limits.AccountSlots != nil {
+		config.AccountSlots = *limits.AccountSlots
+	}
+	if limits.GlobalSlots != nil {
+		config.GlobalSlots = *limits.GlobalSlots
+	}
+	if limits.AccountQueue != nil {
+		config.AccountQueue = *limits.AccountQueue
+	}
+	if limits.GlobalQueue != nil {
+		config.GlobalQueue = *limits.GlobalQueue
+	}
+	if limits.Lifetime != nil {
+		lifetime, err := time.ParseDuration(*limits.Lifetime)
+		if err != nil {
+			return TxPoolLimits{}, fmt.Errorf("invalid lifetime: %v", err)
+		}
+		config.Lifetime = lifetime
+	}
+	return newTxPoolLimits(api.e.txPool.SetLimits(config)), nil
+}
+
 // PrivateAdminAPI is the collection of 420coin full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -205,6 +296,93 @@ func (api *PrivateAdminAPI) ExportChain(file string, first *uint64, last *uint64
 	return true, nil
 }
 
+// PendingReorg returns the last chain reorganisation refused for exceeding the
+// configured maximum reorg depth, or nil if there is none.
+func (api *PrivateAdminAPI) PendingReorg() *core.PendingReorg {
+	return api.fourtwenty.BlockChain().PendingReorg()
+}
+
+// ApproveReorg switches the canonical chain to the given block, which must be
+// heavier than the current head, bypassing the maximum reorg depth.
+func (api *PrivateAdminAPI) ApproveReorg(hash common.Hash) (bool, error) {
+	if err := api.fourtwenty.BlockChain().ApproveReorg(hash); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ScheduleTransaction holds a signed transaction back until the chain reaches the
+// given block number and/or timestamp, then injects it into the local pool. The
+// transaction is injected right away if its target was already reached. Held back
+// transactions are lost on restart.
+func (api *PrivateAdminAPI) ScheduleTransaction(input hexutil.Bytes, block *hexutil.Uint64, timestamp *hexutil.Uint64) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(input, tx); err != nil {
+		return common.Hash{}, err
+	}
+	var atBlock, atTime uint64
+	if block != nil {
+		atBlock = uint64(*block)
+	}
+	if timestamp != nil {
+		atTime = uint64(*timestamp)
+	}
+	if err := api.fourtwenty.txSched.schedule(tx, atBlock, atTime); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// ScheduledTransactions returns the transactions held back until their target,
+// ordered by target block number and timestamp.
+func (api *PrivateAdminAPI) ScheduledTransactions() []*scheduledTx {
+	return api.fourtwenty.txSched.scheduled()
+}
+
+// CancelScheduledTransaction drops a transaction held back until its target,
+// returning whether it was scheduled.
+func (api *PrivateAdminAPI) CancelScheduledTransaction(hash common.Hash) bool {
+	return api.fourtwenty.txSched.cancel(hash)
+}
+
+// ChainTips returns the canonical head and all known competing chain tips,
+// along with their total difficulty and common ancestor with the canonical
+// chain, ordered by descending total difficulty.
+func (api *PrivateAdminAPI) ChainTips() []*chainTip {
+	return api.fourtwenty.chainTips.chainTips()
+}
+
+// ChainTipAlerts creates a subscription that fires whenever a competing chain
+// tip appears within the configured alert depth of the canonical head with a
+// higher total difficulty than the canonical block at the same height.
+func (api *PrivateAdminAPI) ChainTipAlerts(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		alerts := make(chan chainTip, 16)
+		sub := api.fourtwenty.chainTips.subscribeAlerts(alerts)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case tip := <-alerts:
+				notifier.Notify(rpcSub.ID, tip)
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 func hasAllBlocks(chain *core.BlockChain, bs []*types.Block) bool {
 	for _, b := range bs {
 		if !chain.HasBlock(b.Hash(), b.NumberU64()) {
@@ -453,6 +631,55 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// ExecutionWitness returns the RLP encoded witness of the given block: the
+// ancestor headers, trie nodes and contract codes needed to re-execute it
+// without the state database.
+func (api *PrivateDebugAPI) ExecutionWitness(blockHash common.Hash) (hexutil.Bytes, error) {
+	block := api.fourtwenty.blockchain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	witness, err := core.RecordWitness(api.fourtwenty.blockchain, block)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(witness)
+}
+
+// VerifyExecutionWitness re-executes the given block statelessly from the RLP
+// encoded witness, verifying the resulting state root and receipts against the
+// block header.
+func (api *PrivateDebugAPI) VerifyExecutionWitness(blockHash common.Hash, blob hexutil.Bytes) (bool, error) {
+	block := api.fourtwenty.blockchain.GetBlockByHash(blockHash)
+	if block == nil {
+		return false, fmt.Errorf("block %#x not found", blockHash)
+	}
+	witness := new(core.Witness)
+	if err := rlp.DecodeBytes(blob, witness); err != nil {
+		return false, fmt.Errorf("invalid witness: %v", err)
+	}
+	if err := core.ExecuteWitness(api.fourtwenty.blockchain.Config(), api.fourtwenty.blockchain.Genesis().Hash(), api.fourtwenty.engine, block, witness); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BlockArrival returns the times the given block was first announced and first
+// received in full from the network, along with the peers it came from. Nil is
+// returned if the block was never propagated to the local node, or was already
+// evicted from the recent arrivals.
+func (api *PrivateDebugAPI) BlockArrival(blockHash common.Hash) *blockArrival {
+	return api.fourtwenty.handler.arrivals.blockArrival(blockHash)
+}
+
+// TransactionArrival returns the times the given transaction was first announced
+// and first received in full from the network, along with the peers it came
+// from. Nil is returned if the transaction was never propagated to the local
+// node, or was already evicted from the recent arrivals.
+func (api *PrivateDebugAPI) TransactionArrival(txHash common.Hash) *txArrival {
+	return api.fourtwenty.handler.arrivals.txArrival(txHash)
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
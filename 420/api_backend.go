@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync"
 
 	"github.com/420integrated/go-420coin/accounts"
 	"github.com/420integrated/go-420coin/common"
@@ -44,6 +45,15 @@ type FourtwentyAPIBackend struct {
 	extRPCEnabled bool
 	fourtwenty    *Fourtwentycoin
 	gpo           *smokeprice.Oracle
+
+	evmPool sync.Pool // *pooledEVM instances released by finished calls
+}
+
+// pooledEVM is a reusable EVM instance along with the hash of the header its
+// block context was created for.
+type pooledEVM struct {
+	evm  *vm.EVM
+	hash common.Hash
 }
 
 // ChainConfig returns the active chain configuration.
@@ -55,9 +65,9 @@ func (b *FourtwentyAPIBackend) CurrentBlock() *types.Block {
 	return b.fourtwenty.blockchain.CurrentBlock()
 }
 
-func (b *FourtwentyAPIBackend) SetHead(number uint64) {
+func (b *FourtwentyAPIBackend) SetHead(number uint64) error {
 	b.fourtwenty.handler.downloader.Cancel()
-	b.fourtwenty.blockchain.SetHead(number)
+	return b.fourtwenty.blockchain.SetHead(number)
 }
 
 func (b *FourtwentyAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -194,9 +204,28 @@ func (b *FourtwentyAPIBackend) GetTd(ctx context.Context, hash common.Hash) *big
 func (b *FourtwentyAPIBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error) {
 	vmError := func() error { return nil }
 
-	txContext := core.NewEVMTxContext(msg)
-	context := core.NewEVMBlockContext(header, b.fourtwenty.BlockChain(), nil)
-	return vm.NewEVM(context, txContext, state, b.fourtwenty.blockchain.Config(), *b.fourtwenty.blockchain.GetVMConfig()), vmError, nil
+	// Reuse an instance, and with it the block context, if the last released
+	// one was created for the same header.
+	var (
+		evm       *vm.EVM
+		hash      = header.Hash()
+		txContext = core.NewEVMTxContext(msg)
+	)
+	if cached, ok := b.evmPool.Get().(*pooledEVM); ok && cached.hash == hash {
+		evm = cached.evm
+		evm.Reset(txContext, state)
+	} else {
+		context := core.NewEVMBlockContext(header, b.fourtwenty.BlockChain(), nil)
+		evm = vm.NewEVM(context, txContext, state, b.fourtwenty.blockchain.Config(), *b.fourtwenty.blockchain.GetVMConfig())
+	}
+	return evm, vmError, nil
+}
+
+// ReleaseEVM hands an EVM obtained from GetEVM for the given header back for
+// reuse by later calls.
+func (b *FourtwentyAPIBackend) ReleaseEVM(evm *vm.EVM, header *types.Header) {
+	evm.Reset(vm.TxContext{}, nil) // don't hold on to the state
+	b.evmPool.Put(&pooledEVM{evm: evm, hash: header.Hash()})
 }
 
 func (b *FourtwentyAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
@@ -260,6 +289,10 @@ func (b *FourtwentyAPIBackend) TxPoolContent() (map[common.Address]types.Transac
 	return b.fourtwenty.TxPool().Content()
 }
 
+func (b *FourtwentyAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.fourtwenty.TxPool().ContentFrom(addr)
+}
+
 func (b *FourtwentyAPIBackend) TxPool() *core.TxPool {
 	return b.fourtwenty.TxPool()
 }
@@ -268,6 +301,10 @@ func (b *FourtwentyAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent)
 	return b.fourtwenty.TxPool().SubscribeNewTxsEvent(ch)
 }
 
+func (b *FourtwentyAPIBackend) SubscribeDroppedTxEvent(ch chan<- core.DroppedTxEvent) event.Subscription {
+	return b.fourtwenty.TxPool().SubscribeDroppedTxEvent(ch)
+}
+
 func (b *FourtwentyAPIBackend) Downloader() *downloader.Downloader {
 	return b.fourtwenty.Downloader()
 }
@@ -276,6 +313,10 @@ func (b *FourtwentyAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, erro
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *FourtwentyAPIBackend) SmokePriceHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []float64, error) {
+	return b.gpo.SmokePriceHistory(ctx, blocks, lastBlock, percentiles)
+}
+
 func (b *FourtwentyAPIBackend) ChainDb() fourtwentydb.Database {
 	return b.fourtwenty.ChainDb()
 }
@@ -300,6 +341,14 @@ func (b *FourtwentyAPIBackend) RPCTxFeeCap() float64 {
 	return b.fourtwenty.config.RPCTxFeeCap
 }
 
+func (b *FourtwentyAPIBackend) RPCStructuredErrors() bool {
+	return b.fourtwenty.config.RPCStructuredErrors
+}
+
+func (b *FourtwentyAPIBackend) RPCRecipientCheck() bool {
+	return b.fourtwenty.config.RPCRecipientCheck
+}
+
 func (b *FourtwentyAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.fourtwenty.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
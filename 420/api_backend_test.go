@@ -0,0 +1,269 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/internal/420api"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// newTestAPIBackend creates an API backend on top of a test handler's chain.
+func newTestAPIBackend(handler *testHandler) *FourtwentyAPIBackend {
+	return &FourtwentyAPIBackend{
+		fourtwenty: &Fourtwentycoin{
+			blockchain: handler.chain,
+			handler:    handler.handler,
+		},
+	}
+}
+
+// Tests that debug_setHead only rewinds the chain after a matching confirmation
+// and that the rewind is announced to subscribed subsystems.
+func TestSetHeadConfirmation(t *testing.T) {
+	handler := newTestHandlerWithBlocks(10)
+	defer handler.close()
+
+	api := fourtwentyapi.NewPrivateDebugAPI(newTestAPIBackend(handler))
+
+	// Confirming without a pending request must fail
+	if err := api.SetHead(5, "0x00"); err == nil {
+		t.Fatalf("rewind confirmed without a request")
+	}
+	// Targets above the current head must be rejected up front
+	if _, err := api.RequestSetHead(11); err == nil {
+		t.Fatalf("rewind requested above the head")
+	}
+	// A wrong token must be rejected without consuming the request
+	token, err := api.RequestSetHead(5)
+	if err != nil {
+		t.Fatalf("failed to request rewind: %v", err)
+	}
+	if err := api.SetHead(5, "0xdeadbeef"); err == nil {
+		t.Fatalf("rewind confirmed with an invalid token")
+	}
+	// A target mismatch must be rejected and consume the token
+	if err := api.SetHead(4, token); err == nil {
+		t.Fatalf("rewind confirmed with a mismatching target")
+	}
+	if err := api.SetHead(5, token); err == nil {
+		t.Fatalf("rewind confirmed with a consumed token")
+	}
+	if head := handler.chain.CurrentBlock().NumberU64(); head != 10 {
+		t.Fatalf("chain rewound by rejected confirmations: head #%d", head)
+	}
+	// A request must be invalidated if the chain moves in the meantime
+	token, err = api.RequestSetHead(5)
+	if err != nil {
+		t.Fatalf("failed to request rewind: %v", err)
+	}
+	blocks, _ := core.GenerateChain(handler.chain.Config(), handler.chain.CurrentBlock(), handler.chain.Engine(), handler.db, 1, nil)
+	if _, err := handler.chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to extend chain: %v", err)
+	}
+	if err := api.SetHead(5, token); err == nil {
+		t.Fatalf("rewind confirmed after the head changed")
+	}
+	// A valid confirmation must rewind the chain and notify head subscribers
+	heads := make(chan core.ChainHeadEvent, 1)
+	sub := handler.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	token, err = api.RequestSetHead(5)
+	if err != nil {
+		t.Fatalf("failed to request rewind: %v", err)
+	}
+	if err := api.SetHead(hexutil.Uint64(5), token); err != nil {
+		t.Fatalf("failed to confirm rewind: %v", err)
+	}
+	if head := handler.chain.CurrentBlock().NumberU64(); head != 5 {
+		t.Fatalf("head mismatch after rewind: have #%d, want #5", head)
+	}
+	select {
+	case ev := <-heads:
+		if number := ev.Block.NumberU64(); number != 5 {
+			t.Fatalf("head event mismatch: have #%d, want #5", number)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no head event after rewind")
+	}
+}
+
+// Tests that the block fullness history aggregates smoke usage and transaction
+// counts into buckets of the requested resolution.
+func TestSmokeUsedRatio(t *testing.T) {
+	handler := newTestHandler()
+	defer handler.close()
+
+	// Fill blocks #1 and #3 with transactions, leave #2 and #4 empty
+	signer := types.HomesteadSigner{}
+	blocks, _ := core.GenerateChain(handler.chain.Config(), handler.chain.Genesis(), handler.chain.Engine(), handler.db, 4, func(i int, block *core.BlockGen) {
+		txs := map[int]int{0: 2, 2: 1}[i]
+		for j := 0; j < txs; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(testAddr), common.Address{0x01}, big.NewInt(1), params.TxSmoke, big.NewInt(1), nil), signer, testKey)
+			block.AddTx(tx)
+		}
+	})
+	if _, err := handler.chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := fourtwentyapi.NewPublicBlockChainAPI(newTestAPIBackend(handler))
+
+	buckets, err := api.SmokeUsedRatio(context.Background(), 1, rpc.LatestBlockNumber, 2)
+	if err != nil {
+		t.Fatalf("failed to retrieve smoke usage: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("bucket count mismatch: have %d, want 2", len(buckets))
+	}
+	for i, want := range []struct {
+		from, to uint64
+		txs      int
+		perBlock float64
+	}{
+		{1, 2, 2, 1},
+		{3, 4, 1, 0.5},
+	} {
+		bucket := buckets[i]
+		if uint64(bucket.FromBlock) != want.from || uint64(bucket.ToBlock) != want.to {
+			t.Errorf("bucket %d: range mismatch: have #%d-#%d, want #%d-#%d", i, bucket.FromBlock, bucket.ToBlock, want.from, want.to)
+		}
+		limit := blocks[want.from-1].SmokeLimit() + blocks[want.to-1].SmokeLimit()
+		if uint64(bucket.SmokeUsed) != uint64(want.txs)*params.TxSmoke || uint64(bucket.SmokeLimit) != limit {
+			t.Errorf("bucket %d: smoke mismatch: have %d/%d, want %d/%d", i, bucket.SmokeUsed, bucket.SmokeLimit, uint64(want.txs)*params.TxSmoke, limit)
+		}
+		if ratio := float64(bucket.SmokeUsed) / float64(bucket.SmokeLimit); bucket.SmokeUsedRatio != ratio {
+			t.Errorf("bucket %d: ratio mismatch: have %v, want %v", i, bucket.SmokeUsedRatio, ratio)
+		}
+		if bucket.TxsPerBlock != want.perBlock {
+			t.Errorf("bucket %d: transactions per block mismatch: have %v, want %v", i, bucket.TxsPerBlock, want.perBlock)
+		}
+	}
+	// Inverted, unknown and pending ranges must be rejected
+	if _, err := api.SmokeUsedRatio(context.Background(), 3, 1, 1); err == nil {
+		t.Errorf("inverted range accepted")
+	}
+	if _, err := api.SmokeUsedRatio(context.Background(), 1, 5, 1); err == nil {
+		t.Errorf("range beyond the head accepted")
+	}
+	if _, err := api.SmokeUsedRatio(context.Background(), 1, rpc.PendingBlockNumber, 1); err == nil {
+		t.Errorf("pending range accepted")
+	}
+}
+
+// Tests that released EVM instances are only reused for calls on the same
+// header and are handed out reset to the new call.
+func TestEVMPool(t *testing.T) {
+	handler := newTestHandlerWithBlocks(2)
+	defer handler.close()
+
+	var (
+		backend = newTestAPIBackend(handler)
+		ctx     = context.Background()
+		head    = handler.chain.CurrentHeader()
+		parent  = handler.chain.GetHeaderByHash(head.ParentHash)
+		to      = common.Address{0x01}
+	)
+	newState := func() *state.StateDB {
+		statedb, err := handler.chain.StateAt(head.Root)
+		if err != nil {
+			t.Fatalf("failed to retrieve state: %v", err)
+		}
+		return statedb
+	}
+	message := func(from common.Address) core.Message {
+		return (&fourtwentyapi.CallArgs{From: &from, To: &to}).ToMessage(0)
+	}
+	evm, _, err := backend.GetEVM(ctx, message(testAddr), newState(), head)
+	if err != nil {
+		t.Fatalf("failed to create EVM: %v", err)
+	}
+	evm.Cancel()
+	backend.ReleaseEVM(evm, head)
+	if evm.StateDB != nil {
+		t.Fatalf("released EVM still references the state")
+	}
+	// The pool may drop instances at any time, so only check that whatever is
+	// handed out is set up for the new call
+	statedb := newState()
+	reused, _, err := backend.GetEVM(ctx, message(common.Address{0x02}), statedb, head)
+	if err != nil {
+		t.Fatalf("failed to create EVM: %v", err)
+	}
+	if reused.Cancelled() {
+		t.Errorf("handed out EVM is cancelled")
+	}
+	if reused.StateDB != statedb {
+		t.Errorf("handed out EVM state mismatch")
+	}
+	if reused.Origin != (common.Address{0x02}) {
+		t.Errorf("handed out EVM origin mismatch: have %x, want %x", reused.Origin, common.Address{0x02})
+	}
+	// An instance released for one header must not be handed out for another
+	backend.ReleaseEVM(reused, head)
+	other, _, err := backend.GetEVM(ctx, message(testAddr), newState(), parent)
+	if err != nil {
+		t.Fatalf("failed to create EVM: %v", err)
+	}
+	if other == reused || other.Context.BlockNumber.Cmp(parent.Number) != 0 {
+		t.Errorf("EVM of header #%d reused for header #%d", head.Number, parent.Number)
+	}
+}
+
+// Tests that calls keep working on pooled EVM instances, including ones that a
+// previous call aborted.
+func TestDoCallEVMPool(t *testing.T) {
+	handler := newTestHandlerWithBlocks(2)
+	defer handler.close()
+
+	var (
+		backend = newTestAPIBackend(handler)
+		ctx     = context.Background()
+		to      = common.Address{0x01}
+		value   = (*hexutil.Big)(big.NewInt(1))
+		loop    = hexutil.Bytes{0x5b, 0x60, 0x00, 0x56} // JUMPDEST PUSH1 0 JUMP
+	)
+	transfer := fourtwentyapi.CallArgs{From: &testAddr, To: &to, Value: value}
+	endless := fourtwentyapi.CallArgs{From: &testAddr, Data: &loop}
+
+	for i, number := range []rpc.BlockNumber{2, 2, 1, 2, 2} {
+		block := rpc.BlockNumberOrHashWithNumber(number)
+
+		result, err := fourtwentyapi.DoCall(ctx, backend, transfer, block, nil, vm.Config{}, 0, 0)
+		if err != nil {
+			t.Fatalf("call %d: failed to transfer: %v", i, err)
+		}
+		if result.Failed() || result.UsedSmoke != params.TxSmoke {
+			t.Fatalf("call %d: transfer mismatch: failed %v, smoke %d", i, result.Failed(), result.UsedSmoke)
+		}
+		// Abort a call on the same header, the next one must not be affected
+		if _, err := fourtwentyapi.DoCall(ctx, backend, endless, block, nil, vm.Config{}, 10*time.Millisecond, 0); err == nil {
+			t.Fatalf("call %d: endless call not aborted", i)
+		}
+	}
+}
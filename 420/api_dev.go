@@ -0,0 +1,151 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/consensus/clique"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// devSealTimeout is the maximum time to wait for an on-demand block to be sealed.
+const devSealTimeout = 5 * time.Second
+
+var (
+	errSnapshotReorged = errors.New("snapshot block no longer canonical")
+	errSealTimeout     = errors.New("block sealing timed out")
+)
+
+// devSnapshot is a point of the developer chain which can be reverted to.
+type devSnapshot struct {
+	number uint64
+	hash   common.Hash
+	shift  int64 // Clock shift of the engine when the snapshot was taken
+}
+
+// PrivateDevAPI provides Ganache compatible methods to control an instant-seal
+// developer chain, so that dapp test suites can run against it unmodified.
+type PrivateDevAPI struct {
+	fourtwenty *Fourtwentycoin
+	engine     *clique.Clique
+
+	snapshots []devSnapshot
+	lock      sync.Mutex
+}
+
+// NewPrivateDevAPI creates a new developer chain control API.
+func NewPrivateDevAPI(fourtwenty *Fourtwentycoin, engine *clique.Clique) *PrivateDevAPI {
+	return &PrivateDevAPI{fourtwenty: fourtwenty, engine: engine}
+}
+
+// Snapshot records the current head of the chain and the clock shift, returning
+// the identifier to pass to Revert.
+func (api *PrivateDevAPI) Snapshot() hexutil.Uint64 {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	head := api.fourtwenty.blockchain.CurrentBlock()
+	api.snapshots = append(api.snapshots, devSnapshot{
+		number: head.NumberU64(),
+		hash:   head.Hash(),
+		shift:  api.engine.ShiftTime(0),
+	})
+	return hexutil.Uint64(len(api.snapshots))
+}
+
+// Revert rewinds the chain and the clock to the given snapshot, dropping it along
+// with all the later ones. It returns false if the snapshot is unknown.
+func (api *PrivateDevAPI) Revert(id hexutil.Uint64) (bool, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	if id == 0 || uint64(id) > uint64(len(api.snapshots)) {
+		return false, nil
+	}
+	snap := api.snapshots[id-1]
+	api.snapshots = api.snapshots[:id-1]
+
+	chain := api.fourtwenty.blockchain
+	if chain.GetCanonicalHash(snap.number) != snap.hash {
+		return false, errSnapshotReorged
+	}
+	if chain.CurrentBlock().NumberU64() > snap.number {
+		if err := chain.SetHead(snap.number); err != nil {
+			return false, err
+		}
+	}
+	api.engine.ShiftTime(snap.shift - api.engine.ShiftTime(0))
+	log.Info("Reverted developer chain", "snapshot", uint64(id), "number", snap.number, "hash", snap.hash)
+	return true, nil
+}
+
+// IncreaseTime moves the clock used to timestamp new blocks forward by the given
+// number of seconds, returning the total shift from the wall clock.
+func (api *PrivateDevAPI) IncreaseTime(seconds int64) int64 {
+	return api.engine.ShiftTime(seconds)
+}
+
+// Mine seals a new block on top of the chain head, even if it is empty. If a
+// timestamp is given, the clock is moved to it first.
+func (api *PrivateDevAPI) Mine(timestamp *uint64) (string, error) {
+	if timestamp != nil {
+		api.engine.ShiftTime(int64(*timestamp) - time.Now().Unix() - api.engine.ShiftTime(0))
+	}
+	var (
+		chain  = api.fourtwenty.blockchain
+		config = api.fourtwenty.config.Miner
+		parent = chain.CurrentBlock()
+	)
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		SmokeLimit: core.CalcSmokeLimit(parent, config.SmokeFloor, config.SmokeCeil),
+		Extra:      common.CopyBytes(config.ExtraData),
+	}
+	if err := api.engine.Prepare(chain, header); err != nil {
+		return "", err
+	}
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return "", err
+	}
+	block, err := api.engine.FinalizeAndAssemble(chain, header, statedb, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	results := make(chan *types.Block, 1)
+	if err := api.engine.SealEmpty(chain, block, results, nil); err != nil {
+		return "", err
+	}
+	select {
+	case sealed := <-results:
+		if _, err := chain.InsertChain(types.Blocks{sealed}); err != nil {
+			return "", err
+		}
+	case <-time.After(devSealTimeout):
+		return "", errSealTimeout
+	}
+	return "0x0", nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/accounts"
+	"github.com/420integrated/go-420coin/consensus/clique"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+)
+
+// Tests that the developer chain can be mined on demand, moved forward in time
+// and reverted to a snapshot.
+func TestDevAPI(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		genesis = core.DeveloperGenesisBlock(0, addr)
+	)
+	genesis.MustCommit(db)
+
+	engine := clique.New(genesis.Config.Clique, db)
+	engine.Authorize(addr, func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	})
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	api := NewPrivateDevAPI(&Fourtwentycoin{blockchain: chain, config: &Config{}}, engine)
+
+	id := api.Snapshot()
+	for i := 0; i < 2; i++ {
+		if _, err := api.Mine(nil); err != nil {
+			t.Fatalf("failed to mine block %d: %v", i+1, err)
+		}
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 2 {
+		t.Fatalf("head mismatch after mining: have %d, want 2", head)
+	}
+	// Shift the clock and check that new blocks are timestamped accordingly
+	if shift := api.IncreaseTime(3600); shift != 3600 {
+		t.Fatalf("time shift mismatch: have %d, want 3600", shift)
+	}
+	if _, err := api.Mine(nil); err != nil {
+		t.Fatalf("failed to mine shifted block: %v", err)
+	}
+	if have, min := chain.CurrentBlock().Time(), uint64(time.Now().Unix()+3600); have < min {
+		t.Fatalf("shifted block timestamp too early: have %d, want >= %d", have, min)
+	}
+	// Revert to the snapshot and ensure both the chain and the clock are restored
+	if ok, err := api.Revert(id); !ok || err != nil {
+		t.Fatalf("failed to revert: %v, %v", ok, err)
+	}
+	if head := chain.CurrentBlock().NumberU64(); head != 0 {
+		t.Fatalf("head mismatch after revert: have %d, want 0", head)
+	}
+	if shift := api.IncreaseTime(0); shift != 0 {
+		t.Fatalf("time shift not reverted: have %d", shift)
+	}
+	if ok, _ := api.Revert(id); ok {
+		t.Fatalf("reverted to a dropped snapshot")
+	}
+}
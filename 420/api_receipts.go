@@ -0,0 +1,140 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// errReceiptStatusAvailable is returned if a receipt status backfill is requested
+// for blocks whose receipts already carry the transaction outcomes.
+var errReceiptStatusAvailable = errors.New("receipts after Byzantium already carry statuses")
+
+// BackfillReceiptStatus re-executes the canonical blocks between start and end
+// (inclusive) that predate Byzantium and stores the outcome of their transactions
+// alongside the receipts, which only carry intermediate state roots. Blocks that
+// were already backfilled are only re-executed to advance the state. It returns
+// the number of blocks whose receipt statuses were stored.
+func (api *PrivateDebugAPI) BackfillReceiptStatus(ctx context.Context, start, end rpc.BlockNumber) (uint64, error) {
+	var (
+		chain  = api.fourtwenty.blockchain
+		config = chain.Config()
+		head   = chain.CurrentBlock().NumberU64()
+		from   = uint64(start)
+		to     = uint64(end)
+	)
+	if start == rpc.LatestBlockNumber || start == rpc.PendingBlockNumber {
+		from = head
+	}
+	if end == rpc.LatestBlockNumber || end == rpc.PendingBlockNumber {
+		to = head
+	}
+	// Limit the range to the blocks before Byzantium, skipping the genesis
+	if config.ByzantiumBlock != nil {
+		fork := config.ByzantiumBlock.Uint64()
+		if from >= fork {
+			return 0, errReceiptStatusAvailable
+		}
+		if to >= fork {
+			to = fork - 1
+		}
+	}
+	if from == 0 {
+		from = 1
+	}
+	if from > to {
+		return 0, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", to, from)
+	}
+	parent := chain.GetBlockByNumber(from - 1)
+	if parent == nil {
+		return 0, fmt.Errorf("parent block #%d not found", from-1)
+	}
+	statedb, err := api.computeStateDB(parent, defaultTraceReexec)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		database   = statedb.Database()
+		backfilled uint64
+		started    = time.Now()
+		logged     time.Time
+		proot      common.Hash
+	)
+	defer func() {
+		if proot != (common.Hash{}) {
+			database.TrieDB().Dereference(proot)
+		}
+	}()
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return backfilled, err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Backfilling receipt statuses", "block", number, "target", to, "backfilled", backfilled, "elapsed", time.Since(started))
+			logged = time.Now()
+		}
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return backfilled, fmt.Errorf("block #%d not found", number)
+		}
+		receipts, _, _, err := chain.Processor().Process(block, statedb, vm.Config{})
+		if err != nil {
+			return backfilled, fmt.Errorf("processing block %d failed: %v", number, err)
+		}
+		// Only trust the outcomes if the re-execution reproduced the stored receipts
+		if len(receipts) > 0 && !rawdb.HasReceiptStatuses(api.fourtwenty.ChainDb(), block.Hash(), number) {
+			stored := rawdb.ReadRawReceipts(api.fourtwenty.ChainDb(), block.Hash(), number)
+			if len(stored) != len(receipts) {
+				return backfilled, fmt.Errorf("block #%d receipt count mismatch: have %d, want %d", number, len(receipts), len(stored))
+			}
+			statuses := make([]uint64, len(receipts))
+			for i, receipt := range receipts {
+				if !bytes.Equal(receipt.PostState, stored[i].PostState) || receipt.CumulativeSmokeUsed != stored[i].CumulativeSmokeUsed {
+					return backfilled, fmt.Errorf("block #%d receipt %d mismatch after re-execution", number, i)
+				}
+				statuses[i] = receipt.Status
+			}
+			chain.WriteReceiptStatuses(block.Hash(), number, statuses)
+			backfilled++
+		}
+		// Finalize the state so any modifications are written to the trie
+		root, err := statedb.Commit(config.IsEIP158(block.Number()))
+		if err != nil {
+			return backfilled, err
+		}
+		if err := statedb.Reset(root); err != nil {
+			return backfilled, fmt.Errorf("state reset after block %d failed: %v", number, err)
+		}
+		database.TrieDB().Reference(root, common.Hash{})
+		if proot != (common.Hash{}) {
+			database.TrieDB().Dereference(proot)
+		}
+		proot = root
+	}
+	log.Info("Backfilled receipt statuses", "from", from, "to", to, "backfilled", backfilled, "elapsed", time.Since(started))
+	return backfilled, nil
+}
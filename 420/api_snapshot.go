@@ -0,0 +1,77 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+)
+
+// errSnapshotDisabled is returned by the snapshot debug methods if the node runs
+// without the state snapshot.
+var errSnapshotDisabled = errors.New("state snapshot disabled")
+
+// SnapshotStatus is the state of the snapshot tree, as returned by SnapshotStatus.
+type SnapshotStatus struct {
+	DiskRoot   common.Hash   `json:"diskRoot"`         // State root of the persistent layer
+	Layers     int           `json:"layers"`           // Number of layers, including the persistent one
+	Generating bool          `json:"generating"`       // Whether the persistent layer is being generated
+	Marker     hexutil.Bytes `json:"marker,omitempty"` // Account (and slot) hash up to which the persistent layer is generated
+	Progress   float64       `json:"progress"`         // Estimated fraction of the account space generated
+}
+
+// SnapshotStatus reports the layers of the state snapshot and the progress of its
+// background generation.
+func (api *PrivateDebugAPI) SnapshotStatus() (*SnapshotStatus, error) {
+	snaps := api.fourtwenty.blockchain.Snapshots()
+	if snaps == nil {
+		return nil, errSnapshotDisabled
+	}
+	root, layers, marker, err := snaps.Progress()
+	if err != nil {
+		return nil, err
+	}
+	status := &SnapshotStatus{
+		DiskRoot:   root,
+		Layers:     layers,
+		Generating: marker != nil,
+		Marker:     marker,
+		Progress:   1,
+	}
+	if marker != nil {
+		// Account hashes are uniformly distributed, so the position of the marker
+		// in the hash space approximates the generated fraction of the state
+		var prefix [8]byte
+		copy(prefix[:], marker)
+		status.Progress = float64(binary.BigEndian.Uint64(prefix[:])) / math.MaxUint64
+	}
+	return status, nil
+}
+
+// RebuildSnapshot wipes the state snapshot and regenerates it in the background
+// from the state of the current head block, returning the state root it is being
+// generated for. The progress can be followed through SnapshotStatus.
+func (api *PrivateDebugAPI) RebuildSnapshot() (common.Hash, error) {
+	if api.fourtwenty.blockchain.Snapshots() == nil {
+		return common.Hash{}, errSnapshotDisabled
+	}
+	return api.fourtwenty.blockchain.RebuildSnapshot(), nil
+}
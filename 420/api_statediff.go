@@ -0,0 +1,306 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/rlp"
+	"github.com/420integrated/go-420coin/rpc"
+	"github.com/420integrated/go-420coin/trie"
+)
+
+// stateDiffEntry is a single value of the state before and after a block.
+type stateDiffEntry struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// accountStateDiff is the aggregate change of a single account over a block.
+// Fields left untouched by the block are omitted.
+type accountStateDiff struct {
+	Balance *stateDiffEntry                 `json:"balance,omitempty"`
+	Nonce   *stateDiffEntry                 `json:"nonce,omitempty"`
+	Code    *stateDiffEntry                 `json:"code,omitempty"`
+	Storage map[common.Hash]*stateDiffEntry `json:"storage,omitempty"`
+}
+
+// stateDiffTracer is a vm.Tracer collecting every account and storage slot
+// that might have been modified during execution. It over-approximates the
+// touched set, the actual diff is computed by comparing the states afterwards.
+// Accounts modified outside the EVM are added from the state's dirty set.
+type stateDiffTracer struct {
+	accounts   map[common.Address]map[common.Hash]struct{}
+	destructed map[common.Address]struct{} // Accounts whose storage might have been wiped
+}
+
+// newStateDiffTracer creates an empty state diff collector.
+func newStateDiffTracer() *stateDiffTracer {
+	return &stateDiffTracer{
+		accounts:   make(map[common.Address]map[common.Hash]struct{}),
+		destructed: make(map[common.Address]struct{}),
+	}
+}
+
+// touch marks an account as potentially modified.
+func (t *stateDiffTracer) touch(addr common.Address) map[common.Hash]struct{} {
+	slots, ok := t.accounts[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		t.accounts[addr] = slots
+	}
+	return slots
+}
+
+// touchSlot marks a storage slot of an account as potentially modified.
+func (t *stateDiffTracer) touchSlot(addr common.Address, slot common.Hash) {
+	t.touch(addr)[slot] = struct{}{}
+}
+
+func (t *stateDiffTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, smoke uint64, value *big.Int) error {
+	t.touch(from)
+	t.touch(to)
+	return nil
+}
+
+func (t *stateDiffTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, smoke, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	caller := contract.Address()
+	t.touch(caller)
+
+	switch op {
+	case vm.SSTORE:
+		if len(stack.Data()) >= 1 {
+			t.touchSlot(caller, common.Hash(stack.Back(0).Bytes32()))
+		}
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if len(stack.Data()) >= 2 {
+			t.touch(common.Address(stack.Back(1).Bytes20()))
+		}
+	case vm.SELFDESTRUCT:
+		t.destructed[caller] = struct{}{}
+		if len(stack.Data()) >= 1 {
+			t.touch(common.Address(stack.Back(0).Bytes20()))
+		}
+	case vm.CREATE:
+		t.touch(crypto.CreateAddress(caller, env.StateDB.GetNonce(caller)))
+	case vm.CREATE2:
+		if len(stack.Data()) >= 4 {
+			var (
+				offset = int64(stack.Back(1).Uint64())
+				size   = int64(stack.Back(2).Uint64())
+				salt   = stack.Back(3).Bytes32()
+			)
+			t.touch(crypto.CreateAddress2(caller, salt, crypto.Keccak256(memory.GetCopy(offset, size))))
+		}
+	}
+	return nil
+}
+
+func (t *stateDiffTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, smoke, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *stateDiffTracer) CaptureEnd(output []byte, smokeUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// diff compares the touched accounts and slots between the pre and post states
+// and returns the ones that actually changed.
+func (t *stateDiffTracer) diff(pre, post *state.StateDB) map[common.Address]*accountStateDiff {
+	result := make(map[common.Address]*accountStateDiff)
+	for addr, slots := range t.accounts {
+		var (
+			account = new(accountStateDiff)
+			changed bool
+		)
+		if from, to := pre.GetBalance(addr), post.GetBalance(addr); from.Cmp(to) != 0 {
+			account.Balance = &stateDiffEntry{From: (*hexutil.Big)(from), To: (*hexutil.Big)(to)}
+			changed = true
+		}
+		if from, to := pre.GetNonce(addr), post.GetNonce(addr); from != to {
+			account.Nonce = &stateDiffEntry{From: hexutil.Uint64(from), To: hexutil.Uint64(to)}
+			changed = true
+		}
+		if from, to := pre.GetCode(addr), post.GetCode(addr); !bytes.Equal(from, to) {
+			account.Code = &stateDiffEntry{From: hexutil.Bytes(from), To: hexutil.Bytes(to)}
+			changed = true
+		}
+		// A self-destruct wipes the storage as a whole, without touching any of
+		// the slots, so compare all the slots the account had before the block.
+		// It might have been re-created since, hence the comparison.
+		if _, ok := t.destructed[addr]; ok {
+			for hash, entry := range t.wipedSlots(pre, post, addr, slots) {
+				if account.Storage == nil {
+					account.Storage = make(map[common.Hash]*stateDiffEntry)
+				}
+				account.Storage[hash] = entry
+				changed = true
+			}
+		}
+		for slot := range slots {
+			if from, to := pre.GetState(addr, slot), post.GetState(addr, slot); from != to {
+				if account.Storage == nil {
+					account.Storage = make(map[common.Hash]*stateDiffEntry)
+				}
+				account.Storage[slot] = &stateDiffEntry{From: from, To: to}
+				changed = true
+			}
+		}
+		if changed {
+			result[addr] = account
+		}
+	}
+	return result
+}
+
+// wipedSlots marks all the storage slots an account had in the pre state as
+// touched. Slots whose preimage is unknown can't be looked up by the slot, they
+// are compared by their hash instead and the changed ones returned, keyed by the
+// hash of the slot.
+func (t *stateDiffTracer) wipedSlots(pre, post *state.StateDB, addr common.Address, slots map[common.Hash]struct{}) map[common.Hash]*stateDiffEntry {
+	tr := pre.StorageTrie(addr)
+	if tr == nil {
+		return nil
+	}
+	var (
+		changed = make(map[common.Hash]*stateDiffEntry)
+		postTr  = post.StorageTrie(addr)
+	)
+	it := trie.NewIterator(tr.NodeIterator(nil))
+	for it.Next() {
+		if key := tr.GetKey(it.Key); key != nil {
+			slots[common.BytesToHash(key)] = struct{}{}
+			continue
+		}
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			continue
+		}
+		hash := common.BytesToHash(it.Key)
+		if from, to := common.BytesToHash(content), storageByHash(postTr, hash); from != to {
+			changed[hash] = &stateDiffEntry{From: from, To: to}
+		}
+	}
+	return changed
+}
+
+// storageByHash retrieves a storage slot from a storage trie by the hash of the
+// slot, the key it's stored under.
+func storageByHash(tr state.Trie, hash common.Hash) common.Hash {
+	if tr == nil {
+		return common.Hash{}
+	}
+	it := trie.NewIterator(tr.NodeIterator(hash[:]))
+	if !it.Next() || !bytes.Equal(it.Key, hash[:]) {
+		return common.Hash{}
+	}
+	_, content, _, err := rlp.Split(it.Value)
+	if err != nil {
+		return common.Hash{}
+	}
+	return common.BytesToHash(content)
+}
+
+// TraceBlockStateDiffByNumber returns the aggregate state diff of the block with
+// the given number: the balance, nonce, code and storage of every account modified
+// by the block's transactions and rewards, before and after the block.
+func (api *PrivateDebugAPI) TraceBlockStateDiffByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) (map[common.Address]*accountStateDiff, error) {
+	var block *types.Block
+
+	switch number {
+	case rpc.PendingBlockNumber:
+		block = api.fourtwenty.miner.PendingBlock()
+	case rpc.LatestBlockNumber:
+		block = api.fourtwenty.blockchain.CurrentBlock()
+	default:
+		block = api.fourtwenty.blockchain.GetBlockByNumber(uint64(number))
+	}
+	// Trace the block if it was found
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.traceBlockStateDiff(ctx, block, config)
+}
+
+// TraceBlockStateDiff returns the aggregate state diff of the block with the
+// given hash.
+func (api *PrivateDebugAPI) TraceBlockStateDiff(ctx context.Context, hash common.Hash, config *TraceConfig) (map[common.Address]*accountStateDiff, error) {
+	block := api.fourtwenty.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	return api.traceBlockStateDiff(ctx, block, config)
+}
+
+// traceBlockStateDiff replays a block on top of its parent state with a diff
+// collecting tracer and compares the touched accounts before and after.
+func (api *PrivateDebugAPI) traceBlockStateDiff(ctx context.Context, block *types.Block, config *TraceConfig) (map[common.Address]*accountStateDiff, error) {
+	if block.NumberU64() == 0 {
+		return nil, fmt.Errorf("genesis is not traceable")
+	}
+	parent := api.fourtwenty.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, err := api.computeStateDB(parent, reexec)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		pre      = statedb.Copy()
+		chainCfg = api.fourtwenty.blockchain.Config()
+		signer   = types.MakeSigner(chainCfg, block.Number())
+		blockCtx = core.NewEVMBlockContext(block.Header(), api.fourtwenty.blockchain, nil)
+		tracer   = newStateDiffTracer()
+	)
+	for i, tx := range block.Transactions() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, _ := tx.AsMessage(signer)
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, chainCfg, vm.Config{Debug: true, Tracer: tracer})
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.SmokePool).AddSmoke(msg.Smoke())); err != nil {
+			return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		statedb.Finalise(chainCfg.IsEIP158(block.Number()))
+	}
+	// Apply the block and uncle rewards. Neither they nor the fee routing are
+	// visible to the tracer, so add every account the state saw modified.
+	api.fourtwenty.engine.Finalize(api.fourtwenty.blockchain, types.CopyHeader(block.Header()), statedb, block.Transactions(), block.Uncles())
+	for _, addr := range statedb.DirtyAccounts() {
+		tracer.touch(addr)
+	}
+
+	return tracer.diff(pre, statedb), nil
+}
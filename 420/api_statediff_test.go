@@ -0,0 +1,263 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+func TestStateDiffTracerDiff(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0x01")
+		contract = common.HexToAddress("0x02")
+		idle     = common.HexToAddress("0x03")
+		slot     = common.HexToHash("0x01")
+		unused   = common.HexToHash("0x02")
+	)
+	pre, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	pre.SetBalance(sender, big.NewInt(100))
+	pre.SetBalance(idle, big.NewInt(5))
+	pre.SetState(contract, unused, common.HexToHash("0xff"))
+	pre.Finalise(true)
+
+	post := pre.Copy()
+	post.SetBalance(sender, big.NewInt(60))
+	post.SetNonce(sender, 1)
+	post.SetCode(contract, []byte{0x60, 0x00})
+	post.SetState(contract, slot, common.HexToHash("0x2a"))
+	post.Finalise(true)
+
+	tracer := newStateDiffTracer()
+	tracer.touch(sender)
+	tracer.touch(idle)
+	tracer.touchSlot(contract, slot)
+	tracer.touchSlot(contract, unused)
+
+	diff := tracer.diff(pre, post)
+	if len(diff) != 2 {
+		t.Fatalf("diff size mismatch: have %d, want 2", len(diff))
+	}
+	if _, ok := diff[idle]; ok {
+		t.Errorf("unmodified account reported in diff")
+	}
+	account := diff[sender]
+	if account == nil || account.Balance == nil || account.Nonce == nil {
+		t.Fatalf("sender balance and nonce change missing: %+v", account)
+	}
+	if have := account.Balance.To.(*hexutil.Big).ToInt(); have.Cmp(big.NewInt(60)) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want 60", have)
+	}
+	if account.Code != nil || account.Storage != nil {
+		t.Errorf("unexpected sender code or storage change: %+v", account)
+	}
+	account = diff[contract]
+	if account == nil || account.Code == nil {
+		t.Fatalf("contract code change missing: %+v", account)
+	}
+	if len(account.Storage) != 1 || account.Storage[slot] == nil {
+		t.Fatalf("contract storage diff mismatch: %+v", account.Storage)
+	}
+	if have := account.Storage[slot].To.(common.Hash); have != common.HexToHash("0x2a") {
+		t.Errorf("storage value mismatch: have %x, want 0x2a", have)
+	}
+}
+
+// Tests that the state diff of a block includes the accounts credited outside of
+// the EVM: the reward shares of the Veterans Fund and the followers, and the
+// transaction fees routed to the fee fund.
+func TestTraceBlockStateDiffRewards(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		addr      = crypto.PubkeyToAddress(key.PublicKey)
+		registry  = common.Address{0xdd}
+		veterans  = common.Address{0xee}
+		followers = common.Address{0xff}
+		coinbase  = common.Address{0x01}
+		db        = rawdb.NewMemoryDatabase()
+		engine    = ethash.NewFaker()
+	)
+	config, err := params.TestChainConfig.WithRewardEraBlocks(map[string]*big.Int{"indica": big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("failed to move reward eras: %v", err)
+	}
+	config.RewardRegistryBlock = big.NewInt(0)
+	config.RewardRegistry = registry
+	config.VeteransFeeBlock = big.NewInt(0)
+	config.VeteransFeePercent = 20
+
+	gspec := &core.Genesis{
+		Config: config,
+		Alloc: core.GenesisAlloc{
+			addr: {Balance: big.NewInt(1000000000)},
+			registry: {Storage: map[common.Hash]common.Hash{
+				common.BytesToHash([]byte{1}): veterans.Hash(),
+				common.BytesToHash([]byte{2}): followers.Hash(),
+			}},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	blocks, _ := core.GenerateChain(config, genesis, engine, db, 1, func(i int, block *core.BlockGen) {
+		block.SetCoinbase(coinbase)
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), common.Address{0xaa}, big.NewInt(1), params.TxSmoke, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		block.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := NewPrivateDebugAPI(&Fourtwentycoin{blockchain: chain, engine: engine, chainDb: db})
+
+	diff, err := api.TraceBlockStateDiff(context.Background(), blocks[0].Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to trace state diff: %v", err)
+	}
+	post, err := chain.StateAt(blocks[0].Root())
+	if err != nil {
+		t.Fatalf("failed to retrieve post state: %v", err)
+	}
+	for _, account := range []common.Address{addr, common.Address{0xaa}, coinbase, veterans, followers} {
+		change := diff[account]
+		if change == nil || change.Balance == nil {
+			t.Errorf("account %x: balance change missing", account)
+			continue
+		}
+		if have, want := change.Balance.To.(*hexutil.Big).ToInt(), post.GetBalance(account); have.Cmp(want) != 0 {
+			t.Errorf("account %x: balance mismatch: have %v, want %v", account, have, want)
+		}
+	}
+}
+
+// Tests that the storage slots wiped by a self-destruct are reported as cleared,
+// even if they were never touched individually, unless the account was re-created
+// with the same values.
+func TestStateDiffTracerDestruct(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x02")
+		kept     = common.HexToHash("0x01")
+		cleared  = common.HexToHash("0x02")
+		sdb      = state.NewDatabase(rawdb.NewMemoryDatabase())
+	)
+	genesis, _ := state.New(common.Hash{}, sdb, nil)
+	genesis.SetCode(contract, []byte{0x33, 0xff})
+	genesis.SetNonce(contract, 1)
+	genesis.SetState(contract, kept, common.HexToHash("0x2a"))
+	genesis.SetState(contract, cleared, common.HexToHash("0x2b"))
+	root, _ := genesis.Commit(true)
+	pre, _ := state.New(root, sdb, nil)
+
+	// Destroy the contract and re-create it with only one of the slots set
+	post := pre.Copy()
+	post.Suicide(contract)
+	post.Finalise(true)
+	post.SetNonce(contract, 1)
+	post.SetState(contract, kept, common.HexToHash("0x2a"))
+	post.Finalise(true)
+
+	tracer := newStateDiffTracer()
+	tracer.touch(contract)
+	tracer.destructed[contract] = struct{}{}
+
+	account := tracer.diff(pre, post)[contract]
+	if account == nil || account.Code == nil {
+		t.Fatalf("contract code change missing: %+v", account)
+	}
+	if len(account.Storage) != 1 || account.Storage[cleared] == nil {
+		t.Fatalf("contract storage diff mismatch: %+v", account.Storage)
+	}
+	if entry := account.Storage[cleared]; entry.From.(common.Hash) != common.HexToHash("0x2b") || entry.To.(common.Hash) != (common.Hash{}) {
+		t.Errorf("cleared slot mismatch: have %x -> %x, want 0x2b -> 0", entry.From, entry.To)
+	}
+}
+
+// Tests that the state diff of a block with a self-destructing contract reports
+// all of its storage as cleared.
+func TestTraceBlockStateDiffSelfDestruct(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.Address{0xcc}
+		slots    = map[common.Hash]common.Hash{
+			common.HexToHash("0x01"): common.HexToHash("0x2a"),
+			common.HexToHash("0x02"): common.HexToHash("0x2b"),
+		}
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				addr:     {Balance: big.NewInt(1000000000)},
+				contract: {Code: []byte{0x33, 0xff}, Storage: slots, Balance: big.NewInt(1)}, // CALLER, SELFDESTRUCT
+			},
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, 1, func(i int, block *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), contract, new(big.Int), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		block.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := NewPrivateDebugAPI(&Fourtwentycoin{blockchain: chain, engine: engine, chainDb: db})
+
+	diff, err := api.TraceBlockStateDiff(context.Background(), blocks[0].Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to trace state diff: %v", err)
+	}
+	account := diff[contract]
+	if account == nil || account.Code == nil || account.Balance == nil {
+		t.Fatalf("contract code and balance change missing: %+v", account)
+	}
+	if len(account.Storage) != len(slots) {
+		t.Fatalf("storage diff size mismatch: have %d, want %d", len(account.Storage), len(slots))
+	}
+	for slot, value := range slots {
+		entry := account.Storage[slot]
+		if entry == nil {
+			t.Errorf("slot %x: clearing missing", slot)
+			continue
+		}
+		if entry.From.(common.Hash) != value || entry.To.(common.Hash) != (common.Hash{}) {
+			t.Errorf("slot %x: change mismatch: have %x -> %x, want %x -> 0", slot, entry.From, entry.To, value)
+		}
+	}
+}
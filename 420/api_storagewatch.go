@@ -0,0 +1,148 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// maxWatchedSlots is the maximum number of storage slots a single storage
+// change subscription can watch.
+const maxWatchedSlots = 1024
+
+// storageChange is a single watched storage slot which changed value.
+type storageChange struct {
+	Address common.Address `json:"address"`
+	Slot    common.Hash    `json:"slot"`
+	From    common.Hash    `json:"from"`
+	To      common.Hash    `json:"to"`
+}
+
+// storageChangeNotification lists the watched slots changed by a block.
+type storageChangeNotification struct {
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Changes     []storageChange `json:"changes"`
+}
+
+// storageWatcher tracks the last seen values of a set of storage slots.
+type storageWatcher struct {
+	values map[common.Address]map[common.Hash]common.Hash
+}
+
+// newStorageWatcher creates a watcher for the given slots, seeded with their
+// values in the given state.
+func newStorageWatcher(slots map[common.Address][]common.Hash, statedb *state.StateDB) *storageWatcher {
+	w := &storageWatcher{values: make(map[common.Address]map[common.Hash]common.Hash)}
+	for addr, keys := range slots {
+		values := make(map[common.Hash]common.Hash, len(keys))
+		for _, key := range keys {
+			values[key] = statedb.GetState(addr, key)
+		}
+		w.values[addr] = values
+	}
+	return w
+}
+
+// update reads the watched slots from the given state and returns the ones
+// which differ from the previously seen values, ordered by address and slot.
+func (w *storageWatcher) update(statedb *state.StateDB) []storageChange {
+	var changes []storageChange
+	for addr, values := range w.values {
+		for key, prev := range values {
+			if value := statedb.GetState(addr, key); value != prev {
+				changes = append(changes, storageChange{Address: addr, Slot: key, From: prev, To: value})
+				values[key] = value
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if c := bytes.Compare(changes[i].Address[:], changes[j].Address[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(changes[i].Slot[:], changes[j].Slot[:]) < 0
+	})
+	return changes
+}
+
+// StorageChanges creates a subscription that fires for every new canonical block
+// which changed the value of any of the given storage slots. The slots are read
+// from the state of each imported block and compared to the values seen before,
+// so reorgs are reported as changes against the previous chain.
+func (api *PublicDebugAPI) StorageChanges(ctx context.Context, slots map[common.Address][]common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var count int
+	for _, keys := range slots {
+		count += len(keys)
+	}
+	if count == 0 {
+		return nil, errors.New("no storage slots to watch")
+	}
+	if count > maxWatchedSlots {
+		return nil, fmt.Errorf("too many storage slots: have %d, max %d", count, maxWatchedSlots)
+	}
+	chain := api.fourtwenty.blockchain
+	statedb, err := chain.State()
+	if err != nil {
+		return nil, err
+	}
+	watcher := newStorageWatcher(slots, statedb)
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.ChainEvent, 16)
+		sub := chain.SubscribeChainEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				statedb, err := chain.StateAt(ev.Block.Root())
+				if err != nil {
+					log.Warn("Failed to open state for storage watch", "number", ev.Block.Number(), "hash", ev.Hash, "err", err)
+					continue
+				}
+				if changes := watcher.update(statedb); len(changes) > 0 {
+					notifier.Notify(rpcSub.ID, &storageChangeNotification{
+						BlockNumber: hexutil.Uint64(ev.Block.NumberU64()),
+						BlockHash:   ev.Hash,
+						Changes:     changes,
+					})
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
+)
+
+func TestStorageWatcherUpdate(t *testing.T) {
+	var (
+		oracle = common.HexToAddress("0x01")
+		keeper = common.HexToAddress("0x02")
+		price  = common.HexToHash("0x01")
+		round  = common.HexToHash("0x02")
+		job    = common.HexToHash("0x03")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(oracle, price, common.HexToHash("0x10"))
+	statedb.Finalise(true)
+
+	watcher := newStorageWatcher(map[common.Address][]common.Hash{
+		oracle: {price, round},
+		keeper: {job},
+	}, statedb)
+	if changes := watcher.update(statedb); len(changes) != 0 {
+		t.Fatalf("unexpected changes on unmodified state: %v", changes)
+	}
+	// Modify a watched slot of each account, and an unwatched one
+	statedb.SetState(oracle, price, common.HexToHash("0x11"))
+	statedb.SetState(keeper, job, common.HexToHash("0x01"))
+	statedb.SetState(keeper, price, common.HexToHash("0x01"))
+	statedb.Finalise(true)
+
+	want := []storageChange{
+		{Address: oracle, Slot: price, From: common.HexToHash("0x10"), To: common.HexToHash("0x11")},
+		{Address: keeper, Slot: job, From: common.Hash{}, To: common.HexToHash("0x01")},
+	}
+	if have := watcher.update(statedb); !reflect.DeepEqual(have, want) {
+		t.Fatalf("changes mismatch:\nhave %v\nwant %v", have, want)
+	}
+	// Changes must only be reported once
+	if changes := watcher.update(statedb); len(changes) != 0 {
+		t.Fatalf("changes reported twice: %v", changes)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -55,12 +56,17 @@ const (
 	defaultTraceReexec = uint64(128)
 )
 
+// errCustomTracersDisabled is returned if custom tracer code is submitted while
+// only the built-in tracers are allowed.
+var errCustomTracersDisabled = errors.New("custom tracers disabled, use a built-in tracer")
+
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
 	Tracer  *string
 	Timeout *string
 	Reexec  *uint64
+	Summary bool // Only report the outcome and the number of struct logs
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -719,10 +725,20 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 // and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
 	// Retrieve the transaction and assemble its EVM context
-	tx, blockHash, _, index := rawdb.ReadTransaction(api.fourtwenty.ChainDb(), hash)
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(api.fourtwenty.ChainDb(), hash)
 	if tx == nil {
 		return nil, fmt.Errorf("transaction %#x not found", hash)
 	}
+	// Serve plain call traces from the trace store if they were pre-computed for
+	// the canonical block containing the transaction
+	if config != nil && config.Tracer != nil && *config.Tracer == callTracerName && config.Timeout == nil {
+		db := api.fourtwenty.ChainDb()
+		if rawdb.ReadCanonicalHash(db, blockNumber) == blockHash {
+			if trace := rawdb.ReadCallTrace(db, blockHash, hash); trace != nil {
+				return json.RawMessage(trace), nil
+			}
+		}
+	}
 	reexec := defaultTraceReexec
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
@@ -793,10 +809,17 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
+		// Constuct the JavaScript tracer to execute with, within the configured limits
+		if api.fourtwenty.config.RPCNoCustomTracers && !tracers.IsBuiltin(*config.Tracer) {
+			return nil, errCustomTracersDisabled
+		}
 		if tracer, err = tracers.New(*config.Tracer); err != nil {
 			return nil, err
 		}
+		tracer.(*tracers.Tracer).SetLimits(tracers.Limits{
+			Steps:  api.fourtwenty.config.RPCTracerSteps,
+			Memory: api.fourtwenty.config.RPCTracerMemory,
+		})
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
@@ -805,6 +828,14 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		}()
 		defer cancel()
 
+	case config != nil && config.Summary:
+		// Count the steps without retaining them, only the outcome is reported
+		cfg := vm.LogConfig{DisableMemory: true, DisableStack: true, DisableStorage: true, DisableReturnData: true}
+		if config.LogConfig != nil {
+			cfg.Limit = config.Limit
+		}
+		tracer = newChunkedLogger(&cfg, traceChunkSize, nil)
+
 	case config == nil:
 		tracer = vm.NewStructLogger(nil)
 
@@ -833,6 +864,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  fourtwentyapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
+	case *chunkedLogger:
+		return tracer.summary(result), nil
+
 	case *tracers.Tracer:
 		return tracer.GetResult()
 
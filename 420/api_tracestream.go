@@ -0,0 +1,178 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/internal/420api"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// traceChunkSize is the number of struct log entries delivered in a single
+// notification when a transaction trace is streamed.
+const traceChunkSize = 1000
+
+// errStreamTracer is returned if a JavaScript tracer is requested for a streamed
+// trace, which only supports the struct logger.
+var errStreamTracer = errors.New("streamed traces only support the struct logger")
+
+// traceSummary is the outcome of a transaction trace without the struct logs.
+type traceSummary struct {
+	Smoke          uint64 `json:"smoke"`
+	Failed         bool   `json:"failed"`
+	ReturnValue    string `json:"returnValue"`
+	StructLogCount int    `json:"structLogCount"`
+}
+
+// txTraceChunk is a single notification of a streamed transaction trace. All
+// chunks carry struct logs, except the last one which carries either the trace
+// summary or the failure.
+type txTraceChunk struct {
+	StructLogs []fourtwentyapi.StructLogRes `json:"structLogs,omitempty"`
+	Summary    *traceSummary                `json:"summary,omitempty"`
+	Error      string                       `json:"error,omitempty"`
+}
+
+// chunkedLogger is a struct logger which releases its captured entries in chunks
+// as the execution progresses, keeping the memory use of long traces bounded.
+type chunkedLogger struct {
+	*vm.StructLogger
+
+	size    int                        // Number of entries to accumulate before delivering a chunk
+	deliver func([]vm.StructLog) error // Chunk callback, nil to drop the entries
+	count   int                        // Number of entries delivered (or dropped) so far
+	err     error                      // Delivery failure aborting the trace
+}
+
+// newChunkedLogger creates a struct logger which hands over its entries to the
+// given callback every size steps.
+func newChunkedLogger(cfg *vm.LogConfig, size int, deliver func([]vm.StructLog) error) *chunkedLogger {
+	return &chunkedLogger{
+		StructLogger: vm.NewStructLogger(cfg),
+		size:         size,
+		deliver:      deliver,
+	}
+}
+
+// CaptureState implements the Tracer interface, delivering the captured entries
+// whenever a full chunk is accumulated. If the delivery fails, the execution is
+// cancelled as nobody is interested in the rest of the trace anymore.
+func (l *chunkedLogger) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, smoke, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	if cerr := l.StructLogger.CaptureState(env, pc, op, smoke, cost, memory, stack, rStack, rData, contract, depth, err); cerr != nil {
+		return cerr
+	}
+	if len(l.StructLogs()) >= l.size {
+		if l.flush(); l.err != nil {
+			env.Cancel()
+		}
+	}
+	return nil
+}
+
+// flush delivers all the entries captured since the last chunk.
+func (l *chunkedLogger) flush() {
+	logs := l.Flush()
+	l.count += len(logs)
+
+	if l.deliver != nil && l.err == nil && len(logs) > 0 {
+		l.err = l.deliver(logs)
+	}
+}
+
+// summary flushes any remaining entries and assembles the outcome of the trace.
+func (l *chunkedLogger) summary(result *core.ExecutionResult) *traceSummary {
+	l.flush()
+
+	returnVal := fmt.Sprintf("%x", result.Return())
+	if len(result.Revert()) > 0 {
+		returnVal = fmt.Sprintf("%x", result.Revert())
+	}
+	return &traceSummary{
+		Smoke:          result.UsedSmoke,
+		Failed:         result.Failed(),
+		ReturnValue:    returnVal,
+		StructLogCount: l.count,
+	}
+}
+
+// TraceTransactionStream traces a transaction with the struct logger similarly
+// to TraceTransaction, but delivers the struct logs in chunks over a subscription
+// instead of accumulating them, followed by a final summary of the execution.
+// This allows tracing block-filling transactions without holding the entire
+// trace in memory.
+func (api *PrivateDebugAPI) TraceTransactionStream(ctx context.Context, hash common.Hash, config *TraceConfig) (*rpc.Subscription, error) {
+	// Streaming a trace is a **long** operation, only do with subscriptions
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if config != nil && config.Tracer != nil {
+		return nil, errStreamTracer
+	}
+	// Retrieve the transaction and assemble its EVM context
+	tx, blockHash, _, index := rawdb.ReadTransaction(api.fourtwenty.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	block := api.fourtwenty.blockchain.GetBlockByHash(blockHash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	msg, vmctx, statedb, err := api.computeTxEnv(block, int(index), reexec)
+	if err != nil {
+		return nil, err
+	}
+	var logConfig *vm.LogConfig
+	if config != nil {
+		logConfig = config.LogConfig
+	}
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		logger := newChunkedLogger(logConfig, traceChunkSize, func(logs []vm.StructLog) error {
+			return notifier.Notify(sub.ID, &txTraceChunk{StructLogs: fourtwentyapi.FormatLogs(logs)})
+		})
+		vmenv := vm.NewEVM(vmctx, core.NewEVMTxContext(msg), statedb, api.fourtwenty.blockchain.Config(), vm.Config{Debug: true, Tracer: logger})
+
+		result, err := core.ApplyMessage(vmenv, msg, new(core.SmokePool).AddSmoke(msg.Smoke()))
+		switch {
+		case logger.err != nil:
+			log.Debug("Streamed trace aborted", "tx", hash, "err", logger.err)
+			return
+		case err != nil:
+			notifier.Notify(sub.ID, &txTraceChunk{Error: fmt.Sprintf("tracing failed: %v", err)})
+		default:
+			summary := logger.summary(result)
+			if logger.err == nil {
+				notifier.Notify(sub.ID, &txTraceChunk{Summary: summary})
+			}
+		}
+	}()
+	return sub, nil
+}
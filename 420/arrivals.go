@@ -0,0 +1,182 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"sync"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
+	"github.com/420integrated/go-420coin/common/hexutil"
+)
+
+const (
+	// blockArrivalLimit is the maximum number of blocks to retain arrival
+	// timestamps for.
+	blockArrivalLimit = 4096
+
+	// txArrivalLimit is the maximum number of transactions to retain arrival
+	// timestamps for.
+	txArrivalLimit = 65536
+)
+
+// blockArrival contains the times a block was first heard about from the
+// network, both as an announcement and as a full block. Timestamps are in
+// milliseconds since the unix epoch, so that they can be compared across
+// vantage nodes with synchronised clocks.
+type blockArrival struct {
+	Hash          common.Hash     `json:"hash"`
+	Number        hexutil.Uint64  `json:"number"`
+	Announced     *hexutil.Uint64 `json:"announced,omitempty"`   // Time of the first announcement
+	AnnouncedBy   string          `json:"announcedBy,omitempty"` // Peer sending the first announcement
+	Received      *hexutil.Uint64 `json:"received,omitempty"`    // Time the full block was first received
+	ReceivedBy    string          `json:"receivedBy,omitempty"`  // Peer sending the first full block
+	Announcements hexutil.Uint64  `json:"announcements"`         // Number of announcements and broadcasts seen
+}
+
+// txArrival contains the times a transaction was first heard about from the
+// network, both as an announcement and as a full transaction. Timestamps are
+// in milliseconds since the unix epoch.
+type txArrival struct {
+	Hash        common.Hash     `json:"hash"`
+	Announced   *hexutil.Uint64 `json:"announced,omitempty"`   // Time of the first announcement
+	AnnouncedBy string          `json:"announcedBy,omitempty"` // Peer sending the first announcement
+	Received    *hexutil.Uint64 `json:"received,omitempty"`    // Time the full transaction was first received
+	ReceivedBy  string          `json:"receivedBy,omitempty"`  // Peer sending the first full transaction
+}
+
+// arrivalTracker records the first-seen timestamps of recent blocks and
+// transactions propagated by remote peers, so that propagation delays can be
+// measured across the network.
+type arrivalTracker struct {
+	blocks *cache.Cache // Recent block arrivals, keyed by hash
+	txs    *cache.Cache // Recent transaction arrivals, keyed by hash
+	lock   sync.Mutex   // Lock protecting updates of the cached arrivals
+}
+
+// newArrivalTracker creates an empty block and transaction arrival tracker.
+func newArrivalTracker() *arrivalTracker {
+	return &arrivalTracker{
+		blocks: cache.New("", blockArrivalLimit, 0),
+		txs:    cache.New("", txArrivalLimit, 0),
+	}
+}
+
+// arrivalTime converts a wall clock time into an arrival timestamp.
+func arrivalTime(t time.Time) *hexutil.Uint64 {
+	ms := hexutil.Uint64(t.UnixNano() / int64(time.Millisecond))
+	return &ms
+}
+
+// block retrieves the arrival entry of a block, creating it if unknown. The
+// lock must be held by the caller.
+func (t *arrivalTracker) block(hash common.Hash, number uint64) *blockArrival {
+	if arrival, ok := t.blocks.Get(hash); ok {
+		return arrival.(*blockArrival)
+	}
+	arrival := &blockArrival{Hash: hash, Number: hexutil.Uint64(number)}
+	t.blocks.Add(hash, arrival)
+	return arrival
+}
+
+// tx retrieves the arrival entry of a transaction, creating it if unknown. The
+// lock must be held by the caller.
+func (t *arrivalTracker) tx(hash common.Hash) *txArrival {
+	if arrival, ok := t.txs.Get(hash); ok {
+		return arrival.(*txArrival)
+	}
+	arrival := &txArrival{Hash: hash}
+	t.txs.Add(hash, arrival)
+	return arrival
+}
+
+// blockAnnounced records a block announcement from a remote peer.
+func (t *arrivalTracker) blockAnnounced(peer string, hash common.Hash, number uint64, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	arrival := t.block(hash, number)
+	if arrival.Announced == nil {
+		arrival.Announced, arrival.AnnouncedBy = arrivalTime(now), peer
+	}
+	arrival.Announcements++
+}
+
+// blockReceived records a full block propagated by a remote peer.
+func (t *arrivalTracker) blockReceived(peer string, hash common.Hash, number uint64, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	arrival := t.block(hash, number)
+	if arrival.Received == nil {
+		arrival.Received, arrival.ReceivedBy = arrivalTime(now), peer
+	}
+	arrival.Announcements++
+}
+
+// txsAnnounced records a batch of transaction announcements from a remote peer.
+func (t *arrivalTracker) txsAnnounced(peer string, hashes []common.Hash, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, hash := range hashes {
+		if arrival := t.tx(hash); arrival.Announced == nil {
+			arrival.Announced, arrival.AnnouncedBy = arrivalTime(now), peer
+		}
+	}
+}
+
+// txsReceived records a batch of full transactions from a remote peer.
+func (t *arrivalTracker) txsReceived(peer string, hashes []common.Hash, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, hash := range hashes {
+		if arrival := t.tx(hash); arrival.Received == nil {
+			arrival.Received, arrival.ReceivedBy = arrivalTime(now), peer
+		}
+	}
+}
+
+// blockArrival returns a copy of the arrival entry of a block, or nil if the
+// block was never propagated to the local node or was already evicted.
+func (t *arrivalTracker) blockArrival(hash common.Hash) *blockArrival {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	arrival, ok := t.blocks.Peek(hash)
+	if !ok {
+		return nil
+	}
+	cpy := *arrival.(*blockArrival)
+	return &cpy
+}
+
+// txArrival returns a copy of the arrival entry of a transaction, or nil if the
+// transaction was never propagated to the local node or was already evicted.
+func (t *arrivalTracker) txArrival(hash common.Hash) *txArrival {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	arrival, ok := t.txs.Peek(hash)
+	if !ok {
+		return nil
+	}
+	cpy := *arrival.(*txArrival)
+	return &cpy
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+)
+
+// Tests that only the first announcement and delivery of a block or transaction
+// are recorded as its arrival.
+func TestArrivalTracker(t *testing.T) {
+	var (
+		tracker = newArrivalTracker()
+		base    = time.Unix(1600000000, 0)
+		block   = common.Hash{0x01}
+		tx      = common.Hash{0x02}
+	)
+	if arrival := tracker.blockArrival(block); arrival != nil {
+		t.Fatalf("unknown block arrival returned: %+v", arrival)
+	}
+	tracker.blockAnnounced("a", block, 10, base)
+	tracker.blockAnnounced("b", block, 10, base.Add(time.Second))
+	tracker.blockReceived("c", block, 10, base.Add(2*time.Second))
+	tracker.blockReceived("d", block, 10, base.Add(3*time.Second))
+
+	arrival := tracker.blockArrival(block)
+	if arrival == nil {
+		t.Fatalf("block arrival missing")
+	}
+	if arrival.Number != 10 {
+		t.Errorf("block number mismatch: have %d, want %d", arrival.Number, 10)
+	}
+	if have, want := uint64(*arrival.Announced), uint64(1600000000000); have != want || arrival.AnnouncedBy != "a" {
+		t.Errorf("block announcement mismatch: have %d from %s, want %d from %s", have, arrival.AnnouncedBy, want, "a")
+	}
+	if have, want := uint64(*arrival.Received), uint64(1600000002000); have != want || arrival.ReceivedBy != "c" {
+		t.Errorf("block delivery mismatch: have %d from %s, want %d from %s", have, arrival.ReceivedBy, want, "c")
+	}
+	if arrival.Announcements != 4 {
+		t.Errorf("announcement count mismatch: have %d, want %d", arrival.Announcements, 4)
+	}
+	// Transactions delivered without an announcement should only track the delivery
+	tracker.txsReceived("a", []common.Hash{tx}, base)
+	tracker.txsAnnounced("b", []common.Hash{tx}, base.Add(time.Second))
+	tracker.txsReceived("c", []common.Hash{tx}, base.Add(2*time.Second))
+
+	txArrival := tracker.txArrival(tx)
+	if txArrival == nil {
+		t.Fatalf("transaction arrival missing")
+	}
+	if have, want := uint64(*txArrival.Received), uint64(1600000000000); have != want || txArrival.ReceivedBy != "a" {
+		t.Errorf("transaction delivery mismatch: have %d from %s, want %d from %s", have, txArrival.ReceivedBy, want, "a")
+	}
+	if have, want := uint64(*txArrival.Announced), uint64(1600000001000); have != want || txArrival.AnnouncedBy != "b" {
+		t.Errorf("transaction announcement mismatch: have %d from %s, want %d from %s", have, txArrival.AnnouncedBy, want, "b")
+	}
+}
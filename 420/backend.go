@@ -28,13 +28,16 @@ import (
 
 	"github.com/420integrated/go-420coin/accounts"
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/common/hexutil"
 	"github.com/420integrated/go-420coin/consensus"
 	"github.com/420integrated/go-420coin/consensus/clique"
 	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/core/bloombits"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state/pruner"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/420/downloader"
@@ -77,11 +80,17 @@ type Fourtwentycoin struct {
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
 	closeBloomHandler chan struct{}
 
+	traceStore *traceStore      // Call trace store, nil if pre-computing traces is disabled
+	chainTips  *chainTipTracker // Competing chain tip tracker
+	txSched    *txScheduler     // Scheduler holding local transactions back until their target
+	budget     *cacheBudget     // Memory budget apportioned across the caches
+
 	APIBackend *FourtwentyAPIBackend
 
 	miner              *miner.Miner
 	smokePrice         *big.Int
 	fourtwentycoinbase common.Address
+	coinbaseList       []common.Address
 
 	networkID     uint64
 	netRPCService *fourtwentyapi.PublicNetAPI
@@ -117,16 +126,24 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
 
 	// Assemble the 420coin object
+	rawdb.SetValueCompression(config.DatabaseCompress)
 	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "420/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
-	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
+	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlockWithOverride(chainDb, config.Genesis, config.RewardEraOverrides())
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
+		log.Error("Failed to recover state", "error", err)
+	}
+	if chainConfig.ChainID != nil && (!chainConfig.ChainID.IsUint64() || chainConfig.ChainID.Uint64() != config.NetworkId) {
+		log.Warn("Network id differs from the chain id", "networkid", config.NetworkId, "chainid", chainConfig.ChainID, "hint", "set --networkid to the chain id unless intentionally running a distinct network")
+	}
+
 	fourtwenty := &Fourtwentycoin{
 		config:              config,
 		chainDb:             chainDb,
@@ -137,6 +154,7 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 		networkID:           config.NetworkId,
 		smokePrice:          config.Miner.SmokePrice,
 		fourtwentycoinbase:  config.Miner.Fourtwentycoinbase,
+		coinbaseList:        config.Miner.CoinbaseList,
 		bloomRequests:       make(chan chan *bloombits.Retrieval),
 		bloomIndexer:        NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 		p2pServer:           stack.Server(),
@@ -179,6 +197,7 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 	if err != nil {
 		return nil, err
 	}
+	fourtwenty.blockchain.SetMaxReorgDepth(config.MaxReorgDepth)
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -187,10 +206,25 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 	}
 	fourtwenty.bloomIndexer.Start(fourtwenty.blockchain)
 
+	if config.TraceStore {
+		fourtwenty.traceStore = newTraceStore(fourtwenty, config.TraceStoreRetention)
+	}
+	fourtwenty.chainTips = newChainTipTracker(fourtwenty.blockchain, chainDb, config.ChainTipAlertDepth)
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPoolCache > 0 {
+		config.TxPool.GlobalSlots, config.TxPool.GlobalQueue = txPoolLimits(config.TxPool, config.TxPoolCache)
+		log.Info("Allocated transaction pool memory", "allowance", common.StorageSize(config.TxPoolCache)*1024*1024, "slots", config.TxPool.GlobalSlots, "queue", config.TxPool.GlobalQueue)
+	}
+	if config.ChainCache > 0 {
+		cache.SetBudget(config.ChainCache * 1024 * 1024)
+		log.Info("Allocated chain data cache memory", "allowance", common.StorageSize(config.ChainCache)*1024*1024)
+	}
+	fourtwenty.budget = newCacheBudget(config)
 	fourtwenty.txPool = core.NewTxPool(config.TxPool, chainConfig, fourtwenty.blockchain)
+	fourtwenty.txSched = newTxScheduler(fourtwenty.blockchain, fourtwenty.txPool.AddLocal)
 
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
@@ -198,23 +232,40 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 	if checkpoint == nil {
 		checkpoint = params.TrustedCheckpoints[genesisHash]
 	}
+	// Set up federated checkpointing if the chain is configured for it
+	var (
+		tracker  *federation.Tracker
+		signVote func([]byte) ([]byte, error)
+	)
+	if chainConfig.Federation != nil {
+		if tracker, err = federation.NewTracker(chainConfig.Federation); err != nil {
+			return nil, err
+		}
+		if config.FederationSigner != (common.Address{}) {
+			if signVote, err = federationSigner(stack.AccountManager(), tracker, config.FederationSigner); err != nil {
+				return nil, err
+			}
+		}
+	}
 	if fourtwenty.handler, err = newHandler(&handlerConfig{
-		Database:   chainDb,
-		Chain:      fourtwenty.blockchain,
-		TxPool:     fourtwenty.txPool,
-		Network:    config.NetworkId,
-		Sync:       config.SyncMode,
-		BloomCache: uint64(cacheLimit),
-		EventMux:   fourtwenty.eventMux,
-		Checkpoint: checkpoint,
-		Whitelist:  config.Whitelist,
+		Database:       chainDb,
+		Chain:          fourtwenty.blockchain,
+		TxPool:         fourtwenty.txPool,
+		Network:        config.NetworkId,
+		Sync:           config.SyncMode,
+		BloomCache:     uint64(cacheLimit),
+		EventMux:       fourtwenty.eventMux,
+		Checkpoint:     checkpoint,
+		Whitelist:      config.Whitelist,
+		Federation:     tracker,
+		FederationSign: signVote,
 	}); err != nil {
 		return nil, err
 	}
 	fourtwenty.miner = miner.New(fourtwenty, &config.Miner, chainConfig, fourtwenty.EventMux(), fourtwenty.engine, fourtwenty.isLocalBlock)
 	fourtwenty.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
-	fourtwenty.APIBackend = &FourtwentyAPIBackend{stack.Config().ExtRPCEnabled(), fourtwenty, nil}
+	fourtwenty.APIBackend = &FourtwentyAPIBackend{extRPCEnabled: stack.Config().ExtRPCEnabled(), fourtwenty: fourtwenty}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
 		gpoParams.Default = config.Miner.SmokePrice
@@ -231,7 +282,7 @@ func New(stack *node.Node, config *Config) (*Fourtwentycoin, error) {
 	}
 
 	// Start the RPC service
-	fourtwenty.netRPCService = fourtwentyapi.NewPublicNetAPI(fourtwenty.p2pServer)
+	fourtwenty.netRPCService = fourtwentyapi.NewPublicNetAPI(fourtwenty.p2pServer, config.NetworkId)
 
 	// Register the backend on the node
 	stack.RegisterAPIs(fourtwenty.APIs())
@@ -281,6 +332,9 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 	case ethash.ModeFake:
 		log.Warn("Ethash used in fake mode")
 		return ethash.NewFaker()
+	case ethash.ModeFullFake:
+		log.Warn("Ethash used in full fake mode")
+		return ethash.NewFullFaker()
 	case ethash.ModeTest:
 		log.Warn("Ethash used in test mode")
 		return ethash.NewTester(nil, noverify)
@@ -293,12 +347,18 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 			CachesInMem:      config.CachesInMem,
 			CachesOnDisk:     config.CachesOnDisk,
 			CachesLockMmap:   config.CachesLockMmap,
+			CacheURL:         config.CacheURL,
 			DatasetDir:       config.DatasetDir,
 			DatasetsInMem:    config.DatasetsInMem,
 			DatasetsOnDisk:   config.DatasetsOnDisk,
 			DatasetsLockMmap: config.DatasetsLockMmap,
 		}, notify, noverify)
 		engine.SetThreads(-1) // Disable CPU mining
+
+		// Share the verification caches with other nodes if requested
+		if config.CacheServe {
+			stack.RegisterHandler("Ethash caches", "/ethash/caches/", engine.CacheHandler())
+		}
 		return engine
 	}
 }
@@ -311,6 +371,20 @@ func (s *Fourtwentycoin) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the Ethereum naming aliases if requested
+	if s.config.RPCEthCompat {
+		apis = append(apis, fourtwentyapi.EthCompatAPIs()...)
+	}
+
+	// Append the Ganache compatible chain controls on instant-seal developer chains
+	if engine, ok := s.engine.(*clique.Clique); ok && s.blockchain.Config().Clique.Period == 0 {
+		apis = append(apis, rpc.API{
+			Namespace: "evm",
+			Version:   "1.0",
+			Service:   NewPrivateDevAPI(s, engine),
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -333,6 +407,11 @@ func (s *Fourtwentycoin) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateMinerAPI(s),
 			Public:    false,
+		}, {
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPrivateTxPoolAPI(s),
+			Public:    false,
 		}, {
 			Namespace: "fourtwenty",
 			Version:   "1.0",
@@ -400,11 +479,17 @@ func (s *Fourtwentycoin) isLocalBlock(block *types.Block) bool {
 	}
 	// Check if the given address is fourtwentycoinbase.
 	s.lock.RLock()
-	fourtwentycoinbase := s.fourtwentycoinbase
+	fourtwentycoinbase, coinbaseList := s.fourtwentycoinbase, s.coinbaseList
 	s.lock.RUnlock()
 	if author == fourtwentycoinbase {
 		return true
 	}
+	// Check if the given address is one of the rotated coinbases.
+	for _, coinbase := range coinbaseList {
+		if coinbase == author {
+			return true
+		}
+	}
 	// Check if the given address is specified by `txpool.local`
 	// CLI flag.
 	for _, account := range s.config.TxPool.Locals {
@@ -450,6 +535,16 @@ func (s *Fourtwentycoin) SetFourtwentycoinbase(fourtwentycoinbase common.Address
 	s.miner.SetFourtwentycoinbase(fourtwentycoinbase)
 }
 
+// SetCoinbaseList sets the mining reward addresses to rotate among, each one
+// receiving interval consecutive blocks. An empty list disables the rotation.
+func (s *Fourtwentycoin) SetCoinbaseList(coinbases []common.Address, interval uint64) {
+	s.lock.Lock()
+	s.coinbaseList = append([]common.Address(nil), coinbases...)
+	s.lock.Unlock()
+
+	s.miner.SetCoinbaseList(coinbases, interval)
+}
+
 // StartMining starts the miner with the given number of CPU threads. If mining
 // is already running, this method adjust the number of threads allowed to use
 // and updates the minimum price required by the transaction pool.
@@ -543,6 +638,13 @@ func (s *Fourtwentycoin) Start() error {
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers(params.BloomBitsBlocks)
 
+	// Start pre-computing call traces if requested
+	if s.traceStore != nil {
+		s.traceStore.start()
+	}
+	s.chainTips.start()
+	s.txSched.start()
+
 	// Figure out a max peers count based on the server limits
 	maxPeers := s.p2pServer.MaxPeers
 	if s.config.LightServ > 0 {
@@ -565,6 +667,11 @@ func (s *Fourtwentycoin) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.traceStore != nil {
+		s.traceStore.stop()
+	}
+	s.chainTips.stop()
+	s.txSched.stop()
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.blockchain.Stop()
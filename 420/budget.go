@@ -0,0 +1,145 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/420integrated/go-420coin/common/cache"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// errEmptyBudget is returned if the memory budget of the node is resized to, or
+// from, zero, since there are no shares left to apportion it by.
+var errEmptyBudget = errors.New("empty cache budget")
+
+// CacheAllowance is the apportionment of the memory budget of the node across its
+// caches and the transaction pool, all sizes in megabytes.
+type CacheAllowance struct {
+	Total     int `json:"total"`
+	Database  int `json:"database"`
+	TrieClean int `json:"trieClean"`
+	TrieDirty int `json:"trieDirty"`
+	Snapshot  int `json:"snapshot"`
+	TxPool    int `json:"txpool"`
+	Chain     int `json:"chain"`
+}
+
+// scale apportions a new total across the subsystems, keeping their relative
+// shares. The rounding leftover is assigned to the database cache.
+func (a CacheAllowance) scale(total int) CacheAllowance {
+	scaled := CacheAllowance{
+		Total:     total,
+		TrieClean: a.TrieClean * total / a.Total,
+		TrieDirty: a.TrieDirty * total / a.Total,
+		Snapshot:  a.Snapshot * total / a.Total,
+		TxPool:    a.TxPool * total / a.Total,
+		Chain:     a.Chain * total / a.Total,
+	}
+	scaled.Database = total - scaled.TrieClean - scaled.TrieDirty - scaled.Snapshot - scaled.TxPool - scaled.Chain
+	return scaled
+}
+
+// cacheBudget tracks the memory budget of the node, as apportioned by the --cache
+// percentages at startup.
+type cacheBudget struct {
+	allowance CacheAllowance
+	lock      sync.Mutex
+}
+
+// newCacheBudget creates a memory budget from the cache sizes of the config.
+func newCacheBudget(config *Config) *cacheBudget {
+	allowance := CacheAllowance{
+		Database:  config.DatabaseCache,
+		TrieClean: config.TrieCleanCache,
+		TrieDirty: config.TrieDirtyCache,
+		Snapshot:  config.SnapshotCache,
+		TxPool:    config.TxPoolCache,
+		Chain:     config.ChainCache,
+	}
+	allowance.Total = allowance.Database + allowance.TrieClean + allowance.TrieDirty + allowance.Snapshot + allowance.TxPool + allowance.Chain
+	return &cacheBudget{allowance: allowance}
+}
+
+// current returns the present apportionment of the budget.
+func (b *cacheBudget) current() CacheAllowance {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.allowance
+}
+
+// resize rescales the budget to a new total, returning the new apportionment.
+func (b *cacheBudget) resize(total int) (CacheAllowance, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if total <= 0 || b.allowance.Total <= 0 {
+		return CacheAllowance{}, errEmptyBudget
+	}
+	b.allowance = b.allowance.scale(total)
+	return b.allowance, nil
+}
+
+// txPoolLimits converts a memory allowance of the transaction pool into global
+// executable and non-executable slot limits, keeping the queue to pending ratio
+// of the configured limits.
+func txPoolLimits(config core.TxPoolConfig, allowance int) (uint64, uint64) {
+	slots := core.TxPoolSlots(allowance)
+	if slots == 0 {
+		slots = 1
+	}
+	if config.GlobalSlots == 0 {
+		config = core.DefaultTxPoolConfig
+	}
+	queue := slots * config.GlobalQueue / config.GlobalSlots
+	if queue == 0 {
+		queue = 1
+	}
+	return slots, queue
+}
+
+// CacheBudget returns the present apportionment of the memory budget of the node.
+func (api *PrivateDebugAPI) CacheBudget() CacheAllowance {
+	return api.fourtwenty.budget.current()
+}
+
+// SetCacheBudget resizes the memory budget of the node to the given number of
+// megabytes, rescaling the share of every subsystem proportionally. The dirty
+// trie cache, the transaction pool and the chain data caches are resized in
+// place, whereas the database, clean trie and snapshot caches are preallocated
+// and only pick up their new sizes after a restart.
+func (api *PrivateDebugAPI) SetCacheBudget(total int) (CacheAllowance, error) {
+	allowance, err := api.fourtwenty.budget.resize(total)
+	if err != nil {
+		return CacheAllowance{}, err
+	}
+	api.fourtwenty.blockchain.SetTrieDirtyLimit(allowance.TrieDirty)
+	if allowance.TxPool > 0 {
+		limits := api.fourtwenty.txPool.Config()
+		limits.GlobalSlots, limits.GlobalQueue = txPoolLimits(api.fourtwenty.config.TxPool, allowance.TxPool)
+		api.fourtwenty.txPool.SetLimits(limits)
+	}
+	if allowance.Chain > 0 {
+		cache.SetBudget(allowance.Chain * 1024 * 1024)
+	}
+	log.Info("Updated cache memory budget", "total", allowance.Total, "dirty", allowance.TrieDirty, "txpool", allowance.TxPool, "chain", allowance.Chain)
+	log.Warn("Database, clean trie and snapshot caches resized on restart", "database", allowance.Database, "clean", allowance.TrieClean, "snapshot", allowance.Snapshot)
+	return allowance, nil
+}
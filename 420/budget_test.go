@@ -0,0 +1,65 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"testing"
+
+	"github.com/420integrated/go-420coin/core"
+)
+
+// Tests that resizing the memory budget keeps the relative shares of the caches,
+// assigning the rounding leftover to the database cache.
+func TestCacheBudgetResize(t *testing.T) {
+	budget := newCacheBudget(&Config{
+		DatabaseCache:  448,
+		TrieCleanCache: 154,
+		TrieDirtyCache: 256,
+		SnapshotCache:  102,
+		ChainCache:     64,
+	})
+	if have := budget.current().Total; have != 1024 {
+		t.Fatalf("initial total mismatch: have %d, want 1024", have)
+	}
+	allowance, err := budget.resize(2048)
+	if err != nil {
+		t.Fatalf("failed to resize budget: %v", err)
+	}
+	want := CacheAllowance{Total: 2048, Database: 896, TrieClean: 308, TrieDirty: 512, Snapshot: 204, Chain: 128}
+	if allowance != want {
+		t.Fatalf("allowance mismatch: have %+v, want %+v", allowance, want)
+	}
+	allowance, _ = budget.resize(1000)
+	if sum := allowance.Database + allowance.TrieClean + allowance.TrieDirty + allowance.Snapshot + allowance.TxPool + allowance.Chain; sum != 1000 {
+		t.Fatalf("apportioned sum mismatch: have %d, want 1000", sum)
+	}
+	if _, err := budget.resize(0); err != errEmptyBudget {
+		t.Fatalf("empty budget error mismatch: have %v, want %v", err, errEmptyBudget)
+	}
+}
+
+// Tests that the transaction pool allowance is converted into slot limits keeping
+// the configured queue ratio.
+func TestTxPoolLimits(t *testing.T) {
+	config := core.DefaultTxPoolConfig
+	config.GlobalSlots, config.GlobalQueue = 4096, 1024
+
+	slots, queue := txPoolLimits(config, 64)
+	if slots != 2048 || queue != 512 {
+		t.Fatalf("limits mismatch: have %d/%d, want 2048/512", slots, queue)
+	}
+}
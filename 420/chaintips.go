@@ -0,0 +1,220 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/event"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/metrics"
+)
+
+// maxChainTipAge is the number of blocks below the canonical head after which
+// competing chain tips are forgotten.
+const maxChainTipAge = 1024
+
+var chainTipAlertMeter = metrics.NewRegisteredMeter("chain/tips/alerts", nil)
+
+// chainTip is a block without known children, along with its relation to the
+// canonical chain.
+type chainTip struct {
+	Number          uint64      `json:"number"`
+	Hash            common.Hash `json:"hash"`
+	TotalDifficulty *big.Int    `json:"totalDifficulty"`
+	Ancestor        uint64      `json:"ancestor"`  // Number of the common ancestor with the canonical chain
+	Depth           uint64      `json:"depth"`     // Number of canonical blocks above the common ancestor
+	Canonical       bool        `json:"canonical"` // Whether the tip is the canonical head
+}
+
+// chainTipTracker follows the side blocks imported into the chain to keep track
+// of all competing chain tips, and raises alerts for the ones threatening the
+// recent canonical chain.
+type chainTipTracker struct {
+	chain      *core.BlockChain
+	db         fourtwentydb.Reader
+	alertDepth uint64 // Maximum distance from the head to alert about heavier tips (0 = disabled)
+
+	tips map[common.Hash]*types.Header // Non-canonical chain tips
+	lock sync.RWMutex
+
+	alertFeed event.Feed
+	scope     event.SubscriptionScope
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newChainTipTracker creates a chain tip tracker on top of the given chain.
+func newChainTipTracker(chain *core.BlockChain, db fourtwentydb.Reader, alertDepth uint64) *chainTipTracker {
+	return &chainTipTracker{
+		chain:      chain,
+		db:         db,
+		alertDepth: alertDepth,
+		tips:       make(map[common.Hash]*types.Header),
+		quit:       make(chan struct{}),
+	}
+}
+
+// start begins tracking side blocks in the background.
+func (t *chainTipTracker) start() {
+	var (
+		sideCh  = make(chan core.ChainSideEvent, 16)
+		sideSub = t.chain.SubscribeChainSideEvent(sideCh)
+		headCh  = make(chan core.ChainHeadEvent, 16)
+		headSub = t.chain.SubscribeChainHeadEvent(headCh)
+	)
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		defer sideSub.Unsubscribe()
+		defer headSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-sideCh:
+				t.addSide(ev.Block.Header())
+			case ev := <-headCh:
+				t.prune(ev.Block.Header())
+			case <-sideSub.Err():
+				return
+			case <-headSub.Err():
+				return
+			case <-t.quit:
+				return
+			}
+		}
+	}()
+}
+
+// stop terminates the tracking goroutine and all alert subscriptions.
+func (t *chainTipTracker) stop() {
+	close(t.quit)
+	t.wg.Wait()
+	t.scope.Close()
+}
+
+// subscribeAlerts subscribes to the competing chain tips which are heavier than
+// the canonical chain within the alert depth.
+func (t *chainTipTracker) subscribeAlerts(ch chan<- chainTip) event.Subscription {
+	return t.scope.Track(t.alertFeed.Subscribe(ch))
+}
+
+// addSide records a side block as a chain tip in place of its parent, and raises
+// an alert if it threatens the canonical chain.
+func (t *chainTipTracker) addSide(header *types.Header) {
+	t.lock.Lock()
+	delete(t.tips, header.ParentHash)
+	t.tips[header.Hash()] = header
+	t.lock.Unlock()
+
+	if tip := t.alert(header); tip != nil {
+		log.Warn("Heavier competing chain tip detected", "number", tip.Number, "hash", tip.Hash,
+			"td", tip.TotalDifficulty, "ancestor", tip.Ancestor, "depth", tip.Depth)
+		chainTipAlertMeter.Mark(1)
+		t.alertFeed.Send(*tip)
+	}
+}
+
+// prune drops the tips which became canonical or are too old to track.
+func (t *chainTipTracker) prune(head *types.Header) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for hash, header := range t.tips {
+		number := header.Number.Uint64()
+		if number+maxChainTipAge < head.Number.Uint64() || t.chain.GetCanonicalHash(number) == hash {
+			delete(t.tips, hash)
+		}
+	}
+}
+
+// alert returns the description of a side block if it is within the alert depth
+// of the canonical head and has a higher total difficulty than the canonical
+// block at the same height, or nil otherwise.
+func (t *chainTipTracker) alert(header *types.Header) *chainTip {
+	if t.alertDepth == 0 {
+		return nil
+	}
+	head := t.chain.CurrentBlock().Header()
+	number := header.Number.Uint64()
+	if number+t.alertDepth < head.Number.Uint64() {
+		return nil
+	}
+	tip := t.describe(head, header)
+	if tip == nil || tip.Canonical {
+		return nil
+	}
+	canon := head
+	if number < head.Number.Uint64() {
+		canon = t.chain.GetHeaderByNumber(number)
+	}
+	if canon == nil {
+		return nil
+	}
+	if td := t.chain.GetTd(canon.Hash(), canon.Number.Uint64()); td == nil || tip.TotalDifficulty.Cmp(td) <= 0 {
+		return nil
+	}
+	return tip
+}
+
+// describe computes the relation of a block to the canonical chain ending in
+// the given head, or nil if the block is unknown.
+func (t *chainTipTracker) describe(head, header *types.Header) *chainTip {
+	td := t.chain.GetTd(header.Hash(), header.Number.Uint64())
+	if td == nil {
+		return nil
+	}
+	ancestor := rawdb.FindCommonAncestor(t.db, head, header)
+	if ancestor == nil {
+		return nil
+	}
+	return &chainTip{
+		Number:          header.Number.Uint64(),
+		Hash:            header.Hash(),
+		TotalDifficulty: td,
+		Ancestor:        ancestor.Number.Uint64(),
+		Depth:           head.Number.Uint64() - ancestor.Number.Uint64(),
+		Canonical:       header.Hash() == head.Hash(),
+	}
+}
+
+// chainTips returns the canonical head and all tracked competing chain tips,
+// ordered by descending total difficulty.
+func (t *chainTipTracker) chainTips() []*chainTip {
+	head := t.chain.CurrentBlock().Header()
+	tips := []*chainTip{t.describe(head, head)}
+
+	t.lock.RLock()
+	for _, header := range t.tips {
+		if tip := t.describe(head, header); tip != nil && tip.Ancestor < tip.Number {
+			tips = append(tips, tip)
+		}
+	}
+	t.lock.RUnlock()
+
+	sort.SliceStable(tips, func(i, j int) bool {
+		return tips[i].TotalDifficulty.Cmp(tips[j].TotalDifficulty) > 0
+	})
+	return tips
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that competing chain tips are tracked with their common ancestor, and
+// that side blocks heavier than the canonical chain at their height alert.
+func TestChainTipTracker(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = new(core.Genesis).MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	canon, _ := core.GenerateChain(params.TestChainConfig, genesis, engine, db, 5, nil)
+	if _, err := chain.InsertChain(canon); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	// Fork off a short chain with faster, thus harder blocks, which stays lighter
+	// than the canonical head
+	fork, _ := core.GenerateChain(params.TestChainConfig, canon[1], engine, db, 2, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{0x01})
+		b.OffsetTime(-9)
+	})
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != canon[len(canon)-1].Hash() {
+		t.Fatalf("fork became canonical")
+	}
+	tracker := newChainTipTracker(chain, db, 12)
+	alerts := make(chan chainTip, len(fork))
+	sub := tracker.subscribeAlerts(alerts)
+	defer sub.Unsubscribe()
+
+	for _, block := range fork {
+		tracker.addSide(block.Header())
+	}
+	tips := tracker.chainTips()
+	if len(tips) != 2 {
+		t.Fatalf("tip count mismatch: have %d, want 2", len(tips))
+	}
+	if head := tips[0]; !head.Canonical || head.Hash != canon[4].Hash() || head.Depth != 0 {
+		t.Errorf("canonical tip mismatch: have %+v", head)
+	}
+	if tip := tips[1]; tip.Canonical || tip.Hash != fork[1].Hash() || tip.Ancestor != 2 || tip.Depth != 3 {
+		t.Errorf("competing tip mismatch: have %+v", tip)
+	}
+	if len(alerts) != len(fork) {
+		t.Fatalf("alert count mismatch: have %d, want %d", len(alerts), len(fork))
+	}
+	for _, block := range fork {
+		if alert := <-alerts; alert.Hash != block.Hash() {
+			t.Errorf("alert mismatch: have %x, want %x", alert.Hash, block.Hash())
+		}
+	}
+	// Pruning against the current head retains recent non-canonical tips
+	tracker.prune(chain.CurrentBlock().Header())
+	if tips := tracker.chainTips(); len(tips) != 2 {
+		t.Errorf("tip count mismatch after prune: have %d, want 2", len(tips))
+	}
+}
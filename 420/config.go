@@ -79,6 +79,8 @@ var DefaultConfig = Config{
 	RPCSmokeCap: 25000000,
 	GPO:         DefaultFullGPOConfig,
 	RPCTxFeeCap: 1, // 1 420coin
+
+	ChainTipAlertDepth: 12,
 }
 
 func init() {
@@ -122,16 +124,30 @@ type Config struct {
 	NoPrefetch bool // If to disable prefetching and only load state on demand
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	MaxReorgDepth uint64 `toml:",omitempty"` // The maximum number of blocks to reorg without operator approval (0 = unlimited).
+
+	// Call trace store options
+	TraceStore          bool   `toml:",omitempty"` // Whether to pre-compute and persist call traces of imported blocks
+	TraceStoreRetention uint64 `toml:",omitempty"` // The number of recent blocks to retain call traces for (0 = all)
+
+	// ChainTipAlertDepth is the distance from the canonical head within which
+	// heavier competing chain tips raise alerts (0 = disabled).
+	ChainTipAlertDepth uint64 `toml:",omitempty"`
 
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
 
+	// FederationSigner is the unlocked account voting on federated checkpoints,
+	// if the chain config enables federation and the account is a signer.
+	FederationSigner common.Address `toml:",omitempty"`
+
 	// Light client options
-	LightServ    int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
-	LightIngress int  `toml:",omitempty"` // Incoming bandwidth limit for light servers
-	LightEgress  int  `toml:",omitempty"` // Outgoing bandwidth limit for light servers
-	LightPeers   int  `toml:",omitempty"` // Maximum number of LES client peers
-	LightNoPrune bool `toml:",omitempty"` // If to disable light chain pruning
+	LightServ      int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
+	LightIngress   int  `toml:",omitempty"` // Incoming bandwidth limit for light servers
+	LightEgress    int  `toml:",omitempty"` // Outgoing bandwidth limit for light servers
+	LightPeers     int  `toml:",omitempty"` // Maximum number of LES client peers
+	LightNoPrune   bool `toml:",omitempty"` // If to disable light chain pruning
+	LightRemotePoW bool `toml:",omitempty"` // If to verify header PoW with dataset items served by LES servers
 
 	// Ultra Light client options
 	UltraLightServers      []string `toml:",omitempty"` // List of trusted ultra light servers
@@ -143,6 +159,7 @@ type Config struct {
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
 	DatabaseFreezer    string
+	DatabaseCompress   bool `toml:",omitempty"` // Whether to snappy compress the block bodies and receipts written to the key-value store
 
 	TrieCleanCache          int
 	TrieCleanCacheJournal   string        `toml:",omitempty"` // Disk journal directory for trie cache to survive node restarts
@@ -150,6 +167,8 @@ type Config struct {
 	TrieDirtyCache          int
 	TrieTimeout             time.Duration
 	SnapshotCache           int
+	TxPoolCache             int `toml:",omitempty"` // Memory allowance (MB) sizing the transaction pool, 0 = use the slot limits
+	ChainCache              int `toml:",omitempty"` // Memory allowance (MB) shared by the chain data caches, 0 = unlimited
 	Preimages               bool
 
 	// Mining options
@@ -183,9 +202,47 @@ type Config struct {
 	// send-transction variants. The unit is 420coin.
 	RPCTxFeeCap float64 `toml:",omitempty"`
 
+	// RPCStructuredErrors enables numeric error codes and structured data
+	// fields on the errors returned by the RPC APIs.
+	RPCStructuredErrors bool `toml:",omitempty"`
+
+	// RPCRecipientCheck rejects value transfers submitted through the RPC APIs
+	// to precompiled contracts or to recently self-destructed contracts.
+	RPCRecipientCheck bool `toml:",omitempty"`
+
+	// RPCEthCompat additionally serves the RPC APIs under the Ethereum method
+	// and field names, for tooling written against Ethereum nodes.
+	RPCEthCompat bool `toml:",omitempty"`
+
+	// RPCTracerSteps and RPCTracerMemory limit the number of steps a JavaScript
+	// tracer may trace and the size in bytes of its state (0 = no limit).
+	RPCTracerSteps  uint64 `toml:",omitempty"`
+	RPCTracerMemory int    `toml:",omitempty"`
+
+	// RPCNoCustomTracers restricts the tracing APIs to the built-in tracers,
+	// rejecting tracer code submitted by the caller.
+	RPCNoCustomTracers bool `toml:",omitempty"`
+
 	// Checkpoint is a hardcoded checkpoint which can be nil.
 	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
 
 	// CheckpointOracle is the configuration for checkpoint oracle.
 	CheckpointOracle *params.CheckpointOracleConfig `toml:",omitempty"`
+
+	// Indica and Sativa reward era start block overrides, for private networks
+	OverrideIndica *big.Int `toml:",omitempty"`
+	OverrideSativa *big.Int `toml:",omitempty"`
+}
+
+// RewardEraOverrides returns the reward era start blocks overriding the ones of
+// the chain configuration, keyed by era name.
+func (c *Config) RewardEraOverrides() map[string]*big.Int {
+	overrides := make(map[string]*big.Int)
+	if c.OverrideIndica != nil {
+		overrides["indica"] = c.OverrideIndica
+	}
+	if c.OverrideSativa != nil {
+		overrides["sativa"] = c.OverrideSativa
+	}
+	return overrides
 }
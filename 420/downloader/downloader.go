@@ -69,6 +69,9 @@ var (
 	fsHeaderForceVerify    = 24              // Number of headers to verify before and after the pivot to accept it
 	fsHeaderContCheck      = 3 * time.Second // Time interval to check for header continuations during state download
 	fsMinFullBlocks        = 64              // Number of blocks to retrieve fully even in fast sync
+
+	skeletonSealFrequency = 16 // Verification frequency of the seals of skeleton fill batches
+	maxMasterRotations    = 3  // Maximum number of times to replace a stalling master peer in a sync cycle
 )
 
 var (
@@ -148,6 +151,8 @@ type Downloader struct {
 	trackStateReq  chan *stateReq
 	stateCh        chan dataPack // Channel receiving inbound node state data
 
+	sealVerifier SealVerifier // Engine verifying the seals of skeleton fill batches, if supported
+
 	// Cancellation and termination
 	cancelPeer string         // Identifier of the peer currently being used as the master (cancel on drop)
 	cancelCh   chan struct{}  // Channel to cancel mid-flight syncs
@@ -185,6 +190,12 @@ type LightChain interface {
 	SetHead(uint64) error
 }
 
+// SealVerifier is implemented by consensus engines able to verify the seals of a
+// batch of headers concurrently, without their ancestors being known.
+type SealVerifier interface {
+	VerifySeals(headers []*types.Header) (chan<- struct{}, <-chan error)
+}
+
 // BlockChain encapsulates functions required to sync a (full or fast) blockchain.
 type BlockChain interface {
 	LightChain
@@ -251,6 +262,13 @@ func New(checkpoint uint64, stateDb fourtwentydb.Database, stateBloom *trie.Sync
 	return dl
 }
 
+// SetSealVerifier sets the engine used to verify the seals of skeleton fill
+// batches as they are delivered, so peers feeding forged headers are dropped
+// before their batches are scheduled for import.
+func (d *Downloader) SetSealVerifier(verifier SealVerifier) {
+	d.sealVerifier = verifier
+}
+
 // Progress retrieves the synchronisation boundaries, specifically the origin
 // block where synchronisation started at (may have failed/suspended); the block
 // or header sync is currently at; and the latest known block which the sync targets.
@@ -994,7 +1012,11 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 	<-timeout.C                 // timeout channel should be initially empty
 	defer timeout.Stop()
 
-	var ttl time.Duration
+	var (
+		ttl       time.Duration
+		stalled   = make(map[string]bool) // Master peers rotated out due to stalling
+		rotations int
+	)
 	getHeaders := func(from uint64) {
 		request = time.Now()
 
@@ -1180,6 +1202,24 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 			}
 
 		case <-timeout.C:
+			// If another peer can take over the header retrieval, rotate the stalling
+			// master out instead of aborting the sync
+			if rotations < maxMasterRotations {
+				stalled[p.id] = true
+				if next := d.rotateMaster(p, stalled); next != nil {
+					p.log.Debug("Header request stalled, rotating master peer", "elapsed", ttl, "next", next.id)
+					headerTimeoutMeter.Mark(1)
+					rotations++
+
+					p = next
+					if pivoting {
+						getNextPivot()
+					} else {
+						getHeaders(from)
+					}
+					continue
+				}
+			}
 			if d.dropPeer == nil {
 				// The dropPeer method is nil when `--copydb` is used for a local copy.
 				// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
@@ -1207,6 +1247,31 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64) error {
 	}
 }
 
+// rotateMaster picks the peer with the highest total difficulty, at least the
+// one advertised by the stalling master, to take over the header retrieval and
+// marks it as the new master peer. Nil is returned if no such peer exists.
+func (d *Downloader) rotateMaster(master *peerConnection, skip map[string]bool) *peerConnection {
+	_, td := master.peer.Head()
+
+	var best *peerConnection
+	for _, p := range d.peers.AllPeers() {
+		if skip[p.id] {
+			continue
+		}
+		if _, ptd := p.peer.Head(); ptd.Cmp(td) >= 0 {
+			best, td = p, ptd
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	d.cancelLock.Lock()
+	d.cancelPeer = best.id
+	d.cancelLock.Unlock()
+
+	return best
+}
+
 // fillHeaderSkeleton concurrently retrieves headers from all our available peers
 // and maps them to the provided skeleton header chain.
 //
@@ -1235,16 +1300,96 @@ func (d *Downloader) fillHeaderSkeleton(from uint64, skeleton []*types.Header) (
 			p.SetHeadersIdle(accepted, deliveryTime)
 		}
 	)
-	err := d.fetchParts(d.headerCh, deliver, d.queue.headerContCh, expire,
+	// Check the seals of the delivered batches on the side, only handing them to
+	// the fetcher once they pass
+	var (
+		checked = make(chan dataPack)
+		done    = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.checkSeals(d.headerCh, checked, done)
+	}()
+	err := d.fetchParts(checked, deliver, d.queue.headerContCh, expire,
 		d.queue.PendingHeaders, d.queue.InFlightHeaders, reserve,
 		nil, fetch, d.queue.CancelHeaders, capacity, d.peers.HeaderIdlePeers, setIdle, "headers")
 
+	close(done)
+	wg.Wait()
+
 	log.Debug("Skeleton fill terminated", "err", err)
 
 	filled, proced := d.queue.RetrieveHeaders()
 	return filled, proced, err
 }
 
+// checkSeals forwards the header batches delivered during a skeleton fill once
+// a sample of their seals is verified. The batches are checked concurrently, so
+// neither the fetcher nor the deliveries of other peers wait for the engine. A
+// peer delivering a batch with an invalid seal is dropped and its batch discarded,
+// the fetcher reschedules it once the peer's request is revoked or expires.
+func (d *Downloader) checkSeals(in <-chan dataPack, out chan<- dataPack, done <-chan struct{}) {
+	forward := func(packet dataPack) {
+		select {
+		case out <- packet:
+		case <-done:
+		}
+	}
+	for {
+		select {
+		case packet := <-in:
+			if d.sealVerifier == nil {
+				forward(packet)
+				continue
+			}
+			go func(pack *headerPack) {
+				if err := d.verifySeals(pack.headers); err != nil {
+					log.Debug("Skeleton fill with invalid seal, dropping", "peer", pack.peerID, "err", err)
+					if d.dropPeer != nil && !errors.Is(err, errCanceled) {
+						d.dropPeer(pack.peerID)
+					}
+					return
+				}
+				forward(pack)
+			}(packet.(*headerPack))
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// verifySeals concurrently checks a sample of the seals of a skeleton fill batch,
+// every skeletonSealFrequency-th header and the last one, leaving the rest to the
+// sampled verification done on import.
+func (d *Downloader) verifySeals(headers []*types.Header) error {
+	if d.sealVerifier == nil || len(headers) == 0 {
+		return nil
+	}
+	sample := make([]*types.Header, 0, len(headers)/skeletonSealFrequency+1)
+	for i := skeletonSealFrequency - 1; i < len(headers)-1; i += skeletonSealFrequency {
+		sample = append(sample, headers[i])
+	}
+	sample = append(sample, headers[len(headers)-1])
+
+	abort, results := d.sealVerifier.VerifySeals(sample)
+	defer close(abort)
+
+	for _, header := range sample {
+		select {
+		case err := <-results:
+			if err != nil {
+				return fmt.Errorf("%w: header %d: %v", errBadPeer, header.Number, err)
+			}
+		case <-d.cancelCh:
+			return errCanceled
+		}
+	}
+	return nil
+}
+
 // fetchBodies iteratively downloads the scheduled block bodies, taking any
 // available peers, reserving a chunk of blocks for each, waiting for delivery
 // and also periodically checking for timeouts.
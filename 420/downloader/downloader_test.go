@@ -1644,3 +1644,110 @@ func testCheckpointEnforcement(t *testing.T, protocol uint, mode SyncMode) {
 		assertOwnChain(t, tester, chain.len())
 	}
 }
+
+// sealTester is a seal verifier recording the headers it was asked to verify and
+// rejecting a specific one.
+type sealTester struct {
+	checked []uint64
+	invalid uint64
+	lock    sync.Mutex
+}
+
+func (v *sealTester) VerifySeals(headers []*types.Header) (chan<- struct{}, <-chan error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	results := make(chan error, len(headers))
+	for _, header := range headers {
+		v.checked = append(v.checked, header.Number.Uint64())
+		if header.Number.Uint64() == v.invalid {
+			results <- errors.New("invalid seal")
+		} else {
+			results <- nil
+		}
+	}
+	return make(chan struct{}), results
+}
+
+// Tests that the seals of skeleton fill batches are verified in samples as they
+// are delivered, and that a peer delivering an invalid seal is dropped before its
+// batch is imported.
+func TestSkeletonSealVerification(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	chain := testChainBase.shorten(blockCacheMaxItems - 15)
+	tester.newPeer("peer", 65, chain)
+
+	// The last header of the first fill batch is always verified
+	verifier := &sealTester{invalid: uint64(MaxHeaderFetch)}
+	tester.downloader.SetSealVerifier(verifier)
+
+	if err := tester.sync("peer", nil, FullSync); err == nil {
+		t.Fatalf("chain with invalid seal synced")
+	}
+	tester.lock.RLock()
+	_, ok := tester.peers["peer"]
+	tester.lock.RUnlock()
+	if ok {
+		t.Fatalf("peer delivering invalid seal not dropped")
+	}
+	if head := tester.CurrentBlock().NumberU64(); head >= verifier.invalid {
+		t.Fatalf("block with invalid seal imported: head %d", head)
+	}
+	verifier.lock.Lock()
+	verifier.checked, verifier.invalid = nil, 0
+	verifier.lock.Unlock()
+
+	tester.newPeer("peer", 65, chain)
+	if err := tester.sync("peer", nil, FullSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+
+	verifier.lock.Lock()
+	defer verifier.lock.Unlock()
+
+	if len(verifier.checked) == 0 || len(verifier.checked) >= chain.len()/2 {
+		t.Fatalf("verified seal count out of range: have %d, chain %d", len(verifier.checked), chain.len())
+	}
+	var found bool
+	for _, number := range verifier.checked {
+		found = found || number == uint64(MaxHeaderFetch)
+	}
+	if !found {
+		t.Fatalf("last header of first fill batch not verified")
+	}
+}
+
+// Tests that the seals of header batches are verified in samples, and that
+// batches with an invalid seal are rejected.
+func TestSealVerificationSampling(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	verifier := new(sealTester)
+	tester.downloader.SetSealVerifier(verifier)
+
+	headers := make([]*types.Header, MaxHeaderFetch)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i + 1))}
+	}
+	if err := tester.downloader.verifySeals(headers); err != nil {
+		t.Fatalf("failed to verify valid batch: %v", err)
+	}
+	if have, want := len(verifier.checked), MaxHeaderFetch/skeletonSealFrequency; have != want {
+		t.Fatalf("verified seal count mismatch: have %d, want %d", have, want)
+	}
+	if last := verifier.checked[len(verifier.checked)-1]; last != uint64(MaxHeaderFetch) {
+		t.Fatalf("last header not verified: have %d, want %d", last, MaxHeaderFetch)
+	}
+	verifier.invalid = uint64(skeletonSealFrequency)
+	if err := tester.downloader.verifySeals(headers); !errors.Is(err, errBadPeer) {
+		t.Fatalf("invalid seal error mismatch: have %v, want %v", err, errBadPeer)
+	}
+}
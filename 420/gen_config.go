@@ -1,6 +1,7 @@
 package fourtwenty
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/420integrated/go-420coin/common"
@@ -22,12 +23,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPruning               bool
 		NoPrefetch              bool
 		TxLookupLimit           uint64                 `toml:",omitempty"`
+		MaxReorgDepth           uint64                 `toml:",omitempty"`
+		TraceStore              bool                   `toml:",omitempty"`
+		TraceStoreRetention     uint64                 `toml:",omitempty"`
+		ChainTipAlertDepth      uint64                 `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
+		FederationSigner        common.Address         `toml:",omitempty"`
 		LightServ               int                    `toml:",omitempty"`
 		LightIngress            int                    `toml:",omitempty"`
 		LightEgress             int                    `toml:",omitempty"`
 		LightPeers              int                    `toml:",omitempty"`
 		LightNoPrune            bool                   `toml:",omitempty"`
+		LightRemotePoW          bool                   `toml:",omitempty"`
 		UltraLightServers       []string               `toml:",omitempty"`
 		UltraLightFraction      int                    `toml:",omitempty"`
 		UltraLightOnlyAnnounce  bool                   `toml:",omitempty"`
@@ -35,12 +42,15 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DatabaseHandles         int                    `toml:"-"`
 		DatabaseCache           int
 		DatabaseFreezer         string
+		DatabaseCompress        bool `toml:",omitempty"`
 		TrieCleanCache          int
 		TrieCleanCacheJournal   string        `toml:",omitempty"`
 		TrieCleanCacheRejournal time.Duration `toml:",omitempty"`
 		TrieDirtyCache          int
 		TrieTimeout             time.Duration
 		SnapshotCache           int
+		TxPoolCache             int `toml:",omitempty"`
+		ChainCache              int `toml:",omitempty"`
 		Preimages               bool
 		Miner                   miner.Config
 		Ethash                  ethash.Config
@@ -52,8 +62,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EVMInterpreter          string
 		RPCSmokeCap               uint64                         `toml:",omitempty"`
 		RPCTxFeeCap             float64                        `toml:",omitempty"`
+		RPCStructuredErrors     bool                           `toml:",omitempty"`
+		RPCRecipientCheck       bool                           `toml:",omitempty"`
+		RPCEthCompat            bool                           `toml:",omitempty"`
+		RPCTracerSteps          uint64                         `toml:",omitempty"`
+		RPCTracerMemory         int                            `toml:",omitempty"`
+		RPCNoCustomTracers      bool                           `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideIndica          *big.Int                       `toml:",omitempty"`
+		OverrideSativa          *big.Int                       `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -63,12 +81,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
 	enc.TxLookupLimit = c.TxLookupLimit
+	enc.MaxReorgDepth = c.MaxReorgDepth
+	enc.TraceStore = c.TraceStore
+	enc.TraceStoreRetention = c.TraceStoreRetention
+	enc.ChainTipAlertDepth = c.ChainTipAlertDepth
 	enc.Whitelist = c.Whitelist
+	enc.FederationSigner = c.FederationSigner
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
 	enc.LightEgress = c.LightEgress
 	enc.LightPeers = c.LightPeers
 	enc.LightNoPrune = c.LightNoPrune
+	enc.LightRemotePoW = c.LightRemotePoW
 	enc.UltraLightServers = c.UltraLightServers
 	enc.UltraLightFraction = c.UltraLightFraction
 	enc.UltraLightOnlyAnnounce = c.UltraLightOnlyAnnounce
@@ -76,12 +100,15 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
 	enc.DatabaseFreezer = c.DatabaseFreezer
+	enc.DatabaseCompress = c.DatabaseCompress
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieCleanCacheJournal = c.TrieCleanCacheJournal
 	enc.TrieCleanCacheRejournal = c.TrieCleanCacheRejournal
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.SnapshotCache = c.SnapshotCache
+	enc.TxPoolCache = c.TxPoolCache
+	enc.ChainCache = c.ChainCache
 	enc.Preimages = c.Preimages
 	enc.Miner = c.Miner
 	enc.Ethash = c.Ethash
@@ -93,8 +120,16 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EVMInterpreter = c.EVMInterpreter
 	enc.RPCSmokeCap = c.RPCSmokeCap
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCStructuredErrors = c.RPCStructuredErrors
+	enc.RPCRecipientCheck = c.RPCRecipientCheck
+	enc.RPCEthCompat = c.RPCEthCompat
+	enc.RPCTracerSteps = c.RPCTracerSteps
+	enc.RPCTracerMemory = c.RPCTracerMemory
+	enc.RPCNoCustomTracers = c.RPCNoCustomTracers
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
+	enc.OverrideIndica = c.OverrideIndica
+	enc.OverrideSativa = c.OverrideSativa
 	return &enc, nil
 }
 
@@ -108,12 +143,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NoPruning               *bool
 		NoPrefetch              *bool
 		TxLookupLimit           *uint64                `toml:",omitempty"`
+		MaxReorgDepth           *uint64                `toml:",omitempty"`
+		TraceStore              *bool                  `toml:",omitempty"`
+		TraceStoreRetention     *uint64                `toml:",omitempty"`
+		ChainTipAlertDepth      *uint64                `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
+		FederationSigner        *common.Address        `toml:",omitempty"`
 		LightServ               *int                   `toml:",omitempty"`
 		LightIngress            *int                   `toml:",omitempty"`
 		LightEgress             *int                   `toml:",omitempty"`
 		LightPeers              *int                   `toml:",omitempty"`
 		LightNoPrune            *bool                  `toml:",omitempty"`
+		LightRemotePoW          *bool                  `toml:",omitempty"`
 		UltraLightServers       []string               `toml:",omitempty"`
 		UltraLightFraction      *int                   `toml:",omitempty"`
 		UltraLightOnlyAnnounce  *bool                  `toml:",omitempty"`
@@ -121,12 +162,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DatabaseHandles         *int                   `toml:"-"`
 		DatabaseCache           *int
 		DatabaseFreezer         *string
+		DatabaseCompress        *bool `toml:",omitempty"`
 		TrieCleanCache          *int
 		TrieCleanCacheJournal   *string        `toml:",omitempty"`
 		TrieCleanCacheRejournal *time.Duration `toml:",omitempty"`
 		TrieDirtyCache          *int
 		TrieTimeout             *time.Duration
 		SnapshotCache           *int
+		TxPoolCache             *int `toml:",omitempty"`
+		ChainCache              *int `toml:",omitempty"`
 		Preimages               *bool
 		Miner                   *miner.Config
 		Ethash                  *ethash.Config
@@ -138,8 +182,16 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EVMInterpreter          *string
 		RPCSmokeCap               *uint64                        `toml:",omitempty"`
 		RPCTxFeeCap             *float64                       `toml:",omitempty"`
+		RPCStructuredErrors     *bool                          `toml:",omitempty"`
+		RPCRecipientCheck       *bool                          `toml:",omitempty"`
+		RPCEthCompat            *bool                          `toml:",omitempty"`
+		RPCTracerSteps          *uint64                        `toml:",omitempty"`
+		RPCTracerMemory         *int                           `toml:",omitempty"`
+		RPCNoCustomTracers      *bool                          `toml:",omitempty"`
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		OverrideIndica          *big.Int                       `toml:",omitempty"`
+		OverrideSativa          *big.Int                       `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -166,9 +218,24 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
+	if dec.MaxReorgDepth != nil {
+		c.MaxReorgDepth = *dec.MaxReorgDepth
+	}
+	if dec.TraceStore != nil {
+		c.TraceStore = *dec.TraceStore
+	}
+	if dec.TraceStoreRetention != nil {
+		c.TraceStoreRetention = *dec.TraceStoreRetention
+	}
+	if dec.ChainTipAlertDepth != nil {
+		c.ChainTipAlertDepth = *dec.ChainTipAlertDepth
+	}
 	if dec.Whitelist != nil {
 		c.Whitelist = dec.Whitelist
 	}
+	if dec.FederationSigner != nil {
+		c.FederationSigner = *dec.FederationSigner
+	}
 	if dec.LightServ != nil {
 		c.LightServ = *dec.LightServ
 	}
@@ -184,6 +251,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LightNoPrune != nil {
 		c.LightNoPrune = *dec.LightNoPrune
 	}
+	if dec.LightRemotePoW != nil {
+		c.LightRemotePoW = *dec.LightRemotePoW
+	}
 	if dec.UltraLightServers != nil {
 		c.UltraLightServers = dec.UltraLightServers
 	}
@@ -205,6 +275,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseFreezer != nil {
 		c.DatabaseFreezer = *dec.DatabaseFreezer
 	}
+	if dec.DatabaseCompress != nil {
+		c.DatabaseCompress = *dec.DatabaseCompress
+	}
 	if dec.TrieCleanCache != nil {
 		c.TrieCleanCache = *dec.TrieCleanCache
 	}
@@ -223,6 +296,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SnapshotCache != nil {
 		c.SnapshotCache = *dec.SnapshotCache
 	}
+	if dec.TxPoolCache != nil {
+		c.TxPoolCache = *dec.TxPoolCache
+	}
+	if dec.ChainCache != nil {
+		c.ChainCache = *dec.ChainCache
+	}
 	if dec.Preimages != nil {
 		c.Preimages = *dec.Preimages
 	}
@@ -256,11 +335,35 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCStructuredErrors != nil {
+		c.RPCStructuredErrors = *dec.RPCStructuredErrors
+	}
+	if dec.RPCRecipientCheck != nil {
+		c.RPCRecipientCheck = *dec.RPCRecipientCheck
+	}
+	if dec.RPCEthCompat != nil {
+		c.RPCEthCompat = *dec.RPCEthCompat
+	}
+	if dec.RPCTracerSteps != nil {
+		c.RPCTracerSteps = *dec.RPCTracerSteps
+	}
+	if dec.RPCTracerMemory != nil {
+		c.RPCTracerMemory = *dec.RPCTracerMemory
+	}
+	if dec.RPCNoCustomTracers != nil {
+		c.RPCNoCustomTracers = *dec.RPCNoCustomTracers
+	}
 	if dec.Checkpoint != nil {
 		c.Checkpoint = dec.Checkpoint
 	}
 	if dec.CheckpointOracle != nil {
 		c.CheckpointOracle = dec.CheckpointOracle
 	}
+	if dec.OverrideIndica != nil {
+		c.OverrideIndica = dec.OverrideIndica
+	}
+	if dec.OverrideSativa != nil {
+		c.OverrideSativa = dec.OverrideSativa
+	}
 	return nil
 }
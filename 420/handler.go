@@ -26,6 +26,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/forkid"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/420/downloader"
@@ -85,6 +86,9 @@ type handlerConfig struct {
 	EventMux   *event.TypeMux            // Legacy event mux, deprecate for `feed`
 	Checkpoint *params.TrustedCheckpoint // Hard coded checkpoint for sync challenges
 	Whitelist  map[uint64]common.Hash    // Hard coded whitelist for sync challenged
+
+	Federation     *federation.Tracker          // Federated checkpoint vote tracker (nil = disabled)
+	FederationSign func([]byte) ([]byte, error) // Signer for local checkpoint votes (nil = not a signer)
 }
 
 type handler struct {
@@ -116,6 +120,11 @@ type handler struct {
 
 	whitelist map[uint64]common.Hash
 
+	federation     *federation.Tracker
+	federationSign func([]byte) ([]byte, error)
+
+	arrivals *arrivalTracker // First-seen timestamps of propagated blocks and transactions
+
 	// channels for fetcher, syncer, txsyncLoop
 	txsyncCh chan *txsync
 	quitSync chan struct{}
@@ -142,6 +151,11 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		whitelist:  config.Whitelist,
 		txsyncCh:   make(chan *txsync),
 		quitSync:   make(chan struct{}),
+
+		federation:     config.Federation,
+		federationSign: config.FederationSign,
+
+		arrivals: newArrivalTracker(),
 	}
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the fast
@@ -181,6 +195,9 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		h.stateBloom = trie.NewSyncBloom(config.BloomCache, config.Database)
 	}
 	h.downloader = downloader.New(h.checkpointNumber, config.Database, h.stateBloom, h.eventMux, h.chain, nil, h.removePeer)
+	if verifier, ok := h.chain.Engine().(downloader.SealVerifier); ok {
+		h.downloader.SetSealVerifier(verifier)
+	}
 
 	// Construct the fetcher (short sync)
 	validator := func(header *types.Header) error {
@@ -277,6 +294,9 @@ func (h *handler) runFourtwentyPeer(peer *fourtwenty.Peer, handler fourtwenty.Ha
 	// after this will be sent via broadcasts.
 	h.syncTransactions(peer)
 
+	// Propagate the votes of the latest federated checkpoint, if any
+	h.syncCheckpointVotes(peer)
+
 	// If we have a trusted CHT, reject all peers below that (avoid fast sync eclipse)
 	if h.checkpointHash != (common.Hash{}) {
 		// Request the peer's checkpoint header for chain height/weight validation
@@ -369,6 +389,12 @@ func (h *handler) Start(maxPeers int) {
 	h.minedBlockSub = h.eventMux.Subscribe(core.NewMinedBlockEvent{})
 	go h.minedBroadcastLoop()
 
+	// vote on federated checkpoints
+	if h.federation != nil && h.federationSign != nil {
+		h.wg.Add(1)
+		go h.federationLoop()
+	}
+
 	// start sync handlers
 	h.wg.Add(2)
 	go h.chainSync.loop()
@@ -0,0 +1,139 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"fmt"
+
+	"github.com/420integrated/go-420coin/420/protocols/420"
+	"github.com/420integrated/go-420coin/accounts"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/federation"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// checkpointConfirmations is the number of blocks a checkpoint block needs to be
+// buried under before the local federation signer votes for it.
+const checkpointConfirmations = 6
+
+// federationSigner returns a function signing checkpoint votes with the given
+// federation account, which must be available and unlocked in the manager.
+func federationSigner(am *accounts.Manager, tracker *federation.Tracker, signer common.Address) (func([]byte) ([]byte, error), error) {
+	if !tracker.IsSigner(signer) {
+		return nil, fmt.Errorf("account %x is not a federation signer", signer)
+	}
+	account := accounts.Account{Address: signer}
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("federation signer %x: %v", signer, err)
+	}
+	return func(data []byte) ([]byte, error) {
+		return wallet.SignData(account, accounts.MimetypeTextPlain, data)
+	}, nil
+}
+
+// federationLoop votes with the local federation signer on every checkpoint
+// block once it is confirmed on the canonical chain.
+func (h *handler) federationLoop() {
+	defer h.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := h.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	var voted uint64
+	for {
+		select {
+		case ev := <-heads:
+			head := ev.Block.NumberU64()
+			if head < checkpointConfirmations {
+				continue
+			}
+			number := head - checkpointConfirmations
+			number -= number % h.federation.Interval()
+			if number == 0 || number <= voted {
+				continue
+			}
+			voted = number
+
+			hash := h.chain.GetCanonicalHash(number)
+			sig, err := h.federationSign(federation.SigningData(number, hash))
+			if err != nil {
+				log.Warn("Failed to sign checkpoint vote", "number", number, "hash", hash, "err", err)
+				continue
+			}
+			if err := h.addCheckpointVote(&federation.Vote{Number: number, Hash: hash, Signature: sig}); err != nil {
+				log.Warn("Failed to add local checkpoint vote", "number", number, "hash", hash, "err", err)
+			}
+
+		case <-sub.Err():
+			return
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
+// addCheckpointVote records a checkpoint vote, relays it to the peers which
+// don't know about it yet and enforces the checkpoint it finalizes, if any.
+func (h *handler) addCheckpointVote(vote *federation.Vote) error {
+	added, checkpoint, err := h.federation.Add(vote)
+	if err != nil || !added {
+		return err
+	}
+	h.BroadcastCheckpointVote(vote)
+
+	if checkpoint != nil {
+		log.Info("Federated checkpoint finalized", "number", checkpoint.Number, "hash", checkpoint.Hash, "signers", len(checkpoint.Signers))
+		h.chain.SetFederatedCheckpoint(checkpoint.Number, checkpoint.Hash)
+	}
+	return nil
+}
+
+// BroadcastCheckpointVote relays a checkpoint vote to all peers which support
+// federation votes and are not known to already have it.
+func (h *handler) BroadcastCheckpointVote(vote *federation.Vote) {
+	peers := h.peers.fourtwentyPeersWithoutCheckpointVote(vote.ID())
+	for _, peer := range peers {
+		go func(peer *fourtwentyPeer) {
+			if err := peer.SendCheckpointVote(vote); err != nil {
+				peer.Log().Debug("Failed to relay checkpoint vote", "number", vote.Number, "err", err)
+			}
+		}(peer)
+	}
+	log.Trace("Relayed checkpoint vote", "number", vote.Number, "hash", vote.Hash, "recipients", len(peers))
+}
+
+// syncCheckpointVotes sends the votes of the latest federated checkpoint to a
+// newly connected peer, so that it enforces the same checkpoint.
+func (h *handler) syncCheckpointVotes(peer *fourtwenty.Peer) {
+	if h.federation == nil || peer.Version() < fourtwenty.FOURTWENTY66 {
+		return
+	}
+	checkpoint := h.federation.Latest()
+	if checkpoint == nil {
+		return
+	}
+	go func() {
+		for _, vote := range checkpoint.Votes() {
+			if err := peer.SendCheckpointVote(vote); err != nil {
+				return
+			}
+		}
+	}()
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/420/protocols/420"
 	"github.com/420integrated/go-420coin/log"
@@ -91,14 +92,23 @@ func (h *fourtwentyHandler) Handle(peer *fourtwenty.Peer, packet fourtwenty.Pack
 		return h.handleBlockBroadcast(peer, packet.Block, packet.TD)
 
 	case *fourtwenty.NewPooledTransactionHashesPacket:
+		h.arrivals.txsAnnounced(peer.ID(), *packet, time.Now())
 		return h.txFetcher.Notify(peer.ID(), *packet)
 
 	case *fourtwenty.TransactionsPacket:
+		h.recordTxArrivals(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, false)
 
 	case *fourtwenty.PooledTransactionsPacket:
+		h.recordTxArrivals(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, true)
 
+	case *fourtwenty.CheckpointVotePacket:
+		if h.federation == nil {
+			return nil
+		}
+		return (*handler)(h).addCheckpointVote((*federation.Vote)(packet))
+
 	default:
 		return fmt.Errorf("unexpected eth packet type: %T", packet)
 	}
@@ -180,6 +190,10 @@ func (h *fourtwentyHandler) handleBodies(peer *fourtwenty.Peer, txs [][]*types.T
 // handleBlockAnnounces is invoked from a peer's message handler when it transmits a
 // batch of block announcements for the local node to process.
 func (h *fourtwentyHandler) handleBlockAnnounces(peer *fourtwenty.Peer, hashes []common.Hash, numbers []uint64) error {
+	now := time.Now()
+	for i := 0; i < len(hashes); i++ {
+		h.arrivals.blockAnnounced(peer.ID(), hashes[i], numbers[i], now)
+	}
 	// Schedule all the unknown hashes for retrieval
 	var (
 		unknownHashes  = make([]common.Hash, 0, len(hashes))
@@ -192,7 +206,7 @@ func (h *fourtwentyHandler) handleBlockAnnounces(peer *fourtwenty.Peer, hashes [
 		}
 	}
 	for i := 0; i < len(unknownHashes); i++ {
-		h.blockFetcher.Notify(peer.ID(), unknownHashes[i], unknownNumbers[i], time.Now(), peer.RequestOneHeader, peer.RequestBodies)
+		h.blockFetcher.Notify(peer.ID(), unknownHashes[i], unknownNumbers[i], now, peer.RequestOneHeader, peer.RequestBodies)
 	}
 	return nil
 }
@@ -200,6 +214,8 @@ func (h *fourtwentyHandler) handleBlockAnnounces(peer *fourtwenty.Peer, hashes [
 // handleBlockBroadcast is invoked from a peer's message handler when it transmits a
 // block broadcast for the local node to process.
 func (h *fourtwentyHandler) handleBlockBroadcast(peer *fourtwenty.Peer, block *types.Block, td *big.Int) error {
+	h.arrivals.blockReceived(peer.ID(), block.Hash(), block.NumberU64(), time.Now())
+
 	// Schedule the block for import
 	h.blockFetcher.Enqueue(peer.ID(), block)
 
@@ -215,4 +231,14 @@ func (h *fourtwentyHandler) handleBlockBroadcast(peer *fourtwenty.Peer, block *t
 		h.chainSync.handlePeerEvent(peer)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// recordTxArrivals records the first-seen timestamps of a batch of transactions
+// propagated by a remote peer.
+func (h *fourtwentyHandler) recordTxArrivals(peer *fourtwenty.Peer, txs []*types.Transaction) {
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	h.arrivals.txsReceived(peer.ID(), hashes, time.Now())
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/420/protocols/420"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/forkid"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/p2p"
+	"github.com/420integrated/go-420coin/p2p/enode"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/rlp"
+)
+
+// testMsg is a message sent by a test handler to a scripted peer, with its
+// payload already consumed.
+type testMsg struct {
+	code    uint64
+	payload []byte
+}
+
+// decode parses the RLP payload of the message into the given value.
+func (msg testMsg) decode(val interface{}) error {
+	return rlp.Decode(bytes.NewReader(msg.payload), val)
+}
+
+// testScriptedPeer is a remote peer connected to a live test handler, whose side
+// of the protocol is scripted by the test instead of run by a second handler.
+// This allows covering protocol edge cases like announcements, disjoint forks or
+// malformed messages without spinning up full nodes.
+type testScriptedPeer struct {
+	*fourtwenty.Peer // Remote end of the connection, for sending well formed packets
+
+	app   *p2p.MsgPipeRW   // Raw remote end of the connection, for arbitrary messages
+	net   *p2p.MsgPipeRW   // Local end of the connection, run by the handler
+	local *fourtwenty.Peer // Peer of the handler, representing the scripted one
+	msgs  chan testMsg     // Messages sent by the handler after the handshake
+	errc  chan error       // Result of the handler's peer loop once disconnected
+}
+
+// newTestScriptedPeer connects a scripted peer to the handler and runs the
+// protocol handshake, advertising the same head as the handler has.
+func newTestScriptedPeer(t *testing.T, handler *testHandler, protocol uint, id byte) *testScriptedPeer {
+	t.Helper()
+
+	net, app := p2p.MsgPipe()
+	peer := &testScriptedPeer{
+		Peer:  fourtwenty.NewPeer(protocol, p2p.NewPeer(enode.ID{id}, "", nil), app, handler.txpool),
+		app:   app,
+		net:   net,
+		local: fourtwenty.NewPeer(protocol, p2p.NewPeer(enode.ID{id}, "", nil), net, handler.txpool),
+		msgs:  make(chan testMsg, 1024),
+		errc:  make(chan error, 1),
+	}
+	go func() {
+		peer.errc <- handler.handler.runFourtwentyPeer(peer.local, func(p *fourtwenty.Peer) error {
+			return fourtwenty.Handle((*fourtwentyHandler)(handler.handler), p)
+		})
+	}()
+	var (
+		genesis = handler.chain.Genesis()
+		head    = handler.chain.CurrentBlock()
+		td      = handler.chain.GetTd(head.Hash(), head.NumberU64())
+	)
+	if err := peer.Handshake(1, td, head.Hash(), genesis.Hash(), forkid.NewIDWithChain(handler.chain), forkid.NewFilter(handler.chain)); err != nil {
+		peer.close()
+		t.Fatalf("failed to run protocol handshake: %v", err)
+	}
+	go peer.readLoop()
+	return peer
+}
+
+// readLoop consumes all the messages sent by the handler, queueing them up to be
+// expected by the test. Messages overflowing the queue are dropped.
+func (p *testScriptedPeer) readLoop() {
+	for {
+		msg, err := p.app.ReadMsg()
+		if err != nil {
+			return
+		}
+		payload, err := ioutil.ReadAll(msg.Payload)
+		msg.Discard()
+		if err != nil {
+			return
+		}
+		select {
+		case p.msgs <- testMsg{code: msg.Code, payload: payload}:
+		default:
+		}
+	}
+}
+
+// close disconnects the scripted peer and tears down both ends of the pipe.
+func (p *testScriptedPeer) close() {
+	p.Peer.Close()
+	p.local.Close()
+	p.app.Close()
+	p.net.Close()
+}
+
+// sendRaw sends an arbitrary message to the handler, allowing malformed payloads
+// or unexpected message codes.
+func (p *testScriptedPeer) sendRaw(code uint64, data interface{}) error {
+	return p2p.Send(p.app, code, data)
+}
+
+// announce sends a block hash announcement of the given blocks to the handler.
+func (p *testScriptedPeer) announce(blocks ...*types.Block) error {
+	hashes := make([]common.Hash, len(blocks))
+	numbers := make([]uint64, len(blocks))
+	for i, block := range blocks {
+		hashes[i], numbers[i] = block.Hash(), block.NumberU64()
+	}
+	return p.SendNewBlockHashes(hashes, numbers)
+}
+
+// expectMsg waits for the next message of the given code from the handler,
+// skipping any other message in between.
+func (p *testScriptedPeer) expectMsg(code uint64, timeout time.Duration) (testMsg, error) {
+	expire := time.After(timeout)
+	for {
+		select {
+		case msg := <-p.msgs:
+			if msg.code == code {
+				return msg, nil
+			}
+		case err := <-p.errc:
+			return testMsg{}, fmt.Errorf("peer dropped while waiting for message %#x: %v", code, err)
+		case <-expire:
+			return testMsg{}, fmt.Errorf("timed out waiting for message %#x", code)
+		}
+	}
+}
+
+// expectDrop waits for the handler to disconnect the scripted peer, reporting
+// whether it happened within the timeout.
+func (p *testScriptedPeer) expectDrop(timeout time.Duration) bool {
+	select {
+	case <-p.errc:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// makeFork generates a number of blocks on top of the given parent. Forks made
+// with different seeds are disjoint, having different coinbases.
+func (b *testHandler) makeFork(parent *types.Block, n int, seed byte) []*types.Block {
+	blocks, _ := core.GenerateChain(params.TestChainConfig, parent, ethash.NewFaker(), b.db, n, func(i int, block *core.BlockGen) {
+		block.SetCoinbase(common.Address{seed})
+	})
+	return blocks
+}
+
+// Tests that announcements of blocks on disjoint forks are all fetched from the
+// announcing peer, while already known blocks are ignored.
+func TestScriptedAnnounce64(t *testing.T) { testScriptedAnnounce(t, 64) }
+func TestScriptedAnnounce65(t *testing.T) { testScriptedAnnounce(t, 65) }
+
+func testScriptedAnnounce(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	handler := newTestHandlerWithBlocks(4)
+	defer handler.close()
+
+	peer := newTestScriptedPeer(t, handler, protocol, 1)
+	defer peer.close()
+
+	var (
+		known = handler.chain.CurrentBlock()
+		forkA = handler.makeFork(handler.chain.GetBlockByNumber(2), 3, 0xaa)
+		forkB = handler.makeFork(handler.chain.GetBlockByNumber(2), 3, 0xbb)
+	)
+	if err := peer.announce(known, forkA[len(forkA)-1], forkB[len(forkB)-1]); err != nil {
+		t.Fatalf("failed to announce blocks: %v", err)
+	}
+	want := map[common.Hash]bool{
+		forkA[len(forkA)-1].Hash(): true,
+		forkB[len(forkB)-1].Hash(): true,
+	}
+	for len(want) > 0 {
+		msg, err := peer.expectMsg(fourtwenty.GetBlockHeadersMsg, time.Second)
+		if err != nil {
+			t.Fatalf("announced header not requested: %v", err)
+		}
+		var query fourtwenty.GetBlockHeadersPacket
+		if err := msg.decode(&query); err != nil {
+			t.Fatalf("failed to decode header request: %v", err)
+		}
+		if !want[query.Origin.Hash] {
+			t.Fatalf("unexpected header request: %x", query.Origin.Hash)
+		}
+		delete(want, query.Origin.Hash)
+	}
+}
+
+// Tests that peers sending malformed messages are disconnected.
+func TestScriptedMalformed64(t *testing.T) { testScriptedMalformed(t, 64) }
+func TestScriptedMalformed65(t *testing.T) { testScriptedMalformed(t, 65) }
+
+func testScriptedMalformed(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	tests := []struct {
+		code uint64
+		data interface{}
+	}{
+		{fourtwenty.NewBlockHashesMsg, []byte{0x01, 0x02}},                // Not a list of announcements
+		{fourtwenty.NewBlockMsg, []interface{}{uint64(1), big.NewInt(1)}}, // Not a block
+		{fourtwenty.StatusMsg, []interface{}{}},                           // Status after handshake
+	}
+	for i, tt := range tests {
+		handler := newTestHandler()
+		peer := newTestScriptedPeer(t, handler, protocol, byte(i+1))
+
+		if err := peer.sendRaw(tt.code, tt.data); err != nil {
+			t.Errorf("test %d: failed to send message: %v", i, err)
+		}
+		if !peer.expectDrop(time.Second) {
+			t.Errorf("test %d: peer not dropped", i)
+		}
+		peer.close()
+		handler.close()
+	}
+}
@@ -258,6 +258,22 @@ func (ps *peerSet) fourtwentyPeersWithoutTransaction(hash common.Hash) []*fourtw
 	return list
 }
 
+// fourtwentyPeersWithoutCheckpointVote retrieves a list of `fourtwenty` peers
+// supporting federation votes which don't have the given vote in their set of
+// known identifiers.
+func (ps *peerSet) fourtwentyPeersWithoutCheckpointVote(id common.Hash) []*fourtwentyPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*fourtwentyPeer, 0, len(ps.fourtwentyPeers))
+	for _, p := range ps.fourtwentyPeers {
+		if p.Version() >= fourtwenty.FOURTWENTY66 && !p.KnownCheckpointVote(id) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // Len returns if the current number of `fourtwenty` peers in the set. Since the `snap`
 // peers are tied to the existnce of an `fourtwenty` connection, that will always be a
 // subset of `fourtwenty`.
@@ -23,6 +23,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/p2p"
@@ -282,6 +283,9 @@ func handleMessage(backend Backend, peer *Peer) error {
 		if err := msg.Decode(res); err != nil {
 			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 		}
+		if err := res.sanityCheck(); err != nil {
+			return err
+		}
 		return backend.Handle(peer, res)
 
 	case msg.Code == GetBlockBodiesMsg:
@@ -313,6 +317,9 @@ func handleMessage(backend Backend, peer *Peer) error {
 		if err := msg.Decode(res); err != nil {
 			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 		}
+		if err := res.sanityCheck(); err != nil {
+			return err
+		}
 		return backend.Handle(peer, res)
 
 	case msg.Code == GetNodeDataMsg:
@@ -505,6 +512,15 @@ func handleMessage(backend Backend, peer *Peer) error {
 		}
 		return backend.Handle(peer, (*TransactionsPacket)(&txs))
 
+	case msg.Code == CheckpointVoteMsg && peer.version >= FOURTWENTY66:
+		// A federation vote arrived, mark it known and let the backend validate it
+		vote := new(CheckpointVotePacket)
+		if err := msg.Decode(vote); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		peer.markCheckpointVote((*federation.Vote)(vote).ID())
+		return backend.Handle(peer, vote)
+
 	default:
 		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
 	}
@@ -22,6 +22,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/p2p"
 	"github.com/420integrated/go-420coin/rlp"
@@ -36,6 +37,10 @@ const (
 	// before starting to randomly evict them.
 	maxKnownBlocks = 1024
 
+	// maxKnownVotes is the maximum checkpoint vote identifiers to keep in the
+	// known list before starting to randomly evict them.
+	maxKnownVotes = 1024
+
 	// maxQueuedTxs is the maximum number of transactions to queue up before dropping
 	// older broadcasts.
 	maxQueuedTxs = 4096
@@ -83,6 +88,8 @@ type Peer struct {
 	txBroadcast chan []common.Hash // Channel used to queue transaction propagation requests
 	txAnnounce  chan []common.Hash // Channel used to queue transaction announcement requests
 
+	knownVotes mapset.Set // Set of checkpoint vote identifiers known to be known by this peer
+
 	term chan struct{} // Termination channel to stop the broadcasters
 	lock sync.RWMutex  // Mutex protecting the internal fields
 }
@@ -97,6 +104,7 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, txpool TxPool) *Pe
 		version:         version,
 		knownTxs:        mapset.NewSet(),
 		knownBlocks:     mapset.NewSet(),
+		knownVotes:      mapset.NewSet(),
 		queuedBlocks:    make(chan *blockPropagation, maxQueuedBlocks),
 		queuedBlockAnns: make(chan *types.Block, maxQueuedBlockAnns),
 		txBroadcast:     make(chan []common.Hash),
@@ -158,6 +166,11 @@ func (p *Peer) KnownTransaction(hash common.Hash) bool {
 	return p.knownTxs.Contains(hash)
 }
 
+// KnownCheckpointVote returns whether peer is known to already have a vote.
+func (p *Peer) KnownCheckpointVote(id common.Hash) bool {
+	return p.knownVotes.Contains(id)
+}
+
 // markBlock marks a block as known for the peer, ensuring that the block will
 // never be propagated to this particular peer.
 func (p *Peer) markBlock(hash common.Hash) {
@@ -178,6 +191,23 @@ func (p *Peer) markTransaction(hash common.Hash) {
 	p.knownTxs.Add(hash)
 }
 
+// markCheckpointVote marks a checkpoint vote as known for the peer, ensuring
+// that it will never be relayed to this particular peer.
+func (p *Peer) markCheckpointVote(id common.Hash) {
+	// If we reached the memory allowance, drop a previously known vote
+	for p.knownVotes.Cardinality() >= maxKnownVotes {
+		p.knownVotes.Pop()
+	}
+	p.knownVotes.Add(id)
+}
+
+// SendCheckpointVote relays a federation checkpoint vote to the peer and marks
+// it as known. Votes are rare, so they are sent directly without queueing.
+func (p *Peer) SendCheckpointVote(vote *federation.Vote) error {
+	p.markCheckpointVote(vote.ID())
+	return p2p.Send(p.rw, CheckpointVoteMsg, (*CheckpointVotePacket)(vote))
+}
+
 // SendTransactions sends transactions to the peer and includes the hashes
 // in its transaction hash set for future reference.
 //
@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/federation"
 	"github.com/420integrated/go-420coin/core/forkid"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/rlp"
@@ -32,6 +33,7 @@ import (
 const (
 	FOURTWENTY64 = 64
 	FOURTWENTY65 = 65
+	FOURTWENTY66 = 66
 )
 
 // protocolName is the official short name of the `fourtwenty` protocol used during
@@ -40,11 +42,11 @@ const protocolName = "fourtwenty"
 
 // protocolVersions are the supported versions of the `fourtwenty` protocol (first
 // is primary).
-var protocolVersions = []uint{FOURTWENTY65, FOURTWENTY64}
+var protocolVersions = []uint{FOURTWENTY66, FOURTWENTY65, FOURTWENTY64}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{FOURTWENTY65: 17, FOURTWENTY64: 17}
+var protocolLengths = map[uint]uint64{FOURTWENTY66: 18, FOURTWENTY65: 17, FOURTWENTY64: 17}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
@@ -68,6 +70,9 @@ const (
 	NewPooledTransactionHashesMsg = 0x08
 	GetPooledTransactionsMsg      = 0x09
 	PooledTransactionsMsg         = 0x0a
+
+	// Protocol messages introduced in fourtwenty/66
+	CheckpointVoteMsg = 0x11
 )
 
 var (
@@ -168,6 +173,17 @@ func (hn *HashOrNumber) DecodeRLP(s *rlp.Stream) error {
 // BlockHeadersPacket represents a block header response.
 type BlockHeadersPacket []*types.Header
 
+// sanityCheck verifies that the delivered headers are reasonable, as a DoS
+// protection against junk stuffed into their unbounded fields.
+func (p *BlockHeadersPacket) sanityCheck() error {
+	for _, header := range *p {
+		if err := header.SanityCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewBlockPacket is the network packet for the block propagation message.
 type NewBlockPacket struct {
 	Block *types.Block
@@ -193,6 +209,19 @@ type GetBlockBodiesPacket []common.Hash
 // BlockBodiesPacket is the network packet for block content distribution.
 type BlockBodiesPacket []*BlockBody
 
+// sanityCheck verifies that the uncles of the delivered bodies are reasonable,
+// as a DoS protection.
+func (p *BlockBodiesPacket) sanityCheck() error {
+	for _, body := range *p {
+		for _, uncle := range body.Uncles {
+			if err := uncle.SanityCheck(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // BlockBody represents the data content of a single block.
 type BlockBody struct {
 	Transactions []*types.Transaction // Transactions contained within a block
@@ -233,6 +262,10 @@ type GetPooledTransactionsPacket []common.Hash
 // PooledTransactionsPacket is the network packet for transaction distribution.
 type PooledTransactionsPacket []*types.Transaction
 
+// CheckpointVotePacket is the network packet for relaying the vote of a
+// federation signer on a checkpoint block.
+type CheckpointVotePacket federation.Vote
+
 func (*StatusPacket) Name() string { return "Status" }
 func (*StatusPacket) Kind() byte   { return StatusMsg }
 
@@ -276,4 +309,7 @@ func (*GetPooledTransactionsPacket) Name() string { return "GetPooledTransaction
 func (*GetPooledTransactionsPacket) Kind() byte   { return GetPooledTransactionsMsg }
 
 func (*PooledTransactionsPacket) Name() string { return "PooledTransactions" }
-func (*PooledTransactionsPacket) Kind() byte   { return PooledTransactionsMsg }
\ No newline at end of file
+func (*PooledTransactionsPacket) Kind() byte   { return PooledTransactionsMsg }
+
+func (*CheckpointVotePacket) Name() string { return "CheckpointVote" }
+func (*CheckpointVotePacket) Kind() byte   { return CheckpointVoteMsg }
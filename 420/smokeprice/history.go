@@ -0,0 +1,123 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package smokeprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// maxHistoryBlocks is the maximum number of blocks a single smoke price history
+// query may span, protecting the node from expensive requests.
+const maxHistoryBlocks = 1024
+
+var (
+	errInvalidPercentile = errors.New("invalid percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+// SmokePriceHistory returns the smoke price percentiles and the ratio of smoke
+// used to smoke limit of up to the given number of consecutive blocks ending at
+// lastBlock, oldest first, along with the number of the oldest block. Fewer blocks
+// are returned if the range would reach beyond the genesis block.
+//
+// The percentiles must be in ascending order between 0 and 100, and are taken
+// over the smoke prices of all the transactions of a block. Empty blocks report
+// zero prices.
+func (gpo *Oracle) SmokePriceHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []float64, error) {
+	if blocks < 1 {
+		return new(big.Int), nil, nil, nil
+	}
+	if blocks > maxHistoryBlocks {
+		return nil, nil, nil, fmt.Errorf("block count too large: %d > %d", blocks, maxHistoryBlocks)
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 || (i > 0 && p < percentiles[i-1]) {
+			return nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+	}
+	// Resolve the last block of the range, pending being served as latest
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return nil, nil, nil, err
+	}
+	last := head.Number.Uint64()
+	if lastBlock >= 0 {
+		if uint64(lastBlock) > last {
+			return nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, last)
+		}
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	var (
+		oldest = last + 1 - uint64(blocks)
+		prices [][]*big.Int
+		ratios = make([]float64, blocks)
+	)
+	if len(percentiles) > 0 {
+		prices = make([][]*big.Int, blocks)
+	}
+	for i := 0; i < blocks; i++ {
+		number := oldest + uint64(i)
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			if err == nil {
+				err = fmt.Errorf("block #%d not found", number)
+			}
+			return nil, nil, nil, err
+		}
+		if limit := block.SmokeLimit(); limit > 0 {
+			ratios[i] = float64(block.SmokeUsed()) / float64(limit)
+		}
+		if prices != nil {
+			prices[i] = blockPercentiles(block, percentiles)
+		}
+	}
+	return new(big.Int).SetUint64(oldest), prices, ratios, nil
+}
+
+// blockPercentiles returns the given percentiles of the smoke prices of the
+// transactions in a block.
+func blockPercentiles(block *types.Block, percentiles []float64) []*big.Int {
+	txs := block.Transactions()
+
+	result := make([]*big.Int, len(percentiles))
+	if len(txs) == 0 {
+		for i := range result {
+			result[i] = new(big.Int)
+		}
+		return result
+	}
+	txPrices := make([]*big.Int, len(txs))
+	for i, tx := range txs {
+		txPrices[i] = tx.SmokePrice()
+	}
+	sort.Sort(bigIntArray(txPrices))
+
+	for i, p := range percentiles {
+		result[i] = txPrices[int(float64(len(txPrices)-1)*p/100)]
+	}
+	return result
+}
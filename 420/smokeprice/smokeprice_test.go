@@ -18,6 +18,7 @@ package smokeprice
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/big"
 	"testing"
@@ -116,3 +117,41 @@ func TestSuggestPrice(t *testing.T) {
 		t.Fatalf("Smoke price mismatch, want %d, got %d", expect, got)
 	}
 }
+
+func TestSmokePriceHistory(t *testing.T) {
+	backend := newTestBackend(t)
+	oracle := NewOracle(backend, Config{Blocks: 3, Percentile: 60, Default: big.NewInt(params.Maher)})
+
+	// Every block contains a single transaction priced at the block number in Maher
+	oldest, prices, ratios, err := oracle.SmokePriceHistory(context.Background(), 3, rpc.LatestBlockNumber, []float64{0, 50, 100})
+	if err != nil {
+		t.Fatalf("Failed to retrieve smoke price history: %v", err)
+	}
+	if oldest.Uint64() != 30 {
+		t.Fatalf("Oldest block mismatch, want 30, got %d", oldest)
+	}
+	if len(prices) != 3 || len(ratios) != 3 {
+		t.Fatalf("History length mismatch, want 3, got %d prices and %d ratios", len(prices), len(ratios))
+	}
+	for i, block := range prices {
+		expect := big.NewInt(params.Maher * int64(30+i))
+		for j, price := range block {
+			if price.Cmp(expect) != 0 {
+				t.Errorf("Block %d percentile %d mismatch, want %d, got %d", 30+i, j, expect, price)
+			}
+		}
+		if header := backend.chain.GetHeaderByNumber(uint64(30 + i)); ratios[i] != float64(header.SmokeUsed)/float64(header.SmokeLimit) {
+			t.Errorf("Block %d smoke used ratio mismatch, got %f", 30+i, ratios[i])
+		}
+	}
+	// Ranges reaching beyond the genesis are truncated, beyond the head rejected
+	if oldest, prices, _, _ = oracle.SmokePriceHistory(context.Background(), 10, 2, nil); oldest.Uint64() != 0 || prices != nil {
+		t.Fatalf("Truncated history mismatch, want oldest 0 without prices, got %d with %v", oldest, prices)
+	}
+	if _, _, _, err := oracle.SmokePriceHistory(context.Background(), 1, 33, nil); !errors.Is(err, errRequestBeyondHead) {
+		t.Fatalf("Error mismatch, want %v, got %v", errRequestBeyondHead, err)
+	}
+	if _, _, _, err := oracle.SmokePriceHistory(context.Background(), 1, rpc.LatestBlockNumber, []float64{50, 10}); !errors.Is(err, errInvalidPercentile) {
+		t.Fatalf("Error mismatch, want %v, got %v", errInvalidPercentile, err)
+	}
+}
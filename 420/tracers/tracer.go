@@ -283,6 +283,25 @@ func (cw *contractWrapper) pushObject(vm *duktape.Context) {
 	vm.PutPropString(obj, "getInput")
 }
 
+// footprintInterval is the number of steps between two measurements of the
+// tracer's memory footprint.
+const footprintInterval = 1024
+
+var (
+	errStepLimit   = errors.New("tracer step limit exceeded")
+	errMemoryLimit = errors.New("tracer memory limit exceeded")
+)
+
+// Limits bounds the resources a tracer may consume, zero meaning no limit.
+//
+// The JavaScript heap itself can't be metered, so the memory limit applies to
+// the JSON encoding of the tracer object's state, measured periodically, and to
+// the size of its result.
+type Limits struct {
+	Steps  uint64 // Maximum number of executed steps the tracer may trace
+	Memory int    // Maximum size in bytes of the tracer's state and result
+}
+
 // Tracer provides an implementation of Tracer that evaluates a Javascript
 // function for each VM execution step.
 type Tracer struct {
@@ -292,6 +311,7 @@ type Tracer struct {
 
 	tracerObject int // Stack index of the tracer JavaScript object
 	stateObject  int // Stack index of the global state to pull arguments from
+	footprintFn  int // Stack index of the function measuring the tracer's state
 
 	opWrapper       *opWrapper       // Wrapper around the VM opcode
 	stackWrapper    *stackWrapper    // Wrapper around the VM stack
@@ -311,6 +331,9 @@ type Tracer struct {
 
 	interrupt uint32 // Atomic flag to signal execution interruption
 	reason    error  // Textual reason for the interruption
+
+	limits Limits // Resource limits of the tracer
+	steps  uint64 // Number of steps traced so far
 }
 
 // New instantiates a new tracer instance. code specifies a Javascript snippet,
@@ -446,6 +469,10 @@ func New(code string) (*Tracer, error) {
 	tracer.vm.EvalString(bigIntegerJS)
 	tracer.vm.PutGlobalString("bigInt")
 
+	// Keep a JSON encoder on the stack, out of the tracer's reach, to meter its state
+	tracer.vm.EvalString("(function(obj) { return JSON.stringify(obj).length; })")
+	tracer.footprintFn = tracer.vm.GetTopIndex()
+
 	// Push the global environment state as object #1 into the JSVM stack
 	tracer.stateObject = tracer.vm.PushObject()
 
@@ -496,6 +523,12 @@ func New(code string) (*Tracer, error) {
 	return tracer, nil
 }
 
+// SetLimits bounds the resources the tracer may consume. Exceeding them aborts
+// the trace with an error.
+func (jst *Tracer) SetLimits(limits Limits) {
+	jst.limits = limits
+}
+
 // Stop terminates execution of the tracer at the first opportune moment.
 func (jst *Tracer) Stop(err error) {
 	jst.reason = err
@@ -521,6 +554,20 @@ func (jst *Tracer) call(method string, args ...string) (json.RawMessage, error)
 	return json.RawMessage(jst.vm.JsonEncode(-1)), nil
 }
 
+// footprint returns the size of the tracer object's state, measured by its JSON
+// encoding.
+func (jst *Tracer) footprint() (int, error) {
+	jst.vm.Dup(jst.footprintFn)
+	jst.vm.Dup(jst.tracerObject)
+	code := jst.vm.Pcall(1)
+	defer jst.vm.Pop()
+
+	if code != 0 {
+		return 0, errors.New(jst.vm.SafeToString(-1))
+	}
+	return jst.vm.GetInt(-1), nil
+}
+
 func wrapError(context string, err error) error {
 	return fmt.Errorf("%v    in server-side tracer function '%v'", err, context)
 }
@@ -553,6 +600,12 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, smoke, cos
 			jst.err = jst.reason
 			return nil
 		}
+		// If the tracer ran out of steps, abort
+		jst.steps++
+		if jst.limits.Steps > 0 && jst.steps > jst.limits.Steps {
+			jst.err = errStepLimit
+			return nil
+		}
 		jst.opWrapper.op = op
 		jst.stackWrapper.stack = stack
 		jst.memoryWrapper.memory = memory
@@ -573,6 +626,17 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, smoke, cos
 		_, err := jst.call("step", "log", "db")
 		if err != nil {
 			jst.err = wrapError("step", err)
+			return nil
+		}
+		// Periodically ensure the tracer's state stays within its memory limit
+		if jst.limits.Memory > 0 && jst.steps%footprintInterval == 0 {
+			size, err := jst.footprint()
+			switch {
+			case err != nil:
+				jst.err = wrapError("footprint", err)
+			case size > jst.limits.Memory:
+				jst.err = errMemoryLimit
+			}
 		}
 	}
 	return nil
@@ -641,6 +705,8 @@ func (jst *Tracer) GetResult() (json.RawMessage, error) {
 	result, err := jst.call("result", "ctx", "db")
 	if err != nil {
 		jst.err = wrapError("result", err)
+	} else if jst.err == nil && jst.limits.Memory > 0 && len(result) > jst.limits.Memory {
+		jst.err = errMemoryLimit
 	}
 	// Clean up the JavaScript environment
 	jst.vm.DestroyHeap()
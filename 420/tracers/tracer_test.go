@@ -178,3 +178,48 @@ func TestHaltBetweenSteps(t *testing.T) {
 		t.Errorf("Expected timeout error, got %v", err)
 	}
 }
+
+// Tests that tracers exceeding their step limit are aborted.
+func TestStepLimit(t *testing.T) {
+	tracer, err := New("{count: 0, step: function() { this.count += 1; }, fault: function() {}, result: function() { return this.count; }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.SetLimits(Limits{Steps: 2})
+
+	if _, err := runTrace(tracer); err != errStepLimit {
+		t.Errorf("step limit error mismatch: have %v, want %v", err, errStepLimit)
+	}
+}
+
+// Tests that tracers accumulating too much state are aborted, both while tracing
+// and when producing their result.
+func TestMemoryLimit(t *testing.T) {
+	const code = "{data: [], step: function() { this.data.push('0123456789abcdef'); }, fault: function() {}, result: function() { return this.data; }}"
+
+	// A short trace is only checked when assembling its result
+	tracer, err := New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.SetLimits(Limits{Memory: 32})
+	if _, err := runTrace(tracer); err != errMemoryLimit {
+		t.Errorf("result memory limit error mismatch: have %v, want %v", err, errMemoryLimit)
+	}
+	// A long trace is aborted as soon as its state is measured over the limit
+	tracer, err = New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.SetLimits(Limits{Memory: 1024})
+
+	env := vm.NewEVM(vm.BlockContext{BlockNumber: big.NewInt(1)}, vm.TxContext{}, &dummyStatedb{}, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	contract := vm.NewContract(&account{}, &account{}, big.NewInt(0), 0)
+	for i := 0; i < footprintInterval; i++ {
+		tracer.CaptureState(env, 0, 0, 0, 0, nil, nil, nil, nil, contract, 0, nil)
+	}
+	if tracer.err != errMemoryLimit {
+		t.Errorf("state memory limit error mismatch: have %v, want %v", tracer.err, errMemoryLimit)
+	}
+	tracer.GetResult()
+}
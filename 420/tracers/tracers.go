@@ -51,3 +51,9 @@ func tracer(name string) (string, bool) {
 	}
 	return "", false
 }
+
+// IsBuiltin returns whether the given name refers to a built in tracer.
+func IsBuiltin(name string) bool {
+	_, ok := all[name]
+	return ok
+}
@@ -0,0 +1,301 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// callTracerName is the built-in tracer whose results are persisted by the
+// trace store.
+const callTracerName = "callTracer"
+
+// traceReorgDepth is the number of recently traced canonical blocks the trace
+// store remembers, bounding how far back it looks for blocks a reorg made
+// canonical without announcing them individually.
+const traceReorgDepth = 1024
+
+// maxTraceTasks is the maximum number of chain events queued up for the tracer.
+// Past it, a new head replaces the last queued one, as tracing a head traces its
+// untraced ancestors too, otherwise the oldest event is discarded. This bounds
+// the queue while the tracer falls behind block import, e.g. during sync.
+const maxTraceTasks = 1024
+
+// traceTask is a block to trace, or to drop the traces of if it was reorged out
+// of the canonical chain.
+type traceTask struct {
+	hash   common.Hash
+	number uint64
+	drop   bool
+}
+
+// traceStore runs the call tracer over every block imported into the canonical
+// chain and persists the results, so that internal transactions can be served
+// without re-executing them on demand. Traces are keyed by block hash and the
+// ones of blocks leaving the canonical chain are dropped.
+type traceStore struct {
+	fourtwenty *Fourtwentycoin
+	debug      *PrivateDebugAPI
+	retention  uint64 // Number of recent blocks to retain traces for (0 = all)
+
+	tasks  []traceTask            // Chain events waiting for the tracer, in arrival order
+	lock   sync.Mutex             // Protects the task queue
+	wake   chan struct{}          // Notifies the tracer of queued tasks
+	traced map[uint64]common.Hash // Recently traced canonical blocks, accessed by the tracer only
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newTraceStore creates a trace store on top of the given 420coin service.
+func newTraceStore(fourtwenty *Fourtwentycoin, retention uint64) *traceStore {
+	return &traceStore{
+		fourtwenty: fourtwenty,
+		debug:      NewPrivateDebugAPI(fourtwenty),
+		retention:  retention,
+		wake:       make(chan struct{}, 1),
+		traced:     make(map[uint64]common.Hash),
+		quit:       make(chan struct{}),
+	}
+}
+
+// start begins tracing newly imported blocks in the background.
+func (s *traceStore) start() {
+	s.wg.Add(2)
+	go s.loop()
+	go s.tracer()
+
+	log.Info("Started call trace store", "retention", s.retention)
+}
+
+// stop terminates the event loop and the tracer.
+func (s *traceStore) stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// loop queues up chain events for the tracer. It never traces itself, so slow
+// tracing doesn't hold up the chain's event feeds and with them block import.
+func (s *traceStore) loop() {
+	defer s.wg.Done()
+
+	var (
+		chainCh  = make(chan core.ChainEvent, 16)
+		sideCh   = make(chan core.ChainSideEvent, 16)
+		chainSub = s.fourtwenty.blockchain.SubscribeChainEvent(chainCh)
+		sideSub  = s.fourtwenty.blockchain.SubscribeChainSideEvent(sideCh)
+	)
+	defer chainSub.Unsubscribe()
+	defer sideSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-chainCh:
+			s.enqueue(traceTask{hash: ev.Block.Hash(), number: ev.Block.NumberU64()})
+		case ev := <-sideCh:
+			s.enqueue(traceTask{hash: ev.Block.Hash(), number: ev.Block.NumberU64(), drop: true})
+		case <-chainSub.Err():
+			return
+		case <-sideSub.Err():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// enqueue schedules a task for the tracer, coalescing or discarding queued ones
+// if the tracer fell too far behind.
+func (s *traceStore) enqueue(task traceTask) {
+	s.lock.Lock()
+	switch last := len(s.tasks) - 1; {
+	case len(s.tasks) < maxTraceTasks:
+		s.tasks = append(s.tasks, task)
+	case !task.drop && !s.tasks[last].drop:
+		s.tasks[last] = task
+	default:
+		log.Warn("Call trace queue full, discarding event", "number", s.tasks[0].number, "hash", s.tasks[0].hash, "drop", s.tasks[0].drop)
+		copy(s.tasks, s.tasks[1:])
+		s.tasks[last] = task
+	}
+	s.lock.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// tracer processes the queued tasks in order until the store is stopped.
+func (s *traceStore) tracer() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.wake:
+		case <-s.quit:
+			return
+		}
+		s.lock.Lock()
+		tasks := s.tasks
+		s.tasks = nil
+		s.lock.Unlock()
+
+		for _, task := range tasks {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			if task.drop {
+				s.drop(task.hash, task.number)
+				continue
+			}
+			head := s.fourtwenty.blockchain.GetBlock(task.hash, task.number)
+			if head == nil {
+				continue
+			}
+			for _, block := range s.untraced(head) {
+				s.trace(block)
+			}
+			s.prune(task.number)
+		}
+	}
+}
+
+// untraced returns the canonical blocks up to and including head which haven't
+// been traced yet, oldest first. Besides head itself, these are the blocks a
+// reorg made canonical, as only the new head of a reorg is announced.
+func (s *traceStore) untraced(head *types.Block) []*types.Block {
+	var (
+		db     = s.fourtwenty.ChainDb()
+		blocks []*types.Block
+	)
+	for block := head; block != nil && len(blocks) < traceReorgDepth; block = s.fourtwenty.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1) {
+		// Stop if the block left the canonical chain in the meantime, the side
+		// event of the reorg drops it and its successors anyway
+		if rawdb.ReadCanonicalHash(db, block.NumberU64()) != block.Hash() {
+			break
+		}
+		// Stop at the first traced ancestor, or right away if nothing was traced
+		// yet, in which case the store starts with the announced block
+		if s.traced[block.NumberU64()] == block.Hash() {
+			break
+		}
+		blocks = append(blocks, block)
+		if len(s.traced) == 0 || block.NumberU64() == 0 {
+			break
+		}
+	}
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	return blocks
+}
+
+// trace runs the call tracer over all the transactions of a block and stores
+// the successfully produced traces.
+func (s *traceStore) trace(block *types.Block) {
+	number := block.NumberU64()
+
+	s.traced[number] = block.Hash()
+	if number >= traceReorgDepth {
+		delete(s.traced, number-traceReorgDepth)
+	}
+
+	if len(block.Transactions()) == 0 {
+		return
+	}
+	tracer := callTracerName
+	results, err := s.debug.traceBlock(context.Background(), block, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		log.Warn("Failed to trace block", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return
+	}
+	db := s.fourtwenty.ChainDb()
+	batch := db.NewBatch()
+	for i, tx := range block.Transactions() {
+		if results[i].Error != "" {
+			log.Debug("Failed to trace transaction", "hash", tx.Hash(), "err", results[i].Error)
+			continue
+		}
+		blob, err := json.Marshal(results[i].Result)
+		if err != nil {
+			log.Debug("Failed to encode call trace", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		rawdb.WriteCallTrace(batch, block.Hash(), tx.Hash(), blob)
+	}
+	if rawdb.ReadCallTraceTail(db) == nil {
+		rawdb.WriteCallTraceTail(batch, number)
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to store call traces", "number", block.Number(), "err", err)
+	}
+}
+
+// drop deletes the traces of a block which was reorged out of the canonical
+// chain.
+func (s *traceStore) drop(hash common.Hash, number uint64) {
+	block := s.fourtwenty.blockchain.GetBlock(hash, number)
+	if block == nil || len(block.Transactions()) == 0 {
+		return
+	}
+	batch := s.fourtwenty.ChainDb().NewBatch()
+	for _, tx := range block.Transactions() {
+		rawdb.DeleteCallTrace(batch, hash, tx.Hash())
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to drop call traces", "number", number, "hash", hash, "err", err)
+	}
+}
+
+// prune deletes the traces of all canonical blocks which fell out of the
+// retention window of the given head.
+func (s *traceStore) prune(head uint64) {
+	if s.retention == 0 || head < s.retention {
+		return
+	}
+	db := s.fourtwenty.ChainDb()
+	tail := rawdb.ReadCallTraceTail(db)
+	limit := head - s.retention + 1
+	if tail == nil || *tail >= limit {
+		return
+	}
+	batch := db.NewBatch()
+	for number := *tail; number < limit; number++ {
+		block := s.fourtwenty.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			continue
+		}
+		for _, tx := range block.Transactions() {
+			rawdb.DeleteCallTrace(batch, block.Hash(), tx.Hash())
+		}
+	}
+	rawdb.WriteCallTraceTail(batch, limit)
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to prune call traces", "tail", limit, "err", err)
+	}
+}
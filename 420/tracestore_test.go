@@ -0,0 +1,140 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that the trace store traces all blocks becoming canonical, including the
+// ones a reorg makes canonical without announcing them, and drops the traces of
+// the blocks reorged out.
+func TestTraceStoreReorg(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &core.Genesis{Config: params.TestChainConfig, Alloc: core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}}}
+		genesis = gspec.MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	store := newTraceStore(&Fourtwentycoin{blockchain: chain, engine: engine, chainDb: db}, 0)
+	store.start()
+	defer store.stop()
+
+	// makeChain creates a chain of blocks on top of the genesis, each containing a
+	// transfer. Chains with different coinbases and amounts don't share blocks or
+	// transactions.
+	makeChain := func(n int, coinbase common.Address, amount int64) []*types.Block {
+		blocks, _ := core.GenerateChain(gspec.Config, genesis, engine, db, n, func(i int, block *core.BlockGen) {
+			block.SetCoinbase(coinbase)
+			tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), common.Address{0xaa}, big.NewInt(amount), params.TxSmoke, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+			block.AddTx(tx)
+		})
+		return blocks
+	}
+	// waitTraces waits until the traces of all transactions in the blocks are
+	// either present or absent.
+	waitTraces := func(blocks []*types.Block, present bool) {
+		t.Helper()
+		for _, block := range blocks {
+			for _, tx := range block.Transactions() {
+				for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+					if (rawdb.ReadCallTrace(db, block.Hash(), tx.Hash()) != nil) == present {
+						break
+					}
+					if time.Now().After(deadline) {
+						t.Fatalf("block #%d: trace presence mismatch for tx %x: want %v", block.NumberU64(), tx.Hash(), present)
+					}
+				}
+			}
+		}
+	}
+	old := makeChain(2, common.Address{0x01}, 1)
+	if _, err := chain.InsertChain(old); err != nil {
+		t.Fatalf("failed to insert original chain: %v", err)
+	}
+	waitTraces(old, true)
+
+	fork := makeChain(3, common.Address{0x02}, 2)
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != fork[2].Hash() {
+		t.Fatalf("chain not reorged to the fork")
+	}
+	waitTraces(fork, true)
+	waitTraces(old, false)
+}
+
+// Tests that the queue of the trace store stays bounded if the tracer falls
+// behind, coalescing new heads and discarding the oldest events otherwise.
+func TestTraceStoreBackPressure(t *testing.T) {
+	store := &traceStore{wake: make(chan struct{}, 1)}
+
+	// Fill the queue with alternating heads and reorged out blocks, these can't
+	// be coalesced
+	for i := 0; i < maxTraceTasks; i++ {
+		store.enqueue(traceTask{hash: common.Hash{byte(i)}, number: uint64(i), drop: i%2 == 0})
+	}
+	if len(store.tasks) != maxTraceTasks {
+		t.Fatalf("queue length mismatch: have %d, want %d", len(store.tasks), maxTraceTasks)
+	}
+	// New heads past the cap should replace the last queued head
+	for i := maxTraceTasks; i < 2*maxTraceTasks; i++ {
+		store.enqueue(traceTask{hash: common.Hash{byte(i)}, number: uint64(i)})
+	}
+	if len(store.tasks) != maxTraceTasks {
+		t.Fatalf("queue length mismatch after heads: have %d, want %d", len(store.tasks), maxTraceTasks)
+	}
+	if first := store.tasks[0]; first.number != 0 {
+		t.Errorf("oldest task discarded by coalescable head: have #%d, want #0", first.number)
+	}
+	if last := store.tasks[maxTraceTasks-1]; last.number != 2*maxTraceTasks-1 || last.drop {
+		t.Errorf("last task mismatch: have #%d (drop %v), want head #%d", last.number, last.drop, 2*maxTraceTasks-1)
+	}
+	// Events which can't be coalesced should discard the oldest ones
+	store.enqueue(traceTask{number: 2 * maxTraceTasks, drop: true})
+	store.enqueue(traceTask{number: 2*maxTraceTasks + 1})
+
+	if len(store.tasks) != maxTraceTasks {
+		t.Fatalf("queue length mismatch after drops: have %d, want %d", len(store.tasks), maxTraceTasks)
+	}
+	if first := store.tasks[0]; first.number != 2 {
+		t.Errorf("oldest task mismatch: have #%d, want #2", first.number)
+	}
+	if last := store.tasks[maxTraceTasks-1]; last.number != 2*maxTraceTasks+1 || last.drop {
+		t.Errorf("last task mismatch: have #%d (drop %v), want head #%d", last.number, last.drop, 2*maxTraceTasks+1)
+	}
+}
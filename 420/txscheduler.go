@@ -0,0 +1,188 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// maxScheduledTxs is the maximum number of transactions held back at once.
+const maxScheduledTxs = 1024
+
+var (
+	errNoScheduleTarget = errors.New("no target block or timestamp")
+	errAlreadyScheduled = errors.New("transaction already scheduled")
+	errTooManyScheduled = errors.New("too many scheduled transactions")
+)
+
+// scheduledTx is a signed transaction held back until the chain reaches a target
+// block number and/or timestamp.
+type scheduledTx struct {
+	Hash        common.Hash        `json:"hash"`
+	Block       hexutil.Uint64     `json:"block,omitempty"`     // Block number from which the transaction is released
+	Time        hexutil.Uint64     `json:"timestamp,omitempty"` // Block timestamp from which the transaction is released
+	Transaction *types.Transaction `json:"transaction"`
+}
+
+// ready returns whether the transaction can be released on top of the given head.
+func (s *scheduledTx) ready(head *types.Header) bool {
+	return head.Number.Uint64() >= uint64(s.Block) && head.Time >= uint64(s.Time)
+}
+
+// txScheduler holds signed transactions back from the transaction pool until the
+// chain reaches their target, for timelock style operations. Scheduled transactions
+// are only kept in memory and are lost on restart.
+type txScheduler struct {
+	chain *core.BlockChain
+	add   func(tx *types.Transaction) error // Injects a released transaction into the pool
+
+	txs  map[common.Hash]*scheduledTx
+	lock sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newTxScheduler creates a transaction scheduler releasing transactions through
+// the given injection function on top of the given chain.
+func newTxScheduler(chain *core.BlockChain, add func(tx *types.Transaction) error) *txScheduler {
+	return &txScheduler{
+		chain: chain,
+		add:   add,
+		txs:   make(map[common.Hash]*scheduledTx),
+		quit:  make(chan struct{}),
+	}
+}
+
+// start begins releasing scheduled transactions as new heads arrive.
+func (s *txScheduler) start() {
+	var (
+		headCh  = make(chan core.ChainHeadEvent, 16)
+		headSub = s.chain.SubscribeChainHeadEvent(headCh)
+	)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer headSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-headCh:
+				s.release(ev.Block.Header())
+			case <-headSub.Err():
+				return
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+}
+
+// stop terminates the release goroutine.
+func (s *txScheduler) stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// schedule holds a transaction back until the chain reaches the given block number
+// and timestamp, a zero value meaning no constraint. Transactions whose target has
+// already been reached are injected right away.
+func (s *txScheduler) schedule(tx *types.Transaction, block, time uint64) error {
+	if block == 0 && time == 0 {
+		return errNoScheduleTarget
+	}
+	scheduled := &scheduledTx{
+		Hash:        tx.Hash(),
+		Block:       hexutil.Uint64(block),
+		Time:        hexutil.Uint64(time),
+		Transaction: tx,
+	}
+	if scheduled.ready(s.chain.CurrentHeader()) {
+		return s.add(tx)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.txs[scheduled.Hash]; ok {
+		return errAlreadyScheduled
+	}
+	if len(s.txs) >= maxScheduledTxs {
+		return errTooManyScheduled
+	}
+	s.txs[scheduled.Hash] = scheduled
+	log.Info("Scheduled transaction", "hash", scheduled.Hash, "block", block, "timestamp", time)
+	return nil
+}
+
+// cancel drops a scheduled transaction, returning whether it was held back.
+func (s *txScheduler) cancel(hash common.Hash) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.txs[hash]
+	delete(s.txs, hash)
+	return ok
+}
+
+// scheduled returns the transactions held back, ordered by target block number
+// and timestamp.
+func (s *txScheduler) scheduled() []*scheduledTx {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	txs := make([]*scheduledTx, 0, len(s.txs))
+	for _, tx := range s.txs {
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].Block != txs[j].Block {
+			return txs[i].Block < txs[j].Block
+		}
+		return txs[i].Time < txs[j].Time
+	})
+	return txs
+}
+
+// release injects the transactions whose target was reached by the given head.
+// Transactions rejected by the pool are dropped.
+func (s *txScheduler) release(head *types.Header) {
+	s.lock.Lock()
+	var ready []*scheduledTx
+	for hash, tx := range s.txs {
+		if tx.ready(head) {
+			ready = append(ready, tx)
+			delete(s.txs, hash)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, tx := range ready {
+		if err := s.add(tx.Transaction); err != nil {
+			log.Warn("Failed to release scheduled transaction", "hash", tx.Hash, "err", err)
+			continue
+		}
+		log.Info("Released scheduled transaction", "hash", tx.Hash, "number", head.Number)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwenty
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that scheduled transactions are held back until the chain reaches their
+// target block number and timestamp, and that cancelled ones are never released.
+func TestTxScheduler(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		genesis = new(core.Genesis).MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, engine, db, 4, nil)
+
+	var released []common.Hash
+	sched := newTxScheduler(chain, func(tx *types.Transaction) error {
+		released = append(released, tx.Hash())
+		return nil
+	})
+	var (
+		byBlock   = types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		byTime    = types.NewTransaction(1, common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		cancelled = types.NewTransaction(2, common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	)
+	if err := sched.schedule(byBlock, 0, 0); err != errNoScheduleTarget {
+		t.Fatalf("untargeted schedule error mismatch: have %v, want %v", err, errNoScheduleTarget)
+	}
+	if err := sched.schedule(byBlock, 2, 0); err != nil {
+		t.Fatalf("failed to schedule by block: %v", err)
+	}
+	if err := sched.schedule(byBlock, 2, 0); err != errAlreadyScheduled {
+		t.Fatalf("duplicate schedule error mismatch: have %v, want %v", err, errAlreadyScheduled)
+	}
+	if err := sched.schedule(byTime, 0, blocks[3].Time()); err != nil {
+		t.Fatalf("failed to schedule by time: %v", err)
+	}
+	if err := sched.schedule(cancelled, 1, 0); err != nil {
+		t.Fatalf("failed to schedule cancelled: %v", err)
+	}
+	if !sched.cancel(cancelled.Hash()) {
+		t.Fatalf("failed to cancel scheduled transaction")
+	}
+	if have := len(sched.scheduled()); have != 2 {
+		t.Fatalf("scheduled count mismatch: have %d, want 2", have)
+	}
+	// Release the transactions block by block and check their ordering
+	for i, block := range blocks {
+		sched.release(block.Header())
+
+		want := 0
+		if i >= 1 {
+			want = 1
+		}
+		if i >= 3 {
+			want = 2
+		}
+		if len(released) != want {
+			t.Fatalf("block %d: released count mismatch: have %d, want %d", i+1, len(released), want)
+		}
+	}
+	if released[0] != byBlock.Hash() || released[1] != byTime.Hash() {
+		t.Errorf("release order mismatch: have %x", released)
+	}
+	if have := len(sched.scheduled()); have != 0 {
+		t.Errorf("transactions left scheduled: %d", have)
+	}
+	// Transactions with a reached target must be released right away
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if err := sched.schedule(cancelled, 1, 0); err != nil {
+		t.Fatalf("failed to schedule reached target: %v", err)
+	}
+	if len(released) != 3 || released[2] != cancelled.Hash() {
+		t.Errorf("reached target not released")
+	}
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/hexutil"
 	"github.com/420integrated/go-420coin/core/types"
-	"github.com/420integrated/go-420coin/rlp"
 	"github.com/420integrated/go-420coin/rpc"
 )
 
@@ -521,7 +520,7 @@ func (ec *Client) EstimateSmoke(ctx context.Context, msg fourtwentycoin.CallMsg)
 // If the transaction was a contract creation use the TransactionReceipt method to get the
 // contract address after the transaction has been mined.
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
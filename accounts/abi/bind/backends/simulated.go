@@ -548,7 +548,7 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call fourtwentycoin
 	evmContext := core.NewEVMBlockContext(block.Header(), b.blockchain, nil)
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
-	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, b.config, vm.Config{})
+	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, b.config, vm.Config{NoBaseFee: true})
 	smokePool := new(core.SmokePool).AddSmoke(math.MaxUint64)
 
 	return core.NewStateTransition(vmEnv, msg, smokePool).TransitionDb()
@@ -716,6 +716,7 @@ func (m callMsg) SmokePrice() *big.Int   { return m.CallMsg.SmokePrice }
 func (m callMsg) Smoke() uint64          { return m.CallMsg.Smoke }
 func (m callMsg) Value() *big.Int      { return m.CallMsg.Value }
 func (m callMsg) Data() []byte         { return m.CallMsg.Data }
+func (m callMsg) AccessList() types.AccessList { return nil }
 
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
@@ -24,6 +24,7 @@ import (
 	"github.com/420integrated/go-420coin"
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
 	"github.com/420integrated/go-420coin/event"
 	"golang.org/x/crypto/sha3"
 )
@@ -199,6 +200,28 @@ func TextAndHash(data []byte) ([]byte, string) {
 	return hasher.Sum(nil), msg
 }
 
+// TextRecover returns the address of the account which signed the given message,
+// hashed with TextHash. The V value of the signature may be either 27 or 28, as
+// produced by fourtwenty_sign and personal_sign, or the raw recovery id 0 or 1.
+func TextRecover(data []byte, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+	}
+	sig = common.CopyBytes(sig)
+	switch sig[crypto.RecoveryIDOffset] {
+	case 27, 28:
+		sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
+	case 0, 1:
+	default:
+		return common.Address{}, fmt.Errorf("invalid signature recovery id %d", sig[crypto.RecoveryIDOffset])
+	}
+	pubkey, err := crypto.SigToPub(TextHash(data), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
 // WalletEventType represents the different event types that can be fired by
 // the wallet subscription subsystem.
 type WalletEventType int
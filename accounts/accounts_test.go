@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/crypto"
 )
 
 func TestTextHash(t *testing.T) {
@@ -30,3 +31,35 @@ func TestTextHash(t *testing.T) {
 		t.Fatalf("wrong hash: %x", hash)
 	}
 }
+
+func TestTextRecover(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	msg := []byte("Hello Joe")
+	sig, err := crypto.Sign(TextHash(msg), key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	// Both the raw recovery id and the legacy V value must be accepted
+	for _, offset := range []byte{0, 27} {
+		legacy := append([]byte{}, sig...)
+		legacy[crypto.RecoveryIDOffset] += offset
+
+		have, err := TextRecover(msg, legacy)
+		if err != nil {
+			t.Fatalf("V offset %d: failed to recover: %v", offset, err)
+		}
+		if have != addr {
+			t.Errorf("V offset %d: address mismatch: have %x, want %x", offset, have, addr)
+		}
+		if legacy[crypto.RecoveryIDOffset] != sig[crypto.RecoveryIDOffset]+offset {
+			t.Errorf("V offset %d: signature modified", offset)
+		}
+	}
+	// A signature over the unprefixed message must not recover the signer
+	raw, _ := crypto.Sign(crypto.Keccak256(msg), key)
+	if have, err := TextRecover(msg, raw); err == nil && have == addr {
+		t.Errorf("unprefixed signature recovered the signer")
+	}
+}
@@ -17,12 +17,18 @@
 package accounts
 
 import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"strings"
+
+	cmath "github.com/420integrated/go-420coin/common/math"
+	"github.com/420integrated/go-420coin/crypto"
 )
 
 // DefaultRootDerivationPath is the root path to which custom derivation endpoints
@@ -179,3 +185,46 @@ func LedgerLiveIterator(base DerivationPath) func() DerivationPath {
 		return path
 	}
 }
+
+// DeriveKey derives the private key at the given BIP-32 path from a master seed,
+// such as the one generated from a BIP-39 mnemonic.
+func DeriveKey(seed []byte, path DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key, err := crypto.ToECDSA(sum[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %v", err)
+	}
+	chainCode := sum[32:]
+
+	curveOrder := crypto.S256().Params().N
+	for i, index := range path {
+		// Hardened children are derived from the private key, normal ones from the public key
+		var data []byte
+		if index >= 0x80000000 {
+			data = append([]byte{0}, crypto.FromECDSA(key)...)
+		} else {
+			data = crypto.CompressPubkey(&key.PublicKey)
+		}
+		data = append(data, byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		// The child key is the parent key tweaked modulo the curve order, the
+		// derivation failing if either is out of range (probability < 2^-127)
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			return nil, fmt.Errorf("invalid child key at %s", path[:i+1])
+		}
+		tweak.Add(tweak, key.D).Mod(tweak, curveOrder)
+		if key, err = crypto.ToECDSA(cmath.PaddedBigBytes(tweak, 32)); err != nil {
+			return nil, fmt.Errorf("invalid child key at %s: %v", path[:i+1], err)
+		}
+		chainCode = sum[32:]
+	}
+	return key, nil
+}
@@ -17,9 +17,13 @@
 package accounts
 
 import (
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
 )
 
 // Tests that HD derivation paths can be correctly parsed into our internal binary
@@ -117,3 +121,29 @@ func TestHdPathIteration(t *testing.T) {
 			"m/44'/60'/8'/0/0", "m/44'/60'/9'/0/0",
 		})
 }
+
+// Tests that private keys are derived according to the BIP-32 test vectors.
+func TestDeriveKey(t *testing.T) {
+	seed := common.FromHex("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+	}
+	for i, tt := range tests {
+		path, err := ParseDerivationPath(tt.path)
+		if err != nil {
+			t.Fatalf("test %d: invalid path: %v", i, err)
+		}
+		key, err := DeriveKey(seed, path)
+		if err != nil {
+			t.Fatalf("test %d: derivation failed: %v", i, err)
+		}
+		if have := hex.EncodeToString(crypto.FromECDSA(key)); have != tt.key {
+			t.Errorf("test %d: key mismatch: have %s, want %s", i, have, tt.key)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/420integrated/go-420coin/accounts"
+	"github.com/420integrated/go-420coin/common"
+)
+
+// BackupVersion is the version of the keystore backup bundles created by Backup.
+const BackupVersion = 1
+
+var (
+	// ErrBackupVersion is returned if a backup bundle was created by an unknown
+	// version of the format.
+	ErrBackupVersion = errors.New("unsupported backup version")
+
+	// ErrBackupCorrupted is returned if the contents of a decrypted backup bundle
+	// are inconsistent.
+	ErrBackupCorrupted = errors.New("backup bundle corrupted")
+)
+
+// backupBundle is the on-disk format of a keystore backup. The key files are
+// encrypted as a whole with the bundle password, the MAC of which guarantees
+// the integrity of the backup.
+type backupBundle struct {
+	Version int        `json:"version"`
+	Created int64      `json:"created"` // Unix time of the backup
+	Crypto  CryptoJSON `json:"crypto"`
+}
+
+// backupKey is a single key file within a backup bundle. The key file is kept
+// in its original encrypted form, so restoring it needs no account password.
+type backupKey struct {
+	Address common.Address  `json:"address"`
+	File    string          `json:"file"`
+	Key     json.RawMessage `json:"key"`
+}
+
+// Backup bundles all the key files of the keystore into a single backup,
+// encrypting it with the given passphrase.
+func (ks *KeyStore) Backup(passphrase string, scryptN, scryptP int) ([]byte, error) {
+	var keys []backupKey
+	for _, account := range ks.Accounts() {
+		blob, err := ioutil.ReadFile(account.URL.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(blob) {
+			return nil, fmt.Errorf("invalid key file %s", account.URL.Path)
+		}
+		keys = append(keys, backupKey{
+			Address: account.Address,
+			File:    filepath.Base(account.URL.Path),
+			Key:     blob,
+		})
+	}
+	payload, err := json.Marshal(keys)
+	if err != nil {
+		return nil, err
+	}
+	cryptoStruct, err := EncryptDataV3(payload, []byte(passphrase), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&backupBundle{
+		Version: BackupVersion,
+		Created: time.Now().Unix(),
+		Crypto:  cryptoStruct,
+	})
+}
+
+// Restore decrypts a backup bundle with the given passphrase and stores its key
+// files in the keystore. Accounts already present in the keystore are skipped.
+// The restored accounts are returned.
+func (ks *KeyStore) Restore(bundle []byte, passphrase string) ([]accounts.Account, error) {
+	var backup backupBundle
+	if err := json.Unmarshal(bundle, &backup); err != nil {
+		return nil, err
+	}
+	if backup.Version != BackupVersion {
+		return nil, fmt.Errorf("%w: %d", ErrBackupVersion, backup.Version)
+	}
+	payload, err := DecryptDataV3(backup.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var keys []backupKey
+	if err := json.Unmarshal(payload, &keys); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBackupCorrupted, err)
+	}
+	// Ensure all key files are consistent before touching the keystore
+	for _, key := range keys {
+		var header struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(key.Key, &header); err != nil {
+			return nil, fmt.Errorf("%w: key %x: %v", ErrBackupCorrupted, key.Address, err)
+		}
+		if common.HexToAddress(header.Address) != key.Address {
+			return nil, fmt.Errorf("%w: key %x has mismatching address %s", ErrBackupCorrupted, key.Address, header.Address)
+		}
+	}
+	ks.importMu.Lock()
+	defer ks.importMu.Unlock()
+
+	var restored []accounts.Account
+	for _, key := range keys {
+		if ks.cache.hasAddress(key.Address) {
+			continue
+		}
+		// Keep the original file name unless it's unsafe or already taken
+		path := ks.storage.JoinPath(key.File)
+		if key.File != filepath.Base(key.File) || strings.HasPrefix(key.File, ".") {
+			path = ks.storage.JoinPath(keyFileName(key.Address))
+		} else if _, err := os.Stat(path); err == nil {
+			path = ks.storage.JoinPath(keyFileName(key.Address))
+		}
+		if err := writeKeyFile(path, key.Key); err != nil {
+			return restored, err
+		}
+		account := accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: KeyStoreScheme, Path: path}}
+		ks.cache.add(account)
+		restored = append(restored, account)
+	}
+	ks.refreshWallets()
+	return restored, nil
+}
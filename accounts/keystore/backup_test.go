@@ -0,0 +1,94 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/420integrated/go-420coin/accounts"
+)
+
+// Tests that a keystore backup can be restored into another keystore, and that
+// wrong passwords, tampered bundles and unknown versions are rejected.
+func TestBackupRestore(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	a1, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, err := ks.NewAccount("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := ks.Backup("backup", veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatalf("failed to create backup: %v", err)
+	}
+	// Restore into an empty keystore and ensure the keys are usable
+	rdir, rks := tmpKeyStore(t, true)
+	defer os.RemoveAll(rdir)
+
+	if _, err := rks.Restore(bundle, "wrong"); err != ErrDecrypt {
+		t.Fatalf("wrong password error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	restored, err := rks.Restore(bundle, "backup")
+	if err != nil {
+		t.Fatalf("failed to restore backup: %v", err)
+	}
+	if len(restored) != 2 {
+		t.Fatalf("restored account count mismatch: have %d, want %d", len(restored), 2)
+	}
+	for _, test := range []struct {
+		account  accounts.Account
+		password string
+	}{{a1, "foo"}, {a2, "bar"}} {
+		if err := rks.Unlock(accounts.Account{Address: test.account.Address}, test.password); err != nil {
+			t.Errorf("failed to unlock restored account %x: %v", test.account.Address, err)
+		}
+	}
+	// Restoring again should skip the existing accounts
+	if restored, err := rks.Restore(bundle, "backup"); err != nil || len(restored) != 0 {
+		t.Fatalf("repeated restore mismatch: have %d accounts (err %v), want none", len(restored), err)
+	}
+	// Tamper with the bundle and ensure it's rejected
+	var backup backupBundle
+	if err := json.Unmarshal(bundle, &backup); err != nil {
+		t.Fatal(err)
+	}
+	if cipher := backup.Crypto.CipherText; cipher[0] == '0' {
+		backup.Crypto.CipherText = "1" + cipher[1:]
+	} else {
+		backup.Crypto.CipherText = "0" + cipher[1:]
+	}
+	tampered, _ := json.Marshal(&backup)
+	if _, err := rks.Restore(tampered, "backup"); err != ErrDecrypt {
+		t.Errorf("tampered bundle error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	// Bump the version and ensure it's rejected
+	json.Unmarshal(bundle, &backup)
+	backup.Version = BackupVersion + 1
+
+	future, _ := json.Marshal(&backup)
+	if _, err := rks.Restore(future, "backup"); !errors.Is(err, ErrBackupVersion) {
+		t.Errorf("future bundle error mismatch: have %v, want %v", err, ErrBackupVersion)
+	}
+}
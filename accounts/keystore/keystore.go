@@ -37,6 +37,7 @@ import (
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/crypto"
 	"github.com/420integrated/go-420coin/event"
+	"github.com/tyler-smith/go-bip39"
 )
 
 var (
@@ -285,7 +286,7 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 	}
 	// Depending on the presence of the chain ID, sign with EIP155 or homestead
 	if chainID != nil {
-		return types.SignTx(tx, types.NewEIP155Signer(chainID), unlockedKey.PrivateKey)
+		return types.SignTx(tx, types.NewEIP2930Signer(chainID), unlockedKey.PrivateKey)
 	}
 	return types.SignTx(tx, types.HomesteadSigner{}, unlockedKey.PrivateKey)
 }
@@ -313,7 +314,7 @@ func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string,
 
 	// Depending on the presence of the chain ID, sign with EIP155 or homestead
 	if chainID != nil {
-		return types.SignTx(tx, types.NewEIP155Signer(chainID), key.PrivateKey)
+		return types.SignTx(tx, types.NewEIP2930Signer(chainID), key.PrivateKey)
 	}
 	return types.SignTx(tx, types.HomesteadSigner{}, key.PrivateKey)
 }
@@ -472,6 +473,21 @@ func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (acco
 	return ks.importKey(key, passphrase)
 }
 
+// ImportMnemonic derives the key at the given BIP-32 path from a BIP-39 mnemonic
+// and its optional seed password, and stores it into the key directory, encrypting
+// it with the passphrase.
+func (ks *KeyStore) ImportMnemonic(mnemonic, seedPassword string, path accounts.DerivationPath, passphrase string) (accounts.Account, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, seedPassword)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	priv, err := accounts.DeriveKey(seed, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return ks.ImportECDSA(priv, passphrase)
+}
+
 func (ks *KeyStore) importKey(key *Key, passphrase string) (accounts.Account, error) {
 	a := accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: KeyStoreScheme, Path: ks.storage.JoinPath(keyFileName(key.Address))}}
 	if err := ks.storage.StoreKey(a.URL.Path, key, passphrase); err != nil {
@@ -362,6 +362,27 @@ func TestImportECDSA(t *testing.T) {
 	}
 }
 
+// TestImportMnemonic tests the import of HD accounts from a BIP-39 mnemonic.
+func TestImportMnemonic(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	acc, err := ks.ImportMnemonic(mnemonic, "", accounts.DefaultBaseDerivationPath, "foo")
+	if err != nil {
+		t.Fatalf("importing failed: %v", err)
+	}
+	if want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"); acc.Address != want {
+		t.Errorf("derived address mismatch: have %x, want %x", acc.Address, want)
+	}
+	if _, err := ks.ImportMnemonic(mnemonic, "", accounts.DefaultBaseDerivationPath, "foo"); err != ErrAccountAlreadyExists {
+		t.Errorf("importing same account twice error mismatch: have %v, want %v", err, ErrAccountAlreadyExists)
+	}
+	if _, err := ks.ImportMnemonic("abandon abandon abandon", "", accounts.DefaultBaseDerivationPath, "foo"); err == nil {
+		t.Errorf("importing invalid mnemonic succeeded")
+	}
+}
+
 // TestImportECDSA tests the import and export functionality of a keystore.
 func TestImportExport(t *testing.T) {
 	dir, ks := tmpKeyStore(t, true)
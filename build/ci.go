@@ -49,6 +49,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -75,6 +76,7 @@ var (
 		executablePath("puppeth"),
 		executablePath("rlpdump"),
 		executablePath("clef"),
+		executablePath("420sign"),
 	}
 
 	// A debian package is created for all executables listed here.
@@ -107,6 +109,10 @@ var (
 			BinaryName:  "clef",
 			Description: "420coin account management tool.",
 		},
+		{
+			BinaryName:  "420sign",
+			Description: "Offline 420coin transaction signer.",
+		},
 	}
 
 	// A debian package is created for all executables listed here.
@@ -285,6 +291,20 @@ func buildFlags(env build.Environment) (flags []string) {
 		ld = append(ld, "-X", "main.gitCommit="+env.Commit)
 		ld = append(ld, "-X", "main.gitDate="+env.Date)
 	}
+	// Embed the build provenance. The build date is taken from SOURCE_DATE_EPOCH
+	// if set, falling back to the commit date, so that builds stay reproducible.
+	if date := buildDate(env); date != "" {
+		ld = append(ld, "-X", "main.buildDate="+date)
+	}
+	if cflags := os.Getenv("CGO_CFLAGS"); cflags != "" {
+		// The go tool splits -ldflags on whitespace, honouring and stripping quotes,
+		// so only quote the definition if the flags would otherwise be split
+		define := "main.cgoFlags=" + cflags
+		if strings.ContainsAny(cflags, " \t\n") {
+			define = "'" + define + "'"
+		}
+		ld = append(ld, "-X", define)
+	}
 	// Strip DWARF on darwin. This used to be required for certain things,
 	// and there is no downside to this, so we just keep doing it.
 	if runtime.GOOS == "darwin" {
@@ -296,6 +316,16 @@ func buildFlags(env build.Environment) (flags []string) {
 	return flags
 }
 
+// buildDate returns the reproducible build date to embed into binaries.
+func buildDate(env build.Environment) string {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if secs, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(secs, 0).UTC().Format("20060102")
+		}
+	}
+	return env.Date
+}
+
 // goTool returns the go tool. This uses the Go version which runs ci.go.
 func goTool(subcmd string, args ...string) *exec.Cmd {
 	cmd := build.GoTool(subcmd, args...)
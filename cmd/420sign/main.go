@@ -0,0 +1,50 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+// 420sign constructs and signs transactions completely offline, for air-gapped
+// cold wallet workflows. The signed transactions are printed as raw RLP, ready
+// to be broadcast later via fourtwenty_sendRawTransaction.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/420integrated/go-420coin/internal/flags"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+var gitDate = ""
+
+var app *cli.App
+
+func init() {
+	app = flags.NewApp(gitCommit, gitDate, "an offline 420coin transaction signer")
+	app.Commands = []cli.Command{
+		commandSignTx,
+		commandInspectTx,
+	}
+	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
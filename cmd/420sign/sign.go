@@ -0,0 +1,267 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/420integrated/go-420coin/accounts/keystore"
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/common/math"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	keyfileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "the keyfile holding the signing key",
+	}
+	passphraseFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "the file that contains the password for the keyfile",
+	}
+	privateKeyFlag = cli.StringFlag{
+		Name:  "privatekey",
+		Usage: "the file that contains the hex encoded raw signing key, instead of a keyfile",
+	}
+	chainIDFlag = cli.StringFlag{
+		Name:  "chainid",
+		Usage: "the chain id of the network the transaction is meant for (replay protection)",
+	}
+	nonceFlag = cli.StringFlag{
+		Name:  "nonce",
+		Usage: "the nonce of the transaction",
+	}
+	toFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "the recipient of the transaction, omit to create a contract",
+	}
+	valueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "the amount to transfer, in marley",
+		Value: "0",
+	}
+	smokeFlag = cli.Uint64Flag{
+		Name:  "smoke",
+		Usage: "the smoke limit of the transaction",
+		Value: params.TxSmoke,
+	}
+	smokePriceFlag = cli.StringFlag{
+		Name:  "smokeprice",
+		Usage: "the smoke price of the transaction, in marley",
+	}
+	dataFlag = cli.StringFlag{
+		Name:  "data",
+		Usage: "the hex encoded input data of the transaction",
+	}
+	jsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "output JSON instead of human-readable format",
+	}
+)
+
+// outputTx is the description of a signed transaction printed by the commands.
+type outputTx struct {
+	Raw     string
+	Hash    string
+	From    string
+	ChainID string
+}
+
+var commandSignTx = cli.Command{
+	Name:  "sign",
+	Usage: "construct and sign a transaction",
+	Description: `
+Construct a transaction from the given fields and sign it with a keyfile, or a
+raw key file given with the --privatekey flag. Nothing is read from or sent to
+the network: the nonce and smoke price must be looked up beforehand, and the
+printed raw transaction can be broadcast from a networked machine via
+fourtwenty_sendRawTransaction.
+
+The chain id is mandatory, signatures being bound to a single network.
+`,
+	Flags: []cli.Flag{
+		keyfileFlag,
+		passphraseFlag,
+		privateKeyFlag,
+		chainIDFlag,
+		nonceFlag,
+		toFlag,
+		valueFlag,
+		smokeFlag,
+		smokePriceFlag,
+		dataFlag,
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		chainID := mustParseBig(ctx, chainIDFlag)
+		if chainID.Sign() <= 0 {
+			utils.Fatalf("Invalid --%s: must be positive", chainIDFlag.Name)
+		}
+		nonce, ok := math.ParseUint64(ctx.String(nonceFlag.Name))
+		if !ok {
+			utils.Fatalf("Missing or invalid --%s", nonceFlag.Name)
+		}
+		var data []byte
+		if input := ctx.String(dataFlag.Name); input != "" {
+			var err error
+			if data, err = hexutil.Decode(input); err != nil {
+				utils.Fatalf("Invalid --%s: %v", dataFlag.Name, err)
+			}
+		}
+		var (
+			value = mustParseBig(ctx, valueFlag)
+			smoke = ctx.Uint64(smokeFlag.Name)
+			price = mustParseBig(ctx, smokePriceFlag)
+			tx    *types.Transaction
+		)
+		if to := ctx.String(toFlag.Name); to != "" {
+			if !common.IsHexAddress(to) {
+				utils.Fatalf("Invalid --%s: %s", toFlag.Name, to)
+			}
+			tx = types.NewTransaction(nonce, common.HexToAddress(to), value, smoke, price, data)
+		} else {
+			if len(data) == 0 {
+				utils.Fatalf("Contract creation without --%s", dataFlag.Name)
+			}
+			tx = types.NewContractCreation(nonce, value, smoke, price, data)
+		}
+		signed, err := types.SignTx(tx, types.NewEIP155Signer(chainID), loadKey(ctx))
+		if err != nil {
+			utils.Fatalf("Failed to sign transaction: %v", err)
+		}
+		printTx(ctx, signed, chainID)
+		return nil
+	},
+}
+
+var commandInspectTx = cli.Command{
+	Name:      "inspect",
+	Usage:     "decode a signed raw transaction",
+	ArgsUsage: "<raw transaction>",
+	Description: `
+Decode a raw transaction, recovering its sender, to double check it before it is
+broadcast.
+`,
+	Flags: []cli.Flag{
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		raw, err := hexutil.Decode(ctx.Args().First())
+		if err != nil {
+			utils.Fatalf("Invalid raw transaction: %v", err)
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(raw, tx); err != nil {
+			utils.Fatalf("Failed to decode transaction: %v", err)
+		}
+		printTx(ctx, tx, tx.ChainId())
+		return nil
+	},
+}
+
+// loadKey loads the signing key from the raw key file if given, or decrypts the
+// keyfile otherwise.
+func loadKey(ctx *cli.Context) *ecdsa.PrivateKey {
+	if file := ctx.String(privateKeyFlag.Name); file != "" {
+		key, err := crypto.LoadECDSA(file)
+		if err != nil {
+			utils.Fatalf("Can't load private key: %v", err)
+		}
+		return key
+	}
+	keyfilepath := ctx.String(keyfileFlag.Name)
+	if keyfilepath == "" {
+		utils.Fatalf("Either --%s or --%s is required", keyfileFlag.Name, privateKeyFlag.Name)
+	}
+	keyjson, err := ioutil.ReadFile(keyfilepath)
+	if err != nil {
+		utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+	}
+	key, err := keystore.DecryptKey(keyjson, getPassphrase(ctx))
+	if err != nil {
+		utils.Fatalf("Error decrypting key: %v", err)
+	}
+	return key.PrivateKey
+}
+
+// getPassphrase obtains the keyfile passphrase from the --passwordfile flag, or
+// prompts the user for it.
+func getPassphrase(ctx *cli.Context) string {
+	if file := ctx.String(passphraseFlag.Name); file != "" {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read password file '%s': %v", file, err)
+		}
+		return strings.TrimRight(string(content), "\r\n")
+	}
+	return utils.GetPassPhrase("", false)
+}
+
+// mustParseBig parses the decimal or hex number given to a flag.
+func mustParseBig(ctx *cli.Context, flag cli.StringFlag) *big.Int {
+	value, ok := math.ParseBig256(ctx.String(flag.Name))
+	if !ok || ctx.String(flag.Name) == "" {
+		utils.Fatalf("Missing or invalid --%s", flag.Name)
+	}
+	return value
+}
+
+// printTx prints a signed transaction along with its sender.
+func printTx(ctx *cli.Context, tx *types.Transaction, chainID *big.Int) {
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		utils.Fatalf("Failed to encode transaction: %v", err)
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(chainID)
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		utils.Fatalf("Failed to recover sender: %v", err)
+	}
+	out := outputTx{
+		Raw:     hexutil.Encode(raw),
+		Hash:    tx.Hash().Hex(),
+		From:    from.Hex(),
+		ChainID: chainID.String(),
+	}
+	if ctx.Bool(jsonFlag.Name) {
+		str, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to marshal JSON object: %v", err)
+		}
+		fmt.Println(string(str))
+		return
+	}
+	fmt.Println("Raw transaction:", out.Raw)
+	fmt.Println("Hash:           ", out.Hash)
+	fmt.Println("From:           ", out.From)
+	fmt.Println("Chain id:       ", out.ChainID)
+}
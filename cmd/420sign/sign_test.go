@@ -0,0 +1,76 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/rlp"
+)
+
+func TestSignInspect(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "420sign-test")
+	if err != nil {
+		t.Fatal("Can't create temporary directory:", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	key, _ := crypto.GenerateKey()
+	keyfile := filepath.Join(tmpdir, "the-key")
+	if err := crypto.SaveECDSA(keyfile, key); err != nil {
+		t.Fatal("Can't save private key:", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Sign a transfer offline
+	sign := run420sign(t, "sign", "--privatekey", keyfile, "--chainid", "2020", "--nonce", "7",
+		"--to", "0x0000000000000000000000000000000000000420", "--value", "1000", "--smokeprice", "0x3b9aca00")
+	_, matches := sign.ExpectRegexp(`Raw transaction: (0x[0-9a-f]+)\n`)
+	sign.ExpectExit()
+
+	raw, err := hexutil.Decode(matches[1])
+	if err != nil {
+		t.Fatalf("invalid raw transaction: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	from, err := types.Sender(types.NewEIP155Signer(big.NewInt(2020)), tx)
+	if err != nil || from != address {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", from, err, address)
+	}
+	if tx.Nonce() != 7 || *tx.To() != common.HexToAddress("0x420") || tx.Value().Cmp(big.NewInt(1000)) != 0 || tx.SmokePrice().Cmp(big.NewInt(1000000000)) != 0 {
+		t.Fatalf("transaction fields mismatch: %v", tx)
+	}
+	// Inspect it back
+	inspect := run420sign(t, "inspect", matches[1])
+	_, found := inspect.ExpectRegexp(`From: +(0x[0-9a-fA-F]{40})\n`)
+	inspect.ExpectExit()
+
+	if common.HexToAddress(found[1]) != address {
+		t.Errorf("inspected sender mismatch: have %s, want %x", found[1], address)
+	}
+}
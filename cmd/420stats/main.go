@@ -0,0 +1,106 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+// 420stats is a monitoring server collecting live reports from 420coin nodes.
+package main
+
+import (
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/420db/leveldb"
+	"github.com/420integrated/go-420coin/420db/memorydb"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+var (
+	listenFlag = flag.String("addr", ":3000", "Listener address for node reports and REST queries")
+	secretFlag = flag.String("secret", "", "Shared secret nodes must authenticate with")
+	dbFlag     = flag.String("db", "", "Database directory to persist node states into (empty = in memory)")
+	ipcFlag    = flag.String("ipc", "", "IPC endpoint to expose the query API on (empty = disabled)")
+	logFlag    = flag.Int("loglevel", 3, "Log level to use for the stats server")
+)
+
+// statsAPI exposes the collected node reports through the RPC query interface.
+type statsAPI struct {
+	server *server
+}
+
+// Nodes returns the latest state of all known nodes.
+func (api *statsAPI) Nodes() []*nodeState {
+	return api.server.Nodes()
+}
+
+// Node returns the latest state of the node with the given identifier.
+func (api *statsAPI) Node(id string) (*nodeState, error) {
+	return api.server.Node(id)
+}
+
+func main() {
+	// Parse the flags and set up the logger to print everything requested
+	flag.Parse()
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(*logFlag), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	if *secretFlag == "" {
+		log.Crit("No shared secret specified (--secret)")
+	}
+	// Open the persistent store and restore any previous node states
+	var db fourtwentydb.KeyValueStore = memorydb.New()
+	if *dbFlag != "" {
+		ldb, err := leveldb.New(*dbFlag, 16, 16, "420stats/db/")
+		if err != nil {
+			log.Crit("Failed to open database", "dir", *dbFlag, "err", err)
+		}
+		db = ldb
+	}
+	defer db.Close()
+
+	server, err := newServer(*secretFlag, db)
+	if err != nil {
+		log.Crit("Failed to restore node states", "err", err)
+	}
+	// Expose the query API over IPC if requested
+	if *ipcFlag != "" {
+		apis := []rpc.API{{Namespace: "stats", Version: "1.0", Service: &statsAPI{server}, Public: true}}
+		listener, handler, err := rpc.StartIPCEndpoint(*ipcFlag, apis)
+		if err != nil {
+			log.Crit("Failed to start IPC endpoint", "endpoint", *ipcFlag, "err", err)
+		}
+		defer handler.Stop()
+		defer listener.Close()
+		log.Info("IPC endpoint opened", "url", *ipcFlag)
+	}
+	// Start accepting node reports and REST queries
+	listener, err := net.Listen("tcp", *listenFlag)
+	if err != nil {
+		log.Crit("Failed to start stats listener", "addr", *listenFlag, "err", err)
+	}
+	log.Info("Stats server started", "addr", listener.Addr())
+	go http.Serve(listener, server)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+
+	log.Info("Stats server shutting down")
+	listener.Close()
+}
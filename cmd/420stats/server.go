@@ -0,0 +1,323 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/gorilla/websocket"
+)
+
+// nodeKeyPrefix is the database prefix under which node states are persisted.
+var nodeKeyPrefix = []byte("node-")
+
+// nodeKey = nodeKeyPrefix + id
+func nodeKey(id string) []byte {
+	return append(append([]byte{}, nodeKeyPrefix...), id...)
+}
+
+// nodeState is the latest known state of a single reporting node.
+type nodeState struct {
+	ID       string          `json:"id"`
+	Info     json.RawMessage `json:"info"`
+	Block    json.RawMessage `json:"block,omitempty"`
+	Stats    json.RawMessage `json:"stats,omitempty"`
+	Pending  int             `json:"pending"`
+	Latency  string          `json:"latency"`
+	Online   bool            `json:"online"`
+	LastSeen time.Time       `json:"lastSeen"`
+}
+
+// server is a 420stats monitoring server, collecting reports from nodes over
+// websockets and exposing them through a query API.
+type server struct {
+	secret   string                     // Shared secret the nodes authenticate with
+	db       fourtwentydb.KeyValueStore // Persistent store of the node states
+	upgrader websocket.Upgrader
+
+	nodes map[string]*nodeState // Latest state of all known nodes
+	lock  sync.RWMutex          // Protects the node states
+}
+
+// newServer creates a stats server, restoring previously reported node states
+// from the database.
+func newServer(secret string, db fourtwentydb.KeyValueStore) (*server, error) {
+	s := &server{
+		secret: secret,
+		db:     db,
+		nodes:  make(map[string]*nodeState),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+	it := db.NewIterator(nodeKeyPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		node := new(nodeState)
+		if err := json.Unmarshal(it.Value(), node); err != nil {
+			log.Warn("Dropping corrupt node state", "key", string(it.Key()), "err", err)
+			continue
+		}
+		node.Online = false // Connections don't survive a restart
+		s.nodes[node.ID] = node
+	}
+	return s, it.Error()
+}
+
+// ServeHTTP routes websocket ingest requests and REST queries.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/api":
+		s.serveIngest(w, r)
+	case r.URL.Path == "/nodes":
+		s.respond(w, s.Nodes())
+	case strings.HasPrefix(r.URL.Path, "/nodes/"):
+		node, err := s.Node(strings.TrimPrefix(r.URL.Path, "/nodes/"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		s.respond(w, node)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// respond writes a JSON encoded query result.
+func (s *server) respond(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Warn("Failed to send query response", "err", err)
+	}
+}
+
+// Nodes returns the latest state of all known nodes, ordered by identifier.
+func (s *server) Nodes() []*nodeState {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	nodes := make([]*nodeState, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		copy := *node
+		nodes = append(nodes, &copy)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Node returns the latest state of a single node.
+func (s *server) Node(id string) (*nodeState, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	node, ok := s.nodes[id]
+	if !ok {
+		return nil, errors.New("unknown node")
+	}
+	copy := *node
+	return &copy, nil
+}
+
+// serveIngest upgrades a connection to a websocket and processes the reports
+// of a single node until the connection breaks.
+func (s *server) serveIngest(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("Failed to upgrade stats connection", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// The first message must be an authenticated login
+	id, err := s.login(conn)
+	if err != nil {
+		log.Warn("Stats login failed", "addr", r.RemoteAddr, "err", err)
+		return
+	}
+	log.Info("Node connected", "id", id, "addr", r.RemoteAddr)
+	defer func() {
+		s.update(id, func(node *nodeState) { node.Online = false })
+		log.Info("Node disconnected", "id", id)
+	}()
+
+	for {
+		command, payload, err := readEmit(conn)
+		if err != nil {
+			log.Debug("Failed to read stats report", "id", id, "err", err)
+			return
+		}
+		if err := s.handle(conn, id, command, payload); err != nil {
+			log.Warn("Failed to handle stats report", "id", id, "command", command, "err", err)
+			return
+		}
+	}
+}
+
+// login authenticates a freshly connected node and registers it.
+func (s *server) login(conn *websocket.Conn) (string, error) {
+	command, payload, err := readEmit(conn)
+	if err != nil {
+		return "", err
+	}
+	if command != "hello" {
+		return "", errors.New("missing login")
+	}
+	var auth struct {
+		ID     string          `json:"id"`
+		Info   json.RawMessage `json:"info"`
+		Secret string          `json:"secret"`
+	}
+	if err := json.Unmarshal(payload, &auth); err != nil {
+		return "", err
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.Secret), []byte(s.secret)) != 1 {
+		return "", errors.New("invalid secret")
+	}
+	if auth.ID == "" {
+		return "", errors.New("missing node id")
+	}
+	s.update(auth.ID, func(node *nodeState) {
+		node.Info = auth.Info
+		node.Online = true
+	})
+	return auth.ID, writeEmit(conn, "ready")
+}
+
+// handle processes a single report of an authenticated node.
+func (s *server) handle(conn *websocket.Conn, id string, command string, payload json.RawMessage) error {
+	switch command {
+	case "node-ping":
+		return writeEmit(conn, "node-pong", payload)
+
+	case "latency":
+		var report struct {
+			Latency string `json:"latency"`
+		}
+		if err := json.Unmarshal(payload, &report); err != nil {
+			return err
+		}
+		s.update(id, func(node *nodeState) { node.Latency = report.Latency })
+
+	case "block":
+		var report struct {
+			Block json.RawMessage `json:"block"`
+		}
+		if err := json.Unmarshal(payload, &report); err != nil {
+			return err
+		}
+		s.update(id, func(node *nodeState) { node.Block = report.Block })
+
+	case "pending":
+		var report struct {
+			Stats struct {
+				Pending int `json:"pending"`
+			} `json:"stats"`
+		}
+		if err := json.Unmarshal(payload, &report); err != nil {
+			return err
+		}
+		s.update(id, func(node *nodeState) { node.Pending = report.Stats.Pending })
+
+	case "stats":
+		var report struct {
+			Stats json.RawMessage `json:"stats"`
+		}
+		if err := json.Unmarshal(payload, &report); err != nil {
+			return err
+		}
+		s.update(id, func(node *nodeState) { node.Stats = report.Stats })
+
+	case "history":
+		// Historical blocks are only needed by charting frontends, drop them
+	default:
+		log.Debug("Unknown stats report", "id", id, "command", command)
+	}
+	return nil
+}
+
+// update modifies the state of a node and persists the result.
+func (s *server) update(id string, fn func(node *nodeState)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	node, ok := s.nodes[id]
+	if !ok {
+		node = &nodeState{ID: id}
+		s.nodes[id] = node
+	}
+	fn(node)
+	node.LastSeen = time.Now()
+
+	blob, err := json.Marshal(node)
+	if err != nil {
+		log.Error("Failed to encode node state", "id", id, "err", err)
+		return
+	}
+	if err := s.db.Put(nodeKey(id), blob); err != nil {
+		log.Error("Failed to persist node state", "id", id, "err", err)
+	}
+}
+
+// readEmit reads the next broadcast message from a node, skipping over any
+// system level ping-pong messages.
+func readEmit(conn *websocket.Conn) (string, json.RawMessage, error) {
+	for {
+		var blob json.RawMessage
+		if err := conn.ReadJSON(&blob); err != nil {
+			return "", nil, err
+		}
+		var system string
+		if err := json.Unmarshal(blob, &system); err == nil {
+			continue
+		}
+		var msg struct {
+			Emit []json.RawMessage `json:"emit"`
+		}
+		if err := json.Unmarshal(blob, &msg); err != nil {
+			return "", nil, err
+		}
+		if len(msg.Emit) == 0 {
+			return "", nil, errors.New("non-broadcast message")
+		}
+		var command string
+		if err := json.Unmarshal(msg.Emit[0], &command); err != nil {
+			return "", nil, err
+		}
+		var payload json.RawMessage
+		if len(msg.Emit) > 1 {
+			payload = msg.Emit[1]
+		}
+		return command, payload, nil
+	}
+}
+
+// writeEmit sends a broadcast message to a node.
+func writeEmit(conn *websocket.Conn, command string, args ...interface{}) error {
+	return conn.WriteJSON(map[string][]interface{}{
+		"emit": append([]interface{}{command}, args...),
+	})
+}
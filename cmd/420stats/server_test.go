@@ -0,0 +1,97 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/420integrated/go-420coin/420db/memorydb"
+	"github.com/gorilla/websocket"
+)
+
+// Tests that nodes need to authenticate and their reports are tracked and
+// persisted across server restarts.
+func TestServerIngest(t *testing.T) {
+	db := memorydb.New()
+	srv, err := newServer("secret", db)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	url := "ws" + strings.TrimPrefix(httpsrv.URL, "http") + "/api"
+
+	// Ensure invalid secrets are rejected
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	if err := writeEmit(conn, "hello", map[string]string{"id": "node", "secret": "invalid"}); err != nil {
+		t.Fatalf("failed to send login: %v", err)
+	}
+	if _, _, err := readEmit(conn); err == nil {
+		t.Fatalf("unauthorized login accepted")
+	}
+	conn.Close()
+
+	// Log in properly and report a block
+	conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := writeEmit(conn, "hello", map[string]string{"id": "node", "secret": "secret"}); err != nil {
+		t.Fatalf("failed to send login: %v", err)
+	}
+	if command, _, err := readEmit(conn); err != nil || command != "ready" {
+		t.Fatalf("login ack mismatch: have %q, %v; want %q", command, err, "ready")
+	}
+	if err := writeEmit(conn, "block", map[string]interface{}{"id": "node", "block": map[string]int{"number": 420}}); err != nil {
+		t.Fatalf("failed to send block report: %v", err)
+	}
+	// Round trip a ping to ensure the block report was processed
+	if err := writeEmit(conn, "node-ping", map[string]string{"id": "node"}); err != nil {
+		t.Fatalf("failed to send ping: %v", err)
+	}
+	if command, _, err := readEmit(conn); err != nil || command != "node-pong" {
+		t.Fatalf("ping reply mismatch: have %q, %v; want %q", command, err, "node-pong")
+	}
+	node, err := srv.Node("node")
+	if err != nil {
+		t.Fatalf("reporting node not tracked: %v", err)
+	}
+	if !node.Online {
+		t.Errorf("reporting node not online")
+	}
+	var block struct{ Number int }
+	if err := json.Unmarshal(node.Block, &block); err != nil || block.Number != 420 {
+		t.Errorf("reported block mismatch: have %s, want number 420", node.Block)
+	}
+	// Restart the server and ensure the state is restored
+	restored, err := newServer("secret", db)
+	if err != nil {
+		t.Fatalf("failed to restore server: %v", err)
+	}
+	if nodes := restored.Nodes(); len(nodes) != 1 || nodes[0].Online || string(nodes[0].Block) != string(node.Block) {
+		t.Errorf("restored node mismatch: have %+v", nodes)
+	}
+}
@@ -197,6 +197,22 @@ The setpw command stores a password for a given address (keyfile).
 		Description: `
 The delpw command removes a password for a given address (keyfile).
 `}
+	rotateSeedCommand = cli.Command{
+		Action:    utils.MigrateFlags(rotateSeed),
+		Name:      "rotateseed",
+		Usage:     "Replace the master seed and re-encrypt the stored credentials",
+		ArgsUsage: "",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+			signerSecretFlag,
+			utils.LightKDFFlag,
+		},
+		Description: `
+The rotateseed command generates a new master seed, protected by a new password, and
+re-encrypts the stored credentials, rule storage and attestations with it. The previous
+seed is kept with an '.old' suffix until removed manually.`,
+	}
 	newAccountCommand = cli.Command{
 		Action:    utils.MigrateFlags(newAccount),
 		Name:      "newaccount",
@@ -290,6 +306,7 @@ func init() {
 		attestCommand,
 		setCredentialCommand,
 		delCredentialCommand,
+		rotateSeedCommand,
 		newAccountCommand,
 		gendocCommand}
 	cli.CommandHelpTemplate = flags.CommandHelpTemplate
@@ -351,13 +368,46 @@ func initializeSecrets(c *cli.Context) error {
 		return fmt.Errorf("master key %v already exists, will not overwrite", location)
 	}
 	// Key file does not exist yet, generate a new one and encrypt it
+	_, cipherSeed, err := generateSeed(c)
+	if err != nil {
+		return err
+	}
+	// Double check the master key path to ensure nothing wrote there in between
+	if err = os.Mkdir(configDir, 0700); err != nil && !os.IsExist(err) {
+		return err
+	}
+	if _, err := os.Stat(location); err == nil {
+		return fmt.Errorf("master key %v already exists, will not overwrite", location)
+	}
+	// Write the file and print the usual warning message
+	if err = ioutil.WriteFile(location, cipherSeed, 0400); err != nil {
+		return err
+	}
+	fmt.Printf("A master seed has been generated into %s\n", location)
+	fmt.Printf(`
+This is required to be able to store credentials, such as:
+* Passwords for keystores (used by rule engine)
+* Storage for JavaScript auto-signing rules
+* Hash of JavaScript rule-file
+
+You should treat 'masterseed.json' with utmost secrecy and make a backup of it!
+* The password is necessary but not enough, you need to back up the master seed too!
+* The master seed does not contain your accounts, those need to be backed up separately!
+
+`)
+	return nil
+}
+
+// generateSeed creates a new random master seed and encrypts it with a password
+// requested from the user.
+func generateSeed(c *cli.Context) ([]byte, []byte, error) {
 	masterSeed := make([]byte, 256)
 	num, err := io.ReadFull(rand.Reader, masterSeed)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	if num != len(masterSeed) {
-		return fmt.Errorf("failed to read enough random")
+		return nil, nil, fmt.Errorf("failed to read enough random")
 	}
 	n, p := keystore.StandardScryptN, keystore.StandardScryptP
 	if c.GlobalBool(utils.LightKDFFlag.Name) {
@@ -376,33 +426,84 @@ func initializeSecrets(c *cli.Context) error {
 	}
 	cipherSeed, err := encryptSeed(masterSeed, []byte(password), n, p)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt master seed: %v", err)
+		return nil, nil, fmt.Errorf("failed to encrypt master seed: %v", err)
 	}
-	// Double check the master key path to ensure nothing wrote there in between
-	if err = os.Mkdir(configDir, 0700); err != nil && !os.IsExist(err) {
+	return masterSeed, cipherSeed, nil
+}
+
+// rotateSeed replaces the master seed with a freshly generated one and moves
+// all stored credentials, rule storage and attestations into the vault of the
+// new seed.
+func rotateSeed(c *cli.Context) error {
+	if err := initialize(c); err != nil {
 		return err
 	}
-	if _, err := os.Stat(location); err == nil {
-		return fmt.Errorf("master key %v already exists, will not overwrite", location)
+	location := filepath.Join(c.GlobalString(configdirFlag.Name), "masterseed.json")
+	if c.GlobalIsSet(signerSecretFlag.Name) {
+		location = c.GlobalString(signerSecretFlag.Name)
 	}
-	// Write the file and print the usual warning message
-	if err = ioutil.WriteFile(location, cipherSeed, 0400); err != nil {
+	oldSeed, err := readMasterKey(c, nil)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	newSeed, cipherSeed, err := generateSeed(c)
+	if err != nil {
 		return err
 	}
-	fmt.Printf("A master seed has been generated into %s\n", location)
+	oldVault, newVault, err := migrateVault(c.GlobalString(configdirFlag.Name), oldSeed, newSeed)
+	if err != nil {
+		return err
+	}
+	if err := swapSeed(location, cipherSeed); err != nil {
+		return err
+	}
+	log.Info("Master seed rotated", "seed", location, "vault", newVault)
 	fmt.Printf(`
-This is required to be able to store credentials, such as:
-* Passwords for keystores (used by rule engine)
-* Storage for JavaScript auto-signing rules
-* Hash of JavaScript rule-file
+The master seed has been rotated and the vault was migrated to %s.
 
-You should treat 'masterseed.json' with utmost secrecy and make a backup of it!
-* The password is necessary but not enough, you need to back up the master seed too!
-* The master seed does not contain your accounts, those need to be backed up separately!
+Once the signer is confirmed to work with the new seed, remove the previous
+seed %s and the previous vault %s.
+Do not forget to back up the new master seed!
 
-`)
+`, newVault, location+".old", oldVault)
 	return nil
 }
+
+// migrateVault re-encrypts every domain specific storage of the vault belonging
+// to the old seed into a new vault belonging to the new seed, returning the
+// locations of both vaults.
+func migrateVault(configDir string, oldSeed, newSeed []byte) (string, string, error) {
+	oldVault := filepath.Join(configDir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), oldSeed)[:10]))
+	newVault := filepath.Join(configDir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), newSeed)[:10]))
+	if err := os.Mkdir(newVault, 0700); err != nil {
+		return "", "", err
+	}
+	for _, domain := range []string{"credentials", "jsstorage", "config"} {
+		file := domain + ".json"
+		if _, err := os.Stat(filepath.Join(oldVault, file)); os.IsNotExist(err) {
+			continue
+		}
+		src := storage.NewAESEncryptedStorage(filepath.Join(oldVault, file), crypto.Keccak256([]byte(domain), oldSeed))
+		dst := storage.NewAESEncryptedStorage(filepath.Join(newVault, file), crypto.Keccak256([]byte(domain), newSeed))
+		if err := src.CopyTo(dst); err != nil {
+			return "", "", fmt.Errorf("failed to migrate %s: %v", domain, err)
+		}
+	}
+	return oldVault, newVault, nil
+}
+
+// swapSeed replaces the encrypted master seed at location with the given one,
+// keeping the previous seed around with an '.old' suffix.
+func swapSeed(location string, cipherSeed []byte) error {
+	if err := ioutil.WriteFile(location+".new", cipherSeed, 0400); err != nil {
+		return err
+	}
+	if err := os.Rename(location, location+".old"); err != nil {
+		return err
+	}
+	return os.Rename(location+".new", location)
+}
+
 func attestFile(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
@@ -0,0 +1,109 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/420integrated/go-420coin/accounts/keystore"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/signer/storage"
+)
+
+// Tests that rotating the master seed moves the stored credentials and
+// attestations into the vault of the new seed and swaps the seed files.
+func TestRotateSeed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clef-rotateseed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldSeed := bytes.Repeat([]byte{0x01}, 256)
+	newSeed := bytes.Repeat([]byte{0x02}, 256)
+
+	// Populate the vault of the old seed, leaving the rule storage absent
+	oldVault := filepath.Join(dir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), oldSeed)[:10]))
+	if err := os.Mkdir(oldVault, 0700); err != nil {
+		t.Fatal(err)
+	}
+	storage.NewAESEncryptedStorage(filepath.Join(oldVault, "credentials.json"), crypto.Keccak256([]byte("credentials"), oldSeed)).Put("0x01", "secret")
+	storage.NewAESEncryptedStorage(filepath.Join(oldVault, "config.json"), crypto.Keccak256([]byte("config"), oldSeed)).Put("ruleset_sha256", "abcd")
+
+	vault, newVault, err := migrateVault(dir, oldSeed, newSeed)
+	if err != nil {
+		t.Fatalf("failed to migrate vault: %v", err)
+	}
+	if vault != oldVault {
+		t.Fatalf("old vault mismatch: have %s, want %s", vault, oldVault)
+	}
+	for _, want := range []struct{ domain, key, value string }{
+		{"credentials", "0x01", "secret"},
+		{"config", "ruleset_sha256", "abcd"},
+	} {
+		store := storage.NewAESEncryptedStorage(filepath.Join(newVault, want.domain+".json"), crypto.Keccak256([]byte(want.domain), newSeed))
+		if have, err := store.Get(want.key); err != nil || have != want.value {
+			t.Errorf("%s: migrated value mismatch: have %q (%v), want %q", want.domain, have, err, want.value)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(newVault, "jsstorage.json")); !os.IsNotExist(err) {
+		t.Errorf("absent rule storage created in new vault: %v", err)
+	}
+	// Migrating into an existing vault must be refused
+	if _, _, err := migrateVault(dir, oldSeed, newSeed); err == nil {
+		t.Errorf("migration overwrote an existing vault")
+	}
+	// Swap the seeds and check both remain decryptable with their passwords
+	location := filepath.Join(dir, "masterseed.json")
+	oldCipher, err := encryptSeed(oldSeed, []byte("old password"), keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to encrypt old seed: %v", err)
+	}
+	if err := ioutil.WriteFile(location, oldCipher, 0400); err != nil {
+		t.Fatal(err)
+	}
+	newCipher, err := encryptSeed(newSeed, []byte("new password"), keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to encrypt new seed: %v", err)
+	}
+	if err := swapSeed(location, newCipher); err != nil {
+		t.Fatalf("failed to swap seeds: %v", err)
+	}
+	for _, want := range []struct {
+		path, password string
+		seed           []byte
+	}{
+		{location, "new password", newSeed},
+		{location + ".old", "old password", oldSeed},
+	} {
+		blob, err := ioutil.ReadFile(want.path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", want.path, err)
+		}
+		if seed, err := decryptSeed(blob, want.password); err != nil || !bytes.Equal(seed, want.seed) {
+			t.Errorf("%s: seed mismatch (%v)", want.path, err)
+		}
+	}
+	if _, err := os.Stat(location + ".new"); !os.IsNotExist(err) {
+		t.Errorf("temporary seed file left behind: %v", err)
+	}
+}
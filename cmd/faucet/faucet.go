@@ -212,10 +212,10 @@ type faucet struct {
 	nonce    uint64             // Current pending nonce of the faucet
 	price    *big.Int           // Current smoke price to issue funds with
 
-	conns    []*websocket.Conn    // Currently live websocket connections
-	timeouts map[string]time.Time // History of users and their funding timeouts
-	reqs     []*request           // Currently pending funding requests
-	update   chan struct{}        // Channel to signal request updates
+	conns   []*websocket.Conn // Currently live websocket connections
+	limiter *fundLimiter      // Funding timeouts of attested users and addresses
+	reqs    []*request        // Currently pending funding requests
+	update  chan struct{}     // Channel to signal request updates
 
 	lock sync.RWMutex // Lock protecting the faucet's internals
 }
@@ -281,7 +281,7 @@ func newFaucet(genesis *core.Genesis, port int, enodes []*discv5.Node, network u
 		index:    index,
 		keystore: ks,
 		account:  ks.Accounts()[0],
-		timeouts: make(map[string]time.Time),
+		limiter:  newFundLimiter(time.Duration(*minutesFlag) * time.Minute),
 		update:   make(chan struct{}, 1),
 	}, nil
 }
@@ -446,6 +446,7 @@ func (f *faucet) apiHandler(w http.ResponseWriter, r *http.Request) {
 		// Retrieve the 420coin address to fund, the requesting user and a profile picture
 		var (
 			id       string
+			provider string
 			username string
 			avatar   string
 			address  common.Address
@@ -466,12 +467,13 @@ func (f *faucet) apiHandler(w http.ResponseWriter, r *http.Request) {
 			continue
 		case strings.HasPrefix(msg.URL, "https://twitter.com/"):
 			id, username, avatar, address, err = authTwitter(msg.URL, *twitterBearerToken)
+			provider = "twitter"
 		case strings.HasPrefix(msg.URL, "https://www.facebook.com/"):
 			username, avatar, address, err = authFacebook(msg.URL)
-			id = username
+			id, provider = username, "facebook"
 		case *noauthFlag:
 			username, avatar, address, err = authNoAuth(msg.URL)
-			id = username
+			id, provider = username, "noauth"
 		default:
 			//lint:ignore ST1005 This error is to be displayed in the browser
 			err = errors.New("Something funky happened, please open an issue at https://github.com/420integrated/go-420coin/issues")
@@ -485,13 +487,23 @@ func (f *faucet) apiHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		log.Info("Faucet request valid", "url", msg.URL, "tier", msg.Tier, "user", username, "address", address)
 
-		// Ensure the user didn't request funds too recently
+		// Ensure neither the attested user nor the target address requested funds
+		// too recently, otherwise multiple attestations could drain the faucet
+		// into the same account.
+		att := &attestation{provider: provider, user: id, address: address}
+
 		f.lock.Lock()
-		var (
-			fund    bool
-			timeout time.Time
-		)
-		if timeout = f.timeouts[id]; time.Now().After(timeout) {
+		var fund bool
+		timeout, err := f.limiter.blockedUntil(att, time.Now())
+		if err != nil {
+			f.lock.Unlock()
+			if err = sendError(conn, err); err != nil {
+				log.Warn("Failed to send attestation error to client", "err", err)
+				return
+			}
+			continue
+		}
+		if timeout.IsZero() {
 			// User wasn't funded recently, create the funding transaction
 			amount := new(big.Int).Mul(big.NewInt(int64(*payoutFlag)), fourtwentycoin)
 			amount = new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(msg.Tier)), nil))
@@ -522,10 +534,7 @@ func (f *faucet) apiHandler(w http.ResponseWriter, r *http.Request) {
 				Time:    time.Now(),
 				Tx:      signed,
 			}}, f.reqs...)
-			timeout := time.Duration(*minutesFlag*int(math.Pow(3, float64(msg.Tier)))) * time.Minute
-			grace := timeout / 288 // 24h timeout => 5m grace
-
-			f.timeouts[id] = time.Now().Add(timeout - grace)
+			f.limiter.record(att, msg.Tier, time.Now())
 			fund = true
 		}
 		f.lock.Unlock()
@@ -0,0 +1,94 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+)
+
+// errUnattested is returned if a funding request isn't vouched for by any
+// attestation provider.
+var errUnattested = errors.New("funding request not attested")
+
+// attestation is a funding request vouched for by a provider, such as a social
+// network post naming the address, or the noauth mode of private networks.
+type attestation struct {
+	provider string         // Service vouching for the requesting user
+	user     string         // Identity of the user on the attesting service
+	address  common.Address // 420coin address the user asked to fund
+}
+
+// fundLimiter rate limits funding by the attested identity of the requesting
+// user and by the funded address, so that neither multiple addresses nor
+// multiple attestations can drain the faucet faster than allowed. The waiting
+// period of every tier is three times that of the previous one.
+type fundLimiter struct {
+	period   time.Duration        // Waiting period of the lowest tier
+	timeouts map[string]time.Time // Time until which users and addresses can't be funded
+}
+
+// newFundLimiter creates a limiter with the given waiting period for the lowest
+// funding tier.
+func newFundLimiter(period time.Duration) *fundLimiter {
+	return &fundLimiter{
+		period:   period,
+		timeouts: make(map[string]time.Time),
+	}
+}
+
+// keys returns the keys the timeouts of an attested request are tracked by.
+func (l *fundLimiter) keys(att *attestation) []string {
+	return []string{"user:" + att.provider + ":" + att.user, "address:" + att.address.Hex()}
+}
+
+// blockedUntil returns the time until which the user or the address of an
+// attested request can't be funded, or the zero time if they can right away.
+func (l *fundLimiter) blockedUntil(att *attestation, now time.Time) (time.Time, error) {
+	if att.provider == "" || att.user == "" {
+		return time.Time{}, errUnattested
+	}
+	var timeout time.Time
+	for _, key := range l.keys(att) {
+		if t := l.timeouts[key]; t.After(timeout) {
+			timeout = t
+		}
+	}
+	if now.After(timeout) {
+		return time.Time{}, nil
+	}
+	return timeout, nil
+}
+
+// record marks an attested request as funded in the given tier at the given
+// time, dropping the timeouts already expired.
+func (l *fundLimiter) record(att *attestation, tier uint, now time.Time) {
+	for key, timeout := range l.timeouts {
+		if now.After(timeout) {
+			delete(l.timeouts, key)
+		}
+	}
+	period := time.Duration(float64(l.period) * math.Pow(3, float64(tier)))
+	grace := period / 288 // 24h period => 5m grace
+
+	for _, key := range l.keys(att) {
+		l.timeouts[key] = now.Add(period - grace)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+)
+
+// Tests that requests not vouched for by an attestation are rejected.
+func TestFundLimiterUnattested(t *testing.T) {
+	limiter := newFundLimiter(time.Hour)
+	addr := common.HexToAddress("0x01")
+
+	for i, att := range []*attestation{
+		{user: "alice", address: addr},
+		{provider: "twitter", address: addr},
+	} {
+		if _, err := limiter.blockedUntil(att, time.Now()); err != errUnattested {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, errUnattested)
+		}
+	}
+}
+
+// Tests that a funded request blocks both the attested user and the funded
+// address, but no unrelated ones.
+func TestFundLimiterBlocking(t *testing.T) {
+	var (
+		limiter = newFundLimiter(time.Hour)
+		now     = time.Now()
+		addr1   = common.HexToAddress("0x01")
+		addr2   = common.HexToAddress("0x02")
+	)
+	limiter.record(&attestation{provider: "twitter", user: "alice", address: addr1}, 0, now)
+
+	tests := []struct {
+		att     *attestation
+		blocked bool
+	}{
+		{&attestation{provider: "twitter", user: "alice", address: addr1}, true},  // same request
+		{&attestation{provider: "twitter", user: "alice", address: addr2}, true},  // same user, other address
+		{&attestation{provider: "twitter", user: "bob", address: addr1}, true},    // other user, same address
+		{&attestation{provider: "facebook", user: "carol", address: addr1}, true}, // other provider, same address
+		{&attestation{provider: "facebook", user: "alice", address: addr2}, false},
+		{&attestation{provider: "twitter", user: "bob", address: addr2}, false},
+	}
+	for i, tt := range tests {
+		timeout, err := limiter.blockedUntil(tt.att, now.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("test %d: failed to check limit: %v", i, err)
+		}
+		if blocked := !timeout.IsZero(); blocked != tt.blocked {
+			t.Errorf("test %d: blocked mismatch: have %v, want %v", i, blocked, tt.blocked)
+		}
+	}
+}
+
+// Tests that the waiting period triples with every tier, less the grace period,
+// and that expired timeouts are dropped.
+func TestFundLimiterTiers(t *testing.T) {
+	now := time.Now()
+
+	for tier, period := range []time.Duration{24 * time.Hour, 72 * time.Hour, 216 * time.Hour} {
+		var (
+			limiter = newFundLimiter(24 * time.Hour)
+			att     = &attestation{provider: "noauth", user: "alice", address: common.HexToAddress("0x01")}
+		)
+		limiter.record(att, uint(tier), now)
+
+		want := now.Add(period - period/288)
+		if timeout, _ := limiter.blockedUntil(att, now); !timeout.Equal(want) {
+			t.Errorf("tier %d: timeout mismatch: have %v, want %v", tier, timeout, want)
+		}
+		if timeout, _ := limiter.blockedUntil(att, want.Add(time.Second)); !timeout.IsZero() {
+			t.Errorf("tier %d: still blocked after timeout: %v", tier, timeout)
+		}
+		// Recording another request after the timeout should drop the expired ones
+		other := &attestation{provider: "noauth", user: "bob", address: common.HexToAddress("0x02")}
+		limiter.record(other, 0, want.Add(time.Second))
+		if len(limiter.timeouts) != 2 {
+			t.Errorf("tier %d: expired timeouts not dropped: have %d entries, want 2", tier, len(limiter.timeouts))
+		}
+	}
+}
@@ -71,7 +71,8 @@ passwordfile as argument containing the wallet password in plaintext.`,
 		Description: `
 
 Manage accounts, list all existing accounts, import a private key into a new
-account, create a new account or update an existing account.
+account, create a new account, update an existing account or back up and restore
+all accounts.
 
 It supports interactive mode, when you are prompted for password as well as
 non-interactive mode where passwords are supplied via a given password file.
@@ -185,6 +186,56 @@ Note:
 As you can directly copy your encrypted accounts to another 420coin instance,
 this import mechanism is not needed when you transfer an account between
 nodes.
+`,
+			},
+			{
+				Name:      "backup",
+				Usage:     "Export all accounts into a password protected backup",
+				Action:    utils.MigrateFlags(accountBackup),
+				ArgsUsage: "<backupFile>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+				},
+				Description: `
+    g420 account backup <backupfile>
+
+Exports all the key files of the keystore into a single backup file, which is
+encrypted with a password you are prompted for. The key files themselves stay
+encrypted with their own passwords inside the backup.
+
+The backup is versioned and integrity protected, so that a corrupted or tampered
+backup is detected upon restoring it.
+
+For non-interactive use the password can be specified with the --password flag:
+
+    g420 account backup [options] <backupfile>
+`,
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore the accounts of a backup into the keystore",
+				Action:    utils.MigrateFlags(accountRestore),
+				ArgsUsage: "<backupFile>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+				},
+				Description: `
+    g420 account restore <backupfile>
+
+Restores the accounts of a backup created by 'g420 account backup' into the
+keystore, prompting for the password of the backup. Accounts already present
+in the keystore are skipped.
+
+The restored accounts keep the passwords they had when the backup was created.
+
+For non-interactive use the password can be specified with the --password flag:
+
+    g420 account restore [options] <backupfile>
 `,
 			},
 		},
@@ -352,3 +403,53 @@ func accountImport(ctx *cli.Context) error {
 	fmt.Printf("Address: {%x}\n", acct.Address)
 	return nil
 }
+
+// accountBackup exports all accounts of the keystore into an encrypted backup.
+func accountBackup(ctx *cli.Context) error {
+	file := ctx.Args().First()
+	if len(file) == 0 {
+		utils.Fatalf("backup file must be given as argument")
+	}
+	stack, cfg := makeConfigNode(ctx)
+	scryptN, scryptP, _, err := cfg.Node.AccountConfig()
+	if err != nil {
+		utils.Fatalf("Failed to read configuration: %v", err)
+	}
+	passphrase := utils.GetPassPhraseWithList("Your backup is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
+
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	bundle, err := ks.Backup(passphrase, scryptN, scryptP)
+	if err != nil {
+		utils.Fatalf("Could not create the backup: %v", err)
+	}
+	if err := ioutil.WriteFile(file, bundle, 0600); err != nil {
+		utils.Fatalf("Could not write the backup: %v", err)
+	}
+	fmt.Printf("Backed up %d accounts to %s\n", len(ks.Accounts()), file)
+	return nil
+}
+
+// accountRestore imports the accounts of an encrypted backup into the keystore.
+func accountRestore(ctx *cli.Context) error {
+	file := ctx.Args().First()
+	if len(file) == 0 {
+		utils.Fatalf("backup file must be given as argument")
+	}
+	bundle, err := ioutil.ReadFile(file)
+	if err != nil {
+		utils.Fatalf("Could not read the backup: %v", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	passphrase := utils.GetPassPhraseWithList("", false, 0, utils.MakePasswordList(ctx))
+
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	restored, err := ks.Restore(bundle, passphrase)
+	if err != nil {
+		utils.Fatalf("Could not restore the backup: %v", err)
+	}
+	for _, account := range restored {
+		fmt.Printf("Address: {%x}\n", account.Address)
+	}
+	fmt.Printf("Restored %d accounts\n", len(restored))
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/rlp"
+	"github.com/420integrated/go-420coin/trie"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var auditTrieCommand = cli.Command{
+	Action:    utils.MigrateFlags(auditTrie),
+	Name:      "audit-trie",
+	Usage:     "Count the trie node references of a state and report leaked and missing nodes",
+	ArgsUsage: "[<blockHash> | <blockNum>]",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.CacheFlag,
+		utils.RuderalisFlag,
+		utils.YoloV2Flag,
+		utils.LegacyTestnetFlag,
+		utils.SyncModeFlag,
+	},
+	Category: "BLOCKCHAIN COMMANDS",
+	Description: `
+The audit-trie command walks the account and storage tries of the state of the
+given block (the head block by default), counting the references to every trie
+node. The reachable nodes are then compared against the ones stored in the
+database: stored nodes not reachable from the state are reported as leaked, the
+space a pruner keeping only this state could recover, while referenced nodes
+absent from the database are reported as missing.`,
+}
+
+// emptyCodeHash is the code hash of accounts without code.
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// auditTrie walks the state of a block and cross references the reached trie
+// nodes with the ones stored in the database.
+func auditTrie(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	block := chain.CurrentBlock()
+	if arg := ctx.Args().First(); arg != "" {
+		if hashish(arg) {
+			block = chain.GetBlockByHash(common.HexToHash(arg))
+		} else {
+			number, _ := strconv.ParseUint(arg, 10, 64)
+			block = chain.GetBlockByNumber(number)
+		}
+		if block == nil {
+			utils.Fatalf("Block %s not found", arg)
+		}
+	}
+	log.Info("Auditing state trie", "number", block.Number(), "hash", block.Hash(), "root", block.Root())
+
+	var (
+		auditor  = trie.NewAuditor(trie.NewDatabase(db))
+		start    = time.Now()
+		logged   = time.Now()
+		accounts uint64
+		slots    uint64
+	)
+	err := auditor.Walk(block.Root(), func(leaf []byte) error {
+		var account state.Account
+		if err := rlp.DecodeBytes(leaf, &account); err != nil {
+			return err
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Auditing state trie", "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		// Code might be stored in the legacy layout, keyed by its bare hash
+		if !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			auditor.Reference(common.BytesToHash(account.CodeHash))
+		}
+		return auditor.Walk(account.Root, func([]byte) error {
+			slots++
+			return nil
+		})
+	})
+	if err != nil {
+		utils.Fatalf("Failed to audit state trie: %v", err)
+	}
+	// Cross reference the reached nodes with the stored ones
+	var (
+		stored, leaked         int
+		storedSize, leakedSize common.StorageSize
+	)
+	it := db.NewIterator(nil, nil)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		size := common.StorageSize(len(key) + len(it.Value()))
+		stored, storedSize = stored+1, storedSize+size
+		if auditor.Refs(common.BytesToHash(key)) == 0 {
+			leaked, leakedSize = leaked+1, leakedSize+size
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		utils.Fatalf("Failed to iterate database: %v", err)
+	}
+	missing := auditor.Missing()
+	for _, hash := range missing {
+		log.Error("Missing trie node", "hash", hash)
+	}
+	reachable, refs := auditor.Reachable()
+
+	fmt.Printf("State root:        %x (block #%d)\n", block.Root(), block.NumberU64())
+	fmt.Printf("Accounts:          %d\n", accounts)
+	fmt.Printf("Storage slots:     %d\n", slots)
+	fmt.Printf("Reachable entries: %d (%d references)\n", reachable, refs)
+	fmt.Printf("Stored entries:    %d (%v)\n", stored, storedSize)
+	fmt.Printf("Leaked entries:    %d (%v)\n", leaked, leakedSize)
+	fmt.Printf("Missing nodes:     %d\n", len(missing))
+	fmt.Printf("Elapsed:           %v\n", common.PrettyDuration(time.Since(start)))
+	return nil
+}
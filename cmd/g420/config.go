@@ -109,6 +109,7 @@ func defaultNodeConfig() node.Config {
 	cfg := node.DefaultConfig
 	cfg.Name = clientIdentifier
 	cfg.Version = params.VersionWithCommit(gitCommit, gitDate)
+	cfg.BuildInfo = params.NewBuildInfo(gitCommit, gitDate, buildDate, cgoFlags)
 	cfg.HTTPModules = append(cfg.HTTPModules, "fourtwenty")
 	cfg.WSModules = append(cfg.WSModules, "fourtwenty")
 	cfg.IPCPath = "g420.ipc"
@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -32,7 +33,7 @@ import (
 )
 
 var (
-	consoleFlags = []cli.Flag{utils.JSpathFlag, utils.ExecFlag, utils.PreloadJSFlag}
+	consoleFlags = []cli.Flag{utils.JSpathFlag, utils.ExecFlag, utils.ExecJSONFlag, utils.PreloadJSFlag}
 
 	consoleCommand = cli.Command{
 		Action:   utils.MigrateFlags(localConsole),
@@ -101,8 +102,7 @@ func localConsole(ctx *cli.Context) error {
 	defer console.Stop(false)
 
 	// If only a short execution was requested, evaluate and return
-	if script := ctx.GlobalString(utils.ExecFlag.Name); script != "" {
-		console.Evaluate(script)
+	if evaluateExec(ctx, console) {
 		return nil
 	}
 	// Otherwise print the welcome screen and enter interactive mode
@@ -155,8 +155,7 @@ func remoteConsole(ctx *cli.Context) error {
 	}
 	defer console.Stop(false)
 
-	if script := ctx.GlobalString(utils.ExecFlag.Name); script != "" {
-		console.Evaluate(script)
+	if evaluateExec(ctx, console) {
 		return nil
 	}
 
@@ -167,6 +166,30 @@ func remoteConsole(ctx *cli.Context) error {
 	return nil
 }
 
+// evaluateExec runs the statement requested via --exec, if any, reading it from
+// stdin if given as "-". It returns whether a statement was evaluated.
+func evaluateExec(ctx *cli.Context, console *console.Console) bool {
+	script := ctx.GlobalString(utils.ExecFlag.Name)
+	if script == "" {
+		return false
+	}
+	if script == "-" {
+		input, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			utils.Fatalf("Failed to read script from stdin: %v", err)
+		}
+		script = string(input)
+	}
+	if ctx.GlobalBool(utils.ExecJSONFlag.Name) {
+		if err := console.EvaluateJSON(script); err != nil {
+			utils.Fatalf("Failed to evaluate script: %v", err)
+		}
+		return true
+	}
+	console.Evaluate(script)
+	return true
+}
+
 // dialRPC returns a RPC client which connects to the given endpoint.
 // The check for empty endpoint implements the defaulting logic
 // for "g420 attach" and "g420 monitor" with no argument.
@@ -0,0 +1,356 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/420integrated/go-420coin/420/filters"
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/bitutil"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/bloombits"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/event"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	exportLogsAddressFlag = cli.StringFlag{
+		Name:  "address",
+		Usage: "Comma separated list of contract addresses to export the logs of (default = any)",
+	}
+	exportLogsTopicsFlag = cli.StringFlag{
+		Name:  "topics",
+		Usage: "Topic filter, positions separated by ';' and alternatives by ',' (empty position = any)",
+	}
+	exportLogsFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the exported logs (csv or jsonl)",
+		Value: "jsonl",
+	}
+	exportLogsCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportLogs),
+		Name:      "export-logs",
+		Usage:     "Export event logs matching a filter into a file",
+		ArgsUsage: "<filename> <blockNumFirst> <blockNumLast>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			exportLogsAddressFlag,
+			exportLogsTopicsFlag,
+			exportLogsFormatFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-logs command scans the receipts of the given block range and writes
+the logs matching the --address and --topics filters into the given file, either
+as CSV or as one JSON object per line. The bloombits index is used to skip over
+blocks without matches where available.`,
+	}
+)
+
+// exportLogsBatch is the number of blocks filtered in one go, limiting the number
+// of matched logs held in memory before they are written out.
+const exportLogsBatch = params.BloomBitsBlocks
+
+// exportLogs filters the logs of a block range out of the local database and
+// writes the matches into a file.
+func exportLogs(ctx *cli.Context) error {
+	if len(ctx.Args()) < 3 {
+		utils.Fatalf("This command requires three arguments.")
+	}
+	first, ferr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	last, lerr := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+	if ferr != nil || lerr != nil {
+		utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+	}
+	if first > last {
+		utils.Fatalf("Export error: first block %d is after last block %d\n", first, last)
+	}
+	addresses, err := parseLogAddresses(ctx.String(exportLogsAddressFlag.Name))
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	topics, err := parseLogTopics(ctx.String(exportLogsTopicsFlag.Name))
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	format := ctx.String(exportLogsFormatFlag.Name)
+	if format != "csv" && format != "jsonl" {
+		utils.Fatalf("Export error: unknown format %q, want csv or jsonl", format)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	fh, err := os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	defer fh.Close()
+
+	out := bufio.NewWriter(fh)
+	writer := newLogWriter(out, format)
+
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		count   int
+		backend = &logExportBackend{chain: chain, db: db}
+	)
+	for from := first; from <= last; from += exportLogsBatch {
+		to := from + exportLogsBatch - 1
+		if to > last || to < from {
+			to = last
+		}
+		filterCtx, cancel := context.WithCancel(context.Background())
+		logs, err := filters.NewRangeFilter(backend, int64(from), int64(to), addresses, topics).Logs(filterCtx)
+		cancel()
+		if err != nil {
+			utils.Fatalf("Export error: %v", err)
+		}
+		for _, l := range logs {
+			if err := writer.write(l); err != nil {
+				utils.Fatalf("Export error: %v", err)
+			}
+		}
+		count += len(logs)
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting logs", "block", to, "last", last, "logs", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		if to == last {
+			break
+		}
+	}
+	if err := writer.flush(); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	if err := out.Flush(); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	fmt.Printf("Exported %d logs in %v\n", count, time.Since(start))
+	return nil
+}
+
+// parseLogAddresses parses a comma separated list of addresses.
+func parseLogAddresses(input string) ([]common.Address, error) {
+	var addresses []common.Address
+	for _, addr := range utils.SplitAndTrim(input) {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid address %q", addr)
+		}
+		addresses = append(addresses, common.HexToAddress(addr))
+	}
+	return addresses, nil
+}
+
+// parseLogTopics parses a topic filter, where positions are separated by ';' and
+// the alternatives accepted at a position by ','.
+func parseLogTopics(input string) ([][]common.Hash, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	var topics [][]common.Hash
+	for _, position := range strings.Split(input, ";") {
+		var alternatives []common.Hash
+		for _, topic := range utils.SplitAndTrim(position) {
+			blob, err := hexutil.Decode(topic)
+			if err != nil || len(blob) != common.HashLength {
+				return nil, fmt.Errorf("invalid topic %q", topic)
+			}
+			alternatives = append(alternatives, common.BytesToHash(blob))
+		}
+		topics = append(topics, alternatives)
+	}
+	return topics, nil
+}
+
+// logWriter encodes exported logs in the requested output format.
+type logWriter struct {
+	json *json.Encoder // Encoder used for the jsonl format
+	csv  *csv.Writer   // Writer used for the csv format
+}
+
+// newLogWriter creates a log writer for the given format, emitting the CSV
+// header right away if needed.
+func newLogWriter(out io.Writer, format string) *logWriter {
+	if format == "jsonl" {
+		return &logWriter{json: json.NewEncoder(out)}
+	}
+	w := &logWriter{csv: csv.NewWriter(out)}
+	w.csv.Write([]string{"blockNumber", "blockHash", "transactionHash", "transactionIndex", "logIndex", "address", "topics", "data"})
+	return w
+}
+
+// write encodes a single log.
+func (w *logWriter) write(l *types.Log) error {
+	if w.json != nil {
+		return w.json.Encode(l)
+	}
+	topics := make([]string, len(l.Topics))
+	for i, topic := range l.Topics {
+		topics[i] = topic.Hex()
+	}
+	return w.csv.Write([]string{
+		strconv.FormatUint(l.BlockNumber, 10),
+		l.BlockHash.Hex(),
+		l.TxHash.Hex(),
+		strconv.FormatUint(uint64(l.TxIndex), 10),
+		strconv.FormatUint(uint64(l.Index), 10),
+		l.Address.Hex(),
+		strings.Join(topics, ";"),
+		hexutil.Encode(l.Data),
+	})
+}
+
+// flush writes out any buffered data.
+func (w *logWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+// logExportBackend implements filters.Backend on top of an offline chain, so the
+// regular log filters can be run without starting a full node.
+type logExportBackend struct {
+	chain *core.BlockChain
+	db    fourtwentydb.Database
+}
+
+func (b *logExportBackend) ChainDb() fourtwentydb.Database {
+	return b.db
+}
+
+func (b *logExportBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.chain.CurrentBlock().Header(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *logExportBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return b.chain.GetHeaderByHash(hash), nil
+}
+
+func (b *logExportBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.chain.GetReceiptsByHash(hash), nil
+}
+
+func (b *logExportBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
+	receipts := b.chain.GetReceiptsByHash(hash)
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, nil
+}
+
+func (b *logExportBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return noopSubscription()
+}
+
+func (b *logExportBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return noopSubscription()
+}
+
+func (b *logExportBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return noopSubscription()
+}
+
+func (b *logExportBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return noopSubscription()
+}
+
+func (b *logExportBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return noopSubscription()
+}
+
+// BloomStatus reports the number of bloombits sections the node has indexed so
+// far, as stored by the bloom indexer.
+func (b *logExportBackend) BloomStatus() (uint64, uint64) {
+	table := rawdb.NewTable(b.db, string(rawdb.BloomBitsIndexPrefix))
+	data, _ := table.Get([]byte("count"))
+	if len(data) != 8 {
+		return params.BloomBitsBlocks, 0
+	}
+	return params.BloomBitsBlocks, binary.BigEndian.Uint64(data)
+}
+
+// ServiceFilter serves the bloombits retrievals of a matcher session straight
+// from the database until the session's context is cancelled.
+func (b *logExportBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
+	requests := make(chan chan *bloombits.Retrieval)
+
+	go session.Multiplex(16, 0, requests)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case request := <-requests:
+				task := <-request
+				task.Bitsets = make([][]byte, len(task.Sections))
+				for i, section := range task.Sections {
+					head := rawdb.ReadCanonicalHash(b.db, (section+1)*params.BloomBitsBlocks-1)
+					compVector, err := rawdb.ReadBloomBits(b.db, task.Bit, section, head)
+					if err != nil {
+						task.Error = err
+						continue
+					}
+					if task.Bitsets[i], err = bitutil.DecompressBytes(compVector, int(params.BloomBitsBlocks/8)); err != nil {
+						task.Error = err
+					}
+				}
+				request <- task
+			}
+		}
+	}()
+}
+
+// noopSubscription returns a subscription which never delivers any events, the
+// offline backend has no live chain to report on.
+func noopSubscription() event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
+)
+
+func TestParseLogAddresses(t *testing.T) {
+	var (
+		addr1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		addr2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	)
+	tests := []struct {
+		input string
+		want  []common.Address
+		fail  bool
+	}{
+		{input: "", want: nil},
+		{input: "0x1111111111111111111111111111111111111111", want: []common.Address{addr1}},
+		{input: "0x1111111111111111111111111111111111111111, 0x2222222222222222222222222222222222222222", want: []common.Address{addr1, addr2}},
+		{input: "1111111111111111111111111111111111111111", want: []common.Address{addr1}},
+		{input: "0x11", fail: true},
+		{input: "0x1111111111111111111111111111111111111111,nonsense", fail: true},
+	}
+	for i, tt := range tests {
+		have, err := parseLogAddresses(tt.input)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+			continue
+		}
+		if !tt.fail && !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: addresses mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestParseLogTopics(t *testing.T) {
+	var (
+		topic1 = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+		topic2 = common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222")
+	)
+	tests := []struct {
+		input string
+		want  [][]common.Hash
+		fail  bool
+	}{
+		{input: "", want: nil},
+		{input: "  ", want: nil},
+		{input: topic1.Hex(), want: [][]common.Hash{{topic1}}},
+		{input: topic1.Hex() + "," + topic2.Hex(), want: [][]common.Hash{{topic1, topic2}}},
+		{input: topic1.Hex() + ";" + topic2.Hex(), want: [][]common.Hash{{topic1}, {topic2}}},
+		{input: ";" + topic2.Hex(), want: [][]common.Hash{nil, {topic2}}},
+		{input: topic1.Hex() + "; ;" + topic2.Hex(), want: [][]common.Hash{{topic1}, nil, {topic2}}},
+		{input: "0x1111", fail: true},
+		{input: topic1.Hex()[2:], fail: true},
+		{input: topic1.Hex() + ";nonsense", fail: true},
+	}
+	for i, tt := range tests {
+		have, err := parseLogTopics(tt.input)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+			continue
+		}
+		if !tt.fail && !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: topics mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestLogWriterFormats(t *testing.T) {
+	logs := []*types.Log{
+		{
+			Address:     common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			Topics:      []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")},
+			Data:        []byte{0xca, 0xfe},
+			BlockNumber: 10,
+			TxHash:      common.HexToHash("0xaa"),
+			TxIndex:     1,
+			BlockHash:   common.HexToHash("0xbb"),
+			Index:       2,
+		},
+		{
+			Address:     common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			BlockNumber: 11,
+			TxHash:      common.HexToHash("0xcc"),
+			BlockHash:   common.HexToHash("0xdd"),
+		},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "csv",
+			want: "blockNumber,blockHash,transactionHash,transactionIndex,logIndex,address,topics,data\n" +
+				"10,0x00000000000000000000000000000000000000000000000000000000000000bb,0x00000000000000000000000000000000000000000000000000000000000000aa,1,2,0x1111111111111111111111111111111111111111,0x0000000000000000000000000000000000000000000000000000000000000001;0x0000000000000000000000000000000000000000000000000000000000000002,0xcafe\n" +
+				"11,0x00000000000000000000000000000000000000000000000000000000000000dd,0x00000000000000000000000000000000000000000000000000000000000000cc,0,0,0x2222222222222222222222222222222222222222,,0x\n",
+		},
+		{
+			format: "jsonl",
+			want: `{"address":"0x1111111111111111111111111111111111111111","topics":["0x0000000000000000000000000000000000000000000000000000000000000001","0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0xcafe","blockNumber":"0xa","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000aa","transactionIndex":"0x1","blockHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","logIndex":"0x2","removed":false}` + "\n" +
+				`{"address":"0x2222222222222222222222222222222222222222","topics":null,"data":"0x","blockNumber":"0xb","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000cc","transactionIndex":"0x0","blockHash":"0x00000000000000000000000000000000000000000000000000000000000000dd","logIndex":"0x0","removed":false}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		writer := newLogWriter(&out, tt.format)
+		for _, l := range logs {
+			if err := writer.write(l); err != nil {
+				t.Fatalf("%s: failed to write log: %v", tt.format, err)
+			}
+		}
+		if err := writer.flush(); err != nil {
+			t.Fatalf("%s: failed to flush logs: %v", tt.format, err)
+		}
+		if have := out.String(); have != tt.want {
+			t.Errorf("%s: output mismatch:\nhave %s\nwant %s", tt.format, have, tt.want)
+		}
+	}
+}
@@ -53,6 +53,9 @@ var (
 	// Git SHA1 commit hash of the release (set via linker flags)
 	gitCommit = ""
 	gitDate   = ""
+	// Build provenance of the binary (set via linker flags)
+	buildDate = ""
+	cgoFlags  = ""
 	// The app that holds all commands and flags.
 	app = flags.NewApp(gitCommit, gitDate, "the go-420coin command line interface")
 	// flags that configure the node
@@ -65,6 +68,7 @@ var (
 		utils.LegacyBootnodesV5Flag,
 		utils.DataDirFlag,
 		utils.AncientFlag,
+		utils.DatabaseCompressFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag,
@@ -73,10 +77,13 @@ var (
 		utils.EthashCachesInMemoryFlag,
 		utils.EthashCachesOnDiskFlag,
 		utils.EthashCachesLockMmapFlag,
+		utils.EthashCacheURLFlag,
+		utils.EthashCacheServeFlag,
 		utils.EthashDatasetDirFlag,
 		utils.EthashDatasetsInMemoryFlag,
 		utils.EthashDatasetsOnDiskFlag,
 		utils.EthashDatasetsLockMmapFlag,
+		utils.EthashPowModeFlag,
 		utils.TxPoolLocalsFlag,
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
@@ -88,11 +95,17 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolFeeRatioFlag,
+		utils.TxPoolSenderRateFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
+		utils.MaxReorgDepthFlag,
+		utils.TraceStoreFlag,
+		utils.TraceStoreRetentionFlag,
+		utils.ChainTipAlertDepthFlag,
 		utils.LightServeFlag,
 		utils.LegacyLightServFlag,
 		utils.LightIngressFlag,
@@ -100,11 +113,16 @@ var (
 		utils.LightMaxPeersFlag,
 		utils.LegacyLightPeersFlag,
 		utils.LightNoPruneFlag,
+		utils.LightRemotePoWFlag,
 		utils.LightKDFFlag,
 		utils.UltraLightServersFlag,
 		utils.UltraLightFractionFlag,
 		utils.UltraLightOnlyAnnounceFlag,
 		utils.WhitelistFlag,
+		utils.FederationSignerFlag,
+		utils.OverrideIndicaFlag,
+		utils.OverrideSativaFlag,
+		utils.ProfileFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
 		utils.CacheTrieFlag,
@@ -112,8 +130,10 @@ var (
 		utils.CacheTrieRejournalFlag,
 		utils.CacheGCFlag,
 		utils.CacheSnapshotFlag,
+		utils.CacheTxPoolFlag,
 		utils.CacheNoPrefetchFlag,
 		utils.CachePreimagesFlag,
+		utils.CacheChainFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
@@ -132,8 +152,14 @@ var (
 		utils.LegacyMinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerfiyFlag,
+		utils.MinerTxOrderFlag,
+		utils.MinerStratumFlag,
+		utils.MinerStratumDifficultyFlag,
+		utils.MinerCoinbaseListFlag,
+		utils.MinerCoinbaseIntervalFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
+		utils.NoCompressionFlag,
 		utils.DiscoveryV5Flag,
 		utils.NetrestrictFlag,
 		utils.NodeKeyFileFlag,
@@ -186,9 +212,16 @@ var (
 		utils.LegacyWSAllowedOriginsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
+		utils.IPCAllowFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalSmokeCapFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCStructuredErrorsFlag,
+		utils.RPCRecipientCheckFlag,
+		utils.RPCEthCompatFlag,
+		utils.RPCTracerStepsFlag,
+		utils.RPCTracerMemoryFlag,
+		utils.RPCNoCustomTracersFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -229,6 +262,12 @@ func init() {
 		dumpCommand,
 		dumpGenesisCommand,
 		inspectCommand,
+		// See logscmd.go:
+		exportLogsCommand,
+		// See auditcmd.go:
+		auditTrieCommand,
+		// See snapshot.go:
+		snapshotCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
@@ -268,6 +307,9 @@ func init() {
 }
 
 func main() {
+	if runService() {
+		return
+	}
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -293,6 +335,9 @@ func prepare(ctx *cli.Context) {
 	case !ctx.GlobalIsSet(utils.NetworkIdFlag.Name):
 		log.Info("Starting G420 on 420coin mainnet...")
 	}
+	// Apply any tuning profile before the cache defaults are adjusted below
+	minMemory := applyProfile(ctx)
+
 	// If we're a full node on mainnet without --cache specified, bump default cache allowance
 	if ctx.GlobalString(utils.SyncModeFlag.Name) != "light" && !ctx.GlobalIsSet(utils.CacheFlag.Name) && !ctx.GlobalIsSet(utils.NetworkIdFlag.Name) {
 		// Make sure we're not on the testnet either
@@ -309,9 +354,18 @@ func prepare(ctx *cli.Context) {
 	// Cap the cache allowance and tune the garbage collector
 	mem, err := gopsutil.VirtualMemory()
 	if err == nil {
+		if ctx.GlobalString(utils.SyncModeFlag.Name) != "light" && mem.Total < minMemory*1024*1024 {
+			log.Warn("Physical memory below requirements", "available", mem.Total/1024/1024, "required", minMemory)
+			if !ctx.GlobalIsSet(utils.ProfileFlag.Name) {
+				log.Warn("Consider running with --profile=lowmem on constrained machines")
+			}
+		}
 		if 32<<(^uintptr(0)>>63) == 32 && mem.Total > 2*1024*1024*1024 {
 			log.Warn("Lowering memory allowance on 32bit arch", "available", mem.Total/1024/1024, "addressable", 2*1024)
 			mem.Total = 2 * 1024 * 1024 * 1024
+			if !ctx.GlobalIsSet(utils.ProfileFlag.Name) {
+				log.Warn("Consider running with --profile=lowmem on 32bit systems")
+			}
 		}
 		allowance := int(mem.Total / 1024 / 1024 / 3)
 		if cache := ctx.GlobalInt(utils.CacheFlag.Name); cache > allowance {
@@ -346,7 +400,9 @@ func g420(ctx *cli.Context) error {
 	defer stack.Close()
 
 	startNode(ctx, stack, backend)
+	superviseNode(stack)
 	stack.Wait()
+	superviseShutdown()
 	return nil
 }
 
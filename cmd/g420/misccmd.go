@@ -141,6 +141,12 @@ func version(ctx *cli.Context) error {
 	if gitDate != "" {
 		fmt.Println("Git Commit Date:", gitDate)
 	}
+	if buildDate != "" {
+		fmt.Println("Build Date:", buildDate)
+	}
+	if cgoFlags != "" {
+		fmt.Println("CGO Flags:", cgoFlags)
+	}
 	fmt.Println("Architecture:", runtime.GOARCH)
 	fmt.Println("Go Version:", runtime.Version())
 	fmt.Println("Operating System:", runtime.GOOS)
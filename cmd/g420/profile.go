@@ -0,0 +1,92 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"github.com/420integrated/go-420coin/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// profileSetting is a single flag value overridden by a tuning profile.
+type profileSetting struct {
+	flag  cli.Flag
+	value string
+}
+
+// profile is a named set of flag defaults tuned for a class of machines, along
+// with the physical memory it needs to run comfortably.
+type profile struct {
+	settings  []profileSetting
+	minMemory uint64 // Physical memory in megabytes below which a warning is printed
+}
+
+// defaultMinMemory is the physical memory in megabytes recommended for a full node
+// running without a tuning profile.
+const defaultMinMemory = 4096
+
+// profiles are the tuning presets selectable via --profile.
+var profiles = map[string]profile{
+	// lowmem targets Raspberry Pi class machines with 1-2GB of memory, often on a
+	// 32 bit OS where memory maps eat into the scarce address space.
+	"lowmem": {
+		settings: []profileSetting{
+			{utils.CacheFlag, "256"},
+			{utils.CacheDatabaseFlag, "60"},
+			{utils.CacheTrieFlag, "20"},
+			{utils.CacheGCFlag, "20"},
+			{utils.CacheSnapshotFlag, "0"},
+			{utils.EthashCachesInMemoryFlag, "1"},
+			{utils.EthashCachesOnDiskFlag, "2"},
+			{utils.EthashCachesLockMmapFlag, "false"},
+			{utils.EthashDatasetsInMemoryFlag, "1"},
+			{utils.EthashDatasetsOnDiskFlag, "1"},
+			{utils.EthashDatasetsLockMmapFlag, "false"},
+			{utils.TxPoolAccountSlotsFlag, "8"},
+			{utils.TxPoolGlobalSlotsFlag, "1024"},
+			{utils.TxPoolAccountQueueFlag, "16"},
+			{utils.TxPoolGlobalQueueFlag, "256"},
+		},
+		minMemory: 1024,
+	},
+}
+
+// applyProfile overrides the defaults of the flags tuned by the selected profile,
+// leaving the explicitly set ones untouched. It returns the physical memory in
+// megabytes recommended for the resulting configuration.
+func applyProfile(ctx *cli.Context) uint64 {
+	name := ctx.GlobalString(utils.ProfileFlag.Name)
+	if name == "" {
+		return defaultMinMemory
+	}
+	preset, ok := profiles[name]
+	if !ok {
+		utils.Fatalf("Unknown --%s: %q", utils.ProfileFlag.Name, name)
+	}
+	log.Info("Applying tuning profile", "profile", name)
+	for _, setting := range preset.settings {
+		flag := setting.flag.GetName()
+		if ctx.GlobalIsSet(flag) {
+			log.Debug("Keeping explicitly set flag over profile", "flag", flag)
+			continue
+		}
+		if err := ctx.GlobalSet(flag, setting.value); err != nil {
+			utils.Fatalf("Failed to apply profile setting %s=%s: %v", flag, setting.value, err)
+		}
+	}
+	return preset.minMemory
+}
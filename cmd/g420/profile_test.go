@@ -0,0 +1,51 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Tests that the lowmem profile overrides the flag defaults, but leaves the
+// explicitly set flags alone.
+func TestLowmemProfile(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range []cli.Flag{utils.ProfileFlag, utils.CacheFlag, utils.CacheSnapshotFlag, utils.TxPoolGlobalSlotsFlag} {
+		f.Apply(set)
+	}
+	if err := set.Parse([]string{"--profile", "lowmem", "--txpool.globalslots", "2048"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+	if have := applyProfile(ctx); have != profiles["lowmem"].minMemory {
+		t.Errorf("memory requirement mismatch: have %d, want %d", have, profiles["lowmem"].minMemory)
+	}
+	if have := ctx.GlobalInt(utils.CacheFlag.Name); have != 256 {
+		t.Errorf("cache mismatch: have %d, want 256", have)
+	}
+	if have := ctx.GlobalInt(utils.CacheSnapshotFlag.Name); have != 0 {
+		t.Errorf("snapshot cache mismatch: have %d, want 0", have)
+	}
+	if have := ctx.GlobalUint64(utils.TxPoolGlobalSlotsFlag.Name); have != 2048 {
+		t.Errorf("explicit txpool slots overridden: have %d, want 2048", have)
+	}
+}
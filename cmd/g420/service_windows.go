@@ -0,0 +1,92 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/420integrated/go-420coin/log"
+	"golang.org/x/sys/windows/svc"
+)
+
+// serviceName is the name g420 reports to the Windows service manager.
+const serviceName = "g420"
+
+// runService runs g420 under the Windows service manager if it was started as a
+// service, returning false for interactive sessions.
+func runService() bool {
+	interactive, err := svc.IsAnInteractiveSession()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to detect session type:", err)
+		os.Exit(1)
+	}
+	if interactive {
+		return false
+	}
+	if err := svc.Run(serviceName, new(service)); err != nil {
+		log.Error("Windows service failed", "err", err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// service implements svc.Handler, reporting g420 as running only once the node
+// finished starting up.
+type service struct{}
+
+// Execute runs g420 and relays its lifecycle to the service manager.
+func (s *service) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+
+	status <- svc.Status{State: svc.StartPending}
+
+	errc := make(chan error, 1)
+	go func() { errc <- app.Run(os.Args) }()
+
+	ready := nodeReady
+	for {
+		select {
+		case <-ready:
+			status <- svc.Status{State: svc.Running, Accepts: accepted}
+			ready = nil // Report readiness only once
+
+		case err := <-errc:
+			status <- svc.Status{State: svc.StopPending}
+			if err != nil {
+				log.Error("G420 terminated", "err", err)
+				return false, 1
+			}
+			return false, 0
+
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				select {
+				case <-nodeStop:
+				default:
+					close(nodeStop)
+				}
+			}
+		}
+	}
+}
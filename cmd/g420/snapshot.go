@@ -0,0 +1,115 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/420integrated/go-420coin/cmd/utils"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/state/pruner"
+	"github.com/420integrated/go-420coin/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var snapshotCommand = cli.Command{
+	Name:        "snapshot",
+	Usage:       "A set of commands based on the snapshot",
+	Category:    "MISCELLANEOUS COMMANDS",
+	Description: "",
+	Subcommands: []cli.Command{
+		{
+			Name:      "prune-state",
+			Usage:     "Prune stale 420coin state data based on the snapshot",
+			ArgsUsage: "<root>",
+			Action:    utils.MigrateFlags(pruneState),
+			Category:  "MISCELLANEOUS COMMANDS",
+			Flags: []cli.Flag{
+				utils.DataDirFlag,
+				utils.AncientFlag,
+				utils.CacheFlag,
+				utils.CacheTrieJournalFlag,
+				utils.BloomFilterSizeFlag,
+				utils.RuderalisFlag,
+				utils.YoloV2Flag,
+				utils.LegacyTestnetFlag,
+			},
+			Description: `
+g420 snapshot prune-state <state-root>
+will prune historical state data with the help of the state snapshot.
+All trie nodes and contract codes that do not belong to the specified
+version state will be deleted from the database. After pruning, only
+two version states are available: genesis and the specific one.
+
+The default pruning target is the HEAD-127 state.
+
+The state reachability is tracked in a bloom filter, sized by the
+--bloomfilter.size flag, which is written to the data directory once
+complete. If the pruning is interrupted after that point, it is resumed
+from the persisted filter by rerunning the command or on the next node
+startup.
+
+WARNING: It's necessary to delete the trie clean cache after the pruning.
+If you specify another directory for the trie clean cache via "--cache.trie.journal"
+during the use of g420, please also specify it here for correct deletion. Otherwise
+the trie clean cache with default directory will be deleted.
+`,
+		},
+	},
+}
+
+// pruneState deletes the state data unreachable from the pruning target,
+// as reconstructed from the snapshot.
+func pruneState(ctx *cli.Context) error {
+	stack, config := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	p, err := pruner.NewPruner(chaindb, stack.ResolvePath(""), stack.ResolvePath(config.Fourtwenty.TrieCleanCacheJournal), ctx.GlobalUint64(utils.BloomFilterSizeFlag.Name))
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "error", err)
+		return err
+	}
+	if ctx.NArg() > 1 {
+		log.Error("Too many arguments given")
+		return errors.New("too many arguments")
+	}
+	var targetRoot common.Hash
+	if ctx.NArg() == 1 {
+		targetRoot, err = parseRoot(ctx.Args()[0])
+		if err != nil {
+			log.Error("Failed to resolve state root", "error", err)
+			return err
+		}
+	}
+	if err = p.Prune(targetRoot); err != nil {
+		log.Error("Failed to prune state", "error", err)
+		return err
+	}
+	return nil
+}
+
+// parseRoot converts a hex encoded state root into a hash.
+func parseRoot(input string) (common.Hash, error) {
+	var h common.Hash
+	if err := h.UnmarshalText([]byte(input)); err != nil {
+		return h, err
+	}
+	return h, nil
+}
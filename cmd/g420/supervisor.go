@@ -0,0 +1,61 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/420integrated/go-420coin/internal/sdnotify"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/420integrated/go-420coin/node"
+)
+
+var (
+	nodeReady = make(chan struct{}) // Closed once the node finished starting up
+	nodeStop  = make(chan struct{}) // Closed by the service manager to request a shutdown
+)
+
+// superviseNode reports the readiness of a fully started node to the process
+// supervisor g420 runs under, if any: systemd via sd_notify, or the Windows service
+// manager. It also keeps the systemd watchdog fed while the node is running.
+func superviseNode(stack *node.Node) {
+	close(nodeReady)
+
+	if sent, err := sdnotify.Notify(sdnotify.Ready); err != nil {
+		log.Warn("Failed to notify systemd of readiness", "err", err)
+	} else if sent {
+		log.Info("Notified systemd of readiness")
+	}
+	done := make(chan struct{})
+	go func() {
+		stack.Wait()
+		close(done)
+	}()
+	go sdnotify.RunWatchdog(done)
+
+	go func() {
+		select {
+		case <-nodeStop:
+			log.Info("Service stop requested, shutting down...")
+			stack.Close()
+		case <-done:
+		}
+	}()
+}
+
+// superviseShutdown reports to systemd that the node is shutting down.
+func superviseShutdown() {
+	sdnotify.Notify(sdnotify.Stopping)
+}
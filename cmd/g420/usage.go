@@ -36,6 +36,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			configFileFlag,
 			utils.DataDirFlag,
 			utils.AncientFlag,
+			utils.DatabaseCompressFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.SmartCardDaemonPathFlag,
@@ -46,10 +47,17 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.ExitWhenSyncedFlag,
 			utils.GCModeFlag,
 			utils.TxLookupLimitFlag,
+			utils.MaxReorgDepthFlag,
+			utils.TraceStoreFlag,
+			utils.TraceStoreRetentionFlag,
+			utils.ChainTipAlertDepthFlag,
 			utils.FourtwentyStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightKDFFlag,
 			utils.WhitelistFlag,
+			utils.FederationSignerFlag,
+			utils.OverrideIndicaFlag,
+			utils.OverrideSativaFlag,
 		},
 	},
 	{
@@ -63,6 +71,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.UltraLightFractionFlag,
 			utils.UltraLightOnlyAnnounceFlag,
 			utils.LightNoPruneFlag,
+			utils.LightRemotePoWFlag,
 		},
 	},
 	{
@@ -79,10 +88,13 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.EthashCachesInMemoryFlag,
 			utils.EthashCachesOnDiskFlag,
 			utils.EthashCachesLockMmapFlag,
+			utils.EthashCacheURLFlag,
+			utils.EthashCacheServeFlag,
 			utils.EthashDatasetDirFlag,
 			utils.EthashDatasetsInMemoryFlag,
 			utils.EthashDatasetsOnDiskFlag,
 			utils.EthashDatasetsLockMmapFlag,
+			utils.EthashPowModeFlag,
 		},
 	},
 	{
@@ -99,11 +111,14 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolFeeRatioFlag,
+			utils.TxPoolSenderRateFlag,
 		},
 	},
 	{
 		Name: "PERFORMANCE TUNING",
 		Flags: []cli.Flag{
+			utils.ProfileFlag,
 			utils.CacheFlag,
 			utils.CacheDatabaseFlag,
 			utils.CacheTrieFlag,
@@ -111,8 +126,10 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.CacheTrieRejournalFlag,
 			utils.CacheGCFlag,
 			utils.CacheSnapshotFlag,
+			utils.CacheTxPoolFlag,
 			utils.CacheNoPrefetchFlag,
 			utils.CachePreimagesFlag,
+			utils.CacheChainFlag,
 		},
 	},
 	{
@@ -129,6 +146,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 		Flags: []cli.Flag{
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
+			utils.IPCAllowFlag,
 			utils.HTTPEnabledFlag,
 			utils.HTTPListenAddrFlag,
 			utils.HTTPPortFlag,
@@ -145,8 +163,15 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.GraphQLVirtualHostsFlag,
 			utils.RPCGlobalSmokeCapFlag,
 			utils.RPCGlobalTxFeeCapFlag,
+			utils.RPCStructuredErrorsFlag,
+			utils.RPCRecipientCheckFlag,
+			utils.RPCEthCompatFlag,
+			utils.RPCTracerStepsFlag,
+			utils.RPCTracerMemoryFlag,
+			utils.RPCNoCustomTracersFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
+			utils.ExecJSONFlag,
 			utils.PreloadJSFlag,
 		},
 	},
@@ -162,6 +187,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MaxPendingPeersFlag,
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
+			utils.NoCompressionFlag,
 			utils.DiscoveryV5Flag,
 			utils.NetrestrictFlag,
 			utils.NodeKeyFileFlag,
@@ -181,6 +207,11 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
 			utils.MinerNoVerfiyFlag,
+			utils.MinerTxOrderFlag,
+			utils.MinerStratumFlag,
+			utils.MinerStratumDifficultyFlag,
+			utils.MinerCoinbaseListFlag,
+			utils.MinerCoinbaseIntervalFlag,
 		},
 	},
 	{
@@ -421,6 +421,15 @@ func newParityChainSpec(network string, genesis *core.Genesis, bootnodes []strin
 	spec.Params.MaxCodeSize = params.MaxCodeSize
 	// g420 has it set from zero
 	spec.Params.MaxCodeSizeTransition = 0
+	if num := genesis.Config.MaxCodeSizeBlock; num != nil && genesis.Config.MaxCodeSize != 0 {
+		if num.Sign() > 0 {
+			return nil, errors.New("parity does not support scheduled code size limit changes")
+		}
+		spec.Params.MaxCodeSize = hexutil.Uint64(genesis.Config.MaxCodeSize)
+	}
+	if genesis.Config.RefundRemovalBlock != nil {
+		return nil, errors.New("parity does not support the refund removal fork")
+	}
 
 	// Disable this one
 	spec.Params.EIP98Transition = math.MaxInt64
@@ -35,16 +35,41 @@ var nodeDockerfile = `
 FROM fourtwentycoin/client-go:latest
 
 ADD genesis.json /genesis.json
+ADD entrypoint.sh /entrypoint.sh
 {{if .Unlock}}
 	ADD signer.json /signer.json
 	ADD signer.pass /signer.pass
 {{end}}
-RUN \
-  echo 'g420 --cache 512 init /genesis.json' > g420.sh && \{{if .Unlock}}
-	echo 'mkdir -p /root/.420coin/keystore/ && cp /signer.json /root/.420coin/keystore/' >> g420.sh && \{{end}}
-	echo $'exec g420 --networkid {{.NetworkID}} --cache 512 --port {{.Port}} --nat extip:{{.IP}} --maxpeers {{.Peers}} {{.LightFlag}} --fourtwentystats \'{{.fourtwentystats}}\' {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} {{if .Fourtwentycoinbase}}--miner.fourtwentycoinbase {{.Fourtwentycoinbase}} --mine --miner.threads 1{{end}} {{if .Unlock}}--unlock 0 --password /signer.pass --mine{{end}} --miner.smoketarget {{.SmokeTarget}} --miner.smokelimit {{.SmokeLimit}} --miner.smokeprice {{.SmokePrice}}' >> g420.sh
+HEALTHCHECK --interval=30s --timeout=10s --start-period=5m --retries=3 \
+	CMD g420 --exec 'net.listening' --cache=16 attach | grep -q true || exit 1
 
-ENTRYPOINT ["/bin/sh", "g420.sh"]
+ENTRYPOINT ["/bin/sh", "/entrypoint.sh"]
+`
+
+// nodeEntrypoint is the startup script of an 420coin node container, initializing
+// the chain from the genesis before handing the process over to g420.
+var nodeEntrypoint = `#!/bin/sh
+set -e
+
+g420 --cache 512 init /genesis.json
+{{if .Unlock}}
+mkdir -p /root/.420coin/keystore/
+cp /signer.json /root/.420coin/keystore/
+{{end}}
+exec g420 \
+	--networkid {{.NetworkID}} \
+	--cache 512 \
+	--port {{.Port}} \
+	--nat extip:{{.IP}} \
+	--maxpeers {{.Peers}} {{.LightFlag}} \
+	--fourtwentystats '{{.fourtwentystats}}' \{{if .Ancient}}
+	--datadir.ancient /ancient \{{end}}{{if .Bootnodes}}
+	--bootnodes {{.Bootnodes}} \{{end}}{{if .Fourtwentycoinbase}}
+	--miner.fourtwentycoinbase {{.Fourtwentycoinbase}} --mine --miner.threads 1 \{{end}}{{if .Unlock}}
+	--unlock 0 --password /signer.pass --mine \{{end}}
+	--miner.smoketarget {{.SmokeTarget}} \
+	--miner.smokelimit {{.SmokeLimit}} \
+	--miner.smokeprice {{.SmokePrice}}
 `
 
 // nodeComposefile is the docker-compose.yml file required to deploy and maintain
@@ -60,7 +85,8 @@ services:
       - "{{.Port}}:{{.Port}}"
       - "{{.Port}}:{{.Port}}/udp"
     volumes:
-      - {{.Datadir}}:/root/.420coin{{if .Ethashdir}}
+      - {{.Datadir}}:/root/.420coin{{if .Ancientdir}}
+      - {{.Ancientdir}}:/ancient{{end}}{{if .Ethashdir}}
       - {{.Ethashdir}}:/root/.ethash{{end}}
     environment:
       - PORT={{.Port}}/tcp
@@ -71,14 +97,19 @@ services:
       - SMOKE_TARGET={{.SmokeTarget}}
       - SMOKE_LIMIT={{.SmokeLimit}}
       - SMOKE_PRICE={{.SmokePrice}}
+      - RESTART_POLICY={{.Restart}}
     logging:
       driver: "json-file"
       options:
         max-size: "1m"
         max-file: "10"
-    restart: always
+    restart: {{.Restart}}
 `
 
+// nodeRestartPolicies are the docker-compose restart policies a node may be
+// deployed with.
+var nodeRestartPolicies = []string{"always", "unless-stopped", "on-failure", "no"}
+
 // deployNode deploys a new 420coin node container to a remote machine via SSH,
 // docker and docker-compose. If an instance with the specified network name
 // already exists there, it will be overwritten!
@@ -98,6 +129,12 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 	}
 	dockerfile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeDockerfile)).Execute(dockerfile, map[string]interface{}{
+		"Unlock": config.keyJSON != "",
+	})
+	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+
+	entrypoint := new(bytes.Buffer)
+	template.Must(template.New("").Parse(nodeEntrypoint)).Execute(entrypoint, map[string]interface{}{
 		"NetworkID":          config.network,
 		"Port":               config.port,
 		"IP":                 client.address,
@@ -110,13 +147,20 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 		"SmokeLimit":         uint64(1000000 * config.smokeLimit),
 		"SmokePrice":         uint64(1000000000 * config.smokePrice),
 		"Unlock":             config.keyJSON != "",
+		"Ancient":            config.ancientdir != "",
 	})
-	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
+	files[filepath.Join(workdir, "entrypoint.sh")] = entrypoint.Bytes()
+
+	restart := config.restart
+	if restart == "" {
+		restart = nodeRestartPolicies[0]
+	}
 
 	composefile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeComposefile)).Execute(composefile, map[string]interface{}{
 		"Type":                kind,
 		"Datadir":             config.datadir,
+		"Ancientdir":          config.ancientdir,
 		"Ethashdir":           config.ethashdir,
 		"Network":             network,
 		"Port":                config.port,
@@ -128,6 +172,7 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 		"SmokeTarget":         config.smokeTarget,
 		"SmokeLimit":          config.smokeLimit,
 		"SmokePrice":          config.smokePrice,
+		"Restart":             restart,
 	})
 	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
 
@@ -155,6 +200,7 @@ type nodeInfos struct {
 	genesis             []byte
 	network             int64
 	datadir             string
+	ancientdir          string
 	ethashdir           string
 	fourtwentystats     string
 	port                int
@@ -167,6 +213,7 @@ type nodeInfos struct {
 	smokeTarget         float64
 	smokeLimit          float64
 	smokePrice          float64
+	restart             string
 }
 
 // Report converts the typed struct into a plain string->string map, containing
@@ -178,6 +225,10 @@ func (info *nodeInfos) Report() map[string]string {
 		"Peer count (all total)":   strconv.Itoa(info.peersTotal),
 		"Peer count (light nodes)": strconv.Itoa(info.peersLight),
 		"420stats username":        info.fourtwentystats,
+		"Restart policy":           info.restart,
+	}
+	if info.ancientdir != "" {
+		report["Freezer directory"] = info.ancientdir
 	}
 	if info.smokeTarget > 0 {
 		// Miner or signer node
@@ -255,6 +306,7 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 	stats := &nodeInfos{
 		genesis:    genesis,
 		datadir:    infos.volumes["/root/.420coin"],
+		ancientdir: infos.volumes["/ancient"],
 		ethashdir:  infos.volumes["/root/.ethash"],
 		port:       port,
 		peersTotal: totalPeers,
@@ -266,6 +318,7 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 		smokeTarget:  smokeTarget,
 		smokeLimit:   smokeLimit,
 		smokePrice:   smokePrice,
+		restart:      infos.envvars["RESTART_POLICY"],
 	}
 	stats.enode = string(enode)
 
@@ -19,6 +19,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/420integrated/go-420coin/accounts/keystore"
@@ -67,6 +68,14 @@ func (w *wizard) deployNode(boot bool) {
 		fmt.Printf("Where should data be stored on the remote machine? (default = %s)\n", infos.datadir)
 		infos.datadir = w.readDefaultString(infos.datadir)
 	}
+	fmt.Println()
+	if infos.ancientdir == "" {
+		fmt.Printf("Where should ancient chain segments be frozen on the remote machine? (default = inside the data directory)\n")
+		infos.ancientdir = w.readDefaultString("")
+	} else {
+		fmt.Printf("Where should ancient chain segments be frozen on the remote machine? (default = %s)\n", infos.ancientdir)
+		infos.ancientdir = w.readDefaultString(infos.ancientdir)
+	}
 	if w.conf.Genesis.Config.Ethash != nil && !boot {
 		fmt.Println()
 		if infos.ethashdir == "" {
@@ -160,6 +169,20 @@ func (w *wizard) deployNode(boot bool) {
 		fmt.Printf("What smoke price should the signer require (Maher)? (default = %0.3f)\n", infos.smokePrice)
 		infos.smokePrice = w.readDefaultFloat(infos.smokePrice)
 	}
+	// Figure out how the container should be restarted by docker
+	if infos.restart == "" {
+		infos.restart = nodeRestartPolicies[0]
+	}
+	for {
+		fmt.Println()
+		fmt.Printf("Which restart policy should the container use (%s)? (default = %s)\n", strings.Join(nodeRestartPolicies, ", "), infos.restart)
+		restart := w.readDefaultString(infos.restart)
+		if validRestartPolicy(restart) {
+			infos.restart = restart
+			break
+		}
+		log.Error("Invalid restart policy", "policy", restart)
+	}
 	// Try to deploy the full node on the host
 	nocache := false
 	if existed {
@@ -180,3 +203,14 @@ func (w *wizard) deployNode(boot bool) {
 
 	w.networkStats()
 }
+
+// validRestartPolicy reports whether the given docker-compose restart policy is
+// supported for node containers.
+func validRestartPolicy(policy string) bool {
+	for _, valid := range nodeRestartPolicies {
+		if policy == valid {
+			return true
+		}
+	}
+	return false
+}
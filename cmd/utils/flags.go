@@ -111,6 +111,10 @@ var (
 		Name:  "datadir.ancient",
 		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
 	}
+	DatabaseCompressFlag = cli.BoolFlag{
+		Name:  "datadir.compress",
+		Usage: "Snappy compress the block bodies and receipts written to the chain database",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -202,6 +206,25 @@ var (
 		Usage: "Number of recent blocks to maintain transactions index by-hash for (default = index all blocks)",
 		Value: 0,
 	}
+	MaxReorgDepthFlag = cli.Uint64Flag{
+		Name:  "maxreorgdepth",
+		Usage: "Maximum number of blocks to reorg automatically, deeper reorgs wait for admin_approveReorg (default = unlimited)",
+		Value: 0,
+	}
+	TraceStoreFlag = cli.BoolFlag{
+		Name:  "tracestore",
+		Usage: "Pre-compute and store the call traces of imported blocks",
+	}
+	TraceStoreRetentionFlag = cli.Uint64Flag{
+		Name:  "tracestore.retention",
+		Usage: "Number of recent blocks to retain call traces for (default = retain all)",
+		Value: 0,
+	}
+	ChainTipAlertDepthFlag = cli.Uint64Flag{
+		Name:  "chaintips.alertdepth",
+		Usage: "Alert about heavier competing chain tips within this many blocks of the head (0 = disabled)",
+		Value: fourtwenty.DefaultConfig.ChainTipAlertDepth,
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -210,6 +233,18 @@ var (
 		Name:  "whitelist",
 		Usage: "Comma separated block number-to-hash mappings to enforce (<number>=<hash>)",
 	}
+	FederationSignerFlag = cli.StringFlag{
+		Name:  "federation.signer",
+		Usage: "Unlocked account to vote on federated checkpoints with (chain config must enable federation)",
+	}
+	OverrideIndicaFlag = cli.Uint64Flag{
+		Name:  "override.indica",
+		Usage: "Manually specify the Indica reward era start block, overriding the bundled setting",
+	}
+	OverrideSativaFlag = cli.Uint64Flag{
+		Name:  "override.sativa",
+		Usage: "Manually specify the Sativa reward era start block, overriding the bundled setting",
+	}
 	// Light server and client settings
 	LightServeFlag = cli.IntFlag{
 		Name:  "light.serve",
@@ -249,6 +284,10 @@ var (
 		Name:  "light.nopruning",
 		Usage: "Disable ancient light chain data pruning",
 	}
+	LightRemotePoWFlag = cli.BoolFlag{
+		Name:  "light.remotepow",
+		Usage: "Verify header proof-of-work with dataset items served by LES servers instead of generating ethash caches",
+	}
 	// Ethash settings
 	EthashCacheDirFlag = DirectoryFlag{
 		Name:  "ethash.cachedir",
@@ -268,6 +307,14 @@ var (
 		Name:  "ethash.cacheslockmmap",
 		Usage: "Lock memory maps of recent ethash caches",
 	}
+	EthashCacheURLFlag = cli.StringFlag{
+		Name:  "ethash.cacheurl",
+		Usage: "Base URL of a node serving ethash verification caches to fetch instead of generating them",
+	}
+	EthashCacheServeFlag = cli.BoolFlag{
+		Name:  "ethash.cacheserve",
+		Usage: "Serve the ethash verification caches on disk to other nodes at /ethash/caches/ on the HTTP endpoint",
+	}
 	EthashDatasetDirFlag = DirectoryFlag{
 		Name:  "ethash.dagdir",
 		Usage: "Directory to store the ethash mining DAGs",
@@ -287,6 +334,11 @@ var (
 		Name:  "ethash.dagslockmmap",
 		Usage: "Lock memory maps for recent ethash mining DAGs",
 	}
+	EthashPowModeFlag = cli.StringFlag{
+		Name:  "ethash.powmode",
+		Usage: `Proof-of-work verification mode ("normal", "shared", "test", "fake" or "fullfake")`,
+		Value: fourtwenty.DefaultConfig.Ethash.PowMode.String(),
+	}
 	// Transaction pool settings
 	TxPoolLocalsFlag = cli.StringFlag{
 		Name:  "txpool.locals",
@@ -341,7 +393,21 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: fourtwenty.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolFeeRatioFlag = cli.Uint64Flag{
+		Name:  "txpool.feeratio",
+		Usage: "Maximum percentage of a remote sender's balance its pooled transactions may pay in fees (0 = unlimited)",
+		Value: fourtwenty.DefaultConfig.TxPool.FeeRatio,
+	}
+	TxPoolSenderRateFlag = cli.Uint64Flag{
+		Name:  "txpool.senderrate",
+		Usage: "Maximum number of transactions accepted from a remote sender per second (0 = unlimited)",
+		Value: fourtwenty.DefaultConfig.TxPool.SenderRate,
+	}
 	// Performance tuning settings
+	ProfileFlag = cli.StringFlag{
+		Name:  "profile",
+		Usage: "Tuning profile adjusting the cache, ethash and txpool defaults (lowmem)",
+	}
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
 		Usage: "Megabytes of memory allocated to internal caching (default = 4096 mainnet full node, 128 light mode)",
@@ -367,6 +433,11 @@ var (
 		Usage: "Time interval to regenerate the trie cache journal",
 		Value: fourtwenty.DefaultConfig.TrieCleanCacheRejournal,
 	}
+	BloomFilterSizeFlag = cli.Uint64Flag{
+		Name:  "bloomfilter.size",
+		Usage: "Megabytes of memory allocated to bloom-filter for pruning",
+		Value: 2048,
+	}
 	CacheGCFlag = cli.IntFlag{
 		Name:  "cache.gc",
 		Usage: "Percentage of cache memory allowance to use for trie pruning (default = 25% full mode, 0% archive mode)",
@@ -377,6 +448,10 @@ var (
 		Usage: "Percentage of cache memory allowance to use for snapshot caching (default = 10% full mode, 20% archive mode)",
 		Value: 10,
 	}
+	CacheTxPoolFlag = cli.IntFlag{
+		Name:  "cache.txpool",
+		Usage: "Percentage of cache memory allowance to use for sizing the transaction pool (overrides the global slot limits, 0 = disabled)",
+	}
 	CacheNoPrefetchFlag = cli.BoolFlag{
 		Name:  "cache.noprefetch",
 		Usage: "Disable heuristic state prefetch during block import (less CPU and disk IO, more time waiting for data)",
@@ -385,6 +460,10 @@ var (
 		Name:  "cache.preimages",
 		Usage: "Enable recording the SHA3/keccak preimages of trie keys (default: true)",
 	}
+	CacheChainFlag = cli.IntFlag{
+		Name:  "cache.chain",
+		Usage: "Percentage of cache memory allowance to share between the chain data caches (blocks, bodies, receipts, headers; 0 = unlimited)",
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -432,6 +511,28 @@ var (
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
 	}
+	MinerTxOrderFlag = cli.StringFlag{
+		Name:  "miner.txorder",
+		Usage: `Order of the transactions in mined blocks ("price" or "fifo")`,
+		Value: miner.TxOrderPrice,
+	}
+	MinerStratumFlag = cli.StringFlag{
+		Name:  "miner.stratum",
+		Usage: "Listening address of the stratum server for pool mining (e.g. 127.0.0.1:8008)",
+	}
+	MinerStratumDifficultyFlag = cli.Uint64Flag{
+		Name:  "miner.stratum.difficulty",
+		Usage: "Share difficulty of the stratum server (0 = block difficulty)",
+	}
+	MinerCoinbaseListFlag = cli.StringFlag{
+		Name:  "miner.coinbaselist",
+		Usage: "Comma separated list of addresses to rotate the block rewards among, overriding the fourtwentycoinbase",
+	}
+	MinerCoinbaseIntervalFlag = cli.Uint64Flag{
+		Name:  "miner.coinbaseinterval",
+		Usage: "Number of consecutive blocks mined to each rotated coinbase",
+		Value: 1,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -466,6 +567,30 @@ var (
 		Usage: "Sets a cap on transaction fee (in 420coins) that can be sent via the RPC APIs (0 = no cap)",
 		Value: fourtwenty.DefaultConfig.RPCTxFeeCap,
 	}
+	RPCStructuredErrorsFlag = cli.BoolFlag{
+		Name:  "rpc.structurederrors",
+		Usage: "Return RPC errors with specific numeric codes and structured data instead of plain messages",
+	}
+	RPCRecipientCheckFlag = cli.BoolFlag{
+		Name:  "rpc.recipientcheck",
+		Usage: "Reject RPC value transfers to precompiles or recently self-destructed contracts",
+	}
+	RPCEthCompatFlag = cli.BoolFlag{
+		Name:  "rpc.eth-compat",
+		Usage: "Serve the RPC APIs under the Ethereum eth_* method names, translating gas fields to smoke",
+	}
+	RPCTracerStepsFlag = cli.Uint64Flag{
+		Name:  "rpc.tracer.steps",
+		Usage: "Maximum number of steps a JavaScript tracer may trace (0 = no limit)",
+	}
+	RPCTracerMemoryFlag = cli.IntFlag{
+		Name:  "rpc.tracer.memory",
+		Usage: "Maximum size in bytes of a JavaScript tracer's state and result (0 = no limit)",
+	}
+	RPCNoCustomTracersFlag = cli.BoolFlag{
+		Name:  "rpc.tracer.nocustom",
+		Usage: "Only allow the built-in JavaScript tracers, rejecting custom tracer code",
+	}
 	// Logging and debug settings
 	FourtwentyStatsURLFlag = cli.StringFlag{
 		Name:  "fourtwentystats",
@@ -488,6 +613,10 @@ var (
 		Name:  "ipcpath",
 		Usage: "Filename for IPC socket/pipe within the datadir (explicit paths escape it)",
 	}
+	IPCAllowFlag = cli.StringFlag{
+		Name:  "ipcallow",
+		Usage: "Comma separated list of Windows users and groups allowed to connect to the IPC pipe (default = pipe defaults)",
+	}
 	HTTPEnabledFlag = cli.BoolFlag{
 		Name:  "http",
 		Usage: "Enable the HTTP-RPC server",
@@ -557,11 +686,15 @@ var (
 	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
-		Usage: "Execute JavaScript statement",
+		Usage: "Execute JavaScript statement (use \"-\" to read it from stdin)",
+	}
+	ExecJSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the result of --exec as JSON instead of pretty printing it",
 	}
 	PreloadJSFlag = cli.StringFlag{
 		Name:  "preload",
-		Usage: "Comma separated list of JavaScript files to preload into the console",
+		Usage: "Comma separated list of JavaScript files to preload into the console, after the scripts in <datadir>/preload",
 	}
 
 	// Network Settings
@@ -602,6 +735,10 @@ var (
 		Name:  "nodiscover",
 		Usage: "Disables the peer discovery mechanism (manual peer addition)",
 	}
+	NoCompressionFlag = cli.BoolFlag{
+		Name:  "nocompression",
+		Usage: "Disables snappy compression of peer messages (saves CPU on constrained devices)",
+	}
 	DiscoveryV5Flag = cli.BoolFlag{
 		Name:  "v5disc",
 		Usage: "Enables the experimental RLPx V5 (Topic Discovery) mechanism",
@@ -993,6 +1130,9 @@ func setIPC(ctx *cli.Context, cfg *node.Config) {
 	case ctx.GlobalIsSet(IPCPathFlag.Name):
 		cfg.IPCPath = ctx.GlobalString(IPCPathFlag.Name)
 	}
+	if ctx.GlobalIsSet(IPCAllowFlag.Name) {
+		cfg.IPCAllow = SplitAndTrim(ctx.GlobalString(IPCAllowFlag.Name))
+	}
 }
 
 // setLes configures the les server and ultra light client settings from the command line flags.
@@ -1033,6 +1173,9 @@ func setLes(ctx *cli.Context, cfg *fourtwenty.Config) {
 	if ctx.GlobalIsSet(LightNoPruneFlag.Name) {
 		cfg.LightNoPrune = ctx.GlobalBool(LightNoPruneFlag.Name)
 	}
+	if ctx.GlobalIsSet(LightRemotePoWFlag.Name) {
+		cfg.LightRemotePoW = ctx.GlobalBool(LightRemotePoWFlag.Name)
+	}
 }
 
 // makeDatabaseHandles raises out the number of allowed file handles per process
@@ -1166,6 +1309,9 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.GlobalIsSet(NoDiscoverFlag.Name) || lightClient {
 		cfg.NoDiscovery = true
 	}
+	if ctx.GlobalIsSet(NoCompressionFlag.Name) {
+		cfg.NoCompression = ctx.GlobalBool(NoCompressionFlag.Name)
+	}
 
 	// if we're running a light client or server, force enable the v5 peer discovery
 	// unless it is explicitly disabled with --nodiscover note that explicitly specifying
@@ -1335,6 +1481,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolFeeRatioFlag.Name) {
+		cfg.FeeRatio = ctx.GlobalUint64(TxPoolFeeRatioFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolSenderRateFlag.Name) {
+		cfg.SenderRate = ctx.GlobalUint64(TxPoolSenderRateFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *fourtwenty.Config) {
@@ -1353,6 +1505,12 @@ func setEthash(ctx *cli.Context, cfg *fourtwenty.Config) {
 	if ctx.GlobalIsSet(EthashCachesLockMmapFlag.Name) {
 		cfg.Ethash.CachesLockMmap = ctx.GlobalBool(EthashCachesLockMmapFlag.Name)
 	}
+	if ctx.GlobalIsSet(EthashCacheURLFlag.Name) {
+		cfg.Ethash.CacheURL = ctx.GlobalString(EthashCacheURLFlag.Name)
+	}
+	if ctx.GlobalIsSet(EthashCacheServeFlag.Name) {
+		cfg.Ethash.CacheServe = ctx.GlobalBool(EthashCacheServeFlag.Name)
+	}
 	if ctx.GlobalIsSet(EthashDatasetsInMemoryFlag.Name) {
 		cfg.Ethash.DatasetsInMem = ctx.GlobalInt(EthashDatasetsInMemoryFlag.Name)
 	}
@@ -1362,6 +1520,11 @@ func setEthash(ctx *cli.Context, cfg *fourtwenty.Config) {
 	if ctx.GlobalIsSet(EthashDatasetsLockMmapFlag.Name) {
 		cfg.Ethash.DatasetsLockMmap = ctx.GlobalBool(EthashDatasetsLockMmapFlag.Name)
 	}
+	if ctx.GlobalIsSet(EthashPowModeFlag.Name) {
+		if err := cfg.Ethash.PowMode.UnmarshalText([]byte(ctx.GlobalString(EthashPowModeFlag.Name))); err != nil {
+			Fatalf("Invalid --%s: %v", EthashPowModeFlag.Name, err)
+		}
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1398,6 +1561,45 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.Noverify = ctx.GlobalBool(MinerNoVerfiyFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerTxOrderFlag.Name) {
+		switch order := ctx.GlobalString(MinerTxOrderFlag.Name); order {
+		case miner.TxOrderPrice, miner.TxOrderFIFO:
+			cfg.TxOrder = order
+		default:
+			Fatalf("--%s must be either '%s' or '%s'", MinerTxOrderFlag.Name, miner.TxOrderPrice, miner.TxOrderFIFO)
+		}
+	}
+	if ctx.GlobalIsSet(MinerStratumFlag.Name) {
+		cfg.Stratum = ctx.GlobalString(MinerStratumFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerStratumDifficultyFlag.Name) {
+		cfg.StratumDifficulty = ctx.GlobalUint64(MinerStratumDifficultyFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerCoinbaseListFlag.Name) {
+		cfg.CoinbaseList = nil
+		for _, account := range SplitAndTrim(ctx.GlobalString(MinerCoinbaseListFlag.Name)) {
+			if !common.IsHexAddress(account) {
+				Fatalf("Invalid coinbase address in --%s: %s", MinerCoinbaseListFlag.Name, account)
+			}
+			cfg.CoinbaseList = append(cfg.CoinbaseList, common.HexToAddress(account))
+		}
+	}
+	if ctx.GlobalIsSet(MinerCoinbaseIntervalFlag.Name) {
+		cfg.CoinbaseInterval = ctx.GlobalUint64(MinerCoinbaseIntervalFlag.Name)
+	}
+}
+
+// setFederationSigner retrieves the federated checkpoint signer from the CLI
+// flags into the config.
+func setFederationSigner(ctx *cli.Context, cfg *fourtwenty.Config) {
+	if !ctx.GlobalIsSet(FederationSignerFlag.Name) {
+		return
+	}
+	signer := ctx.GlobalString(FederationSignerFlag.Name)
+	if !common.IsHexAddress(signer) {
+		Fatalf("Invalid federation signer address %q", signer)
+	}
+	cfg.FederationSigner = common.HexToAddress(signer)
 }
 
 func setWhitelist(ctx *cli.Context, cfg *fourtwenty.Config) {
@@ -1494,6 +1696,7 @@ func SetFourtwentyConfig(ctx *cli.Context, stack *node.Node, cfg *fourtwenty.Con
 	setEthash(ctx, cfg)
 	setMiner(ctx, &cfg.Miner)
 	setWhitelist(ctx, cfg)
+	setFederationSigner(ctx, cfg)
 	setLes(ctx, cfg)
 
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
@@ -1509,6 +1712,9 @@ func SetFourtwentyConfig(ctx *cli.Context, stack *node.Node, cfg *fourtwenty.Con
 	if ctx.GlobalIsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
 	}
+	if ctx.GlobalIsSet(DatabaseCompressFlag.Name) {
+		cfg.DatabaseCompress = ctx.GlobalBool(DatabaseCompressFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1528,6 +1734,24 @@ func SetFourtwentyConfig(ctx *cli.Context, stack *node.Node, cfg *fourtwenty.Con
 	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.GlobalIsSet(MaxReorgDepthFlag.Name) {
+		cfg.MaxReorgDepth = ctx.GlobalUint64(MaxReorgDepthFlag.Name)
+	}
+	if ctx.GlobalIsSet(TraceStoreFlag.Name) {
+		cfg.TraceStore = ctx.GlobalBool(TraceStoreFlag.Name)
+	}
+	if ctx.GlobalIsSet(ChainTipAlertDepthFlag.Name) {
+		cfg.ChainTipAlertDepth = ctx.GlobalUint64(ChainTipAlertDepthFlag.Name)
+	}
+	if ctx.GlobalIsSet(OverrideIndicaFlag.Name) {
+		cfg.OverrideIndica = new(big.Int).SetUint64(ctx.GlobalUint64(OverrideIndicaFlag.Name))
+	}
+	if ctx.GlobalIsSet(OverrideSativaFlag.Name) {
+		cfg.OverrideSativa = new(big.Int).SetUint64(ctx.GlobalUint64(OverrideSativaFlag.Name))
+	}
+	if ctx.GlobalIsSet(TraceStoreRetentionFlag.Name) {
+		cfg.TraceStoreRetention = ctx.GlobalUint64(TraceStoreRetentionFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
@@ -1543,6 +1767,12 @@ func SetFourtwentyConfig(ctx *cli.Context, stack *node.Node, cfg *fourtwenty.Con
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheSnapshotFlag.Name) {
 		cfg.SnapshotCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheSnapshotFlag.Name) / 100
 	}
+	if ctx.GlobalIsSet(CacheTxPoolFlag.Name) {
+		cfg.TxPoolCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTxPoolFlag.Name) / 100
+	}
+	if ctx.GlobalIsSet(CacheChainFlag.Name) {
+		cfg.ChainCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheChainFlag.Name) / 100
+	}
 	if !ctx.GlobalIsSet(SnapshotFlag.Name) {
 		// If snap-sync is requested, this flag is also required
 		if cfg.SyncMode == downloader.SnapSync {
@@ -1579,6 +1809,24 @@ func SetFourtwentyConfig(ctx *cli.Context, stack *node.Node, cfg *fourtwenty.Con
 	if ctx.GlobalIsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCStructuredErrorsFlag.Name) {
+		cfg.RPCStructuredErrors = ctx.GlobalBool(RPCStructuredErrorsFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRecipientCheckFlag.Name) {
+		cfg.RPCRecipientCheck = ctx.GlobalBool(RPCRecipientCheckFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCEthCompatFlag.Name) {
+		cfg.RPCEthCompat = ctx.GlobalBool(RPCEthCompatFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTracerStepsFlag.Name) {
+		cfg.RPCTracerSteps = ctx.GlobalUint64(RPCTracerStepsFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCTracerMemoryFlag.Name) {
+		cfg.RPCTracerMemory = ctx.GlobalInt(RPCTracerMemoryFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCNoCustomTracersFlag.Name) {
+		cfg.RPCNoCustomTracers = ctx.GlobalBool(RPCNoCustomTracersFlag.Name)
+	}
 	if ctx.GlobalIsSet(NoDiscoverFlag.Name) {
 		cfg.FourtwentyDiscoveryURLs, cfg.SnapDiscoveryURLs = []string{}, []string{}
 	} else if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
@@ -1767,6 +2015,7 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) fourtwentydb.Database
 		err     error
 		chainDb fourtwentydb.Database
 	)
+	rawdb.SetValueCompression(ctx.GlobalBool(DatabaseCompressFlag.Name))
 	if ctx.GlobalString(SyncModeFlag.Name) == "light" {
 		name := "lightchaindata"
 		chainDb, err = stack.OpenDatabase(name, cache, handles, "")
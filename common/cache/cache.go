@@ -0,0 +1,333 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package cache implements a thread safe, size-aware LRU cache.
+//
+// Every entry is accounted with its approximate size in bytes, which allows the
+// caches of the node to be limited both individually and by a global memory
+// budget shared by all of them.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/420integrated/go-420coin/metrics"
+)
+
+var (
+	budget int64 // Maximum total entry size of all the caches, 0 = unlimited
+	total  int64 // Current total entry size of all the caches
+
+	sized     = make(map[*Cache]struct{}) // Caches holding entries with accounted sizes
+	sizedLock sync.Mutex                  // Protects the set of sized caches
+)
+
+// SetBudget sets the maximum total size in bytes of the entries of all the caches,
+// zero meaning unlimited. Whenever the total exceeds the budget, the least recently
+// used entries of the largest caches are evicted until it's back within it, so the
+// small caches aren't drained by the large ones.
+func SetBudget(bytes int) {
+	atomic.StoreInt64(&budget, int64(bytes))
+	enforceBudget()
+}
+
+// Total returns the current total size in bytes of the entries of all the caches.
+func Total() int {
+	return int(atomic.LoadInt64(&total))
+}
+
+// EvictCallback is called for every entry evicted from a cache to make room for
+// new ones. It is not called for entries explicitly removed or purged.
+type EvictCallback func(key, value interface{})
+
+// entry is a single cached key-value pair with its accounted size.
+type entry struct {
+	key   interface{}
+	value interface{}
+	size  int
+}
+
+// Cache is a thread safe LRU cache limited by the number of entries it holds,
+// the total size of those entries and the global memory budget.
+type Cache struct {
+	items   int // Maximum number of entries, 0 = unlimited
+	bytes   int // Maximum total entry size, 0 = unlimited
+	onEvict EvictCallback
+
+	entries map[interface{}]*list.Element
+	order   *list.List // Entries ordered from most to least recently used
+	size    int        // Total size of the entries
+	lock    sync.Mutex
+
+	hitMeter  metrics.Meter
+	missMeter metrics.Meter
+	sizeGauge metrics.Gauge
+}
+
+// New creates a cache holding at most the given number of entries and bytes,
+// zero meaning unlimited. Hit, miss and size metrics are reported under the given
+// name if it's not empty.
+func New(name string, items, bytes int) *Cache {
+	return NewWithEvict(name, items, bytes, nil)
+}
+
+// NewWithEvict creates a cache like New, calling the given callback for every
+// entry evicted to make room for new ones.
+func NewWithEvict(name string, items, bytes int, onEvict EvictCallback) *Cache {
+	c := &Cache{
+		items:   items,
+		bytes:   bytes,
+		onEvict: onEvict,
+		entries: make(map[interface{}]*list.Element),
+		order:   list.New(),
+	}
+	if name != "" {
+		c.hitMeter = metrics.GetOrRegisterMeter(name+"/hit", nil)
+		c.missMeter = metrics.GetOrRegisterMeter(name+"/miss", nil)
+		c.sizeGauge = metrics.GetOrRegisterGauge(name+"/size", nil)
+	}
+	return c
+}
+
+// Add inserts an entry without any size accounting, only limited by the number
+// of entries. It returns whether any entry was evicted.
+func (c *Cache) Add(key, value interface{}) bool {
+	return c.AddSized(key, value, 0)
+}
+
+// AddSized inserts an entry of the given size in bytes, replacing any previous
+// value of the key. It returns whether any entry of the cache itself was evicted
+// to make room for it, entries of other caches may be evicted to stay within the
+// global budget.
+func (c *Cache) AddSized(key, value interface{}, size int) bool {
+	c.lock.Lock()
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		c.resize(size - e.size)
+		e.value, e.size = value, size
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(&entry{key: key, value: value, size: size})
+		c.resize(size)
+	}
+	var evicted bool
+	for c.order.Len() > 1 && c.overflown() {
+		c.evict()
+		evicted = true
+	}
+	c.lock.Unlock()
+
+	// Staying within the global budget may evict from other caches, which needs
+	// their locks, so it's done after releasing ours
+	if size > 0 {
+		enforceBudget()
+	}
+	return evicted
+}
+
+// overflown returns whether the cache exceeds its own limits.
+func (c *Cache) overflown() bool {
+	if c.items > 0 && c.order.Len() > c.items {
+		return true
+	}
+	if c.bytes > 0 && c.size > c.bytes {
+		return true
+	}
+	return false
+}
+
+// enforceBudget evicts the least recently used entries of the largest caches
+// until the total size of all the caches is within the global budget. Every
+// cache keeps at least its most recently used entry.
+func enforceBudget() {
+	for {
+		if limit := atomic.LoadInt64(&budget); limit <= 0 || atomic.LoadInt64(&total) <= limit {
+			return
+		}
+		if c := largestCache(); c == nil || !c.shrink() {
+			return
+		}
+	}
+}
+
+// largestCache returns the sized cache holding the most bytes which has more
+// than one entry, or nil if there's none.
+func largestCache() *Cache {
+	sizedLock.Lock()
+	caches := make([]*Cache, 0, len(sized))
+	for c := range sized {
+		caches = append(caches, c)
+	}
+	sizedLock.Unlock()
+
+	var (
+		largest *Cache
+		most    int
+	)
+	for _, c := range caches {
+		c.lock.Lock()
+		if c.order.Len() > 1 && c.size > most {
+			largest, most = c, c.size
+		}
+		c.lock.Unlock()
+	}
+	return largest
+}
+
+// shrink evicts the least recently used entry, unless it's the only one left. It
+// returns whether an entry was evicted.
+func (c *Cache) shrink() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.order.Len() <= 1 {
+		return false
+	}
+	c.evict()
+	return true
+}
+
+// evict drops the least recently used entry. The lock must be held.
+func (c *Cache) evict() {
+	elem := c.order.Back()
+	e := elem.Value.(*entry)
+
+	c.order.Remove(elem)
+	delete(c.entries, e.key)
+	c.resize(-e.size)
+
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+}
+
+// resize updates the size of the cache and the global total, tracking whether
+// the cache holds any sized entries. The lock must be held.
+func (c *Cache) resize(delta int) {
+	if delta == 0 {
+		return
+	}
+	empty := c.size == 0
+	c.size += delta
+	atomic.AddInt64(&total, int64(delta))
+
+	if empty != (c.size == 0) {
+		sizedLock.Lock()
+		if c.size == 0 {
+			delete(sized, c)
+		} else {
+			sized[c] = struct{}{}
+		}
+		sizedLock.Unlock()
+	}
+	if c.sizeGauge != nil {
+		c.sizeGauge.Update(int64(c.size))
+	}
+}
+
+// Get looks up the value of a key, marking it as recently used.
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		if c.missMeter != nil {
+			c.missMeter.Mark(1)
+		}
+		return nil, false
+	}
+	if c.hitMeter != nil {
+		c.hitMeter.Mark(1)
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry).value, true
+}
+
+// Peek looks up the value of a key without marking it as recently used.
+func (c *Cache) Peek(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		return elem.Value.(*entry).value, true
+	}
+	return nil, false
+}
+
+// Contains checks whether a key is cached without marking it as recently used.
+func (c *Cache) Contains(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.entries[key]
+	return ok
+}
+
+// Remove drops a key from the cache, returning whether it was present.
+func (c *Cache) Remove(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	c.order.Remove(elem)
+	delete(c.entries, key)
+	c.resize(-elem.Value.(*entry).size)
+	return true
+}
+
+// Purge drops all the entries from the cache.
+func (c *Cache) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.resize(-c.size)
+	c.entries = make(map[interface{}]*list.Element)
+	c.order.Init()
+}
+
+// Keys returns the cached keys, from the least to the most recently used.
+func (c *Cache) Keys() []interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := make([]interface{}, 0, len(c.entries))
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		keys = append(keys, elem.Value.(*entry).key)
+	}
+	return keys
+}
+
+// Len returns the number of cached entries.
+func (c *Cache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+// Size returns the total size in bytes of the cached entries.
+func (c *Cache) Size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.size
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests that the least recently used entries are evicted once the entry limit is
+// exceeded, calling the eviction callback.
+func TestItemLimit(t *testing.T) {
+	var evicted []interface{}
+	c := NewWithEvict("", 2, 0, func(key, value interface{}) { evicted = append(evicted, key) })
+
+	c.Add(1, "one")
+	c.Add(2, "two")
+	c.Get(1)
+	if !c.Add(3, "three") {
+		t.Fatalf("no eviction reported")
+	}
+	if !reflect.DeepEqual(evicted, []interface{}{2}) {
+		t.Fatalf("evicted keys mismatch: have %v, want [2]", evicted)
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []interface{}{1, 3}) {
+		t.Fatalf("keys mismatch: have %v, want [1 3]", keys)
+	}
+}
+
+// Tests that entries are evicted once their total size exceeds the byte limit,
+// and that replacing or removing entries updates the accounted size.
+func TestByteLimit(t *testing.T) {
+	c := New("", 0, 100)
+	defer c.Purge()
+
+	c.AddSized("a", nil, 40)
+	c.AddSized("b", nil, 40)
+	if size := c.Size(); size != 80 {
+		t.Fatalf("size mismatch: have %d, want 80", size)
+	}
+	c.AddSized("a", nil, 50)
+	if size := c.Size(); size != 90 {
+		t.Fatalf("resized entry size mismatch: have %d, want 90", size)
+	}
+	c.AddSized("c", nil, 30)
+	if c.Contains("b") || !c.Contains("a") || !c.Contains("c") {
+		t.Fatalf("wrong entry evicted: keys %v", c.Keys())
+	}
+	c.Remove("a")
+	if size := c.Size(); size != 30 {
+		t.Fatalf("size after removal mismatch: have %d, want 30", size)
+	}
+	// An entry larger than the limit is still kept, but evicts everything else
+	c.AddSized("d", nil, 200)
+	if c.Len() != 1 || !c.Contains("d") {
+		t.Fatalf("oversized entry handling mismatch: keys %v", c.Keys())
+	}
+}
+
+// Tests that the global budget is enforced across caches, without affecting the
+// caches which don't account for sizes.
+func TestBudget(t *testing.T) {
+	SetBudget(100)
+	defer SetBudget(0)
+
+	var (
+		first  = New("", 0, 0)
+		second = New("", 0, 0)
+		counts = New("", 0, 0)
+	)
+	defer first.Purge()
+	defer second.Purge()
+
+	counts.Add("x", nil)
+	first.AddSized(1, nil, 60)
+	second.AddSized(1, nil, 30)
+	second.AddSized(2, nil, 30)
+	if total := Total(); total != 90 {
+		t.Fatalf("total mismatch: have %d, want 90", total)
+	}
+	if first.Len() != 1 || second.Len() != 1 || !second.Contains(2) || counts.Len() != 1 {
+		t.Fatalf("budget eviction mismatch: first %v, second %v, counts %v", first.Keys(), second.Keys(), counts.Keys())
+	}
+	first.Purge()
+	if total := Total(); total != 30 {
+		t.Fatalf("total after purge mismatch: have %d, want 30", total)
+	}
+}
+
+// Tests that exceeding the global budget evicts from the largest caches first, so
+// small caches aren't drained, and that every cache keeps its most recent entry.
+func TestBudgetLargestFirst(t *testing.T) {
+	SetBudget(100)
+	defer SetBudget(0)
+
+	var (
+		large = New("", 0, 0)
+		small = New("", 0, 0)
+	)
+	defer large.Purge()
+	defer small.Purge()
+
+	for i := 0; i < 8; i++ {
+		large.AddSized(i, nil, 10)
+	}
+	for i := 0; i < 5; i++ {
+		small.AddSized(i, nil, 5)
+	}
+	if total := Total(); total != 95 {
+		t.Fatalf("total mismatch: have %d, want 95", total)
+	}
+	if large.Len() != 7 || large.Contains(0) || small.Len() != 5 {
+		t.Fatalf("budget eviction mismatch: large %v, small %v", large.Keys(), small.Keys())
+	}
+	// Shrinking the budget evicts right away, down to one entry per cache
+	SetBudget(10)
+	if large.Len() != 1 || !large.Contains(7) || small.Len() != 1 || !small.Contains(4) {
+		t.Fatalf("budget shrink mismatch: large %v, small %v", large.Keys(), small.Keys())
+	}
+}
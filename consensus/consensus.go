@@ -23,6 +23,7 @@ import (
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core/state"
 	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rpc"
 )
@@ -129,3 +130,20 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// FeeRouter is an optional interface of consensus engines that route a share of
+// the transaction fees away from the coinbase, as enabled by the chain config.
+type FeeRouter interface {
+	// FeeFund returns the account the routed share of the transaction fees of the
+	// given block is paid to, as resolved from the given state.
+	FeeFund(chain ChainHeaderReader, header *types.Header, state vm.StateDB) common.Address
+}
+
+// RewardVerifier is an optional interface of consensus engines that can check the
+// rewards credited to the recipients of a block against their emission schedule.
+type RewardVerifier interface {
+	// VerifyRewards runs the given payout of the rewards of the given block and its
+	// uncles, checking the balance changes it makes in the given state, and the
+	// accounts the rewards are paid to as resolved from it.
+	VerifyRewards(chain ChainHeaderReader, header *types.Header, uncles []*types.Header, state vm.StateDB, payout func()) error
+}
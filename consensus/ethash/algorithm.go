@@ -174,7 +174,15 @@ func generateCache(dest []uint32, epoch uint64, seed []byte) {
 			case <-done:
 				return
 			case <-time.After(3 * time.Second):
-				logger.Info("Generating ethash verification cache", "percentage", atomic.LoadUint32(&progress)*100/uint32(rows)/4, "elapsed", common.PrettyDuration(time.Since(start)))
+				var (
+					percentage = atomic.LoadUint32(&progress) * 100 / uint32(rows) / 4
+					elapsed    = time.Since(start)
+					eta        time.Duration
+				)
+				if percentage > 0 {
+					eta = elapsed * time.Duration(100-percentage) / time.Duration(percentage)
+				}
+				logger.Info("Generating ethash verification cache", "percentage", percentage, "elapsed", common.PrettyDuration(elapsed), "eta", common.PrettyDuration(eta))
 			}
 		}
 	}()
@@ -0,0 +1,197 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// cacheChecksumHeader is the HTTP header carrying the hex encoded SHA256 checksum
+// of a served verification cache file.
+const cacheChecksumHeader = "X-Ethash-Checksum"
+
+// cacheFetchTimeout is the maximum time allowed to download a single cache.
+const cacheFetchTimeout = 5 * time.Minute
+
+// cacheFileRegexp matches the names of the completed verification cache files,
+// excluding the temporary ones still being generated.
+var cacheFileRegexp = regexp.MustCompile(`^cache-R\d+-[0-9a-f]{16}(\.be)?$`)
+
+// cacheServer serves the verification caches of a cache directory over HTTP,
+// along with their checksums.
+type cacheServer struct {
+	dir  string
+	sums map[string]string // Checksums of already served files, which are immutable
+	lock sync.Mutex
+}
+
+// CacheHandler returns an HTTP handler serving the verification caches stored on
+// disk, for other nodes configured with a CacheURL pointing to it to fetch them
+// instead of generating them.
+func (ethash *Ethash) CacheHandler() http.Handler {
+	return &cacheServer{dir: ethash.config.CacheDir, sums: make(map[string]string)}
+}
+
+// ServeHTTP implements http.Handler, serving the cache file named by the last
+// element of the request path.
+func (s *cacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+	if s.dir == "" || !cacheFileRegexp.MatchString(name) {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sum, err := s.checksum(name, file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(cacheChecksumHeader, sum)
+	http.ServeContent(w, r, name, stat.ModTime(), file)
+}
+
+// checksum returns the hex encoded SHA256 checksum of a cache file, rewinding it
+// to the start if it needed to be read.
+func (s *cacheServer) checksum(name string, file *os.File) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sum, ok := s.sums[name]; ok {
+		return sum, nil
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	s.sums[name] = sum
+	return sum, nil
+}
+
+// progressReader counts the bytes read through it.
+type progressReader struct {
+	r    io.Reader
+	read uint64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	atomic.AddUint64(&p.read, uint64(n))
+	return n, err
+}
+
+// fetchCache downloads the verification cache stored at path from a remote node
+// serving them at url. The download is checked against the expected size and
+// the checksum advertised by the server before being moved into place. As the
+// checksum comes from the same server, it only guards against transfer errors,
+// the cache itself must not be trusted to reject seals.
+func fetchCache(url string, path string, size uint64, logger log.Logger) error {
+	client := &http.Client{Timeout: cacheFetchTimeout}
+	res, err := client.Get(strings.TrimSuffix(url, "/") + "/" + filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	want := res.Header.Get(cacheChecksumHeader)
+	if want == "" {
+		return errors.New("missing cache checksum")
+	}
+	expected := int64(len(dumpMagic))*4 + int64(size)
+	if res.ContentLength >= 0 && res.ContentLength != expected {
+		return fmt.Errorf("cache size mismatch: have %d, want %d", res.ContentLength, expected)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	temp := path + "." + strconv.Itoa(rand.Int())
+	dump, err := os.Create(temp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp)
+
+	// Download the cache, reporting progress on slow links
+	var (
+		start  = time.Now()
+		body   = &progressReader{r: io.LimitReader(res.Body, expected+1)}
+		hasher = sha256.New()
+		done   = make(chan struct{})
+	)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(3 * time.Second):
+				logger.Info("Fetching ethash verification cache", "percentage", atomic.LoadUint64(&body.read)*100/uint64(expected), "elapsed", common.PrettyDuration(time.Since(start)))
+			}
+		}
+	}()
+	n, err := io.Copy(io.MultiWriter(dump, hasher), body)
+	close(done)
+	if err != nil {
+		dump.Close()
+		return err
+	}
+	if err := dump.Close(); err != nil {
+		return err
+	}
+	if n != expected {
+		return fmt.Errorf("cache size mismatch: have %d, want %d", n, expected)
+	}
+	if have := hex.EncodeToString(hasher.Sum(nil)); have != want {
+		return fmt.Errorf("cache checksum mismatch: have %s, want %s", have, want)
+	}
+	logger.Info("Fetched ethash verification cache", "url", url, "elapsed", common.PrettyDuration(time.Since(start)))
+	return os.Rename(temp, path)
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// Tests that verification caches served by one node can be fetched by another,
+// and that temporary or corrupted files are rejected.
+func TestCacheSharing(t *testing.T) {
+	serverDir, err := ioutil.TempDir("", "ethash-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(serverDir)
+	clientDir, err := ioutil.TempDir("", "ethash-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(clientDir)
+
+	// Generate a cache on the server and share it
+	local := &cache{epoch: 0}
+	local.generate(serverDir, "", 1, false, true)
+
+	var (
+		server  = &Ethash{config: Config{CacheDir: serverDir}}
+		handler = server.CacheHandler()
+		served  int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&served, 1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	// Fetch the cache on the client and ensure it matches
+	remote := &cache{epoch: 0}
+	remote.generate(clientDir, srv.URL, 1, false, true)
+	if n := atomic.LoadInt32(&served); n != 1 {
+		t.Fatalf("cache requests mismatch: have %d, want 1", n)
+	}
+	if !reflect.DeepEqual(local.cache, remote.cache) {
+		t.Fatalf("fetched cache mismatch")
+	}
+	files, _ := ioutil.ReadDir(clientDir)
+	if len(files) != 1 || !cacheFileRegexp.MatchString(files[0].Name()) {
+		t.Fatalf("unexpected client cache files: %v", files)
+	}
+	// Only completed cache files must be served
+	res, err := http.Get(srv.URL + "/" + files[0].Name() + ".1234")
+	if err != nil {
+		t.Fatalf("failed to request temporary file: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("temporary file served: status %d", res.StatusCode)
+	}
+	// Caches failing the checksum must be rejected
+	corrupt := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(&checksumOverride{w}, r)
+	}))
+	defer corrupt.Close()
+
+	path := filepath.Join(clientDir, "corrupt", files[0].Name())
+	if err := fetchCache(corrupt.URL, path, 1024, log.Root()); err == nil {
+		t.Errorf("corrupt cache accepted")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("corrupt cache stored: %v", err)
+	}
+}
+
+// checksumOverride is a response writer replacing the cache checksum set by the
+// cache server with an invalid one.
+type checksumOverride struct {
+	http.ResponseWriter
+}
+
+func (w *checksumOverride) WriteHeader(status int) {
+	w.Header().Set(cacheChecksumHeader, "00")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Tests that a fetched cache rejecting a valid seal is replaced by a locally
+// generated one instead of getting the seal rejected, as the server vouching for
+// the cache can't be trusted.
+func TestFetchedCacheRegeneration(t *testing.T) {
+	serverDir, err := ioutil.TempDir("", "ethash-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(serverDir)
+	clientDir, err := ioutil.TempDir("", "ethash-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(clientDir)
+
+	client := New(Config{CacheDir: clientDir, CachesInMem: 1, CachesOnDisk: 1, PowMode: ModeTest}, nil, false)
+	defer client.Close()
+
+	// Seal a header with a valid cache, then corrupt the cache on the server
+	local := &cache{epoch: 0}
+	local.generate(serverDir, "", 1, false, true)
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	digest, _ := hashimotoLight(32*1024, local.cache, client.SealHash(header).Bytes(), header.Nonce.Uint64())
+	header.MixDigest = common.BytesToHash(digest)
+
+	files, _ := ioutil.ReadDir(serverDir)
+	if len(files) != 1 {
+		t.Fatalf("unexpected server cache files: %v", files)
+	}
+	name := files[0].Name()
+	file, err := os.OpenFile(filepath.Join(serverDir, name), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteAt(make([]byte, 64), int64(len(dumpMagic))*4); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	var (
+		server  = &Ethash{config: Config{CacheDir: serverDir}}
+		handler = server.CacheHandler()
+		served  int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filepath.Base(r.URL.Path) == name {
+			atomic.AddInt32(&served, 1) // Ignore the future cache requested in the background
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	client.config.CacheURL = srv.URL
+
+	// The bogus cache passes the transfer checks, but must not reject the seal
+	if err := client.verifySeal(nil, header, false); err != nil {
+		t.Fatalf("valid seal rejected: %v", err)
+	}
+	if n := atomic.LoadInt32(&served); n != 1 {
+		t.Fatalf("cache requests mismatch: have %d, want 1", n)
+	}
+	if client.cache(1).fetched {
+		t.Errorf("fetched cache still in use after rejecting a valid seal")
+	}
+	if _, err := os.Stat(filepath.Join(clientDir, name+fetchedSuffix)); !os.IsNotExist(err) {
+		t.Errorf("regenerated cache still marked as fetched: %v", err)
+	}
+	// Invalid seals must still be rejected
+	header.MixDigest = common.Hash{}
+	if err := client.verifySeal(nil, header, false); err != errInvalidMixDigest {
+		t.Errorf("invalid seal error mismatch: have %v, want %v", err, errInvalidMixDigest)
+	}
+}
@@ -25,7 +25,6 @@ import (
 	"time"
 
 	mapset "github.com/deckarep/golang-set"
-	"github.com/420integrated/go-420coin/crypto"
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/math"
 	"github.com/420integrated/go-420coin/consensus"
@@ -52,6 +51,9 @@ initiating approximately 1 year from Genesis
 Miners: 75%
 Veterans Fund: 15%
 Followers: 10%
+
+The era boundaries and splits applied are params.DefaultRewardEras, unless the
+chain config defines its own.
 */
 
 // Ethash proof-of-work protocol constants.
@@ -64,36 +66,24 @@ var (
   
         rewardBlockDivisor *big.Int    = big.NewInt(100000)
         rewardBlockFlat *big.Int       = big.NewInt(1000000)
-        // Reward split between Miners, Veterans Fund and, following Cannasseur Network initiation, "Followers"
-        rewardDistMinerRuderalis *big.Int  = big.NewInt(87) // 87% of 9 420coins (7.83 420coin)
-        rewardDistMinerIndica *big.Int     = big.NewInt(80) // 80% of 9 420coins (7.20 420coin)
-        rewardDistCannasseurBlock *big.Int = big.NewInt(1111111) // approximately 6 months following Genesis
-	rewardDistFollower *big.Int    = big.NewInt(7) // 7% of 9 420coin (0.63 420coin)
-	rewardDistVet *big.Int         = big.NewInt(13) // 13% of 9 420coin (1.17 420coin)
-        indicaForkBlock *big.Int       = big.NewInt(1111111)
-	// Final Reward status
-	sativaForkBlock *big.Int          = big.NewInt(2102400) // approximately 1 year following Genesis
-	sativaRewardDistFollower *big.Int = big.NewInt(15) // 15% of block reward - 1.35 420coins
-	sativaRewardDistVet *big.Int      = big.NewInt(10) // 10% of block reward - 0.9 420coins
-	sativaRewardDistMiner *big.Int    = big.NewInt(75) // 75% of block reward - 6.75 420coins
-	
+
 	// calcDifficultyEip2384 is the difficulty adjustment algorithm as specified by EIP 2384.
 	// It offsets the bomb 4M blocks from Constantinople, so in total 9M blocks.
 	// Specification EIP-2384: https://eips.ethereum.org/EIPS/eip-2384
-	calcDifficultyEip2384 = makeDifficultyCalculator(big.NewInt(9000000))
+	calcDifficultyEip2384 = MakeDifficultyCalculatorU256(big.NewInt(9000000))
 
 	// calcDifficultyConstantinople is the difficulty adjustment algorithm for Constantinople.
 	// It returns the difficulty that a new block should have when created at time given the
 	// parent block's time and difficulty. The calculation uses the Byzantium rules, but with
 	// bomb offset 5M.
 	// Specification EIP-1234: https://eips.ethereum.org/EIPS/eip-1234
-	calcDifficultyConstantinople = makeDifficultyCalculator(big.NewInt(5000000))
+	calcDifficultyConstantinople = MakeDifficultyCalculatorU256(big.NewInt(5000000))
 
 	// calcDifficultyByzantium is the difficulty adjustment algorithm. It returns
 	// the difficulty that a new block should have when created at time given the
 	// parent block's time and difficulty. The calculation uses the Byzantium rules.
 	// Specification EIP-649: https://eips.ethereum.org/EIPS/eip-649
-	calcDifficultyByzantium = makeDifficultyCalculator(big.NewInt(3000000))
+	calcDifficultyByzantium = MakeDifficultyCalculatorU256(big.NewInt(3000000))
 )
 
 // Various error messages to mark blocks invalid. These should be private to
@@ -153,47 +143,67 @@ func (ethash *Ethash) VerifyHeaders(chain consensus.ChainHeaderReader, headers [
 		return abort, results
 	}
 
+	return verifyConcurrently(len(headers), func(index int) error {
+		return ethash.verifyHeaderWorker(chain, headers, seals, index)
+	})
+}
+
+// VerifySeals checks the PoW seals of a batch of headers concurrently, without
+// requiring their ancestors to be known. The method returns a quit channel to
+// abort the operations and a results channel to retrieve the async verifications.
+func (ethash *Ethash) VerifySeals(headers []*types.Header) (chan<- struct{}, <-chan error) {
+	return verifyConcurrently(len(headers), func(index int) error {
+		return ethash.verifySeal(nil, headers[index], false)
+	})
+}
+
+// verifyConcurrently runs the given verification for count items on as many
+// workers as allowed threads, delivering the results in order.
+func verifyConcurrently(count int, verify func(index int) error) (chan<- struct{}, <-chan error) {
 	// Spawn as many workers as allowed threads
 	workers := runtime.GOMAXPROCS(0)
-	if len(headers) < workers {
-		workers = len(headers)
+	if count < workers {
+		workers = count
 	}
 
 	// Create a task channel and spawn the verifiers
 	var (
 		inputs = make(chan int)
 		done   = make(chan int, workers)
-		errors = make([]error, len(headers))
+		errors = make([]error, count)
 		abort  = make(chan struct{})
 	)
 	for i := 0; i < workers; i++ {
 		go func() {
 			for index := range inputs {
-				errors[index] = ethash.verifyHeaderWorker(chain, headers, seals, index)
+				errors[index] = verify(index)
 				done <- index
 			}
 		}()
 	}
 
-	errorsOut := make(chan error, len(headers))
+	errorsOut := make(chan error, count)
+	if count == 0 {
+		return abort, errorsOut
+	}
 	go func() {
 		defer close(inputs)
 		var (
 			in, out = 0, 0
-			checked = make([]bool, len(headers))
+			checked = make([]bool, count)
 			inputs  = inputs
 		)
 		for {
 			select {
 			case inputs <- in:
-				if in++; in == len(headers) {
-					// Reached end of headers. Stop sending to workers.
+				if in++; in == count {
+					// Reached end of items. Stop sending to workers.
 					inputs = nil
 				}
 			case index := <-done:
 				for checked[index] = true; checked[out]; out++ {
 					errorsOut <- errors[out]
-					if out == len(headers)-1 {
+					if out == count-1 {
 						return
 					}
 				}
@@ -307,15 +317,24 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 	if header.SmokeUsed > header.SmokeLimit {
 		return fmt.Errorf("invalid smokeUsed: have %d, smokeLimit %d", header.SmokeUsed, header.SmokeLimit)
 	}
-	// Verify that the smoke limit remains within allowed bounds
-	diff := int64(parent.SmokeLimit) - int64(header.SmokeLimit)
-	if diff < 0 {
-		diff *= -1
-	}
-	limit := parent.SmokeLimit / params.SmokeLimitBoundDivisor
+	// Verify the smoke limit and the base fee, which are bound together once the
+	// fee market is active
+	if !chain.Config().IsSmokeBaseFee(header.Number) {
+		// Verify that the smoke limit remains within allowed bounds
+		diff := int64(parent.SmokeLimit) - int64(header.SmokeLimit)
+		if diff < 0 {
+			diff *= -1
+		}
+		limit := parent.SmokeLimit / params.SmokeLimitBoundDivisor
 
-	if uint64(diff) >= limit || header.SmokeLimit < params.MinSmokeLimit {
-		return fmt.Errorf("invalid smoke limit: have %d, want %d += %d", header.SmokeLimit, parent.SmokeLimit, limit)
+		if uint64(diff) >= limit || header.SmokeLimit < params.MinSmokeLimit {
+			return fmt.Errorf("invalid smoke limit: have %d, want %d += %d", header.SmokeLimit, parent.SmokeLimit, limit)
+		}
+		if header.BaseFee != nil {
+			return fmt.Errorf("invalid baseFee before fork: have %d, want <nil>", header.BaseFee)
+		}
+	} else if err := misc.VerifySmokeBaseFeeHeader(chain.Config(), parent, header); err != nil {
+		return err
 	}
         // Verify that the block number is parent's +1
 	if diff := new(big.Int).Sub(header.Number, parent.Number); diff.Cmp(big.NewInt(1)) != 0 {
@@ -346,7 +365,8 @@ func (ethash *Ethash) CalcDifficulty(chain consensus.ChainHeaderReader, time uin
 
 // CalcDifficulty is the difficulty adjustment algorithm. It returns
 // the difficulty that a new block should have when created at time
-// given the parent block's time and difficulty.
+// given the parent block's time and difficulty. The time must not be
+// older than the parent's, which header verification checks beforehand.
 func CalcDifficulty(config *params.ChainConfig, time uint64, parent *types.Header) *big.Int {
 	next := new(big.Int).Add(parent.Number, big1)
 	switch {
@@ -357,9 +377,9 @@ func CalcDifficulty(config *params.ChainConfig, time uint64, parent *types.Heade
 	case config.IsByzantium(next):
 		return calcDifficultyByzantium(time, parent)
 	case config.IsHomestead(next):
-		return calcDifficultyHomestead(time, parent)
+		return CalcDifficultyHomesteadU256(time, parent)
 	default:
-		return calcDifficultyFrontier(time, parent)
+		return CalcDifficultyFrontierU256(time, parent)
 	}
 }
 
@@ -567,8 +587,21 @@ func (ethash *Ethash) verifySeal(chain consensus.ChainHeaderReader, header *type
 			fulldag = false
 		}
 	}
-	// If slow-but-light PoW verification was requested (or DAG not yet ready), use an ethash cache
-	if !fulldag {
+	// If the dataset items are retrieved remotely, verify with them instead of a cache
+	if source := ethash.powItemSource(); !fulldag && source != nil {
+		items, err := source(header)
+		if err != nil {
+			return err
+		}
+		size := datasetSize(number)
+		if ethash.config.PowMode == ModeTest {
+			size = 32 * 1024
+		}
+		if digest, result, err = hashimotoItems(size, items, ethash.SealHash(header).Bytes(), header.Nonce.Uint64()); err != nil {
+			return err
+		}
+	} else if !fulldag {
+		// If slow-but-light PoW verification was requested (or DAG not yet ready), use an ethash cache
 		cache := ethash.cache(number)
 
 		size := datasetSize(number)
@@ -580,8 +613,23 @@ func (ethash *Ethash) verifySeal(chain consensus.ChainHeaderReader, header *type
 		// Caches are unmapped in a finalizer. Ensure that the cache stays alive
 		// until after the call to hashimotoLight so it's not unmapped while being used.
 		runtime.KeepAlive(cache)
+
+		// A cache fetched from a remote node may be bogus, so never reject a seal
+		// based on it. Regenerate it locally instead and check again.
+		if cache.fetched && checkSeal(header, digest, result) != nil {
+			ethash.config.Log.Warn("Seal rejected by fetched ethash cache, regenerating", "epoch", cache.epoch, "number", number)
+
+			cache = ethash.regenerateCache(cache)
+			digest, result = hashimotoLight(size, cache.cache, ethash.SealHash(header).Bytes(), header.Nonce.Uint64())
+			runtime.KeepAlive(cache)
+		}
 	}
-	// Verify the calculated values against the ones provided in the header
+	return checkSeal(header, digest, result)
+}
+
+// checkSeal verifies the mix digest and proof-of-work result computed for a
+// header against the ones it claims.
+func checkSeal(header *types.Header, digest []byte, result []byte) error {
 	if !bytes.Equal(header.MixDigest[:], digest) {
 		return errInvalidMixDigest
 	}
@@ -592,6 +640,34 @@ func (ethash *Ethash) verifySeal(chain consensus.ChainHeaderReader, header *type
 	return nil
 }
 
+// Hashimoto computes the mix digest and proof-of-work result of the given seal
+// hash and nonce at a block number using the light verification cache. Unlike
+// seal verification, it allows checking work against arbitrary targets, such as
+// the share difficulty of a mining pool. Fake modes don't compute any hashes and
+// return empty values.
+func (ethash *Ethash) Hashimoto(number uint64, sealhash common.Hash, nonce uint64) (common.Hash, common.Hash) {
+	if ethash.config.PowMode == ModeFake || ethash.config.PowMode == ModeFullFake {
+		return common.Hash{}, common.Hash{}
+	}
+	// If running a shared PoW, delegate hashing to it
+	if ethash.shared != nil {
+		return ethash.shared.Hashimoto(number, sealhash, nonce)
+	}
+	cache := ethash.localCache(number)
+
+	size := datasetSize(number)
+	if ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	digest, result := hashimotoLight(size, cache.cache, sealhash.Bytes(), nonce)
+
+	// Caches are unmapped in a finalizer. Ensure that the cache stays alive
+	// until after the call to hashimotoLight so it's not unmapped while being used.
+	runtime.KeepAlive(cache)
+
+	return common.BytesToHash(digest), common.BytesToHash(result)
+}
+
 // Prepare implements consensus.Engine, initializing the difficulty field of a
 // header to conform to the ethash protocol. The changes are done inline.
 func (ethash *Ethash) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -607,8 +683,7 @@ func (ethash *Ethash) Prepare(chain consensus.ChainHeaderReader, header *types.H
 // setting the final state on the header
 func (ethash *Ethash) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
 	// Accumulate block and uncle rewards then commit the final state root
-	vaultState := chain.GetHeaderByNumber(0)
-	AccumulateNewRewards(chain.Config(), state, header, uncles, vaultState)
+	AccumulateNewRewards(chain.Config(), state, header, uncles, rewardGenesis(chain, header.Number))
 	// Header complete, assemble into a block and return
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 }
@@ -627,7 +702,7 @@ func (ethash *Ethash) FinalizeAndAssemble(chain consensus.ChainHeaderReader, hea
 func (ethash *Ethash) SealHash(header *types.Header) (hash common.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
 
-	rlp.Encode(hasher, []interface{}{
+	enc := []interface{}{
 		header.ParentHash,
 		header.UncleHash,
 		header.Coinbase,
@@ -641,171 +716,26 @@ func (ethash *Ethash) SealHash(header *types.Header) (hash common.Hash) {
 		header.SmokeUsed,
 		header.Time,
 		header.Extra,
-	})
+	}
+	if header.BaseFee != nil {
+		enc = append(enc, header.BaseFee)
+	}
+	rlp.Encode(hasher, enc)
 	hasher.Sum(hash[:0])
 	return hash
 }
 
-// Some weird constants to avoid constant memory allocs for them.
-var (
-	big8  = big.NewInt(8)
-	big32 = big.NewInt(32)
-)
-
 // AccumulateRewards credits the coinbase of the given block with the mining
 // reward. The total reward consists of the static block reward and rewards for
 // included uncles. The coinbase of each uncle block is also rewarded.
 func AccumulateNewRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, uncles []*types.Header, genesisHeader *types.Header) {
 	// Select the correct block reward and proportion of reward to parties based on chain progression
-	creatorAddress := common.BytesToAddress(genesisHeader.Extra)
-	contractAddress := crypto.CreateAddress(creatorAddress, 0)
-	changeAtBlock := state.GetState(contractAddress, common.BytesToHash([]byte{0})).Big()
-	var vetRewardAddress common.Address
-	var followerRewardAddress common.Address
-	if (header.Number.Cmp(changeAtBlock) == 1) {
-		vetAddrBytes := state.GetState(contractAddress, common.BytesToHash([]byte{1})).Bytes()
-		vetRewardAddress = common.BytesToAddress(vetAddrBytes[len(vetAddrBytes)-20:])
-		followerAddrBytes := state.GetState(contractAddress, common.BytesToHash([]byte{2})).Bytes()
-		followerRewardAddress = common.BytesToAddress(followerAddrBytes[len(followerAddrBytes)-20:])
-	} else {
-		vetAddrBytesprev := state.GetState(contractAddress, common.BytesToHash([]byte{3})).Bytes()
-		vetRewardAddress = common.BytesToAddress(vetAddrBytesprev[len(vetAddrBytesprev)-20:])
-		followerAddrBytesprev := state.GetState(contractAddress, common.BytesToHash([]byte{4})).Bytes()
-		followerRewardAddress = common.BytesToAddress(followerAddrBytesprev[len(followerAddrBytesprev)-20:])
-	}
-	//fmt.Println(header.Number, "header Number")
-	//fmt.Println(changeAtBlock, "changeAtBlock")
-	//fmt.Println(devRewardAddress.Hex(), "devRewardAddress")
-	//fmt.Println(followerRewardAddress.Hex(), "followerRewardAddress")
-	//fmt.Println("###################################################")
-
-        initialBlockReward := new(big.Int)
-        initialBlockReward.SetString("9000000000000000000",10)	
+	vetRewardAddress, followerRewardAddress := RewardAddresses(config, state, header.Number, genesisHeader)
+
 	// Accumulate the rewards for the miner and any included uncles
-	reward := new(big.Int)
-	headerRew := new(big.Int)
-        headerRew.Div(header.Number, rewardBlockDivisor)
-        if (header.Number.Cmp(SlowStart)  == -1 || header.Number.Cmp(SlowStart)  == 0) {
-            reward = reward.Set(slowBlockReward)
-        } else if (header.Number.Cmp(rewardBlockFlat) == 1) {
-            reward = reward.Set(SativaBlockReward)
-        } else {
-    	    headerRew.Mul(headerRew, slowBlockReward)
-            reward = reward.Sub(initialBlockReward, headerRew)
-    }
-	//fmt.Println(header.Number, reward)
-	r := new(big.Int)
-	minerReward := new(big.Int)
-        contractReward :=new(big.Int)
-        contractRewardSplit := new(big.Int)
-		sativaVetReward := new(big.Int)
-		sativaFollowerReward := new(big.Int)
-        cumulativeReward := new(big.Int)
-        rewardDivisor := big.NewInt(100)
-        // if block.Number > 1111111
-        if (header.Number.Cmp(rewardDistCannasseurBlock) == 1) {
-		if (header.Number.Cmp(sativaForkBlock) == 1) {
-	for _, uncle := range uncles {
-		r.Add(uncle.Number, big8)
-		r.Sub(r, header.Number)
-		r.Mul(r, reward)
-		r.Div(r, big8)
-		        // calcuting miner reward Post Sativa Fork Block
-		        minerReward.Mul(r, sativaRewardDistMiner)
-		        minerReward.Div(minerReward, rewardDivisor)
-		        // calculating rewards to be sent to Veterans Fund contract Post Sativa Fork
-			sativaVetReward.Mul(r, sativaRewardDistVet)
-			sativaVetReward.Div(sativaVetReward, rewardDivisor)
-			// Calculating "followers" rewards to be sent to the Cannasseur Network contract post Sativa Fork
-			sativaFollowerReward.Mul(r, sativaRewardDistFollower)
-			sativaFollowerReward.Div(sativaFollowerReward, rewardDivisor)
-		state.AddBalance(uncle.Coinbase, minerReward)
-		state.AddBalance(vetRewardAddress, sativaVetReward)
-		state.AddBalance(followerRewardAddress, sativaFollowerReward)
-		r.Div(reward, big32)
-		reward.Add(reward, r)
-	}
-                        // calcuting miner reward Post Indica Block
-                        // calcuting miner reward Post Sativa Fork Block
-	                minerReward.Mul(reward, sativaRewardDistMiner)
-	                minerReward.Div(minerReward, rewardDivisor)
-		        // calculating rewards to be sent to Veterans Fund contract Post Sativa Fork
-		        sativaVetReward.Mul(reward, sativaRewardDistVet)
-		        sativaVetReward.Div(sativaVetReward, rewardDivisor)
-		        // Calculating follower rewards to be sent to the contract post Sativa Fork
-		        sativaFollowerReward.Mul(reward, sativaRewardDistFollower)
-		        sativaFollowerReward.Div(sativaFollowerReward, rewardDivisor)
-
-		state.AddBalance(vetRewardAddress, sativaVetReward)
-		state.AddBalance(followerRewardAddress, sativaFollowerReward)
-		state.AddBalance(header.Coinbase, minerReward)
-			} else {
-
-    	for _, uncle := range uncles {
-	        r.Add(uncle.Number, big8)
-	        r.Sub(r, header.Number)
-	        r.Mul(r, reward)
-	        r.Div(r, big8)
-		
-	  	        // calcuting miner reward Post Indica Block
-	                minerReward.Mul(r, rewardDistMinerIndica)
-	                minerReward.Div(minerReward, rewardDivisor)
-	                // calculating cumulative rewards to be sent to Cannasseur Network contract Post Indica block
-	                cumulativeReward.Add(rewardDistFollower, rewardDistVet) 
-	                // Calculating contract reward Post Indica Block
-	                contractReward.Mul(r, cumulativeReward)
-	                contractReward.Div(contractReward, rewardDivisor)
-
-	        state.AddBalance(uncle.Coinbase, minerReward)
-	        contractRewardSplit.Div(contractReward, big.NewInt(2))
-	        state.AddBalance(vetRewardAddress, contractRewardSplit)
-	        state.AddBalance(followerRewardAddress, contractRewardSplit)
-	        r.Div(reward, big32)
-	        reward.Add(reward, r)
-	    }
-  		        // calcuting miner reward Post Indica Block
-	                minerReward.Mul(reward, rewardDistMinerIndica)
-	                minerReward.Div(minerReward, rewardDivisor)
-	                // calculating cumulative rewards to be sent to contract Post Indica block
-	                cumulativeReward.Add(rewardDistFollower, rewardDistVet) //per 100
-	                // Calculating contract reward Post Indica Block
-	                contractReward.Mul(reward, cumulativeReward)
-	                contractReward.Div(contractReward, rewardDivisor)
-
-                contractRewardSplit.Div(contractReward, big.NewInt(2))
-                state.AddBalance(vetRewardAddress, contractRewardSplit)
-                state.AddBalance(followerRewardAddress, contractRewardSplit)
-                if (header.Number.Cmp(indicaForkBlock) == 1) {
-         	state.AddBalance(header.Coinbase, minerReward)
-        }
-	    //fmt.Println(state.GetBalance(header.Coinbase), state.GetBalance(devRewardAddress), state.GetBalance(followerRewardAddress))
-	}} else {
-		for _, uncle := range uncles {
-	        r.Add(uncle.Number, big8)
-	        r.Sub(r, header.Number)
-	        r.Mul(r, reward)
-	        r.Div(r, big8)
-	  	// calcuting miner reward Pre Indica Block
-	        minerReward.Mul(r, rewardDistMinerRuderalis)
-	        minerReward.Div(minerReward, rewardDivisor)
-	        // Calculating reward for Veterans Fund contract Pre Indica Block
-	        contractReward.Mul(r, rewardDistVet)
-	        contractReward.Div(contractReward, rewardDivisor)
-
-	        state.AddBalance(uncle.Coinbase, minerReward)
-	        state.AddBalance(vetRewardAddress, contractReward)
-	        r.Div(reward, big32)
-	        reward.Add(reward, r)
-	    }
-		// calcuting miner reward Pre Indica Block
-	        minerReward.Mul(reward, rewardDistMinerRuderalis)
-	        minerReward.Div(minerReward, rewardDivisor)
-	        // Calculating Dev reward Pre Indica Block
-	        contractReward.Mul(reward, rewardDistVet)
-	        contractReward.Div(contractReward, rewardDivisor)
-
-	        state.AddBalance(vetRewardAddress, contractReward)
-	        state.AddBalance(header.Coinbase, minerReward)
-	        // fmt.Println(state.GetBalance(header.Coinbase), state.GetBalance(vetRewardAddress))
+	blockShares, uncleShares := BlockRewards(config, header.Number, uncles)
+	for i, uncle := range uncles {
+		payRewardShares(state, uncleShares[i], uncle.Coinbase, vetRewardAddress, followerRewardAddress)
 	}
+	payRewardShares(state, blockShares, header.Coinbase, vetRewardAddress, followerRewardAddress)
 }
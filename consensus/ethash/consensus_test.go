@@ -139,6 +139,47 @@ func TestDifficultyCalculators(t *testing.T) {
 	}
 }
 
+// Tests that the uint256 difficulty calculators apply this network's minimum
+// difficulty and Frontier duration limit, comparing them against the big.Int
+// calculators around both boundaries.
+func TestDifficultyCalculatorsParams(t *testing.T) {
+	limit := params.DurationLimit.Uint64()
+	for _, diff := range []*big.Int{
+		params.MinimumDifficulty,
+		new(big.Int).Add(params.MinimumDifficulty, big.NewInt(1)),
+		new(big.Int).Add(params.MinimumDifficulty, big.NewInt(64)),
+		big.NewInt(131072),
+		big.NewInt(131200),
+	} {
+		for _, delta := range []uint64{1, limit - 1, limit, limit + 1, 12, 13, 14, 100} {
+			for _, uncles := range []common.Hash{types.EmptyUncleHash, {0x01}} {
+				header := &types.Header{
+					Difficulty: diff,
+					Number:     big.NewInt(1000),
+					Time:       1000000,
+					UncleHash:  uncles,
+				}
+				for i, pair := range []struct {
+					bigFn  func(time uint64, parent *types.Header) *big.Int
+					u256Fn func(time uint64, parent *types.Header) *big.Int
+				}{
+					{FrontierDifficultyCalulator, CalcDifficultyFrontierU256},
+					{HomesteadDifficultyCalulator, CalcDifficultyHomesteadU256},
+					{DynamicDifficultyCalculator(big.NewInt(9000000)), MakeDifficultyCalculatorU256(big.NewInt(9000000))},
+				} {
+					want := pair.bigFn(header.Time+delta, header)
+					if have := pair.u256Fn(header.Time+delta, header); want.Cmp(have) != 0 {
+						t.Errorf("pair %d, diff %v, delta %d: have %v, want %v", i, diff, delta, have, want)
+					}
+					if want.Cmp(params.MinimumDifficulty) < 0 {
+						t.Errorf("pair %d, diff %v, delta %d: difficulty %v below minimum", i, diff, delta, want)
+					}
+				}
+			}
+		}
+	}
+}
+
 func BenchmarkDifficultyCalculator(b *testing.B) {
 	x1 := makeDifficultyCalculator(big.NewInt(1000000))
 	x2 := MakeDifficultyCalculatorU256(big.NewInt(1000000))
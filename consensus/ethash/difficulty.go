@@ -20,16 +20,20 @@ import (
 	"math/big"
 
 	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/params"
 	"github.com/holiman/uint256"
 )
 
-const (
+var (
 	// frontierDurationLimit is for Frontier:
 	// The decision boundary on the blocktime duration used to determine
 	// whether difficulty should go up or down.
-	frontierDurationLimit = 13
+	frontierDurationLimit = params.DurationLimit.Uint64()
 	// minimumDifficulty The minimum that the difficulty may ever be.
-	minimumDifficulty = 131072
+	minimumDifficulty = params.MinimumDifficulty.Uint64()
+)
+
+const (
 	// expDiffPeriod is the exponential difficulty period
 	expDiffPeriodUint = 100000
 	// difficultyBoundDivisorBitShift is the bound divisor of the difficulty (2048),
@@ -43,7 +47,7 @@ const (
 func CalcDifficultyFrontierU256(time uint64, parent *types.Header) *big.Int {
 	/*
 		Algorithm
-		block_diff = pdiff + pdiff / 2048 * (1 if time - ptime < 13 else -1) + int(2^((num // 100000) - 2))
+		block_diff = pdiff + pdiff / 2048 * (1 if time - ptime < 8 else -1) + int(2^((num // 100000) - 2))
 		Where:
 		- pdiff  = parent.difficulty
 		- ptime = parent.time
@@ -65,7 +69,7 @@ func CalcDifficultyFrontierU256(time uint64, parent *types.Header) *big.Int {
 		pDiff.SetUint64(minimumDifficulty)
 	}
 	// 'pdiff' now contains:
-	// pdiff + pdiff / 2048 * (1 if time - ptime < 13 else -1)
+	// pdiff + pdiff / 2048 * (1 if time - ptime < 8 else -1)
 
 	if periodCount := (parent.Number.Uint64() + 1) / expDiffPeriodUint; periodCount > 1 {
 		// diff = diff + 2^(periodCount - 2)
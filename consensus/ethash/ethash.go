@@ -20,6 +20,7 @@ package ethash
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/big"
 	"math/rand"
@@ -34,11 +35,11 @@ import (
 	"unsafe"
 
 	"github.com/edsrzf/mmap-go"
+	lrucache "github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/consensus"
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/metrics"
 	"github.com/420integrated/go-420coin/rpc"
-	"github.com/hashicorp/golang-lru/simplelru"
 )
 
 var ErrInvalidDumpMagic = errors.New("invalid dump magic")
@@ -48,7 +49,7 @@ var (
 	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
 
 	// sharedEthash is a full instance that can be shared between multiple users.
-	sharedEthash = New(Config{"", 3, 0, false, "", 1, 0, false, ModeNormal, nil}, nil, false)
+	sharedEthash = New(Config{"", 3, 0, false, "", false, "", 1, 0, false, ModeNormal, nil}, nil, false)
 
 	// algorithmRevision is the data structure version used for file naming.
 	algorithmRevision = 23
@@ -159,7 +160,7 @@ type lru struct {
 	mu   sync.Mutex
 	// Items are kept in a LRU cache, but there is a special case:
 	// We always keep an item for (highest seen epoch) + 1 as the 'future item'.
-	cache      *simplelru.LRU
+	cache      *lrucache.Cache
 	future     uint64
 	futureItem interface{}
 }
@@ -170,7 +171,7 @@ func newlru(what string, maxItems int, new func(epoch uint64) interface{}) *lru
 	if maxItems <= 0 {
 		maxItems = 1
 	}
-	cache, _ := simplelru.NewLRU(maxItems, func(key, value interface{}) {
+	cache := lrucache.NewWithEvict("", maxItems, 0, func(key, value interface{}) {
 		log.Trace("Evicted ethash "+what, "epoch", key)
 	})
 	return &lru{what: what, new: new, cache: cache}
@@ -204,6 +205,22 @@ func (lru *lru) get(epoch uint64) (item, future interface{}) {
 	return item, future
 }
 
+// replace swaps the item of the given epoch for a new one, unless it was already
+// replaced by someone else. The item now associated with the epoch is returned.
+func (lru *lru) replace(epoch uint64, old, new interface{}) interface{} {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if item, ok := lru.cache.Get(epoch); ok && item != old {
+		return item
+	}
+	lru.cache.Add(epoch, new)
+	if lru.future == epoch {
+		lru.futureItem = new
+	}
+	return new
+}
+
 // cache wraps an ethash cache with some metadata to allow easier concurrent use.
 type cache struct {
 	epoch uint64    // Epoch for which this cache is relevant
@@ -211,16 +228,25 @@ type cache struct {
 	mmap  mmap.MMap // Memory map itself to unmap before releasing
 	cache []uint32  // The actual cache data content (may be memory mapped)
 	once  sync.Once // Ensures the cache is generated only once
+
+	fetched    bool // Whether the content was fetched from a remote node and may be bogus
+	regenerate bool // Whether to regenerate the content locally, ignoring the one on disk
 }
 
+// fetchedSuffix is appended to the path of a verification cache to mark it as
+// fetched from a remote node, so it stays untrusted across restarts.
+const fetchedSuffix = ".fetched"
+
 // newCache creates a new ethash verification cache and returns it as a plain Go
 // interface to be usable in an LRU cache.
 func newCache(epoch uint64) interface{} {
 	return &cache{epoch: epoch}
 }
 
-// generate ensures that the cache content is generated before use.
-func (c *cache) generate(dir string, limit int, lock bool, test bool) {
+// generate ensures that the cache content is generated before use. If a remote
+// url is given, missing caches are fetched from it before falling back to local
+// generation.
+func (c *cache) generate(dir string, url string, limit int, lock bool, test bool) {
 	c.once.Do(func() {
 		size := cacheSize(c.epoch*epochLength + 1)
 		seed := seedHash(c.epoch*epochLength + 1)
@@ -245,17 +271,39 @@ func (c *cache) generate(dir string, limit int, lock bool, test bool) {
 		// cache becomes unused.
 		runtime.SetFinalizer(c, (*cache).finalizer)
 
-		// Try to load the file from disk and memory map it
+		// Try to load the file from disk and memory map it, unless it's the
+		// fetched one being replaced
 		var err error
-		c.dump, c.mmap, c.cache, err = memoryMap(path, lock)
-		if err == nil {
-			logger.Debug("Loaded old ethash cache from disk")
-			return
+		if c.regenerate {
+			url = ""
+		} else {
+			c.dump, c.mmap, c.cache, err = memoryMap(path, lock)
+			if err == nil {
+				_, err := os.Stat(path + fetchedSuffix)
+				c.fetched = err == nil
+				logger.Debug("Loaded old ethash cache from disk", "fetched", c.fetched)
+				return
+			}
+			logger.Debug("Failed to load old ethash cache", "err", err)
+		}
+		// No previous cache available, try fetching it from a remote node
+		if url != "" {
+			if err = fetchCache(url, path, size, logger); err == nil {
+				if err = ioutil.WriteFile(path+fetchedSuffix, nil, 0644); err == nil {
+					c.dump, c.mmap, c.cache, err = memoryMap(path, lock)
+				}
+			}
+			if err != nil {
+				logger.Warn("Failed to fetch remote ethash cache", "url", url, "err", err)
+				os.Remove(path)
+			}
+			c.fetched = err == nil
+		}
+		// No remote cache available either, create a new cache file to fill
+		if url == "" || err != nil {
+			os.Remove(path + fetchedSuffix)
+			c.dump, c.mmap, c.cache, err = memoryMapAndGenerate(path, size, lock, func(buffer []uint32) { generateCache(buffer, c.epoch, seed) })
 		}
-		logger.Debug("Failed to load old ethash cache", "err", err)
-
-		// No previous cache available, create a new cache file to fill
-		c.dump, c.mmap, c.cache, err = memoryMapAndGenerate(path, size, lock, func(buffer []uint32) { generateCache(buffer, c.epoch, seed) })
 		if err != nil {
 			logger.Error("Failed to generate mapped ethash cache", "err", err)
 
@@ -267,6 +315,7 @@ func (c *cache) generate(dir string, limit int, lock bool, test bool) {
 			seed := seedHash(uint64(ep)*epochLength + 1)
 			path := filepath.Join(dir, fmt.Sprintf("cache-R%d-%x%s", algorithmRevision, seed[:8], endian))
 			os.Remove(path)
+			os.Remove(path + fetchedSuffix)
 		}
 	})
 }
@@ -379,7 +428,7 @@ func (d *dataset) finalizer() {
 // MakeCache generates a new ethash cache and optionally stores it to disk.
 func MakeCache(block uint64, dir string) {
 	c := cache{epoch: block / epochLength}
-	c.generate(dir, math.MaxInt32, false, false)
+	c.generate(dir, "", math.MaxInt32, false, false)
 }
 
 // MakeDataset generates a new ethash dataset and optionally stores it to disk.
@@ -399,12 +448,52 @@ const (
 	ModeFullFake
 )
 
+// modeNames are the textual representations of the PoW modes, as accepted in
+// the TOML config and on the command line.
+var modeNames = map[Mode]string{
+	ModeNormal:   "normal",
+	ModeShared:   "shared",
+	ModeTest:     "test",
+	ModeFake:     "fake",
+	ModeFullFake: "fullfake",
+}
+
+// String implements fmt.Stringer.
+func (mode Mode) String() string {
+	if name, ok := modeNames[mode]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint(mode))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (mode Mode) MarshalText() ([]byte, error) {
+	name, ok := modeNames[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown ethash mode %d", uint(mode))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (mode *Mode) UnmarshalText(text []byte) error {
+	for m, name := range modeNames {
+		if name == string(text) {
+			*mode = m
+			return nil
+		}
+	}
+	return fmt.Errorf(`unknown ethash mode %q, want "normal", "shared", "test", "fake" or "fullfake"`, text)
+}
+
 // Config are the configuration parameters of the ethash.
 type Config struct {
 	CacheDir         string
 	CachesInMem      int
 	CachesOnDisk     int
 	CachesLockMmap   bool
+	CacheURL         string // Base URL of a node serving verification caches to fetch instead of generating
+	CacheServe       bool   // Whether to serve the verification caches on disk to other nodes over HTTP
 	DatasetDir       string
 	DatasetsInMem    int
 	DatasetsOnDisk   int
@@ -429,6 +518,8 @@ type Ethash struct {
 	hashrate metrics.Meter // Meter tracking the average hashrate
 	remote   *remoteSealer
 
+	itemSource PowItemSource // Remote dataset item source replacing the verification caches
+
 	// The fields below are hooks for testing
 	shared    *Ethash       // Shared PoW verifier to avoid cache regeneration
 	fakeFail  uint64        // Block number which fails PoW check even in fake mode
@@ -558,12 +649,30 @@ func (ethash *Ethash) cache(block uint64) *cache {
 	current := currentI.(*cache)
 
 	// Wait for generation finish.
-	current.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+	current.generate(ethash.config.CacheDir, ethash.config.CacheURL, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
 
 	// If we need a new future cache, now's a good time to regenerate it.
 	if futureI != nil {
 		future := futureI.(*cache)
-		go future.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+		go future.generate(ethash.config.CacheDir, ethash.config.CacheURL, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+	}
+	return current
+}
+
+// regenerateCache replaces a verification cache fetched from a remote node with
+// a locally generated one, after the fetched content failed to verify a seal.
+func (ethash *Ethash) regenerateCache(fetched *cache) *cache {
+	current := ethash.caches.replace(fetched.epoch, fetched, &cache{epoch: fetched.epoch, regenerate: true}).(*cache)
+	current.generate(ethash.config.CacheDir, "", ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+	return current
+}
+
+// localCache is like cache, but regenerates caches fetched from remote nodes
+// locally. It's used where the results can't be checked against a seal.
+func (ethash *Ethash) localCache(block uint64) *cache {
+	current := ethash.cache(block)
+	if current.fetched {
+		current = ethash.regenerateCache(current)
 	}
 	return current
 }
@@ -658,7 +767,8 @@ func (ethash *Ethash) Hashrate() float64 {
 // APIs implements consensus.Engine, returning the user facing RPC APIs.
 func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	// In order to ensure backward compatibility, we exposes ethash RPC APIs
-	// to both eth and ethash namespaces.
+	// to the eth, fourtwenty and ethash namespaces, the first two serving the
+	// remote miners and pools written for them.
 	return []rpc.API{
 		{
 			Namespace: "eth",
@@ -666,6 +776,12 @@ func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 			Service:   &API{ethash},
 			Public:    true,
 		},
+		{
+			Namespace: "fourtwenty",
+			Version:   "1.0",
+			Service:   &API{ethash},
+			Public:    true,
+		},
 		{
 			Namespace: "ethash",
 			Version:   "1.0",
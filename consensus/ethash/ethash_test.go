@@ -54,6 +54,31 @@ func TestTestMode(t *testing.T) {
 	}
 }
 
+// Tests that the PoW modes round trip through their textual representation, as
+// used by the TOML config and the command line.
+func TestModeText(t *testing.T) {
+	for _, mode := range []Mode{ModeNormal, ModeShared, ModeTest, ModeFake, ModeFullFake} {
+		text, err := mode.MarshalText()
+		if err != nil {
+			t.Fatalf("mode %d: failed to marshal: %v", mode, err)
+		}
+		var have Mode
+		if err := have.UnmarshalText(text); err != nil {
+			t.Fatalf("mode %d: failed to unmarshal %q: %v", mode, text, err)
+		}
+		if have != mode {
+			t.Errorf("mode %d: round trip mismatch: have %d, want %d", mode, have, mode)
+		}
+	}
+	var mode Mode
+	if err := mode.UnmarshalText([]byte("turbo")); err == nil {
+		t.Error("unknown mode accepted")
+	}
+	if _, err := Mode(42).MarshalText(); err == nil {
+		t.Error("unknown mode marshalled")
+	}
+}
+
 // This test checks that cache lru logic doesn't crash under load.
 // It reproduces https://github.com/420integrated/go-420coin/issues/14943
 func TestCacheFileEvict(t *testing.T) {
@@ -0,0 +1,118 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+
+	"github.com/420integrated/go-420coin/core/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// PowItemCount is the number of dataset items hashimoto accesses when verifying
+// the seal of a single header.
+const PowItemCount = loopAccesses * mixBytes / hashBytes
+
+// errInvalidPowItems is returned if the dataset items supplied for verifying a
+// seal are malformed.
+var errInvalidPowItems = errors.New("invalid proof-of-work dataset items")
+
+// PowItemSource retrieves the dataset items needed to verify the seal of a header
+// from a remote node, in the order hashimoto accesses them.
+type PowItemSource func(header *types.Header) ([][]byte, error)
+
+// SetPowItemSource configures the engine to verify seals with the dataset items
+// retrieved from the given source instead of generating verification caches.
+// This is meant for light clients which can't afford the caches.
+//
+// The items can't be authenticated without the cache, so a dishonest source may
+// forge them. Doing so still requires finding a mix which meets the difficulty
+// of the header, the cost of which is proportional to the difficulty, but lacks
+// the memory hardness of ethash.
+func (ethash *Ethash) SetPowItemSource(source PowItemSource) {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	ethash.itemSource = source
+}
+
+// powItemSource returns the configured dataset item source, if any.
+func (ethash *Ethash) powItemSource() PowItemSource {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	return ethash.itemSource
+}
+
+// PowItems returns the dataset items accessed when verifying the seal of the
+// given header, generated from the local verification cache, to serve them to
+// light clients.
+func (ethash *Ethash) PowItems(header *types.Header) [][]byte {
+	number := header.Number.Uint64()
+	cache := ethash.localCache(number)
+
+	size := datasetSize(number)
+	if ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	var (
+		keccak512 = makeHasher(sha3.NewLegacyKeccak512())
+		items     = make([][]byte, 0, PowItemCount)
+	)
+	lookup := func(index uint32) []uint32 {
+		rawData := generateDatasetItem(cache.cache, index, keccak512)
+		items = append(items, rawData)
+
+		data := make([]uint32, len(rawData)/4)
+		for i := 0; i < len(data); i++ {
+			data[i] = binary.LittleEndian.Uint32(rawData[i*4:])
+		}
+		return data
+	}
+	hashimoto(ethash.SealHash(header).Bytes(), header.Nonce.Uint64(), size, lookup)
+
+	// Caches are unmapped in a finalizer. Ensure that the cache stays alive
+	// until after the call to hashimoto so it's not unmapped while being used.
+	runtime.KeepAlive(cache)
+	return items
+}
+
+// hashimotoItems aggregates data from the supplied dataset items, in the order
+// hashimoto accesses them, to compute the digest and result of a seal.
+func hashimotoItems(size uint64, items [][]byte, hash []byte, nonce uint64) ([]byte, []byte, error) {
+	if len(items) != PowItemCount {
+		return nil, nil, errInvalidPowItems
+	}
+	for _, item := range items {
+		if len(item) != hashBytes {
+			return nil, nil, errInvalidPowItems
+		}
+	}
+	next := 0
+	lookup := func(index uint32) []uint32 {
+		data := make([]uint32, hashWords)
+		for i := 0; i < len(data); i++ {
+			data[i] = binary.LittleEndian.Uint32(items[next][i*4:])
+		}
+		next++
+		return data
+	}
+	digest, result := hashimoto(hash, nonce, size, lookup)
+	return digest, result, nil
+}
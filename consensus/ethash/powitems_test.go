@@ -0,0 +1,57 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/core/types"
+)
+
+// Tests that the dataset items served for a header reproduce the digest and
+// result of the light verification, and that malformed items are rejected.
+func TestPowItems(t *testing.T) {
+	ethash := NewTester(nil, false)
+	defer ethash.Close()
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	header.Nonce = types.EncodeNonce(0x1234)
+
+	items := ethash.PowItems(header)
+	if len(items) != PowItemCount {
+		t.Fatalf("item count mismatch: have %d, want %d", len(items), PowItemCount)
+	}
+	hash := ethash.SealHash(header).Bytes()
+	wantDigest, wantResult := hashimotoLight(32*1024, ethash.cache(1).cache, hash, header.Nonce.Uint64())
+
+	digest, result, err := hashimotoItems(32*1024, items, hash, header.Nonce.Uint64())
+	if err != nil {
+		t.Fatalf("failed to verify items: %v", err)
+	}
+	if !bytes.Equal(digest, wantDigest) || !bytes.Equal(result, wantResult) {
+		t.Fatalf("item verification mismatch: have %x/%x, want %x/%x", digest, result, wantDigest, wantResult)
+	}
+	if _, _, err := hashimotoItems(32*1024, items[1:], hash, header.Nonce.Uint64()); err != errInvalidPowItems {
+		t.Errorf("short item list error mismatch: have %v, want %v", err, errInvalidPowItems)
+	}
+	short := append([][]byte{items[0][1:]}, items[1:]...)
+	if _, _, err := hashimotoItems(32*1024, short, hash, header.Nonce.Uint64()); err != errInvalidPowItems {
+		t.Errorf("short item error mismatch: have %v, want %v", err, errInvalidPowItems)
+	}
+}
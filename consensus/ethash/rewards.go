@@ -0,0 +1,399 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/420integrated/go-420coin/common"
+	lrucache "github.com/420integrated/go-420coin/common/cache"
+	"github.com/420integrated/go-420coin/consensus"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/holiman/uint256"
+)
+
+const (
+	rewardCacheLimit    = 4096 // Number of base block rewards to keep in memory
+	contractCacheLimit  = 16   // Number of derived reward contract addresses to keep in memory
+	recipientCacheLimit = 16   // Number of reward contract storage versions to keep in memory
+)
+
+var (
+	u256_8   = new(uint256.Int).SetUint64(8)
+	u256_32  = new(uint256.Int).SetUint64(32)
+	u256_100 = new(uint256.Int).SetUint64(100) // Divisor of the reward split percentages
+
+	// Constants of the big integer reward calculation verifying the payouts
+	big8   = big.NewInt(8)
+	big32  = big.NewInt(32)
+	big100 = big.NewInt(100)
+
+	// slowBlockRewardU256 and sativaBlockRewardU256 are the uint256 forms of the
+	// slow-start and generalized block rewards.
+	slowBlockRewardU256, _   = uint256.FromBig(slowBlockReward)
+	sativaBlockRewardU256, _ = uint256.FromBig(SativaBlockReward)
+
+	rewardCache    = lrucache.New("", rewardCacheLimit, 0)    // Base block rewards by block number
+	contractCache  = lrucache.New("", contractCacheLimit, 0)  // Reward contracts by genesis extra-data
+	recipientCache = lrucache.New("", recipientCacheLimit, 0) // Reward recipients by contract storage version
+
+	errZeroVeterans      = errors.New("veterans fund reward address is zero")
+	errZeroFollowers     = errors.New("followers reward address is zero")
+	errRewardOffSchedule = errors.New("block reward deviates from the emission schedule")
+)
+
+// RewardEra is a range of blocks sharing the same base block reward and the same
+// split of it between the miner, the Veterans Fund and the Cannasseur Network
+// followers. Uncle inclusion rewards are split the same way.
+type RewardEra struct {
+	Name      string   // Name of the emission era
+	FromBlock uint64   // First block of the range
+	ToBlock   *uint64  // Last block of the range, nil if open ended
+	Reward    *big.Int // Base block reward in marleys, excluding uncle inclusion
+	Miner     uint64   // Percentage of the reward paid to the miner
+	Veterans  uint64   // Percentage of the reward paid to the Veterans Fund
+	Followers uint64   // Percentage of the reward paid to the followers
+}
+
+// RewardSchedule returns the emission schedule applied by AccumulateNewRewards on
+// the chain with the given config, split into ranges at every block where the
+// base reward or its split changes.
+func RewardSchedule(config *params.ChainConfig) []*RewardEra {
+	// Collect all the blocks at which the reward or split changes
+	starts := []uint64{1, SlowStart.Uint64() + 1, rewardBlockFlat.Uint64() + 1}
+	for number := rewardBlockDivisor.Uint64(); number <= rewardBlockFlat.Uint64(); number += rewardBlockDivisor.Uint64() {
+		starts = append(starts, number)
+	}
+	for _, era := range config.RewardEras() {
+		if era.Block.Sign() > 0 && era.Block.IsUint64() {
+			starts = append(starts, era.Block.Uint64())
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	eras := make([]*RewardEra, 0, len(starts))
+	for i, start := range starts {
+		if i > 0 && start == starts[i-1] {
+			continue
+		}
+		number := new(big.Int).SetUint64(start)
+		split := config.RewardEraAt(number)
+
+		eras = append(eras, &RewardEra{
+			Name:      split.Name,
+			FromBlock: start,
+			Reward:    blockReward(number).ToBig(),
+			Miner:     split.Miner,
+			Veterans:  split.Veterans,
+			Followers: split.Followers,
+		})
+		if len(eras) > 1 {
+			end := start - 1
+			eras[len(eras)-2].ToBlock = &end
+		}
+	}
+	return eras
+}
+
+// blockReward returns the base reward of the block with the given number, before
+// adding any uncle inclusion rewards.
+func blockReward(number *big.Int) *uint256.Int {
+	if number.IsUint64() {
+		if cached, ok := rewardCache.Get(number.Uint64()); ok {
+			return cached.(*uint256.Int).Clone()
+		}
+	}
+	reward := new(uint256.Int)
+	switch {
+	case number.Cmp(SlowStart) <= 0:
+		reward.Set(slowBlockRewardU256)
+	case number.Cmp(rewardBlockFlat) > 0:
+		reward.Set(sativaBlockRewardU256)
+	default:
+		// The number is within the flat reward block here, so it fits 64 bits
+		reward.SetUint64(number.Uint64() / rewardBlockDivisor.Uint64())
+		reward.Mul(reward, slowBlockRewardU256)
+		reward.Sub(sativaBlockRewardU256, reward)
+	}
+	if number.IsUint64() {
+		rewardCache.Add(number.Uint64(), reward.Clone())
+	}
+	return reward
+}
+
+// RewardShares is a reward split between its recipients. Followers is nil during
+// eras when the followers don't receive anything, such as the Ruderalis era. The
+// shares are computed with uint256 and converted when split, as the state takes
+// balance changes as big integers.
+type RewardShares struct {
+	Miner     *big.Int // Share of the block or uncle miner
+	Veterans  *big.Int // Share of the Veterans Fund
+	Followers *big.Int // Share of the Cannasseur Network followers
+}
+
+// BlockRewards returns the split rewards of the block with the given number and
+// of each of its uncles on the chain with the given config. Every included uncle
+// is rewarded in proportion to its distance from the block and raises the block
+// reward by 1/32 of the running total.
+func BlockRewards(config *params.ChainConfig, number *big.Int, uncles []*types.Header) (*RewardShares, []*RewardShares) {
+	var (
+		era    = config.RewardEraAt(number)
+		reward = blockReward(number)
+		shares = make([]*RewardShares, len(uncles))
+		r      = new(uint256.Int)
+		n      = new(uint256.Int)
+	)
+	n.SetFromBig(number)
+	for i, uncle := range uncles {
+		r.SetFromBig(uncle.Number)
+		r.Add(r, u256_8)
+		r.Sub(r, n)
+		r.Mul(r, reward)
+		r.Div(r, u256_8)
+		shares[i] = splitReward(era, r)
+
+		r.Div(reward, u256_32)
+		reward.Add(reward, r)
+	}
+	return splitReward(era, reward), shares
+}
+
+// splitReward splits a reward between its recipients according to the given era,
+// rounding each share down.
+func splitReward(era *params.RewardEra, reward *uint256.Int) *RewardShares {
+	share := new(uint256.Int)
+	percent := func(pct uint64) *big.Int {
+		share.SetUint64(pct)
+		share.Mul(share, reward)
+		return share.Div(share, u256_100).ToBig()
+	}
+	shares := &RewardShares{
+		Miner:    percent(era.Miner),
+		Veterans: percent(era.Veterans),
+	}
+	if era.Followers > 0 {
+		shares.Followers = percent(era.Followers)
+	}
+	return shares
+}
+
+// payRewardShares credits the split reward to the miner and the reward contract
+// recipients.
+func payRewardShares(state *state.StateDB, shares *RewardShares, miner, veterans, followers common.Address) {
+	state.AddBalance(miner, shares.Miner)
+	state.AddBalance(veterans, shares.Veterans)
+	if shares.Followers != nil {
+		state.AddBalance(followers, shares.Followers)
+	}
+}
+
+// RewardContract returns the address of the contract holding the reward
+// recipient addresses of the block with the given number. Unless the chain
+// config migrated it to a new registry, it's the contract deployed by the
+// account named in the genesis extra-data. The genesis may be nil once the
+// registry was migrated. Chains missing it, such as the ones of stateless
+// witnesses, get the zero address and need to fail the block themselves.
+func RewardContract(config *params.ChainConfig, number *big.Int, genesis *types.Header) common.Address {
+	if config.IsRewardRegistryMigrated(number) {
+		return config.RewardRegistry
+	}
+	if genesis == nil {
+		return common.Address{}
+	}
+	if contract, ok := contractCache.Get(string(genesis.Extra)); ok {
+		return contract.(common.Address)
+	}
+	contract := crypto.CreateAddress(common.BytesToAddress(genesis.Extra), 0)
+	contractCache.Add(string(genesis.Extra), contract)
+	return contract
+}
+
+// rewardGenesis returns the genesis header the reward contract of the block with
+// the given number is derived from, or nil if the registry was migrated and the
+// genesis doesn't need to be looked up.
+func rewardGenesis(chain consensus.ChainHeaderReader, number *big.Int) *types.Header {
+	if chain.Config().IsRewardRegistryMigrated(number) {
+		return nil
+	}
+	return chain.GetHeaderByNumber(0)
+}
+
+// storageRooter is implemented by states which can tell the storage root of an
+// account, allowing values derived from its storage to be cached.
+type storageRooter interface {
+	StorageRoot(addr common.Address) (common.Hash, bool)
+}
+
+// recipientKey identifies a version of the reward contract storage.
+type recipientKey struct {
+	contract common.Address
+	root     common.Hash
+}
+
+// rewardRecipients are the recipient addresses stored in the reward contract.
+// The recipients before the change block are kept in the previous slots.
+type rewardRecipients struct {
+	change   *big.Int          // Block after which the current recipients apply
+	current  [2]common.Address // Veterans Fund and followers after the change block
+	previous [2]common.Address // Veterans Fund and followers up to the change block included
+}
+
+// readRewardRecipients reads the recipient addresses from the reward contract.
+func readRewardRecipients(state vm.StateDB, contract common.Address) *rewardRecipients {
+	address := func(slot byte) common.Address {
+		value := state.GetState(contract, common.BytesToHash([]byte{slot}))
+		return common.BytesToAddress(value[common.HashLength-common.AddressLength:])
+	}
+	return &rewardRecipients{
+		change:   state.GetState(contract, common.BytesToHash([]byte{0})).Big(),
+		current:  [2]common.Address{address(1), address(2)},
+		previous: [2]common.Address{address(3), address(4)},
+	}
+}
+
+// RewardAddresses returns the Veterans Fund and followers addresses the rewards
+// of the block with the given number are paid to, as read from the reward
+// contract in the given state. The addresses are cached for as long as the
+// storage root of the contract doesn't change, if the state can tell it.
+func RewardAddresses(config *params.ChainConfig, state vm.StateDB, number *big.Int, genesis *types.Header) (common.Address, common.Address) {
+	var (
+		contract   = RewardContract(config, number, genesis)
+		recipients *rewardRecipients
+	)
+	if rooter, ok := state.(storageRooter); ok {
+		if root, valid := rooter.StorageRoot(contract); valid {
+			key := recipientKey{contract: contract, root: root}
+			if cached, ok := recipientCache.Get(key); ok {
+				recipients = cached.(*rewardRecipients)
+			} else {
+				recipients = readRewardRecipients(state, contract)
+				recipientCache.Add(key, recipients)
+			}
+		}
+	}
+	if recipients == nil {
+		recipients = readRewardRecipients(state, contract)
+	}
+	if number.Cmp(recipients.change) > 0 {
+		return recipients.current[0], recipients.current[1]
+	}
+	return recipients.previous[0], recipients.previous[1]
+}
+
+// VerifyRewards implements consensus.RewardVerifier, checking the balance changes
+// made by the payout to the recipients of the block and uncle rewards against the
+// emission schedule. Once the chain config enables the reward check, blocks paying
+// rewards to a zero address are also rejected.
+func (ethash *Ethash) VerifyRewards(chain consensus.ChainHeaderReader, header *types.Header, uncles []*types.Header, state vm.StateDB, payout func()) error {
+	return verifyRewards(RewardSchedule(chain.Config()), chain, header, uncles, state, payout)
+}
+
+// verifyRewards checks the rewards credited by the payout against the given
+// emission schedule.
+func verifyRewards(schedule []*RewardEra, chain consensus.ChainHeaderReader, header *types.Header, uncles []*types.Header, state vm.StateDB, payout func()) error {
+	config := chain.Config()
+	veterans, followers := RewardAddresses(config, state, header.Number, rewardGenesis(chain, header.Number))
+	if config.IsRewardCheck(header.Number) {
+		if veterans == (common.Address{}) {
+			return errZeroVeterans
+		}
+		if followers == (common.Address{}) && config.RewardEraAt(header.Number).Followers > 0 {
+			return errZeroFollowers
+		}
+	}
+	expected, err := scheduledRewards(schedule, header, uncles, veterans, followers)
+	if err != nil {
+		return err
+	}
+	// Pay out the rewards and check what every recipient was actually credited
+	before := make(map[common.Address]*big.Int, len(expected))
+	for addr := range expected {
+		before[addr] = new(big.Int).Set(state.GetBalance(addr))
+	}
+	payout()
+
+	for addr, want := range expected {
+		if have := new(big.Int).Sub(state.GetBalance(addr), before[addr]); have.Cmp(want) != 0 {
+			return fmt.Errorf("%w: %x credited %v, want %v", errRewardOffSchedule, addr, have, want)
+		}
+	}
+	return nil
+}
+
+// scheduledRewards computes the credits every recipient of the rewards of a block
+// is owed according to the emission schedule, independently of the calculation
+// paying them out. Every uncle is rewarded (8 - depth) / 8 of the block reward,
+// and raises the block reward by 1/32 for the uncles after it and the miner. Each
+// reward is split by the percentages of the era.
+func scheduledRewards(schedule []*RewardEra, header *types.Header, uncles []*types.Header, veterans, followers common.Address) (map[common.Address]*big.Int, error) {
+	var era *RewardEra
+	if header.Number.IsUint64() {
+		number := header.Number.Uint64()
+		for _, candidate := range schedule {
+			if candidate.FromBlock <= number && (candidate.ToBlock == nil || number <= *candidate.ToBlock) {
+				era = candidate
+				break
+			}
+		}
+	}
+	if era == nil {
+		return nil, fmt.Errorf("%w: block %v outside of the schedule", errRewardOffSchedule, header.Number)
+	}
+	// Sum up the shares every recipient is owed, they may coincide
+	expected := make(map[common.Address]*big.Int)
+	credit := func(addr common.Address, reward *big.Int, percent uint64) {
+		share := new(big.Int).Mul(reward, new(big.Int).SetUint64(percent))
+		share.Div(share, big100)
+
+		if expected[addr] == nil {
+			expected[addr] = new(big.Int)
+		}
+		expected[addr].Add(expected[addr], share)
+	}
+	owe := func(reward *big.Int, miner common.Address) {
+		credit(miner, reward, era.Miner)
+		credit(veterans, reward, era.Veterans)
+		if era.Followers > 0 {
+			credit(followers, reward, era.Followers)
+		}
+	}
+	reward := new(big.Int).Set(era.Reward)
+	for _, uncle := range uncles {
+		depth := new(big.Int).Sub(header.Number, uncle.Number)
+		uncleReward := new(big.Int).Sub(big8, depth)
+		uncleReward.Mul(uncleReward, reward)
+		owe(uncleReward.Div(uncleReward, big8), uncle.Coinbase)
+
+		reward.Add(reward, new(big.Int).Div(reward, big32))
+	}
+	owe(reward, header.Coinbase)
+	return expected, nil
+}
+
+// FeeFund implements consensus.FeeRouter, paying the share of the transaction
+// fees routed away from the coinbase to the Veterans Fund.
+func (ethash *Ethash) FeeFund(chain consensus.ChainHeaderReader, header *types.Header, state vm.StateDB) common.Address {
+	veterans, _ := RewardAddresses(chain.Config(), state, header.Number, rewardGenesis(chain, header.Number))
+	return veterans
+}
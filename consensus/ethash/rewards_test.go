@@ -0,0 +1,372 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that the reward schedule is contiguous and agrees with the rewards and
+// splits applied to the first and last block of every range.
+func TestRewardSchedule(t *testing.T) {
+	eras := RewardSchedule(params.TestChainConfig)
+	if len(eras) == 0 || eras[0].FromBlock != 1 {
+		t.Fatalf("schedule doesn't start at block 1")
+	}
+	if last := eras[len(eras)-1]; last.ToBlock != nil || last.Name != "sativa" {
+		t.Fatalf("last era mismatch: have %+v", last)
+	}
+	for i, era := range eras {
+		numbers := []uint64{era.FromBlock}
+		if era.ToBlock != nil {
+			if i+1 < len(eras) && *era.ToBlock+1 != eras[i+1].FromBlock {
+				t.Errorf("era %d: gap before next era: ends at %d, next starts at %d", i, *era.ToBlock, eras[i+1].FromBlock)
+			}
+			numbers = append(numbers, *era.ToBlock)
+		}
+		for _, number := range numbers {
+			n := new(big.Int).SetUint64(number)
+			if reward := blockReward(n).ToBig(); reward.Cmp(era.Reward) != 0 {
+				t.Errorf("era %d, block %d: reward mismatch: have %v, want %v", i, number, era.Reward, reward)
+			}
+			split := params.TestChainConfig.RewardEraAt(n)
+			if split.Name != era.Name || split.Miner != era.Miner || split.Veterans != era.Veterans || split.Followers != era.Followers {
+				t.Errorf("era %d, block %d: split mismatch", i, number)
+			}
+		}
+		if era.Miner+era.Veterans+era.Followers != 100 {
+			t.Errorf("era %d: split doesn't add up to 100%%", i)
+		}
+	}
+}
+
+// Tests that the rewards reported by BlockRewards are the ones credited by the
+// consensus engine, uncles included, in every era.
+func TestBlockRewards(t *testing.T) {
+	var (
+		genesis   = &types.Header{Number: new(big.Int), Extra: common.Address{0xcc}.Bytes()}
+		contract  = RewardContract(params.TestChainConfig, big.NewInt(1), genesis)
+		veterans  = common.Address{0xaa}
+		followers = common.Address{0xbb}
+		miner     = common.Address{0x01}
+	)
+	for _, number := range []int64{500, 50000, 1500000, 3000000} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetState(contract, common.BytesToHash([]byte{1}), veterans.Hash())
+		statedb.SetState(contract, common.BytesToHash([]byte{2}), followers.Hash())
+
+		header := &types.Header{Number: big.NewInt(number), Coinbase: miner}
+		uncles := []*types.Header{
+			{Number: big.NewInt(number - 1), Coinbase: common.Address{0x02}},
+			{Number: big.NewInt(number - 3), Coinbase: common.Address{0x03}},
+		}
+		AccumulateNewRewards(params.TestChainConfig, statedb, header, uncles, genesis)
+
+		var (
+			blockShares, uncleShares = BlockRewards(params.TestChainConfig, header.Number, uncles)
+			vetTotal                 = new(big.Int).Set(blockShares.Veterans)
+			folTotal                 = new(big.Int)
+		)
+		if blockShares.Followers != nil {
+			folTotal.Set(blockShares.Followers)
+		}
+		if have := statedb.GetBalance(miner); have.Cmp(blockShares.Miner) != 0 {
+			t.Errorf("block %d: miner reward mismatch: have %v, want %v", number, have, blockShares.Miner)
+		}
+		for i, uncle := range uncles {
+			if have := statedb.GetBalance(uncle.Coinbase); have.Cmp(uncleShares[i].Miner) != 0 {
+				t.Errorf("block %d, uncle %d: miner reward mismatch: have %v, want %v", number, i, have, uncleShares[i].Miner)
+			}
+			vetTotal.Add(vetTotal, uncleShares[i].Veterans)
+			if uncleShares[i].Followers != nil {
+				folTotal.Add(folTotal, uncleShares[i].Followers)
+			}
+		}
+		if have := statedb.GetBalance(veterans); have.Cmp(vetTotal) != 0 {
+			t.Errorf("block %d: veterans reward mismatch: have %v, want %v", number, have, vetTotal)
+		}
+		if have := statedb.GetBalance(followers); have.Cmp(folTotal) != 0 {
+			t.Errorf("block %d: followers reward mismatch: have %v, want %v", number, have, folTotal)
+		}
+	}
+}
+
+// Tests that the reward recipients are read from the registry configured by the
+// chain config once migrated, without needing the genesis.
+func TestRewardRegistryMigration(t *testing.T) {
+	var (
+		genesis  = &types.Header{Number: new(big.Int), Extra: common.Address{0xcc}.Bytes()}
+		registry = common.Address{0xdd}
+		config   = *params.TestChainConfig
+	)
+	config.RewardRegistryBlock = big.NewInt(10)
+	config.RewardRegistry = registry
+
+	derived := RewardContract(&config, big.NewInt(9), genesis)
+	if derived != RewardContract(params.TestChainConfig, big.NewInt(10), genesis) {
+		t.Fatalf("pre-migration contract mismatch: have %x", derived)
+	}
+	if have := RewardContract(&config, big.NewInt(10), nil); have != registry {
+		t.Fatalf("post-migration contract mismatch: have %x, want %x", have, registry)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(derived, common.BytesToHash([]byte{1}), common.Address{0x01}.Hash())
+	statedb.SetState(registry, common.BytesToHash([]byte{1}), common.Address{0x02}.Hash())
+
+	if vet, _ := RewardAddresses(&config, statedb, big.NewInt(9), genesis); vet != (common.Address{0x01}) {
+		t.Errorf("pre-migration veterans mismatch: have %x, want %x", vet, common.Address{0x01})
+	}
+	if vet, _ := RewardAddresses(&config, statedb, big.NewInt(10), nil); vet != (common.Address{0x02}) {
+		t.Errorf("post-migration veterans mismatch: have %x, want %x", vet, common.Address{0x02})
+	}
+}
+
+// Tests that the cached reward recipients are refreshed once the storage root of
+// the reward contract changes, and bypassed while it has unhashed modifications.
+func TestRewardAddressesCache(t *testing.T) {
+	var (
+		genesis  = &types.Header{Number: new(big.Int), Extra: common.Address{0xce}.Bytes()}
+		contract = RewardContract(params.TestChainConfig, big.NewInt(1), genesis)
+		number   = big.NewInt(1)
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetState(contract, common.BytesToHash([]byte{1}), common.Address{0x01}.Hash())
+	statedb.IntermediateRoot(true)
+
+	if vet, _ := RewardAddresses(params.TestChainConfig, statedb, number, genesis); vet != (common.Address{0x01}) {
+		t.Fatalf("veterans mismatch: have %x, want %x", vet, common.Address{0x01})
+	}
+	// Unhashed modifications must be seen without waiting for the root to change
+	statedb.SetState(contract, common.BytesToHash([]byte{1}), common.Address{0x02}.Hash())
+	if vet, _ := RewardAddresses(params.TestChainConfig, statedb, number, genesis); vet != (common.Address{0x02}) {
+		t.Fatalf("dirty veterans mismatch: have %x, want %x", vet, common.Address{0x02})
+	}
+	// Once hashed, the new root must invalidate the cached recipients
+	statedb.IntermediateRoot(true)
+	if vet, _ := RewardAddresses(params.TestChainConfig, statedb, number, genesis); vet != (common.Address{0x02}) {
+		t.Fatalf("hashed veterans mismatch: have %x, want %x", vet, common.Address{0x02})
+	}
+}
+
+// Tests that reward eras defined in the chain config override the default split
+// schedule.
+func TestCustomRewardEras(t *testing.T) {
+	config := *params.TestChainConfig
+	config.Ethash = &params.EthashConfig{RewardEras: []params.RewardEra{
+		{Name: "first", Block: big.NewInt(0), Miner: 90, Veterans: 10},
+		{Name: "second", Block: big.NewInt(5000), Miner: 50, Veterans: 20, Followers: 30},
+	}}
+	shares, _ := BlockRewards(&config, big.NewInt(4999), nil)
+	if shares.Followers != nil {
+		t.Errorf("followers paid in first era: %v", shares.Followers)
+	}
+	shares, _ = BlockRewards(&config, big.NewInt(5000), nil)
+	reward := blockReward(big.NewInt(5000)).ToBig()
+	for name, pair := range map[string][2]*big.Int{
+		"miner":     {shares.Miner, big.NewInt(50)},
+		"veterans":  {shares.Veterans, big.NewInt(20)},
+		"followers": {shares.Followers, big.NewInt(30)},
+	} {
+		want := new(big.Int).Div(new(big.Int).Mul(reward, pair[1]), big.NewInt(100))
+		if pair[0] == nil || pair[0].Cmp(want) != 0 {
+			t.Errorf("%s share mismatch: have %v, want %v", name, pair[0], want)
+		}
+	}
+	eras := RewardSchedule(&config)
+	var found bool
+	for _, era := range eras {
+		if era.FromBlock == 5000 && era.Name == "second" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("custom era boundary missing from schedule")
+	}
+}
+
+// rewardChain is a chain header reader only knowing its genesis header.
+type rewardChain struct {
+	config  *params.ChainConfig
+	genesis *types.Header
+}
+
+func (c *rewardChain) Config() *params.ChainConfig                 { return c.config }
+func (c *rewardChain) CurrentHeader() *types.Header                { return c.genesis }
+func (c *rewardChain) GetHeader(common.Hash, uint64) *types.Header { return nil }
+func (c *rewardChain) GetHeaderByHash(common.Hash) *types.Header   { return nil }
+func (c *rewardChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number == 0 {
+		return c.genesis
+	}
+	return nil
+}
+
+// Tests that the rewards paid out for blocks around the slow-start, indica and
+// sativa boundaries, uncles included, agree with the emission schedule, and that
+// tampered payouts are caught.
+func TestVerifyRewardPayout(t *testing.T) {
+	var (
+		genesis   = &types.Header{Number: new(big.Int), Extra: common.Address{0xcf}.Bytes()}
+		contract  = RewardContract(params.TestChainConfig, big.NewInt(1), genesis)
+		veterans  = common.Address{0x01}
+		followers = common.Address{0x02}
+		chain     = &rewardChain{config: params.TestChainConfig, genesis: genesis}
+		engine    = NewFaker()
+	)
+	numbers := []int64{
+		1, 999, 1000, 1001, // slow-start
+		99999, 100000, 999999, 1000000, 1000001, // declining reward until flat
+		1111111, 1111112, // indica
+		2102400, 2102401, // sativa
+	}
+	for _, number := range numbers {
+		header := &types.Header{Number: big.NewInt(number), Coinbase: common.Address{0xaa}}
+
+		var uncles []*types.Header
+		if number > 7 {
+			uncles = []*types.Header{
+				{Number: big.NewInt(number - 1), Coinbase: common.Address{0xbb}},
+				{Number: big.NewInt(number - 7), Coinbase: common.Address{0xaa}},
+			}
+		}
+		tampers := map[string]func(*state.StateDB){
+			"inflated miner share": func(statedb *state.StateDB) {
+				statedb.AddBalance(header.Coinbase, common.Big1)
+			},
+			"withheld veterans share": func(statedb *state.StateDB) {
+				statedb.SubBalance(veterans, common.Big1)
+			},
+			"redirected veterans share": func(statedb *state.StateDB) {
+				shares, _ := BlockRewards(params.TestChainConfig, header.Number, uncles)
+				statedb.SubBalance(veterans, shares.Veterans)
+				statedb.AddBalance(followers, shares.Veterans)
+			},
+		}
+		newState := func() *state.StateDB {
+			statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			statedb.SetState(contract, common.BytesToHash([]byte{1}), veterans.Hash())
+			statedb.SetState(contract, common.BytesToHash([]byte{2}), followers.Hash())
+			statedb.AddBalance(veterans, big.NewInt(1))
+			return statedb
+		}
+		statedb := newState()
+		payout := func() { AccumulateNewRewards(params.TestChainConfig, statedb, header, uncles, genesis) }
+		if err := engine.VerifyRewards(chain, header, uncles, statedb, payout); err != nil {
+			t.Errorf("block %d: reward verification failed: %v", number, err)
+		}
+		for name, tamper := range tampers {
+			statedb := newState()
+			payout := func() {
+				AccumulateNewRewards(params.TestChainConfig, statedb, header, uncles, genesis)
+				tamper(statedb)
+			}
+			if err := engine.VerifyRewards(chain, header, uncles, statedb, payout); !errors.Is(err, errRewardOffSchedule) {
+				t.Errorf("block %d: %s error mismatch: have %v, want %v", number, name, err, errRewardOffSchedule)
+			}
+		}
+	}
+}
+
+// Tests that payouts are checked against the emission schedule rather than the
+// calculation paying them out, by verifying correct payouts against schedules
+// with a tampered reward or split.
+func TestVerifyRewardSchedule(t *testing.T) {
+	var (
+		genesis  = &types.Header{Number: new(big.Int), Extra: common.Address{0xcf}.Bytes()}
+		contract = RewardContract(params.TestChainConfig, big.NewInt(1), genesis)
+		chain    = &rewardChain{config: params.TestChainConfig, genesis: genesis}
+		header   = &types.Header{Number: big.NewInt(1111112), Coinbase: common.Address{0xaa}}
+		uncles   = []*types.Header{{Number: big.NewInt(1111110), Coinbase: common.Address{0xbb}}}
+	)
+	tampers := map[string]func(era *RewardEra){
+		"none":              func(era *RewardEra) {},
+		"raised reward":     func(era *RewardEra) { era.Reward = new(big.Int).Add(era.Reward, common.Big1) },
+		"swapped split":     func(era *RewardEra) { era.Miner, era.Veterans = era.Veterans, era.Miner },
+		"dropped followers": func(era *RewardEra) { era.Miner, era.Followers = era.Miner+era.Followers, 0 },
+	}
+	for name, tamper := range tampers {
+		var schedule []*RewardEra
+		for _, era := range RewardSchedule(params.TestChainConfig) {
+			era := *era
+			if era.FromBlock <= header.Number.Uint64() && (era.ToBlock == nil || header.Number.Uint64() <= *era.ToBlock) {
+				tamper(&era)
+			}
+			schedule = append(schedule, &era)
+		}
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetState(contract, common.BytesToHash([]byte{1}), common.Address{0x01}.Hash())
+		statedb.SetState(contract, common.BytesToHash([]byte{2}), common.Address{0x02}.Hash())
+
+		err := verifyRewards(schedule, chain, header, uncles, statedb, func() {
+			AccumulateNewRewards(params.TestChainConfig, statedb, header, uncles, genesis)
+		})
+		if name == "none" {
+			if err != nil {
+				t.Errorf("payout rejected by the untampered schedule: %v", err)
+			}
+		} else if !errors.Is(err, errRewardOffSchedule) {
+			t.Errorf("%s: error mismatch: have %v, want %v", name, err, errRewardOffSchedule)
+		}
+	}
+}
+
+// Tests that blocks paying rewards to unset reward contract slots are rejected
+// once the reward check is enabled.
+func TestVerifyRewardAddresses(t *testing.T) {
+	var (
+		genesis  = &types.Header{Number: new(big.Int), Extra: common.Address{0xcf}.Bytes()}
+		contract = RewardContract(params.TestChainConfig, big.NewInt(1), genesis)
+		config   = *params.TestChainConfig
+		engine   = NewFaker()
+	)
+	config.RewardCheckBlock = big.NewInt(10)
+	chain := &rewardChain{config: &config, genesis: genesis}
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	verify := func(number *big.Int) error {
+		header := &types.Header{Number: number}
+		return engine.VerifyRewards(chain, header, nil, statedb, func() {
+			AccumulateNewRewards(&config, statedb, header, nil, genesis)
+		})
+	}
+	if err := verify(big.NewInt(9)); err != nil {
+		t.Fatalf("unset recipients rejected before the fork: %v", err)
+	}
+	if err := verify(big.NewInt(10)); err != errZeroVeterans {
+		t.Fatalf("unset veterans error mismatch: have %v, want %v", err, errZeroVeterans)
+	}
+	statedb.SetState(contract, common.BytesToHash([]byte{1}), common.Address{0x01}.Hash())
+	if err := verify(big.NewInt(10)); err != nil {
+		t.Fatalf("set veterans rejected in the ruderalis era: %v", err)
+	}
+	indica := big.NewInt(1111112)
+	if err := verify(indica); err != errZeroFollowers {
+		t.Fatalf("unset followers error mismatch: have %v, want %v", err, errZeroFollowers)
+	}
+	statedb.SetState(contract, common.BytesToHash([]byte{2}), common.Address{0x02}.Hash())
+	if err := verify(indica); err != nil {
+		t.Fatalf("set recipients rejected in the indica era: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/math"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// VerifySmokeBaseFeeHeader verifies the smoke limit and the base fee of a header
+// of the smoke base fee fork. The smoke limit is allowed to double at the fork
+// block, turning the previous limit into the smoke target.
+func VerifySmokeBaseFeeHeader(config *params.ChainConfig, parent, header *types.Header) error {
+	parentSmokeLimit := parent.SmokeLimit
+	if !config.IsSmokeBaseFee(parent.Number) {
+		parentSmokeLimit = parent.SmokeLimit * params.ElasticityMultiplier
+	}
+	if err := VerifySmokeLimit(parentSmokeLimit, header.SmokeLimit); err != nil {
+		return err
+	}
+	if header.BaseFee == nil {
+		return errors.New("header is missing baseFee")
+	}
+	if expected := CalcBaseFee(config, parent); header.BaseFee.Cmp(expected) != 0 {
+		return fmt.Errorf("invalid baseFee: have %s, want %s, parentBaseFee %s, parentSmokeUsed %d",
+			header.BaseFee, expected, parent.BaseFee, parent.SmokeUsed)
+	}
+	return nil
+}
+
+// VerifySmokeLimit verifies that the smoke limit of a header remains within the
+// allowed bounds of its parent's.
+func VerifySmokeLimit(parentSmokeLimit, headerSmokeLimit uint64) error {
+	diff := int64(parentSmokeLimit) - int64(headerSmokeLimit)
+	if diff < 0 {
+		diff *= -1
+	}
+	limit := parentSmokeLimit / params.SmokeLimitBoundDivisor
+	if uint64(diff) >= limit {
+		return fmt.Errorf("invalid smoke limit: have %d, want %d += %d", headerSmokeLimit, parentSmokeLimit, limit-1)
+	}
+	if headerSmokeLimit < params.MinSmokeLimit {
+		return fmt.Errorf("invalid smoke limit: have %d, min %d", headerSmokeLimit, params.MinSmokeLimit)
+	}
+	return nil
+}
+
+// CalcBaseFee calculates the base fee of the header following the given parent.
+// The base fee moves towards the one at which blocks use half their smoke limit,
+// by at most 1/BaseFeeChangeDenominator per block.
+func CalcBaseFee(config *params.ChainConfig, parent *types.Header) *big.Int {
+	// The first block of the fork starts from the initial base fee
+	if !config.IsSmokeBaseFee(parent.Number) {
+		return new(big.Int).SetUint64(params.InitialBaseFee)
+	}
+	var (
+		parentSmokeTarget        = parent.SmokeLimit / params.ElasticityMultiplier
+		parentSmokeTargetBig     = new(big.Int).SetUint64(parentSmokeTarget)
+		baseFeeChangeDenominator = new(big.Int).SetUint64(params.BaseFeeChangeDenominator)
+	)
+	// If the parent smokeUsed is the same as the target, the baseFee remains unchanged
+	if parent.SmokeUsed == parentSmokeTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+	if parent.SmokeUsed > parentSmokeTarget {
+		// If the parent block used more smoke than its target, the baseFee should increase
+		smokeUsedDelta := new(big.Int).SetUint64(parent.SmokeUsed - parentSmokeTarget)
+		x := new(big.Int).Mul(parent.BaseFee, smokeUsedDelta)
+		y := x.Div(x, parentSmokeTargetBig)
+		baseFeeDelta := math.BigMax(
+			x.Div(y, baseFeeChangeDenominator),
+			common.Big1,
+		)
+		return x.Add(parent.BaseFee, baseFeeDelta)
+	}
+	// Otherwise if the parent block used less smoke than its target, the baseFee should decrease
+	smokeUsedDelta := new(big.Int).SetUint64(parentSmokeTarget - parent.SmokeUsed)
+	x := new(big.Int).Mul(parent.BaseFee, smokeUsedDelta)
+	y := x.Div(x, parentSmokeTargetBig)
+	baseFeeDelta := x.Div(y, baseFeeChangeDenominator)
+
+	return math.BigMax(
+		x.Sub(parent.BaseFee, baseFeeDelta),
+		common.Big0,
+	)
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// baseFeeConfig returns a chain config with the smoke base fee fork at block 5.
+func baseFeeConfig() *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.SmokeBaseFeeBlock = big.NewInt(5)
+	return &config
+}
+
+// Tests that the base fee moves towards the smoke target of the parent block.
+func TestCalcBaseFee(t *testing.T) {
+	tests := []struct {
+		parentBaseFee    uint64
+		parentSmokeLimit uint64
+		parentSmokeUsed  uint64
+		expectedBaseFee  uint64
+	}{
+		{params.InitialBaseFee, 20000000, 10000000, params.InitialBaseFee}, // usage == target
+		{params.InitialBaseFee, 20000000, 9000000, 987500000},              // usage below target
+		{params.InitialBaseFee, 20000000, 11000000, 1012500000},            // usage above target
+		{params.InitialBaseFee, 20000000, 0, 875000000},                    // empty block
+		{params.InitialBaseFee, 20000000, 20000000, 1125000000},            // full block
+		{7, 20000000, 10000001, 8},                                         // minimum increase
+	}
+	for i, tt := range tests {
+		parent := &types.Header{
+			Number:     big.NewInt(5),
+			SmokeLimit: tt.parentSmokeLimit,
+			SmokeUsed:  tt.parentSmokeUsed,
+			BaseFee:    new(big.Int).SetUint64(tt.parentBaseFee),
+		}
+		if have := CalcBaseFee(baseFeeConfig(), parent); have.Uint64() != tt.expectedBaseFee {
+			t.Errorf("test %d: base fee mismatch: have %d, want %d", i, have, tt.expectedBaseFee)
+		}
+	}
+}
+
+// Tests the verification of the headers around the fork block, whose smoke limit
+// may double and whose base fee starts from the initial one.
+func TestVerifySmokeBaseFeeHeader(t *testing.T) {
+	config := baseFeeConfig()
+	parent := &types.Header{
+		Number:     big.NewInt(4),
+		SmokeLimit: 10000000,
+		SmokeUsed:  5000000,
+	}
+	header := &types.Header{
+		Number:     big.NewInt(5),
+		SmokeLimit: parent.SmokeLimit * params.ElasticityMultiplier,
+		BaseFee:    new(big.Int).SetUint64(params.InitialBaseFee),
+	}
+	if err := VerifySmokeBaseFeeHeader(config, parent, header); err != nil {
+		t.Fatalf("fork block rejected: %v", err)
+	}
+	header.BaseFee = nil
+	if err := VerifySmokeBaseFeeHeader(config, parent, header); err == nil {
+		t.Errorf("fork block without base fee accepted")
+	}
+	header.BaseFee = big.NewInt(1)
+	if err := VerifySmokeBaseFeeHeader(config, parent, header); err == nil {
+		t.Errorf("fork block with invalid base fee accepted")
+	}
+	header.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
+	header.SmokeLimit = parent.SmokeLimit * 3
+	if err := VerifySmokeBaseFeeHeader(config, parent, header); err == nil {
+		t.Errorf("fork block with excessive smoke limit accepted")
+	}
+}
@@ -48,6 +48,10 @@ var (
 // HistoryFile is the file within the data directory to store input scrollback.
 const HistoryFile = "history"
 
+// PreloadDir is the directory within the data directory holding the persistent
+// helper scripts loaded into every console session, before any explicit preloads.
+const PreloadDir = "preload"
+
 // DefaultPrompt is the default prompt line prefix to use for user input querying.
 const DefaultPrompt = "> "
 
@@ -102,7 +106,12 @@ func New(config Config) (*Console, error) {
 	if err := os.MkdirAll(config.DataDir, 0700); err != nil {
 		return nil, err
 	}
-	if err := console.init(config.Preload); err != nil {
+	// Persistent helpers are loaded in name order, explicit preloads may override them
+	persistent, err := filepath.Glob(filepath.Join(config.DataDir, PreloadDir, "*.js"))
+	if err != nil {
+		return nil, err
+	}
+	if err := console.init(append(persistent, config.Preload...)); err != nil {
 		return nil, err
 	}
 	return console, nil
@@ -126,6 +135,7 @@ func (c *Console) init(preload []string) error {
 	c.jsre.Do(func(vm *goja.Runtime) {
 		c.initAdmin(vm, bridge)
 		c.initPersonal(vm, bridge)
+		c.initHelp(vm)
 	})
 
 	// Preload JavaScript files.
@@ -179,7 +189,10 @@ func (c *Console) initWeb3(bridge *bridge) error {
 		transport.Set("send", jsre.MakeCallback(vm, bridge.Send))
 		transport.Set("sendAsync", jsre.MakeCallback(vm, bridge.Send))
 		vm.Set("_consoleWeb3Transport", transport)
-		_, err = vm.RunString("var web3 = new Web3(_consoleWeb3Transport)")
+		if _, err = vm.RunString("var web3 = new Web3(_consoleWeb3Transport)"); err != nil {
+			return
+		}
+		_, err = vm.RunString(unitsJS)
 	})
 	return err
 }
@@ -225,6 +238,39 @@ func (c *Console) initAdmin(vm *goja.Runtime, bridge *bridge) {
 	}
 }
 
+// initHelp adds a help function to every namespace documented in web3ext.Docs,
+// printing the docs of a single method or listing all of them.
+func (c *Console) initHelp(vm *goja.Runtime) {
+	for namespace, docs := range web3ext.Docs {
+		obj := getObject(vm, namespace)
+		if obj == nil {
+			continue
+		}
+		namespace, docs := namespace, docs
+		obj.Set("help", func(call goja.FunctionCall) goja.Value {
+			if method := call.Argument(0); !goja.IsUndefined(method) {
+				name := method.String()
+				if doc, ok := docs[name]; ok {
+					fmt.Fprintf(c.printer, "%s.%s\n\n%s\n", namespace, name, doc)
+				} else {
+					fmt.Fprintf(c.printer, "No documentation for %s.%s\n", namespace, name)
+				}
+				return goja.Null()
+			}
+			names := make([]string, 0, len(docs))
+			for name := range docs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				summary := strings.SplitN(docs[name], "\n", 2)[0]
+				fmt.Fprintf(c.printer, "%s.%s: %s\n", namespace, name, summary)
+			}
+			return goja.Null()
+		})
+	}
+}
+
 // initPersonal redirects account-related API methods through the bridge.
 //
 // If the console is in interactive mode and the 'personal' API is available, override
@@ -339,6 +385,17 @@ func (c *Console) Evaluate(statement string) {
 	c.jsre.Evaluate(statement, c.printer)
 }
 
+// EvaluateJSON executes code and prints the result as machine readable JSON,
+// returning any evaluation failure instead of printing it.
+func (c *Console) EvaluateJSON(statement string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[native] error: %v", r)
+		}
+	}()
+	return c.jsre.EvaluateJSON(statement, c.printer)
+}
+
 // Interactive starts an interactive user session, where input is propted from
 // the configured user prompter.
 func (c *Console) Interactive() {
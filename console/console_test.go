@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/420"
 	"github.com/420integrated/go-420coin/internal/jsre"
+	"github.com/420integrated/go-420coin/internal/web3ext"
 	"github.com/420integrated/go-420coin/miner"
 	"github.com/420integrated/go-420coin/node"
 )
@@ -195,6 +197,22 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+// Tests that the namespaces document their methods via help().
+func TestHelp(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	tester.console.Evaluate(`miner.help("setSmokePrice")`)
+	if output := tester.output.String(); !strings.Contains(output, web3ext.Docs["miner"]["setSmokePrice"]) {
+		t.Fatalf("method help mismatch: have %s", output)
+	}
+	tester.output.Reset()
+	tester.console.Evaluate(`miner.help()`)
+	if output := tester.output.String(); !strings.Contains(output, "miner.setFourtwentycoinbase: ") {
+		t.Fatalf("namespace help missing method: have %s", output)
+	}
+}
+
 // Tests that the console can be used in interactive mode.
 func TestInteractive(t *testing.T) {
 	// Create a tester and run an interactive console in the background
@@ -225,6 +243,35 @@ func TestInteractive(t *testing.T) {
 	}
 }
 
+// Tests that the console converts between the 420coin denominations.
+func TestUnits(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"web3.toMarley(1, 'maher')", "1000000000"},
+		{"web3.toMarley('0.5')", "500000000000000000"},
+		{"web3.fromMarley('1500000000000000000', '420coin')", "1.5"},
+		{"web3.toMaher(2, 'woody')", "0.000002"},
+		{"web3.fromMaher(3, 'Gmarley')", "3"},
+	}
+	for _, tt := range tests {
+		tester.output.Reset()
+		tester.console.Evaluate(tt.code)
+		if output := tester.output.String(); !strings.Contains(output, tt.want) {
+			t.Errorf("%s: output mismatch: have %s, want %s", tt.code, output, tt.want)
+		}
+	}
+	tester.output.Reset()
+	tester.console.Evaluate("web3.toMarley(1, 'ether')")
+	if output := tester.output.String(); !strings.Contains(output, "unknown unit") {
+		t.Errorf("foreign unit accepted: %s", output)
+	}
+}
+
 // Tests that preloaded JavaScript files have been executed before user is given
 // input.
 func TestPreload(t *testing.T) {
@@ -237,6 +284,49 @@ func TestPreload(t *testing.T) {
 	}
 }
 
+// Tests that the helper scripts persisted in the data directory are loaded into
+// new console sessions before the explicit preloads.
+func TestPersistentPreload(t *testing.T) {
+	tester := newTester(t, nil)
+	defer tester.Close(t)
+
+	datadir := tester.stack.DataDir()
+	if err := os.MkdirAll(filepath.Join(datadir, PreloadDir), 0700); err != nil {
+		t.Fatalf("failed to create preload dir: %v", err)
+	}
+	helper := []byte("function helper() { return 'some-helper-string' }; var preloaded = 'overridden';")
+	if err := ioutil.WriteFile(filepath.Join(datadir, PreloadDir, "helper.js"), helper, 0600); err != nil {
+		t.Fatalf("failed to write helper script: %v", err)
+	}
+	client, err := tester.stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to attach to node: %v", err)
+	}
+	printer := new(bytes.Buffer)
+	console, err := New(Config{
+		DataDir:  datadir,
+		DocRoot:  "testdata",
+		Client:   client,
+		Prompter: &hookedPrompter{scheduler: make(chan string)},
+		Printer:  printer,
+		Preload:  []string{"preload.js"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create JavaScript console: %v", err)
+	}
+	defer console.Stop(false)
+
+	console.Evaluate("helper()")
+	if output := printer.String(); !strings.Contains(output, "some-helper-string") {
+		t.Fatalf("persistent helper missing: have %s, want %s", output, "some-helper-string")
+	}
+	printer.Reset()
+	console.Evaluate("preloaded")
+	if output := printer.String(); !strings.Contains(output, "some-preloaded-string") {
+		t.Fatalf("explicit preload not applied last: have %s, want %s", output, "some-preloaded-string")
+	}
+}
+
 // Tests that JavaScript scripts can be executes from the configured asset path.
 func TestExecute(t *testing.T) {
 	tester := newTester(t, nil)
@@ -0,0 +1,65 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package console
+
+// unitsJS installs the conversion helpers between the 420coin denominations on
+// the console's web3 object. The bundled web3.js only knows the ether units, so
+// its toWei and fromWei would silently use the wrong names for this chain.
+//
+// Like their web3.js counterparts, the helpers return a BigNumber when given one
+// and a decimal string otherwise.
+const unitsJS = `
+(function() {
+	var units = {
+		'marley':  '1',
+		'kmarley': '1000',
+		'woody':   '1000',
+		'mmarley': '1000000',
+		'rogen':   '1000000',
+		'gmarley': '1000000000',
+		'maher':   '1000000000',
+		'snoop':   '1000000000000',
+		'willie':  '1000000000000000',
+		'420coin': '1000000000000000000',
+		'cheech':  '1000000000000000000000',
+		'chong':   '1000000000000000000000000'
+	};
+	var unitValue = function(unit) {
+		unit = unit ? unit.toLowerCase() : '420coin';
+		if (!units.hasOwnProperty(unit)) {
+			throw new Error("unknown unit '" + unit + "', use one of: " + Object.keys(units).join(', '));
+		}
+		return new web3.BigNumber(units[unit], 10);
+	};
+	var convert = function(number, value) {
+		return number instanceof web3.BigNumber ? value : value.toString(10);
+	};
+	web3.units = Object.keys(units);
+	web3.toMarley = function(number, unit) {
+		return convert(number, web3.toBigNumber(number).times(unitValue(unit)));
+	};
+	web3.fromMarley = function(number, unit) {
+		return convert(number, web3.toBigNumber(number).dividedBy(unitValue(unit)));
+	};
+	web3.toMaher = function(number, unit) {
+		return convert(number, web3.toBigNumber(number).times(unitValue(unit)).dividedBy(unitValue('maher')));
+	};
+	web3.fromMaher = function(number, unit) {
+		return convert(number, web3.toBigNumber(number).times(unitValue('maher')).dividedBy(unitValue(unit)));
+	};
+})();
+`
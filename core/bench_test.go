@@ -85,7 +85,7 @@ func genValueTx(nbytes int) func(int, *BlockGen) {
 	return func(i int, gen *BlockGen) {
 		toaddr := common.Address{}
 		data := make([]byte, nbytes)
-		smoke, _ := IntrinsicSmoke(data, false, false, false)
+		smoke, _ := IntrinsicSmoke(data, nil, false, false, false)
 		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(benchRootAddr), toaddr, big.NewInt(1), smoke, nil, data), types.HomesteadSigner{}, benchRootKey)
 		gen.AddTx(tx)
 	}
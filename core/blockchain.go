@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/common/mclock"
 	"github.com/420integrated/go-420coin/common/prque"
 	"github.com/420integrated/go-420coin/consensus"
@@ -44,7 +45,6 @@ import (
 	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rlp"
 	"github.com/420integrated/go-420coin/trie"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 var (
@@ -115,7 +115,11 @@ const (
 	// - Version 8
 	//  The following incompatible database changes were added:
 	//    * New scheme for contract code in order to separate the codes and trie nodes
-	BlockChainVersion uint64 = 8
+	// - Version 9
+	//  The following incompatible database changes were added:
+	//    * the smoke refund applied to a transaction is stored in its receipt, receipts
+	//      written by older versions are still decoded, lacking the refund
+	BlockChainVersion uint64 = 9
 )
 
 // CacheConfig contains the configuration values for the trie caching/pruning
@@ -174,6 +178,12 @@ type BlockChain struct {
 	//  * nil: disable tx reindexer/deleter, but still index new blocks
 	txLookupLimit uint64
 
+	// maxReorgDepth is the maximum number of canonical blocks an automatic
+	// reorg may drop, deeper ones wait for ApproveReorg (0 = unlimited).
+	maxReorgDepth uint64
+	pendingReorg  atomic.Value // *PendingReorg refused for exceeding maxReorgDepth
+	checkpoint    atomic.Value // *federatedCheckpoint the canonical chain may not reorg behind
+
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
 	chainFeed     event.Feed
@@ -190,12 +200,12 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
-	bodyCache     *lru.Cache     // Cache for the most recent block bodies
-	bodyRLPCache  *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
-	receiptsCache *lru.Cache     // Cache for the most recent receipts per block
-	blockCache    *lru.Cache     // Cache for the most recent entire blocks
-	txLookupCache *lru.Cache     // Cache for the most recent transaction lookup data.
-	futureBlocks  *lru.Cache     // future blocks are blocks added for later processing
+	bodyCache     *cache.Cache   // Cache for the most recent block bodies
+	bodyRLPCache  *cache.Cache   // Cache for the most recent block bodies in RLP encoded format
+	receiptsCache *cache.Cache   // Cache for the most recent receipts per block
+	blockCache    *cache.Cache   // Cache for the most recent entire blocks
+	txLookupCache *cache.Cache   // Cache for the most recent transaction lookup data.
+	futureBlocks  *cache.Cache   // future blocks are blocks added for later processing
 
 	quit          chan struct{}  // blockchain quit channel
 	wg            sync.WaitGroup // chain processing wait group for shutting down
@@ -208,7 +218,7 @@ type BlockChain struct {
 	processor  Processor  // Block transaction processor interface
 	vmConfig   vm.Config
 
-	badBlocks          *lru.Cache                     // Bad block cache
+	badBlocks          *cache.Cache                   // Bad block cache
 	shouldPreserve     func(*types.Block) bool        // Function used to determine whether should preserve the given block.
 	terminateInsert    func(common.Hash, uint64) bool // Testing hook used to terminate ancient receipt chain insertion.
 	writeLegacyJournal bool                           // Testing flag used to flush the snapshot journal in legacy format.
@@ -221,13 +231,13 @@ func NewBlockChain(db fourtwentydb.Database, cacheConfig *CacheConfig, chainConf
 	if cacheConfig == nil {
 		cacheConfig = defaultCacheConfig
 	}
-	bodyCache, _ := lru.New(bodyCacheLimit)
-	bodyRLPCache, _ := lru.New(bodyCacheLimit)
-	receiptsCache, _ := lru.New(receiptsCacheLimit)
-	blockCache, _ := lru.New(blockCacheLimit)
-	txLookupCache, _ := lru.New(txLookupCacheLimit)
-	futureBlocks, _ := lru.New(maxFutureBlocks)
-	badBlocks, _ := lru.New(badBlockLimit)
+	bodyCache := cache.New("chain/cache/bodies", bodyCacheLimit, 0)
+	bodyRLPCache := cache.New("chain/cache/bodyrlps", bodyCacheLimit, 0)
+	receiptsCache := cache.New("chain/cache/receipts", receiptsCacheLimit, 0)
+	blockCache := cache.New("chain/cache/blocks", blockCacheLimit, 0)
+	txLookupCache := cache.New("chain/cache/txlookups", txLookupCacheLimit, 0)
+	futureBlocks := cache.New("", maxFutureBlocks, 0)
+	badBlocks := cache.New("", badBlockLimit, 0)
 
 	bc := &BlockChain{
 		chainConfig: chainConfig,
@@ -260,6 +270,7 @@ func NewBlockChain(db fourtwentydb.Database, cacheConfig *CacheConfig, chainConf
 	if err != nil {
 		return nil, err
 	}
+	bc.hc.reorgAllowed = bc.reorgAllowed
 	bc.genesisBlock = bc.GetBlockByNumber(0)
 	if bc.genesisBlock == nil {
 		return nil, ErrNoGenesis
@@ -481,8 +492,21 @@ func (bc *BlockChain) loadLastState() error {
 // was fast synced or full synced and in which state, the method will try to
 // delete minimal data from disk whilst retaining chain consistency.
 func (bc *BlockChain) SetHead(head uint64) error {
-	_, err := bc.SetHeadBeyondRoot(head, common.Hash{})
-	return err
+	if _, err := bc.SetHeadBeyondRoot(head, common.Hash{}); err != nil {
+		return err
+	}
+	block := bc.CurrentBlock()
+
+	// If the snapshot tree doesn't have a layer for the new head, the diff layers
+	// above the disk layer are stale; regenerate from the rewound state.
+	if bc.snaps != nil && bc.snaps.Snapshot(block.Root()) == nil {
+		log.Warn("Snapshot layers stale after rewind, rebuilding", "number", block.NumberU64(), "root", block.Root())
+		bc.snaps.Rebuild(block.Root())
+	}
+	// Send a chain head event so that subscribed subsystems (transaction pool,
+	// miner, chain indexers) unwind to the new head
+	bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+	return nil
 }
 
 // SetHeadBeyondRoot rewinds the local chain to a new head with the extra condition
@@ -664,6 +688,20 @@ func (bc *BlockChain) Snapshots() *snapshot.Tree {
 	return bc.snaps
 }
 
+// RebuildSnapshot discards the state snapshot and starts regenerating it in the
+// background from the state of the current head block, returning its root. The
+// chain is locked meanwhile, so no block is imported on top of a stale tree.
+func (bc *BlockChain) RebuildSnapshot() common.Hash {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	root := bc.CurrentBlock().Root()
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(root)
+	}
+	return root
+}
+
 // CurrentFastBlock retrieves the current fast-sync head block of the canonical
 // chain. The block is retrieved from the blockchain's internal cache.
 func (bc *BlockChain) CurrentFastBlock() *types.Block {
@@ -819,7 +857,7 @@ func (bc *BlockChain) GetBody(hash common.Hash) *types.Body {
 		return nil
 	}
 	// Cache the found body for next time and return
-	bc.bodyCache.Add(hash, body)
+	bc.bodyCache.AddSized(hash, body, bodySize(body))
 	return body
 }
 
@@ -839,7 +877,7 @@ func (bc *BlockChain) GetBodyRLP(hash common.Hash) rlp.RawValue {
 		return nil
 	}
 	// Cache the found body for next time and return
-	bc.bodyRLPCache.Add(hash, body)
+	bc.bodyRLPCache.AddSized(hash, body, len(body))
 	return body
 }
 
@@ -891,7 +929,7 @@ func (bc *BlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
 		return nil
 	}
 	// Cache the found block for next time and return
-	bc.blockCache.Add(block.Hash(), block)
+	bc.blockCache.AddSized(block.Hash(), block, blockSize(block))
 	return block
 }
 
@@ -927,10 +965,45 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	if receipts == nil {
 		return nil
 	}
-	bc.receiptsCache.Add(hash, receipts)
+	bc.receiptsCache.AddSized(hash, receipts, receiptsSize(receipts))
 	return receipts
 }
 
+// WriteReceiptStatuses stores the transaction outcomes of a pre-Byzantium block,
+// obtained by re-executing it, and drops the cached receipts of the block so the
+// statuses are reported from now on.
+func (bc *BlockChain) WriteReceiptStatuses(hash common.Hash, number uint64, statuses []uint64) {
+	rawdb.WriteReceiptStatuses(bc.db, hash, number, statuses)
+	bc.receiptsCache.Remove(hash)
+}
+
+// bodySize approximates the memory used by a block body for cache accounting.
+func bodySize(body *types.Body) int {
+	var size common.StorageSize
+	for _, tx := range body.Transactions {
+		size += tx.Size()
+	}
+	for _, uncle := range body.Uncles {
+		size += uncle.Size()
+	}
+	return int(size)
+}
+
+// blockSize approximates the memory used by a block for cache accounting.
+func blockSize(block *types.Block) int {
+	return int(block.Header().Size()) + bodySize(block.Body())
+}
+
+// receiptsSize approximates the memory used by the receipts of a block for cache
+// accounting.
+func receiptsSize(receipts types.Receipts) int {
+	var size common.StorageSize
+	for _, receipt := range receipts {
+		size += receipt.Size()
+	}
+	return int(size)
+}
+
 // GetBlocksFromHash returns the block corresponding to hash and up to n-1 ancestors.
 // [deprecated by eth/62]
 func (bc *BlockChain) GetBlocksFromHash(hash common.Hash, n int) (blocks []*types.Block) {
@@ -1455,6 +1528,150 @@ func (bc *BlockChain) TxLookupLimit() uint64 {
 	return bc.txLookupLimit
 }
 
+// SetTrieDirtyLimit updates the memory allowance (MB) of the dirty trie nodes,
+// above which they are flushed to disk. The new limit is enforced on the next
+// imported block.
+func (bc *BlockChain) SetTrieDirtyLimit(limit int) {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	bc.cacheConfig.TrieDirtyLimit = limit
+}
+
+// PendingReorg describes a chain reorganisation which was refused for exceeding
+// the maximum automatic reorg depth and is waiting for operator approval.
+type PendingReorg struct {
+	Number   uint64      `json:"number"`   // Number of the head block of the heavier chain
+	Hash     common.Hash `json:"hash"`     // Hash of the head block of the heavier chain
+	Ancestor uint64      `json:"ancestor"` // Number of the common ancestor with the canonical chain
+	Depth    uint64      `json:"depth"`    // Number of canonical blocks the reorg would drop
+}
+
+// federatedCheckpoint is a block finalized by the federation signers.
+type federatedCheckpoint struct {
+	number uint64
+	hash   common.Hash
+}
+
+// SetFederatedCheckpoint sets the latest block finalized by the federation
+// signers. Reorgs dropping the checkpoint or any block below it are refused.
+func (bc *BlockChain) SetFederatedCheckpoint(number uint64, hash common.Hash) {
+	bc.checkpoint.Store(&federatedCheckpoint{number: number, hash: hash})
+	if canon := bc.GetCanonicalHash(number); canon != hash {
+		log.Warn("Federated checkpoint not on the canonical chain", "number", number, "hash", hash, "canonical", canon)
+	}
+}
+
+// FederatedCheckpoint returns the number and hash of the latest block finalized
+// by the federation signers, or a zero hash if there is none.
+func (bc *BlockChain) FederatedCheckpoint() (uint64, common.Hash) {
+	if cp, _ := bc.checkpoint.Load().(*federatedCheckpoint); cp != nil {
+		return cp.number, cp.hash
+	}
+	return 0, common.Hash{}
+}
+
+// behindCheckpoint reports whether a reorg to the given common ancestor would
+// drop the latest federated checkpoint from the canonical chain.
+func (bc *BlockChain) behindCheckpoint(ancestor *types.Header) bool {
+	number, hash := bc.FederatedCheckpoint()
+	return hash != (common.Hash{}) && ancestor.Number.Uint64() < number
+}
+
+// SetMaxReorgDepth sets the maximum number of canonical blocks a reorg may drop
+// automatically. Deeper reorgs are refused until approved with ApproveReorg.
+// Zero disables the limit.
+func (bc *BlockChain) SetMaxReorgDepth(depth uint64) {
+	bc.maxReorgDepth = depth
+}
+
+// PendingReorg returns the last reorg refused for exceeding the maximum reorg
+// depth, or nil if there is none.
+func (bc *BlockChain) PendingReorg() *PendingReorg {
+	pending, _ := bc.pendingReorg.Load().(*PendingReorg)
+	return pending
+}
+
+// ApproveReorg switches the canonical chain to the block with the given hash,
+// regardless of the maximum reorg depth. The block must be fully imported and
+// heavier than the current head.
+func (bc *BlockChain) ApproveReorg(hash common.Hash) error {
+	bc.wg.Add(1)
+	defer bc.wg.Done()
+
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	block := bc.GetBlockByHash(hash)
+	if block == nil {
+		return fmt.Errorf("block %#x not found", hash)
+	}
+	if !bc.HasBlockAndState(hash, block.NumberU64()) {
+		return fmt.Errorf("missing state for block %#x", hash)
+	}
+	current := bc.CurrentBlock()
+	var (
+		localTd  = bc.GetTd(current.Hash(), current.NumberU64())
+		externTd = bc.GetTd(hash, block.NumberU64())
+	)
+	if externTd.Cmp(localTd) <= 0 {
+		return fmt.Errorf("block %#x is not heavier than the current head", hash)
+	}
+	if ancestor := rawdb.FindCommonAncestor(bc.db, current.Header(), block.Header()); ancestor != nil && bc.behindCheckpoint(ancestor) {
+		return fmt.Errorf("block %#x is not descended from the federated checkpoint", hash)
+	}
+	if block.ParentHash() != current.Hash() {
+		if err := bc.reorg(current, block); err != nil {
+			return err
+		}
+	}
+	bc.writeHeadBlock(block)
+	if pending := bc.PendingReorg(); pending != nil && pending.Hash == hash {
+		bc.pendingReorg.Store((*PendingReorg)(nil))
+	}
+	log.Info("Approved chain reorg", "number", block.Number(), "hash", hash)
+
+	bc.chainFeed.Send(ChainEvent{Block: block, Hash: hash})
+	bc.chainHeadFeed.Send(ChainHeadEvent{Block: block})
+	return nil
+}
+
+// reorgAllowed reports whether switching the canonical chain from the current
+// head to the given one keeps the federated checkpoint and stays within the
+// maximum automatic reorg depth. Reorgs exceeding only the depth are recorded
+// as pending approval. It applies to both the block and the header chain.
+func (bc *BlockChain) reorgAllowed(current, head *types.Header) bool {
+	if _, hash := bc.FederatedCheckpoint(); bc.maxReorgDepth == 0 && hash == (common.Hash{}) {
+		return true
+	}
+	ancestor := rawdb.FindCommonAncestor(bc.db, current, head)
+	if ancestor == nil {
+		return true // Let reorg report the broken chain
+	}
+	if bc.behindCheckpoint(ancestor) {
+		number, hash := bc.FederatedCheckpoint()
+		log.Warn("Refusing chain reorg behind federated checkpoint", "number", head.Number, "hash", head.Hash(),
+			"ancestor", ancestor.Number, "checkpoint", number, "checkpointhash", hash)
+		return false
+	}
+	if bc.maxReorgDepth == 0 {
+		return true
+	}
+	depth := current.Number.Uint64() - ancestor.Number.Uint64()
+	if depth <= bc.maxReorgDepth {
+		return true
+	}
+	log.Warn("Refusing deep chain reorg, awaiting approval", "number", head.Number, "hash", head.Hash(),
+		"ancestor", ancestor.Number, "drop", depth, "max", bc.maxReorgDepth)
+	bc.pendingReorg.Store(&PendingReorg{
+		Number:   head.Number.Uint64(),
+		Hash:     head.Hash(),
+		Ancestor: ancestor.Number.Uint64(),
+		Depth:    depth,
+	})
+	return false
+}
+
 var lastWrite uint64
 
 // writeBlockWithoutState writes only the block and its metadata to the database,
@@ -1474,13 +1691,17 @@ func (bc *BlockChain) writeBlockWithoutState(block *types.Block, td *big.Int) (e
 }
 
 // writeKnownBlock updates the head block flag with a known block
-// and introduces chain reorg if necessary.
+// and introduces chain reorg if necessary. Refused reorgs leave the
+// block on the side, they are not an import failure.
 func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
 	current := bc.CurrentBlock()
 	if block.ParentHash() != current.Hash() {
+		if !bc.reorgAllowed(current.Header(), block.Header()) {
+			return nil
+		}
 		if err := bc.reorg(current, block); err != nil {
 			return err
 		}
@@ -1602,6 +1823,10 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 			reorg = !currentPreserve && (blockPreserve || mrand.Float64() < 0.5)
 		}
 	}
+	// Keep deep reorgs on the side until the operator approves them
+	if reorg && block.ParentHash() != currentBlock.Hash() && !bc.reorgAllowed(currentBlock.Header(), block.Header()) {
+		reorg = false
+	}
 	if reorg {
 		// Reorganise the chain if the parent is not the head block
 		if block.ParentHash() != currentBlock.Hash() {
@@ -441,6 +441,140 @@ func testReorg(t *testing.T, first, second []int64, td int64, full bool) {
 	}
 }
 
+// Tests that reorgs deeper than the configured maximum are kept on the side
+// until they are explicitly approved.
+func TestReorgMaxDepth(t *testing.T) {
+	db, blockchain, err := newCanonical(ethash.NewFaker(), 0, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+	blockchain.SetMaxReorgDepth(3)
+
+	genesis := blockchain.CurrentBlock()
+	canon := makeBlockChain(genesis, 5, ethash.NewFaker(), db, canonicalSeed)
+	if _, err := blockchain.InsertChain(canon); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	fork := makeBlockChain(genesis, 6, ethash.NewFaker(), db, forkSeed)
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	head := fork[len(fork)-1]
+	if have, want := blockchain.CurrentBlock().Hash(), canon[len(canon)-1].Hash(); have != want {
+		t.Fatalf("head mismatch after deep fork: have %x, want %x", have, want)
+	}
+	pending := blockchain.PendingReorg()
+	if pending == nil {
+		t.Fatalf("deep reorg not recorded as pending")
+	}
+	if pending.Hash != head.Hash() || pending.Ancestor != 0 || pending.Depth != 5 {
+		t.Errorf("pending reorg mismatch: have %+v", pending)
+	}
+	// Reimporting the known fork must keep it on the side without failing, as
+	// import errors get the delivering peer dropped
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to reimport forked chain: %v", err)
+	}
+	if have, want := blockchain.CurrentBlock().Hash(), canon[len(canon)-1].Hash(); have != want {
+		t.Fatalf("head mismatch after reimported fork: have %x, want %x", have, want)
+	}
+	if err := blockchain.ApproveReorg(canon[2].Hash()); err == nil {
+		t.Errorf("approved reorg to a lighter block")
+	}
+	if err := blockchain.ApproveReorg(head.Hash()); err != nil {
+		t.Fatalf("failed to approve reorg: %v", err)
+	}
+	if have := blockchain.CurrentBlock().Hash(); have != head.Hash() {
+		t.Errorf("head mismatch after approval: have %x, want %x", have, head.Hash())
+	}
+	for _, block := range fork {
+		if hash := rawdb.ReadCanonicalHash(db, block.NumberU64()); hash != block.Hash() {
+			t.Errorf("canonical hash mismatch at #%d: have %x, want %x", block.NumberU64(), hash, block.Hash())
+		}
+	}
+	if pending := blockchain.PendingReorg(); pending != nil {
+		t.Errorf("pending reorg not cleared: %+v", pending)
+	}
+}
+
+// Tests that reorgs behind the federated checkpoint are refused, even if
+// approved by the operator.
+func TestReorgFederatedCheckpoint(t *testing.T) {
+	db, blockchain, err := newCanonical(ethash.NewFaker(), 0, true)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	genesis := blockchain.CurrentBlock()
+	canon := makeBlockChain(genesis, 5, ethash.NewFaker(), db, canonicalSeed)
+	if _, err := blockchain.InsertChain(canon); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	blockchain.SetFederatedCheckpoint(canon[2].NumberU64(), canon[2].Hash())
+
+	fork := makeBlockChain(genesis, 6, ethash.NewFaker(), db, forkSeed)
+	if _, err := blockchain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	if have, want := blockchain.CurrentBlock().Hash(), canon[len(canon)-1].Hash(); have != want {
+		t.Fatalf("head mismatch after fork: have %x, want %x", have, want)
+	}
+	if err := blockchain.ApproveReorg(fork[len(fork)-1].Hash()); err == nil {
+		t.Fatalf("approved reorg behind the federated checkpoint")
+	}
+	// Forks above the checkpoint are still followed
+	extended := makeBlockChain(canon[2], 4, ethash.NewFaker(), db, forkSeed)
+	if _, err := blockchain.InsertChain(extended); err != nil {
+		t.Fatalf("failed to insert fork above checkpoint: %v", err)
+	}
+	if have, want := blockchain.CurrentBlock().Hash(), extended[len(extended)-1].Hash(); have != want {
+		t.Errorf("head mismatch after fork above checkpoint: have %x, want %x", have, want)
+	}
+}
+
+// Tests that header chain reorgs deeper than the configured maximum are kept on
+// the side without failing the import.
+func TestReorgMaxDepthHeaders(t *testing.T) {
+	db, blockchain, err := newCanonical(ethash.NewFaker(), 0, false)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+	blockchain.SetMaxReorgDepth(3)
+
+	genesis := blockchain.CurrentHeader()
+	canon := makeHeaderChain(genesis, 5, ethash.NewFaker(), db, canonicalSeed)
+	if _, err := blockchain.InsertHeaderChain(canon, 1); err != nil {
+		t.Fatalf("failed to insert canonical headers: %v", err)
+	}
+	fork := makeHeaderChain(genesis, 6, ethash.NewFaker(), db, forkSeed)
+	if _, err := blockchain.InsertHeaderChain(fork, 1); err != nil {
+		t.Fatalf("failed to insert forked headers: %v", err)
+	}
+	if have, want := blockchain.CurrentHeader().Hash(), canon[len(canon)-1].Hash(); have != want {
+		t.Fatalf("head mismatch after deep fork: have %x, want %x", have, want)
+	}
+	for _, header := range canon {
+		if hash := rawdb.ReadCanonicalHash(db, header.Number.Uint64()); hash != header.Hash() {
+			t.Errorf("canonical hash mismatch at #%d: have %x, want %x", header.Number, hash, header.Hash())
+		}
+	}
+	pending := blockchain.PendingReorg()
+	if pending == nil || pending.Hash != fork[len(fork)-1].Hash() || pending.Depth != 5 {
+		t.Errorf("pending reorg mismatch: have %+v", pending)
+	}
+	// Shallow reorgs must still go through
+	shallow := makeHeaderChain(canon[2], 4, ethash.NewFaker(), db, forkSeed)
+	if _, err := blockchain.InsertHeaderChain(shallow, 1); err != nil {
+		t.Fatalf("failed to insert shallow fork: %v", err)
+	}
+	if have, want := blockchain.CurrentHeader().Hash(), shallow[len(shallow)-1].Hash(); have != want {
+		t.Errorf("head mismatch after shallow fork: have %x, want %x", have, want)
+	}
+}
+
 // Tests that the insertion functions detect banned hashes.
 func TestBadHeaderHashes(t *testing.T) { testBadHashes(t, false) }
 func TestBadBlockHashes(t *testing.T)  { testBadHashes(t, true) }
@@ -23,6 +23,7 @@ import (
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/consensus"
 	"github.com/420integrated/go-420coin/consensus/misc"
+	"github.com/420integrated/go-420coin/core/rawdb"
 	"github.com/420integrated/go-420coin/core/state"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/core/vm"
@@ -44,8 +45,9 @@ type BlockGen struct {
 	receipts []*types.Receipt
 	uncles   []*types.Header
 
-	config *params.ChainConfig
-	engine consensus.Engine
+	config      *params.ChainConfig
+	engine      consensus.Engine
+	chainreader *fakeChainReader
 }
 
 // SetCoinbase sets the coinbase of the generated block.
@@ -84,8 +86,8 @@ func (b *BlockGen) SetDifficulty(diff *big.Int) {
 // AddTx panics if the transaction cannot be executed. In addition to
 // the protocol-imposed limitations (smoke limit, etc.), there are some
 // further limitations on the content of transactions that can be
-// added. Notably, contract code relying on the BLOCKHASH instruction
-// will panic during execution.
+// added. Notably, the BLOCKHASH instruction will return the zero hash
+// during execution.
 func (b *BlockGen) AddTx(tx *types.Transaction) {
 	b.AddTxWithChain(nil, tx)
 }
@@ -102,8 +104,12 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 	if b.smokePool == nil {
 		b.SetCoinbase(common.Address{})
 	}
+	var chain ChainContext = b.chainreader
+	if bc != nil {
+		chain = bc
+	}
 	b.statedb.Prepare(tx.Hash(), common.Hash{}, len(b.txs))
-	receipt, err := ApplyTransaction(b.config, bc, &b.header.Coinbase, b.smokePool, b.statedb, b.header, tx, &b.header.SmokeUsed, vm.Config{})
+	receipt, err := ApplyTransaction(b.config, chain, &b.header.Coinbase, b.smokePool, b.statedb, b.header, tx, &b.header.SmokeUsed, vm.Config{})
 	if err != nil {
 		panic(err)
 	}
@@ -169,8 +175,7 @@ func (b *BlockGen) OffsetTime(seconds int64) {
 	if b.header.Time <= b.parent.Header().Time {
 		panic("block time out of range")
 	}
-	chainreader := &fakeChainReader{config: b.config}
-	b.header.Difficulty = b.engine.CalcDifficulty(chainreader, b.header.Time, b.parent.Header())
+	b.header.Difficulty = b.engine.CalcDifficulty(b.chainreader, b.header.Time, b.parent.Header())
 }
 
 // GenerateChain creates a chain of n blocks. The first block's
@@ -190,9 +195,9 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		config = params.TestChainConfig
 	}
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
-	chainreader := &fakeChainReader{config: config}
+	chainreader := &fakeChainReader{config: config, engine: engine, genesis: generationGenesis(parent, db)}
 	genblock := func(i int, parent *types.Block, statedb *state.StateDB) (*types.Block, types.Receipts) {
-		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine}
+		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine, chainreader: chainreader}
 		b.header = makeHeader(chainreader, parent, statedb, b.engine)
 
 		// Mutate the state and block according to any hard-fork specs
@@ -248,7 +253,7 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		time = parent.Time() + 10 // block time is fixed at 10 seconds
 	}
 
-	return &types.Header{
+	header := &types.Header{
 		Root:       state.IntermediateRoot(chain.Config().IsEIP158(parent.Number())),
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
@@ -262,6 +267,13 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		Number:   new(big.Int).Add(parent.Number(), common.Big1),
 		Time:     time,
 	}
+	if chain.Config().IsSmokeBaseFee(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(chain.Config(), parent.Header())
+		if !chain.Config().IsSmokeBaseFee(parent.Number()) {
+			header.SmokeLimit = parent.SmokeLimit() * params.ElasticityMultiplier
+		}
+	}
+	return header
 }
 
 // makeHeaderChain creates a deterministic chain of headers rooted at parent.
@@ -282,8 +294,21 @@ func makeBlockChain(parent *types.Block, n int, engine consensus.Engine, db four
 	return blocks
 }
 
+// generationGenesis returns the genesis header of the chain being extended from
+// parent, or nil if it's not in db.
+func generationGenesis(parent *types.Block, db fourtwentydb.Database) *types.Header {
+	if parent.NumberU64() == 0 {
+		return parent.Header()
+	}
+	return rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, 0), 0)
+}
+
+// fakeChainReader serves the chain config, consensus engine and genesis header
+// to the generated blocks, knowing none of the other headers.
 type fakeChainReader struct {
-	config *params.ChainConfig
+	config  *params.ChainConfig
+	engine  consensus.Engine
+	genesis *types.Header
 }
 
 // Config returns the chain configuration.
@@ -291,8 +316,20 @@ func (cr *fakeChainReader) Config() *params.ChainConfig {
 	return cr.config
 }
 
+// Engine returns the consensus engine of the generated blocks.
+func (cr *fakeChainReader) Engine() consensus.Engine {
+	return cr.engine
+}
+
+// GetHeaderByNumber returns the genesis header, if known.
+func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number == 0 {
+		return cr.genesis
+	}
+	return nil
+}
+
 func (cr *fakeChainReader) CurrentHeader() *types.Header                            { return nil }
-func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header           { return nil }
 func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
 func (cr *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
 func (cr *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
@@ -19,7 +19,9 @@ package core
 import (
 	"fmt"
 	"math/big"
+	"testing"
 
+	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core/rawdb"
 	"github.com/420integrated/go-420coin/core/types"
@@ -98,3 +100,57 @@ func ExampleGenerateChain() {
 	// balance of addr2: 10000
 	// balance of addr3: 19687500000000001000
 }
+
+// Tests that transactions added without a chain still route the share of their
+// fees enabled by the chain config to the Veterans Fund, producing blocks the
+// chain accepts.
+func TestGenerateChainFeeRouting(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		creator  = common.Address{0xcc}
+		veterans = common.Address{0xaa}
+		coinbase = common.Address{0x01}
+		db       = rawdb.NewMemoryDatabase()
+		config   = *params.TestChainConfig
+	)
+	config.VeteransFeeBlock = big.NewInt(1)
+	config.VeteransFeePercent = 10
+
+	gspec := &Genesis{
+		Config:    &config,
+		ExtraData: creator.Bytes(),
+		Alloc: GenesisAlloc{
+			addr: {Balance: big.NewInt(1000000000)},
+			crypto.CreateAddress(creator, 0): {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					common.BytesToHash([]byte{1}): veterans.Hash(),
+				},
+			},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbase)
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), params.TxSmoke, big.NewInt(10), nil), types.HomesteadSigner{}, key)
+		b.AddTx(tx)
+	})
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, _ := blockchain.State()
+
+	var (
+		fee       = big.NewInt(int64(params.TxSmoke) * 10)
+		share     = new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(10)), big.NewInt(100))
+		shares, _ = ethash.BlockRewards(blockchain.Config(), blocks[0].Number(), nil)
+	)
+	if have, want := statedb.GetBalance(veterans), new(big.Int).Add(shares.Veterans, share); have.Cmp(want) != 0 {
+		t.Errorf("veterans balance mismatch: have %v, want %v", have, want)
+	}
+}
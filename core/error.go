@@ -57,6 +57,18 @@ var (
 	// is higher than the balance of the user's account.
 	ErrInsufficientFunds = errors.New("insufficient funds for smoke * price + value")
 
+	// ErrFeeCapTooLow is returned if the smoke price of a transaction, its fee cap
+	// for dynamic fee transactions, is below the base fee of the block.
+	ErrFeeCapTooLow = errors.New("fee cap less than block base fee")
+
+	// ErrTipAboveFeeCap is returned if a dynamic fee transaction is willing to
+	// tip the miner more than its total fee cap.
+	ErrTipAboveFeeCap = errors.New("tip higher than fee cap")
+
+	// ErrDynamicFeeNotSupported is returned if a dynamic fee transaction is used
+	// before the smoke base fee fork.
+	ErrDynamicFeeNotSupported = errors.New("dynamic fee transactions not supported")
+
 	// ErrSmokeUintOverflow is returned when calculating smoke usage.
 	ErrSmokeUintOverflow = errors.New("smoke uint64 overflow")
 
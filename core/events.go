@@ -24,6 +24,15 @@ import (
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
 type NewTxsEvent struct{ Txs []*types.Transaction }
 
+// DroppedTxEvent is posted when a transaction leaves the transaction pool without
+// being included in a block, either replaced by a higher priced transaction with
+// the same sender and nonce, or dropped outright.
+type DroppedTxEvent struct {
+	Tx          *types.Transaction
+	Replacement *types.Transaction // Transaction superseding Tx, nil if dropped
+	Reason      string             // One of the TxDrop* reasons
+}
+
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
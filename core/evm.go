@@ -17,6 +17,7 @@
 package core
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/420integrated/go-420coin/common"
@@ -44,6 +45,10 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 	} else {
 		beneficiary = *author
 	}
+	var baseFee *big.Int
+	if header.BaseFee != nil {
+		baseFee = new(big.Int).Set(header.BaseFee)
+	}
 	return vm.BlockContext{
 		CanTransfer: CanTransfer,
 		Transfer:    Transfer,
@@ -53,7 +58,31 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		Time:        new(big.Int).SetUint64(header.Time),
 		Difficulty:  new(big.Int).Set(header.Difficulty),
 		SmokeLimit:    header.SmokeLimit,
-			}
+		BaseFee:     baseFee,
+		FeeFund:     feeFundFn(header, chain),
+	}
+}
+
+// feeFundFn returns a function resolving the account the routed share of the
+// transaction fees of a block is paid to, or nil if there's no chain or its
+// consensus engine doesn't route fees, in which case the coinbase keeps them.
+func feeFundFn(header *types.Header, chain ChainContext) func(vm.StateDB) common.Address {
+	if chain == nil {
+		return nil
+	}
+	router, ok := chain.Engine().(consensus.FeeRouter)
+	if !ok {
+		return nil
+	}
+	// The fee fund is resolved from the chain's genesis, paying it to anyone else
+	// would produce a state the rest of the network rejects
+	reader, ok := chain.(consensus.ChainHeaderReader)
+	if !ok {
+		panic(fmt.Sprintf("fee routing engine used with chain context %T not serving headers", chain))
+	}
+	return func(db vm.StateDB) common.Address {
+		return router.FeeFund(reader, header, db)
+	}
 }
 
 // NewEVMTxContext creates a new transaction context for a single transaction.
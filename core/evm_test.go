@@ -0,0 +1,57 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core/types"
+)
+
+// engineChain is a chain context serving only its consensus engine.
+type engineChain struct {
+	engine consensus.Engine
+}
+
+func (c *engineChain) Engine() consensus.Engine                                { return c.engine }
+func (c *engineChain) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+
+// Tests that the fee fund is only resolved for chains whose engine routes fees,
+// and that a chain unable to serve the headers it's resolved from is rejected.
+func TestFeeFundFn(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1)}
+
+	if fn := feeFundFn(header, nil); fn != nil {
+		t.Errorf("fee fund resolved without a chain")
+	}
+	if fn := feeFundFn(header, &engineChain{}); fn != nil {
+		t.Errorf("fee fund resolved without a fee routing engine")
+	}
+	if fn := feeFundFn(header, &fakeChainReader{engine: ethash.NewFaker()}); fn == nil {
+		t.Errorf("fee fund not resolved for a fee routing engine")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("chain not serving headers accepted by a fee routing engine")
+		}
+	}()
+	feeFundFn(header, &engineChain{engine: ethash.NewFaker()})
+}
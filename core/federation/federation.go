@@ -0,0 +1,207 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package federation implements federated checkpointing, where a configured set
+// of signers vote on the canonical block hash at fixed block intervals.
+package federation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+var (
+	// errNotCheckpoint is returned if a vote is for a block number which is not
+	// a multiple of the checkpoint interval.
+	errNotCheckpoint = errors.New("block is not a checkpoint")
+
+	// errInvalidSignature is returned if the signer of a vote can't be recovered.
+	errInvalidSignature = errors.New("invalid vote signature")
+
+	// errUnauthorizedSigner is returned if a vote is signed by an address which
+	// is not part of the federation.
+	errUnauthorizedSigner = errors.New("unauthorized signer")
+)
+
+// votePrefix domain separates checkpoint votes from other signed data.
+var votePrefix = []byte("420coin federated checkpoint")
+
+// SigningData returns the data a federation signer signs to vote for the block
+// with the given number and hash.
+func SigningData(number uint64, hash common.Hash) []byte {
+	data := make([]byte, len(votePrefix)+8+common.HashLength)
+	copy(data, votePrefix)
+	binary.BigEndian.PutUint64(data[len(votePrefix):], number)
+	copy(data[len(votePrefix)+8:], hash[:])
+	return data
+}
+
+// Vote is a single federation signer's signature over a checkpoint block.
+type Vote struct {
+	Number    uint64      // Number of the checkpoint block
+	Hash      common.Hash // Hash of the checkpoint block
+	Signature []byte      // Signature over the Keccak256 hash of SigningData
+}
+
+// ID returns a unique identifier of the vote, used to avoid relaying it twice.
+func (v *Vote) ID() common.Hash {
+	return crypto.Keccak256Hash(SigningData(v.Number, v.Hash), v.Signature)
+}
+
+// Signer recovers the address which signed the vote.
+func (v *Vote) Signer() (common.Address, error) {
+	if len(v.Signature) != crypto.SignatureLength {
+		return common.Address{}, errInvalidSignature
+	}
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(SigningData(v.Number, v.Hash)), v.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", errInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// Checkpoint is a block which collected votes from enough federation signers.
+type Checkpoint struct {
+	Number  uint64           `json:"number"`
+	Hash    common.Hash      `json:"hash"`
+	Signers []common.Address `json:"signers"`
+
+	votes []*Vote // Votes finalizing the checkpoint, to relay to new peers
+}
+
+// Votes returns the votes which finalized the checkpoint.
+func (c *Checkpoint) Votes() []*Vote {
+	return c.votes
+}
+
+// Tracker collects checkpoint votes from the federation signers and finalizes
+// checkpoints once enough of them voted for the same block.
+type Tracker struct {
+	config  *params.FederationConfig
+	signers map[common.Address]struct{}
+
+	votes  map[uint64]map[common.Address]*Vote // Pending votes by block number and signer
+	latest *Checkpoint                         // Latest finalized checkpoint
+	lock   sync.Mutex
+}
+
+// NewTracker creates a vote tracker for the given federation.
+func NewTracker(config *params.FederationConfig) (*Tracker, error) {
+	if config.Interval == 0 {
+		return nil, errors.New("federation checkpoint interval is zero")
+	}
+	if config.Threshold == 0 || config.Threshold > uint64(len(config.Signers)) {
+		return nil, fmt.Errorf("invalid federation threshold %d for %d signers", config.Threshold, len(config.Signers))
+	}
+	signers := make(map[common.Address]struct{}, len(config.Signers))
+	for _, signer := range config.Signers {
+		signers[signer] = struct{}{}
+	}
+	return &Tracker{
+		config:  config,
+		signers: signers,
+		votes:   make(map[uint64]map[common.Address]*Vote),
+	}, nil
+}
+
+// IsCheckpoint reports whether the block with the given number is voted on.
+func (t *Tracker) IsCheckpoint(number uint64) bool {
+	return number > 0 && number%t.config.Interval == 0
+}
+
+// Interval returns the number of blocks between checkpoints.
+func (t *Tracker) Interval() uint64 {
+	return t.config.Interval
+}
+
+// IsSigner reports whether the given address is part of the federation.
+func (t *Tracker) IsSigner(addr common.Address) bool {
+	_, ok := t.signers[addr]
+	return ok
+}
+
+// Latest returns the latest finalized checkpoint, or nil if there is none.
+func (t *Tracker) Latest() *Checkpoint {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.latest
+}
+
+// Add validates and records a vote. It returns whether the vote was new, so
+// that it should be relayed, and the checkpoint it finalized, if any. Votes at
+// or below the latest checkpoint are ignored, as are repeated votes of the same
+// signer for a block number.
+func (t *Tracker) Add(vote *Vote) (bool, *Checkpoint, error) {
+	if !t.IsCheckpoint(vote.Number) {
+		return false, nil, errNotCheckpoint
+	}
+	signer, err := vote.Signer()
+	if err != nil {
+		return false, nil, err
+	}
+	if !t.IsSigner(signer) {
+		return false, nil, fmt.Errorf("%w: %x", errUnauthorizedSigner, signer)
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.latest != nil && vote.Number <= t.latest.Number {
+		return false, nil, nil
+	}
+	votes := t.votes[vote.Number]
+	if votes == nil {
+		votes = make(map[common.Address]*Vote)
+		t.votes[vote.Number] = votes
+	}
+	if _, ok := votes[signer]; ok {
+		return false, nil, nil
+	}
+	votes[signer] = vote
+
+	// Count the votes for the same block and finalize it if enough
+	var (
+		signers []common.Address
+		final   []*Vote
+	)
+	for addr, v := range votes {
+		if v.Hash == vote.Hash {
+			signers = append(signers, addr)
+			final = append(final, v)
+		}
+	}
+	if uint64(len(signers)) < t.config.Threshold {
+		return true, nil, nil
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i][:], signers[j][:]) < 0
+	})
+	t.latest = &Checkpoint{Number: vote.Number, Hash: vote.Hash, Signers: signers, votes: final}
+	for number := range t.votes {
+		if number <= vote.Number {
+			delete(t.votes, number)
+		}
+	}
+	return true, t.latest, nil
+}
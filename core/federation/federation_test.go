@@ -0,0 +1,89 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package federation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+func signVote(t *testing.T, key *ecdsa.PrivateKey, number uint64, hash common.Hash) *Vote {
+	sig, err := crypto.Sign(crypto.Keccak256(SigningData(number, hash)), key)
+	if err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	return &Vote{Number: number, Hash: hash, Signature: sig}
+}
+
+// Tests that checkpoints are finalized once the threshold of signers voted for
+// the same block, and that invalid votes are rejected.
+func TestTracker(t *testing.T) {
+	var (
+		keys    = make([]*ecdsa.PrivateKey, 3)
+		signers = make([]common.Address, 3)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		signers[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	tracker, err := NewTracker(&params.FederationConfig{Signers: signers, Interval: 100, Threshold: 2})
+	if err != nil {
+		t.Fatalf("failed to create tracker: %v", err)
+	}
+	var (
+		good = common.HexToHash("0x01")
+		bad  = common.HexToHash("0x02")
+	)
+	// Votes for non-checkpoint blocks and from outsiders are rejected
+	if _, _, err := tracker.Add(signVote(t, keys[0], 150, good)); !errors.Is(err, errNotCheckpoint) {
+		t.Errorf("non-checkpoint vote error mismatch: have %v, want %v", err, errNotCheckpoint)
+	}
+	outsider, _ := crypto.GenerateKey()
+	if _, _, err := tracker.Add(signVote(t, outsider, 100, good)); !errors.Is(err, errUnauthorizedSigner) {
+		t.Errorf("outsider vote error mismatch: have %v, want %v", err, errUnauthorizedSigner)
+	}
+	// Split votes don't finalize, repeated ones are ignored
+	if added, cp, err := tracker.Add(signVote(t, keys[0], 100, good)); !added || cp != nil || err != nil {
+		t.Fatalf("first vote: added %v, checkpoint %v, err %v", added, cp, err)
+	}
+	if added, _, _ := tracker.Add(signVote(t, keys[0], 100, good)); added {
+		t.Errorf("repeated vote accepted")
+	}
+	if added, cp, err := tracker.Add(signVote(t, keys[1], 100, bad)); !added || cp != nil || err != nil {
+		t.Fatalf("split vote: added %v, checkpoint %v, err %v", added, cp, err)
+	}
+	// The threshold vote finalizes the checkpoint
+	added, cp, err := tracker.Add(signVote(t, keys[2], 100, good))
+	if !added || err != nil {
+		t.Fatalf("final vote: added %v, err %v", added, err)
+	}
+	if cp == nil || cp.Number != 100 || cp.Hash != good || len(cp.Signers) != 2 || len(cp.Votes()) != 2 {
+		t.Fatalf("checkpoint mismatch: have %+v", cp)
+	}
+	if latest := tracker.Latest(); latest != cp {
+		t.Errorf("latest checkpoint mismatch: have %+v, want %+v", latest, cp)
+	}
+	// Votes at or below the checkpoint are ignored
+	if added, _, err := tracker.Add(signVote(t, keys[1], 100, good)); added || err != nil {
+		t.Errorf("stale vote: added %v, err %v", added, err)
+	}
+}
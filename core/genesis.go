@@ -153,6 +153,15 @@ func (e *GenesisMismatchError) Error() string {
 //
 // The returned chain configuration is never nil.
 func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.ChainConfig, common.Hash, error) {
+	return SetupGenesisBlockWithOverride(db, genesis, nil)
+}
+
+// SetupGenesisBlockWithOverride is like SetupGenesisBlock, but moves the named
+// ethash reward eras of the chain configuration to start at the given blocks.
+// The overrides also apply to the stored configuration of private networks if
+// no genesis is given, and are subject to the same compatibility checks as any
+// other configuration change.
+func SetupGenesisBlockWithOverride(db fourtwentydb.Database, genesis *Genesis, overrideEras map[string]*big.Int) (*params.ChainConfig, common.Hash, error) {
 	if genesis != nil && genesis.Config == nil {
 		return params.AllEthashProtocolChanges, common.Hash{}, errGenesisNoConfig
 	}
@@ -165,6 +174,14 @@ func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.Chai
 		} else {
 			log.Info("Writing custom genesis block")
 		}
+		overridden, err := genesis.withRewardEraBlocks(overrideEras)
+		if err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		genesis = overridden
+		if err := genesis.Config.CheckConfigForkOrder(); err != nil {
+			return genesis.Config, common.Hash{}, fmt.Errorf("invalid genesis chain config: %w", err)
+		}
 		block, err := genesis.Commit(db)
 		if err != nil {
 			return genesis.Config, common.Hash{}, err
@@ -184,6 +201,11 @@ func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.Chai
 		if hash != stored {
 			return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 		}
+		overridden, err := genesis.withRewardEraBlocks(overrideEras)
+		if err != nil {
+			return genesis.Config, hash, err
+		}
+		genesis = overridden
 		block, err := genesis.Commit(db)
 		if err != nil {
 			return genesis.Config, hash, err
@@ -200,7 +222,10 @@ func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.Chai
 	}
 
 	// Get the existing chain configuration.
-	newcfg := genesis.configOrDefault(stored)
+	newcfg, err := genesis.configOrDefault(stored).WithRewardEraBlocks(overrideEras)
+	if err != nil {
+		return genesis.configOrDefault(stored), stored, err
+	}
 	if err := newcfg.CheckConfigForkOrder(); err != nil {
 		return newcfg, common.Hash{}, err
 	}
@@ -213,8 +238,17 @@ func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.Chai
 	// Special case: don't change the existing config of a non-mainnet chain if no new
 	// config is supplied. These chains would get AllProtocolChanges (and a compat error)
 	// if we just continued here.
+	// Reward era overrides are applied to the stored config instead.
 	if genesis == nil && stored != params.MainnetGenesisHash {
-		return storedcfg, stored, nil
+		if len(overrideEras) == 0 {
+			return storedcfg, stored, nil
+		}
+		if newcfg, err = storedcfg.WithRewardEraBlocks(overrideEras); err != nil {
+			return storedcfg, stored, err
+		}
+		if err := newcfg.CheckConfigForkOrder(); err != nil {
+			return newcfg, stored, err
+		}
 	}
 
 	// Check config compatibility and write the config. Compatibility errors
@@ -231,6 +265,21 @@ func SetupGenesisBlock(db fourtwentydb.Database, genesis *Genesis) (*params.Chai
 	return newcfg, stored, nil
 }
 
+// withRewardEraBlocks returns a copy of the genesis with the named reward eras of
+// its chain configuration moved to start at the given blocks.
+func (g *Genesis) withRewardEraBlocks(blocks map[string]*big.Int) (*Genesis, error) {
+	if len(blocks) == 0 {
+		return g, nil
+	}
+	config, err := g.Config.WithRewardEraBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+	cpy := *g
+	cpy.Config = config
+	return &cpy, nil
+}
+
 func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
 	switch {
 	case g != nil:
@@ -281,6 +330,9 @@ func (g *Genesis) ToBlock(db fourtwentydb.Database) *types.Block {
 	if g.Difficulty == nil {
 		head.Difficulty = params.GenesisDifficulty
 	}
+	if g.Config != nil && g.Config.IsSmokeBaseFee(common.Big0) {
+		head.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
+	}
 	statedb.Commit(false)
 	statedb.Database().TrieDB().Commit(root, true, nil)
 
@@ -0,0 +1,215 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// DecodeGenesis strictly decodes a genesis specification from JSON and validates
+// its chain configuration. Contrary to plain JSON decoding, unknown fields are
+// rejected, since a mistyped fork field would otherwise be silently ignored and
+// produce a node diverging from the rest of the network.
+func DecodeGenesis(data []byte) (*Genesis, error) {
+	if err := checkGenesisFields(data); err != nil {
+		return nil, err
+	}
+	genesis := new(Genesis)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis JSON: %v", err)
+	}
+	if err := genesis.validate(); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// validate checks the sanity of the chain configuration of the genesis.
+func (g *Genesis) validate() error {
+	if g.Config == nil {
+		return fmt.Errorf("%w: add a \"config\" object with the chainId and fork blocks", errGenesisNoConfig)
+	}
+	if g.Config.ChainID == nil || g.Config.ChainID.Sign() <= 0 {
+		return fmt.Errorf("invalid genesis config: \"chainId\" must be a positive number, used for replay protection")
+	}
+	// Custom networks reusing the chain id of a public one are open to replays
+	for _, network := range []struct {
+		name    string
+		config  *params.ChainConfig
+		genesis common.Hash
+	}{
+		{"main network", params.MainnetChainConfig, params.MainnetGenesisHash},
+		{"ruderalis test network", params.RuderalisChainConfig, params.RuderalisGenesisHash},
+	} {
+		if g.Config.ChainID.Cmp(network.config.ChainID) == 0 && g.ToBlock(nil).Hash() != network.genesis {
+			return fmt.Errorf("invalid genesis config: \"chainId\" %v is used by the %s, pick a different one for a custom network", g.Config.ChainID, network.name)
+		}
+	}
+	if err := g.Config.CheckConfigForkOrder(); err != nil {
+		return fmt.Errorf("invalid genesis config: %v", err)
+	}
+	return nil
+}
+
+// checkGenesisFields rejects any JSON field of a genesis specification which
+// doesn't match a known one.
+func checkGenesisFields(data []byte) error {
+	var genesis map[string]json.RawMessage
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return fmt.Errorf("invalid genesis JSON: %v", err)
+	}
+	if err := checkFields(genesis, reflect.TypeOf(Genesis{}), "genesis"); err != nil {
+		return err
+	}
+	// Check the chain configuration and its engine specific sections
+	if raw, ok := lookupField(genesis, "config"); ok {
+		config := decodeObject(raw)
+		if err := checkFields(config, reflect.TypeOf(params.ChainConfig{}), "genesis config"); err != nil {
+			return err
+		}
+		for name, typ := range map[string]reflect.Type{
+			"ethash":     reflect.TypeOf(params.EthashConfig{}),
+			"clique":     reflect.TypeOf(params.CliqueConfig{}),
+			"federation": reflect.TypeOf(params.FederationConfig{}),
+		} {
+			raw, ok := lookupField(config, name)
+			if !ok {
+				continue
+			}
+			if err := checkFields(decodeObject(raw), typ, "genesis config."+name); err != nil {
+				return err
+			}
+		}
+	}
+	// Check every pre-funded account
+	if raw, ok := lookupField(genesis, "alloc"); ok {
+		var alloc map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &alloc); err != nil {
+			return nil // Leave type errors to the decoder
+		}
+		for addr, raw := range alloc {
+			if err := checkFields(decodeObject(raw), reflect.TypeOf(GenesisAccount{}), "genesis alloc."+addr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeObject decodes a JSON object into its raw fields. Values which aren't
+// objects yield no fields, leaving the decoder to report them.
+func decodeObject(raw json.RawMessage) map[string]json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// lookupField retrieves a JSON field, matching its name case-insensitively like
+// the decoder does.
+func lookupField(fields map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	for key, raw := range fields {
+		if strings.EqualFold(key, name) {
+			return raw, true
+		}
+	}
+	return nil, false
+}
+
+// checkFields rejects the first JSON field, in alphabetical order, which doesn't
+// match any field of the given struct type, suggesting the closest known one.
+func checkFields(fields map[string]json.RawMessage, typ reflect.Type, path string) error {
+	var known []string
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = typ.Field(i).Name
+		}
+		if name != "-" {
+			known = append(known, name)
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var (
+			found    bool
+			best     string
+			bestDist = len(key)/2 + 1
+		)
+		for _, name := range known {
+			if strings.EqualFold(key, name) {
+				found = true
+				break
+			}
+			if dist := editDistance(strings.ToLower(key), strings.ToLower(name)); dist < bestDist {
+				best, bestDist = name, dist
+			}
+		}
+		if found {
+			continue
+		}
+		if best != "" {
+			return fmt.Errorf("unknown field %q in %s, did you mean %q?", key, path, best)
+		}
+		return fmt.Errorf("unknown field %q in %s, known fields are %s", key, path, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
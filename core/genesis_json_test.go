@@ -0,0 +1,74 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// Tests that genesis specifications are strictly decoded, with errors pointing
+// at the offending field.
+func TestDecodeGenesis(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr string
+	}{
+		{
+			json: `{"config": {"chainId": 4242, "homesteadBlock": 0, "eip150Block": 5}, "difficulty": "0x1", "smokeLimit": "0x1000000", "alloc": {}}`,
+		},
+		{
+			json:    `{"config": {"chainId": 4242, "homesteadBlok": 0}}`,
+			wantErr: `unknown field "homesteadBlok" in genesis config, did you mean "homesteadBlock"?`,
+		},
+		{
+			json:    `{"config": {"chainId": 4242}, "alloc": {"0x0000000000000000000000000000000000000001": {"balanse": "0x1"}}}`,
+			wantErr: `did you mean "balance"?`,
+		},
+		{
+			json:    `{"config": {"chainId": 4242, "ethash": {"foo": 1}}}`,
+			wantErr: `unknown field "foo" in genesis config.ethash`,
+		},
+		{
+			json:    `{"difficulty": "0x1", "alloc": {}}`,
+			wantErr: errGenesisNoConfig.Error(),
+		},
+		{
+			json:    `{"config": {"homesteadBlock": 0}, "difficulty": "0x1", "alloc": {}}`,
+			wantErr: `"chainId" must be a positive number`,
+		},
+		{
+			json:    `{"config": {"chainId": 2020}, "difficulty": "0x1", "alloc": {}}`,
+			wantErr: `"chainId" 2020 is used by the main network`,
+		},
+		{
+			json:    `{"config": {"chainId": 4242, "homesteadBlock": 10, "eip150Block": 5}, "difficulty": "0x1", "alloc": {}}`,
+			wantErr: `invalid genesis config: unsupported fork ordering`,
+		},
+	}
+	for i, test := range tests {
+		genesis, err := DecodeGenesis([]byte(test.json))
+		switch {
+		case test.wantErr == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case test.wantErr == "" && genesis.Config.ChainID.Uint64() != 4242:
+			t.Errorf("test %d: chain id mismatch: have %v, want 4242", i, genesis.Config.ChainID)
+		case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, test.wantErr)
+		}
+	}
+}
@@ -162,3 +162,50 @@ func TestSetupGenesis(t *testing.T) {
 		}
 	}
 }
+
+// Tests that reward era overrides are applied on top of the stored chain config
+// of private networks, without touching the config they were derived from, and
+// that moving an already active era is rejected.
+func TestSetupGenesisOverride(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		genesis   = &Genesis{Config: params.TestChainConfig}
+		overrides = map[string]*big.Int{"indica": big.NewInt(2), "sativa": big.NewInt(3)}
+	)
+	block := genesis.MustCommit(db)
+
+	config, _, err := SetupGenesisBlockWithOverride(db, nil, overrides)
+	if err != nil {
+		t.Fatalf("failed to override reward eras: %v", err)
+	}
+	for number, want := range map[int64]string{1: "ruderalis", 2: "indica", 3: "sativa"} {
+		if have := config.RewardEraAt(big.NewInt(number)).Name; have != want {
+			t.Errorf("block %d: era mismatch: have %s, want %s", number, have, want)
+		}
+	}
+	if have := rawdb.ReadChainConfig(db, block.Hash()).RewardEraAt(big.NewInt(3)).Name; have != "sativa" {
+		t.Errorf("stored config era mismatch: have %s, want sativa", have)
+	}
+	if have := params.TestChainConfig.RewardEraAt(big.NewInt(3)).Name; have != "ruderalis" {
+		t.Errorf("original config modified: era %s at block 3", have)
+	}
+	if _, _, err := SetupGenesisBlockWithOverride(db, nil, map[string]*big.Int{"hybrid": big.NewInt(1)}); err == nil {
+		t.Errorf("unknown era override accepted")
+	}
+	// Advance a fresh chain past the overridden eras and ensure they can't be moved
+	db = rawdb.NewMemoryDatabase()
+	block = genesis.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, genesis.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	blocks, _ := GenerateChain(genesis.Config, block, ethash.NewFaker(), db, 4, nil)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if _, _, err := SetupGenesisBlockWithOverride(db, nil, overrides); err == nil {
+		t.Fatalf("active era moved without a compatibility error")
+	} else if _, ok := err.(*params.ConfigCompatError); !ok {
+		t.Fatalf("error type mismatch: have %T, want *params.ConfigCompatError", err)
+	}
+}
@@ -27,13 +27,13 @@ import (
 	"time"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/consensus"
 	"github.com/420integrated/go-420coin/core/rawdb"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/420db"
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/params"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -64,11 +64,12 @@ type HeaderChain struct {
 	currentHeader     atomic.Value // Current head of the header chain (may be above the block chain!)
 	currentHeaderHash common.Hash  // Hash of the current head of the header chain (prevent recomputing all the time)
 
-	headerCache *lru.Cache // Cache for the most recent block headers
-	tdCache     *lru.Cache // Cache for the most recent block total difficulties
-	numberCache *lru.Cache // Cache for the most recent block numbers
+	headerCache *cache.Cache // Cache for the most recent block headers
+	tdCache     *cache.Cache // Cache for the most recent block total difficulties
+	numberCache *cache.Cache // Cache for the most recent block numbers
 
 	procInterrupt func() bool
+	reorgAllowed  func(current, head *types.Header) bool // Optional check of the encapsulating chain refusing reorgs
 
 	rand   *mrand.Rand
 	engine consensus.Engine
@@ -77,9 +78,9 @@ type HeaderChain struct {
 // NewHeaderChain creates a new HeaderChain structure. ProcInterrupt points
 // to the parent's interrupt semaphore.
 func NewHeaderChain(chainDb fourtwentydb.Database, config *params.ChainConfig, engine consensus.Engine, procInterrupt func() bool) (*HeaderChain, error) {
-	headerCache, _ := lru.New(headerCacheLimit)
-	tdCache, _ := lru.New(tdCacheLimit)
-	numberCache, _ := lru.New(numberCacheLimit)
+	headerCache := cache.New("chain/cache/headers", headerCacheLimit, 0)
+	tdCache := cache.New("chain/cache/tds", tdCacheLimit, 0)
+	numberCache := cache.New("chain/cache/numbers", numberCacheLimit, 0)
 
 	// Seed a fast but crypto originating random generator
 	seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
@@ -186,7 +187,7 @@ func (hc *HeaderChain) writeHeaders(headers []*types.Header) (result *headerWrit
 
 			rawdb.WriteHeader(batch, header)
 			inserted = append(inserted, numberHash{number, hash})
-			hc.headerCache.Add(hash, header)
+			hc.headerCache.AddSized(hash, header, int(header.Size()))
 			hc.numberCache.Add(hash, number)
 			if firstInserted < 0 {
 				firstInserted = i
@@ -226,6 +227,11 @@ func (hc *HeaderChain) writeHeaders(headers []*types.Header) (result *headerWrit
 	// we don't have to go backwards to delete canon blocks, but
 	// simply pile them onto the existing chain
 	chainAlreadyCanon := headers[0].ParentHash == hc.currentHeaderHash
+
+	// Keep the headers on the side if the encapsulating chain refuses the reorg
+	if reorg && !chainAlreadyCanon && hc.reorgAllowed != nil && !hc.reorgAllowed(hc.CurrentHeader(), lastHeader) {
+		reorg = false
+	}
 	if reorg {
 		// If the header can be added into canonical chain, adjust the
 		// header chain markers(canonical indexes and head header flag).
@@ -490,7 +496,7 @@ func (hc *HeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header
 		return nil
 	}
 	// Cache the found header for next time and return
-	hc.headerCache.Add(hash, header)
+	hc.headerCache.AddSized(hash, header, int(header.Size()))
 	return header
 }
 
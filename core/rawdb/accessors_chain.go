@@ -368,7 +368,7 @@ func ReadBodyRLP(db fourtwentydb.Reader, hash common.Hash, number uint64) rlp.Ra
 		}
 	}
 	// Then try to look up the data in leveldb.
-	data, _ = db.Get(blockBodyKey(number, hash))
+	data = readValue(db, blockBodyKey(number, hash))
 	if len(data) > 0 {
 		return data
 	}
@@ -393,7 +393,7 @@ func ReadCanonicalBodyRLP(db fourtwentydb.Reader, number uint64) rlp.RawValue {
 	data, _ := db.Ancient(freezerBodiesTable, number)
 	if len(data) == 0 {
 		// Need to get the hash
-		data, _ = db.Get(blockBodyKey(number, ReadCanonicalHash(db, number)))
+		data = readValue(db, blockBodyKey(number, ReadCanonicalHash(db, number)))
 		// In the background freezer is moving data from leveldb to flatten files.
 		// So during the first check for ancient db, the data is not yet in there,
 		// but when we reach into leveldb, the data was already moved. That would
@@ -407,7 +407,7 @@ func ReadCanonicalBodyRLP(db fourtwentydb.Reader, number uint64) rlp.RawValue {
 
 // WriteBodyRLP stores an RLP encoded block body into the database.
 func WriteBodyRLP(db fourtwentydb.KeyValueWriter, hash common.Hash, number uint64, rlp rlp.RawValue) {
-	if err := db.Put(blockBodyKey(number, hash), rlp); err != nil {
+	if err := db.Put(blockBodyKey(number, hash), encodeValue(rlp)); err != nil {
 		log.Crit("Failed to store block body", "err", err)
 	}
 }
@@ -541,7 +541,7 @@ func ReadReceiptsRLP(db fourtwentydb.Reader, hash common.Hash, number uint64) rl
 		}
 	}
 	// Then try to look up the data in leveldb.
-	data, _ = db.Get(blockReceiptsKey(number, hash))
+	data = readValue(db, blockReceiptsKey(number, hash))
 	if len(data) > 0 {
 		return data
 	}
@@ -599,10 +599,21 @@ func ReadReceipts(db fourtwentydb.Reader, hash common.Hash, number uint64, confi
 		log.Error("Missing body but have receipt", "hash", hash, "number", number)
 		return nil
 	}
-	if err := receipts.DeriveFields(config, hash, number, body.Transactions); err != nil {
+	var baseFee *big.Int
+	if header := ReadHeader(db, hash, number); header != nil {
+		baseFee = header.BaseFee
+	}
+	if err := receipts.DeriveFields(config, hash, number, baseFee, body.Transactions); err != nil {
 		log.Error("Failed to derive block receipts fields", "hash", hash, "number", number, "err", err)
 		return nil
 	}
+	// Pre-Byzantium receipts only carry the intermediate state root, fill in the
+	// transaction outcomes if they were backfilled by re-executing the block
+	if statuses := ReadReceiptStatuses(db, hash, number); len(statuses) == len(receipts) {
+		for i, receipt := range receipts {
+			receipt.Status = statuses[i]
+		}
+	}
 	return receipts
 }
 
@@ -618,7 +629,7 @@ func WriteReceipts(db fourtwentydb.KeyValueWriter, hash common.Hash, number uint
 		log.Crit("Failed to encode block receipts", "err", err)
 	}
 	// Store the flattened receipt slice
-	if err := db.Put(blockReceiptsKey(number, hash), bytes); err != nil {
+	if err := db.Put(blockReceiptsKey(number, hash), encodeValue(bytes)); err != nil {
 		log.Crit("Failed to store block receipts", "err", err)
 	}
 }
@@ -630,6 +641,43 @@ func DeleteReceipts(db fourtwentydb.KeyValueWriter, hash common.Hash, number uin
 	}
 }
 
+// HasReceiptStatuses verifies the existence of the backfilled receipt statuses
+// corresponding to the hash.
+func HasReceiptStatuses(db fourtwentydb.KeyValueReader, hash common.Hash, number uint64) bool {
+	if has, err := db.Has(receiptStatusKey(number, hash)); !has || err != nil {
+		return false
+	}
+	return true
+}
+
+// ReadReceiptStatuses retrieves the transaction outcomes of a pre-Byzantium
+// block, as backfilled by re-executing it. Nil is returned if the block has not
+// been backfilled.
+func ReadReceiptStatuses(db fourtwentydb.KeyValueReader, hash common.Hash, number uint64) []uint64 {
+	data, _ := db.Get(receiptStatusKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var statuses []uint64
+	if err := rlp.DecodeBytes(data, &statuses); err != nil {
+		log.Error("Invalid receipt statuses RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return statuses
+}
+
+// WriteReceiptStatuses stores the transaction outcomes of a pre-Byzantium block
+// alongside its receipts, which only carry the intermediate state roots.
+func WriteReceiptStatuses(db fourtwentydb.KeyValueWriter, hash common.Hash, number uint64, statuses []uint64) {
+	data, err := rlp.EncodeToBytes(statuses)
+	if err != nil {
+		log.Crit("Failed to encode receipt statuses", "err", err)
+	}
+	if err := db.Put(receiptStatusKey(number, hash), data); err != nil {
+		log.Crit("Failed to store receipt statuses", "err", err)
+	}
+}
+
 // ReadBlock retrieves an entire block corresponding to the hash, assembling it
 // back from the stored header and body. If either the header or body could not
 // be retrieved nil is returned.
@@ -728,3 +776,29 @@ func FindCommonAncestor(db fourtwentydb.Reader, a, b *types.Header) *types.Heade
 	}
 	return a
 }
+
+// ReadHeadHeader returns the current canonical head header.
+func ReadHeadHeader(db fourtwentydb.Reader) *types.Header {
+	headHeaderHash := ReadHeadHeaderHash(db)
+	if headHeaderHash == (common.Hash{}) {
+		return nil
+	}
+	headHeaderNumber := ReadHeaderNumber(db, headHeaderHash)
+	if headHeaderNumber == nil {
+		return nil
+	}
+	return ReadHeader(db, headHeaderHash, *headHeaderNumber)
+}
+
+// ReadHeadBlock returns the current canonical head block.
+func ReadHeadBlock(db fourtwentydb.Reader) *types.Block {
+	headBlockHash := ReadHeadBlockHash(db)
+	if headBlockHash == (common.Hash{}) {
+		return nil
+	}
+	headBlockNumber := ReadHeaderNumber(db, headBlockHash)
+	if headBlockNumber == nil {
+		return nil
+	}
+	return ReadBlock(db, headBlockHash, *headBlockNumber)
+}
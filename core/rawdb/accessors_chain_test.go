@@ -104,6 +104,48 @@ func TestBodyStorage(t *testing.T) {
 	}
 }
 
+// Tests that block bodies and receipts written with value compression enabled
+// are stored tagged and read back transparently, even after disabling it.
+func TestCompressedBodyReceiptStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	SetValueCompression(true)
+	defer SetValueCompression(false)
+
+	tx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), bytes.Repeat([]byte{0x42}, 256))
+	body := &types.Body{Transactions: types.Transactions{tx}}
+	receipts := types.Receipts{{
+		Status:              types.ReceiptStatusSuccessful,
+		CumulativeSmokeUsed: 1,
+		Logs:                []*types.Log{{Address: common.BytesToAddress([]byte{0x11}), Data: bytes.Repeat([]byte{0x42}, 256)}},
+		TxHash:              tx.Hash(),
+	}}
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	WriteBody(db, hash, 0, body)
+	WriteReceipts(db, hash, 0, receipts)
+
+	for _, key := range [][]byte{blockBodyKey(0, hash), blockReceiptsKey(0, hash)} {
+		if blob, _ := db.Get(key); len(blob) == 0 || blob[0] != snappyValueTag {
+			t.Fatalf("value of %x not stored compressed", key)
+		}
+	}
+	// Disable the compression, the stored values should still be readable
+	SetValueCompression(false)
+
+	want, _ := rlp.EncodeToBytes(body)
+	if have := ReadBodyRLP(db, hash, 0); !bytes.Equal(have, want) {
+		t.Fatalf("body RLP mismatch: have %x, want %x", have, want)
+	}
+	if err := checkReceiptsRLP(ReadRawReceipts(db, hash, 0), receipts); err != nil {
+		t.Fatalf(err.Error())
+	}
+	// Values written afterwards should be stored plain
+	WriteReceipts(db, hash, 0, receipts)
+	if blob, _ := db.Get(blockReceiptsKey(0, hash)); len(blob) == 0 || blob[0] < 0xc0 {
+		t.Fatalf("receipts stored compressed after disabling compression")
+	}
+}
+
 // Tests block storage and retrieval operations.
 func TestBlockStorage(t *testing.T) {
 	db := NewMemoryDatabase()
@@ -342,6 +384,45 @@ func TestBlockReceiptStorage(t *testing.T) {
 	}
 }
 
+// Tests that backfilled statuses are reported for receipts carrying post states,
+// without altering their consensus encoding.
+func TestReceiptStatusStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	tx1 := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(2, common.HexToAddress("0x2"), big.NewInt(2), 2, big.NewInt(2), nil)
+	body := &types.Body{Transactions: types.Transactions{tx1, tx2}}
+
+	receipts := types.Receipts{
+		{PostState: common.Hash{1}.Bytes(), CumulativeSmokeUsed: 1, TxHash: tx1.Hash()},
+		{PostState: common.Hash{2}.Bytes(), CumulativeSmokeUsed: 2, TxHash: tx2.Hash()},
+	}
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	WriteBody(db, hash, 0, body)
+	WriteReceipts(db, hash, 0, receipts)
+
+	if HasReceiptStatuses(db, hash, 0) {
+		t.Fatalf("non existent receipt statuses reported")
+	}
+	if statuses := ReadReceiptStatuses(db, hash, 0); statuses != nil {
+		t.Fatalf("non existent receipt statuses returned: %v", statuses)
+	}
+	WriteReceiptStatuses(db, hash, 0, []uint64{types.ReceiptStatusSuccessful, types.ReceiptStatusFailed})
+	if !HasReceiptStatuses(db, hash, 0) {
+		t.Fatalf("stored receipt statuses not reported")
+	}
+	rs := ReadReceipts(db, hash, 0, params.TestChainConfig)
+	if len(rs) != 2 {
+		t.Fatalf("receipt count mismatch: have %d, want 2", len(rs))
+	}
+	if rs[0].Status != types.ReceiptStatusSuccessful || rs[1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("receipt statuses mismatch: have [%d %d], want [1 0]", rs[0].Status, rs[1].Status)
+	}
+	if err := checkReceiptsRLP(rs, receipts); err != nil {
+		t.Fatalf(err.Error())
+	}
+}
+
 func checkReceiptsRLP(have, want types.Receipts) error {
 	if len(have) != len(want) {
 		return fmt.Errorf("receipts sizes mismatch: have %d, want %d", len(have), len(want))
@@ -0,0 +1,67 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/log"
+)
+
+// ReadCallTrace retrieves the pre-computed call trace of a transaction within
+// the given block, encoded as JSON. Nil is returned if the transaction hasn't
+// been traced in that block.
+func ReadCallTrace(db fourtwentydb.KeyValueReader, blockHash common.Hash, txHash common.Hash) []byte {
+	data, _ := db.Get(callTraceKey(blockHash, txHash))
+	return data
+}
+
+// WriteCallTrace stores the JSON encoded call trace of a transaction within the
+// given block.
+func WriteCallTrace(db fourtwentydb.KeyValueWriter, blockHash common.Hash, txHash common.Hash, trace []byte) {
+	if err := db.Put(callTraceKey(blockHash, txHash), trace); err != nil {
+		log.Crit("Failed to store call trace", "err", err)
+	}
+}
+
+// DeleteCallTrace removes the call trace of a transaction within the given block.
+func DeleteCallTrace(db fourtwentydb.KeyValueWriter, blockHash common.Hash, txHash common.Hash) {
+	if err := db.Delete(callTraceKey(blockHash, txHash)); err != nil {
+		log.Crit("Failed to delete call trace", "err", err)
+	}
+}
+
+// ReadCallTraceTail retrieves the number of the oldest block whose call traces
+// are retained, or nil if no traces have been stored yet.
+func ReadCallTraceTail(db fourtwentydb.KeyValueReader) *uint64 {
+	data, _ := db.Get(callTraceTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteCallTraceTail stores the number of the oldest block whose call traces
+// are retained.
+func WriteCallTraceTail(db fourtwentydb.KeyValueWriter, number uint64) {
+	if err := db.Put(callTraceTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the call trace tail", "err", err)
+	}
+}
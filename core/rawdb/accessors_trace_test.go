@@ -0,0 +1,54 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+)
+
+// Tests call trace storage and retrieval operations.
+func TestCallTraceStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	block, hash := common.HexToHash("0x01"), common.HexToHash("0x02")
+	trace := []byte(`{"type":"CALL","from":"0x0000000000000000000000000000000000000000"}`)
+
+	if entry := ReadCallTrace(db, block, hash); entry != nil {
+		t.Fatalf("Non existent call trace returned: %s", entry)
+	}
+	WriteCallTrace(db, block, hash, trace)
+	if entry := ReadCallTrace(db, block, hash); !bytes.Equal(entry, trace) {
+		t.Fatalf("Call trace mismatch: have %s, want %s", entry, trace)
+	}
+	if entry := ReadCallTrace(db, common.HexToHash("0x03"), hash); entry != nil {
+		t.Fatalf("Call trace returned for a different block: %s", entry)
+	}
+	DeleteCallTrace(db, block, hash)
+	if entry := ReadCallTrace(db, block, hash); entry != nil {
+		t.Fatalf("Deleted call trace returned: %s", entry)
+	}
+	if tail := ReadCallTraceTail(db); tail != nil {
+		t.Fatalf("Non existent call trace tail returned: %d", *tail)
+	}
+	WriteCallTraceTail(db, 42)
+	if tail := ReadCallTraceTail(db); tail == nil || *tail != 42 {
+		t.Fatalf("Call trace tail mismatch: have %v, want %d", tail, 42)
+	}
+}
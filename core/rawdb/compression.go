@@ -0,0 +1,83 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/log"
+	"github.com/golang/snappy"
+)
+
+// snappyValueTag is the version tag prefixed to the block bodies and receipts
+// stored snappy compressed in the key-value store. Plain values are RLP lists,
+// always starting with a byte of at least 0xc0, so they can't be mistaken for
+// tagged ones.
+const snappyValueTag = 0x01
+
+// compressValues is set if newly written block bodies and receipts should be
+// compressed.
+var compressValues uint32
+
+// SetValueCompression toggles the compression of the block bodies and receipts
+// written into the key-value store. Stored values are decompressed transparently
+// regardless of the setting, so it can be changed between restarts.
+func SetValueCompression(enabled bool) {
+	if enabled {
+		atomic.StoreUint32(&compressValues, 1)
+	} else {
+		atomic.StoreUint32(&compressValues, 0)
+	}
+}
+
+// encodeValue compresses an RLP encoded block body or receipt list, if value
+// compression is enabled.
+func encodeValue(data []byte) []byte {
+	if atomic.LoadUint32(&compressValues) == 0 {
+		return data
+	}
+	enc := make([]byte, 1+snappy.MaxEncodedLen(len(data)))
+	enc[0] = snappyValueTag
+	return enc[:1+len(snappy.Encode(enc[1:], data))]
+}
+
+// decodeValue returns the plain RLP encoding of a stored block body or receipt
+// list, decompressing it according to its version tag.
+func decodeValue(data []byte) ([]byte, error) {
+	switch {
+	case len(data) == 0 || data[0] >= 0xc0:
+		return data, nil
+	case data[0] == snappyValueTag:
+		return snappy.Decode(nil, data[1:])
+	default:
+		return nil, fmt.Errorf("unknown value encoding %#x", data[0])
+	}
+}
+
+// readValue retrieves a block body or receipt list from the key-value store,
+// decompressing it if needed.
+func readValue(db fourtwentydb.KeyValueReader, key []byte) []byte {
+	data, _ := db.Get(key)
+	data, err := decodeValue(data)
+	if err != nil {
+		log.Error("Failed to decode stored value", "key", fmt.Sprintf("%#x", key), "err", err)
+		return nil
+	}
+	return data
+}
@@ -22,10 +22,10 @@ import (
 
 	"github.com/VictoriaMetrics/fastcache"
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/core/rawdb"
 	"github.com/420integrated/go-420coin/420db"
 	"github.com/420integrated/go-420coin/trie"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -113,17 +113,16 @@ func NewDatabase(db fourtwentydb.Database) Database {
 // is safe for concurrent use and retains a lot of collapsed RLP trie nodes in a
 // large memory cache.
 func NewDatabaseWithConfig(db fourtwentydb.Database, config *trie.Config) Database {
-	csc, _ := lru.New(codeSizeCacheSize)
 	return &cachingDB{
 		db:            trie.NewDatabaseWithConfig(db, config),
-		codeSizeCache: csc,
+		codeSizeCache: cache.New("state/cache/codesizes", codeSizeCacheSize, 0),
 		codeCache:     fastcache.New(codeCacheSize),
 	}
 }
 
 type cachingDB struct {
 	db            *trie.Database
-	codeSizeCache *lru.Cache
+	codeSizeCache *cache.Cache
 	codeCache     *fastcache.Cache
 }
 
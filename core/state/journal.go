@@ -17,9 +17,8 @@
 package state
 
 import (
-	"math/big"
-
 	"github.com/420integrated/go-420coin/common"
+	"github.com/holiman/uint256"
 )
 
 // journalEntry is a modification entry in the state change journal that can be
@@ -72,6 +71,20 @@ func (j *journal) revert(statedb *StateDB, snapshot int) {
 	j.entries = j.entries[:snapshot]
 }
 
+// reset clears all tracked modifications, retaining the allocated capacity of
+// the journal so that it can be reused by the next transaction without incurring
+// fresh allocations.
+func (j *journal) reset() {
+	// Nil out the entries to not keep reverted state objects alive
+	for i := range j.entries {
+		j.entries[i] = nil
+	}
+	j.entries = j.entries[:0]
+	for addr := range j.dirties {
+		delete(j.dirties, addr)
+	}
+}
+
 // dirty explicitly sets an address to dirty, even if the change entries would
 // otherwise suggest it as clean. This method is an ugly hack to handle the RIPEMD
 // precompile consensus exception.
@@ -96,13 +109,13 @@ type (
 	suicideChange struct {
 		account     *common.Address
 		prev        bool // if account had already suicided
-		prevbalance *big.Int
+		prevbalance uint256.Int
 	}
 
 	// Changes to individual accounts.
 	balanceChange struct {
 		account *common.Address
-		prev    *big.Int
+		prev    uint256.Int
 	}
 	nonceChange struct {
 		account *common.Address
@@ -164,7 +177,7 @@ func (ch suicideChange) revert(s *StateDB) {
 	obj := s.getStateObject(*ch.account)
 	if obj != nil {
 		obj.suicided = ch.prev
-		obj.setBalance(ch.prevbalance)
+		obj.setBalance(&ch.prevbalance)
 	}
 }
 
@@ -182,7 +195,7 @@ func (ch touchChange) dirtied() *common.Address {
 }
 
 func (ch balanceChange) revert(s *StateDB) {
-	s.getStateObject(*ch.account).setBalance(ch.prev)
+	s.getStateObject(*ch.account).setBalance(&ch.prev)
 }
 
 func (ch balanceChange) dirtied() *common.Address {
@@ -17,6 +17,7 @@
 package snapshot
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -369,3 +370,32 @@ func TestPostCapBasicDataAccess(t *testing.T) {
 		t.Error("expected error capping the disk layer, got none")
 	}
 }
+
+// Tests that the generation progress of the snapshot tree is reported from its
+// disk layer, regardless of the diff layers stacked on top.
+func TestProgress(t *testing.T) {
+	base := &diskLayer{
+		diskdb:    rawdb.NewMemoryDatabase(),
+		root:      common.HexToHash("0x01"),
+		cache:     fastcache.New(1024 * 500),
+		genMarker: []byte{0x80},
+	}
+	snaps := &Tree{
+		layers: map[common.Hash]snapshot{
+			base.root: base,
+		},
+	}
+	snaps.Update(common.HexToHash("0x02"), common.HexToHash("0x01"), nil, map[common.Hash][]byte{common.HexToHash("0xa1"): randomAccount()}, nil)
+
+	root, layers, marker, err := snaps.Progress()
+	if err != nil {
+		t.Fatalf("failed to retrieve progress: %v", err)
+	}
+	if root != base.root || layers != 2 || !bytes.Equal(marker, []byte{0x80}) {
+		t.Fatalf("progress mismatch: have root %x, %d layers, marker %x", root, layers, marker)
+	}
+	base.genMarker = nil
+	if _, _, marker, _ := snaps.Progress(); marker != nil {
+		t.Fatalf("completed snapshot reported marker %x", marker)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/420integrated/go-420coin/crypto"
 	"github.com/420integrated/go-420coin/metrics"
 	"github.com/420integrated/go-420coin/rlp"
+	"github.com/holiman/uint256"
 )
 
 var emptyCodeHash = crypto.Keccak256(nil)
@@ -66,6 +67,7 @@ type stateObject struct {
 	address  common.Address
 	addrHash common.Hash // hash of 420coin address of the account
 	data     Account
+	balance  uint256.Int // account balance, data.Balance is only filled on encoding
 	db       *StateDB
 
 	// DB error.
@@ -94,7 +96,7 @@ type stateObject struct {
 
 // empty returns if the account is considered empty.
 func (s *stateObject) empty() bool {
-	return s.data.Nonce == 0 && s.data.Balance.Sign() == 0 && bytes.Equal(s.data.CodeHash, emptyCodeHash)
+	return s.data.Nonce == 0 && s.balance.IsZero() && bytes.Equal(s.data.CodeHash, emptyCodeHash)
 }
 
 // Account is the 420coin consensus representation of accounts.
@@ -108,8 +110,10 @@ type Account struct {
 
 // newObject creates a state object.
 func newObject(db *StateDB, address common.Address, data Account) *stateObject {
-	if data.Balance == nil {
-		data.Balance = new(big.Int)
+	var balance uint256.Int
+	if data.Balance != nil {
+		balance = *balanceFromBig(data.Balance)
+		data.Balance = nil
 	}
 	if data.CodeHash == nil {
 		data.CodeHash = emptyCodeHash
@@ -122,15 +126,24 @@ func newObject(db *StateDB, address common.Address, data Account) *stateObject {
 		address:        address,
 		addrHash:       crypto.Keccak256Hash(address[:]),
 		data:           data,
+		balance:        balance,
 		originStorage:  make(Storage),
 		pendingStorage: make(Storage),
 		dirtyStorage:   make(Storage),
 	}
 }
 
+// account returns the consensus representation of the object, converting
+// the balance back to a big integer.
+func (s *stateObject) account() Account {
+	data := s.data
+	data.Balance = s.balance.ToBig()
+	return data
+}
+
 // EncodeRLP implements rlp.Encoder.
 func (s *stateObject) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, s.data)
+	return rlp.Encode(w, s.account())
 }
 
 // setError remembers the first non-nil error it is called with.
@@ -386,7 +399,8 @@ func (s *stateObject) AddBalance(amount *big.Int) {
 		}
 		return
 	}
-	s.SetBalance(new(big.Int).Add(s.Balance(), amount))
+	balance := balanceFromBig(amount)
+	s.updateBalance(balance.Add(&s.balance, balance))
 }
 
 // SubBalance removes amount from s's balance.
@@ -395,19 +409,36 @@ func (s *stateObject) SubBalance(amount *big.Int) {
 	if amount.Sign() == 0 {
 		return
 	}
-	s.SetBalance(new(big.Int).Sub(s.Balance(), amount))
+	balance := balanceFromBig(amount)
+	s.updateBalance(balance.Sub(&s.balance, balance))
 }
 
 func (s *stateObject) SetBalance(amount *big.Int) {
+	s.updateBalance(balanceFromBig(amount))
+}
+
+// balanceFromBig converts a balance or balance change to its uint256 form. The
+// state can't represent negative amounts or ones not fitting into 256 bits, so
+// rather than silently truncating them it panics.
+func balanceFromBig(amount *big.Int) *uint256.Int {
+	balance, overflow := uint256.FromBig(amount)
+	if overflow || amount.Sign() < 0 {
+		panic(fmt.Sprintf("invalid balance amount %v", amount))
+	}
+	return balance
+}
+
+// updateBalance journals the current balance and replaces it with amount.
+func (s *stateObject) updateBalance(amount *uint256.Int) {
 	s.db.journal.append(balanceChange{
 		account: &s.address,
-		prev:    new(big.Int).Set(s.data.Balance),
+		prev:    s.balance,
 	})
 	s.setBalance(amount)
 }
 
-func (s *stateObject) setBalance(amount *big.Int) {
-	s.data.Balance = amount
+func (s *stateObject) setBalance(amount *uint256.Int) {
+	s.balance = *amount
 }
 
 // Return the smoke back to the origin. Used by the Virtual machine or Closures
@@ -415,6 +446,7 @@ func (s *stateObject) ReturnSmoke(smoke *big.Int) {}
 
 func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject := newObject(db, s.address, s.data)
+	stateObject.balance = s.balance
 	if s.trie != nil {
 		stateObject.trie = db.db.CopyTrie(s.trie)
 	}
@@ -502,8 +534,9 @@ func (s *stateObject) CodeHash() []byte {
 	return s.data.CodeHash
 }
 
+// Balance returns a copy of the account balance.
 func (s *stateObject) Balance() *big.Int {
-	return s.data.Balance
+	return s.balance.ToBig()
 }
 
 func (s *stateObject) Nonce() uint64 {
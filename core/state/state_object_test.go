@@ -18,9 +18,12 @@ package state
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/rlp"
 )
 
 func BenchmarkCutOriginal(b *testing.B) {
@@ -44,3 +47,79 @@ func BenchmarkCutCustomTrim(b *testing.B) {
 		common.TrimLeftZeroes(value[:])
 	}
 }
+
+func TestStateObjectBalance(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	addr := common.BytesToAddress([]byte{0x01})
+
+	state.AddBalance(addr, big.NewInt(100))
+	snap := state.Snapshot()
+	state.SubBalance(addr, big.NewInt(30))
+	state.AddBalance(addr, big.NewInt(5))
+	if have := state.GetBalance(addr); have.Cmp(big.NewInt(75)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 75", have)
+	}
+	// The returned balance must not alias the object's balance
+	state.GetBalance(addr).SetUint64(0)
+	if have := state.GetBalance(addr); have.Cmp(big.NewInt(75)) != 0 {
+		t.Fatalf("balance modified through getter: have %v, want 75", have)
+	}
+	state.RevertToSnapshot(snap)
+	if have := state.GetBalance(addr); have.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("balance mismatch after revert: have %v, want 100", have)
+	}
+	snap = state.Snapshot()
+	state.Suicide(addr)
+	if have := state.GetBalance(addr); have.Sign() != 0 {
+		t.Fatalf("balance mismatch after suicide: have %v, want 0", have)
+	}
+	state.RevertToSnapshot(snap)
+	if have := state.GetBalance(addr); have.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("balance mismatch after suicide revert: have %v, want 100", have)
+	}
+	// The consensus encoding must carry the balance as a big integer
+	obj := state.getStateObject(addr)
+	have, err := rlp.EncodeToBytes(obj)
+	if err != nil {
+		t.Fatalf("failed to encode state object: %v", err)
+	}
+	want, _ := rlp.EncodeToBytes(&Account{Balance: big.NewInt(100), Root: emptyRoot, CodeHash: emptyCodeHash})
+	if !bytes.Equal(have, want) {
+		t.Fatalf("encoding mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that balance amounts the state can't represent are rejected instead of
+// being silently truncated.
+func TestStateObjectBalanceRange(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	addr := common.BytesToAddress([]byte{0x01})
+
+	maxBalance := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	state.SetBalance(addr, maxBalance)
+	if have := state.GetBalance(addr); have.Cmp(maxBalance) != 0 {
+		t.Fatalf("balance mismatch: have %v, want %v", have, maxBalance)
+	}
+	for i, test := range []struct {
+		name   string
+		update func()
+	}{
+		{"set overflow", func() { state.SetBalance(addr, new(big.Int).Add(maxBalance, big.NewInt(1))) }},
+		{"set negative", func() { state.SetBalance(addr, big.NewInt(-1)) }},
+		{"add overflow", func() { state.AddBalance(addr, new(big.Int).Lsh(big.NewInt(1), 256)) }},
+		{"add negative", func() { state.AddBalance(addr, big.NewInt(-1)) }},
+		{"sub negative", func() { state.SubBalance(addr, big.NewInt(-1)) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("test %d (%s): invalid amount accepted", i, test.name)
+				}
+			}()
+			test.update()
+		}()
+	}
+	if have := state.GetBalance(addr); have.Cmp(maxBalance) != 0 {
+		t.Fatalf("balance modified by invalid amounts: have %v, want %v", have, maxBalance)
+	}
+}
@@ -43,6 +43,14 @@ type revision struct {
 var (
 	// emptyRoot is the known root hash of an empty trie.
 	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// revisionDepthHistogram tracks the deepest snapshot nesting reached within a
+	// transaction, mirroring the depth of its call tree.
+	revisionDepthHistogram = metrics.NewRegisteredHistogram("state/revision/depth", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+	// journalSizeHistogram tracks the number of journal entries accumulated
+	// within a transaction.
+	journalSizeHistogram = metrics.NewRegisteredHistogram("state/journal/entries", nil, metrics.NewExpDecaySample(1028, 0.015))
 )
 
 type proofList [][]byte
@@ -101,6 +109,7 @@ type StateDB struct {
 	journal        *journal
 	validRevisions []revision
 	nextRevisionId int
+	maxRevisions   int // Deepest snapshot nesting since the journal was last cleared
 
 	// Measurements gathered during execution for debugging purposes
 	AccountReads         time.Duration
@@ -374,6 +383,18 @@ func (s *StateDB) StorageTrie(addr common.Address) Trie {
 	return cpy.getTrie(s.db)
 }
 
+// StorageRoot returns the root of the storage trie of an account as of the last
+// time it was hashed, and whether it still reflects the storage of the account,
+// i.e. there are no modifications since which aren't hashed yet. Non-existent
+// accounts have the empty root.
+func (s *StateDB) StorageRoot(addr common.Address) (common.Hash, bool) {
+	stateObject := s.getStateObject(addr)
+	if stateObject == nil {
+		return emptyRoot, true
+	}
+	return stateObject.data.Root, stateObject.fakeStorage == nil && len(stateObject.dirtyStorage) == 0 && len(stateObject.pendingStorage) == 0
+}
+
 func (s *StateDB) HasSuicided(addr common.Address) bool {
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
@@ -452,10 +473,10 @@ func (s *StateDB) Suicide(addr common.Address) bool {
 	s.journal.append(suicideChange{
 		account:     &addr,
 		prev:        stateObject.suicided,
-		prevbalance: new(big.Int).Set(stateObject.Balance()),
+		prevbalance: stateObject.balance,
 	})
 	stateObject.markSuicided()
-	stateObject.data.Balance = new(big.Int)
+	stateObject.balance.Clear()
 
 	return true
 }
@@ -486,7 +507,7 @@ func (s *StateDB) updateStateObject(obj *stateObject) {
 	// enough to track account updates at commit time, deletions need tracking
 	// at transaction boundary level to ensure we capture state clearing.
 	if s.snap != nil {
-		s.snapAccounts[obj.addrHash] = snapshot.SlimAccountRLP(obj.data.Nonce, obj.data.Balance, obj.data.Root, obj.data.CodeHash)
+		s.snapAccounts[obj.addrHash] = snapshot.SlimAccountRLP(obj.data.Nonce, obj.Balance(), obj.data.Root, obj.data.CodeHash)
 	}
 }
 
@@ -627,7 +648,7 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 func (s *StateDB) CreateAccount(addr common.Address) {
 	newObj, prev := s.createObject(addr)
 	if prev != nil {
-		newObj.setBalance(prev.data.Balance)
+		newObj.setBalance(&prev.balance)
 	}
 }
 
@@ -732,6 +753,9 @@ func (s *StateDB) Snapshot() int {
 	id := s.nextRevisionId
 	s.nextRevisionId++
 	s.validRevisions = append(s.validRevisions, revision{id, s.journal.length()})
+	if len(s.validRevisions) > s.maxRevisions {
+		s.maxRevisions = len(s.validRevisions)
+	}
 	return id
 }
 
@@ -793,6 +817,21 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	s.clearJournalAndRefund()
 }
 
+// DirtyAccounts returns the addresses of all accounts modified since the last
+// commit, including the ones touched by the not yet finalised journal.
+func (s *StateDB) DirtyAccounts() []common.Address {
+	addrs := make([]common.Address, 0, len(s.stateObjectsDirty)+len(s.journal.dirties))
+	for addr := range s.stateObjectsDirty {
+		addrs = append(addrs, addr)
+	}
+	for addr := range s.journal.dirties {
+		if _, ok := s.stateObjectsDirty[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 // IntermediateRoot computes the current root hash of the state trie.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
@@ -829,11 +868,16 @@ func (s *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 }
 
 func (s *StateDB) clearJournalAndRefund() {
+	if metrics.Enabled && (len(s.journal.entries) > 0 || s.maxRevisions > 0) {
+		revisionDepthHistogram.Update(int64(s.maxRevisions))
+		journalSizeHistogram.Update(int64(len(s.journal.entries)))
+	}
 	if len(s.journal.entries) > 0 {
-		s.journal = newJournal()
+		s.journal.reset()
 		s.refund = 0
 	}
 	s.validRevisions = s.validRevisions[:0] // Snapshots can be created without journal entires
+	s.maxRevisions = 0
 }
 
 // Commit writes the state to the underlying in-memory trie database.
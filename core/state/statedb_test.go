@@ -915,3 +915,80 @@ func TestStateDBAccessList(t *testing.T) {
 		t.Fatalf("expected empty, got %d", got)
 	}
 }
+
+// Tests that the journal reused across transactions does not leak reverts from
+// one transaction into the next.
+func TestJournalReuse(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	addr := common.BytesToAddress([]byte("so"))
+
+	state.AddBalance(addr, big.NewInt(1))
+	state.Finalise(true)
+	if len(state.journal.entries) != 0 || len(state.journal.dirties) != 0 {
+		t.Fatalf("journal not cleared: %d entries, %d dirties", len(state.journal.entries), len(state.journal.dirties))
+	}
+	id := state.Snapshot()
+	state.AddBalance(addr, big.NewInt(2))
+	state.RevertToSnapshot(id)
+
+	if balance := state.GetBalance(addr); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want %v", balance, 1)
+	}
+}
+
+// Benchmarks the journal under deeply nested call trees, where every call frame
+// takes a snapshot, modifies some state and is either kept or reverted.
+func BenchmarkDeepSnapshotRevert(b *testing.B) { benchmarkDeepSnapshot(b, true) }
+func BenchmarkDeepSnapshotKeep(b *testing.B)   { benchmarkDeepSnapshot(b, false) }
+
+func benchmarkDeepSnapshot(b *testing.B, revert bool) {
+	const depth = 1024 // Maximum call depth of the EVM
+
+	var (
+		state, _ = New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		addr     = common.BytesToAddress([]byte("reentrant"))
+		ids      = make([]int, depth)
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for d := 0; d < depth; d++ {
+			ids[d] = state.Snapshot()
+			state.AddBalance(addr, big.NewInt(1))
+			state.SetState(addr, common.BigToHash(big.NewInt(int64(d))), common.Hash{0x01})
+		}
+		if revert {
+			for d := depth - 1; d >= 0; d-- {
+				state.RevertToSnapshot(ids[d])
+			}
+		}
+		state.Finalise(true)
+	}
+}
+
+// Tests that the storage root of an account is only reported as valid while it
+// reflects all the storage modifications.
+func TestStorageRoot(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	addr := common.Address{0x01}
+
+	if root, valid := state.StorageRoot(addr); root != emptyRoot || !valid {
+		t.Fatalf("non-existent account root mismatch: have %x/%v, want %x/true", root, valid, emptyRoot)
+	}
+	state.SetState(addr, common.Hash{0x01}, common.Hash{0x02})
+	if _, valid := state.StorageRoot(addr); valid {
+		t.Fatalf("dirty storage root reported valid")
+	}
+	state.Finalise(true)
+	if _, valid := state.StorageRoot(addr); valid {
+		t.Fatalf("pending storage root reported valid")
+	}
+	state.IntermediateRoot(true)
+	root, valid := state.StorageRoot(addr)
+	if !valid || root == emptyRoot {
+		t.Fatalf("hashed storage root mismatch: have %x/%v", root, valid)
+	}
+	if trie := state.StorageTrie(addr); trie.Hash() != root {
+		t.Fatalf("storage root mismatch: have %x, want %x", root, trie.Hash())
+	}
+}
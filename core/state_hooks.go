@@ -0,0 +1,66 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync"
+
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+)
+
+// StateHook is implemented by chain specific logic which needs to modify the
+// state around the application of every transaction, e.g. fee routing or system
+// registry updates. Hooks run both when processing imported blocks and when
+// building new ones, so they are part of consensus: they must be deterministic
+// and must only depend on the chain config, the block and the state.
+type StateHook interface {
+	// PreApply is invoked before a transaction is applied to the state. Returning
+	// an error rejects the transaction, invalidating the block containing it.
+	PreApply(env *vm.EVM, statedb *state.StateDB, tx *types.Transaction, msg types.Message) error
+
+	// PostApply is invoked after a transaction was applied to the state, before
+	// its receipt is created, so any state modification is covered by it.
+	// Returning an error rejects the transaction, invalidating the block
+	// containing it.
+	PostApply(env *vm.EVM, statedb *state.StateDB, tx *types.Transaction, msg types.Message, result *ExecutionResult) error
+}
+
+var (
+	stateHooks    []StateHook
+	stateHookLock sync.RWMutex
+)
+
+// RegisterStateHook adds a hook to run around the application of every
+// transaction. Hooks run in the order they were registered. They must be
+// registered before any block is processed, typically from an init function,
+// otherwise blocks may be processed with different rules.
+func RegisterStateHook(hook StateHook) {
+	stateHookLock.Lock()
+	defer stateHookLock.Unlock()
+
+	stateHooks = append(stateHooks, hook)
+}
+
+// registeredStateHooks returns the currently registered state hooks.
+func registeredStateHooks() []StateHook {
+	stateHookLock.RLock()
+	defer stateHookLock.RUnlock()
+
+	return stateHooks
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+var errHookRejected = errors.New("rejected by hook")
+
+// testStateHook counts the transactions applied on a single chain, rejecting the
+// ones sent to a blocked address.
+type testStateHook struct {
+	config  *params.ChainConfig
+	counter common.Address
+	blocked common.Address
+}
+
+func (h *testStateHook) PreApply(env *vm.EVM, statedb *state.StateDB, tx *types.Transaction, msg types.Message) error {
+	if env.ChainConfig() != h.config {
+		return nil
+	}
+	if to := msg.To(); to != nil && *to == h.blocked {
+		return errHookRejected
+	}
+	return nil
+}
+
+func (h *testStateHook) PostApply(env *vm.EVM, statedb *state.StateDB, tx *types.Transaction, msg types.Message, result *ExecutionResult) error {
+	if env.ChainConfig() != h.config {
+		return nil
+	}
+	statedb.AddBalance(h.counter, big.NewInt(1))
+	return nil
+}
+
+// Tests that registered state hooks run around every transaction, both when
+// generating and when importing blocks, and that they can reject transactions.
+func TestStateHooks(t *testing.T) {
+	var (
+		signer     = types.HomesteadSigner{}
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		db         = rawdb.NewMemoryDatabase()
+		config     = *params.TestChainConfig
+		hook       = &testStateHook{config: &config, counter: common.Address{0xc0}, blocked: common.Address{0xbb}}
+		gspec      = &Genesis{
+			Config: &config,
+			Alloc:  GenesisAlloc{testAddr: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+	)
+	RegisterStateHook(hook)
+
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 2, func(i int, b *BlockGen) {
+		for j := 0; j < i+1; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(testAddr), common.Address{0x01}, big.NewInt(1), params.TxSmoke, nil, nil), signer, testKey)
+			b.AddTx(tx)
+		}
+	})
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, _ := blockchain.State()
+	if have := statedb.GetBalance(hook.counter); have.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("hook counter mismatch: have %v, want 3", have)
+	}
+	// Transactions rejected by a hook must invalidate the block
+	tx, _ := types.SignTx(types.NewTransaction(3, hook.blocked, big.NewInt(1), params.TxSmoke, nil, nil), signer, testKey)
+	block := GenerateBadBlock(blockchain.CurrentBlock(), ethash.NewFaker(), types.Transactions{tx})
+	if _, err := blockchain.InsertChain(types.Blocks{block}); !errors.Is(err, errHookRejected) {
+		t.Errorf("rejected transaction error mismatch: have %v, want %v", err, errHookRejected)
+	}
+}
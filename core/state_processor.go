@@ -35,10 +35,17 @@ import (
 // StateProcessor implements Processor.
 type StateProcessor struct {
 	config *params.ChainConfig // Chain configuration options
-	bc     *BlockChain         // Canonical block chain
+	bc     processorChain      // Canonical block chain
 	engine consensus.Engine    // Consensus engine used for block rewards
 }
 
+// processorChain is the chain access needed to process blocks, provided by the
+// BlockChain and by the ancestor headers carried in block witnesses.
+type processorChain interface {
+	ChainContext
+	consensus.ChainHeaderReader
+}
+
 // NewStateProcessor initialises a new StateProcessor.
 func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine) *StateProcessor {
 	return &StateProcessor{
@@ -83,13 +90,27 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		receipts = append(receipts, receipt)
 		allLogs = append(allLogs, receipt.Logs...)
 	}
-	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
-	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles())
+	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards),
+	// checking the credited rewards against the emission schedule if the engine supports it
+	finalize := func() {
+		p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles())
+	}
+	if verifier, ok := p.engine.(consensus.RewardVerifier); ok {
+		if err := verifier.VerifyRewards(p.bc, header, block.Uncles(), statedb, finalize); err != nil {
+			return nil, nil, 0, err
+		}
+	} else {
+		finalize()
+	}
 
 	return receipts, allLogs, *usedSmoke, nil
 }
 
 func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainContext, author *common.Address, gp *SmokePool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedSmoke *uint64, evm *vm.EVM) (*types.Receipt, error) {
+	// Dynamic fee transactions can't be decoded by nodes predating the base fee
+	if tx.IsDynamicFee() && !config.IsSmokeBaseFee(header.Number) {
+		return nil, ErrDynamicFeeNotSupported
+	}
 	// Create a new context to be used in the EVM environment
 	txContext := NewEVMTxContext(msg)
 	// Add addresses to access list if applicable
@@ -102,15 +123,34 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 		for _, addr := range evm.ActivePrecompiles() {
 			statedb.AddAddressToAccessList(addr)
 		}
+		// Warm up everything named by the access list of the transaction
+		for _, el := range msg.AccessList() {
+			statedb.AddAddressToAccessList(el.Address)
+			for _, key := range el.StorageKeys {
+				statedb.AddSlotToAccessList(el.Address, key)
+			}
+		}
 	}
 
 	// Update the evm with the new transaction context.
 	evm.Reset(txContext, statedb)
-	// Apply the transaction to the current state (included in the env)
+
+	// Run the chain specific hooks around applying the transaction to the current state (included in the env)
+	hooks := registeredStateHooks()
+	for _, hook := range hooks {
+		if err := hook.PreApply(evm, statedb, tx, msg); err != nil {
+			return nil, err
+		}
+	}
 	result, err := ApplyMessage(evm, msg, gp)
 	if err != nil {
 		return nil, err
 	}
+	for _, hook := range hooks {
+		if err := hook.PostApply(evm, statedb, tx, msg, result); err != nil {
+			return nil, err
+		}
+	}
 	// Update the state with pending changes
 	var root []byte
 	if config.IsByzantium(header.Number) {
@@ -123,8 +163,12 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 	// Create a new receipt for the transaction, storing the intermediate root and smoke used by the tx
 	// based on the eip phase, we're passing whether the root touch-delete accounts.
 	receipt := types.NewReceipt(root, result.Failed(), *usedSmoke)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.SmokeUsed = result.UsedSmoke
+	receipt.EffectiveSmokePrice = tx.EffectiveSmokePrice(header.BaseFee)
+	receipt.SmokeRefund = &result.RefundedSmoke
+	receipt.SmokeReturned = msg.Smoke() - result.UsedSmoke
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -28,7 +29,7 @@ import (
 	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/crypto"
 	"github.com/420integrated/go-420coin/params"
-	"github.com/420integrated/go-420cointrie"
+	"github.com/420integrated/go-420coin/trie"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -111,6 +112,28 @@ func TestStateProcessorErrors(t *testing.T) {
 	}
 }
 
+// Tests that blocks including dynamic fee transactions before the base fee fork
+// are rejected, as nodes predating it can't decode them.
+func TestDynamicFeeBeforeFork(t *testing.T) {
+	var (
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		db         = rawdb.NewMemoryDatabase()
+		gspec      = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{crypto.PubkeyToAddress(testKey.PublicKey): {Balance: big.NewInt(1000000000)}},
+		}
+		genesis       = gspec.MustCommit(db)
+		blockchain, _ = NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	)
+	defer blockchain.Stop()
+
+	tx, _ := types.SignTx(types.NewDynamicFeeTransaction(0, &common.Address{}, big.NewInt(0), params.TxSmoke, big.NewInt(1), big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+	block := GenerateBadBlock(genesis, ethash.NewFaker(), types.Transactions{tx})
+	if _, err := blockchain.InsertChain(types.Blocks{block}); !errors.Is(err, ErrDynamicFeeNotSupported) {
+		t.Fatalf("dynamic fee transaction error mismatch: have %v, want %v", err, ErrDynamicFeeNotSupported)
+	}
+}
+
 // GenerateBadBlock constructs a "block" which contains the transactions. The transactions are not expected to be
 // valid, and no proper post-state can be made. But from the perspective of the blockchain, the block is sufficiently
 // valid to be considered for import:
@@ -119,7 +142,7 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
-		Difficulty: engine.CalcDifficulty(&fakeChainReader{params.TestChainConfig}, parent.Time()+10, &types.Header{
+		Difficulty: engine.CalcDifficulty(&fakeChainReader{config: params.TestChainConfig}, parent.Time()+10, &types.Header{
 			Number:     parent.Number(),
 			Time:       parent.Time(),
 			Difficulty: parent.Difficulty(),
@@ -149,4 +172,60 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	header.Root = common.BytesToHash(hasher.Sum(nil))
 	// Assemble and return the final block for sealing
 	return types.NewBlock(header, txs, nil, receipts, new(trie.Trie))
-}
\ No newline at end of file
+}
+
+// Tests that the share of the transaction fees enabled by the chain config is
+// routed to the Veterans Fund, with the rest paid to the coinbase.
+func TestVeteransFeeRouting(t *testing.T) {
+	var (
+		signer     = types.HomesteadSigner{}
+		testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+		creator    = common.Address{0xcc}
+		veterans   = common.Address{0xaa}
+		coinbase   = common.Address{0x01}
+		db         = rawdb.NewMemoryDatabase()
+		config     = *params.TestChainConfig
+	)
+	config.VeteransFeeBlock = big.NewInt(1)
+	config.VeteransFeePercent = 10
+
+	gspec := &Genesis{
+		Config:    &config,
+		ExtraData: creator.Bytes(),
+		Alloc: GenesisAlloc{
+			testAddr: {Balance: big.NewInt(1000000000)},
+			crypto.CreateAddress(creator, 0): {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{
+					common.BytesToHash([]byte{1}): veterans.Hash(),
+				},
+			},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+	blockchain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbase)
+		tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), params.TxSmoke, big.NewInt(10), nil), signer, testKey)
+		b.AddTxWithChain(blockchain, tx)
+	})
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	statedb, _ := blockchain.State()
+
+	var (
+		fee       = big.NewInt(int64(params.TxSmoke) * 10)
+		share     = new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(10)), big.NewInt(100))
+		shares, _ = ethash.BlockRewards(blockchain.Config(), blocks[0].Number(), nil)
+	)
+	if have, want := statedb.GetBalance(veterans), new(big.Int).Add(shares.Veterans, share); have.Cmp(want) != 0 {
+		t.Errorf("veterans balance mismatch: have %v, want %v", have, want)
+	}
+	if have, want := statedb.GetBalance(coinbase), new(big.Int).Add(shares.Miner, new(big.Int).Sub(fee, share)); have.Cmp(want) != 0 {
+		t.Errorf("coinbase balance mismatch: have %v, want %v", have, want)
+	}
+}
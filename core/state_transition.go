@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/params"
 )
@@ -48,6 +49,7 @@ type StateTransition struct {
 	msg        Message
 	smoke        uint64
 	smokePrice   *big.Int
+	baseFee      *big.Int
 	initialSmoke uint64
 	value      *big.Int
 	data       []byte
@@ -67,12 +69,21 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
+}
+
+// TipCapper is implemented by messages capping the tip paid to the coinbase on
+// top of the base fee of the block, such as dynamic fee transactions. The smoke
+// price of any other message serves as both its fee cap and its tip cap.
+type TipCapper interface {
+	SmokeTipCap() *big.Int
 }
 
 // ExecutionResult includes all output after executing given evm
 // message no matter the execution itself is successful or not.
 type ExecutionResult struct {
 	UsedSmoke    uint64 // Total used smoke but include the refunded smoke
+	RefundedSmoke uint64 // Smoke refunded from the refund counter, already deducted from the used smoke
 	Err        error  // Any error encountered during the execution(listed in core/vm/errors.go)
 	ReturnData []byte // Returned data from evm(function result or data supplied with revert opcode)
 }
@@ -105,7 +116,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicSmoke computes the 'intrinsic smoke' for a message with the given data.
-func IntrinsicSmoke(data []byte, contractCreation, isHomestead bool, isEIP2028 bool) (uint64, error) {
+func IntrinsicSmoke(data []byte, accessList types.AccessList, contractCreation, isHomestead bool, isEIP2028 bool) (uint64, error) {
 	// Set the starting smoke for the raw transaction
 	var smoke uint64
 	if contractCreation && isHomestead {
@@ -138,6 +149,11 @@ func IntrinsicSmoke(data []byte, contractCreation, isHomestead bool, isEIP2028 b
 		}
 		smoke += z * params.TxDataZeroSmoke
 	}
+	// Bump the required smoke by the accounts and slots warmed by the access list
+	if accessList != nil {
+		smoke += uint64(len(accessList)) * params.TxAccessListAddressSmoke
+		smoke += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeySmoke
+	}
 	return smoke, nil
 }
 
@@ -174,8 +190,10 @@ func (st *StateTransition) to() common.Address {
 }
 
 func (st *StateTransition) buySmoke() error {
+	// The balance must cover the smoke at the fee cap, even if it's bought cheaper
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Smoke()), st.smokePrice)
-	if have, want := st.state.GetBalance(st.msg.From()), mgval; have.Cmp(want) < 0 {
+	balanceCheck := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Smoke()), st.msg.SmokePrice())
+	if have, want := st.state.GetBalance(st.msg.From()), balanceCheck; have.Cmp(want) < 0 {
 		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From().Hex(), have, want)
 	}
 	if err := st.gp.SubSmoke(st.msg.Smoke()); err != nil {
@@ -200,9 +218,42 @@ func (st *StateTransition) preCheck() error {
 				st.msg.From().Hex(), msgNonce, stNonce)
 		}
 	}
+	if st.evm.Context.BaseFee != nil {
+		if err := st.applyBaseFee(); err != nil {
+			return err
+		}
+	}
 	return st.buySmoke()
 }
 
+// applyBaseFee checks that the fee cap of the message covers the base fee of the
+// block, and lowers the smoke price to the base fee plus the tip the message is
+// willing to pay. The base fee part of the fees is burnt.
+func (st *StateTransition) applyBaseFee() error {
+	var (
+		baseFee = st.evm.Context.BaseFee
+		feeCap  = st.msg.SmokePrice()
+		tipCap  = feeCap
+	)
+	if capper, ok := st.msg.(TipCapper); ok {
+		tipCap = capper.SmokeTipCap()
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		return fmt.Errorf("%w: address %v, tip: %v, fee cap: %v", ErrTipAboveFeeCap, st.msg.From().Hex(), tipCap, feeCap)
+	}
+	if feeCap.Cmp(baseFee) < 0 {
+		return fmt.Errorf("%w: address %v, fee cap: %v, base fee: %v", ErrFeeCapTooLow, st.msg.From().Hex(), feeCap, baseFee)
+	}
+	if price := new(big.Int).Add(baseFee, tipCap); price.Cmp(feeCap) < 0 {
+		st.smokePrice = price
+	}
+	st.baseFee = baseFee
+
+	// SMOKEPRICE reports the price actually paid
+	st.evm.TxContext.SmokePrice = st.smokePrice
+	return nil
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the evm execution result with following fields.
 //
@@ -238,7 +289,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	contractCreation := msg.To() == nil
 
 	// Check clauses 4-5, subtract intrinsic smoke if everything is correct
-	smoke, err := IntrinsicSmoke(st.data, contractCreation, homestead, istanbul)
+	smoke, err := IntrinsicSmoke(st.data, msg.AccessList(), contractCreation, homestead, istanbul)
 	if err != nil {
 		return nil, err
 	}
@@ -262,17 +313,20 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
 		ret, st.smoke, vmerr = st.evm.Call(sender, st.to(), st.data, st.smoke, st.value)
 	}
-	st.refundSmoke()
-	st.state.AddBalance(st.evm.Context.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.smokeUsed()), st.smokePrice))
+	refund := st.refundSmoke()
+	st.payFees()
 
 	return &ExecutionResult{
 		UsedSmoke:    st.smokeUsed(),
+		RefundedSmoke: refund,
 		Err:        vmerr,
 		ReturnData: ret,
 	}, nil
 }
 
-func (st *StateTransition) refundSmoke() {
+// refundSmoke applies the refund counter and returns the remaining smoke to the
+// sender and the block smoke pool. The amount of refunded smoke is returned.
+func (st *StateTransition) refundSmoke() uint64 {
 	// Apply refund counter, capped to half of the used smoke.
 	refund := st.smokeUsed() / 2
 	if refund > st.state.GetRefund() {
@@ -287,6 +341,29 @@ func (st *StateTransition) refundSmoke() {
 	// Also return remaining smoke to the block smoke counter so it is
 	// available for the next transaction.
 	st.gp.AddSmoke(st.smoke)
+
+	return refund
+}
+
+// payFees pays the fees of the used smoke to the coinbase, routing the share
+// enabled by the chain config to the fee fund instead. Once the block has a base
+// fee, only the tip on top of it is paid, the rest is burnt.
+func (st *StateTransition) payFees() {
+	price := st.smokePrice
+	if st.baseFee != nil {
+		price = new(big.Int).Sub(price, st.baseFee)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(st.smokeUsed()), price)
+
+	percent := st.evm.ChainConfig().VeteransFeePercentAt(st.evm.Context.BlockNumber)
+	if percent > 0 && st.evm.Context.FeeFund != nil {
+		share := new(big.Int).Mul(fee, new(big.Int).SetUint64(percent))
+		share.Div(share, big.NewInt(100))
+
+		st.state.AddBalance(st.evm.Context.FeeFund(st.state), share)
+		fee.Sub(fee, share)
+	}
+	st.state.AddBalance(st.evm.Context.Coinbase, fee)
 }
 
 // smokeUsed returns the amount of smoke used up by the state transition.
@@ -190,7 +190,10 @@ func (m *txSortedMap) Remove(nonce uint64) bool {
 // Note, all transactions with nonces lower than start will also be returned to
 // prevent getting into and invalid state. This is not something that should ever
 // happen but better to be self correcting than failing!
-func (m *txSortedMap) Ready(start uint64) types.Transactions {
+//
+// If a base fee is given, the sequence stops at the first transaction whose fee
+// cap can't pay it.
+func (m *txSortedMap) Ready(start uint64, baseFee *big.Int) types.Transactions {
 	// Short circuit if no transactions are available
 	if m.index.Len() == 0 || (*m.index)[0] > start {
 		return nil
@@ -198,6 +201,9 @@ func (m *txSortedMap) Ready(start uint64) types.Transactions {
 	// Otherwise start accumulating incremental transactions
 	var ready types.Transactions
 	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next; next++ {
+		if baseFee != nil && m.items[next].SmokeFeeCap().Cmp(baseFee) < 0 {
+			break
+		}
 		ready = append(ready, m.items[next])
 		delete(m.items, next)
 		heap.Pop(m.index)
@@ -279,15 +285,10 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
 	if old != nil {
-		// threshold = oldGP * (100 + priceBump) / 100
-		a := big.NewInt(100 + int64(priceBump))
-		a = a.Mul(a, old.SmokePrice())
-		b := big.NewInt(100)
-		threshold := a.Div(a, b)
-		// Have to ensure that the new smoke price is higher than the old smoke
-		// price as well as checking the percentage threshold to ensure that
-		// this is accurate for low (Marley-level) smoke price replacements
-		if old.SmokePriceCmp(tx) >= 0 || tx.SmokePriceIntCmp(threshold) < 0 {
+		// Both the fee cap and the tip cap need to be bumped, as either of them
+		// may end up being the effective price. For legacy transactions they're
+		// both the smoke price.
+		if !bumped(old.SmokeFeeCap(), tx.SmokeFeeCap(), priceBump) || !bumped(old.SmokeTipCap(), tx.SmokeTipCap(), priceBump) {
 			return false, nil
 		}
 	}
@@ -302,6 +303,20 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	return true, old
 }
 
+// bumped reports whether the new price replaces the old one, which requires it to
+// be at least priceBump percent higher.
+func bumped(old, price *big.Int, priceBump uint64) bool {
+	// threshold = old * (100 + priceBump) / 100
+	a := big.NewInt(100 + int64(priceBump))
+	a = a.Mul(a, old)
+	b := big.NewInt(100)
+	threshold := a.Div(a, b)
+	// Have to ensure that the new price is higher than the old price as well as
+	// checking the percentage threshold to ensure that this is accurate for low
+	// (Marley-level) smoke price replacements
+	return price.Cmp(old) > 0 && price.Cmp(threshold) >= 0
+}
+
 // Forward removes all transactions from the list with a nonce lower than the
 // provided threshold. Every removed transaction is returned for any post-removal
 // maintenance.
@@ -378,8 +393,23 @@ func (l *txList) Remove(tx *types.Transaction) (bool, types.Transactions) {
 // Note, all transactions with nonces lower than start will also be returned to
 // prevent getting into and invalid state. This is not something that should ever
 // happen but better to be self correcting than failing!
-func (l *txList) Ready(start uint64) types.Transactions {
-	return l.txs.Ready(start)
+//
+// If a base fee is given, the sequence stops at the first transaction whose fee
+// cap can't pay it.
+func (l *txList) Ready(start uint64, baseFee *big.Int) types.Transactions {
+	return l.txs.Ready(start, baseFee)
+}
+
+// Underpaying removes the first transaction whose fee cap can't pay the given
+// base fee, along with all the transactions following it, and returns them.
+func (l *txList) Underpaying(baseFee *big.Int) types.Transactions {
+	for _, tx := range l.txs.flatten() {
+		if tx.SmokeFeeCap().Cmp(baseFee) < 0 {
+			nonce := tx.Nonce()
+			return l.txs.Filter(func(tx *types.Transaction) bool { return tx.Nonce() >= nonce })
+		}
+	}
+	return nil
 }
 
 // Len returns the length of the transaction list.
@@ -406,34 +436,52 @@ func (l *txList) LastElement() *types.Transaction {
 }
 
 // priceHeap is a heap.Interface implementation over transactions for retrieving
-// price-sorted transactions to discard when the pool fills up.
-type priceHeap []*types.Transaction
+// price-sorted transactions to discard when the pool fills up. Once there's a
+// base fee, transactions are sorted by the tip they'd effectively pay with it.
+type priceHeap struct {
+	baseFee *big.Int // Base fee of the next block, nil before the base fee fork
+	list    []*types.Transaction
+}
 
-func (h priceHeap) Len() int      { return len(h) }
-func (h priceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *priceHeap) Len() int      { return len(h.list) }
+func (h *priceHeap) Swap(i, j int) { h.list[i], h.list[j] = h.list[j], h.list[i] }
 
-func (h priceHeap) Less(i, j int) bool {
+func (h *priceHeap) Less(i, j int) bool {
 	// Sort primarily by price, returning the cheaper one
-	switch h[i].SmokePriceCmp(h[j]) {
+	switch h.cmp(h.list[i], h.list[j]) {
 	case -1:
 		return true
 	case 1:
 		return false
 	}
 	// If the prices match, stabilize via nonces (high nonce is worse)
-	return h[i].Nonce() > h[j].Nonce()
+	return h.list[i].Nonce() > h.list[j].Nonce()
+}
+
+// cmp compares the prices of two transactions, by effective tip first if there's
+// a base fee, then by fee cap and tip cap.
+func (h *priceHeap) cmp(a, b *types.Transaction) int {
+	if h.baseFee != nil {
+		if c := a.EffectiveSmokeTip(h.baseFee).Cmp(b.EffectiveSmokeTip(h.baseFee)); c != 0 {
+			return c
+		}
+	}
+	if c := a.SmokeFeeCap().Cmp(b.SmokeFeeCap()); c != 0 {
+		return c
+	}
+	return a.SmokeTipCap().Cmp(b.SmokeTipCap())
 }
 
 func (h *priceHeap) Push(x interface{}) {
-	*h = append(*h, x.(*types.Transaction))
+	h.list = append(h.list, x.(*types.Transaction))
 }
 
 func (h *priceHeap) Pop() interface{} {
-	old := *h
+	old := h.list
 	n := len(old)
 	x := old[n-1]
 	old[n-1] = nil
-	*h = old[0 : n-1]
+	h.list = old[0 : n-1]
 	return x
 }
 
@@ -469,34 +517,27 @@ func (l *txPricedList) Put(tx *types.Transaction, local bool) {
 func (l *txPricedList) Removed(count int) {
 	// Bump the stale counter, but exit if still too low (< 25%)
 	l.stales += count
-	if l.stales <= len(*l.remotes)/4 {
+	if l.stales <= l.remotes.Len()/4 {
 		return
 	}
 	// Seems we've reached a critical number of stale transactions, reheap
 	l.Reheap()
 }
 
-// Cap finds all the transactions below the given price threshold, drops them
-// from the priced list and returns them for further removal from the entire pool.
+// Cap finds all the remote transactions tipping below the given threshold and
+// returns them for further removal from the entire pool. The heap is sorted by
+// effective tip, which may be below the tip cap, so all transactions are checked
+// and the dropped ones are left in the heap as stale entries.
 //
 // Note: only remote transactions will be considered for eviction.
 func (l *txPricedList) Cap(threshold *big.Int) types.Transactions {
-	drop := make(types.Transactions, 0, 128) // Remote underpriced transactions to drop
-	for len(*l.remotes) > 0 {
-		// Discard stale transactions if found during cleanup
-		cheapest := (*l.remotes)[0]
-		if l.all.GetRemote(cheapest.Hash()) == nil { // Removed or migrated
-			heap.Pop(l.remotes)
-			l.stales--
-			continue
-		}
-		// Stop the discards if we've reached the threshold
-		if cheapest.SmokePriceIntCmp(threshold) >= 0 {
-			break
+	var drop types.Transactions // Remote underpriced transactions to drop
+	l.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
+		if tx.SmokeTipCap().Cmp(threshold) < 0 {
+			drop = append(drop, tx)
 		}
-		heap.Pop(l.remotes)
-		drop = append(drop, cheapest)
-	}
+		return true
+	}, false, true) // Only iterate remotes
 	return drop
 }
 
@@ -504,8 +545,8 @@ func (l *txPricedList) Cap(threshold *big.Int) types.Transactions {
 // lowest priced (remote) transaction currently being tracked.
 func (l *txPricedList) Underpriced(tx *types.Transaction) bool {
 	// Discard stale price points if found at the heap start
-	for len(*l.remotes) > 0 {
-		head := []*types.Transaction(*l.remotes)[0]
+	for l.remotes.Len() > 0 {
+		head := l.remotes.list[0]
 		if l.all.GetRemote(head.Hash()) == nil { // Removed or migrated
 			l.stales--
 			heap.Pop(l.remotes)
@@ -514,13 +555,12 @@ func (l *txPricedList) Underpriced(tx *types.Transaction) bool {
 		break
 	}
 	// Check if the transaction is underpriced or not
-	if len(*l.remotes) == 0 {
+	if l.remotes.Len() == 0 {
 		return false // There is no remote transaction at all.
 	}
 	// If the remote transaction is even cheaper than the
 	// cheapest one tracked locally, reject it.
-	cheapest := []*types.Transaction(*l.remotes)[0]
-	return cheapest.SmokePriceCmp(tx) >= 0
+	return l.remotes.cmp(l.remotes.list[0], tx) >= 0
 }
 
 // Discard finds a number of most underpriced transactions, removes them from the
@@ -529,7 +569,7 @@ func (l *txPricedList) Underpriced(tx *types.Transaction) bool {
 // Note local transaction won't be considered for eviction.
 func (l *txPricedList) Discard(slots int, force bool) (types.Transactions, bool) {
 	drop := make(types.Transactions, 0, slots) // Remote underpriced transactions to drop
-	for len(*l.remotes) > 0 && slots > 0 {
+	for l.remotes.Len() > 0 && slots > 0 {
 		// Discard stale transactions if found during cleanup
 		tx := heap.Pop(l.remotes).(*types.Transaction)
 		if l.all.GetRemote(tx.Hash()) == nil { // Removed or migrated
@@ -552,12 +592,24 @@ func (l *txPricedList) Discard(slots int, force bool) (types.Transactions, bool)
 
 // Reheap forcibly rebuilds the heap based on the current remote transaction set.
 func (l *txPricedList) Reheap() {
-	reheap := make(priceHeap, 0, l.all.RemoteCount())
-
-	l.stales, l.remotes = 0, &reheap
+	reheap := &priceHeap{
+		baseFee: l.remotes.baseFee,
+		list:    make([]*types.Transaction, 0, l.all.RemoteCount()),
+	}
+	l.stales, l.remotes = 0, reheap
 	l.all.Range(func(hash common.Hash, tx *types.Transaction, local bool) bool {
-		*l.remotes = append(*l.remotes, tx)
+		l.remotes.list = append(l.remotes.list, tx)
 		return true
 	}, false, true) // Only iterate remotes
 	heap.Init(l.remotes)
 }
+
+// SetBaseFee updates the base fee the transactions are sorted by, rebuilding the
+// heap if it changed.
+func (l *txPricedList) SetBaseFee(baseFee *big.Int) {
+	if l.remotes.baseFee == baseFee || (l.remotes.baseFee != nil && baseFee != nil && l.remotes.baseFee.Cmp(baseFee) == 0) {
+		return
+	}
+	l.remotes.baseFee = baseFee
+	l.Reheap()
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/metrics"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// txConcentrationSenders is the number of the most prolific senders whose share
+// of the pool is reported, a sudden rise of which is a telltale of spam.
+const txConcentrationSenders = 10
+
+var (
+	// Smoke price distribution of the transactions accepted into the pool, in maher
+	priceHistogram = metrics.NewRegisteredHistogram("txpool/price", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+	// Composition of the pool, refreshed on every stats report
+	pendingPriceBuckets = newTxPriceBuckets("txpool/pending/price")
+	queuedPriceBuckets  = newTxPriceBuckets("txpool/queued/price")
+
+	pendingSendersGauge = metrics.NewRegisteredGauge("txpool/pending/senders", nil)
+	queuedSendersGauge  = metrics.NewRegisteredGauge("txpool/queued/senders", nil)
+	pendingTopGauge     = metrics.NewRegisteredGauge(fmt.Sprintf("txpool/pending/top%dshare", txConcentrationSenders), nil) // Percentage of pending txs from the top senders
+	queuedTopGauge      = metrics.NewRegisteredGauge(fmt.Sprintf("txpool/queued/top%dshare", txConcentrationSenders), nil)  // Percentage of queued txs from the top senders
+)
+
+// txPriceBucketLimits are the upper smoke price bounds, in maher, of the buckets
+// transactions are counted in. Transactions priced above the last bound are
+// counted in an extra bucket.
+var txPriceBucketLimits = []uint64{1, 10, 100, 1000}
+
+// txPriceBuckets counts the transactions of a pool section per smoke price range.
+type txPriceBuckets struct {
+	limits []*big.Int
+	gauges []metrics.Gauge
+}
+
+// newTxPriceBuckets registers the gauges of the price buckets under the given
+// metrics prefix, named after the range they cover.
+func newTxPriceBuckets(prefix string) *txPriceBuckets {
+	buckets := &txPriceBuckets{
+		gauges: make([]metrics.Gauge, 0, len(txPriceBucketLimits)+1),
+	}
+	lower := uint64(0)
+	for _, limit := range txPriceBucketLimits {
+		buckets.limits = append(buckets.limits, new(big.Int).Mul(new(big.Int).SetUint64(limit), big.NewInt(params.Maher)))
+		buckets.gauges = append(buckets.gauges, metrics.NewRegisteredGauge(fmt.Sprintf("%s/%dto%d", prefix, lower, limit), nil))
+		lower = limit
+	}
+	buckets.gauges = append(buckets.gauges, metrics.NewRegisteredGauge(fmt.Sprintf("%s/over%d", prefix, lower), nil))
+	return buckets
+}
+
+// bucket returns the index of the bucket the smoke price of a transaction falls
+// into.
+func (b *txPriceBuckets) bucket(tx *types.Transaction) int {
+	return sort.Search(len(b.limits), func(i int) bool { return tx.SmokePriceIntCmp(b.limits[i]) < 0 })
+}
+
+// updateComposition reports the price distribution and the sender concentration
+// of the transactions in the given pool section.
+func (b *txPriceBuckets) updateComposition(section map[common.Address]*txList, senders, top metrics.Gauge) {
+	var (
+		counts = make([]int64, len(b.gauges))
+		sizes  = make([]int, 0, len(section))
+		total  int
+	)
+	for _, list := range section {
+		for _, tx := range list.txs.items {
+			counts[b.bucket(tx)]++
+		}
+		sizes = append(sizes, list.Len())
+		total += list.Len()
+	}
+	for i, gauge := range b.gauges {
+		gauge.Update(counts[i])
+	}
+	senders.Update(int64(len(sizes)))
+
+	if total == 0 {
+		top.Update(0)
+		return
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	if len(sizes) > txConcentrationSenders {
+		sizes = sizes[:txConcentrationSenders]
+	}
+	var held int
+	for _, size := range sizes {
+		held += size
+	}
+	top.Update(int64(held * 100 / total))
+}
+
+// updateCompositionMetrics refreshes the gauges describing the composition of
+// the pool. The caller must hold the pool lock.
+func (pool *TxPool) updateCompositionMetrics() {
+	if !metrics.Enabled {
+		return
+	}
+	pendingPriceBuckets.updateComposition(pool.pending, pendingSendersGauge, pendingTopGauge)
+	queuedPriceBuckets.updateComposition(pool.queue, queuedSendersGauge, queuedTopGauge)
+}
+
+// updatePriceHistogram records the smoke price of a transaction accepted into the
+// pool, in maher.
+func updatePriceHistogram(tx *types.Transaction) {
+	if !metrics.Enabled {
+		return
+	}
+	price := new(big.Int).Div(tx.SmokePrice(), big.NewInt(params.Maher))
+	if !price.IsInt64() {
+		return
+	}
+	priceHistogram.Update(price.Int64())
+}
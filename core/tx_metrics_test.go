@@ -0,0 +1,71 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/metrics"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that the composition of a pool section is counted in the right price
+// buckets and that the share of the top senders is reported.
+func TestTxPoolComposition(t *testing.T) {
+	buckets := &txPriceBuckets{}
+	for _, limit := range txPriceBucketLimits {
+		buckets.limits = append(buckets.limits, new(big.Int).Mul(new(big.Int).SetUint64(limit), big.NewInt(params.Maher)))
+		buckets.gauges = append(buckets.gauges, new(metrics.StandardGauge))
+	}
+	buckets.gauges = append(buckets.gauges, new(metrics.StandardGauge))
+
+	// Create a spammer with many cheap transactions and a few regular senders
+	section := make(map[common.Address]*txList)
+	spammer, _ := crypto.GenerateKey()
+	section[crypto.PubkeyToAddress(spammer.PublicKey)] = newTxList(true)
+	for i := uint64(0); i < 20; i++ {
+		section[crypto.PubkeyToAddress(spammer.PublicKey)].Add(pricedTransaction(i, 100000, big.NewInt(1), spammer), 0)
+	}
+	for i := 0; i < 20; i++ {
+		key, _ := crypto.GenerateKey()
+		list := newTxList(true)
+		list.Add(pricedTransaction(0, 100000, big.NewInt(5*params.Maher), key), 0)
+		section[crypto.PubkeyToAddress(key.PublicKey)] = list
+	}
+	var (
+		senders = new(metrics.StandardGauge)
+		top     = new(metrics.StandardGauge)
+	)
+	buckets.updateComposition(section, senders, top)
+
+	want := []int64{20, 20, 0, 0, 0}
+	for i, gauge := range buckets.gauges {
+		if have := gauge.Value(); have != want[i] {
+			t.Errorf("bucket %d: count mismatch: have %d, want %d", i, have, want[i])
+		}
+	}
+	if have := senders.Value(); have != 21 {
+		t.Errorf("senders mismatch: have %d, want 21", have)
+	}
+	// The spammer and the 9 next senders hold 29 of the 40 transactions
+	if have := top.Value(); have != 29*100/40 {
+		t.Errorf("top share mismatch: have %d, want %d", have, 29*100/40)
+	}
+}
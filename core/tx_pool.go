@@ -26,6 +26,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/prque"
+	"github.com/420integrated/go-420coin/consensus/misc"
 	"github.com/420integrated/go-420coin/core/state"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/event"
@@ -83,6 +84,18 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrTxTypeNotSupported is returned if a typed transaction is added to the pool
+	// before typed transactions are activated, or its type is unknown.
+	ErrTxTypeNotSupported = types.ErrTxTypeNotSupported
+
+	// ErrFeeAllowance is returned if the fees of all the pooled transactions of a
+	// remote sender would exceed the configured share of its balance.
+	ErrFeeAllowance = errors.New("pooled fees exceed balance allowance")
+
+	// ErrSenderRateLimit is returned if a remote sender submits transactions faster
+	// than the configured rate.
+	ErrSenderRateLimit = errors.New("sender rate limit exceeded")
 )
 
 var (
@@ -92,10 +105,11 @@ var (
 
 var (
 	// Metrics for the pending pool
-	pendingDiscardMeter   = metrics.NewRegisteredMeter("txpool/pending/discard", nil)
-	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
-	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
-	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingDiscardMeter     = metrics.NewRegisteredMeter("txpool/pending/discard", nil)
+	pendingReplaceMeter     = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
+	pendingRateLimitMeter   = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil)   // Dropped due to rate limiting
+	pendingNofundsMeter     = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)     // Dropped due to out-of-funds
+	pendingUnderpayingMeter = metrics.NewRegisteredMeter("txpool/pending/underpaying", nil) // Demoted due to the base fee
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
@@ -117,6 +131,14 @@ var (
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
 )
 
+// Reasons for a transaction leaving the pool, as reported by DroppedTxEvent.
+const (
+	TxDropReplaced    = "replaced"    // Superseded by a higher priced transaction
+	TxDropUnderpriced = "underpriced" // Priced out by better paying transactions
+	TxDropExpired     = "expired"     // Queued for longer than the lifetime allowance
+	TxDropOverflow    = "overflow"    // Evicted to keep the account within its slot allowance
+)
+
 // TxStatus is the current status of a transaction as seen by the pool.
 type TxStatus uint
 
@@ -153,6 +175,9 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	FeeRatio   uint64 // Maximum percentage of a remote sender's balance its pooled transactions may pay in fees (0 = unlimited)
+	SenderRate uint64 // Maximum number of transactions accepted from a remote sender per second (0 = unlimited)
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -211,6 +236,15 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 	return conf
 }
 
+// TxPoolSlots returns the number of transaction slots fitting into the given
+// memory allowance in megabytes.
+func TxPoolSlots(allowance int) uint64 {
+	if allowance <= 0 {
+		return 0
+	}
+	return uint64(allowance) * 1024 * 1024 / txSlotSize
+}
+
 // TxPool contains all currently known transactions. Transactions
 // enter the pool when they are received from the network or submitted
 // locally. They exit the pool when they are included in the blockchain.
@@ -224,15 +258,19 @@ type TxPool struct {
 	chain       blockChain
 	smokePrice    *big.Int
 	txFeed      event.Feed
+	dropFeed    event.Feed
 	scope       event.SubscriptionScope
 	signer      types.Signer
 	mu          sync.RWMutex
 
 	istanbul bool // Fork indicator if we are in the istanbul stage.
+	baseFee  bool // Fork indicator if we are in the smoke base fee stage.
+	eip2718  bool // Fork indicator if we accept typed transactions (YOLOv2).
 
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxSmoke uint64         // Current smoke limit for transaction caps
+	pendingBaseFee  *big.Int       // Base fee of the next block, nil before the fork
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -243,6 +281,10 @@ type TxPool struct {
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
 
+	allowances map[common.Address]*senderAllowance // Rate allowances of the remote senders
+
+	dropEvents []DroppedTxEvent // Drop notifications waiting for delivery
+
 	chainHeadCh     chan ChainHeadEvent
 	chainHeadSub    event.Subscription
 	reqResetCh      chan *txpoolResetRequest
@@ -272,6 +314,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
+		allowances:      make(map[common.Address]*senderAllowance),
 		all:             newTxLookup(),
 		chainHeadCh:     make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
@@ -351,6 +394,7 @@ func (pool *TxPool) loop() {
 			pool.mu.RLock()
 			pending, queued := pool.stats()
 			stales := pool.priced.stales
+			pool.updateCompositionMetrics()
 			pool.mu.RUnlock()
 
 			if pending != prevPending || queued != prevQueued || stales != prevStales {
@@ -371,11 +415,19 @@ func (pool *TxPool) loop() {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true)
+						pool.queueDropEvent(tx, nil, TxDropExpired)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
+			// Forget the rate allowances replenished in full
+			for addr, allowance := range pool.allowances {
+				if time.Since(allowance.last) > time.Second {
+					delete(pool.allowances, addr)
+				}
+			}
 			pool.mu.Unlock()
+			pool.flushDropEvents()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
@@ -411,6 +463,12 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTxEvent registers a subscription of DroppedTxEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDroppedTxEvent(ch chan<- DroppedTxEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // SmokePrice returns the current smoke price enforced by the transaction pool.
 func (pool *TxPool) SmokePrice() *big.Int {
 	pool.mu.RLock()
@@ -422,16 +480,59 @@ func (pool *TxPool) SmokePrice() *big.Int {
 // SetSmokePrice updates the minimum price required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetSmokePrice(price *big.Int) {
+	defer pool.flushDropEvents()
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
 	pool.smokePrice = price
-	for _, tx := range pool.priced.Cap(price) {
+	drop := pool.priced.Cap(price)
+	for _, tx := range drop {
 		pool.removeTx(tx.Hash(), false)
+		pool.queueDropEvent(tx, nil, TxDropUnderpriced)
 	}
+	pool.priced.Removed(len(drop))
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
+// Config returns a copy of the current configuration of the transaction pool.
+func (pool *TxPool) Config() TxPoolConfig {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	config := pool.config
+	config.Locals = append([]common.Address(nil), config.Locals...)
+	return config
+}
+
+// SetLimits updates the replacement price bump, the slot and queue limits and the
+// queue lifetime of the transaction pool to the ones in the given configuration,
+// ignoring all other fields. Invalid values are sanitized to the defaults. If the
+// pool exceeds the new limits, the surplus transactions are evicted on the next
+// reorg, which is requested here for all queued accounts. The applied
+// configuration is returned.
+func (pool *TxPool) SetLimits(limits TxPoolConfig) TxPoolConfig {
+	pool.mu.Lock()
+	config := pool.config
+	config.PriceBump = limits.PriceBump
+	config.AccountSlots, config.GlobalSlots = limits.AccountSlots, limits.GlobalSlots
+	config.AccountQueue, config.GlobalQueue = limits.AccountQueue, limits.GlobalQueue
+	config.Lifetime = limits.Lifetime
+	pool.config = (&config).sanitize()
+
+	accounts := newAccountSet(pool.signer)
+	for addr := range pool.queue {
+		accounts.add(addr)
+	}
+	config = pool.config
+	pool.mu.Unlock()
+
+	pool.requestPromoteExecutables(accounts)
+	log.Info("Transaction pool limits updated", "bump", config.PriceBump, "accountslots", config.AccountSlots,
+		"globalslots", config.GlobalSlots, "accountqueue", config.AccountQueue, "globalqueue", config.GlobalQueue, "lifetime", config.Lifetime)
+	return config
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (pool *TxPool) Nonce(addr common.Address) uint64 {
@@ -481,6 +582,23 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool, returning the
+// pending as well as queued transactions of this address, grouped by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var pending types.Transactions
+	if list, ok := pool.pending[addr]; ok {
+		pending = list.Flatten()
+	}
+	var queued types.Transactions
+	if list, ok := pool.queue[addr]; ok {
+		queued = list.Flatten()
+	}
+	return pending, queued
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -522,6 +640,10 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // validateTx checks if a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+	// Accept only legacy transactions until typed ones are activated
+	if !pool.eip2718 && tx.Type() != types.LegacyTxType {
+		return ErrTxTypeNotSupported
+	}
 	// Reject transactions over defined size to prevent DOS attacks
 	if uint64(tx.Size()) > txMaxSize {
 		return ErrOversizedData
@@ -535,13 +657,22 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentMaxSmoke < tx.Smoke() {
 		return ErrSmokeLimit
 	}
+	// Accept dynamic fee transactions only once activated, never tipping above the fee cap
+	if tx.IsDynamicFee() {
+		if !pool.baseFee {
+			return ErrDynamicFeeNotSupported
+		}
+		if tx.SmokeTipCap().Cmp(tx.SmokeFeeCap()) > 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
 	// Make sure the transaction is signed properly
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
-	// Drop non-local transactions under our own minimal accepted smoke price
-	if !local && tx.SmokePriceIntCmp(pool.smokePrice) < 0 {
+	// Drop non-local transactions tipping under our own minimal accepted smoke price
+	if !local && tx.SmokeTipCap().Cmp(pool.smokePrice) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -554,16 +685,83 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrInsufficientFunds
 	}
 	// Ensure the transaction has more smoke than the basic tx fee.
-	intrSmoke, err := IntrinsicSmoke(tx.Data(), tx.To() == nil, true, pool.istanbul)
+	intrSmoke, err := IntrinsicSmoke(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.istanbul)
 	if err != nil {
 		return err
 	}
 	if tx.Smoke() < intrSmoke {
 		return ErrIntrinsicSmoke
 	}
+	// Apply the optional spam policies to remote senders
+	if !local {
+		if err := pool.checkFeeAllowance(from, tx); err != nil {
+			return err
+		}
+		if !pool.allowSender(from) {
+			return ErrSenderRateLimit
+		}
+	}
+	return nil
+}
+
+// checkFeeAllowance ensures the fees of all the pooled transactions of a sender,
+// along with the given new one, stay within the configured share of its balance.
+// A pooled transaction with the same nonce is assumed to be replaced.
+func (pool *TxPool) checkFeeAllowance(from common.Address, tx *types.Transaction) error {
+	if pool.config.FeeRatio == 0 {
+		return nil
+	}
+	fees := new(big.Int).Sub(tx.Cost(), tx.Value())
+	for _, list := range []*txList{pool.pending[from], pool.queue[from]} {
+		if list == nil {
+			continue
+		}
+		for nonce, pooled := range list.txs.items {
+			if nonce != tx.Nonce() {
+				fees.Add(fees, new(big.Int).Sub(pooled.Cost(), pooled.Value()))
+			}
+		}
+	}
+	allowance := new(big.Int).Mul(pool.currentState.GetBalance(from), new(big.Int).SetUint64(pool.config.FeeRatio))
+	if fees.Mul(fees, big.NewInt(100)).Cmp(allowance) > 0 {
+		return ErrFeeAllowance
+	}
 	return nil
 }
 
+// senderAllowance is a token bucket limiting the rate of the transactions
+// accepted from a remote sender.
+type senderAllowance struct {
+	tokens float64   // Transactions currently allowed
+	last   time.Time // Time of the last refill
+}
+
+// allowSender reports whether another transaction from the given remote sender
+// fits into its rate allowance, consuming the allowance if so.
+func (pool *TxPool) allowSender(from common.Address) bool {
+	rate := float64(pool.config.SenderRate)
+	if rate == 0 {
+		return true
+	}
+	now := time.Now()
+	allowance := pool.allowances[from]
+	if allowance == nil {
+		allowance = &senderAllowance{tokens: rate, last: now}
+		pool.allowances[from] = allowance
+	}
+	allowance.tokens += now.Sub(allowance.last).Seconds() * rate
+	if allowance.tokens > rate {
+		allowance.tokens = rate
+	}
+	allowance.last = now
+
+	if allowance.tokens < 1 {
+		return false
+	}
+	allowance.tokens--
+	return true
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -589,6 +787,8 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	updatePriceHistogram(tx)
+
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Count()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
@@ -613,6 +813,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.SmokePrice())
 			underpricedTxMeter.Mark(1)
 			pool.removeTx(tx.Hash(), false)
+			pool.queueDropEvent(tx, nil, TxDropUnderpriced)
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
@@ -629,6 +830,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.queueDropEvent(old, tx, TxDropReplaced)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
@@ -680,6 +882,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.queueDropEvent(old, tx, TxDropReplaced)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -736,6 +939,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.queueDropEvent(old, tx, TxDropReplaced)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -1069,6 +1273,15 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 				delete(events, addr)
 			}
 		}
+		// Validate the pool of pending transactions against the new block. This will
+		// remove any transaction that has been included in the block or was invalidated
+		// because of another transaction (e.g. higher smoke price), and queue back the
+		// ones unable to pay the base fee. It's done before promoting, so the queued
+		// transactions continue from the restored pending nonces.
+		pool.demoteUnexecutables()
+		for addr, list := range pool.pending {
+			pool.pendingNonces.set(addr, list.LastElement().Nonce()+1)
+		}
 		// Reset needs promote for all addresses
 		promoteAddrs = make([]common.Address, 0, len(pool.queue))
 		for addr := range pool.queue {
@@ -1078,12 +1291,6 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// Check for pending transactions for every account that sent new ones
 	promoted := pool.promoteExecutables(promoteAddrs)
 
-	// If a new block appeared, validate the pool of pending transactions. This will
-	// remove any transaction that has been included in the block or was invalidated
-	// because of another transaction (e.g. higher smoke price).
-	if reset != nil {
-		pool.demoteUnexecutables()
-	}
 	// Ensure pool.queue and pool.pending sizes stay within the configured limits.
 	pool.truncatePending()
 	pool.truncateQueue()
@@ -1110,6 +1317,29 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		}
 		pool.txFeed.Send(NewTxsEvent{txs})
 	}
+	pool.flushDropEvents()
+}
+
+// queueDropEvent schedules a notification about a transaction leaving the pool,
+// to be delivered once the pool lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) queueDropEvent(tx *types.Transaction, replacement *types.Transaction, reason string) {
+	pool.dropEvents = append(pool.dropEvents, DroppedTxEvent{Tx: tx, Replacement: replacement, Reason: reason})
+}
+
+// flushDropEvents delivers all scheduled drop notifications to subscribers.
+//
+// Note, this method assumes the pool lock is not held!
+func (pool *TxPool) flushDropEvents() {
+	pool.mu.Lock()
+	events := pool.dropEvents
+	pool.dropEvents = nil
+	pool.mu.Unlock()
+
+	for _, ev := range events {
+		pool.dropFeed.Send(ev)
+	}
 }
 
 // reset retrieves the current state of the blockchain and ensures the content
@@ -1137,44 +1367,45 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 				// head from the chain.
 				// If that is the case, we don't have the lost transactions any more, and
 				// there's nothing to add
-				if newNum < oldNum {
-					// If the reorg ended up on a lower number, it's indicative of setHead being the cause
-					log.Debug("Skipping transaction reset caused by setHead",
-						"old", oldHead.Hash(), "oldnum", oldNum, "new", newHead.Hash(), "newnum", newNum)
-				} else {
+				if newNum >= oldNum {
 					// If we reorged to a same or higher number, then it's not a case of setHead
 					log.Warn("Transaction pool reset with missing oldhead",
 						"old", oldHead.Hash(), "oldnum", oldNum, "new", newHead.Hash(), "newnum", newNum)
-				}
-				return
-			}
-			for rem.NumberU64() > add.NumberU64() {
-				discarded = append(discarded, rem.Transactions()...)
-				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
 					return
 				}
-			}
-			for add.NumberU64() > rem.NumberU64() {
-				included = append(included, add.Transactions()...)
-				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
-					return
+				// If the reorg ended up on a lower number, it's indicative of setHead being the cause
+				log.Debug("Skipping transaction reset caused by setHead",
+					"old", oldHead.Hash(), "oldnum", oldNum, "new", newHead.Hash(), "newnum", newNum)
+				// We still need to update the current state s.th. the lost transactions can be readded by the user
+			} else {
+				for rem.NumberU64() > add.NumberU64() {
+					discarded = append(discarded, rem.Transactions()...)
+					if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
+						log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
+						return
+					}
 				}
-			}
-			for rem.Hash() != add.Hash() {
-				discarded = append(discarded, rem.Transactions()...)
-				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
-					log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
-					return
+				for add.NumberU64() > rem.NumberU64() {
+					included = append(included, add.Transactions()...)
+					if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
+						log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
+						return
+					}
 				}
-				included = append(included, add.Transactions()...)
-				if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
-					log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
-					return
+				for rem.Hash() != add.Hash() {
+					discarded = append(discarded, rem.Transactions()...)
+					if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
+						log.Error("Unrooted old chain seen by tx pool", "block", oldHead.Number, "hash", oldHead.Hash())
+						return
+					}
+					included = append(included, add.Transactions()...)
+					if add = pool.chain.GetBlock(add.ParentHash(), add.NumberU64()-1); add == nil {
+						log.Error("Unrooted new chain seen by tx pool", "block", newHead.Number, "hash", newHead.Hash())
+						return
+					}
 				}
+				reinject = types.TxDifference(discarded, included)
 			}
-			reinject = types.TxDifference(discarded, included)
 		}
 	}
 	// Initialize the internal state to the current head
@@ -1198,6 +1429,15 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
+	pool.eip2718 = pool.chainconfig.IsYoloV2(next)
+	pool.baseFee = pool.chainconfig.IsSmokeBaseFee(next)
+
+	// Sort the transactions by the tip they'd pay in the next block
+	pool.pendingBaseFee = nil
+	if pool.baseFee {
+		pool.pendingBaseFee = misc.CalcBaseFee(pool.chainconfig, newHead)
+	}
+	pool.priced.SetBaseFee(pool.pendingBaseFee)
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1229,8 +1469,8 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
-		// Gather all executable transactions and promote them
-		readies := list.Ready(pool.pendingNonces.get(addr))
+		// Gather all executable transactions able to pay the base fee and promote them
+		readies := list.Ready(pool.pendingNonces.get(addr), pool.pendingBaseFee)
 		for _, tx := range readies {
 			hash := tx.Hash()
 			if pool.promoteTx(addr, hash, tx) {
@@ -1312,6 +1552,7 @@ func (pool *TxPool) truncatePending() {
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
+						pool.queueDropEvent(tx, nil, TxDropOverflow)
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
 					pool.priced.Removed(len(caps))
@@ -1339,6 +1580,7 @@ func (pool *TxPool) truncatePending() {
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
+					pool.queueDropEvent(tx, nil, TxDropOverflow)
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
 				pool.priced.Removed(len(caps))
@@ -1383,6 +1625,7 @@ func (pool *TxPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true)
+				pool.queueDropEvent(tx, nil, TxDropOverflow)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1392,6 +1635,7 @@ func (pool *TxPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true)
+			pool.queueDropEvent(txs[i], nil, TxDropOverflow)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1434,6 +1678,20 @@ func (pool *TxPool) demoteUnexecutables() {
 		if pool.locals.contains(addr) {
 			localGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
 		}
+		// Queue back the transactions unable to pay the base fee of the next block,
+		// along with all the ones following them
+		if pool.pendingBaseFee != nil {
+			underpaying := list.Underpaying(pool.pendingBaseFee)
+			for _, tx := range underpaying {
+				hash := tx.Hash()
+				log.Trace("Demoting underpaying pending transaction", "hash", hash)
+
+				// Internal shuffle shouldn't touch the lookup set.
+				pool.enqueueTx(hash, tx, false, false)
+			}
+			pendingGauge.Dec(int64(len(underpaying)))
+			pendingUnderpayingMeter.Mark(int64(len(underpaying)))
+		}
 		// If there's a gap in front, alert (should never happen) and postpone all transactions
 		if list.Len() > 0 && list.txs.Get(nonce) == nil {
 			gapped := list.Cap(0)
@@ -70,6 +70,25 @@ func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) even
 	return bc.chainHeadFeed.Subscribe(ch)
 }
 
+// testBaseFeeChain is a test blockchain whose head carries a base fee. The head
+// uses exactly its smoke target, so the next block has the same base fee.
+type testBaseFeeChain struct {
+	*testBlockChain
+	baseFee *big.Int
+}
+
+func (bc *testBaseFeeChain) CurrentBlock() *types.Block {
+	return types.NewBlock(&types.Header{
+		SmokeLimit: bc.smokeLimit,
+		SmokeUsed:  bc.smokeLimit / params.ElasticityMultiplier,
+		BaseFee:    bc.baseFee,
+	}, nil, nil, nil, new(trie.Trie))
+}
+
+func (bc *testBaseFeeChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.CurrentBlock()
+}
+
 func transaction(nonce uint64, smokelimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
 	return pricedTransaction(nonce, smokelimit, big.NewInt(1), key)
 }
@@ -79,6 +98,11 @@ func pricedTransaction(nonce uint64, smokelimit uint64, smokeprice *big.Int, key
 	return tx
 }
 
+func dynamicFeeTransaction(nonce uint64, smokelimit uint64, feeCap, tipCap *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewDynamicFeeTransaction(nonce, &common.Address{}, big.NewInt(100), smokelimit, feeCap, tipCap, nil), types.HomesteadSigner{}, key)
+	return tx
+}
+
 func pricedDataTransaction(nonce uint64, smokelimit uint64, smokeprice *big.Int, key *ecdsa.PrivateKey, bytes uint64) *types.Transaction {
 	data := make([]byte, bytes)
 	rand.Read(data)
@@ -685,6 +709,39 @@ func TestTransactionPostponing(t *testing.T) {
 	}
 }
 
+// Tests that the content of a single account can be retrieved without the rest
+// of the pool.
+func TestTransactionContentFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	other, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000))
+
+	pool.AddRemotesSync([]*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(3, 100000, key),
+		transaction(0, 100000, other),
+	})
+	pending, queued := pool.ContentFrom(account)
+	if len(pending) != 2 || pending[0].Nonce() != 0 || pending[1].Nonce() != 1 {
+		t.Fatalf("pending transactions mismatch: have %d, want 2", len(pending))
+	}
+	if len(queued) != 1 || queued[0].Nonce() != 3 {
+		t.Fatalf("queued transactions mismatch: have %d, want 1", len(queued))
+	}
+	pending, queued = pool.ContentFrom(common.Address{})
+	if len(pending) != 0 || len(queued) != 0 {
+		t.Fatalf("unknown account content mismatch: have %d pending, %d queued", len(pending), len(queued))
+	}
+}
+
 // Tests that if the transaction pool has both executable and non-executable
 // transactions from an origin account, filling the nonce gap moves all queued
 // ones into the pending pool.
@@ -775,6 +832,121 @@ func TestTransactionQueueAccountLimiting(t *testing.T) {
 	}
 }
 
+// Tests that lowering the limits of a running pool evicts the surplus queued
+// transactions and that invalid limits are sanitized.
+func TestTransactionPoolSetLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(account, big.NewInt(1000000))
+
+	for i := uint64(1); i <= 10; i++ {
+		if err := pool.addRemoteSync(transaction(i, 100000, key)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	limits := pool.Config()
+	limits.AccountQueue = 4
+	limits.PriceBump = 0
+
+	config := pool.SetLimits(limits)
+	if config.AccountQueue != 4 {
+		t.Errorf("account queue mismatch: have %d, want %d", config.AccountQueue, 4)
+	}
+	if config.PriceBump != DefaultTxPoolConfig.PriceBump {
+		t.Errorf("price bump not sanitized: have %d, want %d", config.PriceBump, DefaultTxPoolConfig.PriceBump)
+	}
+	<-pool.requestReset(nil, nil)
+
+	if pool.queue[account].Len() != 4 {
+		t.Errorf("queue size mismatch: have %d, want %d", pool.queue[account].Len(), 4)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that remote transactions committing more fees than the configured share of
+// the sender's balance are rejected, unlike local ones.
+func TestTransactionFeeAllowance(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.FeeRatio = 100
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	// Fill the fee allowance of the sender up to its balance
+	for i := uint64(0); i < 10; i++ {
+		if err := pool.addRemoteSync(transaction(i, 100000, key)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if err := pool.addRemoteSync(transaction(10, 100000, key)); err != ErrFeeAllowance {
+		t.Fatalf("remote transaction above allowance error mismatch: have %v, want %v", err, ErrFeeAllowance)
+	}
+	// Replacements only count once, and locals are exempt
+	if err := pool.addRemoteSync(pricedTransaction(9, 100000, big.NewInt(2), key)); err != ErrFeeAllowance {
+		t.Fatalf("replacement above allowance error mismatch: have %v, want %v", err, ErrFeeAllowance)
+	}
+	if err := pool.addRemoteSync(transaction(9, 50000, key)); err != ErrReplaceUnderpriced {
+		t.Fatalf("replacement within allowance error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	if err := pool.AddLocal(transaction(10, 100000, key)); err != nil {
+		t.Fatalf("failed to add local transaction above allowance: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that remote senders submitting transactions faster than the configured
+// rate are throttled, unlike local ones.
+func TestTransactionSenderRate(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.SenderRate = 2
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	other, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000000))
+
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.addRemoteSync(transaction(i, 100000, key)); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if err := pool.addRemoteSync(transaction(2, 100000, key)); err != ErrSenderRateLimit {
+		t.Fatalf("throttled transaction error mismatch: have %v, want %v", err, ErrSenderRateLimit)
+	}
+	// Other senders have their own allowance, and locals are exempt
+	if err := pool.addRemoteSync(transaction(0, 100000, other)); err != nil {
+		t.Fatalf("failed to add transaction of other sender: %v", err)
+	}
+	if err := pool.AddLocal(transaction(2, 100000, key)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+}
+
 // Tests that if the transaction count belonging to multiple accounts go above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 //
@@ -1746,6 +1918,212 @@ func TestTransactionReplacement(t *testing.T) {
 	}
 }
 
+// Tests that once there's a base fee, replacing a transaction requires bumping
+// both its fee cap and its tip cap.
+func TestTransactionReplacementDynamicFee(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBaseFeeChain{&testBlockChain{statedb, 1000000, new(event.Feed)}, big.NewInt(100)}
+
+	config := *params.TestChainConfig
+	config.SmokeBaseFeeBlock = big.NewInt(0)
+
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.addRemoteSync(dynamicFeeTransaction(0, 100000, big.NewInt(200), big.NewInt(20), key)); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+	if err := pool.AddRemote(dynamicFeeTransaction(0, 100000, big.NewInt(400), big.NewInt(21), key)); err != ErrReplaceUnderpriced {
+		t.Fatalf("tip cap below bump replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	if err := pool.AddRemote(dynamicFeeTransaction(0, 100000, big.NewInt(219), big.NewInt(40), key)); err != ErrReplaceUnderpriced {
+		t.Fatalf("fee cap below bump replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	if err := pool.AddRemote(dynamicFeeTransaction(0, 100000, big.NewInt(220), big.NewInt(22), key)); err != nil {
+		t.Fatalf("failed to replace with both caps bumped: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that once there's a base fee, a full pool evicts the transactions paying
+// the lowest effective tip, not the ones with the lowest fee cap.
+func TestTransactionPoolUnderpricingEffectiveTip(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBaseFeeChain{&testBlockChain{statedb, 1000000, new(event.Feed)}, big.NewInt(100)}
+
+	config := *params.TestChainConfig
+	config.SmokeBaseFeeBlock = big.NewInt(0)
+
+	poolConfig := testTxPoolConfig
+	poolConfig.GlobalSlots = 2
+	poolConfig.GlobalQueue = 2
+
+	pool := NewTxPool(poolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	// Fill the pool, with the highest fee cap tipping the least
+	cheapest := dynamicFeeTransaction(0, 100000, big.NewInt(1000), big.NewInt(1), keys[0])
+	txs := types.Transactions{
+		cheapest,
+		dynamicFeeTransaction(0, 100000, big.NewInt(110), big.NewInt(10), keys[1]),
+		dynamicFeeTransaction(0, 100000, big.NewInt(120), big.NewInt(20), keys[2]),
+		dynamicFeeTransaction(0, 100000, big.NewInt(130), big.NewInt(30), keys[3]),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	// Ensure a transaction tipping as little as the cheapest one is rejected, and
+	// one tipping more evicts it despite its lower fee cap
+	if err := pool.AddRemote(dynamicFeeTransaction(0, 100000, big.NewInt(1000), big.NewInt(1), keys[4])); err != ErrUnderpriced {
+		t.Fatalf("adding underpriced transaction error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTransaction(0, 100000, big.NewInt(105), big.NewInt(5), keys[4])); err != nil {
+		t.Fatalf("failed to add better tipping transaction: %v", err)
+	}
+	if pool.Get(cheapest.Hash()) != nil {
+		t.Fatalf("lowest tipping transaction not evicted")
+	}
+	if pending, queued := pool.Stats(); pending != 4 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 4/0", pending, queued)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that pending transactions unable to pay the base fee of the next block
+// are queued back on a new head, along with the ones following them, and only
+// get promoted again once the base fee drops.
+func TestTransactionPoolBaseFeeDemotion(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBaseFeeChain{&testBlockChain{statedb, 1000000, new(event.Feed)}, big.NewInt(100)}
+
+	config := *params.TestChainConfig
+	config.SmokeBaseFeeBlock = big.NewInt(0)
+
+	pool := NewTxPool(testTxPoolConfig, &config, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	txs := types.Transactions{
+		dynamicFeeTransaction(0, 100000, big.NewInt(200), big.NewInt(1), key),
+		dynamicFeeTransaction(1, 100000, big.NewInt(150), big.NewInt(1), key),
+		dynamicFeeTransaction(2, 100000, big.NewInt(300), big.NewInt(1), key),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	check := func(step string, wantPending, wantQueued int) {
+		t.Helper()
+		if pending, queued := pool.Stats(); pending != wantPending || queued != wantQueued {
+			t.Fatalf("%s: pool stats mismatch: have %d/%d, want %d/%d", step, pending, queued, wantPending, wantQueued)
+		}
+		if err := validateTxPoolInternals(pool); err != nil {
+			t.Fatalf("%s: pool internal state corrupted: %v", step, err)
+		}
+	}
+	check("initial", 3, 0)
+
+	// Raise the base fee above the second fee cap, demoting it and the third one
+	blockchain.baseFee = big.NewInt(160)
+	<-pool.requestReset(nil, nil)
+	check("raised", 1, 2)
+
+	// A new head at the same base fee must not promote them again
+	<-pool.requestReset(nil, nil)
+	check("unchanged", 1, 2)
+
+	// Lowering the base fee makes them executable again
+	blockchain.baseFee = big.NewInt(100)
+	<-pool.requestReset(nil, nil)
+	check("lowered", 3, 0)
+}
+
+// Tests that transactions leaving the pool without being included are announced
+// to drop subscribers, along with the reason and the replacement if any.
+func TestTransactionDropEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	drops := make(chan DroppedTxEvent, 32)
+	sub := pool.SubscribeDroppedTxEvent(drops)
+	defer sub.Unsubscribe()
+
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	// expect waits for the given drop notifications, in any order
+	expect := func(want ...DroppedTxEvent) {
+		t.Helper()
+		for i := 0; i < len(want); i++ {
+			select {
+			case ev := <-drops:
+				found := false
+				for _, w := range want {
+					if ev.Tx == w.Tx && ev.Replacement == w.Replacement && ev.Reason == w.Reason {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("unexpected drop event: tx %x, replacement %v, reason %s", ev.Tx.Hash(), ev.Replacement, ev.Reason)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("drop event %d/%d not fired", i+1, len(want))
+			}
+		}
+		select {
+		case ev := <-drops:
+			t.Fatalf("surplus drop event: tx %x, reason %s", ev.Tx.Hash(), ev.Reason)
+		default:
+		}
+	}
+	// Replacing a pending and a queued transaction must report both originals
+	pending, pendingBump := pricedTransaction(0, 100000, big.NewInt(1), key), pricedTransaction(0, 100000, big.NewInt(2), key)
+	queued, queuedBump := pricedTransaction(2, 100000, big.NewInt(1), key), pricedTransaction(2, 100000, big.NewInt(2), key)
+
+	for _, tx := range []*types.Transaction{pending, pendingBump, queued, queuedBump} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction %x: %v", tx.Hash(), err)
+		}
+	}
+	expect(
+		DroppedTxEvent{Tx: pending, Replacement: pendingBump, Reason: TxDropReplaced},
+		DroppedTxEvent{Tx: queued, Replacement: queuedBump, Reason: TxDropReplaced},
+	)
+	// Raising the price floor must report everything priced out of the pool
+	pool.SetSmokePrice(big.NewInt(3))
+	expect(
+		DroppedTxEvent{Tx: pendingBump, Reason: TxDropUnderpriced},
+		DroppedTxEvent{Tx: queuedBump, Reason: TxDropUnderpriced},
+	)
+	if pending, queued := pool.Stats(); pending != 0 || queued != 0 {
+		t.Fatalf("transactions left in the pool: pending %d, queued %d", pending, queued)
+	}
+}
+
 // Tests that local transactions are journaled to disk, but remote transactions
 // get discarded between restarts.
 func TestTransactionJournaling(t *testing.T)         { testTransactionJournaling(t, false) }
@@ -78,6 +78,23 @@ func TestUncleHash(t *testing.T) {
 	}
 }
 
+func TestHeaderSanityCheck(t *testing.T) {
+	tests := []struct {
+		header *Header
+		fail   bool
+	}{
+		{&Header{Number: big.NewInt(1), Difficulty: big.NewInt(131072)}, false},
+		{&Header{Number: new(big.Int).Lsh(big.NewInt(1), 64), Difficulty: big.NewInt(1)}, true},
+		{&Header{Number: big.NewInt(1), Difficulty: new(big.Int).Lsh(big.NewInt(1), 80)}, true},
+		{&Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Extra: make([]byte, 100*1024+1)}, true},
+	}
+	for i, tt := range tests {
+		if err := tt.header.SanityCheck(); (err != nil) != tt.fail {
+			t.Errorf("test %d: sanity check error mismatch: have %v, want failure %v", i, err, tt.fail)
+		}
+	}
+}
+
 var benchBuffer = bytes.NewBuffer(make([]byte, 0, 32000))
 
 func BenchmarkEncodeBlock(b *testing.B) {
@@ -156,3 +173,48 @@ func makeBenchBlock() *Block {
 	}
 	return NewBlock(header, txs, uncles, receipts, newHasher())
 }
+
+// Tests that the base fee of a header survives an RLP round trip, while legacy
+// headers without it keep their encoding, and thus their hash.
+func TestHeaderBaseFeeEncoding(t *testing.T) {
+	legacy := &Header{
+		Difficulty: big.NewInt(131072),
+		Number:     big.NewInt(1),
+		SmokeLimit: 3141592,
+		Extra:      []byte("legacy"),
+	}
+	legacyEnc, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatalf("failed to encode legacy header: %v", err)
+	}
+	var decoded Header
+	if err := rlp.DecodeBytes(legacyEnc, &decoded); err != nil {
+		t.Fatalf("failed to decode legacy header: %v", err)
+	}
+	if decoded.BaseFee != nil {
+		t.Errorf("legacy header decoded with base fee %v", decoded.BaseFee)
+	}
+	if decoded.Hash() != legacy.Hash() {
+		t.Errorf("legacy header hash mismatch: have %x, want %x", decoded.Hash(), legacy.Hash())
+	}
+	withFee := CopyHeader(legacy)
+	withFee.BaseFee = big.NewInt(params.Maher)
+
+	feeEnc, err := rlp.EncodeToBytes(withFee)
+	if err != nil {
+		t.Fatalf("failed to encode header with base fee: %v", err)
+	}
+	if bytes.Equal(feeEnc, legacyEnc) {
+		t.Fatalf("base fee not encoded")
+	}
+	decoded = Header{}
+	if err := rlp.DecodeBytes(feeEnc, &decoded); err != nil {
+		t.Fatalf("failed to decode header with base fee: %v", err)
+	}
+	if decoded.BaseFee == nil || decoded.BaseFee.Cmp(withFee.BaseFee) != 0 {
+		t.Errorf("base fee mismatch: have %v, want %v", decoded.BaseFee, withFee.BaseFee)
+	}
+	if decoded.Hash() != withFee.Hash() {
+		t.Errorf("header hash mismatch: have %x, want %x", decoded.Hash(), withFee.Hash())
+	}
+}
@@ -16,6 +16,7 @@ var _ = (*receiptMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (r Receipt) MarshalJSON() ([]byte, error) {
 	type Receipt struct {
+		Type              hexutil.Uint64 `json:"type,omitempty"`
 		PostState         hexutil.Bytes  `json:"root"`
 		Status            hexutil.Uint64 `json:"status"`
 		CumulativeSmokeUsed hexutil.Uint64 `json:"cumulativeSmokeUsed" gencodec:"required"`
@@ -24,11 +25,15 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 		TxHash            common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   common.Address `json:"contractAddress"`
 		SmokeUsed           hexutil.Uint64 `json:"smokeUsed" gencodec:"required"`
+		EffectiveSmokePrice *hexutil.Big    `json:"effectiveSmokePrice,omitempty"`
+		SmokeRefund         *hexutil.Uint64 `json:"smokeRefund,omitempty"`
+		SmokeReturned       hexutil.Uint64  `json:"smokeReturned"`
 		BlockHash         common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber       *hexutil.Big   `json:"blockNumber,omitempty"`
 		TransactionIndex  hexutil.Uint   `json:"transactionIndex"`
 	}
 	var enc Receipt
+	enc.Type = hexutil.Uint64(r.Type)
 	enc.PostState = r.PostState
 	enc.Status = hexutil.Uint64(r.Status)
 	enc.CumulativeSmokeUsed = hexutil.Uint64(r.CumulativeSmokeUsed)
@@ -37,6 +42,9 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	enc.TxHash = r.TxHash
 	enc.ContractAddress = r.ContractAddress
 	enc.SmokeUsed = hexutil.Uint64(r.SmokeUsed)
+	enc.EffectiveSmokePrice = (*hexutil.Big)(r.EffectiveSmokePrice)
+	enc.SmokeRefund = (*hexutil.Uint64)(r.SmokeRefund)
+	enc.SmokeReturned = hexutil.Uint64(r.SmokeReturned)
 	enc.BlockHash = r.BlockHash
 	enc.BlockNumber = (*hexutil.Big)(r.BlockNumber)
 	enc.TransactionIndex = hexutil.Uint(r.TransactionIndex)
@@ -46,6 +54,7 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (r *Receipt) UnmarshalJSON(input []byte) error {
 	type Receipt struct {
+		Type              *hexutil.Uint64 `json:"type,omitempty"`
 		PostState         *hexutil.Bytes  `json:"root"`
 		Status            *hexutil.Uint64 `json:"status"`
 		CumulativeSmokeUsed *hexutil.Uint64 `json:"cumulativeSmokeUsed" gencodec:"required"`
@@ -54,6 +63,9 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		TxHash            *common.Hash    `json:"transactionHash" gencodec:"required"`
 		ContractAddress   *common.Address `json:"contractAddress"`
 		SmokeUsed           *hexutil.Uint64 `json:"smokeUsed" gencodec:"required"`
+		EffectiveSmokePrice *hexutil.Big    `json:"effectiveSmokePrice,omitempty"`
+		SmokeRefund         *hexutil.Uint64 `json:"smokeRefund,omitempty"`
+		SmokeReturned       *hexutil.Uint64 `json:"smokeReturned"`
 		BlockHash         *common.Hash    `json:"blockHash,omitempty"`
 		BlockNumber       *hexutil.Big    `json:"blockNumber,omitempty"`
 		TransactionIndex  *hexutil.Uint   `json:"transactionIndex"`
@@ -62,6 +74,9 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 	if err := json.Unmarshal(input, &dec); err != nil {
 		return err
 	}
+	if dec.Type != nil {
+		r.Type = uint8(*dec.Type)
+	}
 	if dec.PostState != nil {
 		r.PostState = *dec.PostState
 	}
@@ -91,6 +106,15 @@ func (r *Receipt) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'smokeUsed' for Receipt")
 	}
 	r.SmokeUsed = uint64(*dec.SmokeUsed)
+	if dec.EffectiveSmokePrice != nil {
+		r.EffectiveSmokePrice = (*big.Int)(dec.EffectiveSmokePrice)
+	}
+	if dec.SmokeRefund != nil {
+		r.SmokeRefund = (*uint64)(dec.SmokeRefund)
+	}
+	if dec.SmokeReturned != nil {
+		r.SmokeReturned = uint64(*dec.SmokeReturned)
+	}
 	if dec.BlockHash != nil {
 		r.BlockHash = *dec.BlockHash
 	}
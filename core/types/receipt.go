@@ -38,6 +38,8 @@ var (
 	receiptStatusSuccessfulRLP = []byte{0x01}
 )
 
+var errEmptyTypedReceipt = errors.New("empty typed receipt bytes")
+
 const (
 	// ReceiptStatusFailed is the status code of a transaction if execution failed.
 	ReceiptStatusFailed = uint64(0)
@@ -49,6 +51,7 @@ const (
 // Receipt represents the results of a transaction.
 type Receipt struct {
 	// Consensus fields: These fields are defined by the Yellow Paper
+	Type              uint8  `json:"type,omitempty"`
 	PostState         []byte `json:"root"`
 	Status            uint64 `json:"status"`
 	CumulativeSmokeUsed uint64 `json:"cumulativeSmokeUsed" gencodec:"required"`
@@ -61,6 +64,13 @@ type Receipt struct {
 	ContractAddress common.Address `json:"contractAddress"`
 	SmokeUsed         uint64         `json:"smokeUsed" gencodec:"required"`
 
+	// Smoke accounting information: These fields break down the fee payment of the
+	// transaction. The refund is stored in the chain database since version 9 and
+	// is nil for older receipts, the others are derived from the transaction.
+	EffectiveSmokePrice *big.Int `json:"effectiveSmokePrice,omitempty"`
+	SmokeRefund         *uint64  `json:"smokeRefund,omitempty"`
+	SmokeReturned       uint64   `json:"smokeReturned"`
+
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
 	BlockHash        common.Hash `json:"blockHash,omitempty"`
@@ -69,14 +79,20 @@ type Receipt struct {
 }
 
 type receiptMarshaling struct {
+	Type              hexutil.Uint64
 	PostState         hexutil.Bytes
 	Status            hexutil.Uint64
 	CumulativeSmokeUsed hexutil.Uint64
 	SmokeUsed           hexutil.Uint64
+	EffectiveSmokePrice *hexutil.Big
+	SmokeRefund         *hexutil.Uint64
+	SmokeReturned       hexutil.Uint64
 	BlockNumber       *hexutil.Big
 	TransactionIndex  hexutil.Uint
 }
 
+//go:generate go run ../../rlp/rlpgen -type receiptRLP -out gen_receipt_rlp.go
+
 // receiptRLP is the consensus encoding of a receipt.
 type receiptRLP struct {
 	PostStateOrStatus []byte
@@ -87,9 +103,18 @@ type receiptRLP struct {
 
 // storedReceiptRLP is the storage encoding of a receipt.
 type storedReceiptRLP struct {
-	PostStateOrStatus []byte
+	PostStateOrStatus   []byte
 	CumulativeSmokeUsed uint64
-	Logs              []*LogForStorage
+	Logs                []*LogForStorage
+	SmokeRefund         uint64
+}
+
+// v8StoredReceiptRLP is the storage encoding of a receipt used up to database
+// version 8, lacking the smoke refund.
+type v8StoredReceiptRLP struct {
+	PostStateOrStatus   []byte
+	CumulativeSmokeUsed uint64
+	Logs                []*LogForStorage
 }
 
 // v4StoredReceiptRLP is the storage encoding of a receipt used in database version 4.
@@ -126,17 +151,51 @@ func NewReceipt(root []byte, failed bool, cumulativeSmokeUsed uint64) *Receipt {
 
 // EncodeRLP implements rlp.Encoder, and flattens the consensus fields of a receipt
 // into an RLP stream. If no post state is present, byzantium fork is assumed.
+// Receipts of typed transactions are encoded as an RLP string holding their
+// envelope.
 func (r *Receipt) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &receiptRLP{r.statusEncoding(), r.CumulativeSmokeUsed, r.Bloom, r.Logs})
+	enc := &receiptRLP{r.statusEncoding(), r.CumulativeSmokeUsed, r.Bloom, r.Logs}
+	if r.Type == LegacyTxType {
+		return enc.EncodeRLP(w)
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte(r.Type)
+	if err := enc.EncodeRLP(buf); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
 }
 
 // DecodeRLP implements rlp.Decoder, and loads the consensus fields of a receipt
 // from an RLP stream.
 func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
-	var dec receiptRLP
-	if err := s.Decode(&dec); err != nil {
+	kind, _, err := s.Kind()
+	if err != nil {
 		return err
 	}
+	var dec receiptRLP
+	if kind == rlp.List {
+		if err := dec.DecodeRLP(s); err != nil {
+			return err
+		}
+		r.Type = LegacyTxType
+	} else {
+		// Receipts of typed transactions are enveloped like them
+		b, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		if len(b) == 0 {
+			return errEmptyTypedReceipt
+		}
+		if b[0] != AccessListTxType {
+			return ErrTxTypeNotSupported
+		}
+		if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
+			return err
+		}
+		r.Type = b[0]
+	}
 	if err := r.setStatus(dec.PostStateOrStatus); err != nil {
 		return err
 	}
@@ -185,17 +244,26 @@ func (r *Receipt) Size() common.StorageSize {
 type ReceiptForStorage Receipt
 
 // EncodeRLP implements rlp.Encoder, and flattens all content fields of a receipt
-// into an RLP stream.
+// into an RLP stream. Receipts with an unknown smoke refund are stored in the
+// legacy format so they aren't reported with a fake zero refund.
 func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
-	enc := &storedReceiptRLP{
-		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
-		CumulativeSmokeUsed: r.CumulativeSmokeUsed,
-		Logs:              make([]*LogForStorage, len(r.Logs)),
-	}
+	logs := make([]*LogForStorage, len(r.Logs))
 	for i, log := range r.Logs {
-		enc.Logs[i] = (*LogForStorage)(log)
+		logs[i] = (*LogForStorage)(log)
+	}
+	if r.SmokeRefund == nil {
+		return rlp.Encode(w, &v8StoredReceiptRLP{
+			PostStateOrStatus:   (*Receipt)(r).statusEncoding(),
+			CumulativeSmokeUsed: r.CumulativeSmokeUsed,
+			Logs:                logs,
+		})
 	}
-	return rlp.Encode(w, enc)
+	return rlp.Encode(w, &storedReceiptRLP{
+		PostStateOrStatus:   (*Receipt)(r).statusEncoding(),
+		CumulativeSmokeUsed: r.CumulativeSmokeUsed,
+		Logs:                logs,
+		SmokeRefund:         *r.SmokeRefund,
+	})
 }
 
 // DecodeRLP implements rlp.Decoder, and loads both consensus and implementation
@@ -212,6 +280,9 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	if err := decodeStoredReceiptRLP(r, blob); err == nil {
 		return nil
 	}
+	if err := decodeV8StoredReceiptRLP(r, blob); err == nil {
+		return nil
+	}
 	if err := decodeV3StoredReceiptRLP(r, blob); err == nil {
 		return nil
 	}
@@ -227,6 +298,25 @@ func decodeStoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
 		return err
 	}
 	r.CumulativeSmokeUsed = stored.CumulativeSmokeUsed
+	r.SmokeRefund = &stored.SmokeRefund
+	r.Logs = make([]*Log, len(stored.Logs))
+	for i, log := range stored.Logs {
+		r.Logs[i] = (*Log)(log)
+	}
+	r.Bloom = CreateBloom(Receipts{(*Receipt)(r)})
+
+	return nil
+}
+
+func decodeV8StoredReceiptRLP(r *ReceiptForStorage, blob []byte) error {
+	var stored v8StoredReceiptRLP
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return err
+	}
+	if err := (*Receipt)(r).setStatus(stored.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.CumulativeSmokeUsed = stored.CumulativeSmokeUsed
 	r.Logs = make([]*Log, len(stored.Logs))
 	for i, log := range stored.Logs {
 		r.Logs[i] = (*Log)(log)
@@ -283,18 +373,23 @@ type Receipts []*Receipt
 // Len returns the number of receipts in this list.
 func (r Receipts) Len() int { return len(r) }
 
-// GetRlp returns the RLP encoding of one receipt from the list.
+// GetRlp returns the canonical encoding of one receipt from the list, which is
+// RLP for receipts of legacy transactions, and the bare envelope for typed ones.
 func (r Receipts) GetRlp(i int) []byte {
-	bytes, err := rlp.EncodeToBytes(r[i])
+	enc := &receiptRLP{r[i].statusEncoding(), r[i].CumulativeSmokeUsed, r[i].Bloom, r[i].Logs}
+	bytes, err := rlp.EncodeToBytes(enc)
 	if err != nil {
 		panic(err)
 	}
+	if r[i].Type != LegacyTxType {
+		bytes = append([]byte{r[i].Type}, bytes...)
+	}
 	return bytes
 }
 
 // DeriveFields fills the receipts with their computed fields based on consensus
 // data and contextual infos like containing block and transactions.
-func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, number uint64, txs Transactions) error {
+func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, number uint64, baseFee *big.Int, txs Transactions) error {
 	signer := MakeSigner(config, new(big.Int).SetUint64(number))
 
 	logIndex := uint(0)
@@ -302,7 +397,8 @@ func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, num
 		return errors.New("transaction and receipt count mismatch")
 	}
 	for i := 0; i < len(r); i++ {
-		// The transaction hash can be retrieved from the transaction itself
+		// The transaction type and hash can be retrieved from the transaction itself
+		r[i].Type = txs[i].Type()
 		r[i].TxHash = txs[i].Hash()
 
 		// block location fields
@@ -322,6 +418,9 @@ func (r Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, num
 		} else {
 			r[i].SmokeUsed = r[i].CumulativeSmokeUsed - r[i-1].CumulativeSmokeUsed
 		}
+		// The smoke price and the smoke returned to the pool follow from the transaction
+		r[i].EffectiveSmokePrice = txs[i].EffectiveSmokePrice(baseFee)
+		r[i].SmokeReturned = txs[i].Smoke() - r[i].SmokeUsed
 		// The derived log fields can simply be set from the block and transaction
 		for j := 0; j < len(r[i].Logs); j++ {
 			r[i].Logs[j].BlockNumber = number
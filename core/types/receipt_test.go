@@ -38,6 +38,10 @@ func TestLegacyReceiptDecoding(t *testing.T) {
 			"StoredReceiptRLP",
 			encodeAsStoredReceiptRLP,
 		},
+		{
+			"V8StoredReceiptRLP",
+			encodeAsV8StoredReceiptRLP,
+		},
 		{
 			"V4StoredReceiptRLP",
 			encodeAsV4StoredReceiptRLP,
@@ -120,6 +124,49 @@ func encodeAsStoredReceiptRLP(want *Receipt) ([]byte, error) {
 	return rlp.EncodeToBytes(stored)
 }
 
+func encodeAsV8StoredReceiptRLP(want *Receipt) ([]byte, error) {
+	stored := &v8StoredReceiptRLP{
+		PostStateOrStatus:   want.statusEncoding(),
+		CumulativeSmokeUsed: want.CumulativeSmokeUsed,
+		Logs:                make([]*LogForStorage, len(want.Logs)),
+	}
+	for i, log := range want.Logs {
+		stored.Logs[i] = (*LogForStorage)(log)
+	}
+	return rlp.EncodeToBytes(stored)
+}
+
+// Tests that the smoke refund survives the storage encoding, and that receipts
+// with an unknown refund aren't decoded with a zero one.
+func TestReceiptSmokeRefundStorage(t *testing.T) {
+	refund := uint64(4800)
+	for _, want := range []*uint64{&refund, nil} {
+		receipt := &Receipt{
+			Status:              ReceiptStatusSuccessful,
+			CumulativeSmokeUsed: 21000,
+			Logs:                []*Log{},
+			SmokeRefund:         want,
+		}
+		enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+		if err != nil {
+			t.Fatalf("Error encoding receipt: %v", err)
+		}
+		var dec ReceiptForStorage
+		if err := rlp.DecodeBytes(enc, &dec); err != nil {
+			t.Fatalf("Error decoding RLP receipt: %v", err)
+		}
+		switch {
+		case want == nil && dec.SmokeRefund != nil:
+			t.Errorf("Receipt SmokeRefund mismatch, want <nil>, have %d", *dec.SmokeRefund)
+		case want != nil && (dec.SmokeRefund == nil || *dec.SmokeRefund != *want):
+			t.Errorf("Receipt SmokeRefund mismatch, want %d, have %v", *want, dec.SmokeRefund)
+		}
+		if dec.Status != receipt.Status || dec.CumulativeSmokeUsed != receipt.CumulativeSmokeUsed {
+			t.Errorf("Receipt consensus fields mismatch, want %v/%d, have %v/%d", receipt.Status, receipt.CumulativeSmokeUsed, dec.Status, dec.CumulativeSmokeUsed)
+		}
+	}
+}
+
 func encodeAsV4StoredReceiptRLP(want *Receipt) ([]byte, error) {
 	stored := &v4StoredReceiptRLP{
 		PostStateOrStatus: want.statusEncoding(),
@@ -186,9 +233,10 @@ func TestDeriveFields(t *testing.T) {
 	// Clear all the computed fields and re-derive them
 	number := big.NewInt(1)
 	hash := common.BytesToHash([]byte{0x03, 0x14})
+	baseFee := big.NewInt(1)
 
 	clearComputedFieldsOnReceipts(t, receipts)
-	if err := receipts.DeriveFields(params.TestChainConfig, hash, number.Uint64(), txs); err != nil {
+	if err := receipts.DeriveFields(params.TestChainConfig, hash, number.Uint64(), baseFee, txs); err != nil {
 		t.Fatalf("DeriveFields(...) = %v, want <nil>", err)
 	}
 	// Iterate over all the computed fields and check that they're correct
@@ -211,6 +259,12 @@ func TestDeriveFields(t *testing.T) {
 		if receipts[i].SmokeUsed != txs[i].Smoke() {
 			t.Errorf("receipts[%d].SmokeUsed = %d, want %d", i, receipts[i].SmokeUsed, txs[i].Smoke())
 		}
+		if want := txs[i].EffectiveSmokePrice(baseFee); receipts[i].EffectiveSmokePrice.Cmp(want) != 0 {
+			t.Errorf("receipts[%d].EffectiveSmokePrice = %v, want %v", i, receipts[i].EffectiveSmokePrice, want)
+		}
+		if receipts[i].SmokeReturned != 0 {
+			t.Errorf("receipts[%d].SmokeReturned = %d, want 0", i, receipts[i].SmokeReturned)
+		}
 		if txs[i].To() != nil && receipts[i].ContractAddress != (common.Address{}) {
 			t.Errorf("receipts[%d].ContractAddress = %s, want %s", i, receipts[i].ContractAddress.String(), (common.Address{}).String())
 		}
@@ -260,6 +314,8 @@ func clearComputedFieldsOnReceipt(t *testing.T, receipt *Receipt) {
 	receipt.TransactionIndex = math.MaxUint32
 	receipt.ContractAddress = common.Address{}
 	receipt.SmokeUsed = 0
+	receipt.EffectiveSmokePrice = nil
+	receipt.SmokeReturned = math.MaxUint32
 
 	clearComputedFieldsOnLogs(t, receipt.Logs)
 }
@@ -31,9 +31,18 @@ import (
 )
 
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
+//go:generate go run ../../rlp/rlpgen -type txdata -out gen_tx_rlp.go
 
 var (
-	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSig         = errors.New("invalid transaction v, r, s values")
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	errEmptyTypedTx       = errors.New("empty typed transaction bytes")
+)
+
+// Transaction types, as defined by the EIP-2718 envelope.
+const (
+	LegacyTxType     = iota // Untyped RLP list, not wrapped in an envelope
+	AccessListTxType        // EIP-2930 transaction with an access list
 )
 
 type Transaction struct {
@@ -59,6 +68,16 @@ type txdata struct {
 	R *big.Int `json:"r" gencodec:"required"`
 	S *big.Int `json:"s" gencodec:"required"`
 
+	// TipCap turns the transaction into a dynamic fee one, with Price acting as
+	// the fee cap. Legacy transactions pay their full smoke price as tip.
+	TipCap *big.Int `json:"maxPriorityFeePerSmoke,omitempty" rlp:"optional"`
+
+	// Typed transaction fields, not part of the legacy encoding. Typed transactions
+	// are encoded as their type byte followed by a type specific payload.
+	Type       uint8      `json:"type"                 rlp:"-"`
+	ChainID    *big.Int   `json:"chainId,omitempty"    rlp:"-"`
+	AccessList AccessList `json:"accessList,omitempty" rlp:"-"`
+
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
@@ -72,6 +91,9 @@ type txdataMarshaling struct {
 	V            *hexutil.Big
 	R            *hexutil.Big
 	S            *hexutil.Big
+	TipCap       *hexutil.Big
+	Type         hexutil.Uint64
+	ChainID      *hexutil.Big
 }
 
 func NewTransaction(nonce uint64, to common.Address, amount *big.Int, smokeLimit uint64, smokePrice *big.Int, data []byte) *Transaction {
@@ -82,6 +104,32 @@ func NewContractCreation(nonce uint64, amount *big.Int, smokeLimit uint64, smoke
 	return newTransaction(nonce, nil, amount, smokeLimit, smokePrice, data)
 }
 
+// NewAccessListTransaction creates an EIP-2930 transaction, which names the
+// accounts and storage slots it accesses upfront and is bound to a chain id.
+func NewAccessListTransaction(chainID *big.Int, nonce uint64, to *common.Address, amount *big.Int, smokeLimit uint64, smokePrice *big.Int, accessList AccessList, data []byte) *Transaction {
+	tx := newTransaction(nonce, to, amount, smokeLimit, smokePrice, data)
+	tx.data.Type = AccessListTxType
+	tx.data.ChainID = new(big.Int)
+	if chainID != nil {
+		tx.data.ChainID.Set(chainID)
+	}
+	tx.data.AccessList = make(AccessList, len(accessList))
+	copy(tx.data.AccessList, accessList)
+	return tx
+}
+
+// NewDynamicFeeTransaction creates a transaction paying the base fee of the block
+// it's included in plus a tip of at most tipCap, as long as the sum is below the
+// fee cap.
+func NewDynamicFeeTransaction(nonce uint64, to *common.Address, amount *big.Int, smokeLimit uint64, feeCap, tipCap *big.Int, data []byte) *Transaction {
+	tx := newTransaction(nonce, to, amount, smokeLimit, feeCap, data)
+	tx.data.TipCap = new(big.Int)
+	if tipCap != nil {
+		tx.data.TipCap.Set(tipCap)
+	}
+	return tx
+}
+
 func newTransaction(nonce uint64, to *common.Address, amount *big.Int, smokeLimit uint64, smokePrice *big.Int, data []byte) *Transaction {
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
@@ -109,13 +157,25 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, smokeLimi
 	}
 }
 
+// Type returns the envelope type of the transaction, LegacyTxType if untyped.
+func (tx *Transaction) Type() uint8 {
+	return tx.data.Type
+}
+
 // ChainId returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainId() *big.Int {
+	if tx.data.Type != LegacyTxType {
+		return new(big.Int).Set(tx.data.ChainID)
+	}
 	return deriveChainId(tx.data.V)
 }
 
 // Protected returns if the transaction is protected from replay protection.
+// Typed transactions always are, carrying their chain id.
 func (tx *Transaction) Protected() bool {
+	if tx.data.Type != LegacyTxType {
+		return true
+	}
 	return isProtectedV(tx.data.V)
 }
 
@@ -128,15 +188,33 @@ func isProtectedV(V *big.Int) bool {
 	return true
 }
 
-// EncodeRLP implements rlp.Encoder
+// EncodeRLP implements rlp.Encoder. Typed transactions are encoded as an RLP
+// string holding their envelope.
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.data.Type == LegacyTxType {
+		return tx.data.EncodeRLP(w)
+	}
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enc)
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		err = tx.data.DecodeRLP(s)
+	} else {
+		var enc []byte
+		if enc, err = s.Bytes(); err == nil {
+			tx.data, err = decodeTypedTx(enc)
+		}
+	}
 	if err == nil {
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
 		tx.time = time.Now()
@@ -144,6 +222,82 @@ func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+// MarshalBinary returns the canonical encoding of the transaction: the RLP list
+// of legacy transactions, or the type byte and payload of typed ones.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.data.Type == LegacyTxType {
+		return rlp.EncodeToBytes(&tx.data)
+	}
+	payload, err := rlp.EncodeToBytes(tx.typedPayload())
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{tx.data.Type}, payload...), nil
+}
+
+// UnmarshalBinary decodes the canonical encoding of a transaction, as returned
+// by MarshalBinary.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	// Legacy transactions are RLP lists, which start above all transaction types
+	if len(b) > 0 && b[0] > 0x7f {
+		return rlp.DecodeBytes(b, tx)
+	}
+	data, err := decodeTypedTx(b)
+	if err != nil {
+		return err
+	}
+	tx.data = data
+	tx.size.Store(common.StorageSize(len(b)))
+	tx.time = time.Now()
+	return nil
+}
+
+// typedPayload returns the consensus fields of a typed transaction, following
+// its type byte in the encoding.
+func (tx *Transaction) typedPayload() *accessListTxdata {
+	return &accessListTxdata{
+		ChainID:      tx.data.ChainID,
+		AccountNonce: tx.data.AccountNonce,
+		Price:        tx.data.Price,
+		SmokeLimit:   tx.data.SmokeLimit,
+		Recipient:    tx.data.Recipient,
+		Amount:       tx.data.Amount,
+		Payload:      tx.data.Payload,
+		AccessList:   tx.data.AccessList,
+		V:            tx.data.V,
+		R:            tx.data.R,
+		S:            tx.data.S,
+	}
+}
+
+// decodeTypedTx decodes the envelope of a typed transaction.
+func decodeTypedTx(b []byte) (txdata, error) {
+	if len(b) == 0 {
+		return txdata{}, errEmptyTypedTx
+	}
+	if b[0] != AccessListTxType {
+		return txdata{}, ErrTxTypeNotSupported
+	}
+	var dec accessListTxdata
+	if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
+		return txdata{}, err
+	}
+	return txdata{
+		AccountNonce: dec.AccountNonce,
+		Price:        dec.Price,
+		SmokeLimit:   dec.SmokeLimit,
+		Recipient:    dec.Recipient,
+		Amount:       dec.Amount,
+		Payload:      dec.Payload,
+		V:            dec.V,
+		R:            dec.R,
+		S:            dec.S,
+		Type:         AccessListTxType,
+		ChainID:      dec.ChainID,
+		AccessList:   dec.AccessList,
+	}, nil
+}
+
 // MarshalJSON encodes the web3 RPC transaction format.
 func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
@@ -158,10 +312,25 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	if err := dec.UnmarshalJSON(input); err != nil {
 		return err
 	}
+	switch dec.Type {
+	case LegacyTxType:
+		dec.ChainID, dec.AccessList = nil, nil
+	case AccessListTxType:
+		if dec.ChainID == nil {
+			return errors.New("missing required field 'chainId' for typed transaction")
+		}
+		if dec.TipCap != nil {
+			return errors.New("typed transaction with a tip cap")
+		}
+	default:
+		return ErrTxTypeNotSupported
+	}
 	withSignature := dec.V.Sign() != 0 || dec.R.Sign() != 0 || dec.S.Sign() != 0
 	if withSignature {
 		var V byte
-		if isProtectedV(dec.V) {
+		if dec.Type != LegacyTxType {
+			V = byte(dec.V.Uint64())
+		} else if isProtectedV(dec.V) {
 			chainID := deriveChainId(dec.V).Uint64()
 			V = byte(dec.V.Uint64() - 35 - 2*chainID)
 		} else {
@@ -187,6 +356,50 @@ func (tx *Transaction) SmokePriceCmp(other *Transaction) int {
 func (tx *Transaction) SmokePriceIntCmp(other *big.Int) int {
 	return tx.data.Price.Cmp(other)
 }
+
+// IsDynamicFee reports whether the transaction caps its tip separately from the
+// total fee it's willing to pay per smoke.
+func (tx *Transaction) IsDynamicFee() bool { return tx.data.TipCap != nil }
+
+// SmokeFeeCap returns the maximum total fee per smoke the transaction pays, which
+// is the smoke price of legacy transactions.
+func (tx *Transaction) SmokeFeeCap() *big.Int { return new(big.Int).Set(tx.data.Price) }
+
+// SmokeTipCap returns the maximum fee per smoke paid to the miner on top of the
+// base fee, which is the smoke price of legacy transactions.
+func (tx *Transaction) SmokeTipCap() *big.Int {
+	if tx.data.TipCap == nil {
+		return new(big.Int).Set(tx.data.Price)
+	}
+	return new(big.Int).Set(tx.data.TipCap)
+}
+
+// EffectiveSmokeTip returns the fee per smoke paid to the miner with the given
+// base fee. The result is negative if the fee cap is below the base fee.
+func (tx *Transaction) EffectiveSmokeTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.SmokeTipCap()
+	}
+	tip := new(big.Int).Sub(tx.data.Price, baseFee)
+	if tipCap := tx.SmokeTipCap(); tip.Cmp(tipCap) > 0 {
+		tip = tipCap
+	}
+	return tip
+}
+
+// EffectiveSmokePrice returns the fee per smoke paid by the transaction with the
+// given base fee, the base fee plus the tip capped by the fee cap. Without a base
+// fee it's the smoke price of the transaction.
+func (tx *Transaction) EffectiveSmokePrice(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.SmokePrice()
+	}
+	return new(big.Int).Add(baseFee, tx.EffectiveSmokeTip(baseFee))
+}
+
+// AccessList returns the access list of the transaction, nil for legacy ones.
+func (tx *Transaction) AccessList() AccessList { return tx.data.AccessList }
+
 func (tx *Transaction) Value() *big.Int  { return new(big.Int).Set(tx.data.Amount) }
 func (tx *Transaction) Nonce() uint64    { return tx.data.AccountNonce }
 func (tx *Transaction) CheckNonce() bool { return true }
@@ -201,13 +414,23 @@ func (tx *Transaction) To() *common.Address {
 	return &to
 }
 
-// Hash hashes the RLP encoding of tx.
+// Time returns the time the transaction was first seen locally.
+func (tx *Transaction) Time() time.Time {
+	return tx.time
+}
+
+// Hash hashes the canonical encoding of tx.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.data.Type == LegacyTxType {
+		v = rlpHash(tx)
+	} else {
+		v = prefixedRlpHash(tx.data.Type, tx.typedPayload())
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -219,7 +442,7 @@ func (tx *Transaction) Size() common.StorageSize {
 		return size.(common.StorageSize)
 	}
 	c := writeCounter(0)
-	rlp.Encode(&c, &tx.data)
+	rlp.Encode(&c, tx)
 	tx.size.Store(common.StorageSize(c))
 	return common.StorageSize(c)
 }
@@ -234,6 +457,8 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 		nonce:      tx.data.AccountNonce,
 		smokeLimit:   tx.data.SmokeLimit,
 		smokePrice:   new(big.Int).Set(tx.data.Price),
+		tipCap:     tx.SmokeTipCap(),
+		accessList: tx.data.AccessList,
 		to:         tx.data.Recipient,
 		amount:     tx.data.Amount,
 		data:       tx.data.Payload,
@@ -282,9 +507,10 @@ func (s Transactions) Len() int { return len(s) }
 // Swap swaps the i'th and the j'th element in s.
 func (s Transactions) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-// GetRlp implements Rlpable and returns the i'th element of s in rlp.
+// GetRlp implements Rlpable and returns the i'th element of s in its canonical
+// encoding, which is RLP for legacy transactions.
 func (s Transactions) GetRlp(i int) []byte {
-	enc, _ := rlp.EncodeToBytes(s[i])
+	enc, _ := s[i].MarshalBinary()
 	return enc
 }
 
@@ -343,25 +569,58 @@ func (s *TxByPriceAndTime) Pop() interface{} {
 	return x
 }
 
+// txByTipAndTime is the fee market aware version of TxByPriceAndTime, ordering
+// the transactions by the tip they pay the miner with the given base fee. Before
+// the fee market (nil base fee), the tip is the smoke price.
+type txByTipAndTime struct {
+	txs     Transactions
+	baseFee *big.Int
+}
+
+func (s *txByTipAndTime) Len() int { return len(s.txs) }
+func (s *txByTipAndTime) Less(i, j int) bool {
+	// If the tips are equal, use the time the transaction was first seen for
+	// deterministic sorting
+	cmp := s.txs[i].EffectiveSmokeTip(s.baseFee).Cmp(s.txs[j].EffectiveSmokeTip(s.baseFee))
+	if cmp == 0 {
+		return s.txs[i].time.Before(s.txs[j].time)
+	}
+	return cmp > 0
+}
+func (s *txByTipAndTime) Swap(i, j int) { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+
+func (s *txByTipAndTime) Push(x interface{}) {
+	s.txs = append(s.txs, x.(*Transaction))
+}
+
+func (s *txByTipAndTime) Pop() interface{} {
+	old := s.txs
+	n := len(old)
+	x := old[n-1]
+	s.txs = old[0 : n-1]
+	return x
+}
+
 // TransactionsByPriceAndNonce represents a set of transactions that can return
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type TransactionsByPriceAndNonce struct {
 	txs    map[common.Address]Transactions // Per account nonce-sorted list of transactions
-	heads  TxByPriceAndTime                // Next transaction for each unique account (price heap)
+	heads  *txByTipAndTime                 // Next transaction for each unique account (tip heap)
 	signer Signer                          // Signer for the set of transactions
 }
 
 // NewTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// price sorted transactions in a nonce-honouring way. Transactions are sorted by
+// the tip they pay on top of the base fee, which is nil before the fee market.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions) *TransactionsByPriceAndNonce {
-	// Initialize a price and received time based heap with the head transactions
-	heads := make(TxByPriceAndTime, 0, len(txs))
+func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions, baseFee *big.Int) *TransactionsByPriceAndNonce {
+	// Initialize a tip and received time based heap with the head transactions
+	heads := &txByTipAndTime{txs: make(Transactions, 0, len(txs)), baseFee: baseFee}
 	for from, accTxs := range txs {
-		heads = append(heads, accTxs[0])
+		heads.txs = append(heads.txs, accTxs[0])
 		// Ensure the sender address is from the signer
 		acc, _ := Sender(signer, accTxs[0])
 		txs[acc] = accTxs[1:]
@@ -369,7 +628,7 @@ func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transa
 			delete(txs, from)
 		}
 	}
-	heap.Init(&heads)
+	heap.Init(heads)
 
 	// Assemble and return the transaction set
 	return &TransactionsByPriceAndNonce{
@@ -381,20 +640,20 @@ func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transa
 
 // Peek returns the next transaction by price.
 func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
-	if len(t.heads) == 0 {
+	if len(t.heads.txs) == 0 {
 		return nil
 	}
-	return t.heads[0]
+	return t.heads.txs[0]
 }
 
 // Shift replaces the current best head with the next one from the same account.
 func (t *TransactionsByPriceAndNonce) Shift() {
-	acc, _ := Sender(t.signer, t.heads[0])
+	acc, _ := Sender(t.signer, t.heads.txs[0])
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
-		t.heads[0], t.txs[acc] = txs[0], txs[1:]
-		heap.Fix(&t.heads, 0)
+		t.heads.txs[0], t.txs[acc] = txs[0], txs[1:]
+		heap.Fix(t.heads, 0)
 	} else {
-		heap.Pop(&t.heads)
+		heap.Pop(t.heads)
 	}
 }
 
@@ -402,7 +661,7 @@ func (t *TransactionsByPriceAndNonce) Shift() {
 // the same account. This should be used when a transaction cannot be executed
 // and hence all subsequent ones should be discarded from the same account.
 func (t *TransactionsByPriceAndNonce) Pop() {
-	heap.Pop(&t.heads)
+	heap.Pop(t.heads)
 }
 
 // Message is a fully derived transaction and implements core.Message
@@ -415,7 +674,9 @@ type Message struct {
 	amount     *big.Int
 	smokeLimit   uint64
 	smokePrice   *big.Int
+	tipCap     *big.Int
 	data       []byte
+	accessList AccessList
 	checkNonce bool
 }
 
@@ -440,3 +701,13 @@ func (m Message) Smoke() uint64          { return m.smokeLimit }
 func (m Message) Nonce() uint64        { return m.nonce }
 func (m Message) Data() []byte         { return m.data }
 func (m Message) CheckNonce() bool     { return m.checkNonce }
+func (m Message) AccessList() AccessList { return m.accessList }
+
+// SmokeTipCap returns the maximum tip per smoke of the message on top of the base
+// fee, defaulting to its smoke price.
+func (m Message) SmokeTipCap() *big.Int {
+	if m.tipCap == nil {
+		return m.smokePrice
+	}
+	return m.tipCap
+}
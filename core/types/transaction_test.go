@@ -140,7 +140,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 		}
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -192,7 +192,7 @@ func TestTransactionTimeSort(t *testing.T) {
 		groups[addr] = append(groups[addr], tx)
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -266,3 +266,98 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+// Tests that access list transactions survive the canonical, the block body and
+// the JSON encodings, and that only the EIP-2930 signer accepts them.
+func TestAccessListTransaction(t *testing.T) {
+	key, from := defaultTestKey()
+	signer := NewEIP2930Signer(big.NewInt(420))
+
+	accesses := AccessList{{
+		Address:     common.Address{0xaa},
+		StorageKeys: []common.Hash{{0x01}, {0x02}},
+	}}
+	tx, err := SignTx(NewAccessListTransaction(big.NewInt(420), 3, &common.Address{0xbb}, big.NewInt(10), 50000, big.NewInt(1), accesses, []byte{0x55, 0x44}), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if tx.Type() != AccessListTxType {
+		t.Fatalf("transaction type mismatch: have %d, want %d", tx.Type(), AccessListTxType)
+	}
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	if enc[0] != AccessListTxType {
+		t.Fatalf("envelope type mismatch: have %#x, want %#x", enc[0], AccessListTxType)
+	}
+	if hash := crypto.Keccak256Hash(enc); tx.Hash() != hash {
+		t.Errorf("transaction hash mismatch: have %x, want %x", tx.Hash(), hash)
+	}
+	// Decode the transaction from the canonical encoding and from within a block body
+	var binary Transaction
+	if err := binary.UnmarshalBinary(enc); err != nil {
+		t.Fatalf("failed to decode canonical encoding: %v", err)
+	}
+	blob, err := rlp.EncodeToBytes(Transactions{tx})
+	if err != nil {
+		t.Fatalf("failed to encode transaction list: %v", err)
+	}
+	var list Transactions
+	if err := rlp.DecodeBytes(blob, &list); err != nil {
+		t.Fatalf("failed to decode transaction list: %v", err)
+	}
+	blob, err = json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to encode JSON: %v", err)
+	}
+	var parsed Transaction
+	if err := json.Unmarshal(blob, &parsed); err != nil {
+		t.Fatalf("failed to decode JSON: %v", err)
+	}
+	for name, dec := range map[string]*Transaction{"binary": &binary, "list": list[0], "json": &parsed} {
+		if dec.Hash() != tx.Hash() {
+			t.Errorf("%s: hash mismatch: have %x, want %x", name, dec.Hash(), tx.Hash())
+		}
+		if dec.AccessList().StorageKeys() != 2 {
+			t.Errorf("%s: access list lost: %v", name, dec.AccessList())
+		}
+		if sender, err := Sender(signer, dec); err != nil || sender != from {
+			t.Errorf("%s: sender mismatch: have %x (%v), want %x", name, sender, err, from)
+		}
+	}
+	// Legacy signers must reject typed transactions
+	if _, err := NewEIP155Signer(big.NewInt(420)).Sender(tx); err != ErrTxTypeNotSupported {
+		t.Errorf("EIP155 signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	if _, err := NewEIP2930Signer(big.NewInt(1)).Sender(tx); err != ErrInvalidChainId {
+		t.Errorf("foreign chain error mismatch: have %v, want %v", err, ErrInvalidChainId)
+	}
+}
+
+// Tests that the effective smoke price is the base fee plus the tip, capped by
+// the fee cap of the transaction.
+func TestEffectiveSmokePrice(t *testing.T) {
+	var (
+		legacy  = NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(10), nil)
+		dynamic = NewDynamicFeeTransaction(0, &common.Address{}, big.NewInt(0), 21000, big.NewInt(10), big.NewInt(3), nil)
+	)
+	tests := []struct {
+		tx      *Transaction
+		baseFee *big.Int
+		want    int64
+	}{
+		{legacy, nil, 10},
+		{legacy, big.NewInt(4), 10},
+		{legacy, big.NewInt(10), 10},
+		{dynamic, nil, 10},
+		{dynamic, big.NewInt(4), 7},
+		{dynamic, big.NewInt(8), 10},
+		{dynamic, big.NewInt(10), 10},
+	}
+	for i, tt := range tests {
+		if have := tt.tx.EffectiveSmokePrice(tt.baseFee); have.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("test %d: effective smoke price mismatch: have %v, want %d", i, have, tt.want)
+		}
+	}
+}
@@ -45,12 +45,18 @@ type (
 // ActivePrecompiles returns the addresses of the precompiles enabled with the current
 // configuration
 func (evm *EVM) ActivePrecompiles() []common.Address {
+	return ActivePrecompiles(evm.chainRules)
+}
+
+// ActivePrecompiles returns the addresses of the precompiles enabled by the
+// given chain rules.
+func ActivePrecompiles(rules params.Rules) []common.Address {
 	switch {
-	case evm.chainRules.IsYoloV2:
+	case rules.IsYoloV2:
 		return PrecompiledAddressesYoloV2
-	case evm.chainRules.IsIstanbul:
+	case rules.IsIstanbul:
 		return PrecompiledAddressesIstanbul
-	case evm.chainRules.IsByzantium:
+	case rules.IsByzantium:
 		return PrecompiledAddressesByzantium
 	default:
 		return PrecompiledAddressesHomestead
@@ -108,6 +114,11 @@ type BlockContext  struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	BaseFee     *big.Int       // Base fee per smoke of the block (nil = before the fee market)
+
+	// FeeFund resolves the account receiving the share of the transaction fees
+	// routed away from the coinbase (nil = all fees paid to the coinbase)
+	FeeFund func(StateDB) common.Address
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -159,6 +170,10 @@ type EVM struct {
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
 // only ever be used *once*.
 func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig *params.ChainConfig, vmConfig Config) *EVM {
+	// Zero priced calls are exempt from the base fee if requested
+	if vmConfig.NoBaseFee && blockCtx.BaseFee != nil && (txCtx.SmokePrice == nil || txCtx.SmokePrice.Sign() == 0) {
+		blockCtx.BaseFee = new(big.Int)
+	}
 	evm := &EVM{
 		Context:      blockCtx,
 		TxContext:    txCtx,
@@ -193,11 +208,13 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 	return evm
 }
 
-// Reset resets the EVM with a new transaction context.Reset
+// Reset resets the EVM with a new transaction context and clears any previous
+// cancellation, keeping the block context.
 // This is not threadsafe and should only be done very cautiously.
 func (evm *EVM) Reset(txCtx TxContext, statedb StateDB) {
 	evm.TxContext = txCtx
 	evm.StateDB = statedb
+	atomic.StoreInt32(&evm.abort, 0)
 }
 
 // Cancel cancels any running EVM operation. This may be called concurrently and
@@ -479,8 +496,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, smoke uint6
 
 	ret, err := run(evm, contract, nil, false)
 
-	// check if the max code size has been exceeded
-	maxCodeSizeExceeded := evm.chainRules.IsEIP158 && len(ret) > params.MaxCodeSize
+	// check if the max code size has been exceeded. Both CREATE and CREATE2 end
+	// up here, making this the only place the EVM installs code. Genesis allocs
+	// and RPC call state overrides write code into the state directly and are
+	// deliberately exempt: the former is agreed upon with the chain config, the
+	// latter never leaves the node.
+	maxCodeSizeExceeded := evm.chainRules.IsEIP158 && len(ret) > evm.chainRules.MaxCodeSize
 	// if the contract creation ran successfully and no errors were returned
 	// calculate the smoke required to store the code. If the code could not
 	// be stored due to not enough smoke set an error and let it be handled
@@ -31,6 +31,7 @@ type Config struct {
 	Tracer                  Tracer // Opcode logger
 	NoRecursion             bool   // Disables call, callcode, delegate call and create
 	EnablePreimageRecording bool   // Enables recording of SHA3/keccak preimages
+	NoBaseFee               bool   // Zeroes the base fee for zero priced calls, e.g. 420_call
 
 	JumpTable [256]*operation // EVM instruction table, automatically populated if unset
 
@@ -125,6 +125,7 @@ type StructLogger struct {
 
 	storage map[common.Address]Storage
 	logs    []StructLog
+	flushed int // Number of log entries already released by Flush
 	output  []byte
 	err     error
 }
@@ -150,7 +151,7 @@ func (l *StructLogger) CaptureStart(from common.Address, to common.Address, crea
 // CaptureState also tracks SLOAD/SSTORE ops to track storage change.
 func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, smoke, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, rData []byte, contract *Contract, depth int, err error) error {
 	// check if already accumulated the specified number of logs
-	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+	if l.cfg.Limit != 0 && l.cfg.Limit <= l.flushed+len(l.logs) {
 		return errTraceLimitReached
 	}
 	// Copy a snapshot of the current memory state to a new buffer
@@ -231,6 +232,15 @@ func (l *StructLogger) CaptureEnd(output []byte, smokeUsed uint64, t time.Durati
 // StructLogs returns the captured log entries.
 func (l *StructLogger) StructLogs() []StructLog { return l.logs }
 
+// Flush returns the log entries captured since the last flush and releases them,
+// allowing long traces to be delivered in chunks with bounded memory.
+func (l *StructLogger) Flush() []StructLog {
+	logs := l.logs
+	l.flushed += len(logs)
+	l.logs = nil
+	return logs
+}
+
 // Error returns the VM error captured by the trace.
 func (l *StructLogger) Error() error { return l.err }
 
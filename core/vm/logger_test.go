@@ -70,3 +70,32 @@ func TestStoreCapture(t *testing.T) {
 		t.Errorf("expected %x, got %x", exp, logger.storage[contract.Address()][index])
 	}
 }
+
+// Tests that flushed log entries are released, but still count towards the limit.
+func TestFlushCapture(t *testing.T) {
+	var (
+		env      = NewEVM(BlockContext{}, TxContext{}, &dummyStatedb{}, params.TestChainConfig, Config{})
+		logger   = NewStructLogger(&LogConfig{Limit: 3})
+		mem      = NewMemory()
+		stack    = newstack()
+		rstack   = newReturnStack()
+		contract = NewContract(&dummyContractRef{}, &dummyContractRef{}, new(big.Int), 0)
+	)
+	for i := 0; i < 2; i++ {
+		if err := logger.CaptureState(env, uint64(i), STOP, 0, 0, mem, stack, rstack, nil, contract, 0, nil); err != nil {
+			t.Fatalf("step %d: failed to capture: %v", i, err)
+		}
+	}
+	if logs := logger.Flush(); len(logs) != 2 {
+		t.Fatalf("flushed log count mismatch: have %d, want 2", len(logs))
+	}
+	if logs := logger.StructLogs(); len(logs) != 0 {
+		t.Fatalf("flushed logs retained: have %d", len(logs))
+	}
+	if err := logger.CaptureState(env, 2, STOP, 0, 0, mem, stack, rstack, nil, contract, 0, nil); err != nil {
+		t.Fatalf("failed to capture below limit: %v", err)
+	}
+	if err := logger.CaptureState(env, 3, STOP, 0, 0, mem, stack, rstack, nil, contract, 0, nil); err != errTraceLimitReached {
+		t.Fatalf("limit error mismatch: have %v, want %v", err, errTraceLimitReached)
+	}
+}
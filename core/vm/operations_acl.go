@@ -79,7 +79,7 @@ func smokeSStoreEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 			return cost + params.SstoreSetSmokeEIP2200, nil
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
-			evm.StateDB.AddRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		}
 		// EIP-2200 original clause:
 		//		return params.SstoreResetSmokeEIP2200, nil // write existing slot (2.1.2)
@@ -87,9 +87,9 @@ func smokeSStoreEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
-			evm.StateDB.SubRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.SubRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
-			evm.StateDB.AddRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		}
 	}
 	if original == value {
@@ -214,7 +214,7 @@ func smokeSelfdestructEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *M
 	if evm.StateDB.Empty(address) && evm.StateDB.GetBalance(contract.Address()).Sign() != 0 {
 		smoke += params.CreateBySelfdestructSmoke
 	}
-	if !evm.StateDB.HasSuicided(contract.Address()) {
+	if !evm.chainRules.IsRefundRemoval && !evm.StateDB.HasSuicided(contract.Address()) {
 		evm.StateDB.AddRefund(params.SelfdestructRefundSmoke)
 	}
 	return smoke, nil
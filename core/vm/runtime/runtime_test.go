@@ -124,6 +124,32 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// Tests that contract creation enforces the chain's code size limit, including
+// any configured override.
+func TestCreateMaxCodeSize(t *testing.T) {
+	// Init code deploying 30000 zero bytes, above the default limit
+	code := []byte{
+		byte(vm.PUSH2), 0x75, 0x30,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	cfg := new(Config)
+	setDefaults(cfg)
+	if _, _, _, err := Create(code, cfg); err != vm.ErrMaxCodeSizeExceeded {
+		t.Fatalf("default limit: error mismatch: have %v, want %v", err, vm.ErrMaxCodeSizeExceeded)
+	}
+	config := *cfg.ChainConfig
+	config.MaxCodeSizeBlock, config.MaxCodeSize = new(big.Int), 49152
+
+	ret, _, _, err := Create(code, &Config{ChainConfig: &config})
+	if err != nil {
+		t.Fatalf("raised limit: failed to create contract: %v", err)
+	}
+	if len(ret) != 30000 {
+		t.Fatalf("raised limit: code size mismatch: have %d, want %d", len(ret), 30000)
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
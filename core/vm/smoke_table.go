@@ -111,7 +111,7 @@ func smokeSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memory
 		case current == (common.Hash{}) && y.Sign() != 0: // 0 => non 0
 			return params.SstoreSetSmoke, nil
 		case current != (common.Hash{}) && y.Sign() == 0: // non 0 => 0
-			evm.StateDB.AddRefund(params.SstoreRefundSmoke)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.SstoreRefundSmoke))
 			return params.SstoreClearSmoke, nil
 		default: // non 0 => non 0 (or 0 => 0)
 			return params.SstoreResetSmoke, nil
@@ -141,15 +141,15 @@ func smokeSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memory
 			return params.NetSstoreInitSmoke, nil
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
-			evm.StateDB.AddRefund(params.NetSstoreClearRefund)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.NetSstoreClearRefund))
 		}
 		return params.NetSstoreCleanSmoke, nil // write existing slot (2.1.2)
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
-			evm.StateDB.SubRefund(params.NetSstoreClearRefund)
+			evm.StateDB.SubRefund(sstoreClearRefund(evm, params.NetSstoreClearRefund))
 		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
-			evm.StateDB.AddRefund(params.NetSstoreClearRefund)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.NetSstoreClearRefund))
 		}
 	}
 	if original == value {
@@ -196,15 +196,15 @@ func smokeSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 			return params.SstoreSetSmokeEIP2200, nil
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
-			evm.StateDB.AddRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		}
 		return params.SstoreResetSmokeEIP2200, nil // write existing slot (2.1.2)
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
-			evm.StateDB.SubRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.SubRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
-			evm.StateDB.AddRefund(params.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(sstoreClearRefund(evm, params.SstoreClearsScheduleRefundEIP2200))
 		}
 	}
 	if original == value {
@@ -217,6 +217,15 @@ func smokeSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 	return params.SloadSmokeEIP2200, nil // dirty update (2.2)
 }
 
+// sstoreClearRefund returns the refund granted for clearing a storage slot. The
+// refund is removed altogether once the refund removal fork is active.
+func sstoreClearRefund(evm *EVM, refund uint64) uint64 {
+	if evm.chainRules.IsRefundRemoval {
+		return 0
+	}
+	return refund
+}
+
 func makeSmokeLog(n uint64) smokeFunc {
 	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 		requestedSize, overflow := stack.Back(1).Uint64WithOverflow()
@@ -434,7 +443,7 @@ func smokeSelfdestruct(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 		}
 	}
 
-	if !evm.StateDB.HasSuicided(contract.Address()) {
+	if !evm.chainRules.IsRefundRemoval && !evm.StateDB.HasSuicided(contract.Address()) {
 		evm.StateDB.AddRefund(params.SelfdestructRefundSmoke)
 	}
 	return smoke, nil
@@ -105,3 +105,49 @@ func TestEIP2200(t *testing.T) {
 		}
 	}
 }
+
+func TestRefundRemoval(t *testing.T) {
+	tests := []struct {
+		input  string
+		refund uint64 // Refund with the fork active
+		legacy uint64 // Refund with the fork inactive
+	}{
+		{"0x60006000556000600055", 0, params.SstoreClearsScheduleRefundEIP2200}, // 1 -> 0 -> 0
+		{"0x60026000556000600055", 0, params.SstoreClearsScheduleRefundEIP2200}, // 1 -> 2 -> 0
+		{"0x6000ff", 0, params.SelfdestructRefundSmoke},                         // SELFDESTRUCT
+	}
+	active := *params.AllEthashProtocolChanges
+	active.RefundRemovalBlock = big.NewInt(0)
+
+	inactive := *params.AllEthashProtocolChanges
+	inactive.RefundRemovalBlock = nil
+
+	for i, tt := range tests {
+		for _, config := range []*params.ChainConfig{&active, &inactive} {
+			address := common.BytesToAddress([]byte("contract"))
+
+			statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			statedb.CreateAccount(address)
+			statedb.SetCode(address, hexutil.MustDecode(tt.input))
+			statedb.SetState(address, common.Hash{}, common.BytesToHash([]byte{1}))
+			statedb.Finalise(true)
+
+			vmctx := BlockContext{
+				CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+				Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			}
+			vmenv := NewEVM(vmctx, TxContext{}, statedb, config, Config{ExtraEips: []int{2200}})
+
+			if _, _, err := vmenv.Call(AccountRef(common.Address{}), address, nil, math.MaxUint64, new(big.Int)); err != nil {
+				t.Fatalf("test %d, fork %v: call failed: %v", i, config == &active, err)
+			}
+			want := tt.refund
+			if config == &inactive {
+				want = tt.legacy
+			}
+			if refund := vmenv.StateDB.GetRefund(); refund != want {
+				t.Errorf("test %d, fork %v: smoke refund mismatch: have %v, want %v", i, config == &active, refund, want)
+			}
+		}
+	}
+}
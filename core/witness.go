@@ -0,0 +1,300 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/420integrated/go-420coin/420db"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/trie"
+)
+
+var (
+	// errIncompleteWitness is returned if the execution of a witness accesses any
+	// data it doesn't carry.
+	errIncompleteWitness = errors.New("incomplete witness")
+
+	// errWitnessGenesis is returned if a witness carries a genesis header other
+	// than the one of the chain it's executed for.
+	errWitnessGenesis = errors.New("witness genesis mismatch")
+)
+
+// Witness is the data needed to execute a block without access to the state
+// database: the ancestor headers the block accesses, starting with its parent,
+// and the trie nodes and contract codes read and written while processing it.
+// All of it is authenticated by the parent header through its hash.
+type Witness struct {
+	Headers []*types.Header
+	Codes   [][]byte
+	Nodes   [][]byte
+}
+
+// RecordWitness processes a block on top of its parent state, recording all the
+// data accessed into a witness. The parent state must be present in the chain.
+func RecordWitness(chain *BlockChain, block *types.Block) (*Witness, error) {
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	recorder := &witnessRecorder{
+		Database: chain.db,
+		nodes:    chain.stateCache.TrieDB(),
+		chain:    chain,
+		reads:    make(map[string][]byte),
+		headers:  map[common.Hash]*types.Header{parent.Hash(): parent},
+	}
+	// Process the block against a fresh state database, so that every trie
+	// node and code is read through the recorder instead of being cached
+	statedb, err := state.New(parent.Root, state.NewDatabase(recorder), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := processWitness(chain.Config(), recorder, block, statedb); err != nil {
+		return nil, err
+	}
+	return recorder.witness(parent), nil
+}
+
+// ExecuteWitness processes a block statelessly, on top of the parent state
+// carried by its witness, and verifies the resulting state and receipts against
+// the block header. It returns an error if the witness is incomplete or the block
+// is invalid.
+//
+// The headers past a gap in the ancestry aren't authenticated by the parent, so
+// the genesis, which the ethash rewards depend on, is checked against the given
+// trusted hash.
+func ExecuteWitness(config *params.ChainConfig, genesis common.Hash, engine consensus.Engine, block *types.Block, witness *Witness) error {
+	if len(witness.Headers) == 0 || witness.Headers[0].Hash() != block.ParentHash() {
+		return errors.New("witness does not start with the parent header")
+	}
+	for _, header := range witness.Headers {
+		if header.Number.Sign() == 0 && header.Hash() != genesis {
+			return fmt.Errorf("%w: have %x, want %x", errWitnessGenesis, header.Hash(), genesis)
+		}
+	}
+	chain := newWitnessChain(config, engine, witness.Headers)
+
+	db := rawdb.NewMemoryDatabase()
+	for _, node := range witness.Nodes {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	for _, code := range witness.Codes {
+		rawdb.WriteCode(db, crypto.Keccak256Hash(code), code)
+	}
+	statedb, err := state.New(witness.Headers[0].Root, state.NewDatabase(db), nil)
+	if err != nil {
+		return err
+	}
+	err = processWitness(config, chain, block, statedb)
+	if chain.missing != nil {
+		return fmt.Errorf("%w: missing header #%d", errIncompleteWitness, *chain.missing)
+	}
+	return err
+}
+
+// processWitness processes a block on top of the given parent state and verifies
+// the result against the block header.
+func processWitness(config *params.ChainConfig, chain processorChain, block *types.Block, statedb *state.StateDB) error {
+	processor := &StateProcessor{config: config, bc: chain, engine: chain.Engine()}
+	receipts, _, usedSmoke, err := processor.Process(block, statedb, vm.Config{})
+	if err == nil {
+		validator := &BlockValidator{config: config, engine: chain.Engine()}
+		err = validator.ValidateState(block, statedb, receipts, usedSmoke)
+	}
+	if dberr := statedb.Error(); dberr != nil {
+		return fmt.Errorf("%w: %v", errIncompleteWitness, dberr)
+	}
+	return err
+}
+
+// witnessRecorder is a database and chain wrapper tracking all the entries and
+// headers accessed through it. Trie nodes are served from the trie database of
+// the chain, as recent states may not be flushed to disk yet.
+type witnessRecorder struct {
+	fourtwentydb.Database
+	nodes *trie.Database
+	chain processorChain
+
+	reads   map[string][]byte             // Database entries read, by key
+	headers map[common.Hash]*types.Header // Headers read, by hash
+	lock    sync.Mutex
+}
+
+// Get retrieves the given key from the wrapped databases, recording the entry.
+func (r *witnessRecorder) Get(key []byte) ([]byte, error) {
+	var (
+		value []byte
+		err   = errors.New("not found")
+	)
+	if len(key) == common.HashLength {
+		value, err = r.nodes.Node(common.BytesToHash(key))
+	}
+	if err != nil {
+		value, err = r.Database.Get(key)
+	}
+	if err == nil {
+		r.lock.Lock()
+		r.reads[string(key)] = common.CopyBytes(value)
+		r.lock.Unlock()
+	}
+	return value, err
+}
+
+// recordHeader tracks the given header if it exists.
+func (r *witnessRecorder) recordHeader(header *types.Header) *types.Header {
+	if header != nil {
+		r.lock.Lock()
+		r.headers[header.Hash()] = header
+		r.lock.Unlock()
+	}
+	return header
+}
+
+func (r *witnessRecorder) Engine() consensus.Engine     { return r.chain.Engine() }
+func (r *witnessRecorder) Config() *params.ChainConfig  { return r.chain.Config() }
+func (r *witnessRecorder) CurrentHeader() *types.Header { return r.chain.CurrentHeader() }
+
+func (r *witnessRecorder) GetHeaderByHash(hash common.Hash) *types.Header {
+	return r.recordHeader(r.chain.GetHeaderByHash(hash))
+}
+
+func (r *witnessRecorder) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return r.recordHeader(r.chain.GetHeader(hash, number))
+}
+
+func (r *witnessRecorder) GetHeaderByNumber(number uint64) *types.Header {
+	return r.recordHeader(r.chain.GetHeaderByNumber(number))
+}
+
+// witness assembles the recorded data into a witness, the headers ordered from
+// the parent backwards and the rest sorted by key.
+func (r *witnessRecorder) witness(parent *types.Header) *Witness {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	witness := &Witness{Headers: []*types.Header{parent}}
+	for hash, header := range r.headers {
+		if hash != parent.Hash() {
+			witness.Headers = append(witness.Headers, header)
+		}
+	}
+	sort.Slice(witness.Headers[1:], func(i, j int) bool {
+		return witness.Headers[1+i].Number.Uint64() > witness.Headers[1+j].Number.Uint64()
+	})
+	keys := make([]string, 0, len(r.reads))
+	for key := range r.reads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if isCode, _ := rawdb.IsCodeKey([]byte(key)); isCode {
+			witness.Codes = append(witness.Codes, r.reads[key])
+			continue
+		}
+		// Legacy codes are stored by their hash like trie nodes, and are served
+		// the same way to the stateless execution
+		if len(key) == common.HashLength && bytes.Equal(crypto.Keccak256(r.reads[key]), []byte(key)) {
+			witness.Nodes = append(witness.Nodes, r.reads[key])
+		}
+	}
+	return witness
+}
+
+// witnessChain serves the ancestor headers carried by a witness.
+type witnessChain struct {
+	config   *params.ChainConfig
+	engine   consensus.Engine
+	byHash   map[common.Hash]*types.Header
+	byNumber map[uint64]*types.Header
+	head     *types.Header
+	missing  *uint64 // First header looked up by number and not carried, if any
+}
+
+// newWitnessChain indexes the headers of a witness. The headers linked to the
+// parent by their hashes are served by number, as are the ones recorded past a
+// gap in the ancestry, such as the genesis, unless they collide with each other.
+func newWitnessChain(config *params.ChainConfig, engine consensus.Engine, headers []*types.Header) *witnessChain {
+	chain := &witnessChain{
+		config:   config,
+		engine:   engine,
+		byHash:   make(map[common.Hash]*types.Header, len(headers)),
+		byNumber: make(map[uint64]*types.Header, len(headers)),
+		head:     headers[0],
+	}
+	for _, header := range headers {
+		chain.byHash[header.Hash()] = header
+	}
+	linked := make(map[uint64]bool)
+	for header := chain.head; header != nil; header = chain.byHash[header.ParentHash] {
+		chain.byNumber[header.Number.Uint64()] = header
+		linked[header.Number.Uint64()] = true
+		if header.Number.Sign() == 0 {
+			break
+		}
+	}
+	collisions := make(map[uint64]bool)
+	for _, header := range headers {
+		number := header.Number.Uint64()
+		if linked[number] {
+			continue
+		}
+		if known := chain.byNumber[number]; known != nil && known.Hash() != header.Hash() {
+			collisions[number] = true
+		}
+		chain.byNumber[number] = header
+	}
+	for number := range collisions {
+		delete(chain.byNumber, number)
+	}
+	return chain
+}
+
+func (c *witnessChain) Engine() consensus.Engine     { return c.engine }
+func (c *witnessChain) Config() *params.ChainConfig  { return c.config }
+func (c *witnessChain) CurrentHeader() *types.Header { return c.head }
+
+func (c *witnessChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.byHash[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func (c *witnessChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.byHash[hash]
+}
+
+func (c *witnessChain) GetHeaderByNumber(number uint64) *types.Header {
+	header := c.byNumber[number]
+	if header == nil && c.missing == nil {
+		c.missing = &number
+	}
+	return header
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/core/vm"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+	"github.com/420integrated/go-420coin/rlp"
+)
+
+// Tests that a block can be re-executed from its recorded witness alone, and
+// that an incomplete witness or a forged genesis is detected.
+func TestWitnessExecution(t *testing.T) {
+	var (
+		gendb   = rawdb.NewMemoryDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		store   = common.Address{0xaa}
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				address: {Balance: big.NewInt(1000000000000000)},
+				// Stores the hash of the block two below the current one
+				store: {Balance: common.Big0, Code: common.FromHex("600243034060005500")},
+			},
+		}
+		genesis = gspec.MustCommit(gendb)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), gendb, 4, func(i int, block *BlockGen) {
+		block.SetCoinbase(common.Address{0x01})
+
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(address), store, big.NewInt(1000), 100000, nil, nil), signer, key)
+		block.AddTx(tx)
+		tx, _ = types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{byte(i + 2)}, big.NewInt(1000), params.TxSmoke, nil, nil), signer, key)
+		block.AddTx(tx)
+	})
+	// Import the chain without flushing the recent states to disk
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	block := blocks[len(blocks)-1]
+
+	witness, err := RecordWitness(chain, block)
+	if err != nil {
+		t.Fatalf("failed to record witness: %v", err)
+	}
+	if witness.Headers[0].Hash() != block.ParentHash() {
+		t.Fatalf("witness starts with header #%d, want parent", witness.Headers[0].Number)
+	}
+	if len(witness.Codes) != 1 {
+		t.Fatalf("witness code count mismatch: have %d, want %d", len(witness.Codes), 1)
+	}
+	// Round trip the witness through its encoding and execute it statelessly
+	blob, err := rlp.EncodeToBytes(witness)
+	if err != nil {
+		t.Fatalf("failed to encode witness: %v", err)
+	}
+	decoded := new(Witness)
+	if err := rlp.DecodeBytes(blob, decoded); err != nil {
+		t.Fatalf("failed to decode witness: %v", err)
+	}
+	if err := ExecuteWitness(gspec.Config, genesis.Hash(), ethash.NewFaker(), block, decoded); err != nil {
+		t.Fatalf("failed to execute witness: %v", err)
+	}
+	// Drop a trie node and ensure execution fails
+	incomplete := &Witness{
+		Headers: witness.Headers,
+		Codes:   witness.Codes,
+		Nodes:   witness.Nodes[1:],
+	}
+	if err := ExecuteWitness(gspec.Config, genesis.Hash(), ethash.NewFaker(), block, incomplete); err == nil {
+		t.Fatalf("incomplete witness executed")
+	}
+	// Execute the witness on top of the wrong parent and ensure it's rejected
+	if err := ExecuteWitness(gspec.Config, genesis.Hash(), ethash.NewFaker(), blocks[len(blocks)-2], witness); err == nil {
+		t.Fatalf("witness executed for foreign block")
+	}
+	// Strip the genesis, which the rewards depend on, and forge it in turn
+	var stripped, forged []*types.Header
+	for _, header := range witness.Headers {
+		if header.Number.Sign() == 0 {
+			fake := types.CopyHeader(header)
+			fake.Extra = common.Address{0xff}.Bytes()
+			forged = append(forged, fake)
+			continue
+		}
+		stripped = append(stripped, header)
+		forged = append(forged, header)
+	}
+	if len(stripped) == len(witness.Headers) {
+		t.Fatalf("genesis missing from witness")
+	}
+	err = ExecuteWitness(gspec.Config, genesis.Hash(), ethash.NewFaker(), block, &Witness{Headers: stripped, Codes: witness.Codes, Nodes: witness.Nodes})
+	if !errors.Is(err, errIncompleteWitness) {
+		t.Fatalf("witness without genesis error mismatch: have %v, want %v", err, errIncompleteWitness)
+	}
+	err = ExecuteWitness(gspec.Config, genesis.Hash(), ethash.NewFaker(), block, &Witness{Headers: forged, Codes: witness.Codes, Nodes: witness.Nodes})
+	if !errors.Is(err, errWitnessGenesis) {
+		t.Fatalf("witness with forged genesis error mismatch: have %v, want %v", err, errWitnessGenesis)
+	}
+}
+
+// Tests that the headers recorded past a gap in the ancestry of a witness, such
+// as the genesis, are served by number, unless they collide with each other.
+func TestWitnessChainGaps(t *testing.T) {
+	headers := []*types.Header{{Number: new(big.Int), Extra: []byte("genesis")}}
+	for i := 1; i <= 4; i++ {
+		headers = append(headers, &types.Header{Number: big.NewInt(int64(i)), ParentHash: headers[i-1].Hash()})
+	}
+	var (
+		orphan1 = &types.Header{Number: big.NewInt(1), Extra: []byte("orphan")}
+		orphan3 = &types.Header{Number: big.NewInt(3), Extra: []byte("orphan")}
+	)
+	chain := newWitnessChain(params.TestChainConfig, ethash.NewFaker(), []*types.Header{
+		headers[4], headers[3], orphan3, headers[1], orphan1, headers[0],
+	})
+	for number, want := range map[uint64]*types.Header{
+		0: headers[0], // recorded past a gap
+		1: nil,        // colliding past a gap
+		2: nil,        // missing
+		3: headers[3], // linked, despite the colliding orphan
+		4: headers[4], // parent
+	} {
+		if have := chain.GetHeaderByNumber(number); have != want {
+			t.Errorf("header #%d mismatch: have %v, want %v", number, have, want)
+		}
+	}
+}
@@ -803,7 +803,7 @@ func (b *Block) Call(ctx context.Context, args struct {
 			return nil, err
 		}
 	}
-	result, err := fourtwentyapi.DoCall(ctx, b.backend, args.Data, *b.numberOrHash, nil, vm.Config{}, 5*time.Second, b.backend.RPCSmokeCap())
+	result, err := fourtwentyapi.DoCall(ctx, b.backend, args.Data, *b.numberOrHash, nil, vm.Config{NoBaseFee: true}, 5*time.Second, b.backend.RPCSmokeCap())
 	if err != nil {
 		return nil, err
 	}
@@ -873,7 +873,7 @@ func (p *Pending) Call(ctx context.Context, args struct {
 	Data fourtwentyapi.CallArgs
 }) (*CallResult, error) {
 	pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
-	result, err := fourtwentyapi.DoCall(ctx, p.backend, args.Data, pendingBlockNr, nil, vm.Config{}, 5*time.Second, p.backend.RPCSmokeCap())
+	result, err := fourtwentyapi.DoCall(ctx, p.backend, args.Data, pendingBlockNr, nil, vm.Config{NoBaseFee: true}, 5*time.Second, p.backend.RPCSmokeCap())
 	if err != nil {
 		return nil, err
 	}
@@ -19,10 +19,14 @@ package fourtwentyapi
 import (
 	"bytes"
 	"context"
+	crand "crypto/rand"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -36,6 +40,8 @@ import (
 	"github.com/420integrated/go-420coin/consensus/clique"
 	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/rawdb"
+	"github.com/420integrated/go-420coin/core/state"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/crypto"
@@ -64,6 +70,52 @@ func (s *PublicFourtwentycoinAPI) SmokePrice(ctx context.Context) (*hexutil.Big,
 	return (*hexutil.Big)(price), err
 }
 
+// SmokePriceHistoryResult is the smoke price trend of a range of blocks, as
+// returned by SmokePriceHistory.
+type SmokePriceHistoryResult struct {
+	OldestBlock    *hexutil.Big     `json:"oldestBlock"`
+	Prices         [][]*hexutil.Big `json:"prices,omitempty"`
+	SmokeUsedRatio []float64        `json:"smokeUsedRatio"`
+}
+
+// SmokePriceHistory returns the requested percentiles of the transaction smoke
+// prices and the smoke used ratio of up to blockCount blocks ending at lastBlock,
+// allowing wallets to display fee trends instead of a single suggested price.
+func (s *PublicFourtwentycoinAPI) SmokePriceHistory(ctx context.Context, blockCount hexutil.Uint, lastBlock rpc.BlockNumber, percentiles []float64) (*SmokePriceHistoryResult, error) {
+	oldest, prices, ratios, err := s.b.SmokePriceHistory(ctx, int(blockCount), lastBlock, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	result := &SmokePriceHistoryResult{
+		OldestBlock:    (*hexutil.Big)(oldest),
+		SmokeUsedRatio: ratios,
+	}
+	if prices != nil {
+		result.Prices = make([][]*hexutil.Big, len(prices))
+		for i, block := range prices {
+			result.Prices[i] = make([]*hexutil.Big, len(block))
+			for j, price := range block {
+				result.Prices[i][j] = (*hexutil.Big)(price)
+			}
+		}
+	}
+	return result, nil
+}
+
+// HashMessage returns the hash signed by fourtwenty_sign and personal_sign for the
+// given message:
+// keccak256("\x19420coin Signed Message:\n"${message length}${message})
+func (s *PublicFourtwentycoinAPI) HashMessage(data hexutil.Bytes) hexutil.Bytes {
+	return accounts.TextHash(data)
+}
+
+// EcRecover returns the address of the account which signed the given message
+// via fourtwenty_sign or personal_sign, applying the signed message prefix (see
+// HashMessage). The V value of the signature may be either 27/28 or 0/1.
+func (s *PublicFourtwentycoinAPI) EcRecover(data, sig hexutil.Bytes) (common.Address, error) {
+	return accounts.TextRecover(data, sig)
+}
+
 // Syncing returns false in case the node is currently not syncing with the network. It can be up to date or has not
 // yet received the latest block headers from its pears. In case it is synchronizing:
 // - startingBlock: block number this node started to synchronise from
@@ -125,6 +177,90 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	return content
 }
 
+// ContentFrom returns the transactions contained within the transaction pool
+// sent by the given address.
+func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCTransaction {
+	content := make(map[string]map[string]*RPCTransaction, 2)
+	pending, queue := s.b.TxPoolContentFrom(addr)
+
+	// Build the pending transactions
+	dump := make(map[string]*RPCTransaction, len(pending))
+	for _, tx := range pending {
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	content["pending"] = dump
+
+	// Build the queued transactions
+	dump = make(map[string]*RPCTransaction, len(queue))
+	for _, tx := range queue {
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	content["queued"] = dump
+
+	return content
+}
+
+// TxPoolFilter selects the transactions returned by ContentFiltered. Unset
+// fields match any transaction.
+type TxPoolFilter struct {
+	From          *common.Address `json:"from"`
+	To            *common.Address `json:"to"`
+	MinSmokePrice *hexutil.Big    `json:"minSmokePrice"`
+	MaxSmokePrice *hexutil.Big    `json:"maxSmokePrice"`
+}
+
+// matches returns whether the given transaction passes the destination and the
+// smoke price filters.
+func (f *TxPoolFilter) matches(tx *types.Transaction) bool {
+	if f.To != nil && (tx.To() == nil || *tx.To() != *f.To) {
+		return false
+	}
+	if f.MinSmokePrice != nil && tx.SmokePrice().Cmp(f.MinSmokePrice.ToInt()) < 0 {
+		return false
+	}
+	if f.MaxSmokePrice != nil && tx.SmokePrice().Cmp(f.MaxSmokePrice.ToInt()) > 0 {
+		return false
+	}
+	return true
+}
+
+// ContentFiltered returns the transactions contained within the transaction pool
+// which match the given sender, destination and smoke price range. Accounts
+// without any matching transaction are omitted.
+func (s *PublicTxPoolAPI) ContentFiltered(filter TxPoolFilter) map[string]map[string]map[string]*RPCTransaction {
+	content := map[string]map[string]map[string]*RPCTransaction{
+		"pending": make(map[string]map[string]*RPCTransaction),
+		"queued":  make(map[string]map[string]*RPCTransaction),
+	}
+	// Avoid copying the entire pool if only a single sender is inspected
+	var pending, queue map[common.Address]types.Transactions
+	if filter.From != nil {
+		txs, queued := s.b.TxPoolContentFrom(*filter.From)
+		pending = map[common.Address]types.Transactions{*filter.From: txs}
+		queue = map[common.Address]types.Transactions{*filter.From: queued}
+	} else {
+		pending, queue = s.b.TxPoolContent()
+	}
+	// Flatten the matching transactions
+	flatten := func(accounts map[common.Address]types.Transactions, content map[string]map[string]*RPCTransaction) {
+		for account, txs := range accounts {
+			dump := make(map[string]*RPCTransaction)
+			for _, tx := range txs {
+				if filter.matches(tx) {
+					dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+				}
+			}
+			if len(dump) > 0 {
+				content[account.Hex()] = dump
+			}
+		}
+	}
+	flatten(pending, content["pending"])
+	flatten(queue, content["queued"])
+
+	return content
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	pending, queue := s.b.Stats()
@@ -169,6 +305,60 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// RPCDroppedTransaction is the notification sent to replacements subscribers
+// when a transaction leaves the pool without being included in a block.
+type RPCDroppedTransaction struct {
+	Hash        common.Hash    `json:"hash"`
+	From        common.Address `json:"from"`
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	SmokePrice  *hexutil.Big   `json:"smokePrice"`
+	Replacement *common.Hash   `json:"replacement"`
+	Reason      string         `json:"reason"`
+}
+
+// Replacements creates a subscription that is triggered each time a pending
+// transaction is replaced by another one with the same sender and nonce, or is
+// dropped from the pool (e.g. underpriced or expired).
+func (s *PublicTxPoolAPI) Replacements(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		drops := make(chan core.DroppedTxEvent, 128)
+		dropSub := s.b.SubscribeDroppedTxEvent(drops)
+		defer dropSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-drops:
+				signer := types.MakeSigner(s.b.ChainConfig(), s.b.CurrentHeader().Number)
+				from, _ := types.Sender(signer, ev.Tx)
+
+				drop := &RPCDroppedTransaction{
+					Hash:       ev.Tx.Hash(),
+					From:       from,
+					Nonce:      hexutil.Uint64(ev.Tx.Nonce()),
+					SmokePrice: (*hexutil.Big)(ev.Tx.SmokePrice()),
+					Reason:     ev.Reason,
+				}
+				if ev.Replacement != nil {
+					hash := ev.Replacement.Hash()
+					drop.Replacement = &hash
+				}
+				notifier.Notify(rpcSub.ID, drop)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -308,6 +498,62 @@ func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (commo
 	return acc.Address, err
 }
 
+// maxMnemonicAccounts is the maximum number of accounts derived from a mnemonic
+// in a single import.
+const maxMnemonicAccounts = 100
+
+// NewMnemonic generates a new BIP-39 mnemonic from the given number of entropy
+// bits (128 to 256, in steps of 32, defaulting to 256). The mnemonic isn't stored,
+// it must be written down and imported via ImportMnemonic.
+func (s *PrivateAccountAPI) NewMnemonic(bits *int) (string, error) {
+	size := 256
+	if bits != nil {
+		size = *bits
+	}
+	entropy, err := bip39.NewEntropy(size)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ImportMnemonic derives count consecutive accounts from a BIP-39 mnemonic,
+// starting at the given BIP-32 derivation path (m/44'/60'/0'/0/0 by default) and
+// increasing its last component, and stores them into the key directory, encrypting
+// them with the password. Accounts already in the keystore are skipped.
+func (s *PrivateAccountAPI) ImportMnemonic(mnemonic string, password string, path *string, count *int) ([]common.Address, error) {
+	base := accounts.DefaultBaseDerivationPath
+	if path != nil {
+		var err error
+		if base, err = accounts.ParseDerivationPath(*path); err != nil {
+			return nil, err
+		}
+	}
+	n := 1
+	if count != nil {
+		n = *count
+	}
+	if n < 1 || n > maxMnemonicAccounts {
+		return nil, fmt.Errorf("account count must be between 1 and %d", maxMnemonicAccounts)
+	}
+	ks, err := fetchKeystore(s.am)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		next  = accounts.DefaultIterator(base)
+		addrs = make([]common.Address, 0, n)
+	)
+	for i := 0; i < n; i++ {
+		acc, err := ks.ImportMnemonic(mnemonic, "", next(), password)
+		if err != nil && err != keystore.ErrAccountAlreadyExists {
+			return nil, err
+		}
+		addrs = append(addrs, acc.Address)
+	}
+	return addrs, nil
+}
+
 // UnlockAccount will unlock the account associated with the given address with
 // the given password for duration seconds. If duration is nil it will use a
 // default of 300 seconds (5 minutes). It returns an indication if the account was unlocked.
@@ -403,14 +649,14 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	}
 	// Before actually sign the transaction, ensure the transaction fee is reasonable.
 	if err := checkTxFee(args.SmokePrice.ToInt(), uint64(*args.Smoke), s.b.RPCTxFeeCap()); err != nil {
-		return nil, err
+		return nil, structuredError(s.b, err)
 	}
 	signed, err := s.signTransaction(ctx, &args, passwd)
 	if err != nil {
 		log.Warn("Failed transaction sign attempt", "from", args.From, "to", args.To, "value", args.Value.ToInt(), "err", err)
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(signed)
+	data, err := signed.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -551,6 +797,47 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
 }
 
+// maxBatchAddresses is the maximum number of accounts which can be queried in a
+// single GetBalances or GetTransactionCounts call.
+const maxBatchAddresses = 1024
+
+// GetBalances returns the balances of the given addresses in the state of the
+// given block. All balances are read from the same state, so they are
+// consistent with each other.
+func (s *PublicBlockChainAPI) GetBalances(ctx context.Context, addresses []common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]*hexutil.Big, error) {
+	if len(addresses) > maxBatchAddresses {
+		return nil, fmt.Errorf("too many addresses: have %d, max %d", len(addresses), maxBatchAddresses)
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balances := make([]*hexutil.Big, len(addresses))
+	for i, address := range addresses {
+		balances[i] = (*hexutil.Big)(state.GetBalance(address))
+	}
+	return balances, state.Error()
+}
+
+// GetTransactionCounts returns the nonces of the given addresses in the state
+// of the given block. Like GetBalances, all nonces are read from the same state.
+// For the pending block, this is the state of the pending block, which doesn't
+// include transactions that are only known to the pool.
+func (s *PublicBlockChainAPI) GetTransactionCounts(ctx context.Context, addresses []common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]hexutil.Uint64, error) {
+	if len(addresses) > maxBatchAddresses {
+		return nil, fmt.Errorf("too many addresses: have %d, max %d", len(addresses), maxBatchAddresses)
+	}
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	nonces := make([]hexutil.Uint64, len(addresses))
+	for i, address := range addresses {
+		nonces[i] = hexutil.Uint64(state.GetNonce(address))
+	}
+	return nonces, state.Error()
+}
+
 // Result structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -864,9 +1151,18 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.Blo
 	}
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
+	stopped := make(chan struct{})
 	go func() {
 		<-ctx.Done()
 		evm.Cancel()
+		close(stopped)
+	}()
+	// Once the call returned and the canceller exited, nothing references
+	// the EVM anymore and the backend may reuse it.
+	defer func() {
+		cancel()
+		<-stopped
+		b.ReleaseEVM(evm, header)
 	}()
 
 	// Setup the smoke pool (also for unmetered requests)
@@ -927,9 +1223,9 @@ func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNrOr
 	if overrides != nil {
 		accounts = *overrides
 	}
-	result, err := DoCall(ctx, s.b, args, blockNrOrHash, accounts, vm.Config{}, 5*time.Second, s.b.RPCSmokeCap())
+	result, err := DoCall(ctx, s.b, args, blockNrOrHash, accounts, vm.Config{NoBaseFee: true}, 5*time.Second, s.b.RPCSmokeCap())
 	if err != nil {
-		return nil, err
+		return nil, structuredError(s.b, err)
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if len(result.Revert()) > 0 {
@@ -973,7 +1269,11 @@ func DoEstimateSmoke(ctx context.Context, b Backend, args CallArgs, blockNrOrHas
 		available := new(big.Int).Set(balance)
 		if args.Value != nil {
 			if args.Value.ToInt().Cmp(available) >= 0 {
-				return 0, errors.New("insufficient funds for transfer")
+				return 0, &apiError{
+					core.ErrInsufficientFundsForTransfer,
+					errCodeInsufficientFunds,
+					map[string]interface{}{"have": (*hexutil.Big)(balance), "want": args.Value},
+				}
 			}
 			available.Sub(available, args.Value.ToInt())
 		}
@@ -1001,7 +1301,7 @@ func DoEstimateSmoke(ctx context.Context, b Backend, args CallArgs, blockNrOrHas
 	executable := func(smoke uint64) (bool, *core.ExecutionResult, error) {
 		args.Smoke = (*hexutil.Uint64)(&smoke)
 
-		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, vm.Config{}, 0, smokeCap)
+		result, err := DoCall(ctx, b, args, blockNrOrHash, nil, vm.Config{NoBaseFee: true}, 0, smokeCap)
 		if err != nil {
 			if errors.Is(err, core.ErrIntrinsicSmoke) {
 				return true, nil, nil // Special case, raise smoke limit
@@ -1041,7 +1341,11 @@ func DoEstimateSmoke(ctx context.Context, b Backend, args CallArgs, blockNrOrHas
 				return 0, result.Err
 			}
 			// Otherwise, the specified smoke cap is too low
-			return 0, fmt.Errorf("smoke required exceeds allowance (%d)", cap)
+			return 0, &apiError{
+				fmt.Errorf("smoke required exceeds allowance (%d)", cap),
+				errCodeSmokeAllowance,
+				map[string]interface{}{"allowance": hexutil.Uint64(cap)},
+			}
 		}
 	}
 	return hexutil.Uint64(hi), nil
@@ -1054,7 +1358,8 @@ func (s *PublicBlockChainAPI) EstimateSmoke(ctx context.Context, args CallArgs,
 	if blockNrOrHash != nil {
 		bNrOrHash = *blockNrOrHash
 	}
-	return DoEstimateSmoke(ctx, s.b, args, bNrOrHash, s.b.RPCSmokeCap())
+	smoke, err := DoEstimateSmoke(ctx, s.b, args, bNrOrHash, s.b.RPCSmokeCap())
+	return smoke, structuredError(s.b, err)
 }
 
 // ExecutionResult groups all structured logs emitted by the EVM
@@ -1075,7 +1380,7 @@ type StructLogRes struct {
 	Smoke     uint64             `json:"smoke"`
 	SmokeCost uint64             `json:"smokeCost"`
 	Depth     int                `json:"depth"`
-	Error     error              `json:"error,omitempty"`
+	Error     string             `json:"error,omitempty"`
 	Stack     *[]string          `json:"stack,omitempty"`
 	Memory    *[]string          `json:"memory,omitempty"`
 	Storage   *map[string]string `json:"storage,omitempty"`
@@ -1091,7 +1396,9 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 			Smoke:     trace.Smoke,
 			SmokeCost: trace.SmokeCost,
 			Depth:     trace.Depth,
-			Error:     trace.Err,
+		}
+		if trace.Err != nil {
+			formatted[index].Error = trace.Err.Error()
 		}
 		if trace.Stack != nil {
 			stack := make([]string, len(trace.Stack))
@@ -1120,7 +1427,7 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 
 // RPCMarshalHeader converts the given header to the RPC output .
 func RPCMarshalHeader(head *types.Header) map[string]interface{} {
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"number":             (*hexutil.Big)(head.Number),
 		"hash":               head.Hash(),
 		"parentHash":         head.ParentHash,
@@ -1139,6 +1446,10 @@ func RPCMarshalHeader(head *types.Header) map[string]interface{} {
 		"transactionsRoot":   head.TxHash,
 		"receiptsRoot":       head.ReceiptHash,
 	}
+	if head.BaseFee != nil {
+		result["baseFeePerSmoke"] = (*hexutil.Big)(head.BaseFee)
+	}
+	return result
 }
 
 // RPCMarshalBlock converts the given block to the RPC output which depends on fullTx. If inclTx is true transactions are
@@ -1177,6 +1488,359 @@ func RPCMarshalBlock(block *types.Block, inclTx bool, fullTx bool) (map[string]i
 	return fields, nil
 }
 
+// maxSmokeUsageRange is the maximum number of blocks a single smoke usage query
+// may aggregate over, protecting the node from expensive requests.
+const maxSmokeUsageRange = 10000
+
+// SmokeUsageBucket is the aggregated block fullness of a consecutive range of
+// blocks, as returned by SmokeUsedRatio.
+type SmokeUsageBucket struct {
+	FromBlock      hexutil.Uint64 `json:"fromBlock"`
+	ToBlock        hexutil.Uint64 `json:"toBlock"`
+	SmokeUsed      hexutil.Uint64 `json:"smokeUsed"`
+	SmokeLimit     hexutil.Uint64 `json:"smokeLimit"`
+	SmokeUsedRatio float64        `json:"smokeUsedRatio"`
+	TxsPerBlock    float64        `json:"txsPerBlock"`
+}
+
+// SmokeUsedRatio returns the network utilization between startBlock and endBlock
+// (inclusive), aggregated into buckets of resolution blocks each. Every bucket
+// contains the ratio of the total smoke used to the total smoke limit and the
+// average number of transactions per block.
+func (s *PublicBlockChainAPI) SmokeUsedRatio(ctx context.Context, startBlock, endBlock rpc.BlockNumber, resolution hexutil.Uint64) ([]*SmokeUsageBucket, error) {
+	start, err := s.resolveBlockNumber(ctx, startBlock)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.resolveBlockNumber(ctx, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("start block #%d after end block #%d", start, end)
+	}
+	if end-start >= maxSmokeUsageRange {
+		return nil, fmt.Errorf("block range too large: %d > %d", end-start+1, maxSmokeUsageRange)
+	}
+	if resolution == 0 {
+		resolution = 1
+	}
+	var (
+		buckets []*SmokeUsageBucket
+		txs     uint64
+	)
+	for number := start; number <= end; number++ {
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil || err != nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		if (number-start)%uint64(resolution) == 0 {
+			buckets = append(buckets, &SmokeUsageBucket{FromBlock: hexutil.Uint64(number)})
+			txs = 0
+		}
+		bucket := buckets[len(buckets)-1]
+		bucket.ToBlock = hexutil.Uint64(number)
+		bucket.SmokeUsed += hexutil.Uint64(header.SmokeUsed)
+		bucket.SmokeLimit += hexutil.Uint64(header.SmokeLimit)
+
+		// Transaction counts need the block body, skip the lookup if it's empty
+		if header.TxHash != types.EmptyRootHash {
+			block, err := s.b.BlockByHash(ctx, header.Hash())
+			if block == nil || err != nil {
+				return nil, fmt.Errorf("block #%d body not found", number)
+			}
+			txs += uint64(len(block.Transactions()))
+		}
+		if bucket.SmokeLimit > 0 {
+			bucket.SmokeUsedRatio = float64(bucket.SmokeUsed) / float64(bucket.SmokeLimit)
+		}
+		bucket.TxsPerBlock = float64(txs) / float64(bucket.ToBlock-bucket.FromBlock+1)
+	}
+	return buckets, nil
+}
+
+const (
+	// defaultHashrateBlocks is the number of recent blocks the network hashrate
+	// is estimated over if the caller doesn't specify it.
+	defaultHashrateBlocks = 120
+
+	// maxHashrateBlocks is the maximum number of blocks a single hashrate estimate
+	// may span, protecting the node from expensive requests.
+	maxHashrateBlocks = 10000
+)
+
+// NetworkHashrate estimates the hashes per second of the whole network from the
+// difficulties and timestamps of the given number of most recent blocks, or of
+// the last defaultHashrateBlocks if unspecified.
+func (s *PublicBlockChainAPI) NetworkHashrate(ctx context.Context, blocks *hexutil.Uint64) (*hexutil.Big, error) {
+	count := uint64(defaultHashrateBlocks)
+	if blocks != nil && *blocks > 0 {
+		count = uint64(*blocks)
+	}
+	if count > maxHashrateBlocks {
+		return nil, fmt.Errorf("block count too large: %d > %d", count, maxHashrateBlocks)
+	}
+	head := s.b.CurrentHeader()
+	if number := head.Number.Uint64(); count > number {
+		count = number
+	}
+	if count == 0 {
+		return (*hexutil.Big)(new(big.Int)), nil
+	}
+	// Sum the work of the blocks mined since the timestamp of the first parent
+	work := new(big.Int)
+	header := head
+	for i := uint64(0); i < count; i++ {
+		work.Add(work, header.Difficulty)
+
+		parent, err := s.b.HeaderByHash(ctx, header.ParentHash)
+		if parent == nil || err != nil {
+			return nil, fmt.Errorf("block #%d not found", header.Number.Uint64()-1)
+		}
+		header = parent
+	}
+	elapsed := head.Time - header.Time
+	if elapsed == 0 {
+		elapsed = 1
+	}
+	return (*hexutil.Big)(work.Div(work, new(big.Int).SetUint64(elapsed))), nil
+}
+
+// RewardEra is a range of blocks sharing the same block reward and split, as
+// returned by RewardSchedule.
+type RewardEra struct {
+	Name             string          `json:"name"`
+	FromBlock        hexutil.Uint64  `json:"fromBlock"`
+	ToBlock          *hexutil.Uint64 `json:"toBlock"`
+	BlockReward      *hexutil.Big    `json:"blockReward"`
+	MinerPercent     uint64          `json:"minerPercent"`
+	VeteransPercent  uint64          `json:"veteransPercent"`
+	FollowersPercent uint64          `json:"followersPercent"`
+}
+
+// RewardScheduleResult is the emission schedule along with the reward recipient
+// addresses currently configured in the reward contract and the routing of the
+// transaction fees to the Veterans Fund.
+type RewardScheduleResult struct {
+	Eras               []*RewardEra    `json:"eras"`
+	RewardContract     common.Address  `json:"rewardContract"`
+	VeteransFund       common.Address  `json:"veteransFund"`
+	Followers          common.Address  `json:"followers"`
+	AddressesAtBlock   hexutil.Uint64  `json:"addressesAtBlock"`
+	VeteransFeeBlock   *hexutil.Uint64 `json:"veteransFeeBlock"`
+	VeteransFeePercent uint64          `json:"veteransFeePercent"`
+}
+
+// RewardSchedule returns the per-era block reward emission schedule applied by
+// the consensus engine, along with the Veterans Fund and followers addresses the
+// next block's rewards are paid to and the share of its transaction fees routed
+// to the Veterans Fund.
+func (s *PublicBlockChainAPI) RewardSchedule(ctx context.Context) (*RewardScheduleResult, error) {
+	genesis, err := s.b.HeaderByNumber(ctx, 0)
+	if genesis == nil || err != nil {
+		return nil, errors.New("genesis block not found")
+	}
+	state, head, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	var (
+		config = s.b.ChainConfig()
+		next   = new(big.Int).Add(head.Number, common.Big1)
+	)
+	veterans, followers := ethash.RewardAddresses(config, state, next, genesis)
+
+	result := &RewardScheduleResult{
+		RewardContract:   ethash.RewardContract(config, next, genesis),
+		VeteransFund:     veterans,
+		Followers:        followers,
+		AddressesAtBlock: hexutil.Uint64(next.Uint64()),
+	}
+	if config.VeteransFeeBlock != nil {
+		block := hexutil.Uint64(config.VeteransFeeBlock.Uint64())
+		result.VeteransFeeBlock = &block
+	}
+	result.VeteransFeePercent = config.VeteransFeePercentAt(next)
+	for _, era := range ethash.RewardSchedule(config) {
+		rpcEra := &RewardEra{
+			Name:             era.Name,
+			FromBlock:        hexutil.Uint64(era.FromBlock),
+			BlockReward:      (*hexutil.Big)(era.Reward),
+			MinerPercent:     era.Miner,
+			VeteransPercent:  era.Veterans,
+			FollowersPercent: era.Followers,
+		}
+		if era.ToBlock != nil {
+			to := hexutil.Uint64(*era.ToBlock)
+			rpcEra.ToBlock = &to
+		}
+		result.Eras = append(result.Eras, rpcEra)
+	}
+	return result, nil
+}
+
+// maxUncleStatsRange is the maximum number of blocks a single uncle statistics
+// query may aggregate over, protecting the node from expensive requests.
+const maxUncleStatsRange = 10000
+
+// UncleInclusion is an uncle included in a canonical block, along with the split
+// reward paid for it.
+type UncleInclusion struct {
+	Number          hexutil.Uint64 `json:"number"`
+	Hash            common.Hash    `json:"hash"`
+	Miner           common.Address `json:"miner"`
+	IncludedIn      common.Hash    `json:"includedIn"`
+	IncludedAt      hexutil.Uint64 `json:"includedAt"`
+	MinerReward     *hexutil.Big   `json:"minerReward"`
+	VeteransReward  *hexutil.Big   `json:"veteransReward"`
+	FollowersReward *hexutil.Big   `json:"followersReward"`
+}
+
+// UncleMinerStats aggregates the uncles mined and included by a single miner.
+type UncleMinerStats struct {
+	Miner            common.Address `json:"miner"`
+	Blocks           hexutil.Uint64 `json:"blocks"`           // Canonical blocks mined
+	Uncles           hexutil.Uint64 `json:"uncles"`           // Uncles mined which got included
+	UncleRewards     *hexutil.Big   `json:"uncleRewards"`     // Miner share of the included uncles
+	Inclusions       hexutil.Uint64 `json:"inclusions"`       // Uncles included in the mined blocks
+	InclusionRewards *hexutil.Big   `json:"inclusionRewards"` // Miner share of the inclusion bonuses
+}
+
+// UncleStats is the uncle inclusion summary of a range of canonical blocks, as
+// returned by UncleStats.
+type UncleStats struct {
+	FromBlock        hexutil.Uint64     `json:"fromBlock"`
+	ToBlock          hexutil.Uint64     `json:"toBlock"`
+	Blocks           hexutil.Uint64     `json:"blocks"`
+	UncleCount       hexutil.Uint64     `json:"uncleCount"`
+	UncleRate        float64            `json:"uncleRate"`
+	UncleRewards     *hexutil.Big       `json:"uncleRewards"`     // Total paid for uncles, all shares included
+	InclusionRewards *hexutil.Big       `json:"inclusionRewards"` // Total paid for inclusions, all shares included
+	Uncles           []*UncleInclusion  `json:"uncles"`
+	Miners           []*UncleMinerStats `json:"miners"`
+}
+
+// UncleStats returns the uncles included between startBlock and endBlock
+// (inclusive), the rewards the consensus engine paid for them and to the miners
+// including them, as well as per-miner aggregates.
+func (s *PublicBlockChainAPI) UncleStats(ctx context.Context, startBlock, endBlock rpc.BlockNumber) (*UncleStats, error) {
+	start, err := s.resolveBlockNumber(ctx, startBlock)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.resolveBlockNumber(ctx, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("start block #%d after end block #%d", start, end)
+	}
+	if end-start >= maxUncleStatsRange {
+		return nil, fmt.Errorf("block range too large: %d > %d", end-start+1, maxUncleStatsRange)
+	}
+	var (
+		stats = &UncleStats{
+			FromBlock: hexutil.Uint64(start),
+			ToBlock:   hexutil.Uint64(end),
+			Blocks:    hexutil.Uint64(end - start + 1),
+			Uncles:    []*UncleInclusion{},
+		}
+		uncleRewards     = new(big.Int)
+		inclusionRewards = new(big.Int)
+		miners           = make(map[common.Address]*UncleMinerStats)
+	)
+	miner := func(addr common.Address) *UncleMinerStats {
+		if miners[addr] == nil {
+			miners[addr] = &UncleMinerStats{Miner: addr, UncleRewards: new(hexutil.Big), InclusionRewards: new(hexutil.Big)}
+		}
+		return miners[addr]
+	}
+	total := func(shares *ethash.RewardShares) *big.Int {
+		sum := new(big.Int).Add(shares.Miner, shares.Veterans)
+		if shares.Followers != nil {
+			sum.Add(sum, shares.Followers)
+		}
+		return sum
+	}
+	for number := start; number <= end; number++ {
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if header == nil || err != nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		miner(header.Coinbase).Blocks++
+
+		// Uncles need the block body, skip the lookup if there are none
+		if header.UncleHash == types.EmptyUncleHash {
+			continue
+		}
+		block, err := s.b.BlockByHash(ctx, header.Hash())
+		if block == nil || err != nil {
+			return nil, fmt.Errorf("block #%d body not found", number)
+		}
+		uncles := block.Uncles()
+		if len(uncles) == 0 {
+			continue
+		}
+		blockShares, uncleShares := ethash.BlockRewards(s.b.ChainConfig(), header.Number, uncles)
+		for i, uncle := range uncles {
+			inclusion := &UncleInclusion{
+				Number:          hexutil.Uint64(uncle.Number.Uint64()),
+				Hash:            uncle.Hash(),
+				Miner:           uncle.Coinbase,
+				IncludedIn:      header.Hash(),
+				IncludedAt:      hexutil.Uint64(number),
+				MinerReward:     (*hexutil.Big)(uncleShares[i].Miner),
+				VeteransReward:  (*hexutil.Big)(uncleShares[i].Veterans),
+				FollowersReward: (*hexutil.Big)(new(big.Int)),
+			}
+			if uncleShares[i].Followers != nil {
+				inclusion.FollowersReward = (*hexutil.Big)(uncleShares[i].Followers)
+			}
+			stats.Uncles = append(stats.Uncles, inclusion)
+			uncleRewards.Add(uncleRewards, total(uncleShares[i]))
+
+			m := miner(uncle.Coinbase)
+			m.Uncles++
+			m.UncleRewards.ToInt().Add(m.UncleRewards.ToInt(), uncleShares[i].Miner)
+		}
+		// The inclusion bonus is whatever the block paid above an uncle-less one
+		baseShares, _ := ethash.BlockRewards(s.b.ChainConfig(), header.Number, nil)
+		inclusionRewards.Add(inclusionRewards, new(big.Int).Sub(total(blockShares), total(baseShares)))
+
+		m := miner(header.Coinbase)
+		m.Inclusions += hexutil.Uint64(len(uncles))
+		m.InclusionRewards.ToInt().Add(m.InclusionRewards.ToInt(), new(big.Int).Sub(blockShares.Miner, baseShares.Miner))
+	}
+	stats.UncleCount = hexutil.Uint64(len(stats.Uncles))
+	stats.UncleRate = float64(stats.UncleCount) / float64(stats.Blocks)
+	stats.UncleRewards = (*hexutil.Big)(uncleRewards)
+	stats.InclusionRewards = (*hexutil.Big)(inclusionRewards)
+
+	stats.Miners = make([]*UncleMinerStats, 0, len(miners))
+	for _, m := range miners {
+		stats.Miners = append(stats.Miners, m)
+	}
+	sort.Slice(stats.Miners, func(i, j int) bool {
+		if stats.Miners[i].Blocks != stats.Miners[j].Blocks {
+			return stats.Miners[i].Blocks > stats.Miners[j].Blocks
+		}
+		return bytes.Compare(stats.Miners[i].Miner[:], stats.Miners[j].Miner[:]) < 0
+	})
+	return stats, nil
+}
+
+// resolveBlockNumber converts a possibly symbolic block number into a concrete
+// one. The pending block is not supported.
+func (s *PublicBlockChainAPI) resolveBlockNumber(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	switch number {
+	case rpc.PendingBlockNumber:
+		return 0, errors.New("pending block not supported")
+	case rpc.LatestBlockNumber:
+		return s.b.CurrentHeader().Number.Uint64(), nil
+	}
+	return uint64(number), nil
+}
+
 // rpcMarshalHeader uses the generalized output filler, then adds the total difficulty field, which requires
 // a `PublicBlockchainAPI`.
 func (s *PublicBlockChainAPI) rpcMarshalHeader(ctx context.Context, header *types.Header) map[string]interface{} {
@@ -1205,6 +1869,10 @@ type RPCTransaction struct {
 	From               common.Address  `json:"from"`
 	Smoke              hexutil.Uint64  `json:"smoke"`
 	SmokePrice         *hexutil.Big    `json:"smokePrice"`
+	SmokeTipCap        *hexutil.Big    `json:"maxPriorityFeePerSmoke,omitempty"`
+	Type               hexutil.Uint64  `json:"type"`
+	ChainID            *hexutil.Big    `json:"chainId,omitempty"`
+	Accesses           *types.AccessList `json:"accessList,omitempty"`
 	Hash               common.Hash     `json:"hash"`
 	Input              hexutil.Bytes   `json:"input"`
 	Nonce              hexutil.Uint64  `json:"nonce"`
@@ -1221,7 +1889,7 @@ type RPCTransaction struct {
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.NewEIP2930Signer(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
@@ -1239,6 +1907,15 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		R:          (*hexutil.Big)(r),
 		S:          (*hexutil.Big)(s),
 	}
+	if tx.IsDynamicFee() {
+		result.SmokeTipCap = (*hexutil.Big)(tx.SmokeTipCap())
+	}
+	if tx.Type() != types.LegacyTxType {
+		al := tx.AccessList()
+		result.Type = hexutil.Uint64(tx.Type())
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.Accesses = &al
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = &blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1267,7 +1944,7 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.By
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	blob, _ := rlp.EncodeToBytes(txs[index])
+	blob, _ := txs[index].MarshalBinary()
 	return blob
 }
 
@@ -1393,8 +2070,53 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 			return nil, nil
 		}
 	}
-	// Serialize to RLP and return
-	return rlp.EncodeToBytes(tx)
+	// Serialize to the canonical encoding and return
+	return tx.MarshalBinary()
+}
+
+// TransactionConfirmations is the inclusion status of a transaction.
+type TransactionConfirmations struct {
+	BlockHash     *common.Hash   `json:"blockHash"`
+	BlockNumber   *hexutil.Big   `json:"blockNumber"`
+	Confirmations hexutil.Uint64 `json:"confirmations"`
+	Canonical     bool           `json:"canonical"`
+	Pending       bool           `json:"pending"`
+}
+
+// GetTransactionConfirmations returns the number of blocks confirming the
+// transaction with the given hash, including its own block, and whether that
+// block is on the canonical chain. If a reorg moved the block off the chain,
+// it is reported as non-canonical with zero confirmations instead of being
+// counted against the new head. Transactions still in the pool are reported
+// as pending, unknown ones as null.
+func (s *PublicTransactionPoolAPI) GetTransactionConfirmations(ctx context.Context, hash common.Hash) (*TransactionConfirmations, error) {
+	tx, blockHash, blockNumber, _, err := s.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		if s.b.GetPoolTransaction(hash) != nil {
+			return &TransactionConfirmations{Pending: true}, nil
+		}
+		return nil, nil
+	}
+	result := &TransactionConfirmations{
+		BlockHash:   &blockHash,
+		BlockNumber: (*hexutil.Big)(new(big.Int).SetUint64(blockNumber)),
+	}
+	// Read the head before the canonical hash, so that a reorg in between can
+	// only make the block appear non-canonical, never overcount confirmations.
+	head := s.b.CurrentHeader()
+	header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header == nil || header.Hash() != blockHash || blockNumber > head.Number.Uint64() {
+		return result, nil
+	}
+	result.Canonical = true
+	result.Confirmations = hexutil.Uint64(head.Number.Uint64() - blockNumber + 1)
+	return result, nil
 }
 
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
@@ -1414,7 +2136,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.NewEIP2930Signer(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 
@@ -1423,18 +2145,25 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		"blockNumber":         hexutil.Uint64(blockNumber),
 		"transactionHash":     hash,
 		"transactionIndex":    hexutil.Uint64(index),
+		"type":                hexutil.Uint(tx.Type()),
 		"from":                from,
 		"to":                  tx.To(),
 		"smokeUsed":           hexutil.Uint64(receipt.SmokeUsed),
 		"cumulativeSmokeUsed": hexutil.Uint64(receipt.CumulativeSmokeUsed),
+		"effectiveSmokePrice": (*hexutil.Big)(receipt.EffectiveSmokePrice),
+		"smokeReturned":       hexutil.Uint64(receipt.SmokeReturned),
 		"contractAddress":     nil,
 		"logs":                receipt.Logs,
 		"logsBloom":           receipt.Bloom,
 	}
 
-	// Assign receipt status or post state.
+	// Assign receipt status or post state, reporting both if the status of a
+	// pre-Byzantium receipt was backfilled.
 	if len(receipt.PostState) > 0 {
 		fields["root"] = hexutil.Bytes(receipt.PostState)
+		if rawdb.HasReceiptStatuses(s.b.ChainDb(), blockHash, blockNumber) {
+			fields["status"] = hexutil.Uint(receipt.Status)
+		}
 	} else {
 		fields["status"] = hexutil.Uint(receipt.Status)
 	}
@@ -1445,6 +2174,10 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
+	// The smoke refund is only known for receipts stored since database version 9
+	if receipt.SmokeRefund != nil {
+		fields["smokeRefund"] = hexutil.Uint64(*receipt.SmokeRefund)
+	}
 	return fields, nil
 }
 
@@ -1469,6 +2202,13 @@ type SendTxArgs struct {
 	SmokePrice *hexutil.Big      `json:"smokePrice"`
 	Value      *hexutil.Big      `json:"value"`
 	Nonce      *hexutil.Uint64   `json:"nonce"`
+	// A tip cap makes the transaction a dynamic fee one, with the smoke price
+	// acting as its fee cap.
+	MaxPriorityFeePerSmoke *hexutil.Big `json:"maxPriorityFeePerSmoke"`
+	// An access list makes the transaction an EIP-2930 typed one, bound to the
+	// chain id, which defaults to the one of the node.
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
 	// We accept "data" and "input" for backwards-compatibility reasons. "input" is the
 	// newer name and should be preferred by clients.
 	Data       *hexutil.Bytes    `json:"data"`
@@ -1497,6 +2237,14 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
 		return errors.New(`both "data" and "input" are set and not equal. Please use "input" to pass transaction call data`)
 	}
+	if args.AccessList != nil {
+		if args.MaxPriorityFeePerSmoke != nil {
+			return errors.New(`both "accessList" and "maxPriorityFeePerSmoke" are set, access list transactions pay their smoke price`)
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(b.ChainConfig().ChainID)
+		}
+	}
 	if args.To == nil {
 		// Contract creation
 		var input []byte
@@ -1529,6 +2277,11 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		if err != nil {
 			return err
 		}
+		// The estimate runs without the access list, add the smoke it costs upfront
+		if args.AccessList != nil {
+			estimated += hexutil.Uint64(uint64(len(*args.AccessList))*params.TxAccessListAddressSmoke +
+				uint64(args.AccessList.StorageKeys())*params.TxAccessListStorageKeySmoke)
+		}
 		args.Smoke = &estimated
 		log.Trace("Estimate smoke usage automatically", "smoke", args.Smoke)
 	}
@@ -1542,6 +2295,12 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Data != nil {
 		input = *args.Data
 	}
+	if args.AccessList != nil {
+		return types.NewAccessListTransaction((*big.Int)(args.ChainID), uint64(*args.Nonce), args.To, (*big.Int)(args.Value), uint64(*args.Smoke), (*big.Int)(args.SmokePrice), *args.AccessList, input)
+	}
+	if args.MaxPriorityFeePerSmoke != nil {
+		return types.NewDynamicFeeTransaction(uint64(*args.Nonce), args.To, (*big.Int)(args.Value), uint64(*args.Smoke), (*big.Int)(args.SmokePrice), (*big.Int)(args.MaxPriorityFeePerSmoke), input)
+	}
 	if args.To == nil {
 		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Smoke), (*big.Int)(args.SmokePrice), input)
 	}
@@ -1550,13 +2309,13 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
-	// If the transaction fee cap is already specified, ensure the
-	// smoke fee of the given transaction is _reasonable_.
-	if err := checkTxFee(tx.SmokePrice(), tx.Smoke(), b.RPCTxFeeCap()); err != nil {
-		return common.Hash{}, err
+	// Reject transactions that could never be executed before they reach the
+	// pool, so that wallets get an actionable error instead of a stuck tx.
+	if err := checkTxPreconditions(ctx, b, tx); err != nil {
+		return common.Hash{}, structuredError(b, err)
 	}
 	if err := b.SendTx(ctx, tx); err != nil {
-		return common.Hash{}, err
+		return common.Hash{}, structuredError(b, err)
 	}
 	if tx.To() == nil {
 		signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
@@ -1613,7 +2372,7 @@ func (s *PublicTransactionPoolAPI) FillTransaction(ctx context.Context, args Sen
 	}
 	// Assemble the transaction and obtain rlp
 	tx := args.toTransaction()
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -1624,7 +2383,7 @@ func (s *PublicTransactionPoolAPI) FillTransaction(ctx context.Context, args Sen
 // The sender is responsible for signing the transaction and using the correct nonce.
 func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
 	tx := new(types.Transaction)
-	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
 		return common.Hash{}, err
 	}
 	return SubmitTransaction(ctx, s.b, tx)
@@ -1679,13 +2438,13 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	}
 	// Before actually sign the transaction, ensure the transaction fee is reasonable.
 	if err := checkTxFee(args.SmokePrice.ToInt(), uint64(*args.Smoke), s.b.RPCTxFeeCap()); err != nil {
-		return nil, err
+		return nil, structuredError(s.b, err)
 	}
 	tx, err := s.sign(args.From, args.toTransaction())
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -1709,7 +2468,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if tx.Protected() {
-			signer = types.NewEIP155Signer(tx.ChainId())
+			signer = types.NewEIP2930Signer(tx.ChainId())
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, exists := accounts[from]; exists {
@@ -1740,7 +2499,7 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 		smoke = uint64(*smokeLimit)
 	}
 	if err := checkTxFee(price, smoke, s.b.RPCTxFeeCap()); err != nil {
-		return common.Hash{}, err
+		return common.Hash{}, structuredError(s.b, err)
 	}
 	// Iterate the pending list for replacement
 	pending, err := s.b.GetPoolTransactions()
@@ -1750,7 +2509,7 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	for _, p := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if p.Protected() {
-			signer = types.NewEIP155Signer(p.ChainId())
+			signer = types.NewEIP2930Signer(p.ChainId())
 		}
 		wantSigHash := signer.Hash(matchTx)
 
@@ -1862,6 +2621,21 @@ func (api *PublicDebugAPI) SeedHash(ctx context.Context, number uint64) (string,
 // debugging endpoint.
 type PrivateDebugAPI struct {
 	b Backend
+
+	setHeadLock    sync.Mutex      // Protects the pending rewind request
+	setHeadPending *setHeadRequest // Rewind awaiting confirmation via debug_setHead
+}
+
+// setHeadTokenTTL is the time window within which a rewind requested via
+// debug_requestSetHead must be confirmed.
+const setHeadTokenTTL = time.Minute
+
+// setHeadRequest is a chain rewind waiting for operator confirmation.
+type setHeadRequest struct {
+	token  string      // Random confirmation token handed out to the operator
+	number uint64      // Block number to rewind to
+	head   common.Hash // Chain head at the time of the request
+	expiry time.Time   // Deadline after which the token is rejected
 }
 
 // NewPrivateDebugAPI creates a new API definition for the private debug methods
@@ -1893,19 +2667,71 @@ func (api *PrivateDebugAPI) ChaindbCompact() error {
 	return nil
 }
 
-// SetHead rewinds the head of the blockchain to a previous block.
-func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) {
-	api.b.SetHead(uint64(number))
+// RequestSetHead schedules a rewind of the blockchain to a previous block and
+// returns a confirmation token which must be passed to SetHead within a minute.
+// The request is invalidated if the chain head changes in the meantime.
+func (api *PrivateDebugAPI) RequestSetHead(number hexutil.Uint64) (string, error) {
+	head := api.b.CurrentHeader()
+	if uint64(number) > head.Number.Uint64() {
+		return "", fmt.Errorf("rewind target #%d above current head #%d", number, head.Number)
+	}
+	var raw [16]byte
+	if _, err := crand.Read(raw[:]); err != nil {
+		return "", err
+	}
+	token := hexutil.Encode(raw[:])
+
+	api.setHeadLock.Lock()
+	defer api.setHeadLock.Unlock()
+
+	api.setHeadPending = &setHeadRequest{
+		token:  token,
+		number: uint64(number),
+		head:   head.Hash(),
+		expiry: time.Now().Add(setHeadTokenTTL),
+	}
+	log.Warn("Chain rewind requested, awaiting confirmation", "target", uint64(number), "head", head.Number)
+	return token, nil
+}
+
+// SetHead rewinds the head of the blockchain to a previous block. The rewind
+// must have been requested beforehand via RequestSetHead and the returned token
+// supplied for confirmation.
+func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64, token string) error {
+	api.setHeadLock.Lock()
+	defer api.setHeadLock.Unlock()
+
+	req := api.setHeadPending
+	if req == nil {
+		return errors.New("no pending rewind request")
+	}
+	if subtle.ConstantTimeCompare([]byte(req.token), []byte(token)) != 1 {
+		return errors.New("invalid confirmation token")
+	}
+	// The token is single use, drop it irrespective of the outcome below
+	api.setHeadPending = nil
+
+	if time.Now().After(req.expiry) {
+		return errors.New("confirmation token expired")
+	}
+	if req.number != uint64(number) {
+		return fmt.Errorf("rewind target mismatch: requested #%d, confirmed #%d", req.number, number)
+	}
+	if head := api.b.CurrentHeader(); head.Hash() != req.head {
+		return fmt.Errorf("chain head changed since request: have #%d [%x…], want [%x…]", head.Number, head.Hash().Bytes()[:4], req.head.Bytes()[:4])
+	}
+	return api.b.SetHead(uint64(number))
 }
 
 // PublicNetAPI offers network related RPC methods
 type PublicNetAPI struct {
-	net *p2p.Server
+	net            *p2p.Server
+	networkVersion uint64
 }
 
 // NewPublicNetAPI creates a new net API instance.
-func NewPublicNetAPI(net *p2p.Server) *PublicNetAPI {
-	return &PublicNetAPI{net}
+func NewPublicNetAPI(net *p2p.Server, networkVersion uint64) *PublicNetAPI {
+	return &PublicNetAPI{net, networkVersion}
 }
 
 // Listening returns an indication if the node is listening for network connections.
@@ -1918,6 +2744,11 @@ func (s *PublicNetAPI) PeerCount() hexutil.Uint {
 	return hexutil.Uint(s.net.PeerCount())
 }
 
+// Version returns the network ID the node is configured with.
+func (s *PublicNetAPI) Version() string {
+	return fmt.Sprintf("%d", s.networkVersion)
+}
+
 // checkTxFee is an internal function used to check whether the fee of
 // the given transaction is _reasonable_(under the cap).
 func checkTxFee(smokePrice *big.Int, smoke uint64, cap float64) error {
@@ -1928,7 +2759,98 @@ func checkTxFee(smokePrice *big.Int, smoke uint64, cap float64) error {
 	feeFourtwenty := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(smokePrice, new(big.Int).SetUint64(smoke))), new(big.Float).SetInt(big.NewInt(params.Fourtwentycoin)))
 	feeFloat, _ := feeFourtwenty.Float64()
 	if feeFloat > cap {
-		return fmt.Errorf("tx fee (%.2f fourtwentycoin) exceeds the configured cap (%.2f fourtwentycoin)", feeFloat, cap)
+		return &apiError{
+			fmt.Errorf("tx fee (%.2f fourtwentycoin) exceeds the configured cap (%.2f fourtwentycoin)", feeFloat, cap),
+			errCodeTxFeeCap,
+			map[string]interface{}{"smoke": hexutil.Uint64(smoke), "smokePrice": (*hexutil.Big)(smokePrice)},
+		}
+	}
+	return nil
+}
+
+// checkTxPreconditions validates the fee, intrinsic smoke and sender balance of
+// a transaction against the current head, without consulting the pool.
+func checkTxPreconditions(ctx context.Context, b Backend, tx *types.Transaction) error {
+	// If the transaction fee cap is already specified, ensure the
+	// smoke fee of the given transaction is _reasonable_.
+	if err := checkTxFee(tx.SmokePrice(), tx.Smoke(), b.RPCTxFeeCap()); err != nil {
+		return err
+	}
+	var (
+		config = b.ChainConfig()
+		number = b.CurrentBlock().Number()
+	)
+	intrinsic, err := core.IntrinsicSmoke(tx.Data(), tx.AccessList(), tx.To() == nil, config.IsHomestead(number), config.IsIstanbul(number))
+	if err != nil {
+		return err
+	}
+	if tx.Smoke() < intrinsic {
+		return &apiError{
+			fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicSmoke, tx.Smoke(), intrinsic),
+			errCodeIntrinsicSmoke,
+			map[string]interface{}{"have": hexutil.Uint64(tx.Smoke()), "want": hexutil.Uint64(intrinsic)},
+		}
+	}
+	from, err := types.Sender(types.MakeSigner(config, number), tx)
+	if err != nil {
+		return err
+	}
+	state, _, err := b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return err
+	}
+	if balance, cost := state.GetBalance(from), tx.Cost(); balance.Cmp(cost) < 0 {
+		return &apiError{
+			fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, from.Hex(), balance, cost),
+			errCodeInsufficientFunds,
+			map[string]interface{}{"have": (*hexutil.Big)(balance), "want": (*hexutil.Big)(cost)},
+		}
+	}
+	if b.RPCRecipientCheck() {
+		return checkRecipient(ctx, b, state, tx)
+	}
+	return nil
+}
+
+// recipientCheckDepth is the number of blocks the recipient check looks back to
+// find contracts which have since self-destructed. It matches the number of
+// recent states a full node keeps in memory.
+const recipientCheckDepth = 128
+
+// checkRecipient rejects value transfers which are a common source of lost
+// funds: sends to precompiled contracts and to addresses which held code in
+// the recent past, but have since self-destructed. The state is the one at
+// the current head.
+func checkRecipient(ctx context.Context, b Backend, state *state.StateDB, tx *types.Transaction) error {
+	to := tx.To()
+	if to == nil || tx.Value().Sign() == 0 {
+		return nil
+	}
+	head := b.CurrentBlock().Number()
+	for _, addr := range vm.ActivePrecompiles(b.ChainConfig().Rules(head)) {
+		if addr == *to {
+			return &apiError{
+				fmt.Errorf("transaction sends value to precompiled contract %v", to.Hex()),
+				errCodeRecipient,
+				map[string]interface{}{"recipient": to, "reason": "precompile"},
+			}
+		}
+	}
+	if state.GetCodeSize(*to) > 0 || head.Uint64() < recipientCheckDepth {
+		return nil
+	}
+	// The old state might have been pruned already, in which case there is
+	// nothing to compare against.
+	past, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(head.Uint64()-recipientCheckDepth))
+	if past == nil || err != nil {
+		return nil
+	}
+	if past.GetCodeSize(*to) > 0 {
+		return &apiError{
+			fmt.Errorf("transaction sends value to self-destructed contract %v", to.Hex()),
+			errCodeRecipient,
+			map[string]interface{}{"recipient": to, "reason": "selfdestructed"},
+		}
 	}
 	return nil
 }
@@ -0,0 +1,240 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwentyapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/consensus/misc"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// PublicFeeCompatAPI serves the fee suggestion methods wallet libraries expect
+// under the "eth" namespace, answering them from the smoke price oracle. Fees are
+// reported per smoke, the unit being the only difference to the original methods.
+type PublicFeeCompatAPI struct {
+	b Backend
+}
+
+// NewPublicFeeCompatAPI creates a new fee suggestion compatibility API.
+func NewPublicFeeCompatAPI(b Backend) *PublicFeeCompatAPI {
+	return &PublicFeeCompatAPI{b}
+}
+
+// GasPrice returns a suggestion for appropriate smoke price.
+func (s *PublicFeeCompatAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := s.b.SuggestPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+// MaxPriorityFeePerGas returns a suggestion for the tip of dynamic fee
+// transactions, the suggested smoke price above the base fee of the latest block.
+// Before the fee market it is the full suggested smoke price.
+func (s *PublicFeeCompatAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	price, err := s.b.SuggestPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(tipAbove(price, head.BaseFee)), nil
+}
+
+// FeeHistoryResult is the fee market history of a range of blocks, as returned
+// by FeeHistory.
+type FeeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the requested percentiles of the tips paid by the
+// transactions, the base fees and the smoke used ratio of up to blockCount blocks
+// ending at lastBlock. The base fees include the one of the block following the
+// range, zero before the fee market. The tips before the fee market are the smoke
+// prices of the transactions.
+func (s *PublicFeeCompatAPI) FeeHistory(ctx context.Context, blockCount hexutil.Uint, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*FeeHistoryResult, error) {
+	oldest, prices, ratios, err := s.b.SmokePriceHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	result := &FeeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: ratios,
+	}
+	if len(ratios) == 0 {
+		return result, nil
+	}
+	var (
+		config  = s.b.ChainConfig()
+		header  *types.Header
+		baseFee *big.Int
+	)
+	result.BaseFee = make([]*hexutil.Big, len(ratios)+1)
+	if prices != nil {
+		result.Reward = make([][]*hexutil.Big, len(prices))
+	}
+	for i := range ratios {
+		number := oldest.Uint64() + uint64(i)
+		if header, err = s.b.HeaderByNumber(ctx, rpc.BlockNumber(number)); header == nil {
+			if err == nil {
+				err = fmt.Errorf("block #%d not found", number)
+			}
+			return nil, err
+		}
+		baseFee = header.BaseFee
+		if baseFee == nil {
+			baseFee = new(big.Int)
+		}
+		result.BaseFee[i] = (*hexutil.Big)(baseFee)
+
+		if prices != nil {
+			result.Reward[i] = make([]*hexutil.Big, len(prices[i]))
+			for j, price := range prices[i] {
+				result.Reward[i][j] = (*hexutil.Big)(tipAbove(price, header.BaseFee))
+			}
+		}
+	}
+	// Append the base fee the block after the range will pay
+	next := new(big.Int)
+	if config.IsSmokeBaseFee(new(big.Int).Add(header.Number, common.Big1)) {
+		next = misc.CalcBaseFee(config, header)
+	}
+	result.BaseFee[len(ratios)] = (*hexutil.Big)(next)
+
+	return result, nil
+}
+
+// tipAbove returns the part of a smoke price above the given base fee, never
+// negative. A nil base fee leaves the full price as tip.
+func tipAbove(price, baseFee *big.Int) *big.Int {
+	if baseFee == nil || price.Sign() == 0 {
+		return price
+	}
+	tip := new(big.Int).Sub(price, baseFee)
+	if tip.Sign() < 0 {
+		tip.SetUint64(0)
+	}
+	return tip
+}
+
+// EthCompatAPIs returns the RPC services mapping the Ethereum naming of the eth
+// namespace onto the 420coin APIs, for tooling written against Ethereum nodes.
+func EthCompatAPIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewEthCompatAlias(),
+			Public:    true,
+		},
+	}
+}
+
+// NewEthCompatAlias returns an alias serving the 420coin APIs under the eth
+// namespace. The smoke related method names and JSON fields are translated from
+// and to their gas counterparts; values, balances in marleys included, are passed
+// through as is.
+func NewEthCompatAlias() *rpc.Alias {
+	return &rpc.Alias{
+		Targets: []string{"420", "fourtwenty"},
+		Method:  gasToSmoke,
+		Params: func(params json.RawMessage) (json.RawMessage, error) {
+			if !bytes.Contains(params, []byte("gas")) && !bytes.Contains(params, []byte("Gas")) {
+				return params, nil
+			}
+			return renameJSONFields(params, gasFieldToSmoke)
+		},
+		Result: func(result json.RawMessage) (json.RawMessage, error) {
+			if !bytes.Contains(result, []byte("smoke")) && !bytes.Contains(result, []byte("Smoke")) {
+				return result, nil
+			}
+			return renameJSONFields(result, smokeToGas)
+		},
+	}
+}
+
+// gasToSmoke translates an Ethereum method or field name into the 420coin one.
+func gasToSmoke(name string) string {
+	if strings.HasPrefix(name, "gas") {
+		name = "smoke" + name[len("gas"):]
+	}
+	return strings.Replace(name, "Gas", "Smoke", -1)
+}
+
+// smokeToGas translates a 420coin method or field name into the Ethereum one.
+func smokeToGas(name string) string {
+	if strings.HasPrefix(name, "smoke") {
+		name = "gas" + name[len("smoke"):]
+	}
+	return strings.Replace(name, "Smoke", "Gas", -1)
+}
+
+// gasFieldToSmoke translates an Ethereum request field name into the 420coin
+// one. The fee cap of dynamic fee transactions is carried by the smoke price.
+func gasFieldToSmoke(name string) string {
+	if name == "maxFeePerGas" {
+		return "smokePrice"
+	}
+	return gasToSmoke(name)
+}
+
+// renameJSONFields renames the fields of all the objects in a JSON document.
+func renameJSONFields(blob json.RawMessage, rename func(string) string) (json.RawMessage, error) {
+	var value interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(blob))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(renameFields(value, rename)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// renameFields renames the fields of the objects in a decoded JSON value.
+func renameFields(value interface{}, rename func(string) string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			renamed[rename(key)] = renameFields(field, rename)
+		}
+		return renamed
+	case []interface{}:
+		for i, item := range value {
+			value[i] = renameFields(item, rename)
+		}
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package fourtwentyapi
+
+import (
+	"errors"
+
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/rpc"
+)
+
+// JSON error codes returned by the API when structured errors are enabled.
+const (
+	errCodeTxFeeCap          = -32010
+	errCodeIntrinsicSmoke    = -32011
+	errCodeInsufficientFunds = -32012
+	errCodeNonceTooLow       = -32013
+	errCodeNonceTooHigh      = -32014
+	errCodeSmokeLimit        = -32015
+	errCodeUnderpriced       = -32016
+	errCodeAlreadyKnown      = -32017
+	errCodeTxPoolFull        = -32018
+	errCodeInvalidSender     = -32019
+	errCodeInvalidTx         = -32020
+	errCodeSmokeAllowance    = -32021
+	errCodeRecipient         = -32022
+)
+
+// coreErrorCodes maps the consensus and transaction pool errors to the JSON
+// error codes they are reported with.
+var coreErrorCodes = []struct {
+	err  error
+	code int
+}{
+	{core.ErrIntrinsicSmoke, errCodeIntrinsicSmoke},
+	{core.ErrInsufficientFunds, errCodeInsufficientFunds},
+	{core.ErrInsufficientFundsForTransfer, errCodeInsufficientFunds},
+	{core.ErrNonceTooLow, errCodeNonceTooLow},
+	{core.ErrNonceTooHigh, errCodeNonceTooHigh},
+	{core.ErrSmokeLimitReached, errCodeSmokeLimit},
+	{core.ErrSmokeLimit, errCodeSmokeLimit},
+	{core.ErrUnderpriced, errCodeUnderpriced},
+	{core.ErrReplaceUnderpriced, errCodeUnderpriced},
+	{core.ErrAlreadyKnown, errCodeAlreadyKnown},
+	{core.ErrTxPoolOverflow, errCodeTxPoolFull},
+	{core.ErrInvalidSender, errCodeInvalidSender},
+	{core.ErrOversizedData, errCodeInvalidTx},
+	{core.ErrNegativeValue, errCodeInvalidTx},
+	{core.ErrSmokeUintOverflow, errCodeInvalidTx},
+}
+
+// apiError is an API error with a JSON error code and an optional structured
+// data field, e.g. the required and available balance of an account.
+type apiError struct {
+	error
+	code int
+	data interface{}
+}
+
+// ErrorCode returns the JSON error code of the error.
+func (e *apiError) ErrorCode() int {
+	return e.code
+}
+
+// ErrorData returns the structured data attached to the error, if any.
+func (e *apiError) ErrorData() interface{} {
+	return e.data
+}
+
+// Unwrap returns the underlying error, so the original cause can be checked.
+func (e *apiError) Unwrap() error {
+	return e.error
+}
+
+// structuredError converts err into the form it should be reported over RPC.
+// If structured errors are enabled, known failures are tagged with a numeric
+// code, otherwise codes and data are dropped for compatibility with clients
+// matching on the error messages only.
+func structuredError(b Backend, err error) error {
+	if err == nil {
+		return nil
+	}
+	if !b.RPCStructuredErrors() {
+		if aerr, ok := err.(*apiError); ok {
+			return aerr.error
+		}
+		return err
+	}
+	if _, ok := err.(rpc.Error); ok {
+		return err
+	}
+	// The RPC server only reports the code and data of the outermost error,
+	// lift them out of any annotations wrapping a coded error.
+	var rerr rpc.Error
+	if errors.As(err, &rerr) {
+		var data interface{}
+		if derr, ok := rerr.(rpc.DataError); ok {
+			data = derr.ErrorData()
+		}
+		return &apiError{err, rerr.ErrorCode(), data}
+	}
+	for _, known := range coreErrorCodes {
+		if errors.Is(err, known.err) {
+			return &apiError{err, known.code, nil}
+		}
+	}
+	return err
+}
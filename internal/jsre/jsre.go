@@ -20,6 +20,7 @@ package jsre
 import (
 	crand "crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -282,6 +283,38 @@ func (re *JSRE) Evaluate(code string, w io.Writer) {
 	})
 }
 
+// EvaluateJSON executes code and writes the result to w as a single line of JSON,
+// serialized via JSON.stringify. Undefined results are written as null. Unlike
+// Evaluate, failures are returned instead of being printed.
+func (re *JSRE) EvaluateJSON(code string, w io.Writer) (err error) {
+	re.Do(func(vm *goja.Runtime) {
+		val, runErr := vm.RunString(code)
+		if runErr != nil {
+			if gojaErr, ok := runErr.(*goja.Exception); ok {
+				runErr = errors.New(gojaErr.String())
+			}
+			err = runErr
+			return
+		}
+		stringify, ok := goja.AssertFunction(vm.Get("JSON").ToObject(vm).Get("stringify"))
+		if !ok {
+			err = errors.New("JSON.stringify is not a function")
+			return
+		}
+		out, jsonErr := stringify(goja.Undefined(), val)
+		if jsonErr != nil {
+			err = jsonErr
+			return
+		}
+		if goja.IsUndefined(out) {
+			fmt.Fprintln(w, "null")
+			return
+		}
+		fmt.Fprintln(w, out.String())
+	})
+	return err
+}
+
 // Compile compiles and then runs a piece of JS code.
 func (re *JSRE) Compile(filename string, src string) (err error) {
 	re.Do(func(vm *goja.Runtime) { _, err = compileAndRun(vm, filename, src) })
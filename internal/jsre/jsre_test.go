@@ -17,6 +17,7 @@
 package jsre
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path"
@@ -135,3 +136,30 @@ func TestLoadScript(t *testing.T) {
 	}
 	jsre.Stop(false)
 }
+
+func TestEvaluateJSON(t *testing.T) {
+	jsre := New("", os.Stdout)
+	defer jsre.Stop(false)
+
+	tests := []struct {
+		code string
+		want string
+	}{
+		{`({a: 1, b: [true, "x"]})`, "{\"a\":1,\"b\":[true,\"x\"]}\n"},
+		{`"str"`, "\"str\"\n"},
+		{`undefined`, "null\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := jsre.EvaluateJSON(tt.code, &out); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.code, err)
+			continue
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: output mismatch: have %q, want %q", tt.code, out.String(), tt.want)
+		}
+	}
+	if err := jsre.EvaluateJSON(`throw new Error("boom")`, new(bytes.Buffer)); err == nil {
+		t.Errorf("expected error for throwing script")
+	}
+}
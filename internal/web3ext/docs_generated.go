@@ -0,0 +1,226 @@
+// Code generated by gendocs. DO NOT EDIT.
+
+package web3ext
+
+// Docs maps the console namespaces to the documentation of their methods, as
+// extracted from the Go API definitions.
+var Docs = map[string]map[string]string{
+	"admin": {
+		"addPeer":                    "AddPeer requests connecting to a remote node, and also maintaining the new\nconnection at all times, even reconnecting if it is lost.",
+		"addTrustedPeer":             "AddTrustedPeer allows a remote node to always connect, even if slots are full",
+		"approveReorg":               "ApproveReorg switches the canonical chain to the given block, which must be\nheavier than the current head, bypassing the maximum reorg depth.",
+		"cancelScheduledTransaction": "CancelScheduledTransaction drops a transaction held back until its target,\nreturning whether it was scheduled.",
+		"chainTipAlerts":             "ChainTipAlerts creates a subscription that fires whenever a competing chain\ntip appears within the configured alert depth of the canonical head with a\nhigher total difficulty than the canonical block at the same height.",
+		"chainTips":                  "ChainTips returns the canonical head and all known competing chain tips,\nalong with their total difficulty and common ancestor with the canonical\nchain, ordered by descending total difficulty.",
+		"datadir":                    "Datadir retrieves the current data directory the node is using.",
+		"exportChain":                "ExportChain exports the current blockchain into a local file,\nor a range of blocks if first and last are non-nil",
+		"importChain":                "ImportChain imports a blockchain from a local file.",
+		"nodeInfo":                   "NodeInfo retrieves all the information we know about the host node at the\nprotocol granularity.",
+		"peerEvents":                 "PeerEvents creates an RPC subscription which receives peer events from the\nnode's p2p.Server",
+		"peers":                      "Peers retrieves all the information we know about each individual peer at the\nprotocol granularity.",
+		"pendingReorg":               "PendingReorg returns the last chain reorganisation refused for exceeding the\nconfigured maximum reorg depth, or nil if there is none.",
+		"removePeer":                 "RemovePeer disconnects from a remote node if the connection exists",
+		"removeTrustedPeer":          "RemoveTrustedPeer removes a remote node from the trusted peer set, but it\ndoes not disconnect it automatically.",
+		"scheduleTransaction":        "ScheduleTransaction holds a signed transaction back until the chain reaches the\ngiven block number and/or timestamp, then injects it into the local pool. The\ntransaction is injected right away if its target was already reached. Held back\ntransactions are lost on restart.",
+		"scheduledTransactions":      "ScheduledTransactions returns the transactions held back until their target,\nordered by target block number and timestamp.",
+		"startRPC":                   "StartRPC starts the HTTP RPC API server.",
+		"startWS":                    "StartWS starts the websocket RPC API server.",
+		"stopRPC":                    "StopRPC shuts down the HTTP server.",
+		"stopWS":                     "StopWS terminates all WebSocket servers.",
+	},
+	"clique": {
+		"discard":           "Discard drops a currently running proposal, stopping the signer from casting\nfurther votes (either for or against).",
+		"getSigners":        "GetSigners retrieves the list of authorized signers at the specified block.",
+		"getSignersAtHash":  "GetSignersAtHash retrieves the list of authorized signers at the specified block.",
+		"getSnapshot":       "GetSnapshot retrieves the state snapshot at a given block.",
+		"getSnapshotAtHash": "GetSnapshotAtHash retrieves the state snapshot at a given block.",
+		"proposals":         "Proposals returns the current proposals the node tries to uphold and vote on.",
+		"propose":           "Propose injects a new authorization proposal that the signer will attempt to\npush through.",
+		"status":            "Status returns the status of the last N blocks,\n- the number of active signers,\n- the number of signers,\n- the percentage of in-turn blocks",
+	},
+	"debug": {
+		"accountRange":                "AccountRange enumerates all accounts in the given block and start point in paging request",
+		"backfillReceiptStatus":       "BackfillReceiptStatus re-executes the canonical blocks between start and end\n(inclusive) that predate Byzantium and stores the outcome of their transactions\nalongside the receipts, which only carry intermediate state roots. Blocks that\nwere already backfilled are only re-executed to advance the state. It returns\nthe number of blocks whose receipt statuses were stored.",
+		"blockArrival":                "BlockArrival returns the times the given block was first announced and first\nreceived in full from the network, along with the peers it came from. Nil is\nreturned if the block was never propagated to the local node, or was already\nevicted from the recent arrivals.",
+		"cacheBudget":                 "CacheBudget returns the present apportionment of the memory budget of the node.",
+		"chaindbCompact":              "ChaindbCompact flattens the entire key-value database into a single level,\nremoving all unused slots and merging all keys.",
+		"chaindbProperty":             "ChaindbProperty returns leveldb properties of the key-value database.",
+		"dumpBlock":                   "DumpBlock retrieves the entire state of the database at a given block.",
+		"executionWitness":            "ExecutionWitness returns the RLP encoded witness of the given block: the\nancestor headers, trie nodes and contract codes needed to re-execute it\nwithout the state database.",
+		"getBadBlocks":                "GetBadBlocks returns a list of the last 'bad blocks' that the client has seen on the network\nand returns them as a JSON list of block-hashes",
+		"getBlockRlp":                 "GetBlockRlp retrieves the RLP encoded for of a single block.",
+		"getModifiedAccountsByHash":   "GetModifiedAccountsByHash returns all accounts that have changed between the\ntwo blocks specified. A change is defined as a difference in nonce, balance,\ncode hash, or storage hash.\n\nWith one parameter, returns the list of accounts modified in the specified block.",
+		"getModifiedAccountsByNumber": "GetModifiedAccountsByNumber returns all accounts that have changed between the\ntwo blocks specified. A change is defined as a difference in nonce, balance,\ncode hash, or storage hash.\n\nWith one parameter, returns the list of accounts modified in the specified block.",
+		"preimage":                    "Preimage is a debug API function that returns the preimage for a sha3 hash, if known.",
+		"printBlock":                  "PrintBlock retrieves a block and returns its pretty printed form.",
+		"rebuildSnapshot":             "RebuildSnapshot wipes the state snapshot and regenerates it in the background\nfrom the state of the current head block, returning the state root it is being\ngenerated for. The progress can be followed through SnapshotStatus.",
+		"requestSetHead":              "RequestSetHead schedules a rewind of the blockchain to a previous block and\nreturns a confirmation token which must be passed to SetHead within a minute.\nThe request is invalidated if the chain head changes in the meantime.",
+		"seedHash":                    "SeedHash retrieves the seed hash of a block.",
+		"setCacheBudget":              "SetCacheBudget resizes the memory budget of the node to the given number of\nmegabytes, rescaling the share of every subsystem proportionally. The dirty\ntrie cache and the transaction pool are resized in place, whereas the database,\nclean trie and snapshot caches are preallocated and only pick up their new\nsizes after a restart.",
+		"setHead":                     "SetHead rewinds the head of the blockchain to a previous block. The rewind\nmust have been requested beforehand via RequestSetHead and the returned token\nsupplied for confirmation.",
+		"snapshotStatus":              "SnapshotStatus reports the layers of the state snapshot and the progress of its\nbackground generation.",
+		"standardTraceBadBlockToFile": "StandardTraceBadBlockToFile dumps the structured logs created during the\nexecution of EVM against a block pulled from the pool of bad ones to the\nlocal file system and returns a list of files to the caller.",
+		"standardTraceBlockToFile":    "StandardTraceBlockToFile dumps the structured logs created during the\nexecution of EVM to the local file system and returns a list of files\nto the caller.",
+		"storageChanges":              "StorageChanges creates a subscription that fires for every new canonical block\nwhich changed the value of any of the given storage slots. The slots are read\nfrom the state of each imported block and compared to the values seen before,\nso reorgs are reported as changes against the previous chain.",
+		"storageRangeAt":              "StorageRangeAt returns the storage at the given block height and transaction index.",
+		"testSignCliqueBlock":         "TestSignCliqueBlock fetches the given block number, and attempts to sign it as a clique header with the\ngiven address, returning the address of the recovered signature\n\nThis is a temporary method to debug the externalsigner integration,\nTODO: Remove this method when the integration is mature",
+		"traceBadBlock":               "TraceBadBlock returns the structured logs created during the execution of\nEVM against a block pulled from the pool of bad ones and returns them as a JSON\nobject.",
+		"traceBlock":                  "TraceBlock returns the structured logs created during the execution of EVM\nand returns them as a JSON object.",
+		"traceBlockByHash":            "TraceBlockByHash returns the structured logs created during the execution of\nEVM and returns them as a JSON object.",
+		"traceBlockByNumber":          "TraceBlockByNumber returns the structured logs created during the execution of\nEVM and returns them as a JSON object.",
+		"traceBlockFromFile":          "TraceBlockFromFile returns the structured logs created during the execution of\nEVM and returns them as a JSON object.",
+		"traceBlockStateDiff":         "TraceBlockStateDiff returns the aggregate state diff of the block with the\ngiven hash.",
+		"traceBlockStateDiffByNumber": "TraceBlockStateDiffByNumber returns the aggregate state diff of the block with\nthe given number: the balance, nonce, code and storage of every account modified\nby the block's transactions and rewards, before and after the block.",
+		"traceCall":                   "TraceCall lets you trace a given 420_call. It collects the structured logs created during the execution of EVM\nif the given transaction was added on top of the provided block and returns them as a JSON object.\nYou can provide -2 as a block number to trace on top of the pending block.",
+		"traceChain":                  "TraceChain returns the structured logs created during the execution of EVM\nbetween two blocks (excluding start) and returns them as a JSON object.",
+		"traceTransaction":            "TraceTransaction returns the structured logs created during the execution of EVM\nand returns them as a JSON object.",
+		"traceTransactionStream":      "TraceTransactionStream traces a transaction with the struct logger similarly\nto TraceTransaction, but delivers the struct logs in chunks over a subscription\ninstead of accumulating them, followed by a final summary of the execution.\nThis allows tracing block-filling transactions without holding the entire\ntrace in memory.",
+		"transactionArrival":          "TransactionArrival returns the times the given transaction was first announced\nand first received in full from the network, along with the peers it came\nfrom. Nil is returned if the transaction was never propagated to the local\nnode, or was already evicted from the recent arrivals.",
+		"verifyExecutionWitness":      "VerifyExecutionWitness re-executes the given block statelessly from the RLP\nencoded witness, verifying the resulting state root and receipts against the\nblock header.",
+	},
+	"ethash": {
+		"getHashrate":    "GetHashrate returns the current hashrate for local CPU miner and remote miner.",
+		"getWork":        "GetWork returns a work package for external miner.\n\nThe work package consists of 3 strings:\n  result[0] - 32 bytes hex encoded current block header pow-hash\n  result[1] - 32 bytes hex encoded seed hash used for DAG\n  result[2] - 32 bytes hex encoded boundary condition (\"target\"), 2^256/difficulty\n  result[3] - hex encoded block number",
+		"submitHashRate": "SubmitHashRate is the former name of SubmitHashrate, kept for the existing\nremote miners.",
+		"submitHashrate": "SubmitHashrate can be used for remote miners to submit their hash rate.\nThis enables the node to report the combined hash rate of all miners\nwhich submit work through this node.\n\nIt accepts the miner hash rate and an identifier which must be unique\nbetween nodes.",
+		"submitWork":     "SubmitWork can be used by external miner to submit their POW solution.\nIt returns an indication if the work was accepted.\nNote either an invalid solution, a stale work a non-existent work will return false.",
+	},
+	"evm": {
+		"increaseTime": "IncreaseTime moves the clock used to timestamp new blocks forward by the given\nnumber of seconds, returning the total shift from the wall clock.",
+		"mine":         "Mine seals a new block on top of the chain head, even if it is empty. If a\ntimestamp is given, the clock is moved to it first.",
+		"revert":       "Revert rewinds the chain and the clock to the given snapshot, dropping it along\nwith all the later ones. It returns false if the snapshot is unknown.",
+		"snapshot":     "Snapshot records the current head of the chain and the clock shift, returning\nthe identifier to pass to Revert.",
+	},
+	"fourtwenty": {
+		"accounts":                               "Accounts returns the collection of accounts this node manages",
+		"blockNumber":                            "BlockNumber returns the block number of the current chain head.",
+		"call":                                   "Call executes the given transaction on the state for the given block number.\n\nAdditionally, the caller can specify a batch of contract for fields overriding.\n\nNote, this function doesn't make and changes in the state/blockchain and is\nuseful to execute and retrieve values.",
+		"chainId":                                "ChainId returns the chainID value for transaction replay protection.",
+		"coinbase":                               "Coinbase is the address that mining rewards will be send to (alias for Fourtwentycoinbase)",
+		"ecRecover":                              "EcRecover returns the address of the account which signed the given message\nvia fourtwenty_sign or personal_sign, applying the signed message prefix (see\nHashMessage). The V value of the signature may be either 27/28 or 0/1.",
+		"estimateSmoke":                          "EstimateSmoke returns an estimate of the amount of smoke needed to execute the\ngiven transaction against the current pending block.",
+		"fillTransaction":                        "FillTransaction fills the defaults (nonce, smoke, smokePrice) on a given unsigned transaction,\nand returns it to the caller for further processing (signing + broadcast)",
+		"fourtwentycoinbase":                     "Fourtwentycoinbase is the address that mining rewards will be send to",
+		"getBalance":                             "GetBalance returns the amount in marleys for the given address in the state of the\ngiven block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta\nblock numbers are also allowed.",
+		"getBalances":                            "GetBalances returns the balances of the given addresses in the state of the\ngiven block. All balances are read from the same state, so they are\nconsistent with each other.",
+		"getBlockByHash":                         "GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full\ndetail, otherwise only the transaction hash is returned.",
+		"getBlockByNumber":                       "GetBlockByNumber returns the requested canonical block.\n* When blockNr is -1 the chain head is returned.\n* When blockNr is -2 the pending chain head is returned.\n* When fullTx is true all transactions in the block are returned, otherwise\n  only the transaction hash is returned.",
+		"getBlockTransactionCountByHash":         "GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.",
+		"getBlockTransactionCountByNumber":       "GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.",
+		"getCode":                                "GetCode returns the code stored at the given address in the state for the given block number.",
+		"getFilterChanges":                       "GetFilterChanges returns the logs for the filter with the given id since\nlast time it was called. This can be used for polling.\n\nFor pending transaction and block filters the result is []common.Hash.\n(pending)Log filters return []Log.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_getfilterchanges",
+		"getFilterLogs":                          "GetFilterLogs returns the logs for the filter with the given id.\nIf the filter could not be found an empty array of logs is returned.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_getfilterlogs",
+		"getHashrate":                            "GetHashrate returns the current hashrate for local CPU miner and remote miner.",
+		"getHeaderByHash":                        "GetHeaderByHash returns the requested header by hash.",
+		"getHeaderByNumber":                      "GetHeaderByNumber returns the requested canonical block header.\n* When blockNr is -1 the chain head is returned.\n* When blockNr is -2 the pending chain head is returned.",
+		"getLogs":                                "GetLogs returns logs matching the given argument that are stored within the state.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_getlogs",
+		"getProof":                               "GetProof returns the Merkle-proof for a given account and optionally some storage keys.",
+		"getRawTransactionByBlockHashAndIndex":   "GetRawTransactionByBlockHashAndIndex returns the bytes of the transaction for the given block hash and index.",
+		"getRawTransactionByBlockNumberAndIndex": "GetRawTransactionByBlockNumberAndIndex returns the bytes of the transaction for the given block number and index.",
+		"getRawTransactionByHash":                "GetRawTransactionByHash returns the bytes of the transaction for the given hash.",
+		"getStorageAt":                           "GetStorageAt returns the storage from the state at the given address, key and\nblock number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block\nnumbers are also allowed.",
+		"getTransactionByBlockHashAndIndex":      "GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.",
+		"getTransactionByBlockNumberAndIndex":    "GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.",
+		"getTransactionByHash":                   "GetTransactionByHash returns the transaction for the given hash",
+		"getTransactionConfirmations":            "GetTransactionConfirmations returns the number of blocks confirming the\ntransaction with the given hash, including its own block, and whether that\nblock is on the canonical chain. If a reorg moved the block off the chain,\nit is reported as non-canonical with zero confirmations instead of being\ncounted against the new head. Transactions still in the pool are reported\nas pending, unknown ones as null.",
+		"getTransactionCount":                    "GetTransactionCount returns the number of transactions the given address has sent for the given block number",
+		"getTransactionCounts":                   "GetTransactionCounts returns the nonces of the given addresses in the state\nof the given block. Like GetBalances, all nonces are read from the same state.\nFor the pending block, this is the state of the pending block, which doesn't\ninclude transactions that are only known to the pool.",
+		"getTransactionReceipt":                  "GetTransactionReceipt returns the transaction receipt for the given transaction hash.",
+		"getUncleByBlockHashAndIndex":            "GetUncleByBlockHashAndIndex returns the uncle block for the given block hash and index. When fullTx is true\nall transactions in the block are returned in full detail, otherwise only the transaction hash is returned.",
+		"getUncleByBlockNumberAndIndex":          "GetUncleByBlockNumberAndIndex returns the uncle block for the given block hash and index. When fullTx is true\nall transactions in the block are returned in full detail, otherwise only the transaction hash is returned.",
+		"getUncleCountByBlockHash":               "GetUncleCountByBlockHash returns number of uncles in the block for the given block hash",
+		"getUncleCountByBlockNumber":             "GetUncleCountByBlockNumber returns number of uncles in the block for the given block number",
+		"getWork":                                "GetWork returns a work package for external miner.\n\nThe work package consists of 3 strings:\n  result[0] - 32 bytes hex encoded current block header pow-hash\n  result[1] - 32 bytes hex encoded seed hash used for DAG\n  result[2] - 32 bytes hex encoded boundary condition (\"target\"), 2^256/difficulty\n  result[3] - hex encoded block number",
+		"hashMessage":                            "HashMessage returns the hash signed by fourtwenty_sign and personal_sign for the\ngiven message:\nkeccak256(\"\\x19420coin Signed Message:\\n\"${message length}${message})",
+		"hashrate":                               "Hashrate returns the POW hashrate",
+		"logs":                                   "Logs creates a subscription that fires for all new log that match the given filter criteria.",
+		"mining":                                 "Mining returns an indication if this node is currently mining.",
+		"networkHashrate":                        "NetworkHashrate estimates the hashes per second of the whole network from the\ndifficulties and timestamps of the given number of most recent blocks, or of\nthe last defaultHashrateBlocks if unspecified.",
+		"newBlockFilter":                         "NewBlockFilter creates a filter that fetches blocks that are imported into the chain.\nIt is part of the filter package since polling goes with fourtwenty_getFilterChanges.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_newblockfilter",
+		"newFilter":                              "NewFilter creates a new filter and returns the filter id. It can be\nused to retrieve logs when the state changes. This method cannot be\nused to fetch logs that are already stored in the state.\n\nDefault criteria for the from and to block are \"latest\".\nUsing \"latest\" as block number will return logs for mined blocks.\nUsing \"pending\" as block number returns logs for not yet mined (pending) blocks.\nIn case logs are removed (chain reorg) previously returned logs are returned\nagain but with the removed property set to true.\n\nIn case \"fromBlock\" > \"toBlock\" an error is returned.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_newfilter",
+		"newHeads":                               "NewHeads send a notification each time a new (header) block is appended to the chain.",
+		"newPendingTransactionFilter":            "NewPendingTransactionFilter creates a filter that fetches pending transaction hashes\nas transactions enter the pending state.\n\nIt is part of the filter package because this filter can be used through the\n`fourtwenty_getFilterChanges` polling method that is also used for log filters.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_newpendingtransactionfilter",
+		"newPendingTransactions":                 "NewPendingTransactions creates a subscription that is triggered each time a transaction\nenters the transaction pool and was signed from one of the transactions this nodes manages.",
+		"pendingTransactions":                    "PendingTransactions returns the transactions that are in the transaction pool\nand have a from address that is one of the accounts this node manages.",
+		"resend":                                 "Resend accepts an existing transaction and a new smoke price and limit. It will remove\nthe given transaction from the pool and reinsert it with the new smoke price and limit.",
+		"rewardSchedule":                         "RewardSchedule returns the per-era block reward emission schedule applied by\nthe consensus engine, along with the Veterans Fund and followers addresses the\nnext block's rewards are paid to and the share of its transaction fees routed\nto the Veterans Fund.",
+		"sendRawTransaction":                     "SendRawTransaction will add the signed transaction to the transaction pool.\nThe sender is responsible for signing the transaction and using the correct nonce.",
+		"sendTransaction":                        "SendTransaction creates a transaction for the given argument, sign it and submit it to the\ntransaction pool.",
+		"sign":                                   "Sign calculates an ECDSA signature for:\nkeccack256(\"\\x19Fourtwentycoin Signed Message:\\n\" + len(message) + message).\n\nNote, the produced signature conforms to the secp256k1 curve R, S and V values,\nwhere the V value will be 27 or 28 for legacy reasons.\n\nThe account associated with addr must be unlocked.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#fourtwenty_sign",
+		"signTransaction":                        "SignTransaction will sign the given transaction with the from account.\nThe node needs to have the private key of the account corresponding with\nthe given from address and it needs to be unlocked.",
+		"smokePrice":                             "SmokePrice returns a suggestion for appropriate smoke price.",
+		"smokePriceHistory":                      "SmokePriceHistory returns the requested percentiles of the transaction smoke\nprices and the smoke used ratio of up to blockCount blocks ending at lastBlock,\nallowing wallets to display fee trends instead of a single suggested price.",
+		"smokeUsedRatio":                         "SmokeUsedRatio returns the network utilization between startBlock and endBlock\n(inclusive), aggregated into buckets of resolution blocks each. Every bucket\ncontains the ratio of the total smoke used to the total smoke limit and the\naverage number of transactions per block.",
+		"submitHashRate":                         "SubmitHashRate is the former name of SubmitHashrate, kept for the existing\nremote miners.",
+		"submitHashrate":                         "SubmitHashrate can be used for remote miners to submit their hash rate.\nThis enables the node to report the combined hash rate of all miners\nwhich submit work through this node.\n\nIt accepts the miner hash rate and an identifier which must be unique\nbetween nodes.",
+		"submitWork":                             "SubmitWork can be used by external miner to submit their POW solution.\nIt returns an indication if the work was accepted.\nNote either an invalid solution, a stale work a non-existent work will return false.",
+		"subscribeSyncStatus":                    "SubscribeSyncStatus creates a subscription that will broadcast new synchronisation updates.\nThe given channel must receive interface values, the result can either",
+		"syncing":                                "Syncing returns false in case the node is currently not syncing with the network. It can be up to date or has not\nyet received the latest block headers from its pears. In case it is synchronizing:\n- startingBlock: block number this node started to synchronise from\n- currentBlock:  block number this node is currently importing\n- highestBlock:  block number of the highest block header this node has received from peers\n- pulledStates:  number of state entries processed until now\n- knownStates:   number of known state entries that still need to be pulled",
+		"uncleStats":                             "UncleStats returns the uncles included between startBlock and endBlock\n(inclusive), the rewards the consensus engine paid for them and to the miners\nincluding them, as well as per-miner aggregates.",
+		"uninstallFilter":                        "UninstallFilter removes the filter with the given filter id.\n\nhttps://github.com/420integrated/go-420coin/wiki/wiki/JSON-RPC#420_uninstallfilter",
+	},
+	"les": {
+		"addBalance":                   "AddBalance adds the given amount to the balance of a client if possible and returns\nthe balance before and after the operation",
+		"benchmark":                    "Benchmark runs a request performance benchmark with a given set of measurement setups\nin multiple passes specified by passCount. The measurement time for each setup in each\npass is specified in milliseconds by length.\n\nNote: measurement time is adjusted for each pass depending on the previous ones.\nTherefore a controlled total measurement time is achievable in multiple passes.",
+		"checkpointSigHash":            "CheckpointSigHash returns the hash the trusted signers need to sign to approve\nthe local checkpoint of the given section for registration.",
+		"clientInfo":                   "ClientInfo returns information about clients listed in the ids list or matching the given tags",
+		"compareCheckpoint":            "CompareCheckpoint compares the latest local checkpoint with the one registered\nin the checkpoint oracle contract.",
+		"getCheckpoint":                "GetLocalCheckpoint returns the specific local checkpoint package.\n\nThe checkpoint package consists of 3 strings:\n  result[0], 32 bytes hex encoded latest section head hash\n  result[1], 32 bytes hex encoded latest section canonical hash trie root hash\n  result[2], 32 bytes hex encoded latest section bloom trie root hash",
+		"getCheckpointContractAddress": "GetCheckpointContractAddress returns the contract contract address in hex format.",
+		"latestCheckpoint":             "LatestCheckpoint returns the latest local checkpoint package.\n\nThe checkpoint package consists of 4 strings:\n  result[0], hex encoded latest section index\n  result[1], 32 bytes hex encoded latest section head hash\n  result[2], 32 bytes hex encoded latest section canonical hash trie root hash\n  result[3], 32 bytes hex encoded latest section bloom trie root hash",
+		"priorityClientInfo":           "PriorityClientInfo returns information about clients with a positive balance\nin the given ID range (stop excluded). If stop is null then the iterator stops\nonly at the end of the ID space. MaxCount limits the number of results returned.\nIf maxCount limit is applied but there are more potential results then the ID\nof the next potential result is included in the map with an empty structure\nassigned to it.",
+		"registerCheckpoint":           "RegisterCheckpoint registers the local checkpoint of the given section in the\ncheckpoint oracle contract with the approvals of the trusted signers, sending\nthe transaction from the given admin account of the local node.",
+		"registeredCheckpoint":         "RegisteredCheckpoint returns the latest checkpoint registered in the checkpoint\noracle contract.",
+		"serverInfo":                   "ServerInfo returns global server parameters",
+		"setClientCapacity":            "SetClientCapacity assigns the given capacity to a connected client and returns\nthe capacity the client has afterwards. Capacity above the free tier minimum is\nonly granted if the token balance of the client gives it enough priority over\nthe other clients, otherwise the error reports the missing balance.",
+		"setClientParams":              "SetClientParams sets client parameters for all clients listed in the ids list\nor all connected clients if the list is empty",
+		"setConnectedBias":             "SetConnectedBias set the connection bias, which is applied to already connected clients\nSo that already connected client won't be kicked out very soon and we can ensure all\nconnected clients can have enough time to request or sync some data.\nWhen the input parameter `bias` < 0 (illegal), return error.",
+		"setDefaultParams":             "SetDefaultParams sets the default parameters applicable to clients connected in the future",
+		"signCheckpoint":               "SignCheckpoint signs the local checkpoint of the given section with a trusted\nsigner account of the local node, returning the signature to register it with.",
+	},
+	"lespay": {
+		"distribution": "Distribution returns a distribution as a series of (X, Y) chart coordinates,\nwhere the X axis is the response time in seconds while the Y axis is the amount of\nservice value received with a response time close to the X coordinate.\nThe distribution is optionally normalized to a sum of 1.\nIf nodeStr == \"\" then the global distribution is returned, otherwise the individual\ndistribution of the specified server node.",
+		"requestStats": "RequestStats returns the current contents of the reference request basket, with\nrequest values meaning average per request rather than total.",
+		"timeout":      "Timeout suggests a timeout value based on either the global distribution or the\ndistribution of the specified node. The parameter is the desired rate of timeouts\nassuming a similar distribution in the future.\nNote that the actual timeout should have a sensible minimum bound so that operating\nunder ideal working conditions for a long time (for example, using a local server\nwith very low response times) will not make it very hard for the system to accommodate\nlonger response times in the future.",
+		"value":        "Value calculates the total service value provided either globally or by the specified\nserver node, using a weight function based on the given timeout.",
+	},
+	"miner": {
+		"getHashrate":           "GetHashrate returns the current hashrate of the miner.",
+		"setCoinbaseList":       "SetCoinbaseList sets the coinbases the miner rotates mined blocks among, each\none receiving interval consecutive blocks. If interval is nil, the coinbases\nare rotated round-robin on every block. An empty list disables the rotation,\nmining to the fourtwentycoinbase again.",
+		"setExtra":              "SetExtra sets the extra data string that is included when this miner mines a block.",
+		"setFourtwentycoinbase": "SetFourtwentycoinbase sets the fourtwentycoinbase of the miner",
+		"setRecommitInterval":   "SetRecommitInterval updates the interval for miner sealing work recommitting.",
+		"setSmokePrice":         "SetSmokePrice sets the minimum accepted smoke price for the miner.",
+		"start":                 "Start starts the miner with the given number of threads. If threads is nil,\nthe number of workers started is equal to the number of logical CPUs that are\nusable by this process. If mining is already running, this method adjust the\nnumber of threads allowed to use and updates the minimum price required by the\ntransaction pool.",
+		"stop":                  "Stop terminates the miner, both at the consensus engine level as well as at\nthe block creation level.",
+	},
+	"personal": {
+		"deriveAccount":          "DeriveAccount requests a HD wallet to derive a new account, optionally pinning\nit for later reuse.",
+		"ecRecover":              "EcRecover returns the address for the account that was used to create the signature.\nNote, this function is compatible with fourtwenty_sign and personal_sign. As such it recovers\nthe address of:\nhash = keccak256(\"\\x19Fourtwentycoin Signed Message:\\n\"${message length}${message})\naddr = ecrecover(hash, signature)\n\nNote, the signature must conform to the secp256k1 curve R, S and V values, where\nthe V value must be 27 or 28 for legacy reasons.\n\nhttps://github.com/420integrated/go-420coin/wiki/Management-APIs#personal_ecRecover",
+		"importMnemonic":         "ImportMnemonic derives count consecutive accounts from a BIP-39 mnemonic,\nstarting at the given BIP-32 derivation path (m/44'/60'/0'/0/0 by default) and\nincreasing its last component, and stores them into the key directory, encrypting\nthem with the password. Accounts already in the keystore are skipped.",
+		"importRawKey":           "ImportRawKey stores the given hex encoded ECDSA key into the key directory,\nencrypting it with the passphrase.",
+		"initializeWallet":       "InitializeWallet initializes a new wallet at the provided URL, by generating and returning a new private key.",
+		"listAccounts":           "listAccounts will return a list of addresses for accounts this node manages.",
+		"listWallets":            "ListWallets will return a list of wallets this node manages.",
+		"lockAccount":            "LockAccount will lock the account associated with the given address when it's unlocked.",
+		"newAccount":             "NewAccount will create a new account and returns the address for the new account.",
+		"newMnemonic":            "NewMnemonic generates a new BIP-39 mnemonic from the given number of entropy\nbits (128 to 256, in steps of 32, defaulting to 256). The mnemonic isn't stored,\nit must be written down and imported via ImportMnemonic.",
+		"openWallet":             "OpenWallet initiates a hardware wallet opening procedure, establishing a USB\nconnection and attempting to authenticate via the provided passphrase. Note,\nthe method may return an extra challenge requiring a second open (e.g. the\nTrezor PIN matrix challenge).",
+		"sendTransaction":        "SendTransaction will create a transaction from the given arguments and\ntries to sign it with the key associated with args.To. If the given passwd isn't\nable to decrypt the key it fails.",
+		"sign":                   "Sign calculates an ECDSA signature for:\nkeccack256(\"\\x19Fourtwentycoin Signed Message:\\n\" + len(message) + message))\n\nNote, the produced signature conforms to the secp256k1 curve R, S and V values,\nwhere the V value will be 27 or 28 for legacy reasons.\n\nThe key used to calculate the signature is decrypted with the given password.\n\nhttps://github.com/420integrated/go-420coin/wiki/Management-APIs#personal_sign",
+		"signAndSendTransaction": "SignAndSendTransaction was renamed to SendTransaction. This method is deprecated\nand will be removed in the future. It primary goal is to give clients time to update.",
+		"signTransaction":        "SignTransaction will create a transaction from the given arguments and\ntries to sign it with the key associated with args.To. If the given passwd isn't\nable to decrypt the key it fails. The transaction is returned in RLP-form, not broadcast\nto other nodes",
+		"unlockAccount":          "UnlockAccount will unlock the account associated with the given address with\nthe given password for duration seconds. If duration is nil it will use a\ndefault of 300 seconds (5 minutes). It returns an indication if the account was unlocked.",
+		"unpair":                 "Unpair deletes a pairing between wallet and g420.",
+	},
+	"txpool": {
+		"config":          "Config returns the current runtime adjustable settings of the transaction pool.",
+		"content":         "Content returns the transactions contained within the transaction pool.",
+		"contentFiltered": "ContentFiltered returns the transactions contained within the transaction pool\nwhich match the given sender, destination and smoke price range. Accounts\nwithout any matching transaction are omitted.",
+		"contentFrom":     "ContentFrom returns the transactions contained within the transaction pool\nsent by the given address.",
+		"inspect":         "Inspect retrieves the content of the transaction pool and flattens it into an\neasily inspectable list.",
+		"replacements":    "Replacements creates a subscription that is triggered each time a pending\ntransaction is replaced by another one with the same sender and nonce, or is\ndropped from the pool (e.g. underpriced or expired).",
+		"setConfig":       "SetConfig updates the replacement price bump, the slot and queue limits and the\nqueue lifetime of the transaction pool without a restart. Transactions above\nlowered limits are evicted. The applied settings are returned, with invalid\nvalues replaced by the defaults.",
+		"status":          "Status returns the number of pending and queued transaction in the pool.",
+	},
+}
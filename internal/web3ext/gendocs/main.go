@@ -0,0 +1,202 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// gendocs extracts the doc comments of the RPC API methods into the console's
+// inline documentation, served by the help() function of each namespace.
+//
+// Usage:
+//
+//	//go:generate go run ./gendocs -root ../.. -out docs_generated.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// source is a Go type whose exported methods are served in a console namespace.
+type source struct {
+	namespace string // JavaScript object the methods are exposed on
+	dir       string // Package directory, relative to the repository root
+	typ       string // Name of the API type
+}
+
+// sources lists the API types documented in the console.
+var sources = []source{
+	{"admin", "420", "PrivateAdminAPI"},
+	{"admin", "node", "privateAdminAPI"},
+	{"admin", "node", "publicAdminAPI"},
+	{"clique", "consensus/clique", "API"},
+	{"debug", "420", "PrivateDebugAPI"},
+	{"debug", "420", "PublicDebugAPI"},
+	{"debug", "internal/420api", "PrivateDebugAPI"},
+	{"debug", "internal/420api", "PublicDebugAPI"},
+	{"ethash", "consensus/ethash", "API"},
+	{"evm", "420", "PrivateDevAPI"},
+	{"fourtwenty", "420", "PublicFourtwentycoinAPI"},
+	{"fourtwenty", "420", "PublicMinerAPI"},
+	{"fourtwenty", "420/downloader", "PublicDownloaderAPI"},
+	{"fourtwenty", "420/filters", "PublicFilterAPI"},
+	{"fourtwenty", "consensus/ethash", "API"},
+	{"fourtwenty", "internal/420api", "PublicAccountAPI"},
+	{"fourtwenty", "internal/420api", "PublicBlockChainAPI"},
+	{"fourtwenty", "internal/420api", "PublicFourtwentycoinAPI"},
+	{"fourtwenty", "internal/420api", "PublicTransactionPoolAPI"},
+	{"les", "les", "PrivateLightAPI"},
+	{"les", "les", "PrivateLightServerAPI"},
+	{"lespay", "les/lespay/client", "PrivateClientAPI"},
+	{"miner", "420", "PrivateMinerAPI"},
+	{"personal", "internal/420api", "PrivateAccountAPI"},
+	{"txpool", "420", "PrivateTxPoolAPI"},
+	{"txpool", "internal/420api", "PublicTxPoolAPI"},
+}
+
+func main() {
+	var (
+		root   = flag.String("root", ".", "repository root directory")
+		output = flag.String("out", "-", "output file (default is stdout)")
+	)
+	flag.Parse()
+
+	docs, err := collect(*root)
+	if err != nil {
+		fatal(err)
+	}
+	code, err := generate(docs)
+	if err != nil {
+		fatal(err)
+	}
+	if *output == "-" {
+		os.Stdout.Write(code)
+	} else if err := ioutil.WriteFile(*output, code, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
+
+// collect gathers the method docs of all the sources, keyed by namespace and by
+// method name as exposed over RPC.
+func collect(root string) (map[string]map[string]string, error) {
+	packages := make(map[string]*doc.Package)
+	docs := make(map[string]map[string]string)
+
+	for _, src := range sources {
+		pkg, ok := packages[src.dir]
+		if !ok {
+			var err error
+			if pkg, err = parsePackage(filepath.Join(root, src.dir)); err != nil {
+				return nil, err
+			}
+			packages[src.dir] = pkg
+		}
+		var typ *doc.Type
+		for _, t := range pkg.Types {
+			if t.Name == src.typ {
+				typ = t
+				break
+			}
+		}
+		if typ == nil {
+			return nil, fmt.Errorf("type %s not found in %s", src.typ, src.dir)
+		}
+		if docs[src.namespace] == nil {
+			docs[src.namespace] = make(map[string]string)
+		}
+		for _, method := range typ.Methods {
+			if !ast.IsExported(method.Name) || method.Doc == "" {
+				continue
+			}
+			docs[src.namespace][rpcName(method.Name)] = strings.TrimSpace(method.Doc)
+		}
+	}
+	return docs, nil
+}
+
+// parsePackage parses the non-test Go files of a package directory.
+func parsePackage(dir string) (*doc.Package, error) {
+	fset := token.NewFileSet()
+	nontest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, dir, nontest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		return doc.New(pkg, dir, doc.AllDecls|doc.AllMethods), nil
+	}
+	return nil, fmt.Errorf("no package found in %s", dir)
+}
+
+// rpcName converts a Go method name to its RPC name, the same way the RPC server
+// does: by lowercasing the first letter.
+func rpcName(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// generate renders the collected docs as a Go source file.
+func generate(docs map[string]map[string]string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by gendocs. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package web3ext")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// Docs maps the console namespaces to the documentation of their methods, as")
+	fmt.Fprintln(buf, "// extracted from the Go API definitions.")
+	fmt.Fprintln(buf, "var Docs = map[string]map[string]string{")
+	for _, namespace := range sortedKeys(docs) {
+		fmt.Fprintf(buf, "%q: {\n", namespace)
+		for _, method := range sortedKeys(docs[namespace]) {
+			fmt.Fprintf(buf, "%q: %q,\n", method, docs[namespace][method])
+		}
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
+	return format.Source(buf.Bytes())
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]map[string]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -17,6 +17,8 @@
 // package web3ext contains g420 specific web3.js extensions.
 package web3ext
 
+//go:generate go run ./gendocs -root ../.. -out docs_generated.go
+
 var Modules = map[string]string{
 	"accounting": AccountingJs,
 	"admin":      AdminJs,
@@ -24,6 +26,7 @@ var Modules = map[string]string{
 	"clique":     CliqueJs,
 	"ethash":     EthashJs,
 	"debug":      DebugJs,
+	"evm":        EvmJs,
 	"420":        FourtwentyJs,
 	"miner":      MinerJs,
 	"net":        NetJs,
@@ -34,6 +37,7 @@ var Modules = map[string]string{
 	"txpool":     TxpoolJs,
 	"les":        LESJs,
 	"lespay":     LESPayJs,
+	"web3":       Web3Js,
 }
 
 const ChequebookJs = `
@@ -118,6 +122,35 @@ web3._extend({
 });
 `
 
+const EvmJs = `
+web3._extend({
+	property: 'evm',
+	methods: [
+		new web3._extend.Method({
+			name: 'snapshot',
+			call: 'evm_snapshot',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'revert',
+			call: 'evm_revert',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'increaseTime',
+			call: 'evm_increaseTime',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'mine',
+			call: 'evm_mine',
+			params: 0
+		}),
+	]
+});
+`
+
 const EthashJs = `
 web3._extend({
 	property: 'ethash',
@@ -181,6 +214,22 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'approveReorg',
+			call: 'admin_approveReorg',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'scheduleTransaction',
+			call: 'admin_scheduleTransaction',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'cancelScheduledTransaction',
+			call: 'admin_cancelScheduledTransaction',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -220,6 +269,18 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'pendingReorg',
+			getter: 'admin_pendingReorg'
+		}),
+		new web3._extend.Property({
+			name: 'chainTips',
+			getter: 'admin_chainTips'
+		}),
+		new web3._extend.Property({
+			name: 'scheduledTransactions',
+			getter: 'admin_scheduledTransactions'
+		}),
 	]
 });
 `
@@ -251,9 +312,40 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null],
 		}),
+		new web3._extend.Method({
+			name: 'requestSetHead',
+			call: 'debug_requestSetHead',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'setHead',
 			call: 'debug_setHead',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'snapshotStatus',
+			call: 'debug_snapshotStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'rebuildSnapshot',
+			call: 'debug_rebuildSnapshot',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'backfillReceiptStatus',
+			call: 'debug_backfillReceiptStatus',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'cacheBudget',
+			call: 'debug_cacheBudget',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'setCacheBudget',
+			call: 'debug_setCacheBudget',
 			params: 1
 		}),
 		new web3._extend.Method({
@@ -425,6 +517,18 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceBlockStateDiff',
+			call: 'debug_traceBlockStateDiff',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'traceBlockStateDiffByNumber',
+			call: 'debug_traceBlockStateDiffByNumber',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'traceTransaction',
 			call: 'debug_traceTransaction',
@@ -453,6 +557,26 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'executionWitness',
+			call: 'debug_executionWitness',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'verifyExecutionWitness',
+			call: 'debug_verifyExecutionWitness',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'blockArrival',
+			call: 'debug_blockArrival',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'transactionArrival',
+			call: 'debug_transactionArrival',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
@@ -490,6 +614,16 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'hashMessage',
+			call: 'fourtwenty_hashMessage',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'ecRecover',
+			call: 'fourtwenty_ecRecover',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'resend',
 			call: 'fourtwenty_resend',
@@ -509,6 +643,35 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'smokePriceHistory',
+			call: 'fourtwenty_smokePriceHistory',
+			params: 3,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'smokeUsedRatio',
+			call: 'fourtwenty_smokeUsedRatio',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'uncleStats',
+			call: 'fourtwenty_uncleStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'rewardSchedule',
+			call: 'fourtwenty_rewardSchedule'
+		}),
+		new web3._extend.Method({
+			name: 'networkHashrate',
+			call: 'fourtwenty_networkHashrate',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'fourtwenty_submitTransaction',
@@ -549,6 +712,11 @@ web3._extend({
 			call: 'fourtwenty_getRawTransactionByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionConfirmations',
+			call: 'fourtwenty_getTransactionConfirmations',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransactionFromBlock',
 			call: function(args) {
@@ -563,6 +731,18 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getBalances',
+			call: 'fourtwenty_getBalances',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionCounts',
+			call: 'fourtwenty_getTransactionCounts',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -601,6 +781,12 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'setCoinbaseList',
+			call: 'miner_setCoinbaseList',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'setExtra',
 			call: 'miner_setExtra',
@@ -648,6 +834,18 @@ web3._extend({
 			call: 'personal_importRawKey',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'newMnemonic',
+			call: 'personal_newMnemonic',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'importMnemonic',
+			call: 'personal_importMnemonic',
+			params: 4,
+			inputFormatter: [null, null, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'personal_sign',
@@ -755,7 +953,28 @@ web3._extend({
 const TxpoolJs = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'contentFrom',
+			call: 'txpool_contentFrom',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'contentFiltered',
+			call: 'txpool_contentFiltered',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'config',
+			call: 'txpool_config'
+		}),
+		new web3._extend.Method({
+			name: 'setConfig',
+			call: 'txpool_setConfig',
+			params: 1
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -848,6 +1067,11 @@ web3._extend({
 			call: 'les_setClientParams',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'setClientCapacity',
+			call: 'les_setClientCapacity',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'setDefaultParams',
 			call: 'les_setDefaultParams',
@@ -858,6 +1082,33 @@ web3._extend({
 			call: 'les_addBalance',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'setConnectedBias',
+			call: 'les_setConnectedBias',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'benchmark',
+			call: 'les_benchmark',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'checkpointSigHash',
+			call: 'les_checkpointSigHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signCheckpoint',
+			call: 'les_signCheckpoint',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'registerCheckpoint',
+			call: 'les_registerCheckpoint',
+			params: 3,
+			inputFormatter: [null, null, web3._extend.formatters.inputAddressFormatter]
+		}),
 	],
 	properties:
 	[
@@ -869,6 +1120,14 @@ web3._extend({
 			name: 'checkpointContractAddress',
 			getter: 'les_getCheckpointContractAddress'
 		}),
+		new web3._extend.Property({
+			name: 'registeredCheckpoint',
+			getter: 'les_registeredCheckpoint'
+		}),
+		new web3._extend.Property({
+			name: 'checkpointStatus',
+			getter: 'les_compareCheckpoint'
+		}),
 		new web3._extend.Property({
 			name: 'serverInfo',
 			getter: 'les_serverInfo'
@@ -907,3 +1166,15 @@ web3._extend({
 	]
 });
 `
+
+const Web3Js = `
+web3._extend({
+	methods: [],
+	properties: [
+		new web3._extend.Property({
+			name: 'clientBuildInfo',
+			getter: 'web3_clientBuildInfo'
+		}),
+	]
+});
+`
@@ -17,21 +17,30 @@
 package les
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/420integrated/go-420coin/accounts"
+	"github.com/420integrated/go-420coin/accounts/abi/bind"
+	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/hexutil"
 	"github.com/420integrated/go-420coin/common/mclock"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
 	lps "github.com/420integrated/go-420coin/les/lespay/server"
 	"github.com/420integrated/go-420coin/p2p/enode"
 )
 
 var (
-	errNoCheckpoint         = errors.New("no local checkpoint provided")
-	errNotActivated         = errors.New("checkpoint registrar is not activated")
-	errUnknownBenchmarkType = errors.New("unknown benchmark type")
-	errNoPriority           = errors.New("priority too low to raise capacity")
+	errNoCheckpoint           = errors.New("no local checkpoint provided")
+	errNotActivated           = errors.New("checkpoint registrar is not activated")
+	errNoRegisteredCheckpoint = errors.New("no checkpoint registered")
+	errNotEnoughSigners       = errors.New("not enough trusted checkpoint signatures")
+	errUnknownBenchmarkType   = errors.New("unknown benchmark type")
+	errNoPriority             = errors.New("priority too low to raise capacity")
 )
 
 // PrivateLightServerAPI provides an API to access the LES light server.
@@ -178,6 +187,32 @@ func (api *PrivateLightServerAPI) SetClientParams(ids []enode.ID, params map[str
 	return err
 }
 
+// SetClientCapacity assigns the given capacity to a connected client and returns
+// the capacity the client has afterwards. Capacity above the free tier minimum is
+// only granted if the token balance of the client gives it enough priority over
+// the other clients, otherwise the error reports the missing balance.
+func (api *PrivateLightServerAPI) SetClientCapacity(id enode.ID, capacity uint64) (uint64, error) {
+	if capacity < api.server.minCapacity {
+		return 0, fmt.Errorf("capacity %d below minimum %d", capacity, api.server.minCapacity)
+	}
+	var (
+		granted uint64
+		err     error
+	)
+	api.server.clientPool.forClients([]enode.ID{id}, func(client *clientInfo) {
+		if !client.connected {
+			err = fmt.Errorf("client %064x is not connected", id)
+			return
+		}
+		var missing uint64
+		if missing, err = api.server.clientPool.setCapacity(client.node, client.address, capacity, 0, true); err == errNoPriority {
+			err = fmt.Errorf("%v (missing balance %d)", err, missing)
+		}
+		granted, _ = api.server.clientPool.ns.GetField(client.node, priorityPoolSetup.CapacityField).(uint64)
+	})
+	return granted, err
+}
+
 // SetDefaultParams sets the default parameters applicable to clients connected in the future
 func (api *PrivateLightServerAPI) SetDefaultParams(params map[string]interface{}) error {
 	update, err := api.setParams(params, nil, &api.defaultPosFactors, &api.defaultNegFactors)
@@ -360,3 +395,197 @@ func (api *PrivateLightAPI) GetCheckpointContractAddress() (string, error) {
 	}
 	return api.backend.oracle.Contract().ContractAddr().Hex(), nil
 }
+
+// checkpointSummary identifies a checkpoint by its section index and hash.
+type checkpointSummary struct {
+	SectionIndex hexutil.Uint64 `json:"sectionIndex"`
+	Hash         common.Hash    `json:"hash"`
+}
+
+// registeredCheckpoint is a checkpoint registered in the checkpoint oracle contract.
+type registeredCheckpoint struct {
+	SectionIndex hexutil.Uint64 `json:"sectionIndex"`
+	Hash         common.Hash    `json:"hash"`
+	Height       hexutil.Uint64 `json:"height"` // Block number of the registration
+}
+
+// checkpointStatus compares the local checkpoints with the registered one.
+type checkpointStatus struct {
+	Local        *checkpointSummary    `json:"local"`        // Latest local checkpoint (nil = none)
+	Registered   *registeredCheckpoint `json:"registered"`   // Latest registered checkpoint (nil = none)
+	Match        bool                  `json:"match"`        // Whether the local checkpoint of the registered section matches it
+	Unregistered bool                  `json:"unregistered"` // Whether a newer local checkpoint awaits registration
+}
+
+// RegisteredCheckpoint returns the latest checkpoint registered in the checkpoint
+// oracle contract.
+func (api *PrivateLightAPI) RegisteredCheckpoint() (*registeredCheckpoint, error) {
+	oracle := api.backend.oracle
+	if oracle == nil || !oracle.IsRunning() {
+		return nil, errNotActivated
+	}
+	index, hash, height, err := oracle.Contract().Contract().GetLatestCheckpoint(nil)
+	if err != nil {
+		return nil, err
+	}
+	if index == 0 && hash == [32]byte{} {
+		return nil, errNoRegisteredCheckpoint
+	}
+	return &registeredCheckpoint{
+		SectionIndex: hexutil.Uint64(index),
+		Hash:         hash,
+		Height:       hexutil.Uint64(height.Uint64()),
+	}, nil
+}
+
+// CompareCheckpoint compares the latest local checkpoint with the one registered
+// in the checkpoint oracle contract.
+func (api *PrivateLightAPI) CompareCheckpoint() (*checkpointStatus, error) {
+	registered, err := api.RegisteredCheckpoint()
+	if err != nil && err != errNoRegisteredCheckpoint {
+		return nil, err
+	}
+	status := &checkpointStatus{Registered: registered}
+	if cp := api.backend.latestLocalCheckpoint(); !cp.Empty() {
+		status.Local = &checkpointSummary{SectionIndex: hexutil.Uint64(cp.SectionIndex), Hash: cp.Hash()}
+		status.Unregistered = registered == nil || cp.SectionIndex > uint64(registered.SectionIndex)
+	}
+	if registered != nil {
+		local := api.backend.localCheckpoint(uint64(registered.SectionIndex))
+		status.Match = local.HashEqual(registered.Hash)
+	}
+	return status, nil
+}
+
+// CheckpointSigHash returns the hash the trusted signers need to sign to approve
+// the local checkpoint of the given section for registration.
+func (api *PrivateLightAPI) CheckpointSigHash(index uint64) (common.Hash, error) {
+	if api.backend.oracle == nil {
+		return common.Hash{}, errNotActivated
+	}
+	cp := api.backend.localCheckpoint(index)
+	if cp.Empty() {
+		return common.Hash{}, errNoCheckpoint
+	}
+	return crypto.Keccak256Hash(api.backend.oracle.SignatureData(index, cp.Hash())), nil
+}
+
+// SignCheckpoint signs the local checkpoint of the given section with a trusted
+// signer account of the local node, returning the signature to register it with.
+func (api *PrivateLightAPI) SignCheckpoint(index uint64, signer common.Address) (hexutil.Bytes, error) {
+	if api.backend.oracle == nil {
+		return nil, errNotActivated
+	}
+	cp := api.backend.localCheckpoint(index)
+	if cp.Empty() {
+		return nil, errNoCheckpoint
+	}
+	account := accounts.Account{Address: signer}
+	wallet, err := api.backend.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := wallet.SignData(account, accounts.MimetypeDataWithValidator, api.backend.oracle.SignatureData(index, cp.Hash()))
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // Transform V from 0/1 to 27/28 as expected by the contract
+	return sig, nil
+}
+
+// RegisterCheckpoint registers the local checkpoint of the given section in the
+// checkpoint oracle contract with the approvals of the trusted signers, sending
+// the transaction from the given admin account of the local node.
+func (api *PrivateLightAPI) RegisterCheckpoint(index uint64, signatures []hexutil.Bytes, sender common.Address) (common.Hash, error) {
+	oracle := api.backend.oracle
+	if oracle == nil || !oracle.IsRunning() {
+		return common.Hash{}, errNotActivated
+	}
+	cp := api.backend.localCheckpoint(index)
+	if cp.Empty() {
+		return common.Hash{}, errNoCheckpoint
+	}
+	// Ensure the transaction will be accepted by the contract
+	admins, err := oracle.Contract().Contract().GetAllAdmin(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	admin := false
+	for _, addr := range admins {
+		if addr == sender {
+			admin = true
+		}
+	}
+	if !admin {
+		return common.Hash{}, fmt.Errorf("account %x is not a checkpoint admin", sender)
+	}
+	var (
+		sigs    = make([][]byte, len(signatures))
+		signers = make([]common.Address, len(signatures))
+		copies  = make([][]byte, len(signatures))
+		sighash = crypto.Keccak256(oracle.SignatureData(index, cp.Hash()))
+	)
+	for i, sig := range signatures {
+		if len(sig) != 65 {
+			return common.Hash{}, fmt.Errorf("invalid signature %d length: %d", i, len(sig))
+		}
+		sigs[i], copies[i] = common.CopyBytes(sig), common.CopyBytes(sig)
+
+		rsv := common.CopyBytes(sig)
+		rsv[64] -= 27 // Transform V from 27/28 to 0/1 for recovery
+		pubkey, err := crypto.SigToPub(sighash, rsv)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid signature %d: %v", i, err)
+		}
+		signers[i] = crypto.PubkeyToAddress(*pubkey)
+	}
+	if ok, _ := oracle.VerifySigners(index, cp.Hash(), copies); !ok {
+		return common.Hash{}, errNotEnoughSigners
+	}
+	// The contract requires the signatures sorted by signer address
+	sort.Sort(signaturesBySigner{sigs, signers})
+
+	// Reference a recent block to protect against replays on other chains
+	number := api.backend.chainReader.CurrentHeader().Number.Uint64()
+	if number > 128 {
+		number -= 128
+	}
+	recent := api.backend.chainReader.GetHeaderByNumber(number)
+	if recent == nil {
+		return common.Hash{}, fmt.Errorf("recent header #%d not found", number)
+	}
+	account := accounts.Account{Address: sender}
+	wallet, err := api.backend.accountManager.Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := &bind.TransactOpts{
+		From: sender,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != sender {
+				return nil, bind.ErrNotAuthorized
+			}
+			return wallet.SignTx(account, tx, api.backend.chainConfig.ChainID)
+		},
+	}
+	tx, err := oracle.Contract().RegisterCheckpoint(opts, index, cp.Hash().Bytes(), recent.Number, recent.Hash(), sigs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// signaturesBySigner sorts checkpoint signatures by the address of their signer.
+type signaturesBySigner struct {
+	sigs    [][]byte
+	signers []common.Address
+}
+
+func (s signaturesBySigner) Len() int { return len(s.sigs) }
+func (s signaturesBySigner) Less(i, j int) bool {
+	return bytes.Compare(s.signers[i][:], s.signers[j][:]) < 0
+}
+func (s signaturesBySigner) Swap(i, j int) {
+	s.sigs[i], s.sigs[j] = s.sigs[j], s.sigs[i]
+	s.signers[i], s.signers[j] = s.signers[j], s.signers[i]
+}
@@ -53,9 +53,9 @@ func (b *LesApiBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.fourtwenty.BlockChain().CurrentHeader())
 }
 
-func (b *LesApiBackend) SetHead(number uint64) {
+func (b *LesApiBackend) SetHead(number uint64) error {
 	b.fourtwenty.handler.downloader.Cancel()
-	b.fourtwenty.blockchain.SetHead(number)
+	return b.fourtwenty.blockchain.SetHead(number)
 }
 
 func (b *LesApiBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -176,6 +176,8 @@ func (b *LesApiBackend) GetEVM(ctx context.Context, msg core.Message, state *sta
 	return vm.NewEVM(context, txContext, state, b.fourtwenty.chainConfig, vm.Config{}), state.Error, nil
 }
 
+func (b *LesApiBackend) ReleaseEVM(evm *vm.EVM, header *types.Header) {}
+
 func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	return b.fourtwenty.txPool.Add(ctx, signedTx)
 }
@@ -208,10 +210,21 @@ func (b *LesApiBackend) TxPoolContent() (map[common.Address]types.Transactions,
 	return b.fourtwenty.txPool.Content()
 }
 
+func (b *LesApiBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.fourtwenty.txPool.ContentFrom(addr)
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.fourtwenty.txPool.SubscribeNewTxsEvent(ch)
 }
 
+func (b *LesApiBackend) SubscribeDroppedTxEvent(ch chan<- core.DroppedTxEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
 func (b *LesApiBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
 	return b.fourtwenty.blockchain.SubscribeChainEvent(ch)
 }
@@ -251,6 +264,10 @@ func (b *LesApiBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return b.gpo.SuggestPrice(ctx)
 }
 
+func (b *LesApiBackend) SmokePriceHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []float64, error) {
+	return b.gpo.SmokePriceHistory(ctx, blocks, lastBlock, percentiles)
+}
+
 func (b *LesApiBackend) ChainDb() fourtwentydb.Database {
 	return b.fourtwenty.chainDb
 }
@@ -271,6 +288,14 @@ func (b *LesApiBackend) RPCTxFeeCap() float64 {
 	return b.fourtwenty.config.RPCTxFeeCap
 }
 
+func (b *LesApiBackend) RPCStructuredErrors() bool {
+	return b.fourtwenty.config.RPCStructuredErrors
+}
+
+func (b *LesApiBackend) RPCRecipientCheck() bool {
+	return b.fourtwenty.config.RPCRecipientCheck
+}
+
 func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	if b.fourtwenty.bloomIndexer == nil {
 		return 0, 0
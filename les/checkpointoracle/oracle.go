@@ -116,6 +116,14 @@ func (oracle *CheckpointOracle) StableCheckpoint() (*params.TrustedCheckpoint, u
 	return nil, 0
 }
 
+// SignatureData returns the EIP 191 style data the trusted signers sign to
+// approve a checkpoint, the keccak256 hash of which is verified by the contract.
+func (oracle *CheckpointOracle) SignatureData(index uint64, hash [32]byte) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, index)
+	return append([]byte{0x19, 0x00}, append(oracle.config.Address.Bytes(), append(buf, hash[:]...)...)...)
+}
+
 // VerifySigners recovers the signer addresses according to the signature and
 // checks if there are enough approvals to finalize the checkpoint.
 func (oracle *CheckpointOracle) VerifySigners(index uint64, hash [32]byte, signatures [][]byte) (bool, []common.Address) {
@@ -141,9 +149,7 @@ func (oracle *CheckpointOracle) VerifySigners(index uint64, hash [32]byte, signa
 		// 4 : checkpoint section_index (uint64)
 		// 5 : checkpoint hash (bytes32)
 		//     hash = keccak256(checkpoint_index, section_head, cht_root, bloom_root)
-		buf := make([]byte, 8)
-		binary.BigEndian.PutUint64(buf, index)
-		data := append([]byte{0x19, 0x00}, append(oracle.config.Address.Bytes(), append(buf, hash[:]...)...)...)
+		data := oracle.SignatureData(index, hash)
 		signatures[i][64] -= 27 // Transform V from 27/28 to 0/1 according to the yellow paper for verification.
 		pubkey, err := crypto.Ecrecover(crypto.Keccak256(data), signatures[i])
 		if err != nil {
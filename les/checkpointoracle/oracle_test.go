@@ -0,0 +1,73 @@
+// Copyright 2019 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package checkpointoracle
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that checkpoint approvals signed over the signature data are accepted
+// once enough trusted signers approved the checkpoint.
+func TestVerifySigners(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+	}
+	oracle := New(&params.CheckpointOracleConfig{
+		Address:   common.HexToAddress("0x0420"),
+		Signers:   []common.Address{crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[1].PublicKey)},
+		Threshold: 2,
+	}, nil)
+
+	var (
+		index = uint64(3)
+		hash  = common.HexToHash("0xdeadbeef")
+	)
+	sign := func(key *ecdsa.PrivateKey) []byte {
+		sig, err := crypto.Sign(crypto.Keccak256(oracle.SignatureData(index, hash)), key)
+		if err != nil {
+			t.Fatalf("failed to sign checkpoint: %v", err)
+		}
+		sig[64] += 27
+		return sig
+	}
+	tests := []struct {
+		signers []*ecdsa.PrivateKey
+		ok      bool
+	}{
+		{[]*ecdsa.PrivateKey{keys[0]}, false},
+		{[]*ecdsa.PrivateKey{keys[0], keys[0]}, false},
+		{[]*ecdsa.PrivateKey{keys[0], keys[2]}, false},
+		{[]*ecdsa.PrivateKey{keys[0], keys[1]}, true},
+		{[]*ecdsa.PrivateKey{keys[1], keys[2], keys[0]}, true},
+	}
+	for i, tt := range tests {
+		var sigs [][]byte
+		for _, key := range tt.signers {
+			sigs = append(sigs, sign(key))
+		}
+		if ok, _ := oracle.VerifySigners(index, hash, sigs); ok != tt.ok {
+			t.Errorf("test %d: verification mismatch: have %v, want %v", i, ok, tt.ok)
+		}
+	}
+}
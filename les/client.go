@@ -21,11 +21,11 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/420integrated/go-420coin/accounts"
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/hexutil"
 	"github.com/420integrated/go-420coin/common/mclock"
 	"github.com/420integrated/go-420coin/consensus"
+	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/core/bloombits"
 	"github.com/420integrated/go-420coin/core/rawdb"
@@ -65,17 +65,17 @@ type Light420coin struct {
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
 
-	ApiBackend     *LesApiBackend
-	eventMux       *event.TypeMux
-	engine         consensus.Engine
-	accountManager *accounts.Manager
-	netRPCService  *fourtwentyapi.PublicNetAPI
+	ApiBackend    *LesApiBackend
+	eventMux      *event.TypeMux
+	engine        consensus.Engine
+	netRPCService *fourtwentyapi.PublicNetAPI
 
 	p2pServer *p2p.Server
 }
 
 // New creates an instance of the light client.
 func New(stack *node.Node, config *fourtwenty.Config) (*Light420coin, error) {
+	rawdb.SetValueCompression(config.DatabaseCompress)
 	chainDb, err := stack.OpenDatabase("lightchaindata", config.DatabaseCache, config.DatabaseHandles, "420/db/chaindata/")
 	if err != nil {
 		return nil, err
@@ -84,7 +84,7 @@ func New(stack *node.Node, config *fourtwenty.Config) (*Light420coin, error) {
 	if err != nil {
 		return nil, err
 	}
-	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
+	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlockWithOverride(chainDb, config.Genesis, config.RewardEraOverrides())
 	if _, isCompat := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !isCompat {
 		return nil, genesisErr
 	}
@@ -93,22 +93,22 @@ func New(stack *node.Node, config *fourtwenty.Config) (*Light420coin, error) {
 	peers := newServerPeerSet()
 	l420 := &Light420coin{
 		lesCommons: lesCommons{
-			genesis:     genesisHash,
-			config:      config,
-			chainConfig: chainConfig,
-			iConfig:     light.DefaultClientIndexerConfig,
-			chainDb:     chainDb,
-			closeCh:     make(chan struct{}),
+			genesis:        genesisHash,
+			config:         config,
+			chainConfig:    chainConfig,
+			iConfig:        light.DefaultClientIndexerConfig,
+			chainDb:        chainDb,
+			accountManager: stack.AccountManager(),
+			closeCh:        make(chan struct{}),
 		},
-		peers:          peers,
-		eventMux:       stack.EventMux(),
-		reqDist:        newRequestDistributor(peers, &mclock.System{}),
-		accountManager: stack.AccountManager(),
-		engine:         fourtwenty.CreateConsensusEngine(stack, chainConfig, &config.Ethash, nil, false, chainDb),
-		bloomRequests:  make(chan chan *bloombits.Retrieval),
-		bloomIndexer:   fourtwenty.NewBloomIndexer(chainDb, params.BloomBitsBlocksClient, params.HelperTrieConfirmations),
-		valueTracker:   lpc.NewValueTracker(lespayDb, &mclock.System{}, requestList, time.Minute, 1/float64(time.Hour), 1/float64(time.Hour*100), 1/float64(time.Hour*1000)),
-		p2pServer:      stack.Server(),
+		peers:         peers,
+		eventMux:      stack.EventMux(),
+		reqDist:       newRequestDistributor(peers, &mclock.System{}),
+		engine:        fourtwenty.CreateConsensusEngine(stack, chainConfig, &config.Ethash, nil, false, chainDb),
+		bloomRequests: make(chan chan *bloombits.Retrieval),
+		bloomIndexer:  fourtwenty.NewBloomIndexer(chainDb, params.BloomBitsBlocksClient, params.HelperTrieConfirmations),
+		valueTracker:  lpc.NewValueTracker(lespayDb, &mclock.System{}, requestList, time.Minute, 1/float64(time.Hour), 1/float64(time.Hour*100), 1/float64(time.Hour*1000)),
+		p2pServer:     stack.Server(),
 	}
 	peers.subscribe((*vtSubscription)(l420.valueTracker))
 
@@ -128,6 +128,11 @@ func New(stack *node.Node, config *fourtwenty.Config) (*Light420coin, error) {
 	l420.bloomTrieIndexer = light.NewBloomTrieIndexer(chainDb, l420.odr, params.BloomBitsBlocksClient, params.BloomTrieFrequency, config.LightNoPrune)
 	l420.odr.SetIndexers(l420.chtIndexer, l420.bloomTrieIndexer, l420.bloomIndexer)
 
+	// Verify header proof-of-work with dataset items served by the servers if requested
+	if engine, ok := l420.engine.(*ethash.Ethash); ok && config.LightRemotePoW {
+		engine.SetPowItemSource(l420.odr.PowItems)
+	}
+
 	checkpoint := config.Checkpoint
 	if checkpoint == nil {
 		checkpoint = params.TrustedCheckpoints[genesisHash]
@@ -171,7 +176,7 @@ func New(stack *node.Node, config *fourtwenty.Config) (*Light420coin, error) {
 		l420.blockchain.DisableCheckFreq()
 	}
 
-	l420.netRPCService = fourtwentyapi.NewPublicNetAPI(l420.p2pServer)
+	l420.netRPCService = fourtwentyapi.NewPublicNetAPI(l420.p2pServer, config.NetworkId)
 
 	// Register the backend on the node
 	stack.RegisterAPIs(l420.APIs())
@@ -238,6 +243,9 @@ func (s *LightDummyAPI) Mining() bool {
 func (s *Light420coin) APIs() []rpc.API {
 	apis := fourtwentyapi.GetAPIs(s.ApiBackend)
 	apis = append(apis, s.engine.APIs(s.BlockChain().HeaderChain())...)
+	if s.config.RPCEthCompat {
+		apis = append(apis, fourtwentyapi.EthCompatAPIs()...)
+	}
 	return append(apis, []rpc.API{
 		{
 			Namespace: "fourtwenty",
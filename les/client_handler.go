@@ -201,6 +201,11 @@ func (h *clientHandler) handleMsg(p *serverPeer) error {
 		if err := msg.Decode(&resp); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
+		for _, header := range resp.Headers {
+			if err := header.SanityCheck(); err != nil {
+				return err
+			}
+		}
 		headers := resp.Headers
 		p.fcServer.ReceivedReply(resp.ReqID, resp.BV)
 		p.answeredRequest(resp.ReqID)
@@ -320,6 +325,22 @@ func (h *clientHandler) handleMsg(p *serverPeer) error {
 			ReqID:   resp.ReqID,
 			Obj:     resp.Status,
 		}
+	case msg.Code == PowItemsMsg && p.version >= lpv4:
+		p.Log().Trace("Received proof-of-work items response")
+		var resp struct {
+			ReqID, BV uint64
+			Items     [][][]byte
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.ReceivedReply(resp.ReqID, resp.BV)
+		p.answeredRequest(resp.ReqID)
+		deliverMsg = &Msg{
+			MsgType: MsgPowItems,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Items,
+		}
 	case msg.Code == StopMsg && p.version >= lpv3:
 		p.freeze()
 		h.backend.retriever.frozen(p)
@@ -0,0 +1,63 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"testing"
+
+	"github.com/420integrated/go-420coin/420"
+	"github.com/420integrated/go-420coin/420/downloader"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/node"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that net_version reports the configured network ID of a light node, not
+// the chain ID of its genesis.
+func TestNetVersion(t *testing.T) {
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	defer stack.Close()
+
+	config := fourtwenty.DefaultConfig
+	config.SyncMode = downloader.LightSync
+	config.Ethash.PowMode = ethash.ModeFake
+	config.Genesis = &core.Genesis{Config: params.AllEthashProtocolChanges}
+	config.NetworkId = 4200
+	if _, err := New(stack, &config); err != nil {
+		t.Fatalf("failed to create light client: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	client, err := stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to attach to node: %v", err)
+	}
+	defer client.Close()
+
+	var version string
+	if err := client.Call(&version, "net_version"); err != nil {
+		t.Fatalf("failed to retrieve network version: %v", err)
+	}
+	if version != "4200" {
+		t.Fatalf("network version mismatch: have %s, want 4200 (chain ID %v)", version, params.AllEthashProtocolChanges.ChainID)
+	}
+}
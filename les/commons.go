@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/420integrated/go-420coin/accounts"
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/core/rawdb"
@@ -55,6 +56,7 @@ func lesTopic(genesisHash common.Hash, protocolVersion uint) discv5.Topic {
 
 type chainReader interface {
 	CurrentHeader() *types.Header
+	GetHeaderByNumber(number uint64) *types.Header
 }
 
 // lesCommons contains fields needed by both server and client.
@@ -67,6 +69,7 @@ type lesCommons struct {
 	chainReader                  chainReader
 	chtIndexer, bloomTrieIndexer *core.ChainIndexer
 	oracle                       *checkpointoracle.CheckpointOracle
+	accountManager               *accounts.Manager
 
 	closeCh chan struct{}
 	wg      sync.WaitGroup
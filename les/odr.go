@@ -22,6 +22,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common/mclock"
 	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/420db"
 	"github.com/420integrated/go-420coin/light"
 )
@@ -89,8 +90,26 @@ const (
 	MsgProofsV2
 	MsgHelperTrieProofs
 	MsgTxStatus
+	MsgPowItems
 )
 
+// powItemsTimeout is the maximum time allowed to retrieve the proof-of-work
+// dataset items of a single header.
+const powItemsTimeout = 10 * time.Second
+
+// PowItems retrieves the proof-of-work dataset items of a header from the LES
+// network (implementation of ethash.PowItemSource)
+func (odr *LesOdr) PowItems(header *types.Header) ([][]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), powItemsTimeout)
+	defer cancel()
+
+	req := &light.PowItemsRequest{Header: header}
+	if err := odr.Retrieve(ctx, req); err != nil {
+		return nil, err
+	}
+	return req.Items, nil
+}
+
 // Msg encodes a LES message that delivers reply data for a request
 type Msg struct {
 	MsgType int
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core/rawdb"
 	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/crypto"
@@ -68,6 +69,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*BloomRequest)(r)
 	case *light.TxStatusRequest:
 		return (*TxStatusRequest)(r)
+	case *light.PowItemsRequest:
+		return (*PowItemsRequest)(r)
 	default:
 		return nil
 	}
@@ -515,6 +518,45 @@ func (r *TxStatusRequest) Validate(db fourtwentydb.Database, msg *Msg) error {
 	return nil
 }
 
+// PowItemsRequest is the ODR request type for proof-of-work dataset items
+type PowItemsRequest light.PowItemsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *PowItemsRequest) GetCost(peer *serverPeer) uint64 {
+	return peer.getRequestCost(GetPowItemsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *PowItemsRequest) CanSend(peer *serverPeer) bool {
+	return peer.version >= lpv4 && peer.HasBlock(r.Header.Hash(), r.Header.Number.Uint64(), false)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *PowItemsRequest) Request(reqID uint64, peer *serverPeer) error {
+	peer.Log().Debug("Requesting proof-of-work items", "number", r.Header.Number, "hash", r.Header.Hash())
+	return peer.requestPowItems(reqID, []common.Hash{r.Header.Hash()})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *PowItemsRequest) Validate(db fourtwentydb.Database, msg *Msg) error {
+	log.Debug("Validating proof-of-work items", "number", r.Header.Number, "hash", r.Header.Hash())
+
+	// Ensure we have a correct message with a single item set. The items can
+	// only be checked by the consensus engine while verifying the seal.
+	if msg.MsgType != MsgPowItems {
+		return errInvalidMessageType
+	}
+	items := msg.Obj.([][][]byte)
+	if len(items) != 1 || len(items[0]) != ethash.PowItemCount {
+		return errInvalidEntryCount
+	}
+	r.Items = items[0]
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
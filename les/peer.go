@@ -480,6 +480,13 @@ func (p *serverPeer) requestTxStatus(reqID uint64, txHashes []common.Hash) error
 	return p.sendRequest(GetTxStatusMsg, reqID, txHashes, len(txHashes))
 }
 
+// requestPowItems fetches the proof-of-work dataset items of a batch of headers
+// from a remote node.
+func (p *serverPeer) requestPowItems(reqID uint64, hashes []common.Hash) error {
+	p.Log().Debug("Requesting proof-of-work items", "count", len(hashes))
+	return p.sendRequest(GetPowItemsMsg, reqID, hashes, len(hashes))
+}
+
 // SendTxs creates a reply with a batch of transactions to be added to the remote transaction pool.
 func (p *serverPeer) sendTxs(reqID uint64, amount int, txs rlp.RawValue) error {
 	p.Log().Debug("Sending batch of transactions", "amount", amount, "size", len(txs))
@@ -918,6 +925,13 @@ func (p *clientPeer) replyTxStatus(reqID uint64, stats []light.TxStatus) *reply
 	return &reply{p.rw, TxStatusMsg, reqID, data}
 }
 
+// replyPowItems creates a reply with the proof-of-work dataset items of a batch
+// of headers.
+func (p *clientPeer) replyPowItems(reqID uint64, items [][][]byte) *reply {
+	data, _ := rlp.EncodeToBytes(items)
+	return &reply{p.rw, PowItemsMsg, reqID, data}
+}
+
 // sendAnnounce announces the availability of a number of blocks through
 // a hash notification.
 func (p *clientPeer) sendAnnounce(request announceData) error {
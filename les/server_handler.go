@@ -27,6 +27,7 @@ import (
 
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/mclock"
+	"github.com/420integrated/go-420coin/consensus/ethash"
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/core/forkid"
 	"github.com/420integrated/go-420coin/core/rawdb"
@@ -57,6 +58,7 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of helper tries to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxPowItemsFetch         = 16  // Amount of headers to serve proof-of-work dataset items for per request
 )
 
 var (
@@ -858,6 +860,56 @@ func (h *serverHandler) handleMsg(p *clientPeer, wg *sync.WaitGroup) error {
 			}()
 		}
 
+	case GetPowItemsMsg:
+		p.Log().Trace("Received proof-of-work items request")
+		if metrics.EnabledExpensive {
+			miscInPowItemsPacketsMeter.Mark(1)
+			miscInPowItemsTrafficMeter.Mark(int64(msg.Size))
+		}
+		var req struct {
+			ReqID  uint64
+			Hashes []common.Hash
+		}
+		if err := msg.Decode(&req); err != nil {
+			clientErrorMeter.Mark(1)
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		engine, ok := h.blockchain.Engine().(*ethash.Ethash)
+		if !ok {
+			p.Log().Debug("Rejected proof-of-work items request without ethash")
+			p.bumpInvalid()
+			return nil
+		}
+		reqCnt := len(req.Hashes)
+		if accept(req.ReqID, uint64(reqCnt), MaxPowItemsFetch) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				items := make([][][]byte, 0, len(req.Hashes))
+				for i, hash := range req.Hashes {
+					if i != 0 && !task.waitOrStop() {
+						sendResponse(req.ReqID, 0, nil, task.servingTime)
+						return
+					}
+					header := h.blockchain.GetHeaderByHash(hash)
+					if header == nil {
+						p.Log().Debug("Failed to retrieve header for proof-of-work items", "hash", hash)
+						p.bumpInvalid()
+						items = append(items, nil)
+						continue
+					}
+					items = append(items, engine.PowItems(header))
+				}
+				reply := p.replyPowItems(req.ReqID, items)
+				sendResponse(req.ReqID, uint64(reqCnt), reply, task.done())
+				if metrics.EnabledExpensive {
+					miscOutPowItemsPacketsMeter.Mark(1)
+					miscOutPowItemsTrafficMeter.Mark(int64(reply.size()))
+					miscServingTimePowItemsTimer.Update(time.Duration(task.servingTime))
+				}
+			}()
+		}
+
 	default:
 		p.Log().Trace("Received invalid message", "code", msg.Code)
 		clientErrorMeter.Mark(1)
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/cache"
 	"github.com/420integrated/go-420coin/consensus"
 	"github.com/420integrated/go-420coin/core"
 	"github.com/420integrated/go-420coin/core/rawdb"
@@ -37,7 +38,6 @@ import (
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rlp"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 var (
@@ -60,9 +60,9 @@ type LightChain struct {
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
-	bodyCache    *lru.Cache // Cache for the most recent block bodies
-	bodyRLPCache *lru.Cache // Cache for the most recent block bodies in RLP encoded format
-	blockCache   *lru.Cache // Cache for the most recent entire blocks
+	bodyCache    *cache.Cache // Cache for the most recent block bodies
+	bodyRLPCache *cache.Cache // Cache for the most recent block bodies in RLP encoded format
+	blockCache   *cache.Cache // Cache for the most recent entire blocks
 
 	chainmu sync.RWMutex // protects header inserts
 	quit    chan struct{}
@@ -78,9 +78,9 @@ type LightChain struct {
 // available in the database. It initialises the default 420coin header
 // validator.
 func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.Engine, checkpoint *params.TrustedCheckpoint) (*LightChain, error) {
-	bodyCache, _ := lru.New(bodyCacheLimit)
-	bodyRLPCache, _ := lru.New(bodyCacheLimit)
-	blockCache, _ := lru.New(blockCacheLimit)
+	bodyCache := cache.New("light/cache/bodies", bodyCacheLimit, 0)
+	bodyRLPCache := cache.New("light/cache/bodyrlps", bodyCacheLimit, 0)
+	blockCache := cache.New("light/cache/blocks", blockCacheLimit, 0)
 
 	bc := &LightChain{
 		chainDb:       odr.Database(),
@@ -263,7 +263,7 @@ func (lc *LightChain) GetBodyRLP(ctx context.Context, hash common.Hash) (rlp.Raw
 		return nil, err
 	}
 	// Cache the found body for next time and return
-	lc.bodyRLPCache.Add(hash, body)
+	lc.bodyRLPCache.AddSized(hash, body, len(body))
 	return body, nil
 }
 
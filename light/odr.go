@@ -190,3 +190,13 @@ type TxStatusRequest struct {
 
 // StoreResult stores the retrieved data in local database
 func (req *TxStatusRequest) StoreResult(db fourtwentydb.Database) {}
+
+// PowItemsRequest is the ODR request type for retrieving the ethash dataset items
+// needed to verify the seal of a header
+type PowItemsRequest struct {
+	Header *types.Header
+	Items  [][]byte
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *PowItemsRequest) StoreResult(db fourtwentydb.Database) {}
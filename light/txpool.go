@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -63,8 +64,8 @@ type TxPool struct {
 	chainDb      fourtwentydb.Database
 	relay        TxRelayBackend
 	head         common.Hash
-	nonce        map[common.Address]uint64            // "pending" nonce
 	pending      map[common.Hash]*types.Transaction   // pending transactions by tx hash
+	nonces       map[common.Address]txNonceIndex      // pending transactions by sender and nonce
 	mined        map[common.Hash][]*types.Transaction // mined transactions by block hash
 	clearIdx     uint64                               // earliest block nr that can contain mined tx info
 
@@ -91,8 +92,8 @@ func NewTxPool(config *params.ChainConfig, chain *LightChain, relay TxRelayBacke
 	pool := &TxPool{
 		config:      config,
 		signer:      types.NewEIP155Signer(config.ChainID),
-		nonce:       make(map[common.Address]uint64),
 		pending:     make(map[common.Hash]*types.Transaction),
+		nonces:      make(map[common.Address]txNonceIndex),
 		mined:       make(map[common.Hash][]*types.Transaction),
 		quit:        make(chan bool),
 		chainHeadCh: make(chan core.ChainHeadEvent, chainHeadChanSize),
@@ -117,23 +118,56 @@ func (pool *TxPool) currentState(ctx context.Context) *state.StateDB {
 
 // GetNonce returns the "pending" nonce of a given address. It always queries
 // the nonce belonging to the latest header too in order to detect if another
-// client using the same key sent a transaction.
+// client using the same key sent a transaction, and continues from there over
+// the locally sent transactions with consecutive nonces, so that a dropped or
+// not yet sent transaction is reported as a gap to fill instead of skipped.
 func (pool *TxPool) GetNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	state := pool.currentState(ctx)
 	nonce := state.GetNonce(addr)
 	if state.Error() != nil {
 		return 0, state.Error()
 	}
-	sn, ok := pool.nonce[addr]
-	if ok && sn > nonce {
-		nonce = sn
-	}
-	if !ok || sn < nonce {
-		pool.nonce[addr] = nonce
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	for pool.nonces[addr][nonce] != nil {
+		nonce++
 	}
 	return nonce, nil
 }
 
+// txNonceIndex is the set of pending transactions of a single account, indexed
+// by their nonce.
+type txNonceIndex map[uint64]*types.Transaction
+
+// track adds a transaction to the pending set. The lock must be held.
+func (pool *TxPool) track(tx *types.Transaction) {
+	pool.pending[tx.Hash()] = tx
+
+	from, _ := types.Sender(pool.signer, tx)
+	if pool.nonces[from] == nil {
+		pool.nonces[from] = make(txNonceIndex)
+	}
+	pool.nonces[from][tx.Nonce()] = tx
+}
+
+// untrack removes a transaction from the pending set. The lock must be held.
+func (pool *TxPool) untrack(hash common.Hash) {
+	tx := pool.pending[hash]
+	if tx == nil {
+		return
+	}
+	delete(pool.pending, hash)
+
+	from, _ := types.Sender(pool.signer, tx)
+	if index := pool.nonces[from]; index[tx.Nonce()] == tx {
+		delete(index, tx.Nonce())
+		if len(index) == 0 {
+			delete(pool.nonces, from)
+		}
+	}
+}
+
 // txStateChanges stores the recent changes between pending/mined states of
 // transactions. True means mined, false means rolled back, no entry means no change
 type txStateChanges map[common.Hash]bool
@@ -189,7 +223,7 @@ func (pool *TxPool) checkMinedTxs(ctx context.Context, hash common.Hash, number
 
 		// Update the transaction pool's state
 		for _, tx := range list {
-			delete(pool.pending, tx.Hash())
+			pool.untrack(tx.Hash())
 			txc.setState(tx.Hash(), true)
 		}
 		pool.mined[hash] = list
@@ -205,7 +239,7 @@ func (pool *TxPool) rollbackTxs(hash common.Hash, txc txStateChanges) {
 		for _, tx := range list {
 			txHash := tx.Hash()
 			rawdb.DeleteTxLookupEntry(batch, txHash)
-			pool.pending[txHash] = tx
+			pool.track(tx)
 			txc.setState(txHash, false)
 		}
 		delete(pool.mined, hash)
@@ -381,7 +415,7 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 	}
 
 	// Should supply enough intrinsic smoke
-	smoke, err := core.IntrinsicSmoke(tx.Data(), tx.To() == nil, true, pool.istanbul)
+	smoke, err := core.IntrinsicSmoke(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.istanbul)
 	if err != nil {
 		return err
 	}
@@ -392,7 +426,8 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 }
 
 // add validates a new transaction and sets its state pending if processable.
-// It also updates the locally stored nonce if necessary.
+// A pending transaction of the same account and nonce is replaced if the new
+// one pays a high enough smoke price, discarding the old one.
 func (pool *TxPool) add(ctx context.Context, tx *types.Transaction) error {
 	hash := tx.Hash()
 
@@ -403,16 +438,20 @@ func (pool *TxPool) add(ctx context.Context, tx *types.Transaction) error {
 	if err != nil {
 		return err
 	}
-
-	if _, ok := pool.pending[hash]; !ok {
-		pool.pending[hash] = tx
-
-		nonce := tx.Nonce() + 1
-
-		addr, _ := types.Sender(pool.signer, tx)
-		if nonce > pool.nonce[addr] {
-			pool.nonce[addr] = nonce
+	from, _ := types.Sender(pool.signer, tx)
+	if old := pool.nonces[from][tx.Nonce()]; old != nil {
+		threshold := new(big.Int).Mul(old.SmokePrice(), big.NewInt(100+int64(core.DefaultTxPoolConfig.PriceBump)))
+		threshold.Div(threshold, big.NewInt(100))
+		if tx.SmokePrice().Cmp(threshold) < 0 {
+			return core.ErrReplaceUnderpriced
 		}
+		pool.untrack(old.Hash())
+		pool.chainDb.Delete(old.Hash().Bytes())
+		pool.relay.Discard([]common.Hash{old.Hash()})
+		log.Debug("Replaced pending transaction", "old", old.Hash(), "new", hash, "nonce", tx.Nonce())
+	}
+	if _, ok := pool.pending[hash]; !ok {
+		pool.track(tx)
 
 		// Notify the subscribers. This event is posted in a goroutine
 		// because it's possible that somewhere during the post "Remove transaction"
@@ -421,7 +460,7 @@ func (pool *TxPool) add(ctx context.Context, tx *types.Transaction) error {
 	}
 
 	// Print a log message if low enough level is set
-	log.Debug("Pooled new transaction", "hash", hash, "from", log.Lazy{Fn: func() common.Address { from, _ := types.Sender(pool.signer, tx); return from }}, "to", tx.To())
+	log.Debug("Pooled new transaction", "hash", hash, "from", from, "to", tx.To())
 	return nil
 }
 
@@ -466,6 +505,9 @@ func (pool *TxPool) AddBatch(ctx context.Context, txs []*types.Transaction) {
 // GetTransaction returns a transaction if it is contained in the pool
 // and nil otherwise.
 func (pool *TxPool) GetTransaction(hash common.Hash) *types.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
 	// check the txs first
 	if tx, ok := pool.pending[hash]; ok {
 		return tx
@@ -505,6 +547,27 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool, returning the
+// pending as well as queued transactions of this address, grouped by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	// Retrieve the pending transactions and sort by nonce
+	var pending types.Transactions
+	for _, tx := range pool.pending {
+		account, _ := types.Sender(pool.signer, tx)
+		if account != addr {
+			continue
+		}
+		pending = append(pending, tx)
+	}
+	sort.Sort(types.TxByNonce(pending))
+
+	// There are no queued transactions in a light pool, just return an empty list
+	return pending, types.Transactions{}
+}
+
 // RemoveTransactions removes all given transactions from the pool.
 func (pool *TxPool) RemoveTransactions(txs types.Transactions) {
 	pool.mu.Lock()
@@ -514,7 +577,7 @@ func (pool *TxPool) RemoveTransactions(txs types.Transactions) {
 	batch := pool.chainDb.NewBatch()
 	for _, tx := range txs {
 		hash := tx.Hash()
-		delete(pool.pending, hash)
+		pool.untrack(hash)
 		batch.Delete(hash.Bytes())
 		hashes = append(hashes, hash)
 	}
@@ -527,7 +590,7 @@ func (pool *TxPool) RemoveTx(hash common.Hash) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 	// delete from pending pool
-	delete(pool.pending, hash)
+	pool.untrack(hash)
 	pool.chainDb.Delete(hash[:])
 	pool.relay.Discard([]common.Hash{hash})
 }
@@ -141,3 +141,69 @@ func TestTxPool(t *testing.T) {
 		}
 	}
 }
+
+func TestTxPoolNonce(t *testing.T) {
+	var (
+		sdb   = rawdb.NewMemoryDatabase()
+		ldb   = rawdb.NewMemoryDatabase()
+		gspec = core.Genesis{Alloc: core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}}}
+	)
+	gspec.MustCommit(sdb)
+	gspec.MustCommit(ldb)
+
+	odr := &testOdr{sdb: sdb, ldb: ldb, indexerConfig: TestClientIndexerConfig}
+	relay := &testTxRelay{
+		send:    make(chan int, 10),
+		discard: make(chan int, 10),
+		mined:   make(chan int, 10),
+	}
+	lightchain, _ := NewLightChain(odr, params.TestChainConfig, ethash.NewFullFaker(), nil)
+	pool := NewTxPool(params.TestChainConfig, lightchain, relay)
+	defer pool.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	transaction := func(nonce uint64, price int64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, acc1Addr, big.NewInt(10000), params.TxSmoke, big.NewInt(price), nil), types.HomesteadSigner{}, testBankKey)
+		return tx
+	}
+	checkNonce := func(want uint64) {
+		t.Helper()
+		if nonce, err := pool.GetNonce(ctx, testBankAddress); err != nil || nonce != want {
+			t.Fatalf("pending nonce mismatch: have %d (%v), want %d", nonce, err, want)
+		}
+	}
+	checkNonce(0)
+
+	// Consecutive transactions advance the nonce, a gap stops it
+	first, second := transaction(0, 100), transaction(1, 100)
+	for _, tx := range []*types.Transaction{first, second, transaction(3, 100)} {
+		if err := pool.Add(ctx, tx); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", tx.Nonce(), err)
+		}
+	}
+	checkNonce(2)
+
+	// Replacements need a price bump and discard the replaced transaction
+	if err := pool.Add(ctx, second); err == nil {
+		t.Fatalf("known transaction accepted")
+	}
+	if err := pool.Add(ctx, transaction(1, 105)); err != core.ErrReplaceUnderpriced {
+		t.Fatalf("underpriced replacement error mismatch: have %v, want %v", err, core.ErrReplaceUnderpriced)
+	}
+	if err := pool.Add(ctx, transaction(1, 110)); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	if got := <-relay.discard; got != 1 {
+		t.Fatalf("relay.Discard expected len = 1, got %d", got)
+	}
+	if pool.GetTransaction(second.Hash()) != nil {
+		t.Fatalf("replaced transaction still pending")
+	}
+	checkNonce(2)
+
+	// Dropping a transaction opens a gap to refill
+	pool.RemoveTx(first.Hash())
+	checkNonce(0)
+}
@@ -50,6 +50,13 @@ type Config struct {
 	SmokePrice  *big.Int       // Minimum smoke price for mining a transaction
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 	Noverify  bool           // Disable remote mining solution verification(only useful in ethash).
+	TxOrder   string         `toml:",omitempty"` // Order of the transactions in mined blocks ("price" or "fifo")
+	Stratum   string         `toml:",omitempty"` // Listening address of the stratum server for pool mining (empty = disabled)
+	StratumDifficulty uint64 `toml:",omitempty"` // Share difficulty of the stratum server (0 = block difficulty)
+	CoinbaseList      []common.Address `toml:",omitempty"` // Coinbases to rotate mined blocks among, overriding the fourtwentycoinbase
+	CoinbaseInterval  uint64           `toml:",omitempty"` // Number of consecutive blocks mined to each rotated coinbase (0 = 1)
+
+	TxOrdering TxOrdering `toml:"-"` // Custom transaction ordering, overriding TxOrder
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -198,6 +205,13 @@ func (miner *Miner) SetFourtwentycoinbase(addr common.Address) {
 	miner.worker.setFourtwentycoinbase(addr)
 }
 
+// SetCoinbaseList sets the coinbases to rotate mined blocks among, each receiving
+// the given number of consecutive blocks. An empty list disables the rotation,
+// mining to the fourtwentycoinbase again.
+func (miner *Miner) SetCoinbaseList(coinbases []common.Address, interval uint64) {
+	miner.worker.setCoinbaseList(coinbases, interval)
+}
+
 // EnablePreseal turns on the preseal mining feature. It's enabled by default.
 // Note this function shouldn't be exposed to API, it's unnecessary for users
 // (miners) to actually know the underlying detail. It's only for outside project
@@ -0,0 +1,158 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"container/heap"
+	"math/big"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+const (
+	TxOrderPrice = "price" // Highest paying transactions first (default)
+	TxOrderFIFO  = "fifo"  // Transactions in the order they were first seen
+)
+
+// TransactionSet is a nonce honouring sequence of pending transactions, which the
+// worker fills blocks from.
+type TransactionSet interface {
+	// Peek returns the next transaction, nil if the set is exhausted.
+	Peek() *types.Transaction
+
+	// Shift replaces the next transaction with the following one from the same
+	// account.
+	Shift()
+
+	// Pop removes the next transaction along with all the remaining ones from the
+	// same account, used when it cannot be executed.
+	Pop()
+}
+
+// TxOrdering creates the set a block is filled from out of the nonce sorted
+// pending transactions of the accounts. Local and remote transactions are always
+// ordered separately, the local ones being packed first.
+type TxOrdering func(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) TransactionSet
+
+// PriceOrdering orders the transactions by the tip they pay the miner, the
+// profit maximizing default.
+func PriceOrdering(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) TransactionSet {
+	return types.NewTransactionsByPriceAndNonce(signer, txs, baseFee)
+}
+
+// FIFOOrdering orders the transactions by the time they were first seen locally,
+// regardless of the fees they pay.
+func FIFOOrdering(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) TransactionSet {
+	return newTransactionsByTimeAndNonce(txs)
+}
+
+// txOrdering returns the transaction ordering configured for the miner. Unknown
+// ordering names fall back to the price ordering.
+func (config *Config) txOrdering() TxOrdering {
+	if config.TxOrdering != nil {
+		return config.TxOrdering
+	}
+	switch config.TxOrder {
+	case "", TxOrderPrice:
+		return PriceOrdering
+	case TxOrderFIFO:
+		return FIFOOrdering
+	default:
+		log.Warn("Sanitizing invalid miner transaction ordering", "provided", config.TxOrder, "updated", TxOrderPrice)
+		return PriceOrdering
+	}
+}
+
+// txByTime implements the heap interface, ordering the transactions by the time
+// they were first seen.
+type txByTime types.Transactions
+
+func (s txByTime) Len() int           { return len(s) }
+func (s txByTime) Less(i, j int) bool { return s[i].Time().Before(s[j].Time()) }
+func (s txByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (s *txByTime) Push(x interface{}) {
+	*s = append(*s, x.(*types.Transaction))
+}
+
+func (s *txByTime) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[0 : n-1]
+	return x
+}
+
+// transactionsByTimeAndNonce is the first seen first served counterpart of
+// types.TransactionsByPriceAndNonce.
+type transactionsByTimeAndNonce struct {
+	txs   []types.Transactions       // Remaining nonce sorted transactions of each account
+	heads txByTime                   // Next transaction of each account (time heap)
+	index map[*types.Transaction]int // Account slot of each head transaction
+}
+
+// newTransactionsByTimeAndNonce creates a transaction set retrieving the
+// transactions in the order they were first seen, in a nonce honouring way.
+func newTransactionsByTimeAndNonce(txs map[common.Address]types.Transactions) *transactionsByTimeAndNonce {
+	set := &transactionsByTimeAndNonce{
+		txs:   make([]types.Transactions, 0, len(txs)),
+		heads: make(txByTime, 0, len(txs)),
+		index: make(map[*types.Transaction]int, len(txs)),
+	}
+	for _, accTxs := range txs {
+		if len(accTxs) == 0 {
+			continue
+		}
+		set.index[accTxs[0]] = len(set.txs)
+		set.heads = append(set.heads, accTxs[0])
+		set.txs = append(set.txs, accTxs[1:])
+	}
+	heap.Init(&set.heads)
+	return set
+}
+
+// Peek returns the earliest seen executable transaction.
+func (t *transactionsByTimeAndNonce) Peek() *types.Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0]
+}
+
+// Shift replaces the current head with the next one from the same account.
+func (t *transactionsByTimeAndNonce) Shift() {
+	head := t.heads[0]
+	account := t.index[head]
+	delete(t.index, head)
+
+	if txs := t.txs[account]; len(txs) > 0 {
+		t.index[txs[0]] = account
+		t.heads[0], t.txs[account] = txs[0], txs[1:]
+		heap.Fix(&t.heads, 0)
+	} else {
+		heap.Pop(&t.heads)
+	}
+}
+
+// Pop removes the current head, *not* replacing it with the next one from the
+// same account.
+func (t *transactionsByTimeAndNonce) Pop() {
+	delete(t.index, t.heads[0])
+	heap.Pop(&t.heads)
+}
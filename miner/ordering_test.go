@@ -0,0 +1,105 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
+)
+
+// Tests that the FIFO ordering retrieves the transactions in the order they were
+// first seen, regardless of their price, while honouring the account nonces.
+func TestTransactionTimeNonceSort(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 10)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := types.HomesteadSigner{}
+
+	// Generate the transactions round robin, with decreasing prices
+	groups := map[common.Address]types.Transactions{}
+	for nonce := 0; nonce < 10; nonce++ {
+		for i, key := range keys {
+			price := big.NewInt(int64(1000 - nonce*len(keys) - i))
+			tx, _ := types.SignTx(types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(100), 100, price, nil), signer, key)
+
+			addr := crypto.PubkeyToAddress(key.PublicKey)
+			groups[addr] = append(groups[addr], tx)
+		}
+	}
+	txset := FIFOOrdering(signer, groups, nil)
+
+	var txs types.Transactions
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx)
+		txset.Shift()
+	}
+	if len(txs) != 10*10 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), 10*10)
+	}
+	nonces := make(map[common.Address]uint64)
+	for i, tx := range txs {
+		from, _ := types.Sender(signer, tx)
+		if tx.Nonce() != nonces[from] {
+			t.Errorf("tx #%d: nonce mismatch: have %d, want %d", i, tx.Nonce(), nonces[from])
+		}
+		nonces[from]++
+
+		if i > 0 && tx.Time().Before(txs[i-1].Time()) {
+			t.Errorf("tx #%d: seen before its predecessor", i)
+		}
+	}
+}
+
+// Tests that popping a transaction drops the remaining ones of its account.
+func TestTransactionTimeNoncePop(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := types.HomesteadSigner{}
+
+	groups := map[common.Address]types.Transactions{}
+	for _, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		for nonce := 0; nonce < 3; nonce++ {
+			tx, _ := types.SignTx(types.NewTransaction(uint64(nonce), common.Address{}, big.NewInt(100), 100, big.NewInt(1), nil), signer, key)
+			groups[addr] = append(groups[addr], tx)
+		}
+	}
+	txset := FIFOOrdering(signer, groups, nil)
+
+	dropped, _ := types.Sender(signer, txset.Peek())
+	txset.Pop()
+
+	count := 0
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		if from, _ := types.Sender(signer, tx); from == dropped {
+			t.Errorf("transaction %d of popped account retrieved", tx.Nonce())
+		}
+		count++
+		txset.Shift()
+	}
+	if count != 3 {
+		t.Errorf("transaction count mismatch: have %d, want %d", count, 3)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build integration
+
+package miner_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that the block and uncle rewards credited on a chain sealed by competing
+// miners follow the emission schedule through every reward era, as seen by a
+// node importing the chain from the network. The eras are compressed into a few
+// blocks each so the whole schedule is covered within seconds of fake sealing.
+//
+// The test is excluded from the default test run, use `go test -tags integration`
+// to run it.
+func TestSimulatedRewards(t *testing.T) {
+	// Compress the default reward eras, keeping their splits
+	eras := make([]params.RewardEra, len(params.DefaultRewardEras))
+	for i, era := range params.DefaultRewardEras {
+		eras[i] = era
+		eras[i].Block = big.NewInt(int64(6 * i))
+	}
+	config := *params.TestChainConfig
+	config.Ethash = &params.EthashConfig{RewardEras: eras}
+
+	// Deploy the reward recipients at the contract named in the genesis
+	var (
+		deployer  = common.Address{0xde}
+		veterans  = common.Address{0xaa}
+		followers = common.Address{0xbb}
+		contract  = ethash.RewardContract(&config, big.NewInt(1), &types.Header{Extra: deployer.Bytes()})
+		coinbases = []common.Address{{0x01}, {0x02}}
+	)
+	genesis := &core.Genesis{
+		Config:     &config,
+		Difficulty: params.MinimumDifficulty,
+		SmokeLimit: 25000000,
+		ExtraData:  deployer.Bytes(),
+		Alloc: core.GenesisAlloc{
+			contract: {
+				Nonce: 1,
+				Storage: map[common.Hash]common.Hash{
+					common.BytesToHash([]byte{1}): veterans.Hash(),
+					common.BytesToHash([]byte{2}): followers.Hash(),
+				},
+			},
+		},
+	}
+	// Seal with all but the last node, so competing blocks get included as uncles
+	network, backends := newSimulatedNetwork(t, genesis, len(coinbases)+1)
+	defer network.Shutdown()
+
+	for i, coinbase := range coinbases {
+		backends[i].SetFourtwentycoinbase(coinbase)
+		if err := backends[i].StartMining(1); err != nil {
+			t.Fatalf("miner %d: failed to start mining: %v", i, err)
+		}
+	}
+	var (
+		observer = backends[len(backends)-1]
+		target   = eras[len(eras)-1].Block.Uint64() + 6
+		deadline = time.Now().Add(2 * time.Minute)
+	)
+	for observer.BlockChain().CurrentBlock().NumberU64() < target {
+		if time.Now().After(deadline) {
+			t.Fatalf("chain not sealed in time, head #%d, want #%d", observer.BlockChain().CurrentBlock().NumberU64(), target)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	for i := range coinbases {
+		backends[i].StopMining()
+	}
+	waitSynced(t, backends[0], backends, deadline)
+
+	// Replay the rewards block by block from the era table, checking the balances
+	// after each of them
+	var (
+		chain    = observer.BlockChain()
+		head     = chain.CurrentBlock().NumberU64()
+		schedule = ethash.RewardSchedule(&config)
+		expected = make(map[common.Address]*big.Int)
+		covered  = make(map[string]bool)
+	)
+	credit := func(addr common.Address, reward *big.Int, percent uint64) {
+		if expected[addr] == nil {
+			expected[addr] = new(big.Int)
+		}
+		share := new(big.Int).Mul(reward, new(big.Int).SetUint64(percent))
+		expected[addr].Add(expected[addr], share.Div(share, big.NewInt(100)))
+	}
+	for number := uint64(1); number <= head; number++ {
+		block := chain.GetBlockByNumber(number)
+		if len(block.Transactions()) > 0 {
+			t.Fatalf("block #%d: unexpected transactions paying fees", number)
+		}
+		var era *ethash.RewardEra
+		for _, candidate := range schedule {
+			if candidate.FromBlock <= number && (candidate.ToBlock == nil || number <= *candidate.ToBlock) {
+				era = candidate
+				break
+			}
+		}
+		if era == nil {
+			t.Fatalf("block #%d: outside of the reward schedule", number)
+		}
+		pay := func(reward *big.Int, miner common.Address) {
+			credit(miner, reward, era.Miner)
+			credit(veterans, reward, era.Veterans)
+			credit(followers, reward, era.Followers)
+		}
+		// Every uncle earns (8 - depth) / 8 of the running block reward, which
+		// grows by 1/32 with each of them
+		reward := new(big.Int).Set(era.Reward)
+		for _, uncle := range block.Uncles() {
+			depth := new(big.Int).Sub(block.Number(), uncle.Number)
+			uncleReward := new(big.Int).Sub(big.NewInt(8), depth)
+			uncleReward.Mul(uncleReward, reward)
+			pay(uncleReward.Div(uncleReward, big.NewInt(8)), uncle.Coinbase)
+
+			reward.Add(reward, new(big.Int).Div(reward, big.NewInt(32)))
+		}
+		pay(reward, block.Coinbase())
+		covered[era.Name] = true
+
+		statedb, err := chain.StateAt(block.Root())
+		if err != nil {
+			t.Fatalf("block #%d: failed to retrieve state: %v", number, err)
+		}
+		for addr, want := range expected {
+			if have := statedb.GetBalance(addr); have.Cmp(want) != 0 {
+				t.Fatalf("block #%d (%s era): balance mismatch of %x: have %v, want %v", number, era.Name, addr, have, want)
+			}
+		}
+	}
+	for _, era := range eras {
+		if !covered[era.Name] {
+			t.Errorf("%s era not covered by the sealed chain", era.Name)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/consensus"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/log"
+)
+
+const (
+	// stratumMaxRequestSize is the maximum size of a single stratum request line.
+	stratumMaxRequestSize = 4096
+
+	// stratumReadTimeout is the maximum time a stratum worker may stay silent
+	// before being disconnected.
+	stratumReadTimeout = 10 * time.Minute
+
+	// stratumWriteTimeout is the maximum time allowed for sending a message to
+	// a stratum worker.
+	stratumWriteTimeout = 10 * time.Second
+)
+
+var (
+	// two256 is a big integer representing 2^256, used to convert difficulties
+	// into proof-of-work targets.
+	two256 = new(big.Int).Exp(big.NewInt(2), big.NewInt(256), big.NewInt(0))
+
+	errStratumNotLoggedIn = errors.New("not logged in")
+	errStratumNoWork      = errors.New("no mining work available yet")
+	errStratumBadParams   = errors.New("invalid parameters")
+)
+
+// shareHasher is implemented by proof-of-work engines able to hash submitted
+// work against arbitrary targets, allowing pool shares to be validated.
+type shareHasher interface {
+	SealHash(header *types.Header) common.Hash
+	Hashimoto(number uint64, sealhash common.Hash, nonce uint64) (common.Hash, common.Hash)
+}
+
+// stratumRequest is a request sent by a stratum worker. The server speaks the
+// stratum dialect of the common pool mining software, which wraps the getWork
+// methods into newline delimited JSON-RPC messages over a plain TCP connection.
+type stratumRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []string        `json:"params"`
+	Worker string          `json:"worker"`
+}
+
+// stratumError is the error returned to a stratum worker.
+type stratumError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// stratumResponse is a reply to a stratum request, or a job notification if the
+// id is zero.
+type stratumResponse struct {
+	ID      json.RawMessage `json:"id"`
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *stratumError   `json:"error,omitempty"`
+}
+
+// stratumJob is a sealing task handed out to stratum workers.
+type stratumJob struct {
+	block       *types.Block
+	work        [4]string                     // Work package sent to the workers
+	shareTarget *big.Int                      // Maximum proof-of-work value of an accepted share
+	blockTarget *big.Int                      // Maximum proof-of-work value sealing the block
+	shares      map[types.BlockNonce]struct{} // Nonces already submitted, to reject duplicates
+}
+
+// stratumServer is a stratum mining server pushing the sealing tasks of the
+// worker to connected pool miners and validating their submitted shares against
+// a share difficulty, forwarding the ones sealing a block back to the worker.
+type stratumServer struct {
+	hasher     shareHasher
+	difficulty *big.Int            // Share difficulty (nil = block difficulty)
+	results    chan<- *types.Block // Channel to deliver sealed blocks to
+
+	listener net.Listener
+	jobs     map[common.Hash]*stratumJob // Recent sealing tasks, keyed by seal hash
+	current  *stratumJob                 // Most recent sealing task
+	sessions map[*stratumSession]struct{}
+	closed   bool
+	lock     sync.Mutex
+
+	wg sync.WaitGroup
+}
+
+// stratumSession is a connection to a single stratum worker.
+type stratumSession struct {
+	conn   net.Conn
+	login  string         // Account the worker logged in with (empty = not logged in)
+	notify chan [4]string // Latest work package to push to the worker
+	closed chan struct{}
+
+	accepted uint64 // Number of accepted shares
+	rejected uint64 // Number of rejected shares
+
+	writeLock sync.Mutex
+}
+
+// startStratum creates a stratum server for the given engine and starts serving
+// workers in the background. Nil is returned if the engine can't validate shares
+// or the server couldn't be started.
+func startStratum(config *Config, engine consensus.Engine, results chan<- *types.Block) *stratumServer {
+	hasher, ok := engine.(shareHasher)
+	if !ok {
+		log.Error("Stratum mining requires an ethash engine", "addr", config.Stratum)
+		return nil
+	}
+	server, err := newStratumServer(config.Stratum, config.StratumDifficulty, hasher, results)
+	if err != nil {
+		log.Error("Failed to start stratum server", "addr", config.Stratum, "err", err)
+		return nil
+	}
+	log.Info("Stratum server started", "addr", server.listener.Addr(), "difficulty", config.StratumDifficulty)
+	return server
+}
+
+// newStratumServer creates a stratum server listening on the given address. A
+// zero share difficulty makes the server only accept shares sealing a block.
+func newStratumServer(addr string, difficulty uint64, hasher shareHasher, results chan<- *types.Block) (*stratumServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &stratumServer{
+		hasher:   hasher,
+		results:  results,
+		listener: listener,
+		jobs:     make(map[common.Hash]*stratumJob),
+		sessions: make(map[*stratumSession]struct{}),
+	}
+	if difficulty > 0 {
+		s.difficulty = new(big.Int).SetUint64(difficulty)
+	}
+	s.wg.Add(1)
+	go s.loop()
+	return s, nil
+}
+
+// close stops accepting new workers and disconnects all existing ones.
+func (s *stratumServer) close() {
+	s.listener.Close()
+
+	s.lock.Lock()
+	s.closed = true
+	for session := range s.sessions {
+		session.conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+}
+
+// loop accepts incoming stratum workers until the server is closed.
+func (s *stratumServer) loop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				log.Debug("Temporary stratum accept error", "err", err)
+				time.Sleep(time.Second)
+				continue
+			}
+			return
+		}
+		session := &stratumSession{
+			conn:   conn,
+			notify: make(chan [4]string, 1),
+			closed: make(chan struct{}),
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			conn.Close()
+			return
+		}
+		s.sessions[session] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(2)
+		go s.serve(session)
+		go s.push(session)
+	}
+}
+
+// serve reads and answers the requests of a stratum worker until the connection
+// is dropped.
+func (s *stratumServer) serve(session *stratumSession) {
+	defer s.wg.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.sessions, session)
+		s.lock.Unlock()
+
+		close(session.closed)
+		session.conn.Close()
+
+		log.Debug("Stratum worker disconnected", "addr", session.conn.RemoteAddr(), "login", session.login, "accepted", session.accepted, "rejected", session.rejected)
+	}()
+	scanner := bufio.NewScanner(session.conn)
+	scanner.Buffer(make([]byte, 0, stratumMaxRequestSize), stratumMaxRequestSize)
+
+	for {
+		session.conn.SetReadDeadline(time.Now().Add(stratumReadTimeout))
+		if !scanner.Scan() {
+			return
+		}
+		var req stratumRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			log.Debug("Invalid stratum request", "addr", session.conn.RemoteAddr(), "err", err)
+			return
+		}
+		res := &stratumResponse{ID: req.ID, Version: "2.0"}
+		if result, err := s.handle(session, &req); err != nil {
+			res.Error = &stratumError{Code: -1, Message: err.Error()}
+		} else {
+			res.Result = result
+		}
+		if err := session.send(res); err != nil {
+			return
+		}
+	}
+}
+
+// push sends new work packages to a stratum worker as they become available.
+func (s *stratumServer) push(session *stratumSession) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case work := <-session.notify:
+			if err := session.send(&stratumResponse{ID: json.RawMessage("0"), Version: "2.0", Result: work}); err != nil {
+				session.conn.Close()
+				return
+			}
+		case <-session.closed:
+			return
+		}
+	}
+}
+
+// handle executes a single stratum request.
+func (s *stratumServer) handle(session *stratumSession, req *stratumRequest) (interface{}, error) {
+	switch req.Method {
+	case "eth_submitLogin":
+		if len(req.Params) == 0 || req.Params[0] == "" {
+			return nil, errStratumBadParams
+		}
+		s.lock.Lock()
+		session.login = req.Params[0]
+		s.lock.Unlock()
+
+		log.Debug("Stratum worker logged in", "addr", session.conn.RemoteAddr(), "login", req.Params[0], "worker", req.Worker)
+		return true, nil
+
+	case "eth_getWork":
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if session.login == "" {
+			return nil, errStratumNotLoggedIn
+		}
+		if s.current == nil {
+			return nil, errStratumNoWork
+		}
+		return s.current.work, nil
+
+	case "eth_submitWork":
+		s.lock.Lock()
+		login := session.login
+		s.lock.Unlock()
+
+		if login == "" {
+			return nil, errStratumNotLoggedIn
+		}
+		if len(req.Params) != 3 {
+			return nil, errStratumBadParams
+		}
+		nonce, err := hexutil.DecodeUint64(req.Params[0])
+		if err != nil {
+			return nil, errStratumBadParams
+		}
+		sealhash, err := hexutil.Decode(req.Params[1])
+		if err != nil || len(sealhash) != common.HashLength {
+			return nil, errStratumBadParams
+		}
+		mix, err := hexutil.Decode(req.Params[2])
+		if err != nil || len(mix) != common.HashLength {
+			return nil, errStratumBadParams
+		}
+		if s.submitWork(types.EncodeNonce(nonce), common.BytesToHash(sealhash), common.BytesToHash(mix)) {
+			session.accepted++
+			return true, nil
+		}
+		session.rejected++
+		return false, nil
+
+	case "eth_submitHashrate":
+		return true, nil
+
+	default:
+		return nil, errors.New("method not found")
+	}
+}
+
+// setWork turns a new sealing task into a job and notifies all logged in stratum
+// workers about it.
+func (s *stratumServer) setWork(block *types.Block) {
+	var (
+		header      = block.Header()
+		sealhash    = s.hasher.SealHash(header)
+		blockTarget = new(big.Int).Div(two256, header.Difficulty)
+		shareTarget = blockTarget
+	)
+	if s.difficulty != nil && s.difficulty.Cmp(header.Difficulty) < 0 {
+		shareTarget = new(big.Int).Div(two256, s.difficulty)
+	}
+	job := &stratumJob{
+		block:       block,
+		shareTarget: shareTarget,
+		blockTarget: blockTarget,
+		shares:      make(map[types.BlockNonce]struct{}),
+	}
+	job.work[0] = sealhash.Hex()
+	job.work[1] = common.BytesToHash(ethash.SeedHash(block.NumberU64())).Hex()
+	job.work[2] = common.BytesToHash(shareTarget.Bytes()).Hex()
+	job.work[3] = hexutil.EncodeBig(block.Number())
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Drop the jobs too old to be accepted by the worker anymore
+	for hash, old := range s.jobs {
+		if old.block.NumberU64()+staleThreshold <= block.NumberU64() {
+			delete(s.jobs, hash)
+		}
+	}
+	s.jobs[sealhash] = job
+	s.current = job
+
+	for session := range s.sessions {
+		if session.login == "" {
+			continue
+		}
+		// Replace any work package not yet pushed, it's stale anyway
+		select {
+		case <-session.notify:
+		default:
+		}
+		session.notify <- job.work
+	}
+}
+
+// submitWork validates a share submitted by a stratum worker, delivering the
+// sealed block to the worker if it also satisfies the block difficulty.
+func (s *stratumServer) submitWork(nonce types.BlockNonce, sealhash common.Hash, mix common.Hash) bool {
+	s.lock.Lock()
+	job := s.jobs[sealhash]
+	if job == nil {
+		s.lock.Unlock()
+		log.Debug("Stratum share submitted for unknown work", "sealhash", sealhash)
+		return false
+	}
+	s.lock.Unlock()
+
+	// Verify the proof-of-work of the share outside of the lock, as it might
+	// need to generate a verification cache
+	digest, result := s.hasher.Hashimoto(job.block.NumberU64(), sealhash, nonce.Uint64())
+	if digest != mix {
+		log.Debug("Stratum share with invalid mix digest", "sealhash", sealhash, "nonce", nonce.Uint64())
+		return false
+	}
+	value := new(big.Int).SetBytes(result[:])
+	if value.Cmp(job.shareTarget) > 0 {
+		log.Debug("Stratum share below share difficulty", "sealhash", sealhash, "nonce", nonce.Uint64())
+		return false
+	}
+	// The share is valid, make sure it wasn't submitted before
+	s.lock.Lock()
+	_, known := job.shares[nonce]
+	job.shares[nonce] = struct{}{}
+	s.lock.Unlock()
+
+	if known {
+		log.Debug("Duplicate stratum share submitted", "sealhash", sealhash, "nonce", nonce.Uint64())
+		return false
+	}
+	if value.Cmp(job.blockTarget) > 0 {
+		return true
+	}
+	// The share seals the block, hand it over to the worker
+	header := job.block.Header()
+	header.Nonce = nonce
+	header.MixDigest = mix
+
+	solution := job.block.WithSeal(header)
+	select {
+	case s.results <- solution:
+		log.Info("Stratum share sealed block", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
+	default:
+		log.Warn("Sealing result is not read by miner", "mode", "stratum", "sealhash", sealhash)
+	}
+	return true
+}
+
+// send writes a message to the stratum worker.
+func (session *stratumSession) send(msg *stratumResponse) error {
+	blob, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	session.writeLock.Lock()
+	defer session.writeLock.Unlock()
+
+	session.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+	_, err = session.conn.Write(append(blob, '\n'))
+	return err
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/common/hexutil"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core/types"
+)
+
+// stratumTestClient is a minimal stratum worker used to exercise the server.
+type stratumTestClient struct {
+	t    *testing.T
+	conn net.Conn
+	dec  *json.Decoder
+	id   int
+}
+
+// call sends a stratum request and waits for its response.
+func (c *stratumTestClient) call(method string, params ...string) stratumTestResponse {
+	c.id++
+	req := fmt.Sprintf(`{"id":%d,"jsonrpc":"2.0","method":%q,"params":%s}`+"\n", c.id, method, mustJSON(params))
+	if _, err := c.conn.Write([]byte(req)); err != nil {
+		c.t.Fatalf("failed to send %s: %v", method, err)
+	}
+	res := c.read()
+	if res.ID != c.id {
+		c.t.Fatalf("%s: response id mismatch: have %d, want %d", method, res.ID, c.id)
+	}
+	return res
+}
+
+// read waits for the next message from the server.
+func (c *stratumTestClient) read() stratumTestResponse {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var res stratumTestResponse
+	if err := c.dec.Decode(&res); err != nil {
+		c.t.Fatalf("failed to read response: %v", err)
+	}
+	return res
+}
+
+type stratumTestResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+func mustJSON(v interface{}) string {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(blob)
+}
+
+// Tests that the stratum server hands out work to logged in workers, validates
+// their shares against the share difficulty and delivers the ones sealing the
+// block.
+func TestStratumShares(t *testing.T) {
+	engine := ethash.NewTester(nil, false)
+	defer engine.Close()
+
+	results := make(chan *types.Block, 1)
+	server, err := newStratumServer("127.0.0.1:0", 2, engine, results)
+	if err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+	defer server.close()
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(64),
+	})
+	server.setWork(block)
+
+	// Find a nonce only satisfying the share difficulty and one sealing the block
+	var (
+		sealhash    = engine.SealHash(block.Header())
+		shareTarget = new(big.Int).Div(two256, big.NewInt(2))
+		blockTarget = new(big.Int).Div(two256, block.Difficulty())
+
+		share, seal       uint64
+		shareMix, sealMix common.Hash
+		foundShare        bool
+		foundSeal         bool
+	)
+	for nonce := uint64(0); !foundShare || !foundSeal; nonce++ {
+		digest, result := engine.Hashimoto(block.NumberU64(), sealhash, nonce)
+		value := new(big.Int).SetBytes(result[:])
+
+		switch {
+		case value.Cmp(blockTarget) <= 0 && !foundSeal:
+			seal, sealMix, foundSeal = nonce, digest, true
+		case value.Cmp(blockTarget) > 0 && value.Cmp(shareTarget) <= 0 && !foundShare:
+			share, shareMix, foundShare = nonce, digest, true
+		}
+	}
+	conn, err := net.Dial("tcp", server.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect to stratum server: %v", err)
+	}
+	defer conn.Close()
+
+	client := &stratumTestClient{t: t, conn: conn, dec: json.NewDecoder(bufio.NewReader(conn))}
+
+	// Ensure work is only handed out after logging in
+	if res := client.call("eth_getWork"); res.Error == nil {
+		t.Fatalf("work handed out before login")
+	}
+	if res := client.call("eth_submitLogin", "0x0000000000000000000000000000000000000001"); string(res.Result) != "true" {
+		t.Fatalf("login failed: %s %v", res.Result, res.Error)
+	}
+	var work [4]string
+	if err := json.Unmarshal(client.call("eth_getWork").Result, &work); err != nil {
+		t.Fatalf("failed to decode work: %v", err)
+	}
+	if work[0] != sealhash.Hex() {
+		t.Errorf("work seal hash mismatch: have %s, want %s", work[0], sealhash.Hex())
+	}
+	if want := common.BytesToHash(shareTarget.Bytes()).Hex(); work[2] != want {
+		t.Errorf("work target mismatch: have %s, want %s", work[2], want)
+	}
+	// Submit an invalid share, a valid share, a duplicate and a sealing share
+	tests := []struct {
+		nonce  uint64
+		mix    common.Hash
+		result string
+		sealed bool
+	}{
+		{share, common.Hash{}, "false", false},
+		{share, shareMix, "true", false},
+		{share, shareMix, "false", false},
+		{seal, sealMix, "true", true},
+	}
+	for i, tt := range tests {
+		res := client.call("eth_submitWork", hexutil.EncodeUint64(tt.nonce), sealhash.Hex(), tt.mix.Hex())
+		if string(res.Result) != tt.result {
+			t.Errorf("test %d: submission result mismatch: have %s, want %s", i, res.Result, tt.result)
+		}
+		select {
+		case sealed := <-results:
+			if !tt.sealed {
+				t.Errorf("test %d: unexpected block sealed", i)
+			}
+			if sealed.Nonce() != tt.nonce || sealed.MixDigest() != tt.mix {
+				t.Errorf("test %d: sealed block mismatch: have nonce %d mix %x", i, sealed.Nonce(), sealed.MixDigest())
+			}
+		default:
+			if tt.sealed {
+				t.Errorf("test %d: block not sealed", i)
+			}
+		}
+	}
+	// Ensure new work is pushed to the logged in worker
+	next := types.NewBlockWithHeader(&types.Header{
+		Number:     big.NewInt(2),
+		Difficulty: big.NewInt(64),
+	})
+	server.setWork(next)
+
+	res := client.read()
+	if res.ID != 0 {
+		t.Fatalf("pushed work id mismatch: have %d, want 0", res.ID)
+	}
+	if err := json.Unmarshal(res.Result, &work); err != nil {
+		t.Fatalf("failed to decode pushed work: %v", err)
+	}
+	if want := engine.SealHash(next.Header()).Hex(); work[0] != want {
+		t.Errorf("pushed work seal hash mismatch: have %s, want %s", work[0], want)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/420integrated/go-420coin/420"
+	"github.com/420integrated/go-420coin/420/downloader"
+	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/consensus/ethash"
+	"github.com/420integrated/go-420coin/core"
+	"github.com/420integrated/go-420coin/core/types"
+	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/node"
+	"github.com/420integrated/go-420coin/p2p/enode"
+	"github.com/420integrated/go-420coin/p2p/simulations"
+	"github.com/420integrated/go-420coin/p2p/simulations/adapters"
+	"github.com/420integrated/go-420coin/params"
+)
+
+// Tests that a simulated network of nodes converges on the chain sealed by one
+// of them, and that the transactions submitted to the other nodes propagate to
+// the miner and get included. This is the ethash stress scenario, run in-process
+// with fake proof-of-work so it's suitable for CI.
+func TestSimulatedMining(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping network simulation in short mode")
+	}
+	// Create a batch of deterministic faucet accounts and a genesis funding them
+	faucets := make([]*ecdsa.PrivateKey, 8)
+	for i := range faucets {
+		faucets[i], _ = crypto.ToECDSA(common.LeftPadBytes([]byte{byte(i + 1)}, 32))
+	}
+	genesis := &core.Genesis{
+		Config:     params.TestChainConfig,
+		Difficulty: params.MinimumDifficulty,
+		SmokeLimit: 25000000,
+		Alloc:      core.GenesisAlloc{},
+	}
+	for _, faucet := range faucets {
+		genesis.Alloc[crypto.PubkeyToAddress(faucet.PublicKey)] = core.GenesisAccount{
+			Balance: new(big.Int).Exp(big.NewInt(2), big.NewInt(128), nil),
+		}
+	}
+	network, backends := newSimulatedNetwork(t, genesis, 4)
+	defer network.Shutdown()
+
+	// Seal with the first node only, submitting the transactions to the others
+	miner := backends[0]
+	if err := miner.StartMining(1); err != nil {
+		t.Fatalf("failed to start mining: %v", err)
+	}
+	signer := types.NewEIP155Signer(genesis.Config.ChainID)
+	for i, faucet := range faucets {
+		tx, err := types.SignTx(types.NewTransaction(0, crypto.PubkeyToAddress(faucet.PublicKey), new(big.Int), params.TxSmoke, big.NewInt(1), nil), signer, faucet)
+		if err != nil {
+			t.Fatalf("failed to sign transaction %d: %v", i, err)
+		}
+		if err := backends[1+i%(len(backends)-1)].TxPool().AddLocal(tx); err != nil {
+			t.Fatalf("failed to submit transaction %d: %v", i, err)
+		}
+	}
+	// Wait until all the transactions are mined, then until all nodes agree on the head
+	deadline := time.Now().Add(time.Minute)
+	for !included(t, miner, faucets) {
+		if time.Now().After(deadline) {
+			t.Fatalf("transactions not mined, head #%d", miner.BlockChain().CurrentBlock().NumberU64())
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	miner.StopMining()
+	waitSynced(t, miner, backends, deadline)
+}
+
+// included checks whether the transaction of every faucet is part of the chain
+// of the given node.
+func included(t *testing.T, backend *fourtwenty.Fourtwentycoin, faucets []*ecdsa.PrivateKey) bool {
+	statedb, err := backend.BlockChain().State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	for _, faucet := range faucets {
+		if statedb.GetNonce(crypto.PubkeyToAddress(faucet.PublicKey)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// newSimulatedNetwork starts a fully connected network of in-memory nodes running
+// a 420coin service each on top of the given genesis, sealing with fake proof of
+// work. The backends are returned in the order the nodes were created.
+func newSimulatedNetwork(t *testing.T, genesis *core.Genesis, nodes int) (*simulations.Network, []*fourtwenty.Fourtwentycoin) {
+	t.Helper()
+
+	var (
+		lock     sync.Mutex
+		services = make(map[enode.ID]*fourtwenty.Fourtwentycoin)
+	)
+	adapter := adapters.NewSimAdapter(adapters.LifecycleConstructors{
+		"fourtwenty": func(ctx *adapters.ServiceContext, stack *node.Node) (node.Lifecycle, error) {
+			config := fourtwenty.DefaultConfig
+			config.Genesis = genesis
+			config.NetworkId = 420
+			config.SyncMode = downloader.FullSync
+			config.Ethash.PowMode = ethash.ModeFake
+			config.Miner.Fourtwentycoinbase = crypto.PubkeyToAddress(ctx.Config.PrivateKey.PublicKey)
+			config.Miner.SmokePrice = big.NewInt(1)
+			config.Miner.Recommit = time.Second
+
+			backend, err := fourtwenty.New(stack, &config)
+			if err != nil {
+				return nil, err
+			}
+			lock.Lock()
+			services[ctx.Config.ID] = backend
+			lock.Unlock()
+			return backend, nil
+		},
+	})
+	network := simulations.NewNetwork(adapter, &simulations.NetworkConfig{DefaultService: "fourtwenty"})
+
+	ids := make([]enode.ID, nodes)
+	for i := range ids {
+		config := adapters.RandomNodeConfig()
+		config.Lifecycles = []string{"fourtwenty"}
+
+		simNode, err := network.NewNodeWithConfig(config)
+		if err != nil {
+			network.Shutdown()
+			t.Fatalf("failed to create node %d: %v", i, err)
+		}
+		ids[i] = simNode.ID()
+	}
+	if err := network.StartAll(); err != nil {
+		network.Shutdown()
+		t.Fatalf("failed to start nodes: %v", err)
+	}
+	if err := network.ConnectNodesFull(ids); err != nil {
+		network.Shutdown()
+		t.Fatalf("failed to connect nodes: %v", err)
+	}
+	backends := make([]*fourtwenty.Fourtwentycoin, nodes)
+	for i, id := range ids {
+		backends[i] = services[id]
+	}
+	return network, backends
+}
+
+// waitSynced waits until all the backends have the same head block as the miner.
+// A block sealed right before mining stopped may still be propagating, so the
+// current head of the miner is always compared against.
+func waitSynced(t *testing.T, miner *fourtwenty.Fourtwentycoin, backends []*fourtwenty.Fourtwentycoin, deadline time.Time) {
+	t.Helper()
+
+	for i, backend := range backends {
+		for {
+			head, have := miner.BlockChain().CurrentBlock(), backend.BlockChain().CurrentBlock()
+			if have.Hash() == head.Hash() {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d head mismatch: have #%d, want #%d", i, have.NumberU64(), head.NumberU64())
+			}
+			time.Sleep(250 * time.Millisecond)
+		}
+	}
+}
@@ -124,6 +124,7 @@ type intervalAdjust struct {
 // and gathering the sealing result.
 type worker struct {
 	config      *Config
+	txOrdering  TxOrdering
 	chainConfig *params.ChainConfig
 	engine      consensus.Engine
 	fourtwenty         Backend
@@ -154,10 +155,13 @@ type worker struct {
 	localUncles  map[common.Hash]*types.Block // A set of side blocks generated locally as the possible uncle blocks.
 	remoteUncles map[common.Hash]*types.Block // A set of side blocks as the possible uncle blocks.
 	unconfirmed  *unconfirmedBlocks           // A set of locally mined blocks pending canonicalness confirmations.
+	stratum      *stratumServer               // Stratum server for pool mining (nil = disabled)
 
-	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
-	coinbase common.Address
-	extra    []byte
+	mu               sync.RWMutex // The lock used to protect the coinbase and extra fields
+	coinbase         common.Address
+	coinbases        []common.Address // Coinbase rotation list, overriding coinbase if non-empty
+	coinbaseInterval uint64           // Number of consecutive blocks mined to each rotated coinbase
+	extra            []byte
 
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
@@ -190,6 +194,7 @@ type worker struct {
 func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, fourtwenty Backend, mux *event.TypeMux, isLocalBlock func(*types.Block) bool, init bool) *worker {
 	worker := &worker{
 		config:             config,
+		txOrdering:         config.txOrdering(),
 		chainConfig:        chainConfig,
 		engine:             engine,
 		fourtwenty:         fourtwenty,
@@ -211,12 +216,18 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		resubmitIntervalCh: make(chan time.Duration),
 		resubmitAdjustCh:   make(chan *intervalAdjust, resubmitAdjustChanSize),
 	}
+	worker.setCoinbaseList(config.CoinbaseList, config.CoinbaseInterval)
+
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = fourtwenty.TxPool().SubscribeNewTxsEvent(worker.txsCh)
 	// Subscribe events for blockchain
 	worker.chainHeadSub = fourtwenty.BlockChain().SubscribeChainHeadEvent(worker.chainHeadCh)
 	worker.chainSideSub = fourtwenty.BlockChain().SubscribeChainSideEvent(worker.chainSideCh)
 
+	// Start the stratum server if pool mining was requested
+	if config.Stratum != "" {
+		worker.stratum = startStratum(config, engine, worker.resultCh)
+	}
 	// Sanitize recommit interval if the user-specified one is too short.
 	recommit := worker.config.Recommit
 	if recommit < minRecommitInterval {
@@ -243,6 +254,30 @@ func (w *worker) setFourtwentycoinbase(addr common.Address) {
 	w.coinbase = addr
 }
 
+// setCoinbaseList sets the coinbases to rotate the mined blocks among, switching
+// to the next one every interval blocks.
+func (w *worker) setCoinbaseList(coinbases []common.Address, interval uint64) {
+	if interval == 0 {
+		interval = 1
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.coinbases = append([]common.Address(nil), coinbases...)
+	w.coinbaseInterval = interval
+}
+
+// blockCoinbase returns the coinbase of the block with the given number, taken
+// from the rotation list if one is set.
+func (w *worker) blockCoinbase(number uint64) common.Address {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.coinbases) == 0 {
+		return w.coinbase
+	}
+	return w.coinbases[(number/w.coinbaseInterval)%uint64(len(w.coinbases))]
+}
+
 // setExtra sets the content used to initialize the block extra field.
 func (w *worker) setExtra(extra []byte) {
 	w.mu.Lock()
@@ -305,6 +340,9 @@ func (w *worker) isRunning() bool {
 func (w *worker) close() {
 	atomic.StoreInt32(&w.running, 0)
 	close(w.exitCh)
+	if w.stratum != nil {
+		w.stratum.close()
+	}
 }
 
 // recalcRecommit recalculates the resubmitting interval upon feedback.
@@ -487,16 +525,14 @@ func (w *worker) mainLoop() {
 				if gp := w.current.smokePool; gp != nil && gp.Smoke() < params.TxSmoke {
 					continue
 				}
-				w.mu.RLock()
-				coinbase := w.coinbase
-				w.mu.RUnlock()
+				coinbase := w.blockCoinbase(w.current.header.Number.Uint64())
 
 				txs := make(map[common.Address]types.Transactions)
 				for _, tx := range ev.Txs {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs)
+				txset := w.txOrdering(w.current.signer, txs, w.current.header.BaseFee)
 				tcount := w.current.tcount
 				w.commitTransactions(txset, coinbase, nil)
 				// Only update the snapshot if any new transactons were added
@@ -564,6 +600,10 @@ func (w *worker) taskLoop() {
 			w.pendingTasks[sealHash] = task
 			w.pendingMu.Unlock()
 
+			if w.stratum != nil {
+				w.stratum.setWork(task.block)
+			}
+
 			if err := w.engine.Seal(w.chain, task.block, w.resultCh, stopCh); err != nil {
 				log.Warn("Block sealing failed", "err", err)
 			}
@@ -737,7 +777,7 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coinbase common.Address, interrupt *int32) bool {
+func (w *worker) commitTransactions(txs TransactionSet, coinbase common.Address, interrupt *int32) bool {
 	// Short circuit if current is nil
 	if w.current == nil {
 		return true
@@ -808,6 +848,11 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			log.Trace("Skipping transaction with low nonce", "sender", from, "nonce", tx.Nonce())
 			txs.Shift()
 
+		case errors.Is(err, core.ErrFeeCapTooLow):
+			// The transaction doesn't pay the base fee, neither will the next ones of the account
+			log.Trace("Skipping account with fee cap below base fee", "sender", from, "feeCap", tx.SmokeFeeCap(), "baseFee", w.current.header.BaseFee)
+			txs.Pop()
+
 		case errors.Is(err, core.ErrNonceTooHigh):
 			// Reorg notification data race between the transaction pool and miner, skip account =
 			log.Trace("Skipping account with hight nonce", "sender", from, "nonce", tx.Nonce())
@@ -876,13 +921,22 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		Extra:      w.extra,
 		Time:       uint64(timestamp),
 	}
+	// Set the base fee once the fee market is active, doubling the smoke limit at
+	// the fork block so the previous limit becomes the smoke target
+	if w.chainConfig.IsSmokeBaseFee(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(w.chainConfig, parent.Header())
+		if !w.chainConfig.IsSmokeBaseFee(parent.Number()) {
+			header.SmokeLimit = parent.SmokeLimit() * params.ElasticityMultiplier
+		}
+	}
 	// Only set the coinbase if our consensus engine is running (avoid spurious block rewards)
+	coinbase := w.blockCoinbase(header.Number.Uint64())
 	if w.isRunning() {
-		if w.coinbase == (common.Address{}) {
+		if coinbase == (common.Address{}) {
 			log.Error("Refusing to mine without fourtwentycoinbase")
 			return
 		}
-		header.Coinbase = w.coinbase
+		header.Coinbase = coinbase
 	}
 	if err := w.engine.Prepare(w.chain, header); err != nil {
 		log.Error("Failed to prepare header for mining", "err", err)
@@ -965,14 +1019,14 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		}
 	}
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
-		if w.commitTransactions(txs, w.coinbase, interrupt) {
+		txs := w.txOrdering(w.current.signer, localTxs, w.current.header.BaseFee)
+		if w.commitTransactions(txs, coinbase, interrupt) {
 			return
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
-		if w.commitTransactions(txs, w.coinbase, interrupt) {
+		txs := w.txOrdering(w.current.signer, remoteTxs, w.current.header.BaseFee)
+		if w.commitTransactions(txs, coinbase, interrupt) {
 			return
 		}
 	}
@@ -501,3 +501,36 @@ func testAdjustInterval(t *testing.T, chainConfig *params.ChainConfig, engine co
 		t.Error("interval reset timeout")
 	}
 }
+
+func TestCoinbaseRotation(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+	defer b.chain.Stop()
+
+	var (
+		first  = common.HexToAddress("0x01")
+		second = common.HexToAddress("0x02")
+	)
+	tests := []struct {
+		coinbases []common.Address
+		interval  uint64
+		want      []common.Address // coinbases of blocks 0..5
+	}{
+		{nil, 0, []common.Address{testBankAddress, testBankAddress, testBankAddress, testBankAddress, testBankAddress, testBankAddress}},
+		{[]common.Address{first, second}, 0, []common.Address{first, second, first, second, first, second}},
+		{[]common.Address{first, second}, 1, []common.Address{first, second, first, second, first, second}},
+		{[]common.Address{first, second}, 2, []common.Address{first, first, second, second, first, first}},
+		{[]common.Address{first, second, testBankAddress}, 3, []common.Address{first, first, first, second, second, second}},
+	}
+	for i, tt := range tests {
+		w.setCoinbaseList(tt.coinbases, tt.interval)
+		for number, want := range tt.want {
+			if have := w.blockCoinbase(uint64(number)); have != want {
+				t.Errorf("test %d, block %d: coinbase mismatch: have %x, want %x", i, number, have, want)
+			}
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/420integrated/go-420coin/accounts/keystore"
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/crypto"
+	"github.com/tyler-smith/go-bip39"
 )
 
 const (
@@ -210,6 +211,31 @@ func (ks *KeyStore) ImportECDSAKey(key []byte, passphrase string) (account *Acco
 	return &Account{acc}, nil
 }
 
+// ImportMnemonic derives the key at the given BIP-32 derivation path (e.g.
+// "m/44'/60'/0'/0/0") from a BIP-39 mnemonic and its optional seed password, and
+// stores it into the key directory, encrypting it with the passphrase.
+func (ks *KeyStore) ImportMnemonic(mnemonic, seedPassword, path, passphrase string) (account *Account, _ error) {
+	derivPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := ks.keystore.ImportMnemonic(mnemonic, seedPassword, derivPath, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{acc}, nil
+}
+
+// NewMnemonic generates a new BIP-39 mnemonic from the given number of entropy
+// bits, which must be between 128 and 256 and a multiple of 32.
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
 // ImportPreSaleKey decrypts the given 420coin presale wallet and stores
 // a key file in the key directory. The key file is encrypted with the same passphrase.
 func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (ccount *Account, _ error) {
@@ -219,3 +245,20 @@ func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (ccount
 	}
 	return &Account{account}, nil
 }
+
+// HashMessage returns the hash signed by the personal message signing methods for
+// the given message, keccak256("\x19420coin Signed Message:\n"${message length}${message}).
+func HashMessage(message []byte) []byte {
+	return accounts.TextHash(message)
+}
+
+// EcRecover returns the address of the account which signed the given message
+// with the personal message prefix (see HashMessage). The V value of the signature
+// may be either 27/28 or 0/1.
+func EcRecover(message []byte, signature []byte) (address *Address, _ error) {
+	addr, err := accounts.TextRecover(message, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{addr}, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/420integrated/go-420coin/internal/debug"
 	"github.com/420integrated/go-420coin/p2p"
 	"github.com/420integrated/go-420coin/p2p/enode"
+	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rpc"
 )
 
@@ -184,6 +185,7 @@ func (api *privateAdminAPI) StartRPC(host *string, port *int, cors *string, apis
 		CorsAllowedOrigins: api.node.config.HTTPCors,
 		Vhosts:             api.node.config.HTTPVirtualHosts,
 		Modules:            api.node.config.HTTPModules,
+		APIKeys:            api.node.config.APIKeys,
 	}
 	if cors != nil {
 		config.CorsAllowedOrigins = nil
@@ -243,6 +245,7 @@ func (api *privateAdminAPI) StartWS(host *string, port *int, allowedOrigins *str
 	config := wsConfig{
 		Modules: api.node.config.WSModules,
 		Origins: api.node.config.WSOrigins,
+		APIKeys: api.node.config.APIKeys,
 		// ExposeAll: api.node.config.WSExposeAll,
 	}
 	if apis != nil {
@@ -303,7 +306,9 @@ func (api *publicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
 	if server == nil {
 		return nil, ErrNodeStopped
 	}
-	return server.NodeInfo(), nil
+	info := server.NodeInfo()
+	info.Build = api.node.config.BuildInfo
+	return info, nil
 }
 
 // Datadir retrieves the current data directory the node is using.
@@ -321,6 +326,11 @@ func (s *publicWeb3API) ClientVersion() string {
 	return s.stack.Server().Name
 }
 
+// ClientBuildInfo returns the provenance metadata of the running binary.
+func (s *publicWeb3API) ClientBuildInfo() *params.BuildInfo {
+	return s.stack.config.BuildInfo
+}
+
 // Sha3 applies the 420coin sha3 implementation on the input.
 // It assumes the input is hex encoded.
 func (s *publicWeb3API) Sha3(input hexutil.Bytes) hexutil.Bytes {
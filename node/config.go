@@ -36,6 +36,7 @@ import (
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/p2p"
 	"github.com/420integrated/go-420coin/p2p/enode"
+	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rpc"
 )
 
@@ -63,6 +64,11 @@ type Config struct {
 	// in the devp2p node identifier.
 	Version string `toml:"-"`
 
+	// BuildInfo contains the provenance metadata of the running binary. It is
+	// reported over RPC to allow verifying builds across a fleet of nodes. If
+	// unset, only the properties of the Go runtime are reported.
+	BuildInfo *params.BuildInfo `toml:"-"`
+
 	// DataDir is the file system folder the node should use for any data storage
 	// requirements. The configured data directory will not be directly shared with
 	// registered services, instead those can use utility methods to create/access
@@ -104,6 +110,12 @@ type Config struct {
 	// relative), then that specific path is enforced. An empty path disables IPC.
 	IPCPath string
 
+	// IPCAllow is the list of Windows users and groups allowed to connect to the
+	// IPC named pipe besides the account running the node. If empty, the default
+	// named pipe security applies. It has no effect on Unix sockets, which are only
+	// accessible by their owner.
+	IPCAllow []string `toml:",omitempty"`
+
 	// HTTPHost is the host interface on which to start the HTTP RPC server. If this
 	// field is empty, no HTTP API endpoint will be started.
 	HTTPHost string
@@ -162,6 +174,11 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// APIKeys restricts the HTTP and WebSocket RPC endpoints to the listed API keys,
+	// each of which may only access its own modules within its own quotas. If the
+	// list is empty, the endpoints are open to anyone.
+	APIKeys []APIKeyConfig `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -184,6 +201,15 @@ type Config struct {
 	oldG420ResourceWarning bool
 }
 
+// APIKeyConfig is a tenant of the HTTP and WebSocket RPC endpoints, identified by
+// the key it sends in the X-API-Key request header.
+type APIKeyConfig struct {
+	Key              string   // Secret sent by the tenant's clients
+	Modules          []string // API modules the tenant may access
+	RateLimit        float64  `toml:",omitempty"` // Calls per second per endpoint, batch items counted separately (0 = unlimited)
+	MaxSubscriptions int      `toml:",omitempty"` // Concurrent subscriptions, websocket only as HTTP can't subscribe (0 = unlimited)
+}
+
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
 // account the set data folders as well as the designated platform we're currently
 // running on.
@@ -32,6 +32,7 @@ import (
 	"github.com/420integrated/go-420coin/event"
 	"github.com/420integrated/go-420coin/log"
 	"github.com/420integrated/go-420coin/p2p"
+	"github.com/420integrated/go-420coin/params"
 	"github.com/420integrated/go-420coin/rpc"
 	"github.com/prometheus/tsdb/fileutil"
 )
@@ -82,6 +83,9 @@ func New(conf *Config) (*Node, error) {
 	if conf.Logger == nil {
 		conf.Logger = log.New()
 	}
+	if conf.BuildInfo == nil {
+		conf.BuildInfo = params.NewBuildInfo("", "", "", "")
+	}
 
 	// Ensure that the instance name doesn't cause weird conflicts with
 	// other files in the data directory.
@@ -138,7 +142,7 @@ func New(conf *Config) (*Node, error) {
 	// Configure RPC servers.
 	node.http = newHTTPServer(node.log, conf.HTTPTimeouts)
 	node.ws = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
-	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
+	node.ipc = newIPCServer(node.log, conf.IPCEndpoint(), conf.IPCAllow)
 
 	return node, nil
 }
@@ -343,6 +347,7 @@ func (n *Node) startRPC() error {
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
+			APIKeys:            n.config.APIKeys,
 		}
 		if err := n.http.setListenAddr(n.config.HTTPHost, n.config.HTTPPort); err != nil {
 			return err
@@ -358,6 +363,7 @@ func (n *Node) startRPC() error {
 		config := wsConfig{
 			Modules: n.config.WSModules,
 			Origins: n.config.WSOrigins,
+			APIKeys: n.config.APIKeys,
 		}
 		if err := server.setListenAddr(n.config.WSHost, n.config.WSPort); err != nil {
 			return err
@@ -39,17 +39,28 @@ type httpConfig struct {
 	Modules            []string
 	CorsAllowedOrigins []string
 	Vhosts             []string
+	APIKeys            []APIKeyConfig
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
 	Origins []string
 	Modules []string
+	APIKeys []APIKeyConfig
 }
 
 type rpcHandler struct {
 	http.Handler
 	server *rpc.Server
+	keys   *apiKeyHandler // nil if the endpoint isn't restricted to API keys
+}
+
+// stop shuts down the RPC server and the servers of all API keys.
+func (h *rpcHandler) stop() {
+	h.server.Stop()
+	if h.keys != nil {
+		h.keys.stop()
+	}
 }
 
 type httpServer struct {
@@ -209,11 +220,11 @@ func (h *httpServer) doStop() {
 	wsHandler := h.httpHandler.Load().(*rpcHandler)
 	if httpHandler != nil {
 		h.httpHandler.Store((*rpcHandler)(nil))
-		httpHandler.server.Stop()
+		httpHandler.stop()
 	}
 	if wsHandler != nil {
 		h.wsHandler.Store((*rpcHandler)(nil))
-		wsHandler.server.Stop()
+		wsHandler.stop()
 	}
 	h.server.Shutdown(context.Background())
 	h.listener.Close()
@@ -238,11 +249,20 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	handler := &rpcHandler{server: srv}
+	if len(config.APIKeys) > 0 {
+		keys, err := newAPIKeyHandler(apis, config.APIKeys, func(srv *rpc.Server) http.Handler { return srv })
+		if err != nil {
+			srv.Stop()
+			return err
+		}
+		handler.keys = keys
+		handler.Handler = NewHTTPHandlerStack(keys, config.CorsAllowedOrigins, config.Vhosts)
+	} else {
+		handler.Handler = NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts)
+	}
 	h.httpConfig = config
-	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts),
-		server:  srv,
-	})
+	h.httpHandler.Store(handler)
 	return nil
 }
 
@@ -251,7 +271,7 @@ func (h *httpServer) disableRPC() bool {
 	handler := h.httpHandler.Load().(*rpcHandler)
 	if handler != nil {
 		h.httpHandler.Store((*rpcHandler)(nil))
-		handler.server.Stop()
+		handler.stop()
 	}
 	return handler != nil
 }
@@ -270,11 +290,21 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if err := RegisterApisFromWhitelist(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
+	handler := &rpcHandler{server: srv}
+	if len(config.APIKeys) > 0 {
+		keys, err := newAPIKeyHandler(apis, config.APIKeys, func(srv *rpc.Server) http.Handler {
+			return srv.WebsocketHandler(config.Origins)
+		})
+		if err != nil {
+			srv.Stop()
+			return err
+		}
+		handler.Handler, handler.keys = keys, keys
+	} else {
+		handler.Handler = srv.WebsocketHandler(config.Origins)
+	}
 	h.wsConfig = config
-	h.wsHandler.Store(&rpcHandler{
-		Handler: srv.WebsocketHandler(config.Origins),
-		server:  srv,
-	})
+	h.wsHandler.Store(handler)
 	return nil
 }
 
@@ -295,7 +325,7 @@ func (h *httpServer) disableWS() bool {
 	ws := h.wsHandler.Load().(*rpcHandler)
 	if ws != nil {
 		h.wsHandler.Store((*rpcHandler)(nil))
-		ws.server.Stop()
+		ws.stop()
 	}
 	return ws != nil
 }
@@ -385,6 +415,67 @@ func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "invalid host specified", http.StatusForbidden)
 }
 
+// apiKeyHeader is the request header carrying the API key of a tenant.
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyHandler is a handler which serves each API key from a dedicated RPC server
+// exposing only the modules allowed for the key. The servers enforce the request
+// quota of their key on every call, not just on every HTTP request, so batches
+// and websocket connections can't be used to sidestep it.
+type apiKeyHandler struct {
+	tenants map[string]*apiKeyTenant
+}
+
+// apiKeyTenant is the RPC server of a single API key.
+type apiKeyTenant struct {
+	handler http.Handler
+	server  *rpc.Server
+}
+
+// newAPIKeyHandler creates an RPC server for every configured API key and wraps
+// it into a transport specific handler.
+func newAPIKeyHandler(apis []rpc.API, keys []APIKeyConfig, wrap func(*rpc.Server) http.Handler) (*apiKeyHandler, error) {
+	h := &apiKeyHandler{tenants: make(map[string]*apiKeyTenant)}
+	for _, key := range keys {
+		if key.Key == "" {
+			h.stop()
+			return nil, fmt.Errorf("empty API key")
+		}
+		if _, exist := h.tenants[key.Key]; exist {
+			h.stop()
+			return nil, fmt.Errorf("duplicate API key")
+		}
+		srv := rpc.NewServer()
+		if err := RegisterApisFromWhitelist(apis, key.Modules, srv, false); err != nil {
+			srv.Stop()
+			h.stop()
+			return nil, err
+		}
+		srv.SetRateLimit(key.RateLimit)
+		srv.SetSubscriptionLimit(key.MaxSubscriptions)
+
+		h.tenants[key.Key] = &apiKeyTenant{handler: wrap(srv), server: srv}
+	}
+	return h, nil
+}
+
+// ServeHTTP serves JSON-RPC requests of known API keys.
+func (h *apiKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenant := h.tenants[r.Header.Get(apiKeyHeader)]
+	if tenant == nil {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+	tenant.handler.ServeHTTP(w, r)
+}
+
+// stop shuts down the RPC servers of all API keys.
+func (h *apiKeyHandler) stop() {
+	for _, tenant := range h.tenants {
+		tenant.server.Stop()
+	}
+}
+
 var gzPool = sync.Pool{
 	New: func() interface{} {
 		w := gzip.NewWriter(ioutil.Discard)
@@ -428,14 +519,15 @@ func newGzipHandler(next http.Handler) http.Handler {
 type ipcServer struct {
 	log      log.Logger
 	endpoint string
+	allow    []string // Users and groups allowed to access the endpoint
 
 	mu       sync.Mutex
 	listener net.Listener
 	srv      *rpc.Server
 }
 
-func newIPCServer(log log.Logger, endpoint string) *ipcServer {
-	return &ipcServer{log: log, endpoint: endpoint}
+func newIPCServer(log log.Logger, endpoint string, allow []string) *ipcServer {
+	return &ipcServer{log: log, endpoint: endpoint, allow: allow}
 }
 
 // Start starts the httpServer's http.Server
@@ -446,7 +538,7 @@ func (is *ipcServer) start(apis []rpc.API) error {
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	listener, srv, err := rpc.StartRestrictedIPCEndpoint(is.endpoint, is.allow, apis)
 	if err != nil {
 		is.log.Warn("IPC opening failed", "url", is.endpoint, "error", err)
 		return err
@@ -483,7 +575,7 @@ func RegisterApisFromWhitelist(apis []rpc.API, modules []string, srv *rpc.Server
 	}
 	// Register all the APIs exposed by the services
 	for _, api := range apis {
-		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
+		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) || aliasWhitelisted(api, whitelist) {
 			if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
 				return err
 			}
@@ -491,3 +583,18 @@ func RegisterApisFromWhitelist(apis []rpc.API, modules []string, srv *rpc.Server
 	}
 	return nil
 }
+
+// aliasWhitelisted reports whether the API is an alias of a whitelisted module.
+// Aliases are exposed along with the modules they alias.
+func aliasWhitelisted(api rpc.API, whitelist map[string]bool) bool {
+	alias, ok := api.Service.(*rpc.Alias)
+	if !ok {
+		return false
+	}
+	for _, target := range alias.Targets {
+		if whitelist[target] {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,9 @@ package node
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"testing"
@@ -53,6 +56,79 @@ func TestVhosts(t *testing.T) {
 	assert.Equal(t, resp2.StatusCode, http.StatusForbidden)
 }
 
+// TestAPIKeys makes sure API keys and their quotas are enforced on the http server.
+func TestAPIKeys(t *testing.T) {
+	keys := []APIKeyConfig{{Key: "alpha"}, {Key: "beta", RateLimit: 1}}
+	srv := createAndStartServer(t, httpConfig{APIKeys: keys}, false, wsConfig{})
+	defer srv.stop()
+
+	resp := testRequest(t, "", "", "", srv)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = testRequest(t, apiKeyHeader, "gamma", "", srv)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	for i := 0; i < 3; i++ {
+		resp = testRequest(t, apiKeyHeader, "alpha", "", srv)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	// The quota must apply to every call, batched ones included
+	call := `{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`
+	assert.Equal(t, []int{0, -32005}, testAPIKeyRequest(t, srv, "beta", "["+call+","+call+"]"))
+	assert.Equal(t, []int{-32005}, testAPIKeyRequest(t, srv, "beta", call))
+}
+
+// TestAPIKeysWebsocket makes sure the quotas of API keys are enforced on every
+// message of a websocket connection, not only on the upgrade request, and that
+// subscriptions are only available over websocket.
+func TestAPIKeysWebsocket(t *testing.T) {
+	var (
+		apis = []rpc.API{{Namespace: "test", Version: "1.0", Service: new(testSubscriptionService), Public: true}}
+		keys = []APIKeyConfig{
+			{Key: "beta", Modules: []string{"test"}, RateLimit: 1},
+			{Key: "delta", Modules: []string{"test"}, MaxSubscriptions: 1},
+		}
+	)
+	srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+	assert.NoError(t, srv.enableRPC(apis, httpConfig{APIKeys: keys}))
+	assert.NoError(t, srv.enableWS(apis, wsConfig{APIKeys: keys}))
+	assert.NoError(t, srv.setListenAddr("localhost", 0))
+	assert.NoError(t, srv.start())
+	defer srv.stop()
+
+	_, _, err := websocket.DefaultDialer.Dial("ws://"+srv.listenAddr(), http.Header{apiKeyHeader: []string{"gamma"}})
+	assert.Error(t, err)
+
+	// Calls beyond the quota must be rejected on an established connection
+	call := `{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`
+
+	conn := testAPIKeyDial(t, srv, "beta")
+	defer conn.Close()
+	assert.Equal(t, 0, testWebsocketCall(t, conn, call))
+	assert.Equal(t, -32005, testWebsocketCall(t, conn, call))
+
+	// Subscriptions beyond the limit must be rejected, and refused over HTTP
+	subscribe := `{"jsonrpc":"2.0","id":1,"method":"test_subscribe","params":["ticks"]}`
+
+	conn = testAPIKeyDial(t, srv, "delta")
+	defer conn.Close()
+	assert.Equal(t, 0, testWebsocketCall(t, conn, subscribe))
+	assert.NotEqual(t, 0, testWebsocketCall(t, conn, subscribe))
+	assert.NotEqual(t, []int{0}, testAPIKeyRequest(t, srv, "delta", subscribe))
+}
+
+// testSubscriptionService is an RPC service with a subscription which stays
+// active until it's unsubscribed.
+type testSubscriptionService struct{}
+
+func (s *testSubscriptionService) Ticks(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	return notifier.CreateSubscription(), nil
+}
+
 type originTest struct {
 	spec    string
 	expOk   []string
@@ -214,3 +290,77 @@ func testRequest(t *testing.T, key, value, host string, srv *httpServer) *http.R
 	}
 	return resp
 }
+
+// testAPIKeyRequest sends a JSON-RPC request or batch with the given API key over
+// HTTP and returns the error codes of the responses, zero for successful ones.
+func testAPIKeyRequest(t *testing.T, srv *httpServer, apiKey, body string) []int {
+	t.Helper()
+
+	req, _ := http.NewRequest("POST", "http://"+srv.listenAddr(), strings.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(apiKeyHeader, apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	blob, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(body, "[") {
+		blob = append(append([]byte("["), blob...), ']')
+	}
+	var msgs []testRPCResponse
+	if err := json.Unmarshal(blob, &msgs); err != nil {
+		t.Fatalf("invalid response %q: %v", blob, err)
+	}
+	codes := make([]int, len(msgs))
+	for i, msg := range msgs {
+		codes[i] = msg.code()
+	}
+	return codes
+}
+
+// testAPIKeyDial opens a websocket connection to the server with the given API key.
+func testAPIKeyDial(t *testing.T, srv *httpServer, apiKey string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+srv.listenAddr(), http.Header{apiKeyHeader: []string{apiKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// testWebsocketCall sends a JSON-RPC request over the websocket connection and
+// returns the error code of the response, zero if it succeeded.
+func testWebsocketCall(t *testing.T, conn *websocket.Conn, call string) int {
+	t.Helper()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(call)); err != nil {
+		t.Fatal(err)
+	}
+	var msg testRPCResponse
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg.code()
+}
+
+// testRPCResponse is the part of a JSON-RPC response the API key tests check.
+type testRPCResponse struct {
+	Error *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// code returns the error code of the response, zero if it succeeded.
+func (msg testRPCResponse) code() int {
+	if msg.Error == nil {
+		return 0
+	}
+	return msg.Error.Code
+}
@@ -35,8 +35,33 @@ var (
 	egressConnectMeter  = metrics.NewRegisteredMeter("p2p/dials", nil)
 	egressTrafficMeter  = metrics.NewRegisteredMeter(egressMeterName, nil)
 	activePeerGauge     = metrics.NewRegisteredGauge("p2p/peers", nil)
+
+	snappyNegotiatedMeter = metrics.NewRegisteredMeter("p2p/compression/negotiated", nil)
+	snappyDeclinedMeter   = metrics.NewRegisteredMeter("p2p/compression/declined", nil)
+
+	ingressPlainMeter = metrics.NewRegisteredMeter("p2p/compression/ingress/plain", nil)
+	ingressWireMeter  = metrics.NewRegisteredMeter("p2p/compression/ingress/wire", nil)
+	ingressRatioGauge = metrics.NewRegisteredGauge("p2p/compression/ingress/ratio", nil)
+	egressPlainMeter  = metrics.NewRegisteredMeter("p2p/compression/egress/plain", nil)
+	egressWireMeter   = metrics.NewRegisteredMeter("p2p/compression/egress/wire", nil)
+	egressRatioGauge  = metrics.NewRegisteredGauge("p2p/compression/egress/ratio", nil)
 )
 
+// meterCompression accounts the plain and on-the-wire payload sizes of a snappy
+// compressed message, updating the overall compression ratio in percents (wire
+// size relative to the plain size, lower being better).
+func meterCompression(ingress bool, plain, wire int) {
+	plainMeter, wireMeter, ratioGauge := egressPlainMeter, egressWireMeter, egressRatioGauge
+	if ingress {
+		plainMeter, wireMeter, ratioGauge = ingressPlainMeter, ingressWireMeter, ingressRatioGauge
+	}
+	plainMeter.Mark(int64(plain))
+	wireMeter.Mark(int64(wire))
+	if total := plainMeter.Count(); total > 0 {
+		ratioGauge.Update(wireMeter.Count() * 100 / total)
+	}
+}
+
 // meteredConn is a wrapper around a net.Conn that meters both the
 // inbound and outbound network traffic.
 type meteredConn struct {
@@ -40,6 +40,7 @@ import (
 	"github.com/420integrated/go-420coin/p2p/enr"
 	"github.com/420integrated/go-420coin/p2p/nat"
 	"github.com/420integrated/go-420coin/p2p/netutil"
+	"github.com/420integrated/go-420coin/params"
 )
 
 const (
@@ -149,6 +150,10 @@ type Config struct {
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool `toml:",omitempty"`
 
+	// If NoCompression is true, the server will not negotiate snappy compression
+	// of messages, trading bandwidth for CPU time on constrained devices.
+	NoCompression bool `toml:",omitempty"`
+
 	// If EnableMsgEvents is set then the server will emit PeerEvents
 	// whenever a message is sent to or received from a peer
 	EnableMsgEvents bool
@@ -493,6 +498,10 @@ func (srv *Server) setupLocalNode() error {
 	// Create the devp2p handshake.
 	pubkey := crypto.FromECDSAPub(&srv.PrivateKey.PublicKey)
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: pubkey[1:]}
+	if srv.NoCompression {
+		// Advertise the last version without snappy support to keep it disabled
+		srv.ourHandshake.Version = snappyProtocolVersion - 1
+	}
 	for _, p := range srv.Protocols {
 		srv.ourHandshake.Caps = append(srv.ourHandshake.Caps, p.cap())
 	}
@@ -1069,6 +1078,7 @@ type NodeInfo struct {
 	} `json:"ports"`
 	ListenAddr string                 `json:"listenAddr"`
 	Protocols  map[string]interface{} `json:"protocols"`
+	Build      *params.BuildInfo      `json:"build,omitempty"` // Provenance of the running binary, if known
 }
 
 // NodeInfo gathers and returns a collection of metadata known about the host.
@@ -48,6 +48,7 @@ type rlpxTransport struct {
 	rmu, wmu sync.Mutex
 	wbuf     bytes.Buffer
 	conn     *rlpx.Conn
+	snappy   bool // Whether snappy compression was negotiated, for metering
 }
 
 func newRLPX(conn net.Conn, dialDest *ecdsa.PublicKey) transport {
@@ -69,6 +70,9 @@ func (t *rlpxTransport) ReadMsg() (Msg, error) {
 			meterSize:  uint32(wireSize),
 			Payload:    bytes.NewReader(data),
 		}
+		if metrics.Enabled && t.snappy {
+			meterCompression(true, len(data), wireSize)
+		}
 	}
 	return msg, err
 }
@@ -92,6 +96,9 @@ func (t *rlpxTransport) WriteMsg(msg Msg) error {
 
 	// Set metrics.
 	msg.meterSize = size
+	if metrics.Enabled && t.snappy {
+		meterCompression(false, t.wbuf.Len(), int(size))
+	}
 	if metrics.Enabled && msg.meterCap.Name != "" { // don't meter non-subprotocol messages
 		m := fmt.Sprintf("%s/%s/%d/%#02x", egressMeterName, msg.meterCap.Name, msg.meterCap.Version, msg.meterCode)
 		metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))
@@ -140,8 +147,18 @@ func (t *rlpxTransport) doProtoHandshake(our *protoHandshake) (their *protoHands
 	if err := <-werr; err != nil {
 		return nil, fmt.Errorf("write error: %v", err)
 	}
-	// If the protocol version supports Snappy encoding, upgrade immediately
-	t.conn.SetSnappy(their.Version >= snappyProtocolVersion)
+	// If both ends support Snappy encoding, upgrade immediately. Our own version is
+	// checked too, as compression may be disabled locally by advertising a lower one.
+	t.snappy = our.Version >= snappyProtocolVersion && their.Version >= snappyProtocolVersion
+	t.conn.SetSnappy(t.snappy)
+
+	if metrics.Enabled {
+		if t.snappy {
+			snappyNegotiatedMeter.Mark(1)
+		} else {
+			snappyDeclinedMeter.Mark(1)
+		}
+	}
 
 	return their, nil
 }
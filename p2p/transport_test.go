@@ -17,6 +17,7 @@
 package p2p
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"reflect"
 	"sync"
@@ -146,3 +147,54 @@ func TestProtocolHandshakeErrors(t *testing.T) {
 		}
 	}
 }
+
+// Tests that snappy compression is only enabled if both ends of the connection
+// advertise support for it.
+func TestProtocolHandshakeSnappy(t *testing.T) {
+	tests := []struct {
+		dialVersion, listenVersion uint64
+		snappy                     bool
+	}{
+		{snappyProtocolVersion, snappyProtocolVersion, true},
+		{snappyProtocolVersion - 1, snappyProtocolVersion, false},
+		{snappyProtocolVersion, snappyProtocolVersion - 1, false},
+		{snappyProtocolVersion - 1, snappyProtocolVersion - 1, false},
+	}
+	for i, tt := range tests {
+		var (
+			prv0, _ = crypto.GenerateKey()
+			prv1, _ = crypto.GenerateKey()
+			hs0     = &protoHandshake{Version: tt.dialVersion, ID: crypto.FromECDSAPub(&prv0.PublicKey)[1:]}
+			hs1     = &protoHandshake{Version: tt.listenVersion, ID: crypto.FromECDSAPub(&prv1.PublicKey)[1:]}
+		)
+		fd0, fd1, err := pipes.TCPPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var (
+			dial   = newRLPX(fd0, &prv1.PublicKey).(*rlpxTransport)
+			listen = newRLPX(fd1, nil).(*rlpxTransport)
+			wg     sync.WaitGroup
+		)
+		wg.Add(2)
+		handshake := func(tr *rlpxTransport, prv *ecdsa.PrivateKey, hs *protoHandshake) {
+			defer wg.Done()
+			if _, err := tr.doEncHandshake(prv); err != nil {
+				t.Errorf("test %d: enc handshake failed: %v", i, err)
+				return
+			}
+			if _, err := tr.doProtoHandshake(hs); err != nil {
+				t.Errorf("test %d: proto handshake failed: %v", i, err)
+			}
+		}
+		go handshake(dial, prv0, hs0)
+		go handshake(listen, prv1, hs1)
+		wg.Wait()
+
+		if dial.snappy != tt.snappy || listen.snappy != tt.snappy {
+			t.Errorf("test %d: snappy mismatch: dial %v, listen %v, want %v", i, dial.snappy, listen.snappy, tt.snappy)
+		}
+		fd0.Close()
+		fd1.Close()
+	}
+}
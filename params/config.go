@@ -18,6 +18,7 @@ package params
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -152,16 +153,16 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, 0, nil, 0, nil, common.Address{}, nil, nil, new(EthashConfig), nil, nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, 0, nil, 0, nil, common.Address{}, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil}
 
-	TestChainConfig = &ChainConfig{big.NewInt(422), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil}
+	TestChainConfig = &ChainConfig{big.NewInt(422), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, 0, nil, 0, nil, common.Address{}, nil, nil, new(EthashConfig), nil, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -236,13 +237,86 @@ type ChainConfig struct {
 	YoloV2Block *big.Int `json:"yoloV2Block,omitempty"` // YOLO v2: Gas repricings TODO @holiman add EIP references
 	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
 
+	// RefundRemovalBlock removes the SELFDESTRUCT and SSTORE clearing refunds to
+	// defeat refund driven smoke token schemes (EIP-3529 style).
+	RefundRemovalBlock *big.Int `json:"refundRemovalBlock,omitempty"` // Refund removal switch block (nil = no fork, 0 = already activated)
+
+	// MaxCodeSize overrides the contract code size limit of EIP-170 from the
+	// MaxCodeSizeBlock onwards, e.g. to permit large on-chain registries.
+	MaxCodeSizeBlock *big.Int `json:"maxCodeSizeBlock,omitempty"` // Code size limit switch block (nil = no override, 0 = already activated)
+	MaxCodeSize      uint64   `json:"maxCodeSize,omitempty"`      // Maximum bytecode to permit for a contract once the override is active
+
+	// VeteransFee routes a percentage of the transaction fees, otherwise paid to
+	// the coinbase in full, to the Veterans Fund from the VeteransFeeBlock onwards.
+	VeteransFeeBlock   *big.Int `json:"veteransFeeBlock,omitempty"`   // Fee routing switch block (nil = no fork, 0 = already activated)
+	VeteransFeePercent uint64   `json:"veteransFeePercent,omitempty"` // Percentage of the transaction fees routed once the fork is active
+
+	// RewardRegistry migrates the contract holding the reward recipient addresses,
+	// otherwise derived from the creator named in the genesis extra-data, to the
+	// given address from the RewardRegistryBlock onwards.
+	RewardRegistryBlock *big.Int       `json:"rewardRegistryBlock,omitempty"` // Registry migration switch block (nil = no fork, 0 = already activated)
+	RewardRegistry      common.Address `json:"rewardRegistry,omitempty"`      // Address of the migrated registry contract
+
+	// RewardCheckBlock rejects blocks paying their Veterans Fund or followers
+	// rewards to the zero address, i.e. to an unset reward contract slot.
+	RewardCheckBlock *big.Int `json:"rewardCheckBlock,omitempty"` // Reward recipient check switch block (nil = no fork, 0 = already activated)
+
+	// SmokeBaseFeeBlock introduces an EIP-1559 style fee market: every block has a
+	// base fee per smoke adjusting to its parent's usage, which is burnt, and
+	// transactions may cap their total price and the tip paid to the miner.
+	SmokeBaseFeeBlock *big.Int `json:"smokeBaseFeeBlock,omitempty"` // Smoke base fee switch block (nil = no fork, 0 = already activated)
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
+
+	// Federation enables federated checkpointing on top of the consensus engine
+	Federation *FederationConfig `json:"federation,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
-type EthashConfig struct{}
+type EthashConfig struct {
+	RewardEras []RewardEra `json:"rewardEras,omitempty"` // Block reward split schedule (empty = DefaultRewardEras)
+}
+
+// RewardEra is a range of blocks sharing the same split of the ethash block reward
+// between the miner, the Veterans Fund and the Cannasseur Network followers. An
+// era lasts until the next one begins.
+type RewardEra struct {
+	Name      string   `json:"name"`
+	Block     *big.Int `json:"block"`     // First block of the era
+	Miner     uint64   `json:"miner"`     // Percentage of the reward paid to the miner
+	Veterans  uint64   `json:"veterans"`  // Percentage of the reward paid to the Veterans Fund
+	Followers uint64   `json:"followers"` // Percentage of the reward paid to the followers
+}
+
+// DefaultRewardEras is the block reward split schedule of the 420coin networks,
+// applied unless the chain config defines its own.
+var DefaultRewardEras = []RewardEra{
+	{Name: "ruderalis", Block: big.NewInt(0), Miner: 87, Veterans: 13},
+	{Name: "indica", Block: big.NewInt(1111112), Miner: 80, Veterans: 10, Followers: 10},
+	{Name: "sativa", Block: big.NewInt(2102401), Miner: 75, Veterans: 10, Followers: 15},
+}
+
+// CheckRewardEras verifies that the configured reward eras start at genesis, are
+// ordered by ascending block number and split exactly 100% of the reward.
+func (c *EthashConfig) CheckRewardEras() error {
+	for i, era := range c.RewardEras {
+		if era.Block == nil {
+			return fmt.Errorf("reward era %q has no start block", era.Name)
+		}
+		if i == 0 && era.Block.Sign() != 0 {
+			return fmt.Errorf("first reward era %q starts at block %v instead of genesis", era.Name, era.Block)
+		}
+		if i > 0 && era.Block.Cmp(c.RewardEras[i-1].Block) <= 0 {
+			return fmt.Errorf("reward era %q starts at block %v, not after era %q at %v", era.Name, era.Block, c.RewardEras[i-1].Name, c.RewardEras[i-1].Block)
+		}
+		if total := era.Miner + era.Veterans + era.Followers; total != 100 {
+			return fmt.Errorf("reward era %q splits %d%% of the reward instead of 100%%", era.Name, total)
+		}
+	}
+	return nil
+}
 
 // String implements the stringer interface, returning the consensus engine details.
 func (c *EthashConfig) String() string {
@@ -260,6 +334,15 @@ func (c *CliqueConfig) String() string {
 	return "clique"
 }
 
+// FederationConfig is the configuration of federated checkpointing, where a set
+// of signers vote on the canonical block hash every few blocks. Once enough
+// votes are collected, nodes refuse to reorg behind the checkpoint.
+type FederationConfig struct {
+	Signers   []common.Address `json:"signers"`   // Addresses allowed to sign checkpoints
+	Interval  uint64           `json:"interval"`  // Number of blocks between checkpoints
+	Threshold uint64           `json:"threshold"` // Number of signatures needed to finalize a checkpoint
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	var engine interface{}
@@ -351,6 +434,96 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsRefundRemoval returns whether num is either equal to the refund removal fork block or greater.
+func (c *ChainConfig) IsRefundRemoval(num *big.Int) bool {
+	return isForked(c.RefundRemovalBlock, num)
+}
+
+// MaxCodeSizeAt returns the maximum bytecode size permitted for a contract at
+// block num, taking any chain specific override into account.
+func (c *ChainConfig) MaxCodeSizeAt(num *big.Int) int {
+	if c.MaxCodeSize != 0 && isForked(c.MaxCodeSizeBlock, num) {
+		return int(c.MaxCodeSize)
+	}
+	return MaxCodeSize
+}
+
+// VeteransFeePercentAt returns the percentage of the transaction fees of block
+// num routed to the Veterans Fund instead of the coinbase.
+func (c *ChainConfig) VeteransFeePercentAt(num *big.Int) uint64 {
+	if isForked(c.VeteransFeeBlock, num) {
+		return c.VeteransFeePercent
+	}
+	return 0
+}
+
+// RewardEras returns the ethash block reward split schedule of the chain.
+func (c *ChainConfig) RewardEras() []RewardEra {
+	if c.Ethash != nil && len(c.Ethash.RewardEras) > 0 {
+		return c.Ethash.RewardEras
+	}
+	return DefaultRewardEras
+}
+
+// RewardEraAt returns the ethash reward era block num belongs to.
+func (c *ChainConfig) RewardEraAt(num *big.Int) *RewardEra {
+	eras := c.RewardEras()
+	for i := len(eras) - 1; i > 0; i-- {
+		if isForked(eras[i].Block, num) {
+			return &eras[i]
+		}
+	}
+	return &eras[0]
+}
+
+// WithRewardEraBlocks returns a copy of the chain config with the named ethash
+// reward eras moved to start at the given blocks, leaving the original config
+// untouched. The resulting schedule is validated by CheckConfigForkOrder.
+func (c *ChainConfig) WithRewardEraBlocks(blocks map[string]*big.Int) (*ChainConfig, error) {
+	if len(blocks) == 0 {
+		return c, nil
+	}
+	if c.Ethash == nil {
+		return nil, errors.New("reward eras can only be moved on ethash chains")
+	}
+	eras := append([]RewardEra{}, c.RewardEras()...)
+	for name, block := range blocks {
+		found := false
+		for i := range eras {
+			if eras[i].Name == name {
+				eras[i].Block, found = new(big.Int).Set(block), true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown reward era %q", name)
+		}
+	}
+	ethash := *c.Ethash
+	ethash.RewardEras = eras
+
+	cpy := *c
+	cpy.Ethash = &ethash
+	return &cpy, nil
+}
+
+// IsRewardRegistryMigrated returns whether num is either equal to the reward
+// registry migration fork block or greater.
+func (c *ChainConfig) IsRewardRegistryMigrated(num *big.Int) bool {
+	return isForked(c.RewardRegistryBlock, num)
+}
+
+// IsRewardCheck returns whether num is either equal to the reward recipient check
+// fork block or greater.
+func (c *ChainConfig) IsRewardCheck(num *big.Int) bool {
+	return isForked(c.RewardCheckBlock, num)
+}
+
+// IsSmokeBaseFee returns whether num is either equal to the smoke base fee fork
+// block or greater.
+func (c *ChainConfig) IsSmokeBaseFee(num *big.Int) bool {
+	return isForked(c.SmokeBaseFeeBlock, num)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -409,6 +582,17 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if c.VeteransFeePercent > 100 {
+		return fmt.Errorf("invalid veterans fee percentage: %d", c.VeteransFeePercent)
+	}
+	if c.RewardRegistryBlock != nil && c.RewardRegistry == (common.Address{}) {
+		return errors.New("reward registry migration scheduled without a registry address")
+	}
+	if c.Ethash != nil {
+		if err := c.Ethash.CheckRewardEras(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -459,6 +643,62 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.RefundRemovalBlock, newcfg.RefundRemovalBlock, head) {
+		return newCompatError("refund removal fork block", c.RefundRemovalBlock, newcfg.RefundRemovalBlock)
+	}
+	if isForkIncompatible(c.MaxCodeSizeBlock, newcfg.MaxCodeSizeBlock, head) {
+		return newCompatError("max code size fork block", c.MaxCodeSizeBlock, newcfg.MaxCodeSizeBlock)
+	}
+	if isForked(c.MaxCodeSizeBlock, head) && c.MaxCodeSize != newcfg.MaxCodeSize {
+		return newCompatError("max code size", c.MaxCodeSizeBlock, newcfg.MaxCodeSizeBlock)
+	}
+	if isForkIncompatible(c.VeteransFeeBlock, newcfg.VeteransFeeBlock, head) {
+		return newCompatError("veterans fee fork block", c.VeteransFeeBlock, newcfg.VeteransFeeBlock)
+	}
+	if isForked(c.VeteransFeeBlock, head) && c.VeteransFeePercent != newcfg.VeteransFeePercent {
+		return newCompatError("veterans fee percentage", c.VeteransFeeBlock, newcfg.VeteransFeeBlock)
+	}
+	if isForkIncompatible(c.RewardRegistryBlock, newcfg.RewardRegistryBlock, head) {
+		return newCompatError("reward registry fork block", c.RewardRegistryBlock, newcfg.RewardRegistryBlock)
+	}
+	if isForked(c.RewardRegistryBlock, head) && c.RewardRegistry != newcfg.RewardRegistry {
+		return newCompatError("reward registry address", c.RewardRegistryBlock, newcfg.RewardRegistryBlock)
+	}
+	if isForkIncompatible(c.RewardCheckBlock, newcfg.RewardCheckBlock, head) {
+		return newCompatError("reward check fork block", c.RewardCheckBlock, newcfg.RewardCheckBlock)
+	}
+	if isForkIncompatible(c.SmokeBaseFeeBlock, newcfg.SmokeBaseFeeBlock, head) {
+		return newCompatError("smoke base fee fork block", c.SmokeBaseFeeBlock, newcfg.SmokeBaseFeeBlock)
+	}
+	if err := checkRewardErasCompatible(c.RewardEras(), newcfg.RewardEras(), head); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkRewardErasCompatible checks whether the reward split schedule may be
+// changed from stored to newEras, i.e. that the first differing era didn't begin
+// yet at the given head in either of them.
+func checkRewardErasCompatible(stored, newEras []RewardEra, head *big.Int) *ConfigCompatError {
+	for i := 0; i < len(stored) || i < len(newEras); i++ {
+		var s1, s2 *big.Int
+		if i < len(stored) {
+			s1 = stored[i].Block
+		}
+		if i < len(newEras) {
+			s2 = newEras[i].Block
+		}
+		if i < len(stored) && i < len(newEras) && configNumEqual(s1, s2) {
+			a, b := stored[i], newEras[i]
+			if a.Miner == b.Miner && a.Veterans == b.Veterans && a.Followers == b.Followers {
+				continue
+			}
+		}
+		if isForked(s1, head) || isForked(s2, head) {
+			return newCompatError("reward era", s1, s2)
+		}
+		return nil
+	}
 	return nil
 }
 
@@ -526,7 +766,8 @@ type Rules struct {
 	ChainID                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
-	IsYoloV2                                                bool
+	IsYoloV2, IsRefundRemoval, IsSmokeBaseFee               bool
+	MaxCodeSize                                             int
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -546,5 +787,8 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsPetersburg:     c.IsPetersburg(num),
 		IsIstanbul:       c.IsIstanbul(num),
 		IsYoloV2:         c.IsYoloV2(num),
+		IsRefundRemoval:  c.IsRefundRemoval(num),
+		IsSmokeBaseFee:   c.IsSmokeBaseFee(num),
+		MaxCodeSize:      c.MaxCodeSizeAt(num),
 	}
 }
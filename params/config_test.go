@@ -20,8 +20,17 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/420integrated/go-420coin/common"
 )
 
+// customRewardEras is a reward split schedule diverging from the default one at
+// its second era.
+var customRewardEras = []RewardEra{
+	{Name: "ruderalis", Block: big.NewInt(0), Miner: 87, Veterans: 13},
+	{Name: "custom", Block: big.NewInt(1000), Miner: 50, Veterans: 25, Followers: 25},
+}
+
 func TestCheckCompatible(t *testing.T) {
 	type test struct {
 		stored, new *ChainConfig
@@ -87,6 +96,73 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     30,
 			},
 		},
+		{
+			stored:  &ChainConfig{MaxCodeSizeBlock: big.NewInt(10), MaxCodeSize: 49152},
+			new:     &ChainConfig{MaxCodeSizeBlock: big.NewInt(10), MaxCodeSize: 65536},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{MaxCodeSizeBlock: big.NewInt(10), MaxCodeSize: 49152},
+			new:    &ChainConfig{MaxCodeSizeBlock: big.NewInt(10), MaxCodeSize: 65536},
+			head:   10,
+			wantErr: &ConfigCompatError{
+				What:         "max code size",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{RefundRemovalBlock: big.NewInt(30)},
+			new:    &ChainConfig{RefundRemovalBlock: nil},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "refund removal fork block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    nil,
+				RewindTo:     29,
+			},
+		},
+		{
+			stored: &ChainConfig{VeteransFeeBlock: big.NewInt(10), VeteransFeePercent: 10},
+			new:    &ChainConfig{VeteransFeeBlock: big.NewInt(10), VeteransFeePercent: 20},
+			head:   10,
+			wantErr: &ConfigCompatError{
+				What:         "veterans fee percentage",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{RewardRegistryBlock: big.NewInt(10), RewardRegistry: common.Address{0x01}},
+			new:    &ChainConfig{RewardRegistryBlock: big.NewInt(10), RewardRegistry: common.Address{0x02}},
+			head:   10,
+			wantErr: &ConfigCompatError{
+				What:         "reward registry address",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{Ethash: &EthashConfig{RewardEras: customRewardEras}},
+			head:    100,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{Ethash: &EthashConfig{RewardEras: customRewardEras}},
+			head:   1200000,
+			wantErr: &ConfigCompatError{
+				What:         "reward era",
+				StoredConfig: big.NewInt(1111112),
+				NewConfig:    big.NewInt(1000),
+				RewindTo:     999,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -96,3 +172,57 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestMaxCodeSizeAt(t *testing.T) {
+	config := &ChainConfig{MaxCodeSizeBlock: big.NewInt(10), MaxCodeSize: 49152}
+	if size := config.MaxCodeSizeAt(big.NewInt(9)); size != MaxCodeSize {
+		t.Errorf("pre-fork limit mismatch: have %d, want %d", size, MaxCodeSize)
+	}
+	if size := config.MaxCodeSizeAt(big.NewInt(10)); size != 49152 {
+		t.Errorf("post-fork limit mismatch: have %d, want %d", size, 49152)
+	}
+	if size := AllEthashProtocolChanges.MaxCodeSizeAt(big.NewInt(10)); size != MaxCodeSize {
+		t.Errorf("default limit mismatch: have %d, want %d", size, MaxCodeSize)
+	}
+}
+
+func TestVeteransFeePercentAt(t *testing.T) {
+	config := &ChainConfig{VeteransFeeBlock: big.NewInt(10), VeteransFeePercent: 10}
+	if percent := config.VeteransFeePercentAt(big.NewInt(9)); percent != 0 {
+		t.Errorf("pre-fork percentage mismatch: have %d, want 0", percent)
+	}
+	if percent := config.VeteransFeePercentAt(big.NewInt(10)); percent != 10 {
+		t.Errorf("post-fork percentage mismatch: have %d, want 10", percent)
+	}
+	config.VeteransFeePercent = 101
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Errorf("invalid percentage accepted")
+	}
+}
+
+func TestRewardEras(t *testing.T) {
+	config := &ChainConfig{Ethash: &EthashConfig{RewardEras: customRewardEras}}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("valid reward eras rejected: %v", err)
+	}
+	for number, want := range map[int64]string{0: "ruderalis", 999: "ruderalis", 1000: "custom", 5000000: "custom"} {
+		if have := config.RewardEraAt(big.NewInt(number)).Name; have != want {
+			t.Errorf("block %d: era mismatch: have %s, want %s", number, have, want)
+		}
+	}
+	if have := (&ChainConfig{}).RewardEraAt(big.NewInt(2102401)).Name; have != "sativa" {
+		t.Errorf("default era mismatch: have %s, want sativa", have)
+	}
+	invalid := [][]RewardEra{
+		{{Name: "late", Block: big.NewInt(1), Miner: 100}},
+		{{Name: "a", Block: big.NewInt(0), Miner: 100}, {Name: "b", Block: big.NewInt(0), Miner: 100}},
+		{{Name: "short", Block: big.NewInt(0), Miner: 80, Veterans: 10}},
+		{{Name: "nil", Miner: 100}},
+	}
+	for i, eras := range invalid {
+		config := &ChainConfig{Ethash: &EthashConfig{RewardEras: eras}}
+		if err := config.CheckConfigForkOrder(); err == nil {
+			t.Errorf("invalid reward eras %d accepted", i)
+		}
+	}
+}
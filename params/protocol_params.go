@@ -23,6 +23,10 @@ const (
 	MinSmokeLimit          uint64 = 5000    // Minimum the smoke limit may ever be.
 	GenesisSmokeLimit      uint64 = 5000000 // Smoke limit of the Genesis block.
 
+	BaseFeeChangeDenominator uint64 = 8          // Bounds the amount the base fee can change between blocks.
+	ElasticityMultiplier     uint64 = 2          // Bounds the maximum smoke limit of a base fee block relative to its target.
+	InitialBaseFee           uint64 = 1000000000 // Base fee per smoke of the first block of the fee market.
+
 	MaximumExtraDataSize    uint64 = 32    // Maximum size extra data may be after Genesis.
 	ExpByteSmoke            uint64 = 10    // Times ceil(log256(exponent)) for the EXP instruction.
 	SloadSmoke              uint64 = 50    // Multiplied by the number of 32-byte words that are copied (round up) for any *COPY operation and added.
@@ -75,6 +79,9 @@ const (
 	TxDataNonZeroSmokeFrontier uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
 	TxDataNonZeroSmokeEIP2028  uint64 = 16    // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
 
+	TxAccessListAddressSmoke    uint64 = 2400 // Per address specified in EIP 2930 access list
+	TxAccessListStorageKeySmoke uint64 = 1900 // Per storage key specified in EIP 2930 access list
+
 	// These have been changed during the course of the chain
 	CallSmokeFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
 	CallSmokeEIP150                uint64 = 700 // Static portion of smoke for CALL-derivates after EIP 150 (Tangerine)
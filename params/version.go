@@ -18,6 +18,7 @@ package params
 
 import (
 	"fmt"
+	"runtime"
 )
 
 const (
@@ -65,3 +66,34 @@ func VersionWithCommit(gitCommit, gitDate string) string {
 	}
 	return vsn
 }
+
+// BuildInfo describes the provenance of the running binary. It is exposed over
+// RPC so that operators can verify that consensus-critical nodes across a fleet
+// run identical builds.
+type BuildInfo struct {
+	Version   string `json:"version"`             // Release version including metadata
+	GitCommit string `json:"gitCommit,omitempty"` // Git SHA1 of the source the binary was built from
+	GitDate   string `json:"gitDate,omitempty"`   // Commit date of the source (YYYYMMDD)
+	BuildDate string `json:"buildDate,omitempty"` // Build date, derived from the source for reproducibility
+	GoVersion string `json:"goVersion"`           // Version of the Go toolchain used for the build
+	OS        string `json:"os"`                  // Target operating system
+	Arch      string `json:"arch"`                // Target architecture
+	CGO       bool   `json:"cgo"`                 // Whether cgo was enabled during the build
+	CGOFlags  string `json:"cgoFlags,omitempty"`  // C compiler flags used for cgo dependencies
+}
+
+// NewBuildInfo assembles the build metadata of the running binary from the values
+// injected at link time and the properties of the Go runtime.
+func NewBuildInfo(gitCommit, gitDate, buildDate, cgoFlags string) *BuildInfo {
+	return &BuildInfo{
+		Version:   VersionWithMeta,
+		GitCommit: gitCommit,
+		GitDate:   gitDate,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CGO:       cgoEnabled,
+		CGOFlags:  cgoFlags,
+	}
+}
@@ -255,6 +255,94 @@ func TestStreamList(t *testing.T) {
 	}
 }
 
+func TestStreamReadBytes(t *testing.T) {
+	tests := []struct {
+		input string
+		size  int
+		err   string
+	}{
+		// kind List
+		{input: "C0", size: 1, err: "rlp: expected String or Byte"},
+		// kind Byte
+		{input: "04", size: 0, err: "rlp: input value has wrong size 1, want 0"},
+		{input: "04", size: 1},
+		{input: "04", size: 2, err: "rlp: input value has wrong size 1, want 2"},
+		// kind String
+		{input: "820102", size: 0, err: "rlp: input value has wrong size 2, want 0"},
+		{input: "820102", size: 1, err: "rlp: input value has wrong size 2, want 1"},
+		{input: "820102", size: 2},
+		{input: "820102", size: 3, err: "rlp: input value has wrong size 2, want 3"},
+		{input: "8101", size: 1, err: "rlp: non-canonical size information"},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("input_%s/size_%d", test.input, test.size)
+		t.Run(name, func(t *testing.T) {
+			s := NewStream(bytes.NewReader(unhex(test.input)), 0)
+			b := make([]byte, test.size)
+			err := s.ReadBytes(b)
+			if test.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error %q", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				} else if err.Error() != test.err {
+					t.Errorf("wrong error %q", err)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamSizedUints(t *testing.T) {
+	s := NewStream(bytes.NewReader(unhex("CA81FF82FFFF84FFFFFFFF")), 0)
+	if _, err := s.List(); err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if v, err := s.Uint8(); err != nil || v != 0xFF {
+		t.Errorf("Uint8: got %d, %v", v, err)
+	}
+	if v, err := s.Uint16(); err != nil || v != 0xFFFF {
+		t.Errorf("Uint16: got %d, %v", v, err)
+	}
+	if v, err := s.Uint32(); err != nil || v != 0xFFFFFFFF {
+		t.Errorf("Uint32: got %d, %v", v, err)
+	}
+	if s.MoreDataInList() {
+		t.Error("MoreDataInList returned true at end of list")
+	}
+	if err := s.ListEnd(); err != nil {
+		t.Fatalf("ListEnd error: %v", err)
+	}
+	s = NewStream(bytes.NewReader(unhex("820100")), 0)
+	if _, err := s.Uint8(); err != errUintOverflow {
+		t.Errorf("Uint8 error mismatch, got %v, want %v", err, errUintOverflow)
+	}
+}
+
+func TestStreamBigInt(t *testing.T) {
+	s := NewStream(bytes.NewReader(unhex("CE80058801020304050607088200FF")), 0)
+	if _, err := s.List(); err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	for _, want := range []*big.Int{big.NewInt(0), big.NewInt(5), new(big.Int).SetBytes(unhex("0102030405060708"))} {
+		if !s.MoreDataInList() {
+			t.Fatal("MoreDataInList returned false before end of list")
+		}
+		v, err := s.BigInt()
+		if err != nil {
+			t.Fatalf("BigInt error: %v", err)
+		}
+		if v.Cmp(want) != 0 {
+			t.Errorf("BigInt returned wrong value, got %v, want %v", v, want)
+		}
+	}
+	if _, err := s.BigInt(); err != ErrCanonInt {
+		t.Errorf("BigInt error mismatch, got %v, want %v", err, ErrCanonInt)
+	}
+}
+
 func TestStreamRaw(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -357,6 +445,34 @@ type tailPrivateFields struct {
 	x, y bool   //lint:ignore U1000 unused fields required for testing purposes.
 }
 
+type optionalFields struct {
+	A uint
+	B uint `rlp:"optional"`
+	C uint `rlp:"optional"`
+}
+
+type optionalAndTailField struct {
+	A    uint
+	B    uint   `rlp:"optional"`
+	Tail []uint `rlp:"tail"`
+}
+
+type optionalBigIntField struct {
+	A uint
+	B *big.Int `rlp:"optional"`
+}
+
+type optionalPtrField struct {
+	A uint
+	B *[3]byte `rlp:"optional"`
+}
+
+type nonOptionalPtrField struct {
+	A uint
+	B *[3]byte `rlp:"optional"`
+	C *[3]byte
+}
+
 type nilListUint struct {
 	X *uint `rlp:"nilList"`
 }
@@ -548,6 +664,63 @@ var decodeTests = []decodeTest{
 		error: `rlp: invalid struct tag "tail" for rlp.invalidTail2.B (field type is not slice)`,
 	},
 
+	// struct tag "optional"
+	{
+		input: "C101",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 0, 0},
+	},
+	{
+		input: "C20102",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 0},
+	},
+	{
+		input: "C3010203",
+		ptr:   new(optionalFields),
+		value: optionalFields{1, 2, 3},
+	},
+	{
+		input: "C401020304",
+		ptr:   new(optionalFields),
+		error: "rlp: input list has too many elements for rlp.optionalFields",
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalAndTailField),
+		value: optionalAndTailField{A: 1},
+	},
+	{
+		input: "C3010203",
+		ptr:   new(optionalAndTailField),
+		value: optionalAndTailField{A: 1, B: 2, Tail: []uint{3}},
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalBigIntField),
+		value: optionalBigIntField{A: 1, B: nil},
+	},
+	{
+		input: "C20102",
+		ptr:   new(optionalBigIntField),
+		value: optionalBigIntField{A: 1, B: big.NewInt(2)},
+	},
+	{
+		input: "C101",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1},
+	},
+	{
+		input: "C50183010203",
+		ptr:   new(optionalPtrField),
+		value: optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}},
+	},
+	{
+		input: "C101",
+		ptr:   new(nonOptionalPtrField),
+		error: "rlp: struct field rlp.nonOptionalPtrField.C needs \"optional\" tag",
+	},
+
 	// struct tag "-"
 	{
 		input: "C20102",
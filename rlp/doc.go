@@ -102,7 +102,8 @@ Signed integers, floating point numbers, maps, channels and functions cannot be
 
 Struct Tags
 
-Package rlp honours certain struct tags: "-", "tail", "nil", "nilList" and "nilString".
+Package rlp honours certain struct tags: "-", "tail", "nil", "nilList", "nilString" and
+"optional".
 
 The "-" tag ignores fields.
 
@@ -126,5 +127,11 @@ an empty RLP list.
 The choice of null value can be made explicit with the "nilList" and "nilString" struct
 tags. Using these tags encodes/decodes a Go nil pointer value as the kind of empty
 RLP value defined by the tag.
+
+The "optional" tag allows a struct field to be missing from the input list. Trailing
+fields holding their zero value are omitted when encoding, and fields missing from the
+input are set to their zero value when decoding. Once a field is marked "optional", all
+subsequent exported fields must be "optional" too. The "optional" tag can't be combined
+with the "tail" tag.
 */
 package rlp
@@ -262,6 +262,14 @@ var encTests = []encTest{
 	{val: &tailRaw{A: 1, Tail: []RawValue{}}, output: "C101"},
 	{val: &tailRaw{A: 1, Tail: nil}, output: "C101"},
 	{val: &hasIgnoredField{A: 1, B: 2, C: 3}, output: "C20103"},
+	{val: &optionalFields{A: 1, B: 0, C: 0}, output: "C101"},
+	{val: &optionalFields{A: 1, B: 2, C: 0}, output: "C20102"},
+	{val: &optionalFields{A: 1, B: 0, C: 3}, output: "C3018003"},
+	{val: &optionalAndTailField{A: 1, B: 0, Tail: nil}, output: "C101"},
+	{val: &optionalAndTailField{A: 1, B: 2, Tail: []uint{3, 4}}, output: "C401020304"},
+	{val: &optionalBigIntField{A: 1, B: nil}, output: "C101"},
+	{val: &optionalPtrField{A: 1, B: nil}, output: "C101"},
+	{val: &optionalPtrField{A: 1, B: &[3]byte{1, 2, 3}}, output: "C50183010203"},
 	{val: &intField{X: 3}, error: "rlp: type int is not RLP-serializable (struct field rlp.intField.X)"},
 
 	// nil
@@ -430,6 +438,59 @@ func TestEncodeToReaderReturnToPool(t *testing.T) {
 	wg.Wait()
 }
 
+// bufferEncoder implements EncodeRLP using EncoderBuffer.
+type bufferEncoder struct {
+	A uint64
+	B []byte
+	C *big.Int
+}
+
+func (e *bufferEncoder) EncodeRLP(w io.Writer) error {
+	buf := NewEncoderBuffer(w)
+	l := buf.List()
+	buf.WriteUint64(e.A)
+	buf.WriteBytes(e.B)
+	buf.WriteBigInt(e.C)
+	buf.WriteBool(true)
+	buf.WriteString("foo")
+	buf.ListEnd(l)
+	return buf.Flush()
+}
+
+func TestEncoderBuffer(t *testing.T) {
+	v := &bufferEncoder{A: 1024, B: []byte{1, 2, 3}, C: new(big.Int).Lsh(big.NewInt(1), 100)}
+	want, err := EncodeToBytes([]interface{}{v.A, v.B, v.C, true, "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Encoding to a plain writer goes through a pooled buffer.
+	out := new(bytes.Buffer)
+	if err := v.EncodeRLP(out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("wrong output: got %x, want %x", out.Bytes(), want)
+	}
+	// Encoding as part of an outer value writes to the outer buffer.
+	nested, err := EncodeToBytes([]interface{}{v, uint(1), v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNested, _ := EncodeToBytes([]interface{}{RawValue(want), uint(1), RawValue(want)})
+	if !bytes.Equal(nested, wantNested) {
+		t.Errorf("wrong nested output: got %x, want %x", nested, wantNested)
+	}
+	// AppendToBytes keeps the existing content of dst.
+	w := NewEncoderBuffer(nil)
+	w.WriteUint64(5)
+	if got := w.AppendToBytes([]byte{0xAA}); !bytes.Equal(got, []byte{0xAA, 0x05}) {
+		t.Errorf("wrong AppendToBytes output: %x", got)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 var sink interface{}
 
 func BenchmarkIntsize(b *testing.B) {
@@ -0,0 +1,736 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// generatedHeader is the first line of every file produced by rlpgen.
+const generatedHeader = "// Code generated by rlpgen. DO NOT EDIT."
+
+// buildContext holds the types of package rlp needed to decide how
+// a type is encoded.
+type buildContext struct {
+	encoderIface *types.Interface
+	decoderIface *types.Interface
+	rawValueType types.Type
+}
+
+func newBuildContext(rlppkg *types.Package) *buildContext {
+	lookup := func(name string) types.Type {
+		return rlppkg.Scope().Lookup(name).Type()
+	}
+	return &buildContext{
+		encoderIface: lookup("Encoder").Underlying().(*types.Interface),
+		decoderIface: lookup("Decoder").Underlying().(*types.Interface),
+		rawValueType: lookup("RawValue"),
+	}
+}
+
+// genContext tracks the state of code generation for a single method.
+type genContext struct {
+	inPackage   *types.Package
+	imports     map[string]struct{}
+	tempCounter int
+}
+
+func newGenContext(inPackage *types.Package) *genContext {
+	return &genContext{
+		inPackage: inPackage,
+		imports:   make(map[string]struct{}),
+	}
+}
+
+// temp returns a fresh temporary variable name.
+func (ctx *genContext) temp() string {
+	v := fmt.Sprintf("_tmp%d", ctx.tempCounter)
+	ctx.tempCounter++
+	return v
+}
+
+// resetTemp restarts temporary variable numbering for a new method.
+func (ctx *genContext) resetTemp() {
+	ctx.tempCounter = 0
+}
+
+// qualify is a types.Qualifier recording the imports needed by the output.
+func (ctx *genContext) qualify(pkg *types.Package) string {
+	if pkg.Path() == ctx.inPackage.Path() {
+		return ""
+	}
+	ctx.imports[pkg.Path()] = struct{}{}
+	return pkg.Name()
+}
+
+// typeString renders typ as it should appear in the output file.
+func (ctx *genContext) typeString(typ types.Type) string {
+	return types.TypeString(typ, ctx.qualify)
+}
+
+// importsList returns the sorted import block of the output file, with
+// standard library packages grouped before all others.
+func (ctx *genContext) importsList() string {
+	var std, other []string
+	for path := range ctx.imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	var b bytes.Buffer
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// op is the code generation strategy for a single type.
+type op interface {
+	// genWrite creates the encoder. The generated code should write v,
+	// which is an addressable Go expression, to the rlp.EncoderBuffer 'w'.
+	genWrite(ctx *genContext, v string) string
+
+	// genDecode creates the decoder. The generated code should read a value
+	// from the rlp.Stream 'dec'. It returns the code and an expression
+	// holding the decoded value.
+	genDecode(ctx *genContext) (string, string)
+}
+
+// tags are the supported rlp struct tags of a field.
+type tags struct {
+	ignored  bool
+	nilOK    bool
+	nilList  bool
+	optional bool
+}
+
+func parseTags(field *types.Var, tag string) (tags, error) {
+	var ts tags
+	for _, t := range strings.Split(reflect.StructTag(tag).Get("rlp"), ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "":
+		case "-":
+			ts.ignored = true
+		case "nil", "nilString", "nilList":
+			ptr, ok := field.Type().(*types.Pointer)
+			if !ok {
+				return ts, fmt.Errorf("invalid struct tag %q on field %s: field is not a pointer", t, field.Name())
+			}
+			ts.nilOK = true
+			switch t {
+			case "nil":
+				ts.nilList = !isStringKind(ptr.Elem())
+			case "nilList":
+				ts.nilList = true
+			}
+		case "optional":
+			ts.optional = true
+		case "tail":
+			return ts, fmt.Errorf("struct tag %q on field %s is not supported by rlpgen", t, field.Name())
+		default:
+			return ts, fmt.Errorf("unknown struct tag %q on field %s", t, field.Name())
+		}
+	}
+	return ts, nil
+}
+
+// isStringKind reports whether a nil pointer to typ is encoded as an empty
+// string. All other nil pointers are encoded as an empty list.
+func isStringKind(typ types.Type) bool {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		return u.Info()&(types.IsUnsigned|types.IsString|types.IsBoolean) != 0
+	case *types.Slice:
+		return isByte(u.Elem())
+	case *types.Array:
+		return isByte(u.Elem())
+	}
+	return false
+}
+
+// isByte reports whether typ is the plain byte type.
+func isByte(typ types.Type) bool {
+	return types.Identical(typ, types.Typ[types.Uint8])
+}
+
+// isBigInt reports whether typ is math/big.Int.
+func isBigInt(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "math/big" && obj.Name() == "Int"
+}
+
+// makeOp creates the op for a value of type typ, following the encoding
+// rules of the reflection based codec in package rlp.
+func (bctx *buildContext) makeOp(typ types.Type, ts tags) (op, error) {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		if isBigInt(ptr.Elem()) {
+			return bigIntOp{pointer: true}, nil
+		}
+		return bctx.makePtrOp(ptr, ts)
+	}
+	if isBigInt(typ) {
+		return bigIntOp{}, nil
+	}
+	if types.Identical(typ, bctx.rawValueType) {
+		return rawValueOp{}, nil
+	}
+	var (
+		ptr     = types.NewPointer(typ)
+		encoder = types.Implements(ptr, bctx.encoderIface)
+		decoder = types.Implements(ptr, bctx.decoderIface)
+	)
+	if encoder || decoder {
+		return encoderDecoderOp{typ: typ, encoder: encoder, decoder: decoder}, nil
+	}
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsUnsigned != 0:
+			return makeUintOp(typ, u), nil
+		case u.Kind() == types.Bool:
+			return boolOp{typ: typ}, nil
+		case u.Kind() == types.String:
+			return stringOp{typ: typ}, nil
+		}
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			return byteSliceOp{}, nil
+		}
+		elem, err := bctx.makeOp(u.Elem(), tags{})
+		if err != nil {
+			return nil, err
+		}
+		return sliceOp{typ: typ, elem: elem}, nil
+	case *types.Array:
+		if isByte(u.Elem()) {
+			return byteArrayOp{typ: typ}, nil
+		}
+		return nil, fmt.Errorf("non-byte array type %v is not supported by rlpgen", typ)
+	case *types.Struct:
+		return bctx.makeStructOp(typ, u)
+	}
+	return nil, fmt.Errorf("type %v is not RLP-serializable", typ)
+}
+
+// makeStructOp creates the op for a struct type. Existing EncodeRLP and
+// DecodeRLP methods of typ itself are ignored.
+func (bctx *buildContext) makeStructOp(typ types.Type, styp *types.Struct) (op, error) {
+	var (
+		fields      []structField
+		anyOptional bool
+	)
+	for i := 0; i < styp.NumFields(); i++ {
+		f := styp.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		ts, err := parseTags(f, styp.Tag(i))
+		if err != nil {
+			return nil, err
+		}
+		if ts.ignored {
+			continue
+		}
+		if ts.optional {
+			anyOptional = true
+		} else if anyOptional {
+			return nil, fmt.Errorf("field %s needs \"optional\" tag", f.Name())
+		}
+		fop, err := bctx.makeOp(f.Type(), ts)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name(), err)
+		}
+		if ts.optional {
+			if _, err := nonZeroCheck("", f.Type()); err != nil {
+				return nil, fmt.Errorf("field %s: %v", f.Name(), err)
+			}
+		}
+		fields = append(fields, structField{name: f.Name(), typ: f.Type(), optional: ts.optional, op: fop})
+	}
+	return structOp{typ: typ, fields: fields}, nil
+}
+
+func (bctx *buildContext) makePtrOp(ptr *types.Pointer, ts tags) (op, error) {
+	elem, err := bctx.makeOp(ptr.Elem(), tags{})
+	if err != nil {
+		return nil, err
+	}
+	nilList := !isStringKind(ptr.Elem())
+	if ts.nilOK {
+		nilList = ts.nilList
+	}
+	return ptrOp{elemTyp: ptr.Elem(), elem: elem, nilOK: ts.nilOK, nilList: nilList}, nil
+}
+
+// generate produces the source file containing the methods for typ.
+func (bctx *buildContext) generate(pkg *types.Package, typ *types.Named, encoder, decoder bool) ([]byte, error) {
+	styp, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %v is not a struct", typ)
+	}
+	op, err := bctx.makeStructOp(typ, styp)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ctx  = newGenContext(pkg)
+		body bytes.Buffer
+		name = typ.Obj().Name()
+	)
+	ctx.imports[pathOfPackageRLP] = struct{}{}
+	if encoder {
+		ctx.imports["io"] = struct{}{}
+		ctx.resetTemp()
+		fmt.Fprintf(&body, "func (obj *%s) EncodeRLP(_w io.Writer) error {\n", name)
+		fmt.Fprintf(&body, "w := rlp.NewEncoderBuffer(_w)\n")
+		body.WriteString(op.genWrite(ctx, "obj"))
+		fmt.Fprintf(&body, "return w.Flush()\n}\n\n")
+	}
+	if decoder {
+		ctx.resetTemp()
+		code, result := op.genDecode(ctx)
+		fmt.Fprintf(&body, "func (obj *%s) DecodeRLP(dec *rlp.Stream) error {\n", name)
+		body.WriteString(code)
+		fmt.Fprintf(&body, "*obj = %s\n", result)
+		fmt.Fprintf(&body, "return nil\n}\n")
+	}
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\n\n", generatedHeader)
+	fmt.Fprintf(&out, "package %s\n\n", pkg.Name())
+	out.WriteString(ctx.importsList())
+	out.WriteString("\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format generated code: %v\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+// decodeError is the error check following a decoding statement.
+const decodeError = "if err != nil {\nreturn err\n}\n"
+
+// addressOf returns code and an expression taking the address of expr,
+// introducing a temporary if expr is not a plain variable.
+func addressOf(ctx *genContext, expr string) (string, string) {
+	if token.IsIdentifier(expr) {
+		return "", "&" + expr
+	}
+	v := ctx.temp()
+	return fmt.Sprintf("%s := %s\n", v, expr), "&" + v
+}
+
+// uintOp handles unsigned integer types.
+type uintOp struct {
+	typ  types.Type
+	bits int
+}
+
+func makeUintOp(typ types.Type, u *types.Basic) uintOp {
+	switch u.Kind() {
+	case types.Uint8:
+		return uintOp{typ, 8}
+	case types.Uint16:
+		return uintOp{typ, 16}
+	case types.Uint32:
+		return uintOp{typ, 32}
+	default:
+		return uintOp{typ, 64}
+	}
+}
+
+func (op uintOp) nativeType() types.Type {
+	switch op.bits {
+	case 8:
+		return types.Typ[types.Uint8]
+	case 16:
+		return types.Typ[types.Uint16]
+	case 32:
+		return types.Typ[types.Uint32]
+	default:
+		return types.Typ[types.Uint64]
+	}
+}
+
+func (op uintOp) genWrite(ctx *genContext, v string) string {
+	if types.Identical(op.typ, types.Typ[types.Uint64]) {
+		return fmt.Sprintf("w.WriteUint64(%s)\n", v)
+	}
+	return fmt.Sprintf("w.WriteUint64(uint64(%s))\n", v)
+}
+
+func (op uintOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	code := fmt.Sprintf("%s, err := dec.Uint%d()\n", v, op.bits) + decodeError
+	if types.Identical(op.typ, op.nativeType()) {
+		return code, v
+	}
+	return code, fmt.Sprintf("%s(%s)", ctx.typeString(op.typ), v)
+}
+
+// boolOp handles boolean types.
+type boolOp struct {
+	typ types.Type
+}
+
+func (op boolOp) genWrite(ctx *genContext, v string) string {
+	if types.Identical(op.typ, types.Typ[types.Bool]) {
+		return fmt.Sprintf("w.WriteBool(%s)\n", v)
+	}
+	return fmt.Sprintf("w.WriteBool(bool(%s))\n", v)
+}
+
+func (op boolOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	code := fmt.Sprintf("%s, err := dec.Bool()\n", v) + decodeError
+	if types.Identical(op.typ, types.Typ[types.Bool]) {
+		return code, v
+	}
+	return code, fmt.Sprintf("%s(%s)", ctx.typeString(op.typ), v)
+}
+
+// stringOp handles string types.
+type stringOp struct {
+	typ types.Type
+}
+
+func (op stringOp) genWrite(ctx *genContext, v string) string {
+	if types.Identical(op.typ, types.Typ[types.String]) {
+		return fmt.Sprintf("w.WriteString(%s)\n", v)
+	}
+	return fmt.Sprintf("w.WriteString(string(%s))\n", v)
+}
+
+func (op stringOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	code := fmt.Sprintf("%s, err := dec.Bytes()\n", v) + decodeError
+	return code, fmt.Sprintf("%s(%s)", ctx.typeString(op.typ), v)
+}
+
+// byteSliceOp handles []byte and named byte slice types.
+type byteSliceOp struct{}
+
+func (op byteSliceOp) genWrite(ctx *genContext, v string) string {
+	return fmt.Sprintf("w.WriteBytes(%s)\n", v)
+}
+
+func (op byteSliceOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	return fmt.Sprintf("%s, err := dec.Bytes()\n", v) + decodeError, v
+}
+
+// byteArrayOp handles [N]byte and named byte array types.
+type byteArrayOp struct {
+	typ types.Type
+}
+
+func (op byteArrayOp) genWrite(ctx *genContext, v string) string {
+	return fmt.Sprintf("w.WriteBytes(%s[:])\n", v)
+}
+
+func (op byteArrayOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", v, ctx.typeString(op.typ))
+	fmt.Fprintf(&b, "if err := dec.ReadBytes(%s[:]); err != nil {\nreturn err\n}\n", v)
+	return b.String(), v
+}
+
+// bigIntOp handles big.Int and *big.Int.
+type bigIntOp struct {
+	pointer bool
+}
+
+func (op bigIntOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+	if op.pointer {
+		fmt.Fprintf(&b, "if %s == nil {\n", v)
+		fmt.Fprintf(&b, "w.Write(rlp.EmptyString)\n")
+		fmt.Fprintf(&b, "} else {\n")
+	}
+	fmt.Fprintf(&b, "if %s.Sign() == -1 {\nreturn rlp.ErrNegativeBigInt\n}\n", v)
+	if op.pointer {
+		fmt.Fprintf(&b, "w.WriteBigInt(%s)\n", v)
+		fmt.Fprintf(&b, "}\n")
+	} else {
+		fmt.Fprintf(&b, "w.WriteBigInt(&%s)\n", v)
+	}
+	return b.String()
+}
+
+func (op bigIntOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	code := fmt.Sprintf("%s, err := dec.BigInt()\n", v) + decodeError
+	if op.pointer {
+		return code, v
+	}
+	return code, "(*" + v + ")"
+}
+
+// rawValueOp handles rlp.RawValue.
+type rawValueOp struct{}
+
+func (op rawValueOp) genWrite(ctx *genContext, v string) string {
+	return fmt.Sprintf("w.Write(%s)\n", v)
+}
+
+func (op rawValueOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	return fmt.Sprintf("%s, err := dec.Raw()\n", v) + decodeError, v
+}
+
+// encoderDecoderOp handles types implementing rlp.Encoder or rlp.Decoder.
+// If only one of the interfaces is implemented, the other direction falls
+// back to the reflection based codec.
+type encoderDecoderOp struct {
+	typ     types.Type
+	encoder bool
+	decoder bool
+}
+
+func (op encoderDecoderOp) genWrite(ctx *genContext, v string) string {
+	if op.encoder {
+		return fmt.Sprintf("if err := %s.EncodeRLP(w); err != nil {\nreturn err\n}\n", v)
+	}
+	return fmt.Sprintf("if err := rlp.Encode(w, &%s); err != nil {\nreturn err\n}\n", v)
+}
+
+func (op encoderDecoderOp) genDecode(ctx *genContext) (string, string) {
+	v := ctx.temp()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "var %s %s\n", v, ctx.typeString(op.typ))
+	if op.decoder {
+		fmt.Fprintf(&b, "if err := %s.DecodeRLP(dec); err != nil {\nreturn err\n}\n", v)
+	} else {
+		fmt.Fprintf(&b, "if err := dec.Decode(&%s); err != nil {\nreturn err\n}\n", v)
+	}
+	return b.String(), v
+}
+
+// ptrOp handles pointer types other than *big.Int.
+type ptrOp struct {
+	elemTyp types.Type
+	elem    op
+	nilOK   bool // decode empty values as nil (rlp:"nil")
+	nilList bool // nil is encoded as an empty list instead of an empty string
+}
+
+func (op ptrOp) genWrite(ctx *genContext, v string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "if %s == nil {\n", v)
+	if op.nilList {
+		fmt.Fprintf(&b, "w.Write(rlp.EmptyList)\n")
+	} else {
+		fmt.Fprintf(&b, "w.Write(rlp.EmptyString)\n")
+	}
+	fmt.Fprintf(&b, "} else {\n")
+	b.WriteString(op.elem.genWrite(ctx, "(*"+v+")"))
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+func (op ptrOp) genDecode(ctx *genContext) (string, string) {
+	if !op.nilOK {
+		code, result := op.elem.genDecode(ctx)
+		addrCode, addr := addressOf(ctx, result)
+		return code + addrCode, addr
+	}
+	var (
+		b    bytes.Buffer
+		v    = ctx.temp()
+		kind = ctx.temp()
+		size = ctx.temp()
+	)
+	fmt.Fprintf(&b, "var %s *%s\n", v, ctx.typeString(op.elemTyp))
+	fmt.Fprintf(&b, "if %s, %s, err := dec.Kind(); err != nil {\nreturn err\n}", kind, size)
+	// Empty values of the wrong kind are rejected by dec.List and dec.Bytes.
+	fmt.Fprintf(&b, " else if %s != rlp.Byte && %s == 0 {\n", kind, size)
+	if op.nilList {
+		fmt.Fprintf(&b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+		fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+	} else {
+		fmt.Fprintf(&b, "if _, err := dec.Bytes(); err != nil {\nreturn err\n}\n")
+	}
+	fmt.Fprintf(&b, "} else {\n")
+	code, result := op.elem.genDecode(ctx)
+	addrCode, addr := addressOf(ctx, result)
+	b.WriteString(code)
+	b.WriteString(addrCode)
+	fmt.Fprintf(&b, "%s = %s\n", v, addr)
+	fmt.Fprintf(&b, "}\n")
+	return b.String(), v
+}
+
+// sliceOp handles non-byte slice types.
+type sliceOp struct {
+	typ  types.Type
+	elem op
+}
+
+func (op sliceOp) genWrite(ctx *genContext, v string) string {
+	var (
+		b    bytes.Buffer
+		list = ctx.temp()
+		elem = ctx.temp()
+	)
+	fmt.Fprintf(&b, "%s := w.List()\n", list)
+	fmt.Fprintf(&b, "for _, %s := range %s {\n", elem, v)
+	b.WriteString(op.elem.genWrite(ctx, elem))
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "w.ListEnd(%s)\n", list)
+	return b.String()
+}
+
+func (op sliceOp) genDecode(ctx *genContext) (string, string) {
+	var (
+		b bytes.Buffer
+		v = ctx.temp()
+	)
+	// Empty lists decode as empty, non-nil slices just like in package rlp.
+	fmt.Fprintf(&b, "%s := %s{}\n", v, ctx.typeString(op.typ))
+	fmt.Fprintf(&b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(&b, "for dec.MoreDataInList() {\n")
+	code, result := op.elem.genDecode(ctx)
+	b.WriteString(code)
+	fmt.Fprintf(&b, "%s = append(%s, %s)\n", v, v, result)
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+	return b.String(), v
+}
+
+// structField is a single encoded field of a struct.
+type structField struct {
+	name     string
+	typ      types.Type
+	optional bool // field may be omitted from the end of the list (rlp:"optional")
+	op       op
+}
+
+// nonZeroCheck returns an expression reporting whether the value v of type typ
+// differs from its zero value, which decides if an optional field is written.
+func nonZeroCheck(v string, typ types.Type) (string, error) {
+	switch u := typ.Underlying().(type) {
+	case *types.Pointer:
+		return v + " != nil", nil
+	case *types.Slice:
+		return "len(" + v + ") > 0", nil
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return v, nil
+		case u.Info()&types.IsString != 0:
+			return v + ` != ""`, nil
+		case u.Info()&types.IsUnsigned != 0:
+			return v + " != 0", nil
+		}
+	case *types.Array:
+		if isByte(u.Elem()) {
+			return v + " != [" + fmt.Sprint(u.Len()) + "]byte{}", nil
+		}
+	}
+	return "", fmt.Errorf("optional field of type %v is not supported by rlpgen", typ)
+}
+
+// structOp handles struct types.
+type structOp struct {
+	typ    types.Type
+	fields []structField
+}
+
+func (op structOp) genWrite(ctx *genContext, v string) string {
+	var (
+		b    bytes.Buffer
+		list = ctx.temp()
+	)
+	fmt.Fprintf(&b, "%s := w.List()\n", list)
+
+	// Optional fields are written if they or any subsequent field are non-zero
+	var nonZero []string
+	for _, f := range op.fields {
+		if f.optional {
+			check, _ := nonZeroCheck(v+"."+f.name, f.typ)
+			nonZero = append(nonZero, ctx.temp())
+			fmt.Fprintf(&b, "%s := %s\n", nonZero[len(nonZero)-1], check)
+		}
+	}
+	optional := 0
+	for _, f := range op.fields {
+		if !f.optional {
+			b.WriteString(f.op.genWrite(ctx, v+"."+f.name))
+			continue
+		}
+		fmt.Fprintf(&b, "if %s {\n", strings.Join(nonZero[optional:], " || "))
+		b.WriteString(f.op.genWrite(ctx, v+"."+f.name))
+		fmt.Fprintf(&b, "}\n")
+		optional++
+	}
+	fmt.Fprintf(&b, "w.ListEnd(%s)\n", list)
+	return b.String()
+}
+
+func (op structOp) genDecode(ctx *genContext) (string, string) {
+	var (
+		b bytes.Buffer
+		v = ctx.temp()
+	)
+	fmt.Fprintf(&b, "var %s %s\n", v, ctx.typeString(op.typ))
+	fmt.Fprintf(&b, "{\n")
+	fmt.Fprintf(&b, "if _, err := dec.List(); err != nil {\nreturn err\n}\n")
+
+	// Optional fields are only decoded if the list has more elements, nesting
+	// each of them in the check of the previous one
+	var closing string
+	for _, f := range op.fields {
+		fmt.Fprintf(&b, "// %s:\n", f.name)
+		if f.optional {
+			fmt.Fprintf(&b, "if dec.MoreDataInList() {\n")
+			closing += "}\n"
+		}
+		code, result := f.op.genDecode(ctx)
+		b.WriteString(code)
+		fmt.Fprintf(&b, "%s.%s = %s\n", v, f.name, result)
+	}
+	b.WriteString(closing)
+	fmt.Fprintf(&b, "if err := dec.ListEnd(); err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(&b, "}\n")
+	return b.String(), v
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Package rlp is loaded only once and reused for all tests.
+var (
+	testFset     = token.NewFileSet()
+	testImporter = importer.ForCompiler(testFset, "source", nil)
+)
+
+var tests = []string{"uints", "nil", "rawvalue", "bigint", "structs", "slices", "encoder", "optional"}
+
+func TestOutput(t *testing.T) {
+	for _, test := range tests {
+		test := test
+		t.Run(test, func(t *testing.T) {
+			inputFile := filepath.Join("testdata", test+".in.txt")
+			outputFile := filepath.Join("testdata", test+".out.txt")
+			bctx, pkg, typ, err := loadTestSource(inputFile, "Test")
+			if err != nil {
+				t.Fatal("error loading test source:", err)
+			}
+			output, err := bctx.generate(pkg, typ, true, true)
+			if err != nil {
+				t.Fatal("error in generate:", err)
+			}
+			wantOutput, err := ioutil.ReadFile(outputFile)
+			if err != nil {
+				t.Fatal("error loading expected test output:", err)
+			}
+			if !bytes.Equal(output, wantOutput) {
+				t.Fatalf("output mismatch, want:\n%s\ngot:\n%s", wantOutput, output)
+			}
+		})
+	}
+}
+
+func loadTestSource(file string, typeName string) (*buildContext, *types.Package, *types.Named, error) {
+	// Load the test input.
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	f, err := parser.ParseFile(testFset, file, content, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	conf := types.Config{Importer: testImporter}
+	pkg, err := conf.Check("test", testFset, []*ast.File{f}, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Find the test struct.
+	rlppkg, err := testImporter.Import(pathOfPackageRLP)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	typ := pkg.Scope().Lookup(typeName).Type().(*types.Named)
+	return newBuildContext(rlppkg), pkg, typ, nil
+}
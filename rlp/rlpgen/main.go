@@ -0,0 +1,135 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of go-420coin.
+//
+// go-420coin is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-420coin is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-420coin. If not, see <http://www.gnu.org/licenses/>.
+
+// rlpgen generates specialized EncodeRLP and DecodeRLP methods for struct
+// types, replacing the reflection based codec of package rlp on hot paths.
+//
+// Usage:
+//
+//	//go:generate go run ../../rlp/rlpgen -type Header -out gen_header_rlp.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const pathOfPackageRLP = "github.com/420integrated/go-420coin/rlp"
+
+func main() {
+	var (
+		pkgdir     = flag.String("dir", ".", "input package directory")
+		output     = flag.String("out", "-", "output file (default is stdout)")
+		genEncoder = flag.Bool("encoder", true, "generate EncodeRLP?")
+		genDecoder = flag.Bool("decoder", true, "generate DecodeRLP?")
+		typename   = flag.String("type", "", "type to generate methods for")
+	)
+	flag.Parse()
+
+	cfg := Config{
+		Dir:             *pkgdir,
+		Type:            *typename,
+		GenerateEncoder: *genEncoder,
+		GenerateDecoder: *genDecoder,
+	}
+	code, err := cfg.process()
+	if err != nil {
+		fatal(err)
+	}
+	if *output == "-" {
+		os.Stdout.Write(code)
+	} else if err := ioutil.WriteFile(*output, code, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
+
+// Config is the configuration of a single generator run.
+type Config struct {
+	Dir  string // input package directory
+	Type string // name of the struct type to generate methods for
+
+	GenerateEncoder bool
+	GenerateDecoder bool
+}
+
+// process generates the Go code for the configured type.
+func (cfg *Config) process() (code []byte, err error) {
+	if cfg.Type == "" {
+		return nil, errors.New("no type specified, use -type")
+	}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	pkg, err := loadPackage(fset, imp, cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	// The rlp package is loaded through the same importer, so its types are
+	// identical to the ones referenced by the input package.
+	rlppkg, err := imp.Import(pathOfPackageRLP)
+	if err != nil {
+		return nil, fmt.Errorf("can't load package rlp: %v", err)
+	}
+	obj := pkg.Scope().Lookup(cfg.Type)
+	if obj == nil {
+		return nil, fmt.Errorf("no such identifier: %s", cfg.Type)
+	}
+	typ, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a named type", cfg.Type)
+	}
+	bctx := newBuildContext(rlppkg)
+	gen, err := bctx.generate(pkg, typ, cfg.GenerateEncoder, cfg.GenerateDecoder)
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+// loadPackage parses and type-checks the non-test Go files of the package in dir.
+func loadPackage(fset *token.FileSet, imp types.Importer, dir string) (*types.Package, error) {
+	bpkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range bpkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	// Type errors are ignored: the package may reference the methods which
+	// are about to be generated, and declarations are checked regardless.
+	conf := types.Config{Importer: imp, Error: func(error) {}}
+	pkg, _ := conf.Check(bpkg.ImportPath, fset, files, nil)
+	return pkg, nil
+}
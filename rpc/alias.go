@@ -0,0 +1,129 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Alias serves a namespace with the methods and subscriptions registered under
+// other namespaces of the same server, allowing clients written for a different
+// naming scheme to use an API unmodified. Methods registered directly under the
+// alias namespace take precedence over the aliased ones.
+//
+// An alias is registered like a service, by passing it to RegisterName.
+type Alias struct {
+	Targets []string // Namespaces searched for the aliased methods, in order
+
+	// Method translates the name of an aliased method or subscription, without
+	// the namespace, into the one it is registered under. Optional.
+	Method func(name string) string
+
+	// Params translates the JSON encoded positional parameters of the aliased
+	// calls and subscriptions. Optional.
+	Params func(params json.RawMessage) (json.RawMessage, error)
+
+	// Result translates the JSON encoded results of the aliased calls and the
+	// notifications of the aliased subscriptions. Optional.
+	Result func(result json.RawMessage) (json.RawMessage, error)
+}
+
+// targetName returns the name the aliased method is registered under.
+func (a *Alias) targetName(name string) string {
+	if a.Method == nil {
+		return name
+	}
+	return a.Method(name)
+}
+
+// translateParams runs the parameter translator of the alias, if any.
+func (a *Alias) translateParams(params json.RawMessage) (json.RawMessage, error) {
+	if a == nil || a.Params == nil || len(params) == 0 {
+		return params, nil
+	}
+	return a.Params(params)
+}
+
+// translateResult runs the result translator of the alias, if any.
+func (a *Alias) translateResult(result json.RawMessage) (json.RawMessage, error) {
+	if a == nil || a.Result == nil || len(result) == 0 {
+		return result, nil
+	}
+	return a.Result(result)
+}
+
+// registerAlias adds the given alias to the registry under the given name.
+func (r *serviceRegistry) registerAlias(name string, alias *Alias) error {
+	if name == "" {
+		return errors.New("no alias name")
+	}
+	if len(alias.Targets) == 0 {
+		return errors.New("alias " + name + " has no target namespaces")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.aliases == nil {
+		r.aliases = make(map[string]*Alias)
+	}
+	r.aliases[name] = alias
+	return nil
+}
+
+// aliasCallback returns the callback an aliased RPC method name resolves to,
+// along with the alias itself.
+func (r *serviceRegistry) aliasCallback(method string) (*callback, *Alias) {
+	elem := strings.SplitN(method, serviceMethodSeparator, 2)
+	if len(elem) != 2 {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alias := r.aliases[elem[0]]
+	if alias == nil {
+		return nil, nil
+	}
+	name := alias.targetName(elem[1])
+	for _, target := range alias.Targets {
+		if callb := r.services[target].callbacks[name]; callb != nil {
+			return callb, alias
+		}
+	}
+	return nil, nil
+}
+
+// aliasSubscription returns the subscription callback an aliased subscription
+// resolves to, along with the alias itself.
+func (r *serviceRegistry) aliasSubscription(service, name string) (*callback, *Alias) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alias := r.aliases[service]
+	if alias == nil {
+		return nil, nil
+	}
+	name = alias.targetName(name)
+	for _, target := range alias.Targets {
+		if callb := r.services[target].subscriptions[name]; callb != nil {
+			return callb, alias
+		}
+	}
+	return nil, nil
+}
@@ -94,6 +94,31 @@ func TestClientErrorData(t *testing.T) {
 	}
 }
 
+// This test checks that wrapped server errors are reported with the default
+// code, as only the outermost error is checked for a code and data.
+func TestClientWrappedErrorData(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var resp interface{}
+	err := client.Call(&resp, "test_returnWrappedError")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if e, ok := err.(Error); !ok {
+		t.Fatalf("client did not return rpc.Error, got %#v", e)
+	} else if e.ErrorCode() != defaultErrorCode {
+		t.Fatalf("wrong error code %d, want %d", e.ErrorCode(), defaultErrorCode)
+	}
+	if e, ok := err.(DataError); !ok {
+		t.Fatalf("client did not return rpc.DataError, got %#v", e)
+	} else if e.ErrorData() != nil {
+		t.Fatalf("unexpected error data %#v", e.ErrorData())
+	}
+}
+
 func TestClientBatchRequest(t *testing.T) {
 	server := newTestServer()
 	defer server.Stop()
@@ -310,6 +335,65 @@ func TestClientSubscribe(t *testing.T) {
 	}
 }
 
+// This test checks that the server refuses subscriptions beyond its limit and
+// hands out the slot again after unsubscribing.
+func TestClientSubscribeLimit(t *testing.T) {
+	server := newTestServer()
+	server.SetSubscriptionLimit(1)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	sub, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	_, err = client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0)
+	if err == nil || err.Error() != ErrSubscriptionLimit.Error() {
+		t.Fatalf("wrong error for subscription over limit: %v", err)
+	}
+	sub.Unsubscribe()
+
+	// The unsubscribe request is sent in the background, wait for the slot to free up.
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if sub, err = client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 0, 0); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("can't subscribe after unsubscribe:", err)
+		}
+	}
+	sub.Unsubscribe()
+}
+
+// This test checks that the server rate limits every call, counting the calls of
+// a batch separately.
+func TestClientRateLimit(t *testing.T) {
+	server := newTestServer()
+	server.SetRateLimit(0.1)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	batch := []BatchElem{
+		{Method: "test_noArgsRets", Result: new(interface{})},
+		{Method: "test_noArgsRets", Result: new(interface{})},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil {
+		t.Fatalf("first call rejected: %v", batch[0].Error)
+	}
+	if err, ok := batch[1].Error.(Error); !ok || err.ErrorCode() != new(rateLimitError).ErrorCode() {
+		t.Fatalf("wrong error for batched call over limit: %v", batch[1].Error)
+	}
+	err := client.Call(nil, "test_noArgsRets")
+	if err, ok := err.(Error); !ok || err.ErrorCode() != new(rateLimitError).ErrorCode() {
+		t.Fatalf("wrong error for call over limit: %v", err)
+	}
+}
+
 // In this test, the connection drops while Subscribe is waiting for a response.
 func TestClientSubscribeClose(t *testing.T) {
 	server := newTestServer()
@@ -610,7 +694,7 @@ func ipcTestClient(srv *Server, fl *flakeyListener) (*Client, net.Listener) {
 	} else {
 		endpoint = os.TempDir() + "/" + endpoint
 	}
-	l, err := ipcListen(endpoint)
+	l, err := ipcListen(endpoint, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -25,6 +25,13 @@ import (
 
 // StartIPCEndpoint starts an IPC endpoint.
 func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
+	return StartRestrictedIPCEndpoint(ipcEndpoint, nil, apis)
+}
+
+// StartRestrictedIPCEndpoint starts an IPC endpoint only reachable by the given
+// users and groups besides the account running it. Access lists are supported on
+// Windows named pipes, whereas Unix sockets are always restricted to their owner.
+func StartRestrictedIPCEndpoint(ipcEndpoint string, allow []string, apis []API) (net.Listener, *Server, error) {
 	// Register all the APIs exposed by the services.
 	var (
 		handler    = NewServer()
@@ -43,7 +50,7 @@ func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, er
 	}
 	log.Debug("IPCs registered", "namespaces", strings.Join(registered, ","))
 	// All APIs registered, start the IPC listener.
-	listener, err := ipcListen(ipcEndpoint)
+	listener, err := ipcListen(ipcEndpoint, allow)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,6 +213,7 @@ func (h *handler) cancelServerSubscriptions(err error) {
 		s.err <- err
 		close(s.err)
 		delete(h.serverSubs, id)
+		h.reg.subs.release()
 	}
 }
 
@@ -317,24 +318,39 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if !h.reg.allowCall() {
+		return msg.errorResponse(&rateLimitError{})
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
-	var callb *callback
+	var (
+		callb *callback
+		alias *Alias
+	)
 	if msg.isUnsubscribe() {
 		callb = h.unsubscribeCb
-	} else {
-		callb = h.reg.callback(msg.Method)
+	} else if callb = h.reg.callback(msg.Method); callb == nil {
+		callb, alias = h.reg.aliasCallback(msg.Method)
 	}
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
-	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
+	params, err := alias.translateParams(msg.Params)
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()})
+	}
+	args, err := parsePositionalArguments(params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	start := time.Now()
 	answer := h.runMethod(cp.ctx, msg, callb, args)
+	if answer.Error == nil {
+		if answer.Result, err = alias.translateResult(answer.Result); err != nil {
+			answer = msg.errorResponse(err)
+		}
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -363,25 +379,40 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	namespace := msg.namespace()
+	var alias *Alias
 	callb := h.reg.subscription(namespace, name)
+	if callb == nil {
+		callb, alias = h.reg.aliasSubscription(namespace, name)
+	}
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
-
+	params, err := alias.translateParams(msg.Params)
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()})
+	}
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
-	args, err := parsePositionalArguments(msg.Params, argTypes)
+	args, err := parsePositionalArguments(params, argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
 	args = args[1:]
 
+	// Reserve a slot for the subscription, it's handed back on unsubscribe.
+	if !h.reg.subs.acquire() {
+		return msg.errorResponse(ErrSubscriptionLimit)
+	}
 	// Install notifier in context so the subscription handler can find it.
-	n := &Notifier{h: h, namespace: namespace}
+	n := &Notifier{h: h, namespace: namespace, alias: alias}
 	cp.notifiers = append(cp.notifiers, n)
 	ctx := context.WithValue(cp.ctx, notifierKey{}, n)
 
-	return h.runMethod(ctx, msg, callb, args)
+	resp := h.runMethod(ctx, msg, callb, args)
+	if resp.Error != nil {
+		h.reg.subs.release()
+	}
+	return resp
 }
 
 // runMethod runs the Go callback for an RPC method.
@@ -404,6 +435,7 @@ func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	}
 	close(s.err)
 	delete(h.serverSubs, id)
+	h.reg.subs.release()
 	return true, nil
 }
 
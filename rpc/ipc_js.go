@@ -27,7 +27,7 @@ import (
 var errNotSupported = errors.New("rpc: not supported")
 
 // ipcListen will create a named pipe on the given endpoint.
-func ipcListen(endpoint string) (net.Listener, error) {
+func ipcListen(endpoint string, allow []string) (net.Listener, error) {
 	return nil, errNotSupported
 }
 
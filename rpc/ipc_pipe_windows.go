@@ -0,0 +1,263 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// pipeBufferSize is the size of the input and output buffers of the restricted
+	// named pipes.
+	pipeBufferSize = 65536
+
+	// Named pipe creation flags and errors, see CreateNamedPipe and winerror.h
+	pipeAccessDuplex        = 0x00000003
+	pipeFirstInstance       = 0x00080000
+	pipeRejectRemoteClients = 0x00000008
+	pipeUnlimitedInstances  = 255
+	errorPipeConnected      = syscall.Errno(535)
+	errorPipeNotConnected   = syscall.Errno(233)
+)
+
+var errPipeClosed = errors.New("named pipe listener closed")
+
+// pipeSecurityDescriptor builds the security descriptor of a named pipe granting
+// full access to the local system and the account running the node, and read and
+// write access to the given users and groups. Accounts may be given either by
+// name (e.g. DOMAIN\user) or by their string SID (e.g. S-1-5-32-544).
+func pipeSecurityDescriptor(allow []string) (*windows.SECURITY_DESCRIPTOR, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve process account: %v", err)
+	}
+	sddl := "D:P(A;;GA;;;SY)(A;;GA;;;" + user.User.Sid.String() + ")"
+	for _, account := range allow {
+		var (
+			sid *windows.SID
+			err error
+		)
+		if strings.HasPrefix(account, "S-") {
+			sid, err = windows.StringToSid(account)
+		} else {
+			sid, _, _, err = windows.LookupSID("", account)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unknown IPC account %q: %v", account, err)
+		}
+		sddl += "(A;;GRGW;;;" + sid.String() + ")"
+	}
+	return windows.SecurityDescriptorFromString(sddl)
+}
+
+// pipeAddr is the address of a named pipe.
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeListener is a named pipe listener creating each pipe instance with a custom
+// security descriptor.
+type pipeListener struct {
+	addr pipeAddr
+	path *uint16
+	sa   *windows.SecurityAttributes
+
+	handle windows.Handle // Pipe instance waiting for the next client
+	closed bool
+	lock   sync.Mutex
+}
+
+// listenPipe creates the first instance of a named pipe protected by the given
+// security descriptor and returns a listener accepting clients on it.
+func listenPipe(endpoint string, sd *windows.SECURITY_DESCRIPTOR) (net.Listener, error) {
+	path, err := windows.UTF16PtrFromString(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{
+		addr: pipeAddr(endpoint),
+		path: path,
+		sa: &windows.SecurityAttributes{
+			SecurityDescriptor: sd,
+		},
+	}
+	l.sa.Length = uint32(unsafe.Sizeof(*l.sa))
+
+	if l.handle, err = l.createPipe(true); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// createPipe creates a new instance of the named pipe.
+func (l *pipeListener) createPipe(first bool) (windows.Handle, error) {
+	flags := uint32(pipeAccessDuplex | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= pipeFirstInstance
+	}
+	// Byte stream pipe in blocking mode, only reachable from the local machine
+	return windows.CreateNamedPipe(l.path, flags, pipeRejectRemoteClients, pipeUnlimitedInstances, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+// Accept waits for a client to connect to the named pipe and returns the pipe
+// instance it connected to.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.lock.Lock()
+	if l.closed {
+		l.lock.Unlock()
+		return nil, errPipeClosed
+	}
+	handle := l.handle
+	l.lock.Unlock()
+
+	if err := waitOverlapped(handle, func(overlapped *windows.Overlapped) error {
+		err := windows.ConnectNamedPipe(handle, overlapped)
+		if err == errorPipeConnected {
+			return nil // Client connected before we started waiting
+		}
+		return err
+	}, nil); err != nil {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+
+		if l.closed {
+			return nil, errPipeClosed
+		}
+		return nil, err
+	}
+	// Client connected, create a new instance for the next one
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		windows.CloseHandle(handle)
+		return nil, errPipeClosed
+	}
+	next, err := l.createPipe(false)
+	if err != nil {
+		windows.CloseHandle(handle)
+		return nil, err
+	}
+	l.handle = next
+	return &pipeConn{handle: handle, addr: l.addr}, nil
+}
+
+// Close stops listening on the named pipe, aborting any pending Accept.
+func (l *pipeListener) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	windows.CancelIoEx(l.handle, nil)
+	return windows.CloseHandle(l.handle)
+}
+
+// Addr returns the address of the named pipe.
+func (l *pipeListener) Addr() net.Addr {
+	return l.addr
+}
+
+// pipeConn is the server end of a connected named pipe instance.
+type pipeConn struct {
+	handle windows.Handle
+	addr   pipeAddr
+	once   sync.Once
+}
+
+// Read implements net.Conn, reading data from the client.
+func (c *pipeConn) Read(b []byte) (int, error) {
+	var n uint32
+	err := waitOverlapped(c.handle, func(overlapped *windows.Overlapped) error {
+		return windows.ReadFile(c.handle, b, &n, overlapped)
+	}, &n)
+	if err == syscall.ERROR_BROKEN_PIPE || err == errorPipeNotConnected {
+		return int(n), io.EOF
+	}
+	return int(n), err
+}
+
+// Write implements net.Conn, writing data to the client.
+func (c *pipeConn) Write(b []byte) (int, error) {
+	var n uint32
+	err := waitOverlapped(c.handle, func(overlapped *windows.Overlapped) error {
+		return windows.WriteFile(c.handle, b, &n, overlapped)
+	}, &n)
+	return int(n), err
+}
+
+// Close implements net.Conn, disconnecting the client.
+func (c *pipeConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		windows.CancelIoEx(c.handle, nil)
+		err = windows.CloseHandle(c.handle)
+	})
+	return err
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+// SetDeadline does nothing and always returns nil, deadlines are not supported
+// on restricted named pipes.
+func (c *pipeConn) SetDeadline(time.Time) error { return nil }
+
+// SetReadDeadline does nothing and always returns nil.
+func (c *pipeConn) SetReadDeadline(time.Time) error { return nil }
+
+// SetWriteDeadline does nothing and always returns nil.
+func (c *pipeConn) SetWriteDeadline(time.Time) error { return nil }
+
+// waitOverlapped starts an overlapped operation on the given handle and blocks
+// until it completes, storing the number of transferred bytes in n if not nil.
+func waitOverlapped(handle windows.Handle, op func(*windows.Overlapped) error, n *uint32) error {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(event)
+
+	overlapped := &windows.Overlapped{HEvent: event}
+	if err := op(overlapped); err != windows.ERROR_IO_PENDING {
+		return err
+	}
+	var done uint32
+	if err := windows.GetOverlappedResult(handle, overlapped, &done, true); err != nil {
+		return err
+	}
+	if n != nil {
+		*n = done
+	}
+	return nil
+}
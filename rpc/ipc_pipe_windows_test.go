@@ -0,0 +1,63 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package rpc
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Tests that a named pipe restricted to a set of accounts still serves the
+// account running the node.
+func TestRestrictedPipe(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	endpoint := fmt.Sprintf(`\\.\pipe\go-420coin-test-pipe-%d-%d`, os.Getpid(), rand.Int63())
+
+	// Allow the builtin users group by SID and the administrators by name
+	listener, err := ipcListen(endpoint, []string{"S-1-5-32-545", "Administrators"})
+	if err != nil {
+		t.Fatalf("failed to create restricted pipe: %v", err)
+	}
+	defer listener.Close()
+	go server.ServeListener(listener)
+
+	for i := 0; i < 2; i++ {
+		client, err := Dial(endpoint)
+		if err != nil {
+			t.Fatalf("client %d: failed to dial restricted pipe: %v", i, err)
+		}
+		var resp echoResult
+		if err := client.Call(&resp, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+			t.Fatalf("client %d: call failed: %v", i, err)
+		}
+		if !reflect.DeepEqual(resp, echoResult{"hello", 10, &echoArgs{"world"}}) {
+			t.Errorf("client %d: incorrect result %#v", i, resp)
+		}
+		client.Close()
+	}
+	// Ensure unknown accounts are rejected
+	if _, err := ipcListen(endpoint+"-invalid", []string{"go-420coin-nonexistent-account"}); err == nil {
+		t.Fatalf("pipe created for unknown account")
+	}
+}
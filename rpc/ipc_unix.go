@@ -28,8 +28,12 @@ import (
 	"github.com/420integrated/go-420coin/log"
 )
 
-// ipcListen will create a Unix socket on the given endpoint.
-func ipcListen(endpoint string) (net.Listener, error) {
+// ipcListen will create a Unix socket on the given endpoint. The socket is only
+// accessible by its owner, so access lists are not supported.
+func ipcListen(endpoint string, allow []string) (net.Listener, error) {
+	if len(allow) > 0 {
+		log.Warn("IPC access lists are only supported on Windows, ignoring", "allow", allow)
+	}
 	if len(endpoint) > int(max_path_size) {
 		log.Warn(fmt.Sprintf("The ipc endpoint is longer than %d characters. ", max_path_size),
 			"endpoint", endpoint)
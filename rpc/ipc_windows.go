@@ -30,9 +30,18 @@ import (
 // defaultDialTimeout because named pipes are local and there is no need to wait so long.
 const defaultPipeDialTimeout = 2 * time.Second
 
-// ipcListen will create a named pipe on the given endpoint.
-func ipcListen(endpoint string) (net.Listener, error) {
-	return npipe.Listen(endpoint)
+// ipcListen will create a named pipe on the given endpoint. If a list of users and
+// groups is given, the pipe is only accessible by them, the local system and the
+// account running the node; otherwise the default pipe security applies.
+func ipcListen(endpoint string, allow []string) (net.Listener, error) {
+	if len(allow) == 0 {
+		return npipe.Listen(endpoint)
+	}
+	sd, err := pipeSecurityDescriptor(allow)
+	if err != nil {
+		return nil, err
+	}
+	return listenPipe(endpoint, sd)
 }
 
 // newIPCConnection will connect to a named pipe with the given endpoint as name.
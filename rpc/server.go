@@ -23,6 +23,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/420integrated/go-420coin/log"
+	"golang.org/x/time/rate"
 )
 
 const MetadataApi = "rpc"
@@ -58,11 +59,39 @@ func NewServer() *Server {
 	return server
 }
 
+// SetSubscriptionLimit caps the number of subscriptions which may be active at
+// the same time across all connections of the server. Zero means no limit.
+func (s *Server) SetSubscriptionLimit(limit int) {
+	atomic.StoreInt32(&s.services.subs.limit, int32(limit))
+}
+
+// SetRateLimit caps the number of method calls per second served across all
+// connections of the server. Every call of a batch and every message of a
+// websocket connection counts separately. Zero means no limit.
+func (s *Server) SetRateLimit(limit float64) {
+	var limiter *rate.Limiter
+	if limit > 0 {
+		burst := int(limit)
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit), burst)
+	}
+	s.services.mu.Lock()
+	s.services.calls = limiter
+	s.services.mu.Unlock()
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
 // service collection this server provides to clients.
+//
+// If the receiver is an *Alias, the name is made an alias of other namespaces.
 func (s *Server) RegisterName(name string, receiver interface{}) error {
+	if alias, ok := receiver.(*Alias); ok {
+		return s.services.registerAlias(name, alias)
+	}
 	return s.services.registerName(name, receiver)
 }
 
@@ -143,5 +172,8 @@ func (s *RPCService) Modules() map[string]string {
 	for name := range s.server.services.services {
 		modules[name] = "1.0"
 	}
+	for name := range s.server.services.aliases {
+		modules[name] = "1.0"
+	}
 	return modules
 }
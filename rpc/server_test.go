@@ -19,6 +19,7 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net"
@@ -51,6 +52,44 @@ func TestServerRegisterName(t *testing.T) {
 	}
 }
 
+// Tests that aliased methods are resolved in the target namespaces, with their
+// names, parameters and results translated.
+func TestServerAlias(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	alias := &Alias{
+		Targets: []string{"missing", "test"},
+		Method:  func(name string) string { return strings.Replace(name, "reverb", "echo", 1) },
+		Params: func(params json.RawMessage) (json.RawMessage, error) {
+			return bytes.Replace(params, []byte(`"T"`), []byte(`"S"`), 1), nil
+		},
+		Result: func(result json.RawMessage) (json.RawMessage, error) {
+			return bytes.Replace(result, []byte(`"String"`), []byte(`"Str"`), 1), nil
+		},
+	}
+	if err := server.RegisterName("alias", alias); err != nil {
+		t.Fatalf("failed to register alias: %v", err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result map[string]interface{}
+	if err := client.Call(&result, "alias_reverb", "hello", 10, map[string]string{"T": "world"}); err != nil {
+		t.Fatalf("aliased call failed: %v", err)
+	}
+	if result["Str"] != "hello" || result["Int"] != float64(10) {
+		t.Errorf("result mismatch: %v", result)
+	}
+	if args, _ := result["Args"].(map[string]interface{}); args == nil || args["S"] != "world" {
+		t.Errorf("parameters not translated: %v", result["Args"])
+	}
+	// Unknown methods are still reported as such
+	if err := client.Call(nil, "alias_missing"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected method not found error, got %v", err)
+	}
+}
+
 func TestServer(t *testing.T) {
 	files, err := ioutil.ReadDir("testdata")
 	if err != nil {
@@ -27,6 +27,7 @@ import (
 	"unicode"
 
 	"github.com/420integrated/go-420coin/log"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -39,6 +40,9 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	aliases  map[string]*Alias
+	subs     subscriptionQuota // shared by all connections of the server
+	calls    *rate.Limiter     // shared by all connections of the server, nil if unlimited
 }
 
 // service represents a registered object.
@@ -92,6 +96,16 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	return nil
 }
 
+// allowCall reports whether the call rate limit of the server permits serving
+// another method call.
+func (r *serviceRegistry) allowCall() bool {
+	r.mu.Lock()
+	limiter := r.calls
+	r.mu.Unlock()
+
+	return limiter == nil || limiter.Allow()
+}
+
 // callback returns the callback corresponding to the given RPC method name.
 func (r *serviceRegistry) callback(method string) *callback {
 	elem := strings.SplitN(method, serviceMethodSeparator, 2)
@@ -28,6 +28,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,8 +37,32 @@ var (
 	ErrNotificationsUnsupported = errors.New("notifications not supported")
 	// ErrNotificationNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionLimit is returned when the server's subscription limit is reached
+	ErrSubscriptionLimit = errors.New("too many active subscriptions")
 )
 
+// subscriptionQuota tracks the live subscriptions of a server against its limit.
+type subscriptionQuota struct {
+	limit  int32 // maximum number of live subscriptions, zero means unlimited
+	active int32 // number of live or pending subscriptions
+}
+
+// acquire reserves a slot for a new subscription, returning false if the limit
+// has already been reached.
+func (q *subscriptionQuota) acquire() bool {
+	active := atomic.AddInt32(&q.active, 1)
+	if limit := atomic.LoadInt32(&q.limit); limit > 0 && active > limit {
+		atomic.AddInt32(&q.active, -1)
+		return false
+	}
+	return true
+}
+
+// release frees a slot held by a subscription.
+func (q *subscriptionQuota) release() {
+	atomic.AddInt32(&q.active, -1)
+}
+
 var globalGen = randomIDGenerator()
 
 // ID defines a pseudo random number that is used to identify RPC subscriptions.
@@ -93,6 +118,7 @@ func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
 type Notifier struct {
 	h         *handler
 	namespace string
+	alias     *Alias // Alias the subscription was made through, translating the notifications
 
 	mu           sync.Mutex
 	sub          *Subscription
@@ -125,6 +151,9 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	if err != nil {
 		return err
 	}
+	if enc, err = n.alias.translateResult(enc); err != nil {
+		return err
+	}
 
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -26,6 +26,19 @@ import (
 	"github.com/420integrated/go-420coin/log"
 )
 
+// decision describes the outcome of a request for the audit log, telling user
+// or rule rejections apart from other failures.
+func decision(err error) string {
+	switch err {
+	case nil:
+		return "approved"
+	case ErrRequestDenied:
+		return "rejected"
+	default:
+		return "failed"
+	}
+}
+
 type AuditLogger struct {
 	log log.Logger
 	api ExternalAPI
@@ -34,13 +47,16 @@ type AuditLogger struct {
 func (l *AuditLogger) List(ctx context.Context) ([]common.Address, error) {
 	l.log.Info("List", "type", "request", "metadata", MetadataFromContext(ctx).String())
 	res, e := l.api.List(ctx)
-	l.log.Info("List", "type", "response", "data", res)
+	l.log.Info("List", "type", "response", "decision", decision(e), "data", res)
 
 	return res, e
 }
 
 func (l *AuditLogger) New(ctx context.Context) (common.Address, error) {
-	return l.api.New(ctx)
+	l.log.Info("New", "type", "request", "metadata", MetadataFromContext(ctx).String())
+	addr, e := l.api.New(ctx)
+	l.log.Info("New", "type", "response", "decision", decision(e), "address", addr.String(), "error", e)
+	return addr, e
 }
 
 func (l *AuditLogger) SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*fourtwentyapi.SignTransactionResult, error) {
@@ -54,9 +70,9 @@ func (l *AuditLogger) SignTransaction(ctx context.Context, args SendTxArgs, meth
 
 	res, e := l.api.SignTransaction(ctx, args, methodSelector)
 	if res != nil {
-		l.log.Info("SignTransaction", "type", "response", "data", common.Bytes2Hex(res.Raw), "error", e)
+		l.log.Info("SignTransaction", "type", "response", "decision", decision(e), "data", common.Bytes2Hex(res.Raw), "error", e)
 	} else {
-		l.log.Info("SignTransaction", "type", "response", "data", res, "error", e)
+		l.log.Info("SignTransaction", "type", "response", "decision", decision(e), "data", res, "error", e)
 	}
 	return res, e
 }
@@ -66,7 +82,7 @@ func (l *AuditLogger) SignData(ctx context.Context, contentType string, addr com
 	l.log.Info("SignData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"addr", addr.String(), "data", marshalledData, "content-type", contentType)
 	b, e := l.api.SignData(ctx, contentType, addr, data)
-	l.log.Info("SignData", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	l.log.Info("SignData", "type", "response", "decision", decision(e), "data", common.Bytes2Hex(b), "error", e)
 	return b, e
 }
 
@@ -81,9 +97,9 @@ func (l *AuditLogger) SignGnosisSafeTx(ctx context.Context, addr common.Mixedcas
 	res, e := l.api.SignGnosisSafeTx(ctx, addr, gnosisTx, methodSelector)
 	if res != nil {
 		data, _ := json.Marshal(res) // can ignore error, marshalling what we just unmarshalled
-		l.log.Info("SignGnosisSafeTx", "type", "response", "data", string(data), "error", e)
+		l.log.Info("SignGnosisSafeTx", "type", "response", "decision", decision(e), "data", string(data), "error", e)
 	} else {
-		l.log.Info("SignGnosisSafeTx", "type", "response", "data", res, "error", e)
+		l.log.Info("SignGnosisSafeTx", "type", "response", "decision", decision(e), "data", res, "error", e)
 	}
 	return res, e
 }
@@ -92,7 +108,7 @@ func (l *AuditLogger) SignTypedData(ctx context.Context, addr common.MixedcaseAd
 	l.log.Info("SignTypedData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"addr", addr.String(), "data", data)
 	b, e := l.api.SignTypedData(ctx, addr, data)
-	l.log.Info("SignTypedData", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	l.log.Info("SignTypedData", "type", "response", "decision", decision(e), "data", common.Bytes2Hex(b), "error", e)
 	return b, e
 }
 
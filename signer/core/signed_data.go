@@ -236,6 +236,10 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		if err := rlp.DecodeBytes(cliqueData, header); err != nil {
 			return nil, usefourtwentycoinV, err
 		}
+		// Refuse anything that is not a clique header, e.g. an ethash header
+		if err := validateCliqueHeader(header); err != nil {
+			return nil, usefourtwentycoinV, err
+		}
 		// The incoming clique header is already truncated, sent to us with a extradata already shortened
 		if len(header.Extra) < 65 {
 			// Need to add it back, to get a suitable length for hashing
@@ -254,6 +258,11 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 				Typ:   "clique",
 				Value: fmt.Sprintf("clique header %d [0x%x]", header.Number, header.Hash()),
 			},
+			{
+				Name:  "Chain ID",
+				Typ:   "chainid",
+				Value: api.chainID.String(),
+			},
 		}
 		// Clique uses V on the form 0 or 1
 		usefourtwentycoinV = false
@@ -293,6 +302,28 @@ func SignTextValidator(validatorData ValidatorData) (hexutil.Bytes, string) {
 	return crypto.Keccak256([]byte(msg)), msg
 }
 
+// validateCliqueHeader checks that the header carries the fields mandated by
+// the clique consensus engine, so that headers of other engines, which would
+// be interpreted differently by the network, are never signed.
+func validateCliqueHeader(header *types.Header) error {
+	if header.Number == nil || header.Difficulty == nil {
+		return errors.New("clique header without number or difficulty")
+	}
+	if header.UncleHash != types.EmptyUncleHash {
+		return errors.New("clique header with non-empty uncle hash")
+	}
+	if header.MixDigest != (common.Hash{}) {
+		return errors.New("clique header with non-zero mix digest")
+	}
+	if header.Nonce != (types.BlockNonce{}) && header.Nonce != types.EncodeNonce(math.MaxUint64) {
+		return fmt.Errorf("clique header with invalid vote nonce %x", header.Nonce)
+	}
+	if !header.Difficulty.IsUint64() || (header.Difficulty.Uint64() != 1 && header.Difficulty.Uint64() != 2) {
+		return fmt.Errorf("clique header with invalid difficulty %v", header.Difficulty)
+	}
+	return nil
+}
+
 // cliqueHeaderHashAndRlp returns the hash which is used as input for the proof-of-authority
 // signing. It is the hash of the entire header apart from the 65 byte signature
 // contained at the end of the extra data.
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"path"
 	"strings"
 	"testing"
@@ -30,7 +31,9 @@ import (
 	"github.com/420integrated/go-420coin/common"
 	"github.com/420integrated/go-420coin/common/hexutil"
 	"github.com/420integrated/go-420coin/common/math"
+	"github.com/420integrated/go-420coin/core/types"
 	"github.com/420integrated/go-420coin/crypto"
+	"github.com/420integrated/go-420coin/rlp"
 	"github.com/420integrated/go-420coin/signer/core"
 )
 
@@ -231,6 +234,47 @@ func TestSignData(t *testing.T) {
 	}
 }
 
+func TestSignDataClique(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "1"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0])
+
+	header := &types.Header{
+		UncleHash:  types.EmptyUncleHash,
+		Difficulty: big.NewInt(2),
+		Number:     big.NewInt(1),
+		Extra:      make([]byte, 32+65),
+	}
+	encode := func(h *types.Header) string {
+		blob, err := rlp.EncodeToBytes(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hexutil.Encode(blob)
+	}
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	signature, err := api.SignData(context.Background(), core.ApplicationClique.Mime, a, encode(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signature) != 65 || signature[64] > 1 {
+		t.Errorf("Expected 65 byte signature with V in 0/1 form, got %x", signature)
+	}
+	// Headers of other engines must be refused before asking the user
+	ethashHeader := types.CopyHeader(header)
+	ethashHeader.Difficulty = big.NewInt(131072)
+	ethashHeader.MixDigest = common.HexToHash("0x01")
+	if _, err := api.SignData(context.Background(), core.ApplicationClique.Mime, a, encode(ethashHeader)); err == nil {
+		t.Error("Expected error for non-clique header")
+	}
+}
+
 func TestDomainChainId(t *testing.T) {
 	withoutChainID := core.TypedData{
 		Types: core.Types{
@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/420integrated/go-420coin/common"
+	"github.com/420integrated/go-420coin/core/vm"
 	"github.com/420integrated/go-420coin/signer/core"
 )
 
@@ -73,6 +74,14 @@ func (db *Database) ValidateTransaction(selector *string, tx *core.SendTxArgs) (
 	if bytes.Equal(tx.To.Address().Bytes(), common.Address{}.Bytes()) {
 		messages.Crit("Transaction recipient is the zero address")
 	}
+	// Precompiles can't do anything with value sent to them, the funds are lost
+	if _, ok := vm.PrecompiledContractsYoloV2[tx.To.Address()]; ok {
+		if tx.Value.ToInt().Sign() > 0 {
+			messages.Crit("Transaction sends value to a precompiled contract")
+		} else {
+			messages.Warn("Transaction recipient is a precompiled contract")
+		}
+	}
 	// Semantic fields validated, try to make heads or tails of the call data
 	db.ValidateCallData(selector, data, messages)
 	return messages, nil
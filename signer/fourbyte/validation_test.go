@@ -97,6 +97,12 @@ func TestTransactionValidation(t *testing.T) {
 		// Send to 0
 		{from: "000000000000000000000000000000000000dead", to: "0x0000000000000000000000000000000000000000",
 			n: "0x01", g: "0x20", gp: "0x40", value: "0x01", numMessages: 1},
+		// Send value to precompile
+		{from: "000000000000000000000000000000000000dead", to: "0x0000000000000000000000000000000000000001",
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x01", numMessages: 1},
+		// Call precompile without value
+		{from: "000000000000000000000000000000000000dead", to: "0x0000000000000000000000000000000000000002",
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", numMessages: 1},
 		// Create empty contract (no value)
 		{from: "000000000000000000000000000000000000dead", to: "",
 			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", numMessages: 1},
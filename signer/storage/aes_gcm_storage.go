@@ -21,6 +21,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -111,6 +112,29 @@ func (s *AESEncryptedStorage) Del(key string) {
 	}
 }
 
+// CopyTo re-encrypts every entry of the storage with the key of dst and writes
+// them into the file of dst, replacing its previous content. It is used when
+// rotating the master seed the storage keys are derived from.
+func (s *AESEncryptedStorage) CopyTo(dst *AESEncryptedStorage) error {
+	data, err := s.readEncryptedStorage()
+	if err != nil {
+		return err
+	}
+	creds := make(map[string]storedCredential, len(data))
+	for key, encrypted := range data {
+		entry, err := decrypt(s.key, encrypted.Iv, encrypted.CipherText, []byte(key))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt key %q: %v", key, err)
+		}
+		ciphertext, iv, err := encrypt(dst.key, entry, []byte(key))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt key %q: %v", key, err)
+		}
+		creds[key] = storedCredential{Iv: iv, CipherText: ciphertext}
+	}
+	return dst.writeEncryptedStorage(creds)
+}
+
 // readEncryptedStorage reads the file with encrypted creds
 func (s *AESEncryptedStorage) readEncryptedStorage() (map[string]storedCredential, error) {
 	creds := make(map[string]storedCredential)
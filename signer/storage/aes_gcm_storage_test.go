@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/420integrated/go-420coin/common"
@@ -115,6 +116,37 @@ func TestEnd2End(t *testing.T) {
 	}
 }
 
+func TestCopyTo(t *testing.T) {
+	d, err := ioutil.TempDir("", "420-encrypted-storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+
+	src := NewAESEncryptedStorage(fmt.Sprintf("%v/old.json", d), []byte("AES256Key-32Characters1234567890"))
+	src.Put("k1", "v1")
+	src.Put("k2", "v2")
+
+	dst := NewAESEncryptedStorage(fmt.Sprintf("%v/new.json", d), []byte("AES256Key-32Characters0987654321"))
+	dst.Put("stale", "entry")
+	if err := src.CopyTo(dst); err != nil {
+		t.Fatalf("failed to copy storage: %v", err)
+	}
+	for k, want := range map[string]string{"k1": "v1", "k2": "v2"} {
+		if v, err := dst.Get(k); v != want || err != nil {
+			t.Errorf("Expected %v->%v (nil error), got '%v' (%v error)", k, want, v, err)
+		}
+	}
+	if _, err := dst.Get("stale"); err != ErrNotFound {
+		t.Errorf("Expected stale entry to be dropped, got %v", err)
+	}
+	// The copy must not be readable with the old key
+	stolen := NewAESEncryptedStorage(dst.filename, src.key)
+	if _, err := stolen.Get("k1"); err == nil {
+		t.Error("Expected decryption with old key to fail")
+	}
+}
+
 func TestSwappedKeys(t *testing.T) {
 	// It should not be possible to swap the keys/values, so that
 	// K1:V1, K2:V2 can be swapped into K1:V2, K2:V1
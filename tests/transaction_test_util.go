@@ -55,7 +55,7 @@ func (tt *TransactionTest) Run(config *params.ChainConfig) error {
 			return nil, nil, err
 		}
 		// Intrinsic smoke
-		requiredSmoke, err := core.IntrinsicSmoke(tx.Data(), tx.To() == nil, isHomestead, isIstanbul)
+		requiredSmoke, err := core.IntrinsicSmoke(tx.Data(), tx.AccessList(), tx.To() == nil, isHomestead, isIstanbul)
 		if err != nil {
 			return nil, nil, err
 		}
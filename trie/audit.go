@@ -0,0 +1,118 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"github.com/420integrated/go-420coin/common"
+)
+
+// Auditor walks tries stored in a database, counting the references to every
+// reachable node and collecting the referenced nodes missing from the database.
+// As opposed to the node iterator, it doesn't abort at the first missing node,
+// so it can quantify how much of a state is lost or, compared to the stored
+// nodes, how much of the database is taken up by unreachable ones.
+type Auditor struct {
+	db      *Database
+	refs    map[common.Hash]uint32   // Number of references to each reached node
+	missing map[common.Hash]struct{} // Referenced nodes not found in the database
+}
+
+// NewAuditor creates a trie auditor on top of the given node database.
+func NewAuditor(db *Database) *Auditor {
+	return &Auditor{
+		db:      db,
+		refs:    make(map[common.Hash]uint32),
+		missing: make(map[common.Hash]struct{}),
+	}
+}
+
+// Walk audits the trie with the given root. The onleaf callback is invoked with
+// every value stored in the trie, allowing tries referenced from the values to
+// be walked too. Subtries shared with already walked ones only have their root
+// reference counted, so their values are reported once.
+func (a *Auditor) Walk(root common.Hash, onleaf func(value []byte) error) error {
+	if root == emptyRoot || root == (common.Hash{}) {
+		return nil
+	}
+	return a.walk(hashNode(root.Bytes()), onleaf)
+}
+
+func (a *Auditor) walk(n node, onleaf func(value []byte) error) error {
+	switch n := n.(type) {
+	case hashNode:
+		hash := common.BytesToHash(n)
+		if a.refs[hash]++; a.refs[hash] > 1 {
+			return nil
+		}
+		resolved := a.db.node(hash)
+		if resolved == nil {
+			a.missing[hash] = struct{}{}
+			return nil
+		}
+		return a.walk(resolved, onleaf)
+
+	case *shortNode:
+		return a.walk(n.Val, onleaf)
+
+	case *fullNode:
+		for _, child := range &n.Children {
+			if child != nil {
+				if err := a.walk(child, onleaf); err != nil {
+					return err
+				}
+			}
+		}
+	case valueNode:
+		if onleaf != nil {
+			return onleaf(n)
+		}
+	}
+	return nil
+}
+
+// Reference marks a database entry keyed by its hash as reachable, without it
+// being a trie node (e.g. contract code stored in the legacy layout).
+func (a *Auditor) Reference(hash common.Hash) {
+	a.refs[hash]++
+}
+
+// Refs returns the number of references to the entry with the given hash, zero
+// if it's unreachable from the walked tries.
+func (a *Auditor) Refs(hash common.Hash) uint32 {
+	return a.refs[hash]
+}
+
+// Reachable returns the number of distinct entries reached, and the total number
+// of references to them.
+func (a *Auditor) Reachable() (entries int, refs uint64) {
+	for hash, count := range a.refs {
+		if _, missing := a.missing[hash]; !missing {
+			entries++
+			refs += uint64(count)
+		}
+	}
+	return entries, refs
+}
+
+// Missing returns the hashes of the referenced nodes not found in the database.
+func (a *Auditor) Missing() []common.Hash {
+	hashes := make([]common.Hash, 0, len(a.missing))
+	for hash := range a.missing {
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
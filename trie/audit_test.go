@@ -0,0 +1,86 @@
+// Copyright 2020 The The 420Integrated Development Group
+// This file is part of the go-420coin library.
+//
+// The go-420coin library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-420coin library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-420coin library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"testing"
+
+	"github.com/420integrated/go-420coin/common"
+)
+
+// Tests that the auditor reaches every stored node of a trie, and that it keeps
+// walking past missing nodes, reporting them.
+func TestAuditor(t *testing.T) {
+	triedb, trie, content := makeTestTrie()
+	root := trie.Hash()
+	if err := triedb.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	diskdb := triedb.DiskDB()
+
+	// Audit the complete trie and cross check with the stored nodes
+	auditor := NewAuditor(NewDatabase(diskdb))
+	leaves := 0
+	if err := auditor.Walk(root, func([]byte) error { leaves++; return nil }); err != nil {
+		t.Fatalf("failed to audit trie: %v", err)
+	}
+	if leaves != len(content) {
+		t.Errorf("leaf count mismatch: have %d, want %d", leaves, len(content))
+	}
+	if missing := auditor.Missing(); len(missing) != 0 {
+		t.Errorf("missing nodes reported for complete trie: %x", missing)
+	}
+	var victim common.Hash
+	stored := 0
+
+	it := diskdb.NewIterator(nil, nil)
+	for it.Next() {
+		if len(it.Key()) != common.HashLength {
+			continue // Preimages of the secure trie
+		}
+		hash := common.BytesToHash(it.Key())
+		if auditor.Refs(hash) == 0 {
+			t.Errorf("stored node %x unreachable", hash)
+		}
+		if hash != root {
+			victim = hash
+		}
+		stored++
+	}
+	it.Release()
+
+	if entries, _ := auditor.Reachable(); entries != stored {
+		t.Errorf("reachable node count mismatch: have %d, want %d", entries, stored)
+	}
+	// Drop a node and ensure the rest of the trie is still audited
+	diskdb.Delete(victim[:])
+
+	auditor = NewAuditor(NewDatabase(diskdb))
+	leaves = 0
+	if err := auditor.Walk(root, func([]byte) error { leaves++; return nil }); err != nil {
+		t.Fatalf("failed to audit trie: %v", err)
+	}
+	if missing := auditor.Missing(); len(missing) != 1 || missing[0] != victim {
+		t.Errorf("missing nodes mismatch: have %x, want [%x]", missing, victim)
+	}
+	if leaves == 0 || leaves >= len(content) {
+		t.Errorf("leaf count mismatch: have %d, want between 0 and %d", leaves, len(content))
+	}
+	if entries, _ := auditor.Reachable(); entries >= stored {
+		t.Errorf("reachable node count mismatch: have %d, want below %d", entries, stored)
+	}
+}